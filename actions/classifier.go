@@ -0,0 +1,210 @@
+package actions
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/bayes"
+	"github.com/stephane-martin/mailstats/logging"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/parser"
+	"github.com/stephane-martin/mailstats/utils"
+	"github.com/urfave/cli"
+	"go.uber.org/fx"
+	"golang.org/x/sync/errgroup"
+)
+
+// TrainAction adds the mails of a mbox, of a file or of stdin to a class of the Bayesian model.
+func TrainAction(c *cli.Context) error {
+	args, err := arguments.GetArgs(c)
+	if err != nil {
+		err = fmt.Errorf("error validating train cli arguments: %s", err)
+		return cli.NewExitError(err.Error(), 1)
+	}
+	logger := logging.NewLogger(args)
+
+	class := strings.ToLower(strings.TrimSpace(c.String("class")))
+	if !bayes.ValidClass(class) {
+		return cli.NewExitError(fmt.Sprintf("invalid class name: '%s'", class), 1)
+	}
+	// the model is read, modified and written back: a concurrent train would lose the mails of one
+	// of the commands
+	unlock, err := bayes.LockModel(args.Classifier.ModelPath)
+	if err != nil {
+		return cli.NewExitError(fmt.Sprintf("failed to lock the classifier model: %s", err), 1)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer unlock()
+	model, err := bayes.LoadModel(args.Classifier.ModelPath)
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+
+	learned := 0
+	err = parseMails(c, args, logger, func(features *models.FeaturesMail) error {
+		err := model.Learn(class, bayes.Tokens(features))
+		if err == nil {
+			learned++
+		}
+		return err
+	})
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+	if learned == 0 {
+		return nil
+	}
+	err = model.Save(args.Classifier.ModelPath)
+	if err != nil {
+		return cli.NewExitError(fmt.Sprintf("failed to save the classifier model: %s", err), 1)
+	}
+	logger.Info("Mails learned", "class", class, "mails", learned, "model", args.Classifier.ModelPath, "documents", model.Documents())
+	return nil
+}
+
+// ClassifyAction prints the classification of the mails of a mbox, of a file or of stdin.
+func ClassifyAction(c *cli.Context) error {
+	args, err := arguments.GetArgs(c)
+	if err != nil {
+		err = fmt.Errorf("error validating classify cli arguments: %s", err)
+		return cli.NewExitError(err.Error(), 1)
+	}
+	logger := logging.NewLogger(args)
+
+	model, err := bayes.LoadModel(args.Classifier.ModelPath)
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+	if len(model.Classes) < 2 {
+		return cli.NewExitError("the classifier model must be trained with two classes at least", 1)
+	}
+
+	err = parseMails(c, args, logger, func(features *models.FeaturesMail) error {
+		fmt.Println(utils.JSONString(map[string]interface{}{
+			"title":          features.Title,
+			"classification": model.Classify(bayes.Tokens(features)),
+		}))
+		return nil
+	})
+	if err != nil {
+		return cli.NewExitError(err.Error(), 1)
+	}
+	return nil
+}
+
+// parseMails parses the mails of the mbox given by the --mbox flag, the mail in the file given by
+// --filename, or the mail read from stdin, and passes their features to handle. Only the services
+// that the tokens of the classifier need are provided: no DNS enrichment, and no threading, VIP or
+// DLP state.
+func parseMails(c *cli.Context, args *arguments.Args, logger log15.Logger, handle func(*models.FeaturesMail) error) error {
+	var theparser parser.Parser
+
+	app := fx.New(
+		parser.Service,
+
+		fx.Provide(
+			func() *cli.Context { return c },
+			func() *arguments.Args { return args },
+			func() log15.Logger { return logger },
+		),
+		fx.Logger(logging.PrintfLogger{Logger: logger}),
+		fx.Invoke(func(p parser.Parser) {
+			theparser = p
+		}),
+	)
+	done := app.Done()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go func() {
+		for range done {
+			cancel()
+		}
+	}()
+
+	startCtx, cancelStart := context.WithTimeout(ctx, app.StartTimeout())
+	defer cancelStart()
+	err := app.Start(startCtx)
+	if err != nil {
+		return fmt.Errorf("failed to start: %s", err)
+	}
+	defer func() {
+		stopCtx, cancelStop := context.WithTimeout(context.Background(), app.StopTimeout())
+		defer cancelStop()
+		_ = app.Stop(stopCtx)
+	}()
+
+	g, lctx := errgroup.WithContext(ctx)
+	incomings := make(chan *models.IncomingMail)
+	features := make(chan *models.FeaturesMail)
+
+	g.Go(func() error {
+		theparser.ParseMany(lctx, incomings, features)
+		return nil
+	})
+
+	g.Go(func() error {
+		// the parser does not stop before the features are read
+		var err error
+		for feature := range features {
+			if err == nil {
+				err = handle(feature)
+			}
+		}
+		return err
+	})
+
+	g.Go(func() error {
+		defer close(incomings)
+		mboxName := strings.TrimSpace(c.String("mbox"))
+		if mboxName != "" {
+			f, err := os.Open(mboxName)
+			if err != nil {
+				return err
+			}
+			//noinspection GoUnhandledErrorResult
+			defer f.Close()
+			err = scanMbox(lctx, f, incomings, logger)
+			if err != nil {
+				logger.Warn("Error parsing mail from mbox", "error", err)
+			}
+			return nil
+		}
+		var r io.Reader = os.Stdin
+		filename := strings.TrimSpace(c.String("filename"))
+		if filename != "" {
+			f, err := os.Open(filename)
+			if err != nil {
+				return err
+			}
+			//noinspection GoUnhandledErrorResult
+			defer f.Close()
+			r = f
+		}
+		data, err := ioutil.ReadAll(r)
+		if err != nil {
+			return err
+		}
+		incoming := &models.IncomingMail{
+			BaseInfos: models.BaseInfos{
+				Family:       "file",
+				TimeReported: time.Now(),
+			},
+			Data: data,
+		}
+		select {
+		case incomings <- incoming:
+		case <-lctx.Done():
+			return lctx.Err()
+		}
+		return nil
+	})
+
+	return g.Wait()
+}
@@ -11,9 +11,16 @@ import (
 	"github.com/stephane-martin/mailstats/consumers"
 	"github.com/stephane-martin/mailstats/extractors"
 	"github.com/stephane-martin/mailstats/logging"
+	"github.com/stephane-martin/mailstats/logos"
+	"github.com/stephane-martin/mailstats/bayes"
+	"github.com/stephane-martin/mailstats/dlp"
+	"github.com/stephane-martin/mailstats/dnsenrich"
+	"github.com/stephane-martin/mailstats/mailcrypto"
 	"github.com/stephane-martin/mailstats/models"
 	"github.com/stephane-martin/mailstats/parser"
+	"github.com/stephane-martin/mailstats/threading"
 	"github.com/stephane-martin/mailstats/utils"
+	"github.com/stephane-martin/mailstats/vip"
 	"github.com/urfave/cli"
 	"go.uber.org/fx"
 	"golang.org/x/sync/errgroup"
@@ -137,6 +144,13 @@ func IMAPDownloadAction(c *cli.Context) error {
 		parser.Service,
 		extractors.ExifToolService,
 		utils.GeoIPService,
+		logos.Service,
+		mailcrypto.Service,
+		dnsenrich.Service,
+		vip.Service,
+		threading.Service,
+		dlp.Service,
+		bayes.Service,
 
 		fx.Provide(
 			func() *cli.Context { return c },
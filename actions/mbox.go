@@ -7,8 +7,15 @@ import (
 	"github.com/inconshreveable/log15"
 	"github.com/stephane-martin/mailstats/extractors"
 	"github.com/stephane-martin/mailstats/logging"
+	"github.com/stephane-martin/mailstats/logos"
+	"github.com/stephane-martin/mailstats/bayes"
+	"github.com/stephane-martin/mailstats/dlp"
+	"github.com/stephane-martin/mailstats/dnsenrich"
+	"github.com/stephane-martin/mailstats/mailcrypto"
 	"github.com/stephane-martin/mailstats/parser"
+	"github.com/stephane-martin/mailstats/threading"
 	"github.com/stephane-martin/mailstats/utils"
+	"github.com/stephane-martin/mailstats/vip"
 	"go.uber.org/fx"
 	"io"
 	"os"
@@ -51,6 +58,13 @@ func MBoxAction(c *cli.Context) error {
 		parser.Service,
 		extractors.ExifToolService,
 		utils.GeoIPService,
+		logos.Service,
+		mailcrypto.Service,
+		dnsenrich.Service,
+		vip.Service,
+		threading.Service,
+		dlp.Service,
+		bayes.Service,
 
 		fx.Provide(
 			func() *cli.Context { return c },
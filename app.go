@@ -19,6 +19,7 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 )
 
 
@@ -175,11 +176,109 @@ func MakeApp() *cli.App {
 			Usage: "path to the GeoIP lite database",
 			EnvVar: "MAILSTATS_GEOIP_DATABASE_PATH",
 		},
+		cli.StringFlag{
+			Name: "geoip-country-database-path",
+			Value: "",
+			Usage: "path to the GeoIP country database",
+			EnvVar: "MAILSTATS_GEOIP_COUNTRY_DATABASE_PATH",
+		},
+		cli.StringFlag{
+			Name: "geoip-asn-database-path",
+			Value: "",
+			Usage: "path to the GeoIP ASN database",
+			EnvVar: "MAILSTATS_GEOIP_ASN_DATABASE_PATH",
+		},
+		cli.StringFlag{
+			Name: "hosting-asns",
+			Value: "",
+			Usage: "path to a file listing the ASNs of hosting and cloud providers, one per line, instead of the bundled list",
+			EnvVar: "MAILSTATS_HOSTING_ASNS",
+		},
 		cli.BoolFlag{
 			Name: "geoip",
 			Usage: "enable geolocation of IP addresses",
 			EnvVar: "MAILSTATS_GEOIP",
 		},
+		cli.BoolFlag{
+			Name: "dns-enrichment",
+			Usage: "enable the DNS lookups about the senders (reverse DNS, MX, NS, blocklists)",
+			EnvVar: "MAILSTATS_DNS_ENRICHMENT",
+		},
+		cli.IntFlag{
+			Name: "dns-rate",
+			Usage: "maximum number of DNS queries per second (0 for no limit)",
+			Value: 50,
+			EnvVar: "MAILSTATS_DNS_RATE",
+		},
+		cli.DurationFlag{
+			Name: "dns-cache-ttl",
+			Usage: "how long the DNS answers are cached",
+			Value: time.Hour,
+			EnvVar: "MAILSTATS_DNS_CACHE_TTL",
+		},
+		cli.StringSliceFlag{
+			Name: "dnsbl",
+			Usage: "DNS blocklist of IP addresses, like zen.spamhaus.org (can be specified multiple times)",
+			EnvVar: "MAILSTATS_DNSBL",
+		},
+		cli.StringSliceFlag{
+			Name: "uribl",
+			Usage: "DNS blocklist of domains, like dbl.spamhaus.org or multi.surbl.org (can be specified multiple times)",
+			EnvVar: "MAILSTATS_URIBL",
+		},
+		cli.StringSliceFlag{
+			Name: "protected-domain",
+			Usage: "domain to protect against lookalike domains in the senders and URLs (can be specified multiple times)",
+			EnvVar: "MAILSTATS_PROTECTED_DOMAINS",
+		},
+		cli.BoolFlag{
+			Name: "threading",
+			Usage: "group the mails in threads, and remember the threads in the cache directory",
+			EnvVar: "MAILSTATS_THREADING",
+		},
+		cli.BoolFlag{
+			Name: "dlp",
+			Usage: "enable the data-loss-prevention detectors (payment cards, national IDs, IBANs, API and private keys)",
+			EnvVar: "MAILSTATS_DLP",
+		},
+		cli.StringFlag{
+			Name: "dlp-config",
+			Value: "",
+			Usage: "path to a JSON file that configures the DLP detectors, thresholds and custom classifiers (implies --dlp)",
+			EnvVar: "MAILSTATS_DLP_CONFIG",
+		},
+		cli.BoolFlag{
+			Name: "classifier",
+			Usage: "classify the mails with the Bayesian model built by the train command",
+			EnvVar: "MAILSTATS_CLASSIFIER",
+		},
+		cli.StringFlag{
+			Name: "classifier-model",
+			Value: "",
+			Usage: "path to the model of the Bayesian classifier (default: classifier.gob in the cache directory)",
+			EnvVar: "MAILSTATS_CLASSIFIER_MODEL",
+		},
+		cli.StringFlag{
+			Name: "vip-directory",
+			Value: "",
+			Usage: "path to a JSON file listing the VIPs to protect against impersonation",
+			EnvVar: "MAILSTATS_VIP_DIRECTORY",
+		},
+		cli.StringSliceFlag{
+			Name: "internal-network",
+			Usage: "internal network, as a CIDR, used to find the first external hop of Received headers (can be specified multiple times)",
+			EnvVar: "MAILSTATS_INTERNAL_NETWORKS",
+		},
+		cli.StringSliceFlag{
+			Name: "trusted-network",
+			Usage: "network, as a CIDR, of the SMTP clients that submit the mails of the organization, to find the direction of the mails; the loopback is only trusted when listed (can be specified multiple times)",
+			EnvVar: "MAILSTATS_TRUSTED_NETWORKS",
+		},
+		cli.StringSliceFlag{
+			Name: "internal-domain",
+			Usage: "domain of the organization, to find the direction of the mails (can be specified multiple times)",
+			EnvVar: "MAILSTATS_INTERNAL_DOMAINS",
+		},
 		cli.StringSliceFlag{
 			Name: "broker",
 			Usage: "kafka broker, for kafka output (can be specified multiple times)",
@@ -218,6 +317,30 @@ func MakeApp() *cli.App {
 			EnvVar: "MAILSTATS_PHISHTANK_APPKEY",
 			Value: "",
 		},
+		cli.StringFlag{
+			Name: "logos-dir",
+			Usage: "Directory of the brand logos library (default: logos in the cache directory)",
+			EnvVar: "MAILSTATS_LOGOS_DIR",
+			Value: "",
+		},
+		cli.IntFlag{
+			Name: "logos-max-distance",
+			Usage: "Maximum Hamming distance between the perceptual hashes of an image and of a brand logo",
+			EnvVar: "MAILSTATS_LOGOS_MAX_DISTANCE",
+			Value: 8,
+		},
+		cli.StringFlag{
+			Name: "smime-trust-store",
+			Usage: "PEM file or directory of the CA certificates trusted for S/MIME signatures (default: system roots)",
+			EnvVar: "MAILSTATS_SMIME_TRUST_STORE",
+			Value: "",
+		},
+		cli.StringFlag{
+			Name: "pgp-keyring",
+			Usage: "File or directory of the OpenPGP public keys trusted for signatures",
+			EnvVar: "MAILSTATS_PGP_KEYRING",
+			Value: "",
+		},
 
 	}
 	app.Version = Version
@@ -318,6 +441,40 @@ func MakeApp() *cli.App {
 			},
 			Action: actions.MBoxAction,
 		},
+		{
+			Name:  "train",
+			Usage: "add mails to a class of the Bayesian classifier",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "class, c",
+					Usage: "the class of the mails (ham, spam, phish, newsletter, or any other name)",
+				},
+				cli.StringFlag{
+					Name:  "mbox",
+					Usage: "the mbox file to learn",
+				},
+				cli.StringFlag{
+					Name:  "filename, f",
+					Usage: "the mail file to learn (default: read the mail from stdin)",
+				},
+			},
+			Action: actions.TrainAction,
+		},
+		{
+			Name:  "classify",
+			Usage: "classify mails with the Bayesian classifier",
+			Flags: []cli.Flag{
+				cli.StringFlag{
+					Name:  "mbox",
+					Usage: "the mbox file to classify",
+				},
+				cli.StringFlag{
+					Name:  "filename, f",
+					Usage: "the mail file to classify (default: read the mail from stdin)",
+				},
+			},
+			Action: actions.ClassifyAction,
+		},
 		{
 			Name:  "maildir",
 			Usage: "read a maildir directory",
@@ -23,6 +23,15 @@ type Args struct {
 	GeoIP         GeoIPArgs
 	Elasticsearch ElasticsearchArgs
 	Phishtank     PhishtankArgs
+	Logos         LogosArgs
+	Crypto        CryptoArgs
+	Network       NetworkArgs
+	DNS           DNSArgs
+	Lookalike     LookalikeArgs
+	VIP           VIPArgs
+	Threading     ThreadingArgs
+	DLP           DLPArgs
+	Classifier    ClassifierArgs
 	Secret        *memguard.LockedBuffer `json:"-"`
 	NbParsers     int
 	NoDKIM        bool
@@ -51,6 +60,15 @@ func GetArgs(c *cli.Context) (*Args, error) {
 		&args.GeoIP,
 		&args.Elasticsearch,
 		&args.Phishtank,
+		&args.Logos,
+		&args.Crypto,
+		&args.Network,
+		&args.DNS,
+		&args.Lookalike,
+		&args.VIP,
+		&args.Threading,
+		&args.DLP,
+		&args.Classifier,
 	}
 
 	for _, i := range toInit {
@@ -0,0 +1,30 @@
+package arguments
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+type ClassifierArgs struct {
+	Enabled bool
+	// ModelPath is the file where the train command saves the model of the Bayesian classifier
+	ModelPath string
+}
+
+func (args *ClassifierArgs) Populate(c *cli.Context) {
+	args.Enabled = c.GlobalBool("classifier")
+	args.ModelPath = strings.TrimSpace(c.GlobalString("classifier-model"))
+	if args.ModelPath == "" {
+		cacheDir := strings.TrimSpace(c.GlobalString("cache-dir"))
+		if cacheDir == "" {
+			cacheDir = "/var/lib/mailstats"
+		}
+		args.ModelPath = filepath.Join(cacheDir, "classifier.gob")
+	}
+}
+
+func (args ClassifierArgs) Verify() error {
+	return nil
+}
@@ -0,0 +1,32 @@
+package arguments
+
+import (
+	"os"
+	"strings"
+
+	"github.com/storozhukBM/verifier"
+	"github.com/urfave/cli"
+)
+
+type CryptoArgs struct {
+	TrustStore string
+	Keyring    string
+}
+
+func (args *CryptoArgs) Populate(c *cli.Context) {
+	args.TrustStore = strings.TrimSpace(c.GlobalString("smime-trust-store"))
+	args.Keyring = strings.TrimSpace(c.GlobalString("pgp-keyring"))
+}
+
+func (args CryptoArgs) Verify() error {
+	v := verifier.New()
+	if args.TrustStore != "" {
+		_, err := os.Stat(args.TrustStore)
+		v.That(err == nil, "The S/MIME trust store does not exist")
+	}
+	if args.Keyring != "" {
+		_, err := os.Stat(args.Keyring)
+		v.That(err == nil, "The OpenPGP keyring does not exist")
+	}
+	return v.GetError()
+}
@@ -0,0 +1,30 @@
+package arguments
+
+import (
+	"os"
+	"strings"
+
+	"github.com/storozhukBM/verifier"
+	"github.com/urfave/cli"
+)
+
+type DLPArgs struct {
+	Enabled bool
+	// ConfigPath is the JSON file that configures the detectors and the custom classifiers
+	ConfigPath string
+}
+
+func (args *DLPArgs) Populate(c *cli.Context) {
+	args.ConfigPath = strings.TrimSpace(c.GlobalString("dlp-config"))
+	args.Enabled = c.GlobalBool("dlp") || args.ConfigPath != ""
+}
+
+func (args DLPArgs) Verify() error {
+	if args.ConfigPath == "" {
+		return nil
+	}
+	v := verifier.New()
+	_, err := os.Stat(args.ConfigPath)
+	v.That(err == nil, "The DLP configuration '%s' can not be read", args.ConfigPath)
+	return v.GetError()
+}
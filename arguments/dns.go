@@ -0,0 +1,46 @@
+package arguments
+
+import (
+	"strings"
+	"time"
+
+	"github.com/storozhukBM/verifier"
+	"github.com/urfave/cli"
+)
+
+type DNSArgs struct {
+	Enabled  bool
+	Rate     int
+	CacheTTL time.Duration
+	DNSBLs   []string
+	URIBLs   []string
+}
+
+func (args *DNSArgs) Populate(c *cli.Context) {
+	args.Enabled = c.GlobalBool("dns-enrichment")
+	args.Rate = c.GlobalInt("dns-rate")
+	args.CacheTTL = c.GlobalDuration("dns-cache-ttl")
+	args.DNSBLs = splitList(c.GlobalStringSlice("dnsbl"))
+	args.URIBLs = splitList(c.GlobalStringSlice("uribl"))
+}
+
+func (args DNSArgs) Verify() error {
+	v := verifier.New()
+	v.That(args.Rate >= 0, "The DNS queries rate must be positive")
+	v.That(args.CacheTTL >= 0, "The DNS cache TTL must be positive")
+	return v.GetError()
+}
+
+// splitList splits the comma separated values of a repeated flag.
+func splitList(values []string) []string {
+	var list []string
+	for _, value := range values {
+		for _, s := range strings.Split(value, ",") {
+			s = strings.TrimSpace(s)
+			if s != "" {
+				list = append(list, s)
+			}
+		}
+	}
+	return list
+}
@@ -5,30 +5,44 @@ import (
 	"github.com/oschwald/geoip2-golang"
 	"github.com/storozhukBM/verifier"
 	"github.com/urfave/cli"
+	"os"
 	"strings"
 )
 
 type GeoIPArgs struct {
 	Enabled bool
 	DatabasePath string
+	CountryDatabasePath string
+	ASNDatabasePath string
+	// file listing the ASNs of hosting and cloud providers, instead of the bundled list
+	HostingASNsPath string
 }
 
 func (args *GeoIPArgs) Populate(c *cli.Context) {
 	args.Enabled = c.GlobalBool("geoip")
 	args.DatabasePath = strings.TrimSpace(c.GlobalString("geoip-database-path"))
+	args.CountryDatabasePath = strings.TrimSpace(c.GlobalString("geoip-country-database-path"))
+	args.ASNDatabasePath = strings.TrimSpace(c.GlobalString("geoip-asn-database-path"))
+	args.HostingASNsPath = strings.TrimSpace(c.GlobalString("hosting-asns"))
 }
 
 func (args GeoIPArgs) Verify() error {
 	v := verifier.New()
 	if args.Enabled {
-		v.That(args.DatabasePath != "", "Specify GeoIP database")
-		if args.DatabasePath != "" {
-			r, err := geoip2.Open(args.DatabasePath)
-			v.That(err == nil, fmt.Sprintf("Error loading database path: %s", err))
-			if err != nil {
-				_ = r.Close()
+		v.That(args.DatabasePath != "" || args.CountryDatabasePath != "" || args.ASNDatabasePath != "", "Specify GeoIP database")
+		for _, path := range []string{args.DatabasePath, args.CountryDatabasePath, args.ASNDatabasePath} {
+			if path != "" {
+				r, err := geoip2.Open(path)
+				v.That(err == nil, fmt.Sprintf("Error loading database path: %s", err))
+				if err == nil {
+					_ = r.Close()
+				}
 			}
 		}
+		if args.HostingASNsPath != "" {
+			_, err := os.Stat(args.HostingASNsPath)
+			v.That(err == nil, "The hosting ASNs file does not exist")
+		}
 	}
 	return v.GetError()
 }
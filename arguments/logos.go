@@ -0,0 +1,32 @@
+package arguments
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/storozhukBM/verifier"
+	"github.com/urfave/cli"
+)
+
+type LogosArgs struct {
+	Directory   string
+	MaxDistance int
+}
+
+func (args *LogosArgs) Populate(c *cli.Context) {
+	args.Directory = strings.TrimSpace(c.GlobalString("logos-dir"))
+	if args.Directory == "" {
+		cacheDir := strings.TrimSpace(c.GlobalString("cache-dir"))
+		if cacheDir == "" {
+			cacheDir = "/var/lib/mailstats"
+		}
+		args.Directory = filepath.Join(cacheDir, "logos")
+	}
+	args.MaxDistance = c.GlobalInt("logos-max-distance")
+}
+
+func (args LogosArgs) Verify() error {
+	v := verifier.New()
+	v.That(args.MaxDistance >= 0 && args.MaxDistance < 32, "The maximum distance between logos hashes must be between 0 and 31")
+	return v.GetError()
+}
@@ -0,0 +1,27 @@
+package arguments
+
+import (
+	"strings"
+
+	"github.com/storozhukBM/verifier"
+	"github.com/urfave/cli"
+)
+
+type LookalikeArgs struct {
+	ProtectedDomains []string
+}
+
+func (args *LookalikeArgs) Populate(c *cli.Context) {
+	args.ProtectedDomains = splitList(c.GlobalStringSlice("protected-domain"))
+	for i, d := range args.ProtectedDomains {
+		args.ProtectedDomains[i] = strings.Trim(strings.ToLower(d), ".")
+	}
+}
+
+func (args LookalikeArgs) Verify() error {
+	v := verifier.New()
+	for _, d := range args.ProtectedDomains {
+		v.That(strings.Contains(d, "."), "Invalid protected domain '%s'", d)
+	}
+	return v.GetError()
+}
@@ -0,0 +1,81 @@
+package arguments
+
+import (
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/storozhukBM/verifier"
+	"github.com/urfave/cli"
+)
+
+type NetworkArgs struct {
+	InternalNetworks []string
+	// TrustedNetworks are the clients that submit the mails of the organization
+	TrustedNetworks []string
+	InternalDomains []string
+}
+
+func (args *NetworkArgs) Populate(c *cli.Context) {
+	args.InternalNetworks = splitList(c.GlobalStringSlice("internal-network"))
+	args.TrustedNetworks = splitList(c.GlobalStringSlice("trusted-network"))
+	args.InternalDomains = splitList(c.GlobalStringSlice("internal-domain"))
+	for i, d := range args.InternalDomains {
+		args.InternalDomains[i] = strings.Trim(strings.ToLower(d), ".")
+	}
+}
+
+func (args NetworkArgs) Verify() error {
+	v := verifier.New()
+	for _, n := range args.InternalNetworks {
+		_, err := parseNetwork(n)
+		v.That(err == nil, "Invalid internal network '%s'", n)
+	}
+	for _, n := range args.TrustedNetworks {
+		_, err := parseNetwork(n)
+		v.That(err == nil, "Invalid trusted network '%s'", n)
+	}
+	for _, d := range args.InternalDomains {
+		v.That(strings.Contains(d, "."), "Invalid internal domain '%s'", d)
+	}
+	return v.GetError()
+}
+
+// Internal returns the configured internal networks.
+func (args NetworkArgs) Internal() []*net.IPNet {
+	return parseNetworks(args.InternalNetworks)
+}
+
+// Trusted returns the trusted networks. The internal networks are trusted as well.
+func (args NetworkArgs) Trusted() []*net.IPNet {
+	return append(parseNetworks(args.TrustedNetworks), args.Internal()...)
+}
+
+func parseNetworks(list []string) []*net.IPNet {
+	networks := make([]*net.IPNet, 0, len(list))
+	for _, n := range list {
+		network, err := parseNetwork(n)
+		if err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// parseNetwork parses a CIDR, or a single IP address.
+func parseNetwork(s string) (*net.IPNet, error) {
+	if !strings.Contains(s, "/") {
+		ip := net.ParseIP(s)
+		if ip == nil {
+			return nil, fmt.Errorf("invalid IP address: %s", s)
+		}
+		bits := 8 * net.IPv6len
+		if ip.To4() != nil {
+			ip = ip.To4()
+			bits = 8 * net.IPv4len
+		}
+		return &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)}, nil
+	}
+	_, network, err := net.ParseCIDR(s)
+	return network, err
+}
@@ -0,0 +1,27 @@
+package arguments
+
+import (
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli"
+)
+
+type ThreadingArgs struct {
+	Enabled bool
+	// StatePath is the file where the known messages and threads are saved
+	StatePath string
+}
+
+func (args *ThreadingArgs) Populate(c *cli.Context) {
+	args.Enabled = c.GlobalBool("threading")
+	cacheDir := strings.TrimSpace(c.GlobalString("cache-dir"))
+	if cacheDir == "" {
+		cacheDir = "/var/lib/mailstats"
+	}
+	args.StatePath = filepath.Join(cacheDir, "threads.gob")
+}
+
+func (args ThreadingArgs) Verify() error {
+	return nil
+}
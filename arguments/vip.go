@@ -0,0 +1,35 @@
+package arguments
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/storozhukBM/verifier"
+	"github.com/urfave/cli"
+)
+
+type VIPArgs struct {
+	DirectoryPath string
+	// StatePath is the file where the addresses that used the name of a VIP are remembered
+	StatePath string
+}
+
+func (args *VIPArgs) Populate(c *cli.Context) {
+	args.DirectoryPath = strings.TrimSpace(c.GlobalString("vip-directory"))
+	cacheDir := strings.TrimSpace(c.GlobalString("cache-dir"))
+	if cacheDir == "" {
+		cacheDir = "/var/lib/mailstats"
+	}
+	args.StatePath = filepath.Join(cacheDir, "vip_seen.json")
+}
+
+func (args VIPArgs) Verify() error {
+	if args.DirectoryPath == "" {
+		return nil
+	}
+	v := verifier.New()
+	_, err := os.Stat(args.DirectoryPath)
+	v.That(err == nil, "The VIP directory '%s' can not be read", args.DirectoryPath)
+	return v.GetError()
+}
@@ -0,0 +1,30 @@
+package bayes
+
+import (
+	"os"
+	"path/filepath"
+	"syscall"
+)
+
+// LockModel takes an exclusive lock on the model at path, so that two train commands do not
+// overwrite each other's mails. The lock is held on a separate file, as Save replaces the model
+// file. The returned function releases the lock.
+func LockModel(path string) (func() error, error) {
+	err := os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.OpenFile(path+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	err = syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return func() error {
+		defer f.Close()
+		return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+	}, nil
+}
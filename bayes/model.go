@@ -0,0 +1,175 @@
+// Package bayes classifies the mails with a multinomial naive Bayes model, trained on the tokens
+// of the features: the words of the body, some header values, the URL hosts and the attachment
+// types. The classes are free: ham, spam, phish, newsletter or any other.
+package bayes
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// Class holds the token counts of the mails learned in a class.
+type Class struct {
+	Documents int
+	Tokens    map[string]int
+	// Total is the sum of the token counts
+	Total int
+}
+
+// Model is the state of the classifier. Learning is incremental: the counts of the new mails are
+// added to the model.
+type Model struct {
+	Classes map[string]*Class
+	// Vocabulary counts the mails in which each token appears
+	Vocabulary map[string]int
+}
+
+func NewModel() *Model {
+	return &Model{
+		Classes:    make(map[string]*Class),
+		Vocabulary: make(map[string]int),
+	}
+}
+
+var classRE = regexp.MustCompile(`^[a-z0-9][a-z0-9_.-]*$`)
+
+// ValidClass reports whether name can be used as a class name.
+func ValidClass(name string) bool {
+	return classRE.MatchString(name)
+}
+
+// Learn adds the tokens of a mail to class.
+func (m *Model) Learn(class string, tokens map[string]int) error {
+	if !ValidClass(class) {
+		return fmt.Errorf("invalid class name: '%s'", class)
+	}
+	c := m.Classes[class]
+	if c == nil {
+		c = &Class{Tokens: make(map[string]int)}
+		m.Classes[class] = c
+	}
+	c.Documents++
+	for token, count := range tokens {
+		if count <= 0 {
+			continue
+		}
+		c.Tokens[token] += count
+		c.Total += count
+		m.Vocabulary[token]++
+	}
+	return nil
+}
+
+// Documents returns the number of mails learned in all the classes.
+func (m *Model) Documents() int {
+	n := 0
+	for _, c := range m.Classes {
+		n += c.Documents
+	}
+	return n
+}
+
+// Classify returns the probabilities of the classes for a mail, with Laplace smoothing. The tokens
+// that were never learned are ignored. It returns nil when the model does not have two classes.
+func (m *Model) Classify(tokens map[string]int) *models.Classification {
+	if len(m.Classes) < 2 {
+		return nil
+	}
+	total := float64(m.Documents())
+	vocabulary := float64(len(m.Vocabulary))
+	known := 0
+	for token := range tokens {
+		if m.Vocabulary[token] > 0 {
+			known++
+		}
+	}
+
+	names := make([]string, 0, len(m.Classes))
+	for name := range m.Classes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	scores := make([]float64, len(names))
+	best := math.Inf(-1)
+	for i, name := range names {
+		c := m.Classes[name]
+		score := math.Log(float64(c.Documents) / total)
+		denominator := math.Log(float64(c.Total) + vocabulary)
+		for token, count := range tokens {
+			if count <= 0 || m.Vocabulary[token] == 0 {
+				continue
+			}
+			score += float64(count) * (math.Log(float64(c.Tokens[token]+1)) - denominator)
+		}
+		scores[i] = score
+		if score > best {
+			best = score
+		}
+	}
+
+	// softmax, shifted by the best score to avoid the underflow
+	sum := 0.0
+	for i := range scores {
+		scores[i] = math.Exp(scores[i] - best)
+		sum += scores[i]
+	}
+	result := &models.Classification{
+		Probabilities: make(map[string]float64, len(names)),
+		Tokens:        known,
+	}
+	for i, name := range names {
+		p := scores[i] / sum
+		result.Probabilities[name] = math.Round(p*10000) / 10000
+		if p > result.Probability {
+			result.Class = name
+			result.Probability = p
+		}
+	}
+	result.Probability = math.Round(result.Probability*10000) / 10000
+	return result
+}
+
+// LoadModel reads the model saved at path. A missing file is an empty model.
+func LoadModel(path string) (*Model, error) {
+	content, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return NewModel(), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	m := NewModel()
+	err = gob.NewDecoder(bytes.NewReader(content)).Decode(m)
+	if err != nil {
+		return nil, fmt.Errorf("invalid classifier model '%s': %s", path, err)
+	}
+	return m, nil
+}
+
+// Save writes the model at path, atomically.
+func (m *Model) Save(path string) error {
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(m)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(path), 0755)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	err = ioutil.WriteFile(tmp, buf.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
@@ -0,0 +1,159 @@
+package bayes
+
+import (
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func trainedModel(t *testing.T) *Model {
+	m := NewModel()
+	if err := m.Learn("spam", map[string]int{"w:viagra": 2}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Learn("ham", map[string]int{"w:meeting": 1}); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestLearn(t *testing.T) {
+	m := trainedModel(t)
+	if err := m.Learn("Not A Class", map[string]int{"w:x": 1}); err == nil {
+		t.Error("invalid class name accepted")
+	}
+	if err := m.Learn("spam", map[string]int{"w:viagra": 1, "w:ignored": 0}); err != nil {
+		t.Fatal(err)
+	}
+	spam := m.Classes["spam"]
+	if spam.Documents != 2 || spam.Tokens["w:viagra"] != 3 || spam.Total != 3 {
+		t.Errorf("spam class = %+v", spam)
+	}
+	if m.Vocabulary["w:viagra"] != 2 || m.Vocabulary["w:meeting"] != 1 || len(m.Vocabulary) != 2 {
+		t.Errorf("vocabulary = %v", m.Vocabulary)
+	}
+	if m.Documents() != 3 {
+		t.Errorf("documents = %d, want 3", m.Documents())
+	}
+}
+
+func TestClassify(t *testing.T) {
+	m := NewModel()
+	if err := m.Learn("spam", map[string]int{"w:viagra": 1}); err != nil {
+		t.Fatal(err)
+	}
+	if m.Classify(map[string]int{"w:viagra": 1}) != nil {
+		t.Error("a model with one class classifies")
+	}
+
+	m = trainedModel(t)
+	// vocabulary of 2 tokens: P(viagra|spam) = (2+1)/(2+2), P(viagra|ham) = (0+1)/(1+2) with the
+	// Laplace smoothing, and equal priors
+	spam, ham := 0.75, 1.0/3
+	want := math.Round(spam/(spam+ham)*10000) / 10000
+	c := m.Classify(map[string]int{"w:viagra": 1, "w:unknown": 3})
+	if c == nil {
+		t.Fatal("no classification")
+	}
+	if c.Class != "spam" || c.Probability != want || c.Probabilities["spam"] != want {
+		t.Errorf("classification = %+v, want spam with %v", c, want)
+	}
+	if c.Probabilities["ham"] != math.Round(ham/(spam+ham)*10000)/10000 {
+		t.Errorf("ham probability = %v", c.Probabilities["ham"])
+	}
+	if c.Tokens != 1 {
+		t.Errorf("known tokens = %d, want 1", c.Tokens)
+	}
+
+	// only unknown tokens: the priors decide
+	c = m.Classify(map[string]int{"w:unknown": 1})
+	if c.Probabilities["spam"] != 0.5 || c.Probabilities["ham"] != 0.5 || c.Tokens != 0 {
+		t.Errorf("classification of unknown tokens = %+v", c)
+	}
+
+	// a long mail does not underflow
+	c = m.Classify(map[string]int{"w:meeting": 100000})
+	if c.Class != "ham" || c.Probability != 1 {
+		t.Errorf("classification of a long mail = %+v", c)
+	}
+}
+
+func TestSaveLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bayes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "model", "bayes.gob")
+
+	m, err := LoadModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(m.Classes) != 0 || m.Vocabulary == nil {
+		t.Errorf("missing model = %+v, want an empty model", m)
+	}
+
+	m = trainedModel(t)
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if loaded.Documents() != 2 || loaded.Classes["spam"].Tokens["w:viagra"] != 2 || loaded.Vocabulary["w:meeting"] != 1 {
+		t.Errorf("loaded model = %+v", loaded)
+	}
+	if _, err := os.Stat(path + ".tmp"); !os.IsNotExist(err) {
+		t.Errorf("temporary file left: %v", err)
+	}
+
+	if err := ioutil.WriteFile(path, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadModel(path); err == nil {
+		t.Error("invalid model loaded")
+	}
+}
+
+func TestLockModel(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bayes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "bayes.gob")
+
+	unlock, err := LockModel(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	locked := make(chan func() error)
+	go func() {
+		unlock, err := LockModel(path)
+		if err != nil {
+			t.Error(err)
+		}
+		locked <- unlock
+	}()
+	select {
+	case <-locked:
+		t.Fatal("the model was locked twice")
+	case <-time.After(100 * time.Millisecond):
+	}
+	if err := unlock(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case unlock := <-locked:
+		if unlock != nil {
+			_ = unlock()
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the model was not released")
+	}
+}
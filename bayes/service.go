@@ -0,0 +1,117 @@
+package bayes
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"go.uber.org/fx"
+)
+
+// reloadInterval is the interval between the checks of the model file, that the train command
+// updates while the service runs.
+var reloadInterval = time.Minute
+
+type Classifier interface {
+	utils.Service
+	utils.Prestartable
+	utils.Startable
+	// Classify returns the probabilities of the classes for a mail, or nil when the model is not
+	// trained.
+	Classify(features *models.FeaturesMail) *models.Classification
+}
+
+type impl struct {
+	modelPath string
+	logger    log15.Logger
+	lock      sync.RWMutex
+	model     *Model
+	modTime   time.Time
+}
+
+func NewClassifier(modelPath string, logger log15.Logger) Classifier {
+	return &impl{modelPath: modelPath, logger: logger, model: NewModel()}
+}
+
+func (i *impl) Name() string {
+	return "BayesClassifier"
+}
+
+func (i *impl) Prestart() error {
+	_, err := i.reload()
+	return err
+}
+
+// reload reads the model file when it was modified since the last load.
+func (i *impl) reload() (bool, error) {
+	info, err := os.Stat(i.modelPath)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	i.lock.RLock()
+	same := info.ModTime().Equal(i.modTime)
+	i.lock.RUnlock()
+	if same {
+		return false, nil
+	}
+	model, err := LoadModel(i.modelPath)
+	if err != nil {
+		return false, err
+	}
+	i.lock.Lock()
+	i.model = model
+	i.modTime = info.ModTime()
+	i.lock.Unlock()
+	i.logger.Info("Classifier model loaded", "path", i.modelPath, "classes", len(model.Classes), "mails", model.Documents(), "tokens", len(model.Vocabulary))
+	return true, nil
+}
+
+// Start reloads the model when the file changes.
+func (i *impl) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(reloadInterval):
+		}
+		_, err := i.reload()
+		if err != nil {
+			i.logger.Warn("Error reloading the classifier model", "error", err)
+		}
+	}
+}
+
+func (i *impl) Classify(features *models.FeaturesMail) *models.Classification {
+	t := Tokens(features)
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+	return i.model.Classify(t)
+}
+
+type Params struct {
+	fx.In
+	Args   *arguments.Args `optional:"true"`
+	Logger log15.Logger    `optional:"true"`
+}
+
+var Service = fx.Provide(func(lc fx.Lifecycle, params Params) Classifier {
+	if params.Args == nil || !params.Args.Classifier.Enabled {
+		return nil
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	c := NewClassifier(params.Args.Classifier.ModelPath, logger)
+	utils.Append(lc, c, logger)
+	return c
+})
@@ -0,0 +1,80 @@
+package bayes
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestClassifierReload(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bayes")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "bayes.gob")
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	features := &models.FeaturesMail{BagOfWords: map[string]int{"viagra": 1}}
+
+	c := NewClassifier(path, logger)
+	if err := c.Prestart(); err != nil {
+		t.Fatal(err)
+	}
+	if c.Classify(features) != nil {
+		t.Error("classification without a model")
+	}
+
+	m := trainedModel(t)
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	reloaded, err := c.(*impl).reload()
+	if err != nil || !reloaded {
+		t.Fatalf("reload = %v, %v", reloaded, err)
+	}
+	if result := c.Classify(features); result == nil || result.Class != "spam" {
+		t.Errorf("classification = %+v, want spam", result)
+	}
+	if reloaded, _ := c.(*impl).reload(); reloaded {
+		t.Error("model reloaded without a change")
+	}
+
+	// a train command changes the model
+	if err := m.Learn("ham", map[string]int{"w:viagra": 10}); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	later := time.Now().Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded, err := c.(*impl).reload(); err != nil || !reloaded {
+		t.Fatalf("reload after a change = %v, %v", reloaded, err)
+	}
+	if result := c.Classify(features); result == nil || result.Class != "ham" {
+		t.Errorf("classification after a reload = %+v, want ham", result)
+	}
+
+	// a broken model keeps the previous one
+	if err := ioutil.WriteFile(path, []byte("garbage"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	later = later.Add(time.Minute)
+	if err := os.Chtimes(path, later, later); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.(*impl).reload(); err == nil {
+		t.Error("broken model reloaded")
+	}
+	if result := c.Classify(features); result == nil || result.Class != "ham" {
+		t.Errorf("classification after a broken reload = %+v, want ham", result)
+	}
+}
@@ -0,0 +1,100 @@
+package bayes
+
+import (
+	"path/filepath"
+	"strings"
+	"unicode"
+
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+// maxCount bounds the count of a token in a mail, so that a word repeated in a long mail does not
+// decide alone.
+const maxCount = 5
+
+type tokens map[string]int
+
+func (t tokens) add(prefix string, value string, count int) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "" || count <= 0 {
+		return
+	}
+	token := prefix + ":" + value
+	t[token] += count
+	if t[token] > maxCount {
+		t[token] = maxCount
+	}
+}
+
+// Tokens returns the tokens of a mail, prefixed by their origin: the stemmed words of the body
+// (w), the words of the subject (s), the sender and reply-to domains, the mailer, the category, the
+// language, the names of the headers (h), the URL hosts (url) and the types of the attachments.
+func Tokens(features *models.FeaturesMail) map[string]int {
+	t := make(tokens)
+	for word, count := range features.BagOfWords {
+		t.add("w", word, count)
+	}
+	subject := features.Title
+	if features.Obfuscation != nil && features.Obfuscation.Subject != nil && features.Obfuscation.Subject.Deobfuscated != "" {
+		subject = features.Obfuscation.Subject.Deobfuscated
+	}
+	words := strings.FieldsFunc(strings.ToLower(subject), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	for _, word := range words {
+		if len([]rune(word)) > 2 {
+			t.add("s", word, 1)
+		}
+	}
+
+	var fromDomain string
+	if features.From != nil {
+		fromDomain = utils.DomainFromAddress(features.From.Address.Address)
+		t.add("from", fromDomain, 1)
+	}
+	for _, addr := range features.ReplyTo {
+		if d := utils.DomainFromAddress(addr.Address); d != fromDomain {
+			t.add("replyto", d, 1)
+		}
+	}
+	if features.Mailer != nil {
+		t.add("mailer", features.Mailer.Name, 1)
+		t.add("mailerkind", features.Mailer.Kind, 1)
+	}
+	t.add("category", features.Category, 1)
+	t.add("esp", features.ESP, 1)
+	t.add("lang", features.Language, 1)
+	if i := strings.IndexByte(features.ContentType, ';'); i >= 0 {
+		t.add("ct", features.ContentType[:i], 1)
+	} else {
+		t.add("ct", features.ContentType, 1)
+	}
+	for _, name := range features.HeaderOrder {
+		t.add("h", name, 1)
+	}
+
+	for _, u := range features.URLs {
+		t.add("url", utils.URLHost(u), 1)
+	}
+
+	var walk func(a *models.Attachment)
+	walk = func(a *models.Attachment) {
+		if a == nil {
+			return
+		}
+		t.add("att", a.InferredType, 1)
+		t.add("ext", strings.TrimPrefix(filepath.Ext(a.Name), "."), 1)
+		if a.Executable {
+			t.add("att", "executable", 1)
+		}
+		walk(a.SubAttachment)
+		for _, sub := range a.SubAttachments {
+			walk(sub)
+		}
+	}
+	for _, a := range features.Attachments {
+		walk(a)
+	}
+	return t
+}
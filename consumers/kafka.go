@@ -71,19 +71,28 @@ func (c *KafkaConsumer) Consume(features *models.FeaturesMail) error {
 	if err != nil {
 		return err
 	}
+	direction := ""
+	if features.Direction != nil {
+		direction = features.Direction.Direction
+	}
 	m := &sarama.ProducerMessage{
 		Topic: c.topic,
 		Key:   sarama.StringEncoder(features.Family),
 		Value: sarama.ByteEncoder(b),
 		Metadata: map[string]string{
-			"uid":    features.UID,
-			"family": features.Family,
+			"uid":       features.UID,
+			"family":    features.Family,
+			"direction": direction,
 		},
 		Headers: []sarama.RecordHeader{
 			{
 				Key:   []byte("Content-Type"),
 				Value: []byte("application/json"),
 			},
+			{
+				Key:   []byte("Direction"),
+				Value: []byte(direction),
+			},
 		},
 	}
 	c.client.Input() <- m
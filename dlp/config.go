@@ -0,0 +1,146 @@
+// Package dlp looks for the sensitive data that leaves the organization in the outbound mails:
+// payment cards, national identifiers, bank accounts, API keys and private keys, and the data
+// described by custom classifiers.
+//
+// The built-in detectors are enabled by default. They can be disabled, their thresholds changed,
+// and custom classifiers added in a JSON file:
+//
+//	{
+//	  "disabled": ["uk-nino"],
+//	  "thresholds": {"iban": {"min_count": 10, "min_confidence": 0.8}},
+//	  "classifiers": [
+//	    {"name": "project-atlas", "regex": "\\bATLAS-\\d{4}\\b", "keywords": ["confidential"]},
+//	    {"name": "codenames", "dictionary": ["Blue Falcon", "Nightshade"], "confidence": 0.8}
+//	  ]
+//	}
+//
+// The confidence of a value is raised when one of the keywords of the detector appears within
+// proximity bytes. A detector raises the DLP verdict when it finds min_count distinct values with
+// at least min_confidence.
+package dlp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"regexp"
+	"strings"
+)
+
+// defaultConfidence is the confidence of the values found by the custom classifiers.
+const defaultConfidence = 0.5
+
+// Classifier is a custom detector, by regular expression or by dictionary.
+type Classifier struct {
+	Name       string   `json:"name"`
+	Regex      string   `json:"regex,omitempty"`
+	Dictionary []string `json:"dictionary,omitempty"`
+	Keywords   []string `json:"keywords,omitempty"`
+	// Proximity is the distance, in bytes, at which the keywords are searched
+	Proximity  int        `json:"proximity,omitempty"`
+	Confidence float64    `json:"confidence,omitempty"`
+	Threshold  *Threshold `json:"threshold,omitempty"`
+}
+
+// Config is the configuration of the detectors.
+type Config struct {
+	Disabled    []string             `json:"disabled,omitempty"`
+	Thresholds  map[string]Threshold `json:"thresholds,omitempty"`
+	Classifiers []Classifier         `json:"classifiers,omitempty"`
+}
+
+// ReadConfig reads the configuration from a JSON file.
+func ReadConfig(path string) (*Config, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	c := new(Config)
+	err = json.Unmarshal(content, c)
+	if err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// detectors returns the enabled built-in detectors and the custom classifiers.
+func (c *Config) detectors() ([]*detector, error) {
+	if c == nil {
+		c = new(Config)
+	}
+	disabled := make(map[string]bool)
+	for _, name := range c.Disabled {
+		disabled[strings.TrimSpace(name)] = true
+	}
+	var detectors []*detector
+	for _, d := range builtins() {
+		if !disabled[d.name] {
+			detectors = append(detectors, d)
+		}
+	}
+	for _, cl := range c.Classifiers {
+		d, err := cl.detector()
+		if err != nil {
+			return nil, err
+		}
+		if !disabled[d.name] {
+			detectors = append(detectors, d)
+		}
+	}
+	for _, d := range detectors {
+		if t, ok := c.Thresholds[d.name]; ok {
+			d.threshold = t
+		}
+	}
+	return detectors, nil
+}
+
+func (cl Classifier) detector() (*detector, error) {
+	name := strings.TrimSpace(cl.Name)
+	if name == "" {
+		return nil, fmt.Errorf("DLP classifier without a name")
+	}
+	d := &detector{
+		name:       name,
+		confidence: cl.Confidence,
+		proximity:  cl.Proximity,
+		prefix:     2,
+	}
+	if d.confidence <= 0 {
+		d.confidence = defaultConfidence
+	}
+	for _, k := range cl.Keywords {
+		if k = strings.ToLower(strings.TrimSpace(k)); k != "" {
+			d.keywords = append(d.keywords, k)
+		}
+	}
+	switch {
+	case cl.Regex != "" && len(cl.Dictionary) > 0:
+		return nil, fmt.Errorf("DLP classifier '%s' has both a regex and a dictionary", name)
+	case cl.Regex != "":
+		re, err := regexp.Compile(cl.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex for DLP classifier '%s': %s", name, err)
+		}
+		d.re = re
+	case len(cl.Dictionary) > 0:
+		var words []string
+		for _, w := range cl.Dictionary {
+			if w = strings.TrimSpace(w); w != "" {
+				words = append(words, regexp.QuoteMeta(w))
+			}
+		}
+		if len(words) == 0 {
+			return nil, fmt.Errorf("empty dictionary for DLP classifier '%s'", name)
+		}
+		d.re = regexp.MustCompile(`(?i)(?:^|[^\p{L}\p{N}])(?P<secret>` + strings.Join(words, "|") + `)(?:[^\p{L}\p{N}]|$)`)
+	default:
+		return nil, fmt.Errorf("DLP classifier '%s' has neither a regex nor a dictionary", name)
+	}
+	if cl.Threshold != nil {
+		d.threshold = *cl.Threshold
+	} else {
+		d.threshold = Threshold{MinCount: 1, MinConfidence: d.confidence}
+	}
+	return d, nil
+}
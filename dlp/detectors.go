@@ -0,0 +1,352 @@
+package dlp
+
+import (
+	"math"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"github.com/stephane-martin/mailstats/extractors"
+)
+
+// keywordBoost is added to the confidence of a value when a keyword of the detector is nearby.
+const keywordBoost = 0.3
+
+// defaultProximity is the distance, in bytes, at which the keywords are searched around a value.
+const defaultProximity = 100
+
+// Threshold is the number of distinct values, with at least MinConfidence, that raises the verdict.
+type Threshold struct {
+	MinCount      int     `json:"min_count"`
+	MinConfidence float64 `json:"min_confidence"`
+}
+
+type detector struct {
+	name string
+	re   *regexp.Regexp
+	// validate checks the checksum or the structure of a value
+	validate func(value string) bool
+	// confidence of a valid value, without keyword
+	confidence float64
+	keywords   []string
+	proximity  int
+	// prefix and suffix are the number of letters and digits that are not redacted
+	prefix    int
+	suffix    int
+	threshold Threshold
+	// clear is set when the match itself is not sensitive, like the header of a private key
+	clear bool
+}
+
+// value returns the sensitive part of a match: the "secret" group if the regexp has one.
+func (d *detector) value(text string, loc []int) string {
+	for idx, name := range d.re.SubexpNames() {
+		if name == "secret" && loc[2*idx] >= 0 {
+			return text[loc[2*idx]:loc[2*idx+1]]
+		}
+	}
+	return text[loc[0]:loc[1]]
+}
+
+// score returns the confidence of the value found at loc.
+func (d *detector) score(text string, loc []int) float64 {
+	score := d.confidence
+	if len(d.keywords) == 0 {
+		return score
+	}
+	proximity := d.proximity
+	if proximity <= 0 {
+		proximity = defaultProximity
+	}
+	start, end := loc[0]-proximity, loc[1]+proximity
+	if start < 0 {
+		start = 0
+	}
+	if end > len(text) {
+		end = len(text)
+	}
+	around := strings.ToLower(text[start:end])
+	for _, k := range d.keywords {
+		if strings.Contains(around, k) {
+			// rounded, so that 0.6+0.3 reaches a 0.9 threshold
+			return math.Min(1, math.Round((score+keywordBoost)*100)/100)
+		}
+	}
+	return score
+}
+
+// redact replaces the letters and the digits of the value by stars, but the first prefix and the
+// last suffix ones.
+func redact(value string, prefix int, suffix int) string {
+	total := 0
+	for _, r := range value {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			total++
+		}
+	}
+	// never reveal more than half of the value
+	for prefix+suffix > total/2 && prefix+suffix > 0 {
+		if suffix > 0 {
+			suffix--
+		} else {
+			prefix--
+		}
+	}
+	idx := 0
+	return strings.Map(func(r rune) rune {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			return r
+		}
+		idx++
+		if idx <= prefix || idx > total-suffix {
+			return r
+		}
+		return '*'
+	}, value)
+}
+
+func onlyDigits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+func alphanumeric(s string) string {
+	return strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			return unicode.ToUpper(r)
+		}
+		return -1
+	}, s)
+}
+
+// builtins are the detectors that are enabled unless disabled by the configuration.
+func builtins() []*detector {
+	return []*detector{
+		{
+			name:       "payment-card",
+			re:         regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`),
+			validate:   validCard,
+			confidence: 0.6,
+			keywords:   []string{"card", "credit", "debit", "visa", "mastercard", "amex", "cvv", "cvc", "expir", "carte", "bancaire", "kreditkarte", "карт"},
+			suffix:     4,
+			threshold:  Threshold{MinCount: 1, MinConfidence: 0.9},
+		},
+		{
+			name:       "fr-nir",
+			re:         regexp.MustCompile(`\b[12][ .]?\d{2}[ .]?\d{2}[ .]?(?:\d{2}|2[ABab])[ .]?\d{3}[ .]?\d{3}[ .]?\d{2}\b`),
+			validate:   validNIR,
+			confidence: 0.7,
+			keywords:   []string{"sécurité sociale", "securite sociale", "nir", "insee", "sécu", "carte vitale"},
+			suffix:     2,
+			threshold:  Threshold{MinCount: 1, MinConfidence: 0.7},
+		},
+		{
+			name:       "us-ssn",
+			re:         regexp.MustCompile(`\b\d{3}[- ]\d{2}[- ]\d{4}\b`),
+			validate:   validSSN,
+			confidence: 0.5,
+			keywords:   []string{"ssn", "social security", "social sec"},
+			suffix:     4,
+			threshold:  Threshold{MinCount: 1, MinConfidence: 0.8},
+		},
+		{
+			name:       "uk-nino",
+			re:         regexp.MustCompile(`\b[A-CEGHJ-PR-TW-Z][A-CEGHJ-NPR-TW-Z] ?\d{2} ?\d{2} ?\d{2} ?[A-D]\b`),
+			validate:   validNINO,
+			confidence: 0.5,
+			keywords:   []string{"national insurance", "nino", "ni number", "ni no"},
+			suffix:     1,
+			threshold:  Threshold{MinCount: 1, MinConfidence: 0.8},
+		},
+		{
+			name:       "es-dni",
+			re:         regexp.MustCompile(`\b[XYZ]?\d{7,8}-?[A-Z]\b`),
+			validate:   validDNI,
+			confidence: 0.7,
+			keywords:   []string{"dni", "nie", "nif", "documento nacional"},
+			suffix:     1,
+			threshold:  Threshold{MinCount: 1, MinConfidence: 0.7},
+		},
+		{
+			name: "iban",
+			re:   extractors.IBANRE,
+			validate: func(value string) bool {
+				_, ok := extractors.NormalizeIBAN(value)
+				return ok
+			},
+			confidence: 0.8,
+			keywords:   []string{"iban", "account", "compte", "rib", "konto", "bank", "banque"},
+			prefix:     2,
+			suffix:     4,
+			threshold:  Threshold{MinCount: 5, MinConfidence: 0.8},
+		},
+		{
+			name: "api-key",
+			re: regexp.MustCompile(`\b(?:AKIA|ASIA)[0-9A-Z]{16}\b|\bgh[pousr]_[A-Za-z0-9]{36}\b|\bgithub_pat_[A-Za-z0-9_]{82}\b|` +
+				`\bxox[abprs]-[A-Za-z0-9-]{10,72}\b|\bAIza[0-9A-Za-z_\-]{35}\b|\b[rs]k_live_[0-9a-zA-Z]{24,99}\b|` +
+				`(?i:api[_-]?key|secret[_-]?key|access[_-]?token|auth[_-]?token|client[_-]?secret)["']?\s*[:=]\s*["']?(?P<secret>[A-Za-z0-9_\-+/=.]{20,})`),
+			validate:   highEntropy,
+			confidence: 0.7,
+			keywords:   []string{"key", "token", "secret", "password", "api"},
+			prefix:     4,
+			threshold:  Threshold{MinCount: 1, MinConfidence: 0.7},
+		},
+		{
+			name:       "private-key",
+			re:         regexp.MustCompile(`-----BEGIN (?:RSA |EC |DSA |OPENSSH |ENCRYPTED |PGP )?PRIVATE KEY(?: BLOCK)?-----`),
+			confidence: 1,
+			clear:      true,
+			threshold:  Threshold{MinCount: 1, MinConfidence: 1},
+		},
+	}
+}
+
+// validCard checks the Luhn checksum and the issuer ranges of the main card networks.
+func validCard(value string) bool {
+	d := onlyDigits(value)
+	if len(d) < 13 || len(d) > 19 || strings.Count(d, d[:1]) == len(d) {
+		return false
+	}
+	sum := 0
+	double := false
+	for i := len(d) - 1; i >= 0; i-- {
+		n := int(d[i] - '0')
+		if double {
+			n *= 2
+			if n > 9 {
+				n -= 9
+			}
+		}
+		sum += n
+		double = !double
+	}
+	if sum%10 != 0 {
+		return false
+	}
+	return cardNetwork(d) != ""
+}
+
+func prefixIn(d string, length int, low int, high int) bool {
+	if len(d) < length {
+		return false
+	}
+	n := 0
+	for _, c := range d[:length] {
+		n = n*10 + int(c-'0')
+	}
+	return n >= low && n <= high
+}
+
+func cardNetwork(d string) string {
+	l := len(d)
+	switch {
+	case d[0] == '4' && (l == 13 || l == 16 || l == 19):
+		return "visa"
+	case (prefixIn(d, 2, 51, 55) || prefixIn(d, 4, 2221, 2720)) && l == 16:
+		return "mastercard"
+	case (prefixIn(d, 2, 34, 34) || prefixIn(d, 2, 37, 37)) && l == 15:
+		return "amex"
+	case (prefixIn(d, 4, 6011, 6011) || prefixIn(d, 3, 644, 649) || prefixIn(d, 2, 65, 65)) && l >= 16:
+		return "discover"
+	case prefixIn(d, 4, 3528, 3589) && l >= 16:
+		return "jcb"
+	case (prefixIn(d, 3, 300, 305) || prefixIn(d, 2, 36, 36) || prefixIn(d, 2, 38, 39)) && l >= 14:
+		return "diners"
+	case prefixIn(d, 2, 62, 62) && l >= 16:
+		return "unionpay"
+	case (prefixIn(d, 2, 50, 50) || prefixIn(d, 2, 56, 69)) && l >= 12:
+		return "maestro"
+	}
+	return ""
+}
+
+// validNIR checks the key of a French social security number: 97 minus the number modulo 97.
+// The Corsican departments 2A and 2B count as 19 and 18.
+func validNIR(value string) bool {
+	v := alphanumeric(value)
+	if len(v) != 15 {
+		return false
+	}
+	body := v[:13]
+	switch v[5:7] {
+	case "2A":
+		body = v[:5] + "19" + v[7:13]
+	case "2B":
+		body = v[:5] + "18" + v[7:13]
+	}
+	month := int(v[3]-'0')*10 + int(v[4]-'0')
+	if month == 0 || (month > 12 && month < 20) || (month > 42 && month < 50) {
+		return false
+	}
+	mod := 0
+	for _, c := range body {
+		if c < '0' || c > '9' {
+			return false
+		}
+		mod = (mod*10 + int(c-'0')) % 97
+	}
+	key := int(v[13]-'0')*10 + int(v[14]-'0')
+	return key == 97-mod
+}
+
+// validSSN excludes the area, group and serial numbers that are never assigned.
+func validSSN(value string) bool {
+	d := onlyDigits(value)
+	if len(d) != 9 {
+		return false
+	}
+	area, group, serial := d[:3], d[3:5], d[5:]
+	if area == "000" || area == "666" || area[0] == '9' || group == "00" || serial == "0000" {
+		return false
+	}
+	// the numbers used in advertisements
+	return d != "078051120" && d != "219099999" && d != "123456789"
+}
+
+// validNINO excludes the prefixes that are never allocated.
+func validNINO(value string) bool {
+	v := alphanumeric(value)
+	switch v[:2] {
+	case "BG", "GB", "NK", "KN", "TN", "NT", "ZZ":
+		return false
+	}
+	return true
+}
+
+// validDNI checks the control letter of a Spanish DNI or NIE: the number modulo 23 indexes the
+// letters. The first letter of a NIE counts as 0, 1 or 2.
+func validDNI(value string) bool {
+	v := alphanumeric(value)
+	v = strings.NewReplacer("X", "0", "Y", "1", "Z", "2").Replace(v[:1]) + v[1:]
+	if len(v) != 9 {
+		return false
+	}
+	n := 0
+	for _, c := range v[:8] {
+		if c < '0' || c > '9' {
+			return false
+		}
+		n = n*10 + int(c-'0')
+	}
+	return "TRWAGMYFPDXBNJZSQVHLCKE"[n%23] == v[8]
+}
+
+// highEntropy rejects the placeholders like "your_api_key_here" or "xxxxxxxxxxxxxxxxxxxx".
+func highEntropy(value string) bool {
+	counts := make(map[rune]float64)
+	n := 0.0
+	for _, r := range value {
+		counts[r]++
+		n++
+	}
+	entropy := 0.0
+	for _, c := range counts {
+		p := c / n
+		entropy -= p * math.Log2(p)
+	}
+	return entropy >= 3.5
+}
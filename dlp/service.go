@@ -0,0 +1,164 @@
+package dlp
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"go.uber.org/fx"
+)
+
+// maxSamples is the number of redacted samples reported by finding.
+const maxSamples = 3
+
+// Text is a text to scan, with its origin.
+type Text struct {
+	// Source is "body", or the name of an attachment
+	Source  string
+	Content string
+}
+
+type Scanner interface {
+	utils.Service
+	utils.Prestartable
+	// Scan runs the detectors on the texts. It returns nil when nothing was found.
+	Scan(texts []Text) *models.DLP
+}
+
+type impl struct {
+	configPath string
+	detectors  []*detector
+	logger     log15.Logger
+}
+
+// NewScanner returns a Scanner with the built-in detectors, and the configuration at configPath
+// if not empty.
+func NewScanner(configPath string, logger log15.Logger) Scanner {
+	return &impl{configPath: configPath, logger: logger}
+}
+
+func (i *impl) Name() string {
+	return "DLPScanner"
+}
+
+func (i *impl) Prestart() (err error) {
+	var c *Config
+	if i.configPath != "" {
+		c, err = ReadConfig(i.configPath)
+		if err != nil {
+			return fmt.Errorf("failed to read DLP configuration: %s", err)
+		}
+	}
+	i.detectors, err = c.detectors()
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, d := range i.detectors {
+		names = append(names, d.name)
+	}
+	i.logger.Info("DLP detectors loaded", "detectors", strings.Join(names, ","))
+	return nil
+}
+
+// match is a distinct value found by a detector in a text.
+type match struct {
+	score  float64
+	sample string
+}
+
+func (i *impl) Scan(texts []Text) *models.DLP {
+	result := new(models.DLP)
+	for _, d := range i.detectors {
+		// the distinct values found in all the texts, with their best score
+		values := make(map[string]float64)
+		for _, t := range texts {
+			matches, order := d.find(t.Content)
+			if len(matches) == 0 {
+				continue
+			}
+			finding := models.DLPFinding{Detector: d.name, Source: t.Source, Count: len(matches)}
+			for _, v := range order {
+				m := matches[v]
+				if m.score > finding.Confidence {
+					finding.Confidence = m.score
+				}
+				if len(finding.Samples) < maxSamples {
+					finding.Samples = append(finding.Samples, m.sample)
+				}
+				if m.score > values[v] {
+					values[v] = m.score
+				}
+			}
+			result.Findings = append(result.Findings, finding)
+		}
+		count := 0
+		for _, score := range values {
+			if score >= d.threshold.MinConfidence {
+				count++
+			}
+		}
+		if count > 0 && count >= d.threshold.MinCount {
+			result.Verdict = true
+			result.Triggered = append(result.Triggered, d.name)
+		}
+	}
+	if len(result.Findings) == 0 {
+		return nil
+	}
+	sort.Strings(result.Triggered)
+	return result
+}
+
+// find returns the distinct values found in text, and their order of appearance.
+func (d *detector) find(text string) (map[string]match, []string) {
+	matches := make(map[string]match)
+	var order []string
+	for _, loc := range d.re.FindAllStringSubmatchIndex(text, -1) {
+		value := d.value(text, loc)
+		if d.validate != nil && !d.validate(value) {
+			continue
+		}
+		key := alphanumeric(value)
+		score := d.score(text, loc)
+		if m, ok := matches[key]; ok {
+			if score > m.score {
+				m.score = score
+				matches[key] = m
+			}
+			continue
+		}
+		full := text[loc[0]:loc[1]]
+		sample := full
+		if !d.clear {
+			sample = strings.Replace(full, value, redact(value, d.prefix, d.suffix), 1)
+		}
+		matches[key] = match{score: score, sample: strings.TrimSpace(sample)}
+		order = append(order, key)
+	}
+	return matches, order
+}
+
+type Params struct {
+	fx.In
+	Args   *arguments.Args `optional:"true"`
+	Logger log15.Logger    `optional:"true"`
+}
+
+var Service = fx.Provide(func(lc fx.Lifecycle, params Params) Scanner {
+	if params.Args == nil || !params.Args.DLP.Enabled {
+		return nil
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	s := NewScanner(params.Args.DLP.ConfigPath, logger)
+	utils.Append(lc, s, logger)
+	return s
+})
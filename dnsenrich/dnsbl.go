@@ -0,0 +1,135 @@
+package dnsenrich
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+// The blocklists answer with an A record in 127.0.0.0/8. The meaning of the address depends on
+// the list: some use one address per reason, others a bitmask in the last octet.
+
+type codeTable struct {
+	codes map[string]string
+	// bits maps the bits of the last octet to their meaning, for bitmask lists
+	bits map[int]string
+	// errors are the addresses that report a failed query, and not a listing
+	errors map[string]string
+}
+
+var spamhausCodes = codeTable{
+	codes: map[string]string{
+		"127.0.0.2":   "SBL: spam source",
+		"127.0.0.3":   "SBL CSS: snowshoe spam source",
+		"127.0.0.4":   "XBL: exploited host",
+		"127.0.0.5":   "XBL: exploited host",
+		"127.0.0.6":   "XBL: exploited host",
+		"127.0.0.7":   "XBL: exploited host",
+		"127.0.0.9":   "DROP: hijacked network",
+		"127.0.0.10":  "PBL: end-user address (ISP)",
+		"127.0.0.11":  "PBL: end-user address (Spamhaus)",
+		"127.0.1.2":   "DBL: spam domain",
+		"127.0.1.4":   "DBL: phishing domain",
+		"127.0.1.5":   "DBL: malware domain",
+		"127.0.1.6":   "DBL: botnet C&C domain",
+		"127.0.1.102": "DBL: abused legit spam domain",
+		"127.0.1.103": "DBL: abused spammed redirector",
+		"127.0.1.104": "DBL: abused legit phishing domain",
+		"127.0.1.105": "DBL: abused legit malware domain",
+		"127.0.1.106": "DBL: abused legit botnet C&C domain",
+	},
+	errors: map[string]string{
+		"127.255.255.252": "typing error in the DNSBL name",
+		"127.255.255.254": "query through a public resolver",
+		"127.255.255.255": "excessive number of queries",
+	},
+}
+
+var surblCodes = codeTable{
+	bits: map[int]string{
+		8:   "PH: phishing",
+		16:  "MW: malware",
+		64:  "ABUSE: spam",
+		128: "CR: cracked site",
+	},
+}
+
+var uriblCodes = codeTable{
+	bits: map[int]string{
+		2: "black: spam domain",
+		4: "grey: bulk sender domain",
+		8: "red: new spam domain",
+	},
+	errors: map[string]string{
+		"127.0.0.1": "query refused",
+	},
+}
+
+// knownLists maps the domains of the lists to their codes.
+var knownLists = map[string]*codeTable{
+	"spamhaus.org": &spamhausCodes,
+	"surbl.org":    &surblCodes,
+	"uribl.com":    &uriblCodes,
+}
+
+func lookupTable(zone string) *codeTable {
+	zone = canonical(zone)
+	for suffix, table := range knownLists {
+		if zone == suffix || strings.HasSuffix(zone, "."+suffix) {
+			return table
+		}
+	}
+	return nil
+}
+
+// interpret returns the meaning of the answers of a list, and the error that the list reported.
+func interpret(zone string, answers []string) (meanings []string, listErr string) {
+	table := lookupTable(zone)
+	for _, answer := range answers {
+		if !strings.HasPrefix(answer, "127.") {
+			continue
+		}
+		if table == nil {
+			meanings = append(meanings, "listed")
+			continue
+		}
+		if e, ok := table.errors[answer]; ok {
+			listErr = e
+			continue
+		}
+		if m, ok := table.codes[answer]; ok {
+			meanings = append(meanings, m)
+			continue
+		}
+		if table.bits != nil {
+			octets := strings.Split(answer, ".")
+			last, err := strconv.Atoi(octets[len(octets)-1])
+			if err == nil {
+				for bit := 1; bit < 256; bit <<= 1 {
+					if last&bit != 0 {
+						if m, ok := table.bits[bit]; ok {
+							meanings = append(meanings, m)
+						}
+					}
+				}
+				continue
+			}
+		}
+		meanings = append(meanings, "listed")
+	}
+	return meanings, listErr
+}
+
+// reverseIP returns the name under which a blocklist publishes an IP address.
+func reverseIP(ip net.IP) string {
+	if ip4 := ip.To4(); ip4 != nil {
+		return fmt.Sprintf("%d.%d.%d.%d", ip4[3], ip4[2], ip4[1], ip4[0])
+	}
+	ip16 := ip.To16()
+	nibbles := make([]string, 0, 32)
+	for i := len(ip16) - 1; i >= 0; i-- {
+		nibbles = append(nibbles, fmt.Sprintf("%x", ip16[i]&0xf), fmt.Sprintf("%x", ip16[i]>>4))
+	}
+	return strings.Join(nibbles, ".")
+}
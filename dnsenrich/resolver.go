@@ -0,0 +1,206 @@
+package dnsenrich
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Resolver is the subset of net.Resolver used by the enrichment. It can be replaced by a Zone
+// in tests.
+type Resolver interface {
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+	LookupHost(ctx context.Context, host string) ([]string, error)
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupNS(ctx context.Context, name string) ([]*net.NS, error)
+}
+
+// Zone is an in-memory Resolver. Names are fully qualified, without the trailing dot.
+type Zone struct {
+	// A holds the A and AAAA records
+	A   map[string][]string
+	PTR map[string][]string
+	MX  map[string][]*net.MX
+	NS  map[string][]*net.NS
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func canonical(name string) string {
+	return strings.ToLower(strings.TrimSuffix(name, "."))
+}
+
+func (z *Zone) LookupAddr(_ context.Context, addr string) ([]string, error) {
+	if names, ok := z.PTR[addr]; ok {
+		return names, nil
+	}
+	return nil, notFound(addr)
+}
+
+func (z *Zone) LookupHost(_ context.Context, host string) ([]string, error) {
+	if addrs, ok := z.A[canonical(host)]; ok {
+		return addrs, nil
+	}
+	return nil, notFound(host)
+}
+
+func (z *Zone) LookupMX(_ context.Context, name string) ([]*net.MX, error) {
+	if mx, ok := z.MX[canonical(name)]; ok {
+		return mx, nil
+	}
+	return nil, notFound(name)
+}
+
+func (z *Zone) LookupNS(_ context.Context, name string) ([]*net.NS, error) {
+	if ns, ok := z.NS[canonical(name)]; ok {
+		return ns, nil
+	}
+	return nil, notFound(name)
+}
+
+// IsNotFound reports whether err means that the name or the record does not exist.
+func IsNotFound(err error) bool {
+	if e, ok := err.(*net.DNSError); ok {
+		return e.IsNotFound
+	}
+	return false
+}
+
+type cacheEntry struct {
+	value   interface{}
+	err     error
+	expires time.Time
+}
+
+// cachingResolver caches the answers, including the negative ones, and limits the rate of the
+// queries sent to the underlying resolver.
+type cachingResolver struct {
+	resolver   Resolver
+	ttl        time.Duration
+	maxEntries int
+	limiter    *limiter
+	lock       sync.Mutex
+	entries    map[string]cacheEntry
+}
+
+func newCachingResolver(resolver Resolver, ttl time.Duration, maxEntries int, rate float64) *cachingResolver {
+	return &cachingResolver{
+		resolver:   resolver,
+		ttl:        ttl,
+		maxEntries: maxEntries,
+		limiter:    newLimiter(rate),
+		entries:    make(map[string]cacheEntry),
+	}
+}
+
+func (c *cachingResolver) get(key string) (cacheEntry, bool) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	e, ok := c.entries[key]
+	if !ok || time.Now().After(e.expires) {
+		return e, false
+	}
+	return e, true
+}
+
+func (c *cachingResolver) put(key string, value interface{}, err error) {
+	// temporary errors are not cached
+	if c.ttl <= 0 || (err != nil && !IsNotFound(err)) {
+		return
+	}
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	now := time.Now()
+	if len(c.entries) >= c.maxEntries {
+		for k, e := range c.entries {
+			if now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		if len(c.entries) >= c.maxEntries {
+			c.entries = make(map[string]cacheEntry)
+		}
+	}
+	c.entries[key] = cacheEntry{value: value, err: err, expires: now.Add(c.ttl)}
+}
+
+func (c *cachingResolver) lookup(ctx context.Context, key string, f func() (interface{}, error)) (interface{}, error) {
+	if e, ok := c.get(key); ok {
+		return e.value, e.err
+	}
+	err := c.limiter.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	value, err := f()
+	c.put(key, value, err)
+	return value, err
+}
+
+func (c *cachingResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	v, err := c.lookup(ctx, "PTR "+addr, func() (interface{}, error) { return c.resolver.LookupAddr(ctx, addr) })
+	names, _ := v.([]string)
+	return names, err
+}
+
+func (c *cachingResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	v, err := c.lookup(ctx, "A "+canonical(host), func() (interface{}, error) { return c.resolver.LookupHost(ctx, host) })
+	addrs, _ := v.([]string)
+	return addrs, err
+}
+
+func (c *cachingResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	v, err := c.lookup(ctx, "MX "+canonical(name), func() (interface{}, error) { return c.resolver.LookupMX(ctx, name) })
+	mx, _ := v.([]*net.MX)
+	return mx, err
+}
+
+func (c *cachingResolver) LookupNS(ctx context.Context, name string) ([]*net.NS, error) {
+	v, err := c.lookup(ctx, "NS "+canonical(name), func() (interface{}, error) { return c.resolver.LookupNS(ctx, name) })
+	ns, _ := v.([]*net.NS)
+	return ns, err
+}
+
+// limiter is a token bucket that allows rate queries per second, in bursts of up to rate queries.
+type limiter struct {
+	lock   sync.Mutex
+	rate   float64
+	tokens float64
+	last   time.Time
+}
+
+func newLimiter(rate float64) *limiter {
+	return &limiter{rate: rate, tokens: rate, last: time.Now()}
+}
+
+// Wait blocks until a query is allowed, or until ctx is done.
+func (l *limiter) Wait(ctx context.Context) error {
+	if l.rate <= 0 {
+		return nil
+	}
+	for {
+		l.lock.Lock()
+		now := time.Now()
+		l.tokens += now.Sub(l.last).Seconds() * l.rate
+		if l.tokens > l.rate {
+			l.tokens = l.rate
+		}
+		l.last = now
+		if l.tokens >= 1 {
+			l.tokens--
+			l.lock.Unlock()
+			return nil
+		}
+		wait := time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+		l.lock.Unlock()
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
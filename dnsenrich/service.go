@@ -0,0 +1,300 @@
+// Package dnsenrich enriches the features of mails with DNS lookups: forward-confirmed reverse
+// DNS of the client, MX/NS/A records of the sender domains, and DNS blocklists.
+package dnsenrich
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"go.uber.org/fx"
+)
+
+// maxReverseNames is the number of reverse names of the client that are resolved.
+const maxReverseNames = 5
+
+// maxDomainItems is the number of domains that are checked against the domain blocklists.
+const maxDomainItems = 20
+
+var maxCacheEntries = 100000
+
+// Query describes what to look up for a mail.
+type Query struct {
+	ClientIP       net.IP
+	EnvelopeDomain string
+	FromDomain     string
+	URLHosts       []string
+}
+
+type Enricher interface {
+	utils.Service
+	Enrich(ctx context.Context, q Query) *models.DNS
+}
+
+type impl struct {
+	resolver Resolver
+	dnsbls   []string
+	uribls   []string
+	logger   log15.Logger
+}
+
+// NewEnricher returns an Enricher that sends its queries to resolver, or to the system resolver
+// when resolver is nil. The answers are cached for ttl, and at most rate queries per second are
+// sent (no limit if rate is 0).
+func NewEnricher(resolver Resolver, dnsbls []string, uribls []string, rate int, ttl time.Duration, logger log15.Logger) Enricher {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	if ttl > 0 || rate > 0 {
+		resolver = newCachingResolver(resolver, ttl, maxCacheEntries, float64(rate))
+	}
+	return &impl{
+		resolver: resolver,
+		dnsbls:   dnsbls,
+		uribls:   uribls,
+		logger:   logger,
+	}
+}
+
+func (i *impl) Name() string {
+	return "DNSEnricher"
+}
+
+// result accumulates the answers of the concurrent lookups.
+type result struct {
+	lock sync.Mutex
+	dns  models.DNS
+	wg   sync.WaitGroup
+}
+
+func (r *result) do(f func()) {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		f()
+	}()
+}
+
+func (r *result) error(what string, err error) {
+	r.lock.Lock()
+	r.dns.Errors = append(r.dns.Errors, fmt.Sprintf("%s: %s", what, err))
+	r.lock.Unlock()
+}
+
+func (i *impl) Enrich(ctx context.Context, q Query) *models.DNS {
+	r := new(result)
+	if q.ClientIP != nil {
+		r.dns.ClientIP = q.ClientIP.String()
+		r.do(func() { i.fcrdns(ctx, q.ClientIP, r) })
+		for _, list := range i.dnsbls {
+			list := list
+			r.do(func() { i.checkList(ctx, list, reverseIP(q.ClientIP), q.ClientIP.String(), "ip", r) })
+		}
+	}
+
+	domains := make(map[string]*models.DomainDNS)
+	for _, d := range []struct{ domain, source string }{{q.EnvelopeDomain, "envelope"}, {q.FromDomain, "from"}} {
+		domain := canonical(d.domain)
+		if domain == "" {
+			continue
+		}
+		if domains[domain] == nil {
+			domains[domain] = &models.DomainDNS{Domain: domain}
+		}
+		domains[domain].Sources = append(domains[domain].Sources, d.source)
+	}
+	for _, d := range domains {
+		d := d
+		r.do(func() { i.domainRecords(ctx, d, r) })
+	}
+
+	if len(i.uribls) > 0 {
+		for _, item := range domainItems(domains, q.URLHosts) {
+			for _, list := range i.uribls {
+				item, list := item, list
+				if ip := net.ParseIP(item); ip != nil {
+					r.do(func() { i.checkList(ctx, list, reverseIP(ip), item, "ip", r) })
+				} else {
+					r.do(func() { i.checkList(ctx, list, item, item, "domain", r) })
+				}
+			}
+		}
+	}
+	r.wg.Wait()
+
+	for _, d := range domains {
+		r.dns.Domains = append(r.dns.Domains, *d)
+	}
+	sort.Slice(r.dns.Domains, func(a, b int) bool { return r.dns.Domains[a].Domain < r.dns.Domains[b].Domain })
+	sort.Slice(r.dns.Listings, func(a, b int) bool {
+		if r.dns.Listings[a].List != r.dns.Listings[b].List {
+			return r.dns.Listings[a].List < r.dns.Listings[b].List
+		}
+		return r.dns.Listings[a].Item < r.dns.Listings[b].Item
+	})
+	sort.Strings(r.dns.Errors)
+	return &r.dns
+}
+
+// fcrdns checks that a reverse name of ip resolves back to ip.
+func (i *impl) fcrdns(ctx context.Context, ip net.IP, r *result) {
+	names, err := i.resolver.LookupAddr(ctx, ip.String())
+	if err != nil {
+		if !IsNotFound(err) {
+			r.error("PTR "+ip.String(), err)
+		}
+		return
+	}
+	confirmed := false
+	for idx, name := range names {
+		if idx >= maxReverseNames {
+			break
+		}
+		addrs, err := i.resolver.LookupHost(ctx, name)
+		if err != nil {
+			if !IsNotFound(err) {
+				r.error("A "+name, err)
+			}
+			continue
+		}
+		for _, addr := range addrs {
+			if a := net.ParseIP(addr); a != nil && a.Equal(ip) {
+				confirmed = true
+			}
+		}
+	}
+	r.lock.Lock()
+	for _, name := range names {
+		r.dns.ReverseNames = append(r.dns.ReverseNames, canonical(name))
+	}
+	r.dns.FCrDNS = confirmed
+	r.lock.Unlock()
+}
+
+// domainRecords looks up the MX, NS and A records of a sender domain.
+func (i *impl) domainRecords(ctx context.Context, d *models.DomainDNS, r *result) {
+	mx, err := i.resolver.LookupMX(ctx, d.Domain)
+	if err != nil && !IsNotFound(err) {
+		r.error("MX "+d.Domain, err)
+	}
+	// the NS records are at the apex of the zone
+	apex := utils.RegisteredDomain(d.Domain)
+	ns, nsErr := i.resolver.LookupNS(ctx, apex)
+	if nsErr != nil && !IsNotFound(nsErr) {
+		r.error("NS "+apex, nsErr)
+	}
+	addrs, aErr := i.resolver.LookupHost(ctx, d.Domain)
+	if aErr != nil && !IsNotFound(aErr) {
+		r.error("A "+d.Domain, aErr)
+	}
+
+	r.lock.Lock()
+	defer r.lock.Unlock()
+	for _, m := range mx {
+		d.MX = append(d.MX, canonical(m.Host))
+	}
+	d.HasMX = len(mx) > 0
+	d.NullMX = len(mx) == 1 && canonical(mx[0].Host) == ""
+	for _, n := range ns {
+		d.NS = append(d.NS, canonical(n.Host))
+	}
+	d.HasNS = len(ns) > 0
+	d.HasA = len(addrs) > 0
+}
+
+// checkList queries a DNS blocklist.
+func (i *impl) checkList(ctx context.Context, list string, name string, item string, kind string, r *result) {
+	answers, err := i.resolver.LookupHost(ctx, name+"."+canonical(list))
+	if err != nil {
+		if !IsNotFound(err) {
+			r.error(list+" "+item, err)
+		}
+		return
+	}
+	meanings, listErr := interpret(list, answers)
+	if listErr != "" {
+		r.error(list+" "+item, fmt.Errorf("%s", listErr))
+	}
+	if len(meanings) == 0 {
+		return
+	}
+	r.lock.Lock()
+	r.dns.Listings = append(r.dns.Listings, models.Listing{
+		List:     list,
+		Item:     item,
+		Kind:     kind,
+		Answers:  answers,
+		Meanings: meanings,
+	})
+	r.lock.Unlock()
+}
+
+// domainItems returns the registered domains of the senders and of the URL hosts, and the URL
+// hosts that are IP addresses.
+func domainItems(senders map[string]*models.DomainDNS, urlHosts []string) []string {
+	seen := make(map[string]bool)
+	var items []string
+	add := func(host string) {
+		host = canonical(host)
+		if host == "" || len(items) >= maxDomainItems {
+			return
+		}
+		if net.ParseIP(host) == nil {
+			host = utils.RegisteredDomain(host)
+			if !strings.Contains(host, ".") {
+				return
+			}
+		}
+		if !seen[host] {
+			seen[host] = true
+			items = append(items, host)
+		}
+	}
+	var domains []string
+	for d := range senders {
+		domains = append(domains, d)
+	}
+	sort.Strings(domains)
+	for _, d := range domains {
+		add(d)
+	}
+	for _, h := range urlHosts {
+		add(h)
+	}
+	return items
+}
+
+type Params struct {
+	fx.In
+	Args   *arguments.Args `optional:"true"`
+	Logger log15.Logger    `optional:"true"`
+}
+
+var Service = fx.Provide(func(lc fx.Lifecycle, params Params) Enricher {
+	if params.Args == nil || !params.Args.DNS.Enabled {
+		return nil
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	e := NewEnricher(
+		nil,
+		params.Args.DNS.DNSBLs,
+		params.Args.DNS.URIBLs,
+		params.Args.DNS.Rate,
+		params.Args.DNS.CacheTTL,
+		logger,
+	)
+	utils.Append(lc, e, logger)
+	return e
+})
@@ -0,0 +1,118 @@
+package dnsenrich
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+)
+
+func testZone() *Zone {
+	return &Zone{
+		A: map[string][]string{
+			"mail.example.com":            {"192.0.2.10"},
+			"example.com":                 {"192.0.2.80"},
+			"generic.isp.net":             {"198.51.100.1"},
+			"10.2.0.192.zen.spamhaus.org": {"127.0.0.4", "127.0.0.10"},
+			"20.2.0.192.zen.spamhaus.org": {"127.255.255.254"},
+			"spammy.test.multi.surbl.org": {"127.0.0.80"},
+			"spammy.test.multi.uribl.com": {"127.0.0.2"},
+			"example.com.multi.uribl.com": {"127.0.0.1"},
+			"20.2.0.192.bl.example.net":   {"127.0.0.2"},
+		},
+		PTR: map[string][]string{
+			"192.0.2.10": {"mail.example.com."},
+			"192.0.2.20": {"generic.isp.net."},
+		},
+		MX: map[string][]*net.MX{
+			"example.com": {{Host: "mail.example.com.", Pref: 10}},
+			"nomail.test": {{Host: ".", Pref: 0}},
+		},
+		NS: map[string][]*net.NS{
+			"example.com": {{Host: "ns1.example.com."}},
+		},
+	}
+}
+
+func newTestEnricher(dnsbls, uribls []string) Enricher {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	return NewEnricher(testZone(), dnsbls, uribls, 0, 0, logger)
+}
+
+func TestFCrDNS(t *testing.T) {
+	e := newTestEnricher(nil, nil)
+	res := e.Enrich(context.Background(), Query{ClientIP: net.ParseIP("192.0.2.10")})
+	if !res.FCrDNS || len(res.ReverseNames) != 1 || res.ReverseNames[0] != "mail.example.com" {
+		t.Errorf("expected confirmed reverse DNS, got %+v", res)
+	}
+	res = e.Enrich(context.Background(), Query{ClientIP: net.ParseIP("192.0.2.20")})
+	if res.FCrDNS || len(res.ReverseNames) != 1 {
+		t.Errorf("expected unconfirmed reverse DNS, got %+v", res)
+	}
+	res = e.Enrich(context.Background(), Query{ClientIP: net.ParseIP("192.0.2.30")})
+	if res.FCrDNS || len(res.ReverseNames) != 0 || len(res.Errors) != 0 {
+		t.Errorf("expected no reverse DNS, got %+v", res)
+	}
+}
+
+func TestDomainRecords(t *testing.T) {
+	e := newTestEnricher(nil, nil)
+	res := e.Enrich(context.Background(), Query{EnvelopeDomain: "example.com", FromDomain: "nomail.test"})
+	if len(res.Domains) != 2 {
+		t.Fatalf("expected 2 domains, got %+v", res.Domains)
+	}
+	example, nomail := res.Domains[0], res.Domains[1]
+	if !example.HasMX || example.NullMX || !example.HasNS || !example.HasA || example.Sources[0] != "envelope" {
+		t.Errorf("unexpected records for example.com: %+v", example)
+	}
+	if !nomail.HasMX || !nomail.NullMX || nomail.HasNS || nomail.HasA || nomail.Sources[0] != "from" {
+		t.Errorf("unexpected records for nomail.test: %+v", nomail)
+	}
+}
+
+func TestBlocklists(t *testing.T) {
+	e := newTestEnricher(
+		[]string{"zen.spamhaus.org", "bl.example.net"},
+		[]string{"multi.surbl.org", "multi.uribl.com"},
+	)
+	res := e.Enrich(context.Background(), Query{
+		ClientIP:   net.ParseIP("192.0.2.10"),
+		FromDomain: "example.com",
+		URLHosts:   []string{"www.spammy.test"},
+	})
+	if len(res.Listings) != 3 {
+		t.Fatalf("expected 3 listings, got %+v", res.Listings)
+	}
+	zen := res.Listings[2]
+	if zen.List != "zen.spamhaus.org" || len(zen.Meanings) != 2 || zen.Meanings[0] != "XBL: exploited host" {
+		t.Errorf("unexpected spamhaus listing: %+v", zen)
+	}
+	surbl := res.Listings[0]
+	if surbl.Item != "spammy.test" || len(surbl.Meanings) != 2 {
+		t.Errorf("unexpected surbl listing: %+v", surbl)
+	}
+	// 127.0.0.1 means that uribl refused the query for example.com
+	if len(res.Errors) != 1 {
+		t.Errorf("expected 1 error, got %+v", res.Errors)
+	}
+
+	res = e.Enrich(context.Background(), Query{ClientIP: net.ParseIP("192.0.2.20")})
+	if len(res.Listings) != 1 || res.Listings[0].List != "bl.example.net" || res.Listings[0].Meanings[0] != "listed" {
+		t.Errorf("unexpected listings: %+v", res.Listings)
+	}
+	if len(res.Errors) != 1 {
+		t.Errorf("expected the spamhaus error, got %+v", res.Errors)
+	}
+}
+
+func TestReverseIP(t *testing.T) {
+	if r := reverseIP(net.ParseIP("192.0.2.1")); r != "1.2.0.192" {
+		t.Errorf("unexpected reversed IPv4: %s", r)
+	}
+	r := reverseIP(net.ParseIP("2001:db8::1"))
+	if r != "1.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.0.8.b.d.0.1.0.0.2" {
+		t.Errorf("unexpected reversed IPv6: %s", r)
+	}
+}
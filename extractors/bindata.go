@@ -1,7 +1,24 @@
 // Code generated by go-bindata. DO NOT EDIT.
 // sources:
+// data/stopwords-cs.txt (799B)
+// data/stopwords-da.txt (514B)
+// data/stopwords-de.txt (1.662kB)
 // data/stopwords-en.txt (3.652kB)
+// data/stopwords-es.txt (1.729kB)
+// data/stopwords-fi.txt (1.577kB)
 // data/stopwords-fr.txt (4.082kB)
+// data/stopwords-hu.txt (1.26kB)
+// data/stopwords-id.txt (970B)
+// data/stopwords-it.txt (1.754kB)
+// data/stopwords-nl.txt (706B)
+// data/stopwords-no.txt (860B)
+// data/stopwords-pl.txt (1.654kB)
+// data/stopwords-pt.txt (1.397kB)
+// data/stopwords-ro.txt (1.346kB)
+// data/stopwords-ru.txt (1.559kB)
+// data/stopwords-sv.txt (618B)
+// data/stopwords-tr.txt (521B)
+// data/stopwords-uk.txt (967B)
 
 package extractors
 
@@ -70,6 +87,66 @@ func (fi bindataFileInfo) Sys() interface{} {
 	return nil
 }
 
+var _dataStopwordsCsTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x91\x41\x8e\xab\x30\x10\x44\xf7\x75\x4b\x13\x5b\x8a\x21\xb6\x51\x00\x4b\x70\x87\x39\xc0\x68\x56\x2c\xfe\x22\x8b\xe8\x9f\x80\x6c\x08\xf7\x9a\xd7\xe4\x4b\x5f\xea\xaa\x6a\x4c\xdb\x5d\x6e\x3b\xb9\x66\x96\x6b\xe5\x6e\x41\x2e\x47\x50\xe4\x06\xf4\x78\xa9\x09\x8b\x9a\xc9\x87\x93\xd2\xc9\xc7\x2a\xea\x9b\xf9\x66\x70\x46\xd1\xa8\xa8\xd9\xb7\x51\x97\x42\xb0\xd1\xbb\xdb\xb1\xee\x4f\xf9\x90\xe5\x73\x18\xe4\x0b\xd1\xdc\xf7\x4d\xfe\xfd\xd3\x4d\x6d\xd4\xb5\x28\xaa\x75\x9d\xa1\xa8\x0d\xc4\xd5\xb4\x35\xc4\xcb\xd5\x84\x13\x5a\x4e\x00\x9c\xd9\xd2\x7b\x7c\xff\x88\xbd\x6d\xcc\xfb\x03\xb6\xd5\x01\x63\x2d\x7e\xdb\x21\x71\xc6\x50\x26\x68\x24\xdb\x57\x75\xea\x5c\x52\x87\xff\x8e\xfe\x9d\x9f\xa9\xef\xf8\x18\xc3\x9d\x32\x13\x2b\x32\x79\x7c\x64\x33\x39\xbe\x69\xdb\x4d\xe2\xb8\x14\x96\xa8\x44\x64\xea\x53\xa6\x79\x9a\x95\xd8\x04\xe8\x91\xf6\xe7\xa8\x64\xab\xc7\xdf\xd6\xe8\x78\x05\x65\x47\x78\x70\xac\x51\x99\xef\xd0\x14\xe8\x72\x3d\xfe\x20\xa7\xc1\x1c\x32\x2d\x72\xc0\x4e\x8e\x17\xe5\x52\x31\x60\xbc\x29\xef\x6b\x32\x62\xcc\x56\x53\x54\x3c\x11\x54\x32\xe1\x40\x04\x45\x3d\x73\xeb\x11\xfe\x02\x5e\xae\x2f\xdd\x64\xf9\xb4\x58\xbe\xf8\xbb\xab\xcc\xa5\xbf\x17\xc3\xf8\x8f\xcd\x1e\xc9\xfb\x0b\xae\x76\x7c\xcf\x00\x2a\xfe\xfb\xe3\x3b\xf8\x93\x07\xe3\xa8\x41\x43\x10\x43\x1d\xe2\x05\x35\x7b\xd0\xc6\xa3\x98\xa4\x0f\xf3\xb7\xda\xbd\x47\x47\x74\x06\xca\x60\xeb\x32\x3a\x7a\x32\xa1\x31\xf8\x19\xca\x06\x5b\xb1\xb8\x62\x17\x3e\xd3\x72\xae\x24\xc3\x27\x9b\x4c\x26\xc2\x94\xad\xb3\xe9\xfe\x38\xf9\x99\x4e\xb2\x1c\xcf\x20\x39\x4d\x9a\x98\x62\x55\x65\xdc\x41\x95\x98\x55\x6d\x86\xf5\x9c\xa1\xf1\x8b\x69\xff\xcf\x36\xb2\x27\x97\xaa\xc7\x8a\x69\x98\x97\xc1\xdf\xa2\xc5\x69\xf1\x86\x20\x66\xf8\xfe\x8a\xb2\x8b\xfc\x02\xcd\xf6\x27\xc6\x1f\x03\x00\x00")
+
+func dataStopwordsCsTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsCsTxt,
+		"data/stopwords-cs.txt",
+	)
+}
+
+func dataStopwordsCsTxt() (*asset, error) {
+	bytes, err := dataStopwordsCsTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-cs.txt", size: 799, mode: os.FileMode(420), modTime: time.Unix(1792352094, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x27, 0xf0, 0xc7, 0x72, 0x10, 0x9a, 0xe3, 0x41, 0x73, 0xbe, 0x65, 0x1b, 0x75, 0x2a, 0x4d, 0x22, 0x1c, 0x3e, 0x2e, 0x1e, 0x93, 0x26, 0x8, 0x9, 0xe2, 0xfc, 0x5a, 0x1e, 0xb6, 0x6f, 0x81, 0x13}}
+	return a, nil
+}
+
+var _dataStopwordsDaTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x91\x6b\x72\xc3\x20\x0c\x84\xff\xef\x2d\xe9\x20\xdb\x8a\x31\x64\x00\x73\xa3\xf4\x22\xb9\x58\x3f\x25\x9d\xf1\xae\x10\x5a\xbd\x70\xca\x4a\x9b\x52\x29\xf6\xa1\x6e\x39\x0e\x33\xe0\x84\x6a\xb6\xaa\x34\xf5\x93\xba\xe9\xa7\xd8\x82\x7c\xd9\x97\xbb\x72\x12\xfa\x6c\x97\x42\x07\x6a\x78\x3d\x60\x03\x9e\x81\xc9\x9d\xef\x00\x85\x8f\x81\xd7\xf0\x6e\xd9\x36\x91\x5a\x74\x15\xd9\x56\xb3\xe2\x34\xb5\xb5\xae\xad\x27\x1d\xe9\x02\x35\x30\xa0\x0e\x16\xed\x60\xc8\x1e\xa0\x86\x1b\x4c\x9c\xdc\xc3\xcb\xa0\xd2\xd1\x70\x6f\xec\x4a\x19\x8a\xc0\xf2\x72\xda\xbf\x89\x80\xa3\x58\xad\x7f\x28\x47\x0b\x6c\xb4\x75\xf9\x89\xd0\x99\xe5\x61\x3b\xe8\x7a\x34\x9d\x77\x2c\x76\xa1\x03\x3b\x27\xcb\x60\x67\xd4\x8b\x6a\x17\xcb\x5d\x1e\x36\x44\xce\x65\x23\xbc\x0e\x55\x64\xb5\x85\x0c\xe6\x81\xeb\xad\xfa\x7e\x75\xb1\x7e\xdb\xc7\xfb\xa5\x76\xa9\x3d\xc5\xb4\x2d\x86\x7c\x72\x33\xac\x2c\x0d\x0a\x0e\x0a\x8e\x28\x38\x28\x38\xce\x54\xa0\x3b\xfe\xd2\x20\x89\xdc\x18\x7a\xa6\x53\xf3\x70\x4d\x2f\xba\xb3\xee\x1a\x4f\xbf\x78\xa7\x65\xb5\x50\xe3\x6b\x4c\xcb\xf9\x4a\x80\xfc\xd8\x7a\xbd\x7f\xf9\x9d\x1f\x9e\xfa\x03\xa9\xa7\x94\xe0\x02\x02\x00\x00")
+
+func dataStopwordsDaTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsDaTxt,
+		"data/stopwords-da.txt",
+	)
+}
+
+func dataStopwordsDaTxt() (*asset, error) {
+	bytes, err := dataStopwordsDaTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-da.txt", size: 514, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x22, 0x18, 0x31, 0xcf, 0xf6, 0x77, 0x6, 0x9b, 0x64, 0x41, 0xbf, 0x7f, 0xc6, 0xec, 0xa, 0x41, 0x8f, 0x3e, 0xc1, 0x7f, 0x19, 0x29, 0x24, 0x72, 0x97, 0x8a, 0x82, 0xbf, 0x40, 0x77, 0x14, 0x5c}}
+	return a, nil
+}
+
+var _dataStopwordsDeTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x55\x41\x72\xe4\x20\x0c\xbc\xf3\x4b\x1c\x6b\x6c\x62\x4b\x54\x81\x1d\x57\xf9\x33\xb9\xec\x1b\xf6\x94\x9b\x3f\xb6\xdd\x12\x93\xad\x1a\x75\x83\x84\x40\x42\xc2\x93\x27\x69\x29\xef\xbb\x38\xa8\xa3\x39\x86\xba\x03\x5d\x6a\xca\xb0\xc2\x64\x33\x4d\x44\x19\xa4\x83\x87\xf1\xd7\xdc\x83\x87\x79\x58\x87\x11\xb6\xf3\x63\x05\xbc\x20\x9c\x3c\xdf\xd2\x66\x6c\x35\x49\x81\x34\x29\x47\x4f\x53\x31\x08\xb9\x1f\x80\xe3\x90\x34\xdd\x57\x9a\x33\x7e\x2b\x8e\x99\xb3\x96\x03\x68\x9b\x10\x0d\xd0\x29\x01\xb2\x4f\x54\xdf\x27\xe0\xf9\x4e\xb3\x60\x37\x82\x04\x6a\xd0\xd0\xb5\x20\x78\xba\x41\xdd\x9b\x36\x17\x87\x5f\x15\xd7\xb6\xc0\x71\xc8\x18\xd0\xc8\x1d\xfa\x9a\xf7\x89\xfc\x5f\xdb\x49\x05\x19\xcf\x45\x28\xdd\x21\x86\xb1\xc7\x18\x58\x8c\x34\x68\xcc\x5a\x10\x9d\x30\xac\xdc\xa6\x36\x64\x8e\xd4\xce\x86\x99\xd0\x8f\xf0\x42\xcd\x7a\x62\xa6\x9e\x68\xe4\x19\x69\x46\x96\x91\x24\xb0\x2c\x81\x32\x48\x07\xdb\xe0\x36\xd8\x17\x6b\xde\x13\x35\x98\x1c\x57\x76\xc0\x90\x05\x94\x93\xfa\x13\xbd\x40\x50\x47\x73\x0c\x75\x4f\xaf\x96\x4f\x80\x9c\x36\xef\xb8\x00\x58\x5f\xcf\x4f\x4b\x4b\xb6\x3b\x2d\x22\x6b\x43\x55\x07\xdb\x7b\x00\xb3\x2c\x3e\x6d\x79\xa6\xf9\xf2\xbb\x58\xca\x74\xa4\x65\x17\xde\xe3\xd2\x9e\x1f\xf4\xcc\x9b\x2d\xad\x79\xa2\x88\x03\xa7\xfb\x5e\x81\x07\xe5\x90\x40\xa8\xd9\x81\xab\x9c\xd4\x14\x9c\x43\x40\x87\xac\xb8\x31\x08\x4f\xe6\xe6\x65\x55\x88\x51\x84\xd8\x28\xe2\xa0\x8e\xae\x94\x50\xf7\x54\xa0\x54\xe5\x14\x7a\x63\x07\x15\xc3\x65\x7b\x17\x14\x83\x1d\xed\xfb\x29\x48\x84\xa0\x8e\xe6\xd8\x1c\x3b\x91\x45\xfd\x14\x13\x07\x75\x34\xc7\xe6\xc8\x35\xc7\x7d\xa4\x8d\x6d\xbe\xb1\xc0\x9b\x57\x78\x8b\x12\x6f\x51\xe3\x2d\x8a\xbc\x45\x95\xb7\xaa\x0a\x8f\xe7\xaf\xb9\x8d\x8c\xb4\xf7\x82\x46\x09\x6c\x49\xb3\x57\x44\xb3\x0b\xc6\x83\x74\xf0\x5b\xdd\x06\xf7\xa4\x28\x10\x00\x01\xa8\x07\xa0\x11\x80\x46\x00\x1a\x01\x68\x04\xa0\xaf\x25\x29\xef\x53\xd1\xb6\x7c\xaa\x5a\x1b\xcb\xaa\x27\x9e\x28\x01\xe1\x18\x22\x48\x86\x45\x47\x60\x4f\xc6\xab\xb0\xd3\x20\x2d\xd5\x29\x55\xde\x53\x45\x25\x52\xff\x58\xab\xa5\xce\x08\x3a\x23\xe8\x1e\x41\x8f\x08\x7a\x44\xd0\x23\x82\x1e\x11\xf0\x49\xe1\xf2\x3b\x83\xe8\x78\x79\x1d\xe5\x49\xf8\x96\xf5\xba\x33\xd9\x20\x1d\x6c\x83\xdb\xe0\x4e\xde\x1d\x0e\xae\x8d\xaf\x18\x98\x3b\xd6\x0b\xdb\x1d\xad\x1e\x77\x3a\x35\xa1\xb9\x21\x9d\x02\x77\x47\x19\xa4\x83\x6d\xf0\xdb\xcc\xc5\xec\xb7\xb3\x5f\xe9\xab\xc8\x9e\xbe\xaa\x42\x0c\xd2\xd2\x95\x5d\xe0\x04\xc4\x71\x7c\x6f\x97\x2c\x14\xea\xa4\xec\x04\xba\x5f\xe2\x99\x04\xe9\x60\x1b\xfc\x36\xd3\xd9\xa8\xc4\x27\x36\x10\x13\xc4\x0f\xe1\xe5\x5e\x05\x69\x5e\x85\x83\x36\x13\x78\x62\xc5\xcf\xff\x0c\xae\xc8\xff\xaa\xe1\x76\xfa\x1e\x67\x4c\x9e\x3f\x7c\x0c\xf0\xc1\x93\xa6\xda\xc9\x12\x5e\xd4\x8d\x5b\xb9\x51\xc0\xfb\xec\x19\x6f\x03\x3a\xe6\x74\x5f\xa5\x7b\x7c\xcf\x0f\xdb\xef\x1f\x66\x73\xb5\xcb\x7e\x06\x00\x00")
+
+func dataStopwordsDeTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsDeTxt,
+		"data/stopwords-de.txt",
+	)
+}
+
+func dataStopwordsDeTxt() (*asset, error) {
+	bytes, err := dataStopwordsDeTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-de.txt", size: 1662, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x77, 0xce, 0xee, 0x29, 0xcf, 0xb5, 0x8c, 0xe9, 0x80, 0xa3, 0xbe, 0x59, 0x2, 0x2a, 0x84, 0x3, 0xe4, 0xd2, 0xdd, 0x97, 0x38, 0x7d, 0x2e, 0x65, 0x3f, 0x76, 0x67, 0x2c, 0xa, 0xd1, 0xec, 0x59}}
+	return a, nil
+}
+
 var _dataStopwordsEnTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4c\x57\x5d\xba\xe5\xa0\xaa\x7c\x67\x22\xfd\x74\x07\x65\x22\x89\xde\x65\x24\x47\x70\xa5\x3d\xa3\x3f\x5f\x41\x76\x77\x3f\xec\xaa\x5a\x06\xff\x10\xc1\x9d\xb6\xc6\x94\x36\x99\x06\xfc\x42\xab\x51\xda\x77\x19\x39\xf5\x9d\x5f\x59\xfb\xf9\x57\xb5\x45\x69\x1f\xa2\x4a\x69\xb7\x99\x1a\x7e\xe7\xcc\x99\xd2\x71\xf0\x6e\x7f\x84\x77\x72\xa5\x94\x0e\xe3\x11\xf8\xa4\x91\x95\xd2\x99\x6a\x0f\xc4\x84\xed\x12\x27\xe9\xec\x78\x52\x6a\x83\x53\x5e\x94\x9a\x0a\xa5\x66\x45\xe6\x59\x28\xb5\x27\x2d\xa5\x74\xb9\x09\x10\xdd\x7a\x97\xe9\x8b\xed\x62\x05\xf3\xf4\xb5\x09\xfa\xf6\x55\xe4\x01\x5d\x32\xf0\x79\xf9\xf8\x7d\x59\xf1\xc5\xf5\xf5\xa4\xf5\x92\x3a\x17\x86\xdd\x7d\xa7\xc1\xdd\xb0\xb1\xfb\x1e\xf2\xbb\x5e\xc9\x18\xbf\x06\x77\x07\xa3\x34\xaa\x32\xa5\x21\xb3\x67\x4a\x5a\x33\x53\xd2\x8f\x8f\x3a\xad\x50\xfa\xa6\xda\xc2\xb9\x3e\xc5\x73\x4c\xf8\x69\x4b\xfb\x87\x36\xde\xd3\xc5\x4e\x53\x9d\xe5\xfa\x21\x0d\xc6\x38\x1b\x73\xa7\x8d\x0f\xac\x3c\xa8\xa4\x9e\x69\xe3\xb3\xf6\xc0\x1e\x66\xaf\xd2\x90\xa0\x52\xdd\x30\x3e\xb7\xca\x5f\x8c\xd0\xe4\xa1\x8d\x7d\xa5\x41\xb0\xb4\x27\x66\x59\x82\x1e\x55\x1a\x6d\x62\x85\xb6\x51\xf9\x08\x6c\x8b\x7c\xb9\x3b\xbc\x6c\xa0\x5f\x40\xac\xdc\x51\x69\xe7\x61\x38\xcc\x97\xd1\x01\x1b\x8a\xed\xec\xd2\xe3\x63\x30\x96\xf4\x4a\x7c\x9b\x2d\x07\x76\xa3\x9c\x8c\x29\xd7\x8c\xf1\x73\x3d\x0e\x76\x3f\x67\x61\x75\xf0\x66\x41\xff\x8c\x43\xcc\x12\x0d\x4f\x77\x88\xa0\xca\x73\xc0\x80\xd3\x5e\x88\x3d\xf2\x88\xeb\x59\x5e\x5c\xc4\xd5\xc3\x83\x9b\xb2\x43\x1c\x37\x77\x8f\x6f\xee\x1e\x61\xac\x37\xef\xd5\xa3\x9a\xed\xff\x52\x23\xfe\x72\x07\x0c\x87\x15\xe8\xe1\xe5\x0a\x8b\x71\x11\x31\xe5\xf2\x1d\xf7\xf7\xce\xb7\xd1\x51\x0f\x2b\x74\xd4\xa1\xd0\x5f\xa6\x43\x5a\x93\x87\xf3\x2b\xd0\x2b\x94\xd2\x21\xe3\xe2\xf1\x52\x5b\x10\xe8\xeb\x41\x76\xc8\x1c\x74\x0c\xb9\xe8\x98\xc3\xf7\xf1\xb2\xc7\xf6\x99\xbe\x4c\x27\x9b\x02\xfc\xea\x9d\x98\x0b\xd0\x1d\x15\xe8\xed\xf0\xe8\x89\x65\x9f\x62\xc6\x9d\x4a\xba\x6f\xee\x4a\x25\x8d\xdc\x16\x95\xe4\xbe\x2e\x18\x10\xf0\xfe\x40\xd7\xc2\xb8\x65\xbe\x39\x40\x5c\x6a\xa8\x6d\x39\xd5\xee\xa4\x8e\xf3\x16\xff\xe9\xbf\x94\xdb\x41\xa5\x5e\x2f\xfb\xf2\x0b\xa2\xa4\xc8\xb3\x71\x35\xb0\xfb\xb8\xcc\x9e\x07\x67\xaa\xbf\x5a\xa3\x7a\x5d\x9c\x2b\xe2\xe2\x8f\x6a\x8b\xea\x75\xcb\x30\xcf\x4e\x3f\xd2\xa8\xf6\xcc\xe8\xd6\x33\xff\xa6\xda\xe1\xc1\x64\x55\x3a\x21\xbf\x70\xc2\x17\x13\xaa\xfd\xcb\xfd\x6d\x46\xc8\x50\xf5\xbd\x56\xf3\xd9\xcc\x57\x57\x7f\x7d\x99\xfe\x7f\xaa\xd1\x87\xf9\x76\x50\xfa\xe0\x24\x3f\x5d\x1e\x87\xee\xa8\xd4\xd2\x38\xb1\xa2\x96\xd4\xa8\xc5\xea\x40\x03\xf8\x97\xd0\xca\x6e\xc2\xaa\x00\x28\x53\x6a\xf5\xc3\x0e\xd9\x11\x66\xb5\xa3\xc5\xac\x31\x35\x91\x8f\x03\x3c\x0f\x56\xba\x52\x66\xba\x52\x6d\x26\x20\x5c\xb5\x2b\x7d\xd8\x01\x5f\x3b\x7e\xaf\x8d\xe9\xe2\xd4\x1d\xd4\xd1\xea\x15\x6d\x4f\xa9\x0d\x6a\x60\xb2\xcb\xaf\xc6\x55\x5b\x83\x43\xae\xaa\x4a\x1e\x4a\x00\xc1\x59\x78\x4e\x06\xc0\x78\xee\x85\x2e\x78\xe5\x5a\xee\xa6\x8e\xa4\x00\x68\x8b\x3a\xa7\xe1\xe0\x7a\x67\xd5\x34\xea\x3f\x1a\x8a\xb3\x83\x52\x7f\x6f\x61\xf7\xf3\x76\xb4\xc2\xee\x9a\xce\xbf\x8d\x3a\x7c\x00\xb0\x45\x5d\xfc\xa2\x75\x44\x2b\xe0\x8f\xa1\x44\xcb\xb8\xfc\xa2\x76\x41\xd5\x41\xf2\x87\xaf\xba\xc4\xfd\x93\xcd\x33\x4f\x10\x67\x92\xed\x5b\x65\x6a\x5b\x24\x07\xe2\x5e\x3e\x69\x91\x5c\xd5\xd0\x59\x10\x4e\xd2\x59\x49\xe0\x56\x41\xb4\x44\x31\x71\xd4\xa0\x07\x49\x1f\x69\xc2\x48\x26\x1a\x27\x22\x1b\x97\x4b\xa6\x79\x6a\x75\xc7\x01\x52\x6b\x14\x97\xfb\x4e\x27\x3b\x28\xdd\x69\x98\x43\xdd\x67\x4b\xe3\x1f\xd9\x16\xdd\x08\x91\x9b\x47\x49\xb7\xd2\xdd\xd2\xce\x99\x6e\x04\x0e\xd3\xdd\xa6\xd2\x2d\xe2\x66\xa2\x5a\x51\x59\x5e\x81\x16\x43\x54\xbb\x2b\xee\xc1\x19\xf5\x23\x79\xf9\xba\x07\x2b\xb2\xe8\x3d\xf8\x67\xef\xf7\xa8\x57\x9c\xce\x3d\x64\x4b\x5b\x88\xeb\x0e\x73\x99\x19\xf8\xf5\xf2\xf0\x9f\x59\xf7\x4f\x5b\x60\x63\x1a\xc9\xdd\x81\xaa\x8c\xde\x83\x7d\xbe\xc1\x3b\x26\x08\xf2\xdf\x87\xd2\xe0\x33\xc5\xa3\x21\x94\x9f\x59\x48\x30\xae\x48\x0e\xae\x5f\xf6\x4e\xca\x69\xec\x05\xe2\xc6\xcb\xe1\xa7\x75\xb6\xb0\x84\x88\xe1\xa0\x94\x86\x87\xee\x98\x9d\x34\xd5\x4c\x8a\x58\xd4\xb4\x60\xa2\x28\xe5\x8a\x41\xa4\x93\xb2\x17\x41\x65\xbe\x1c\x38\x3b\xfd\xb4\xc1\x90\x61\xd5\x0e\x7a\xcf\xd1\xdd\xa5\x9e\xf3\x81\x23\x35\xd2\x82\xb3\xd4\x82\xce\x85\x7f\x85\x56\xd2\xe2\xe5\x2b\x08\x59\x44\xf1\xd6\x00\xb8\x21\xb2\x84\xa3\x5b\x3e\x4a\x5a\xcf\x5e\x8f\xba\x23\x5b\xfd\xa3\xdb\x22\xad\x57\x45\x34\xbc\xec\x2d\x08\x47\x6d\xd8\x26\x7e\x22\x53\x02\xfc\x36\x40\xf8\x54\x72\x31\xae\x01\xd8\x4a\xea\xaf\xf0\xcd\x41\xd5\xb7\x13\x84\xba\x7a\x4a\xb2\x57\xe0\x82\xa8\xc0\x45\x32\xc6\x22\x2f\x7a\x58\x10\xce\xf4\xfd\x81\x6d\xb8\xfa\x69\x09\xff\x5a\x85\x07\x4c\x6e\x52\x1b\xe2\xcf\x41\x9d\x9b\x22\x0b\x47\x08\xea\xdc\x71\xed\xe3\xc5\xa3\xc8\x1b\x3a\x8f\xa3\xee\x35\x22\x44\xe7\x79\x22\x05\xea\x1c\x4c\x86\xf4\x05\xe8\x40\x8c\x6f\xdc\x1a\x19\xf7\xac\xe4\x9b\x02\x7c\x02\xa3\xe5\x37\xd0\x1c\x70\x16\x60\x8d\x5f\x5f\x26\x2b\x5c\x47\x20\x1a\xf9\x72\x78\xcf\xd6\x0a\xa6\x89\x22\x66\xee\x01\xfb\x5b\xc6\xec\xad\x63\xce\x39\xc8\xdf\x5e\xf6\x56\x36\xe7\x98\x91\x07\xcb\x11\xfc\x63\xa0\x41\x26\xc1\x5e\xfc\xa2\x47\xac\x4a\x1d\x7d\xf8\xe5\xa3\xaf\x77\xa8\x15\x23\xac\xb0\xab\xbe\xd7\x8a\xd1\xc4\xbb\xc8\xa4\xf7\xe9\x1b\x14\xac\x78\x09\x5a\x19\x32\xcf\x97\xca\x0f\xe3\x29\x6f\x65\xa0\xdb\x54\x32\x39\xd9\xaf\xad\x89\xe0\xef\x43\x26\x5e\xf8\x82\x94\x6c\xe0\x9c\x81\xd0\xb3\x2d\xb2\xe1\xe7\x6c\x4f\xdd\x99\x66\xcf\x3c\x68\xa2\x9e\xda\xec\x51\xe1\x66\xf7\xfb\x3c\xbb\xd7\xaf\x20\x6f\xb6\xda\x80\x42\xbe\xfb\xa9\x9c\x01\xc7\x6c\x2f\xc1\xc8\x45\xf7\xfe\x78\x3b\x4e\xc5\x5c\x53\xe3\xdf\x88\x6f\x6a\x93\xe9\x9b\x06\x72\x15\xf9\x6b\xeb\x2b\x4d\xe9\xc1\x9d\x01\x40\xaa\xeb\x45\x0f\x37\x7f\x67\x3e\x7e\x28\x0f\xee\xed\x83\xe3\x78\xe2\xe1\xf8\xb8\xe7\x3d\xe2\x01\x5e\x6c\x9e\x37\x66\x1e\x8f\x99\x07\xe1\xf0\x44\x38\x80\x5e\x13\x1f\xe3\x6f\x5c\x84\xd4\xe0\x6d\x05\xd7\x1e\xfc\x36\xfb\x7e\x5d\xbd\x43\xb8\xcb\x9f\x52\xf7\x42\x51\x72\x9f\x52\x2f\xc0\xfb\x41\x32\xe0\xb5\x16\xff\x2e\xb1\x30\xb9\x1c\x7e\x3e\xa9\x03\xd3\x53\x33\x7c\xfc\xa0\x58\xf7\x93\x9e\xaa\x85\x9e\x6a\x01\x58\x4d\x35\x3f\xf9\x47\xb0\x75\xc1\xc9\x3e\x32\x5a\xa6\xc7\x93\xd4\xf3\xbe\xb1\x17\xf2\xfb\x92\xf9\x2b\xb0\x35\x50\xe0\xf0\x96\xc1\xae\x03\x35\x10\xd9\x71\xfd\xad\x74\x4b\xe6\xd7\xbf\xc3\xbf\xff\xe5\x21\xff\x0b\x00\x00\xff\xff\x56\xfb\x48\xa0\x44\x0e\x00\x00")
 
 func dataStopwordsEnTxtBytes() ([]byte, error) {
@@ -90,6 +167,46 @@ func dataStopwordsEnTxt() (*asset, error) {
 	return a, nil
 }
 
+var _dataStopwordsEsTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x55\x4b\x92\xeb\x20\x0c\xdc\x73\x4b\x62\x33\x09\x55\x36\x4a\x6c\xe3\x2a\x1f\x67\x96\x5e\xe4\x02\x6f\x9b\x8b\xbd\x6e\x7d\x9c\xa9\x40\x77\x23\x10\xe2\x23\x93\x9c\xf2\x43\x96\x9c\xf2\x84\x72\x17\x42\x6f\x79\x35\x16\x70\xdb\x8a\x02\xe4\xab\x7f\xde\x29\xaf\x84\xad\xb4\x2d\xcf\x85\x9d\xb7\x5a\x5a\x1a\xf2\x98\xd3\x20\xb3\x00\x1a\xeb\x86\x39\x87\x8e\x59\x01\x6d\x94\x34\x16\x94\x09\x75\xa5\x92\x46\xec\x8b\xce\x8e\xdf\x84\x32\x65\x85\x95\x88\xc0\x98\x14\xd3\x2f\xe8\xc4\x4c\xa8\x75\x25\x36\x02\x15\xd6\xa3\x25\xb3\x52\x60\xe0\x2a\xac\x6c\x6c\x59\xe1\x16\x54\x57\x17\xcd\xd9\xda\xb3\x0f\x5e\x0c\xcb\xd5\xfe\xfc\x06\xb7\x10\xd1\x73\x06\xd7\xb0\xbc\xf3\x25\xfe\xd8\xbe\x73\xbd\x73\xbb\x94\xd9\x14\x8b\x82\x0f\xab\x33\x0f\xd0\x05\xb7\xb1\x19\x68\xa7\x1c\xc4\xbe\x17\xa3\x5a\x16\x69\x2e\xdd\x1d\xca\x27\x34\x55\xdd\xaa\x93\xd8\x66\x3e\xbf\xb7\x58\xd3\xe7\xb7\x3a\x37\x23\x6b\x9d\x86\xde\x77\x5a\xdf\xb9\xa6\x9f\xbc\xcb\x92\x7e\x7a\x61\x65\xe8\x9f\x5e\x59\x39\x1b\x88\x81\x8d\xe0\x79\x5f\xf2\x50\xb1\xbf\x47\x46\xb9\xd9\x89\x52\x60\x0d\x46\xcd\xd9\xcd\xa7\x53\xf5\xf6\x3b\x07\x7f\x2d\xd7\x1c\x3c\x48\x17\x6a\x09\x37\xf7\xba\x9c\xbe\x3e\xe1\xe2\x1e\x43\x59\x80\xb4\xb0\xc5\x2c\x79\xe4\x83\x55\x85\x3b\x1d\x3a\xe0\xd0\x21\x87\x1e\xd5\xa3\xa0\x68\xa7\x20\x49\x1f\xfd\x56\x08\x76\x0d\x14\xda\x05\xe6\x49\x38\x57\xb5\x48\xc2\x78\xa6\xf4\x54\x50\x40\x30\x60\xec\x5c\xd2\x5c\x51\xa0\xfa\xf0\x10\x43\x36\x8e\x34\xf3\x64\x66\x7c\x61\x33\x37\x35\xeb\xba\x81\xa2\xb0\xa6\xc6\x2c\x69\x35\x35\x49\xfc\x38\x51\x65\xe3\xf7\x60\x82\x96\x8e\x4b\xc3\x07\xe3\x1c\x06\x09\x5e\x93\x24\x02\xc7\x98\x2b\xfd\x92\x39\x3f\x33\xac\x4f\x6c\x2b\x3d\x65\x20\x2c\xac\x2f\x5c\xfc\xb3\x97\xd1\xb1\x25\x1a\x5e\xbd\xaa\x02\x62\x5f\x78\x18\xce\xb4\xe6\xa9\x8f\x98\x05\x5f\xe2\x5a\x32\xeb\xac\x2d\x1c\x27\x80\xca\xd2\x01\x8c\x6c\x50\x6c\x46\x66\x3b\x0d\xab\xb5\xde\xd9\xe9\x6a\x87\xef\x3b\x37\x67\xb6\xf5\x82\xd6\x0a\x93\x20\xdd\x80\x6c\x8a\x8e\x15\x1a\x8f\xb4\x76\x14\x34\x3b\x6e\x99\xa0\x52\x14\x20\x71\xd4\x78\x07\x6e\x95\xf9\xbe\xe1\x39\x92\x84\x4b\xc4\xeb\x36\xda\x62\x55\x61\xb9\xce\x2d\x44\xf4\x9c\xc1\x35\x2c\x58\x78\x88\x3f\xb6\xef\x5c\x5c\x7e\x28\xb5\x45\x1c\xe4\x26\xf0\x9e\x0d\xdd\x78\xb7\xd1\x77\x1b\x7a\x17\x45\xdd\x33\x44\x44\xf5\x98\x57\xc4\x6f\xbc\x88\x66\xb1\x2a\x0a\xe2\x18\x36\x23\x98\x05\x0f\x0e\x01\xb2\xa3\x90\xf0\xd4\x5c\x0f\x4d\x3c\x33\xf1\xc8\x7c\x9f\x18\x7d\x60\x36\x9e\xeb\xa6\xe7\xba\xf1\x5c\x37\x3d\xd7\xed\xf3\x2f\xf5\x86\x92\x51\x25\xe9\x5f\x49\x87\xd7\x68\x88\xb0\x7b\xe4\xee\x1e\xb9\xbb\x7b\xee\xee\x91\xbb\xbb\xe7\xee\x1e\xb9\x7b\x24\xc4\x42\x8c\xcf\x39\xa1\x2e\xba\xcb\xff\xae\xc2\x7e\x5c\xc1\x06\x00\x00")
+
+func dataStopwordsEsTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsEsTxt,
+		"data/stopwords-es.txt",
+	)
+}
+
+func dataStopwordsEsTxt() (*asset, error) {
+	bytes, err := dataStopwordsEsTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-es.txt", size: 1729, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb0, 0xdb, 0xa, 0xee, 0xc, 0xd6, 0x65, 0x6c, 0x2c, 0xf1, 0xbc, 0x6e, 0x72, 0xcf, 0x15, 0xb7, 0x44, 0x8b, 0xa3, 0x55, 0xc2, 0xb2, 0xf1, 0x21, 0x9a, 0x5b, 0xaf, 0xd9, 0xed, 0xab, 0x7f, 0xa1}}
+	return a, nil
+}
+
+var _dataStopwordsFiTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3d\x94\x5b\x8e\x2b\x31\x08\x44\xff\xbd\xcb\x96\x06\x69\x3c\x6e\xd3\x57\x17\xd2\x52\xf6\x93\x9d\x64\x63\x53\x54\x91\x91\x62\x8e\x5f\xd0\x80\x21\x36\x87\xcd\xfb\xfd\xca\x61\x7b\xdb\x30\x1f\x86\x69\xa6\x95\x78\xbf\xc6\xb7\xe1\x37\x6b\x7c\xbd\x5f\xde\xcc\xe2\xf7\xe4\xf2\x3c\x4d\xe0\xe5\x79\x4a\x69\x46\x34\x7b\x4d\xd0\x02\x84\x59\x93\xca\x9a\xf4\xb9\x9d\x9f\x9b\x9f\x3b\xb2\xc4\xd9\xdf\x51\x12\xb5\x9c\x19\x36\x7e\x8e\xf1\x73\x7d\x5f\x0e\x39\xbf\x8c\x28\xef\x80\x15\xb3\x70\x9e\x87\x60\x44\x72\xe5\x94\x11\x82\xf6\x28\x17\x05\x35\xa5\x28\x3d\xa9\x51\xcb\x79\x25\x6a\x50\x5d\xda\x12\x38\x5a\x87\xd7\xfe\x32\x7a\xb3\x94\x2b\xa0\x6c\x2e\xa5\x6c\x75\xca\x56\xa7\x0c\x74\x81\xf1\xae\xce\xdc\x52\xe6\x96\x31\x67\x05\xd9\x50\xea\xc4\x3e\x3f\x3f\x17\x75\xcf\x7b\xd5\xe6\x3a\x7b\x35\x49\xc8\x5c\x5c\x70\x6b\xce\x44\x28\xeb\x8a\xf2\xfc\x51\x9e\x3e\x38\xf3\x81\x82\xd8\xfd\xf0\xbb\x1f\x7e\x2b\x98\xad\x28\x76\x47\xb1\x3b\x8a\xdd\xee\xef\x76\x7f\xcb\xfd\x2d\x1d\xc6\x0f\xc9\x1d\xaa\xb7\x76\x2b\x4f\xd7\x91\x3f\x0e\xca\x4a\xbe\x68\x62\x6a\xed\x94\x95\x61\xb2\x77\x93\x52\x87\x34\x23\x4f\xda\x91\x99\xb2\xcd\x05\xe2\x3b\x70\xef\x91\x50\x75\x1b\x3e\xf9\x4e\x40\xb9\x09\x94\x9f\x40\x7d\x97\x80\x4e\x31\x45\x5e\x71\xcd\xf9\x8d\x62\x9f\x11\x2a\x42\x57\x11\xba\x8a\xd0\x55\x84\xae\x22\x74\x15\x21\xc0\x0b\xce\x69\x05\xe4\xaa\x44\x67\x25\xfa\xe3\x1a\xfe\xcc\x81\x6f\xc9\x22\x48\x93\x20\x6d\x82\xb4\x46\xd6\x97\x6b\x92\x3d\xf1\xa6\x5c\xac\xc9\xe7\xa4\xb9\x81\x0b\xea\x18\xd5\xfa\x80\x97\x48\x8a\xac\x8d\x59\x43\x67\xb3\xce\x66\x4c\xc9\xde\x8b\xcf\x6e\x36\xb2\xb7\xef\x83\x3b\x12\xda\xd4\x16\x52\x50\xdd\xc4\x8f\x9c\x78\x32\xf4\xec\x55\x27\xff\x90\xa5\x35\x07\x7a\x39\xac\x1e\x2a\xe0\x4b\xe0\x3d\x84\x0a\x25\x94\xdc\x60\xe4\xc1\xb8\x43\x51\x87\x62\x0e\xd6\x4d\x74\xdd\x44\xd7\x4d\x74\xdd\x04\xeb\x26\xba\x6e\xa2\xeb\x26\x58\x37\xa1\xba\x89\xfe\x4e\x19\xcb\x63\x62\xd0\x3c\xdc\xcf\x6e\x82\xec\x26\x48\x35\x41\xaa\x09\xb2\x9b\x20\xbb\x09\xb2\x9b\x20\xbb\x09\x52\x4d\x90\xf6\xff\x36\xa6\x2c\xf1\xae\x18\xf5\x87\x05\x54\x3c\x40\x39\x4d\x18\x91\x5c\xf1\x82\x73\x5a\x6e\x17\x74\x40\x83\xef\x17\xff\x51\x41\xda\x78\xbf\xa8\x0c\xf4\xe9\xfe\x03\x2f\xb9\x96\xf2\x0d\xec\x5b\x85\xbb\xfa\xe1\x46\xcc\x18\x0b\xcd\xff\xc4\xd3\x3f\xeb\xc2\x5d\x46\xe9\xf4\x2f\x6e\x72\x74\x45\x29\x06\x00\x00")
+
+func dataStopwordsFiTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsFiTxt,
+		"data/stopwords-fi.txt",
+	)
+}
+
+func dataStopwordsFiTxt() (*asset, error) {
+	bytes, err := dataStopwordsFiTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-fi.txt", size: 1577, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xbe, 0xfa, 0x4d, 0x26, 0x2b, 0x9a, 0x73, 0x55, 0xf6, 0xd6, 0x6a, 0x49, 0x59, 0xe4, 0xc7, 0x26, 0x3b, 0x7b, 0xef, 0x2e, 0x97, 0x5a, 0xf7, 0xf7, 0x4b, 0x55, 0x4e, 0x5b, 0xe7, 0x6b, 0x54, 0x3c}}
+	return a, nil
+}
+
 var _dataStopwordsFrTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x54\x57\x31\x96\xe4\x2a\xcf\xcd\xb5\x91\x17\xf5\xa2\x28\x2c\x77\x69\x0e\x06\xb7\x04\x3e\xd5\xbd\x9a\x97\xfd\x53\x13\x4f\xf6\x42\x6f\xec\x3f\x57\xc2\xd5\xfd\x25\xf7\x5e\x04\x05\x32\x48\x82\x4a\xb7\xa6\x0b\xa5\x9b\xb5\x32\x36\xae\x9d\xd2\x2a\x95\x92\xb8\x14\x36\x4a\x52\x0a\x0f\x85\xa8\x26\x94\x4a\x99\x9d\xa5\x34\x87\x6a\xa0\xf3\x2f\xa5\xd2\x30\xac\x76\x56\xe1\xa1\xdf\x8a\x7f\x48\xa3\xb4\x4f\x3c\x7f\x1b\x25\x33\xfe\xa2\xd4\x3b\xd7\x65\x50\x1a\x79\xd4\x40\x0e\x32\x4a\xe3\x57\x1b\x70\x31\xf8\x9f\xfb\x10\x4a\x23\xe6\x18\x1f\x83\x0b\xa5\xa1\xc9\x41\x02\xdd\x3b\x08\x0b\x8a\x96\x37\xb0\xd6\x50\x99\xe4\xae\x0f\x7d\x11\x06\x1a\xbe\x71\x74\xf8\x0c\x5c\x9b\xcf\xd2\x95\x63\x73\xa0\xc2\xe0\x6e\x3c\xe0\x40\x71\x8b\x4b\xa3\x74\x4c\x07\x0e\x5f\xff\xf0\xe5\x8f\xe4\x16\xce\x80\x2f\x4a\x87\x04\xfa\xca\x47\x13\x05\x42\x7f\xfa\xc0\x4f\xf4\x7e\xc2\x70\x4b\xc6\x4c\xb7\x56\xf1\xed\x74\xe3\x34\x1c\x72\x1b\x3b\xdd\x84\x2b\xdd\xe4\x5d\x31\x60\x6c\x74\xd3\x74\x34\xba\xa9\x2a\x65\xce\x42\x99\x17\x7e\x50\xe6\x92\x00\x85\x03\xdf\xbc\x07\xa2\x9c\xff\x86\xb2\x49\xaf\x2e\xbb\xfa\x86\x04\xce\x9e\x21\x57\x87\x63\xed\x94\x79\xe7\xba\x24\x57\xda\x93\xd4\x8b\xf9\x25\xec\x52\x21\xbc\xdd\x3b\xfa\xc7\xc3\x21\x26\x1f\x8f\x98\xfb\x9e\x10\x02\x41\x0c\xfe\x18\x20\x56\x07\x03\x7e\x51\xbe\x4b\xbe\xc3\x3c\x3a\xe5\xfb\xf9\x5b\x79\x92\x51\x96\xfa\xe1\x30\x52\x9d\x8e\xcc\xc6\xb7\x92\xf3\xf7\x36\x5b\x53\x96\x94\x29\x17\xc9\x94\xdb\xe6\xfb\x9a\xdb\x06\x3b\x10\x1f\xd7\xb6\x3d\x69\xba\x15\xfe\x21\xcd\xb5\x0a\xb8\x66\xd6\xea\xdb\xd0\x2a\x62\x27\x37\x5d\x24\x15\x9e\xbf\x1e\x98\x59\x53\xa6\x25\x55\xa3\x85\x6f\x6d\x74\x5a\x78\xb6\xee\x48\x9b\x85\x7f\x25\x5a\x7c\x67\x17\xde\x87\xc0\xa2\x55\x58\x2f\x66\x08\xfd\x16\xfe\xd5\x0b\x5b\xd3\x2d\xf9\x68\xbb\x62\x71\x4a\x17\xd6\x46\xb0\xd3\x78\x38\xc4\x47\xbf\x94\x3b\xb9\xb0\x07\xe9\xc2\x07\xbb\x37\x87\xa6\x40\xe9\xb4\xc8\xba\xb2\xfa\xa8\x4b\x61\x99\x4b\x87\x3c\x9f\xaf\x11\x21\xf9\xa7\xfe\x39\x06\x1a\xbe\x8b\x72\xee\x2f\x0e\x2f\xc4\x0d\x3e\x24\x1c\x71\xe2\x8b\x61\x78\xbc\xdd\x87\x3b\xf5\x78\xab\x3c\x56\x17\xc6\xbb\x5b\xe6\x97\x35\xf4\x37\x39\x7c\xca\x56\x33\x00\x6a\x7c\xb1\xe3\x1c\xa6\x52\xdf\x69\xd1\xe6\x8b\x46\x2d\x59\x86\xfa\x36\x9c\xcf\xdb\x70\xba\xf6\x97\xd7\x95\x3b\xf1\x7b\x2a\x1c\xe8\xfe\xba\x32\xf2\xf4\xf2\xa4\xda\xce\x3f\x5b\xc8\xb0\x5a\x58\x8c\xb8\xe6\xa6\x4c\x5c\x51\x5e\xd9\x83\x84\xab\x7f\x22\xd7\x43\xb4\x55\x62\xb3\x24\xc4\x1d\xeb\xb3\xf7\x0f\xfc\x6e\xf8\xb6\xf2\x30\xe3\xc0\xab\x65\x4e\xa8\x24\xce\x28\x17\xc8\xa2\x6b\xbd\x47\xba\x36\x95\x1f\x99\xf7\x7e\x3e\x89\x1f\x9d\xab\x35\xe7\x28\xd1\x7c\xfe\xe7\x83\xcf\xff\x70\x44\x2b\x3e\x14\x10\x25\xcc\x55\x70\x80\x0f\x19\xd0\xe7\xff\xb5\x4a\x2b\x7b\xcd\x5c\x91\x3c\x6b\x69\x99\xbc\x58\xae\x6e\x6b\x9a\x99\xd6\xf0\x7d\x75\xdf\xd7\xe9\xfb\x1a\xbe\xaf\xd3\xf7\xf5\xf2\x7d\x0d\x57\xd6\x70\xe5\x9d\xab\xd1\x1d\x8b\xdd\x59\x2a\xdd\x19\xb7\xcd\xbd\x21\x3d\xee\x4d\x37\x31\xf2\xac\xb9\xa3\x10\x7a\x38\x00\xe2\x58\xef\x43\x35\xdd\xe9\x7e\x3e\x4b\x32\x92\x6d\x6f\xda\x99\x7e\x0d\xfb\x18\x81\xde\xe8\x4c\x25\x89\x19\x2b\x95\x14\x99\x43\x85\x3d\x06\xca\x77\x2a\x95\x2b\x95\x70\x05\x52\xdc\x83\xa5\xd5\xf7\xce\xdb\x0e\xa5\x01\x98\x13\xd5\x11\xb9\x14\xc2\xc3\x60\x4b\x52\x7a\x03\xd5\x1e\xc8\x5e\x24\x3c\xa0\xb6\x54\x50\xba\x9d\xce\x27\x6d\xe9\x21\x1b\x82\xcb\xe7\x00\x18\x6d\xac\x59\x68\x43\x3d\x02\x54\x9e\x64\xce\x40\x78\xbd\x49\xcd\x8e\x0e\x73\x92\x36\x9d\x71\x11\xce\x34\xa9\x0b\x2a\x24\x04\xf0\x93\x6b\x78\x33\x4a\x97\x1d\x3f\x9a\xc2\x68\xfe\x22\x22\xa9\xa6\x3e\x94\xcb\xc5\x85\xbf\x95\x51\xe5\x54\x63\xc2\xca\xd9\x23\x58\xf9\x87\xf4\xf8\xf3\x2c\xad\x3c\x8e\x38\x9f\xda\xb6\x9b\xf2\x40\x10\x4c\xf9\x80\xd8\xce\x27\x0c\x3d\x45\xc9\xad\x0d\x29\x50\x51\xbe\x00\x57\x58\xd7\x36\x0e\xdc\x82\x93\x1f\x54\xcf\xe7\xcb\x83\xf3\xaf\xff\xc6\xc9\xa8\x95\x72\x3e\xa9\xd5\x2f\x06\xc4\xda\xcd\x3b\x86\x24\xa0\x75\x07\xa6\xe6\xf7\x3d\x66\xd4\x3e\xe9\x6a\x1a\xed\x09\xa1\xbc\x27\xf5\xe8\xde\x93\x16\x0e\xac\x3d\x58\x41\x9b\x00\x5b\xf4\x19\x76\x7e\x4f\xea\xa9\x0c\x96\x3c\x8a\xc4\xc0\xd0\x5e\xbc\xff\xa7\xb5\xc5\x7c\x66\xe7\x93\xae\x3b\x75\xe7\x6a\x4c\x3b\xeb\xc6\x68\xa8\x35\xc4\xc0\x25\x8c\x76\x1e\xb0\x0f\xaf\x73\x3b\xf6\x71\x5f\xd7\x4e\xfb\x0a\x33\x0e\x62\x97\xf3\x37\x9c\x2f\xc8\xa1\xbd\xb4\xb1\xd2\x5e\x06\xd6\x05\x9b\x83\x78\x50\xef\x65\xf4\xf3\x6f\xa7\xbd\x21\x3b\x4d\xd6\x6f\x65\x2e\x05\x97\xdf\x25\x60\x1a\xe9\x0e\x54\x87\x8f\xd1\xc4\x85\xbf\xba\xa6\xc0\x6c\xc3\x1f\x40\xbb\x72\x2a\x7e\x7d\xee\xca\x59\x6c\x7e\xad\xf2\xe6\xbb\x02\x8e\x1d\xb9\xd4\xd5\x6f\xb4\x6b\xbb\xcd\x5f\xb6\x9b\xdf\xe2\xbb\xb6\xcc\xb1\x3f\xda\xf0\x0c\x88\x97\xe4\x6e\xd2\x3b\xf9\xcd\x09\x40\x4a\xee\x78\x7a\xe2\xc6\x5f\x1c\x7b\xe0\xdb\xf9\xef\x9b\x35\x89\x46\x02\xa9\xcf\xfb\x31\x52\x6f\xfa\x15\x42\x2f\x7a\x3b\xa4\xbe\xf7\x68\x44\x0c\x7d\x4b\xf7\xd2\x4b\x06\x20\xb7\x8a\x45\x67\x2d\xb9\x2a\x08\xf8\x63\xfc\x33\xea\x54\x7c\x71\x74\x01\xe5\xf5\x4b\xa9\xbe\xbc\x3b\xd7\x04\x36\x4d\x1a\xe0\x6b\x41\x18\x29\x97\xd4\xe5\xe0\x97\x88\x3e\xde\x92\x7e\x0c\xdf\x2b\xe5\xba\x38\xe0\xc7\x6c\x1e\x87\xe0\x68\xf1\x6c\xa9\xe4\x2e\x2b\x29\x77\x1c\xa3\xf2\xd1\x24\x4b\x30\xea\xac\xa2\xee\x58\xca\xca\xb7\xc2\x83\x0c\x07\x69\x78\xb1\x58\xc2\xab\xa7\x0b\x59\x1a\x2b\x19\x62\xcb\x58\xbe\x98\x8c\x4b\x83\xde\x6e\x71\xd8\xa1\xfc\x2e\x71\x39\x2d\xd1\x76\xd8\x03\x62\x63\x8d\x35\x39\x48\x60\x0c\xf1\x88\x72\x8a\x96\x37\xf8\x0b\x28\x93\x70\x7d\x18\xeb\x8b\x30\x70\x14\x07\x0e\xf4\x1d\x32\xff\xa0\x28\xa4\x36\x0b\xa9\x79\x21\x35\xa9\x70\xfc\x7a\x3f\xd8\xab\x68\x5a\x0b\x2f\x90\xfa\x86\x57\x85\x35\x79\x78\xb4\x18\x9e\x86\x30\x7a\xf7\x80\x8a\x3c\xb4\x86\xe7\xbb\x35\x7f\xbf\xdb\xce\x59\x56\x3f\xc9\x6f\x19\xe6\x81\xb3\x5b\xc9\x7a\xdb\x29\xce\x62\x7a\x39\x6e\x5d\x10\x39\x36\xd6\x35\x2e\xe0\x97\xe2\x90\xb0\xc0\xa1\x11\xea\x88\x31\xce\xfc\x12\x76\xa9\x10\x8a\x9e\x5f\x8c\x81\x3b\xeb\xde\x0c\x6d\xed\x78\x88\xf6\x54\x17\x31\xf2\x28\xe9\x49\xf1\xd4\xa2\xee\x41\xec\xe8\x5e\xf5\x88\xe6\x8e\x88\xed\xe7\xb3\xd0\xbc\xca\xf0\xbf\x0d\x20\x0a\xec\x4c\x1d\x9b\xdc\x63\x93\xfb\xdc\xe4\xee\x9b\xdc\x5f\xbb\xda\xdb\xc8\x77\xff\x75\xf3\x3f\x75\xe8\x1b\x0e\x6e\xea\x1c\xe8\x05\xd7\x95\x51\x57\x8f\xb0\x1e\x0f\x4b\xaf\xef\x5d\xbd\x1f\x18\xc7\xf6\x2d\xc3\x65\x6d\x3b\x75\xaf\x0d\xdd\x9a\x54\xe0\x90\x4a\x03\x2e\x8d\x2a\x6b\xd3\x39\x72\x54\x3f\xa1\x20\x23\xff\xe7\x76\x24\xbf\xf2\xfd\x85\x76\xa0\x08\x46\x1d\x38\xe4\x48\x8e\xec\x60\x74\x94\x54\x29\x32\xe7\x68\xc2\x0e\xb5\xd3\xcc\xa1\x03\x2e\x1e\x08\x9c\xa3\xb9\x15\x85\xe5\xc0\xc7\x1e\x3f\x2e\xb5\x23\x2e\xae\x63\x5e\x5c\x07\xde\xa3\x9f\xa9\x56\x3a\x9f\x3d\xd2\xc0\x85\x05\x45\x6b\x1a\x1d\x91\x07\x20\x44\xdc\xf9\xec\xe7\x93\x27\xcd\xa6\xd1\xf9\x07\x9b\x78\xfe\xc1\x32\xff\x1f\x00\x00\xff\xff\xc0\x3e\x63\xff\xf2\x0f\x00\x00")
 
 func dataStopwordsFrTxtBytes() ([]byte, error) {
@@ -110,6 +227,246 @@ func dataStopwordsFrTxt() (*asset, error) {
 	return a, nil
 }
 
+var _dataStopwordsHuTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3d\x54\x4b\x8e\xab\x40\x0c\xdc\xfb\x96\x4d\xf0\x40\xa7\x3f\x44\x74\x07\x29\xdc\x62\x8e\xc0\x92\xc5\xac\x46\xca\x62\x94\x5d\x2b\xf7\x7a\x55\x86\x3c\x05\x57\x99\xe0\xb8\xed\xb2\x89\x13\xd7\x75\x2e\x8b\x1b\xc7\x69\x05\x4e\xc3\x83\x18\xc5\x05\x4f\x0b\x80\x30\xcd\xe2\xa2\xab\x55\x5c\xd2\xf8\x38\x50\xc3\x87\x3b\xcd\x1f\x57\xcf\x90\x0f\x67\x8b\xf2\x34\x4b\x92\x3c\x1f\x4c\xf1\xc1\x13\x53\xfb\x19\xc4\xe5\xec\x10\x33\xcf\x8e\xd0\x7e\x71\x30\xca\x58\xa7\x40\xc8\x06\x56\xde\x5a\x69\x6d\xa3\x7b\x3f\x79\x75\x8c\x6e\xfb\x5c\xa5\x53\x5c\xb1\xbd\x22\x28\x67\xdc\xb4\x6d\x96\x8b\x0f\xc1\x40\x3f\x84\xba\x2e\x05\xe7\xf5\x2a\xf8\x74\xac\x5c\xfb\xde\x0f\xa2\x68\x1b\xa6\xc5\xb0\x46\x3e\x18\x1e\xe8\x1e\x38\x2b\xb1\xed\x1d\xa8\xed\x65\x15\x1d\x47\x05\x9a\x2e\x1a\x71\x59\x78\x2c\xef\x6f\x60\xdb\x91\x2d\x9a\xfb\xfe\x2e\x6b\x7b\xce\xe6\x41\x3c\x4d\x1e\xca\x20\x32\x53\x16\x9d\x99\x17\x69\x56\xde\xac\xfc\x1e\x3d\xea\xba\x32\xe3\xd1\xd4\x17\xdc\x2f\x66\x94\xd1\x65\x4d\x32\xfa\xc2\x38\x9b\x11\x01\xba\xf8\x01\x5f\xf8\x18\x69\x5a\x17\x95\xe3\x08\x43\x96\xe7\x0b\x2e\xe8\xe8\x71\xfe\x75\xb2\x49\x5f\xdb\x2f\x2d\x4a\xd0\x7e\x41\xc3\x01\xf5\x1b\x28\x62\x82\xce\x5a\x4a\x7b\xe5\x70\xb8\x90\x1c\x9a\x62\x15\x42\xfb\x59\xcc\x6d\x4f\x36\x95\xdb\x33\xd1\x87\xcb\x5f\xd1\xc1\xc3\xa8\x83\x8b\x6d\xeb\x3a\x7a\xac\x23\xea\x08\xc9\x0d\x99\x3d\xda\x6c\x88\x1e\x08\x25\xed\xcc\xe4\x06\x47\x68\x1b\xfd\x6b\x2f\x49\x07\xd8\x51\x90\x2d\xdc\xb9\x6f\x49\x21\x0a\xb6\xe9\x58\xa6\x53\xcd\xe4\x73\xff\x9f\x82\x3f\x9d\x6a\x8c\xc1\x82\xcc\xaf\x23\xce\xf0\x74\x17\xa8\x9a\xbc\x09\x9c\xa6\x02\xe0\xaa\x00\x8a\x01\x46\x9e\x38\x42\x5b\xce\xec\xa0\x34\x01\xca\x1d\x9c\x05\x0d\x60\x7c\x98\x06\xd0\x0b\xc7\x92\x7d\xbe\x14\xc9\x6d\xc7\x11\x44\x2c\xe7\x83\x4e\x0c\xd4\xe4\x58\xf6\x09\xad\xdc\x94\x05\xdd\x74\xc6\x14\x65\x6e\x9b\x14\x29\xee\xca\xae\x0b\xb2\xc0\xd0\x5a\xc1\xe2\xc3\x5c\x25\x06\xec\x37\x62\x13\xd7\x14\x3c\xb3\x95\xb2\x7a\x47\xc8\x55\x41\x6d\x63\xf5\x4e\x2a\x65\xcf\x52\x75\x64\xba\xaa\xf1\x8a\xc1\x56\xac\x0b\x5c\xdc\x4f\x8b\x0d\xe5\x64\x9c\x5c\xdb\x13\xf7\x77\xae\x10\x36\xe4\x5e\xa7\x58\xb0\x15\xc7\x7b\x65\xe8\x64\x61\xef\x04\x04\x90\x50\xd8\x82\x3f\x01\xf4\x4c\x4a\x1f\x42\x49\x70\xf0\xe3\x05\x5d\x2e\xc7\xdb\x0c\x85\x55\x16\x5f\xca\xea\x48\x78\x85\x11\x34\x45\x26\x9d\xa2\xb9\x95\x51\xa4\x64\x74\xc7\xba\xb5\x0d\xb7\xf1\x24\x94\x89\x74\xec\xa5\xed\xe0\xfd\x76\x53\x52\x11\x4c\xe5\x21\x58\x41\x4a\xd8\xfe\xe8\xff\x5d\x69\xf8\x13\x23\x41\x8a\xf6\xea\x17\xac\x23\x97\x07\x83\xc6\x7b\xf8\xfe\x0e\x34\xec\xe1\x3f\x05\x4f\x45\xd2\xec\x04\x00\x00")
+
+func dataStopwordsHuTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsHuTxt,
+		"data/stopwords-hu.txt",
+	)
+}
+
+func dataStopwordsHuTxt() (*asset, error) {
+	bytes, err := dataStopwordsHuTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-hu.txt", size: 1260, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xa6, 0x4, 0xb4, 0x8d, 0xde, 0x8b, 0x8, 0xea, 0xf6, 0x4f, 0xc2, 0xaa, 0xb, 0xf9, 0x13, 0x1a, 0x69, 0xa5, 0xf2, 0xcd, 0xc1, 0x31, 0xa4, 0x5a, 0x39, 0x28, 0xd6, 0xe4, 0x6c, 0xf5, 0xd0, 0x2b}}
+	return a, nil
+}
+
+var _dataStopwordsIdTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3d\x53\x5b\x8e\xc3\x20\x0c\xfc\xe7\x96\xce\xc6\x4a\x1c\xc0\x45\x80\x55\xf5\xf6\x3b\xe3\xae\x56\x2a\x33\xae\x31\x7e\x47\x4e\x29\x72\x4a\x93\x9b\xe4\x1f\xfc\xbb\xa4\x12\x66\x91\x2a\x0e\xb8\x6d\xa6\xbe\x06\x4f\x5a\x76\xd9\x45\x9c\x4f\xfd\xef\xad\x6f\x3e\x20\x42\x39\xf2\x1c\xd6\x92\x2b\xef\x07\xcc\x2e\x2b\xb2\x65\x11\xe0\xea\x2d\xad\x1c\x88\x63\x5d\x5c\x28\x19\xe0\x66\x48\xd0\x9b\x1a\xab\x00\x84\x26\x8d\xc4\x19\x80\x37\xfc\x1d\x7a\xe8\x64\x9c\x43\x2f\x73\x4b\xda\xb8\xd4\x16\x1d\xe8\x48\xe6\xdf\x60\x66\x14\x0a\x56\x63\x93\x17\x0a\x00\x2f\x5a\x31\xc9\xc3\x16\xe0\xd5\x14\x8e\x03\xa5\x1d\xc1\x34\x7e\xa2\xc6\x28\xac\xaf\x03\x1d\x67\xe0\xee\x94\x69\x09\x03\xfd\x2a\xa7\x56\xea\xb4\x43\xa7\x7e\xd1\x0a\x92\xe1\xc2\x68\x16\x10\xa2\x45\xb9\x51\x2b\xce\x0b\xd0\x87\x4d\x10\x3b\x7a\xd3\x15\x20\x56\xb9\xcd\xaf\x0b\x9a\xd7\x64\x6b\xf1\xde\xb2\xad\x76\x44\xc1\x8d\x79\x61\x91\xac\xf0\x11\x04\x78\x24\x43\x3d\x56\xa5\x3c\x71\x11\x3a\xcd\x2b\x1e\x05\x10\xd9\x00\x28\x39\xce\x44\x3b\x40\xcb\x68\xb0\x21\x2a\x7e\xfd\x90\x66\xe4\x40\xb6\xb0\xd2\x2c\xa7\xea\x7c\x88\x9b\x9e\xab\xc1\x38\xa7\xd6\x04\x19\x34\x41\x21\x8d\x9d\x6b\x7a\xc0\x57\xc7\x60\x4b\xce\xae\xa7\x6f\xa2\x5f\xa0\x28\x5d\x1b\x36\x85\xd1\xbb\xc2\xe2\x22\x59\xb3\x6a\x10\x3c\x2b\xe8\x3a\x95\xcf\x75\xc6\xc8\x35\xeb\xaf\xfb\x05\x0c\xbf\x2a\x62\x39\x36\xc9\x4a\x0e\x24\xf3\xc2\xf6\xd0\xf7\xe0\x76\x0d\x9d\x8e\x5a\x07\x36\x11\xe0\x65\x09\x3a\xb6\x04\x79\xaf\xec\xd9\xca\x81\xe5\x88\x01\x03\x93\x5f\x6c\x17\x6d\xd0\xf3\xa5\xdf\x6d\x00\xe7\xae\x80\x83\x8f\xf4\x64\x9e\x4b\xff\x06\xb1\xf4\xc1\xc2\x2d\xe4\x38\xbf\xfa\xac\x7e\x29\xf2\xcf\x15\x87\x14\x44\xcf\x39\x2f\x45\x52\x9b\x3c\x37\xb5\x5b\x33\x11\xe3\xfe\xad\x38\x29\xa3\x4c\x44\xdf\xe2\x83\x38\xac\x7c\x6d\x36\xfd\x21\xc0\xd6\x79\xa3\xd0\x41\x01\x1f\x04\x89\x99\x6d\x08\x5f\x6b\x3b\x91\x4f\xf8\x8e\x5a\xde\x52\xb1\x08\x1f\xb1\xc4\x8a\xc5\xf8\xd0\xc5\x2f\x27\x4c\x91\x81\xca\x03\x00\x00")
+
+func dataStopwordsIdTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsIdTxt,
+		"data/stopwords-id.txt",
+	)
+}
+
+func dataStopwordsIdTxt() (*asset, error) {
+	bytes, err := dataStopwordsIdTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-id.txt", size: 970, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xac, 0x9d, 0x97, 0x2d, 0x50, 0x22, 0xd2, 0x2c, 0x1e, 0xbd, 0xea, 0xc0, 0xa4, 0x80, 0x9, 0x76, 0xc2, 0x99, 0x1b, 0x54, 0x4b, 0xd8, 0x5, 0x25, 0x9d, 0x5f, 0xc1, 0x46, 0xca, 0x72, 0xfe, 0xbb}}
+	return a, nil
+}
+
+var _dataStopwordsItTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x45\x53\x31\xb6\xdc\x20\x0c\xec\xb9\x25\x6b\x6b\xbd\x7a\x0f\xc3\x06\x30\x45\x4e\x93\x32\x69\x7f\x97\x7a\x2f\x96\xd1\x48\xfe\x79\x46\x33\x48\x08\x10\x92\x9c\x53\x7e\x3c\x34\x3b\x9e\xcd\xb9\x06\x4f\x49\x79\x4f\xf9\x28\x26\x9a\x32\x06\xa6\x85\x92\x0d\xc4\x00\xce\x75\x7b\x89\x61\xeb\x30\x2f\x39\xed\xa0\x25\x75\x27\x8d\x21\x41\x3d\x74\x0d\x72\xb7\x31\x7d\x7d\xd2\xec\xca\xca\x8e\xee\xb1\x18\x91\x71\xac\xd2\x73\x99\xad\x5b\x50\xc0\x4a\x8f\x2e\x8f\x87\xdc\xdc\xdd\xc2\xf5\x08\x09\xec\xe4\x77\xf6\xb8\xb4\xfb\xad\xfd\xf3\x8b\xf8\x05\xbc\x66\x26\x0a\x51\x89\x2d\x6d\xc9\x1e\xba\xbd\x34\x6d\x36\x32\x2c\x0d\x93\x56\x20\x27\x16\x5a\x35\x99\xdd\xec\x7d\xd7\x8c\xa4\x6d\x97\xa6\x3d\x63\x20\x8b\x3b\xd3\xb8\x67\x13\xd3\x8a\x43\x26\x0a\xb1\xa5\x5d\xcc\x53\xe8\x29\x26\xa6\x15\x87\x4c\x14\x22\x3c\xb1\xaa\x78\x41\x45\x7c\x7b\x5b\x26\x92\xf0\x59\x0e\x22\x01\x20\x4d\xb2\x27\x39\xba\x1c\x0a\x1d\x05\x82\x54\xce\x98\x5e\x32\x9e\x29\x1d\x8e\xb6\x03\x75\xb2\xc2\x3d\xf3\x86\x07\x06\x9d\xb7\x5e\xef\x09\x76\x70\x42\x9d\xd9\x35\x8e\x8d\x2c\x79\x70\xbf\x2d\x7a\x73\xf8\x8e\x19\x3e\xd3\x57\x56\x0e\x8a\xf5\x15\x97\x79\xd5\x39\x09\x47\x33\xdb\xb4\xd2\xa1\x73\xde\x6f\x8d\xaf\x0f\xee\x6e\xe1\x7a\xc4\xc8\x0e\x30\xf2\xeb\x7b\x5c\xcf\x0e\x00\xa1\x03\x0c\xbf\xd2\x53\x36\x21\xd8\x19\x82\x6a\x3f\x1b\x1f\xd5\xe2\x49\x8d\x0f\x6a\xf1\x9c\xc6\xd3\x1a\xcf\xba\x30\x8c\x78\xdf\xd5\x1b\xc2\x3a\x04\x25\x42\xd9\x0e\xc5\xf9\x56\xd8\xa3\xe7\x9f\x2a\xe9\x95\x31\x14\x62\xb1\xbf\x5a\xd2\xa4\x25\x69\x4d\xc8\x6a\x49\x28\x36\xb6\x14\x44\x8f\x0d\xa8\x76\x69\xb8\xb7\xe0\xe8\x33\xa7\x13\xa8\x46\x62\x62\x4a\x4b\x67\x2b\xe8\xd0\x2a\x18\x68\xa0\xca\x06\xaa\x62\x62\x5a\x71\xc8\x44\x21\xc2\x19\xcd\x5b\xd1\xb3\x15\x91\xa3\x31\x48\xe2\xa4\x4e\x2d\xe1\x3b\x0e\x4d\x6f\xe9\x26\xdb\xeb\xf3\xc7\x18\x19\x7a\xeb\xe7\x6f\x7a\xe3\x84\x1f\x57\xc6\x89\xc0\x8a\x7f\x86\x14\x9a\x3a\x35\x10\xaf\x26\x89\x93\x3a\x71\x6d\x70\x1f\x2b\x42\x52\x27\x5b\xd3\x8a\x26\x1f\xb9\xd8\x2f\x38\x58\xea\x11\xa5\x1e\x51\xea\xf1\x5d\xea\xc1\x52\x8f\x28\xf5\xf0\x52\x8f\x28\xf5\x88\x52\x0f\x2f\xf5\x60\xa9\x07\x4b\x8d\xb2\x0e\xdb\x28\xe7\x1b\xaf\x47\x59\x07\x52\x3b\xd8\xf4\x83\x1d\x3f\xd8\xee\x43\xb9\xd1\xea\x69\x01\x43\xd4\xc0\x5a\xde\xc8\xcd\xdd\x8d\xfd\x5b\xf7\x18\xe7\xff\x20\xa7\x47\x39\xef\x30\x67\xc4\x39\xef\x40\xe7\x1d\xe9\x8c\x50\xa7\xc7\x3a\x3d\xd8\x89\xdf\xd5\xd1\x77\xf1\x37\x21\xbb\xef\xe2\xce\x65\xb6\xb8\x80\x7f\xa3\x53\x0f\x5d\x83\x62\x7d\xfa\x3a\x2f\x95\xe9\x1a\xc8\xbd\x27\xcd\x96\x92\xe9\x39\x99\x9e\x94\xe9\x59\x41\x99\xc6\x85\x81\xf5\x0b\xea\x85\xde\x33\xc0\x9e\xcb\xc4\xb4\xe2\x90\x89\x42\xb4\x4d\x14\x4d\x38\xde\x9a\x6f\x5e\x18\x46\x02\x69\x26\xb0\x5f\x76\xb9\x61\x4b\x57\xc5\xc8\x90\x96\xf0\xc4\xd5\x4c\xd8\xb5\xcb\xbb\x76\x79\xd7\x2e\xef\xda\xcf\xef\xf4\x0f\x44\x0d\x06\x90\xda\x06\x00\x00")
+
+func dataStopwordsItTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsItTxt,
+		"data/stopwords-it.txt",
+	)
+}
+
+func dataStopwordsItTxt() (*asset, error) {
+	bytes, err := dataStopwordsItTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-it.txt", size: 1754, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x69, 0xdd, 0x37, 0x7a, 0xfe, 0x17, 0x38, 0xef, 0xb4, 0x66, 0xf, 0xa3, 0xd4, 0x2d, 0xfd, 0x19, 0x7c, 0xba, 0x3c, 0x44, 0xb0, 0x3f, 0x2a, 0x74, 0xfa, 0xbf, 0xf, 0x5f, 0x5c, 0xd2, 0x5d, 0x48}}
+	return a, nil
+}
+
+var _dataStopwordsNlTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x92\xeb\x8e\xed\x20\x08\x85\xff\xf3\x96\xce\x94\x5d\x6d\xad\x4c\x14\xdb\xc4\xa7\x3f\x1f\xdd\x27\x61\x2d\x04\xa9\xdc\x9a\x52\x93\x54\x91\xaa\x2f\x0d\x38\xe0\xe5\xd8\x50\x77\x1a\x2e\xa9\x6d\xda\x55\x7e\x74\x4b\xed\x74\x74\x03\xc3\xf1\x94\x03\xb4\x16\x0e\xbb\xe1\x2d\xa5\x0e\xc5\xa1\x9d\x90\xcb\xa6\x08\x3e\x5d\x1c\x4a\x00\x9f\xfd\x66\x28\xe2\xcd\xb8\x9b\x43\xf4\x37\x3b\x61\x8a\x0f\x60\xd7\x53\x25\x8c\x76\x2a\x95\xc5\x55\xbc\xbf\xa7\xf4\x92\xcb\xae\x48\x8a\xaf\xd0\x71\xa3\x8f\x52\x93\xec\x3d\xa5\x1d\x36\xf5\x2f\x73\x97\xa3\xaa\x9c\x36\x50\xab\xc1\xdd\xb5\x96\x83\x0c\x59\x7f\x02\xd1\x51\x56\xfd\x38\x7c\x01\x74\x21\x65\xa6\xbd\x6c\x44\x59\x91\x3c\x9b\x14\x8d\x56\x8a\x5e\x0c\x04\xe5\x43\xca\x29\x05\xff\x90\x23\xc9\xa1\x72\xd8\x7c\xe4\x98\xb5\xd2\xe8\x49\xa5\xa7\x5d\x0e\x71\x98\xef\x90\x50\x2e\x57\x54\x73\x29\xa2\xa1\x1b\xc0\x49\x2a\x80\x11\x85\x5f\xf7\x2e\x2d\x21\x44\x36\x12\xbd\x34\xa4\x19\xee\x29\xf6\x11\xbb\x90\x18\xaf\xc5\x6a\xe0\x01\x98\xb0\xd9\x29\xf6\x27\xec\xa2\x4b\x57\xdd\x86\x8c\xd2\x60\xa6\xe4\xba\x93\x2b\xd6\x1a\xf3\xf5\x58\x81\xc7\x0a\xdc\x5c\x7c\x8e\xc1\x71\xca\x64\x3b\xb4\x70\x53\xfb\xad\x5a\xe5\x8e\xfd\xdc\xbd\x28\x69\xbe\x13\x7b\xa2\xa6\x20\x4a\x78\x12\x5d\x69\x98\xb4\xf5\xa4\xce\x13\x4f\x1a\x00\xeb\xf5\xf3\xc2\xf3\x6e\xf2\xd1\xbe\x41\x2b\x22\x98\xcd\x53\x6a\xa0\x86\x69\x7d\xfb\xaf\x5c\x16\x7f\x22\x5d\x2c\xad\x1f\x59\x85\x0a\x17\x63\x59\x31\x96\x65\x48\xfc\x99\xeb\xdb\xf1\xb2\x29\x6b\xbe\x2f\xfc\x03\xc2\x01\x7e\xec\xc2\x02\x00\x00")
+
+func dataStopwordsNlTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsNlTxt,
+		"data/stopwords-nl.txt",
+	)
+}
+
+func dataStopwordsNlTxt() (*asset, error) {
+	bytes, err := dataStopwordsNlTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-nl.txt", size: 706, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1b, 0xf5, 0x9, 0x9d, 0x60, 0xdc, 0x98, 0x5c, 0x17, 0xa4, 0x65, 0x23, 0xc5, 0xff, 0xf9, 0x63, 0x53, 0x47, 0xae, 0xb9, 0x6b, 0x5a, 0x61, 0xcd, 0x4a, 0x1d, 0xe6, 0x44, 0x47, 0xec, 0xce, 0x9a}}
+	return a, nil
+}
+
+var _dataStopwordsNoTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x92\x5b\x8e\xe4\x20\x0c\x45\xff\xbd\x4b\x4a\xa1\x12\xc2\xab\x84\x49\xa4\x5e\x50\xcd\x1a\xfa\x3f\x1b\x9b\x73\x99\x91\x7c\x0f\x60\xc0\x76\x4c\x42\x29\xd1\xc2\xb4\x70\xdb\x2b\x8c\x68\xaf\xb8\xef\xb0\x2c\x25\xb0\x34\x84\x39\xed\xf5\x7c\xb7\x28\x46\xdb\x82\x31\xdd\xe2\x8e\x92\x54\x85\x11\x16\xa3\x33\xc8\xd1\xa4\xa6\x73\x43\x5a\x93\xb5\x39\xa5\xc9\x9a\xbb\x89\x53\xc9\x5d\x0b\x72\x6c\x97\x6d\x3f\x39\x2f\x04\x6e\x3d\x5f\x23\x49\xe4\x50\x4c\x53\x02\x14\x3d\x16\xdd\x48\x41\x06\xd3\x9a\x8d\xb9\xc4\xe2\xdd\x97\x08\xff\xa6\xa6\xf7\xf3\x3b\xec\x08\xd8\x46\xd1\x47\x68\x92\x03\xbc\x54\x7f\x10\x61\x4d\x55\xea\x22\x7b\x04\x39\x52\x71\xe2\x1f\x27\x35\x1c\x1d\x63\xb7\xb7\x5e\xa1\xfb\x82\x76\x2f\x74\x13\xfb\xe6\x8b\x01\xd7\xee\x54\x72\xfc\x3f\x68\x33\x71\xf8\xee\x63\x61\x53\x72\x46\x15\x98\x2c\x65\x0e\x82\x13\xb6\x9d\xc3\x10\x6f\xfb\xe7\xd0\xb2\x25\x3b\x83\x9d\xb4\x20\x73\x33\x93\x3c\xf7\xc1\xd3\xb8\x46\xef\x96\x49\x8f\x28\x3c\x53\x04\x1a\x0b\x47\x2c\x3e\x99\x11\x32\xdf\x49\x52\xc2\x4a\x08\xc4\x0b\x57\xd9\x26\xc9\x45\x74\x44\x03\x2b\x4d\x25\x45\xe5\x5a\x4d\x98\x06\x42\x57\x75\xbd\x76\x74\x1f\x56\x7f\x54\x5d\xe3\x76\xeb\x58\x94\x1a\xc8\x41\xe8\x0b\x6b\x4d\x42\xc8\xa3\x37\xfa\x87\x86\xf5\x1d\x73\x56\xe4\xe8\x9f\x8f\xa9\x8d\x5d\x2d\xfb\xe0\xf3\x50\xa9\xca\xa9\xc5\x63\xb9\xcd\x13\x16\x90\x2a\x84\x51\x5c\xe2\x90\xea\xf1\xf3\xf9\x2d\xe6\x39\x08\x97\xfe\x63\x2f\x29\x1b\x3d\x71\xc2\x23\x45\xeb\x95\x83\x0a\xfe\x7c\x69\xe7\x0c\xfc\x58\x33\x15\xbb\xaa\x5d\xe4\xbf\x26\x46\x60\x75\x0d\xcd\x85\x68\x37\xdf\x46\xe7\x5a\x49\x3b\xe3\x90\x63\xb9\x13\x56\x24\x72\xf1\x84\x0b\x2e\x70\x51\x9f\x77\x3f\x7f\xe4\x84\xd3\xc8\xf9\x17\x68\xa4\xfa\xae\x5c\x03\x00\x00")
+
+func dataStopwordsNoTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsNoTxt,
+		"data/stopwords-no.txt",
+	)
+}
+
+func dataStopwordsNoTxt() (*asset, error) {
+	bytes, err := dataStopwordsNoTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-no.txt", size: 860, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xb0, 0xbf, 0x61, 0xe2, 0x1a, 0x27, 0xf, 0xcd, 0x98, 0xa4, 0xa3, 0xba, 0xc, 0x2e, 0x67, 0x70, 0x5c, 0xbe, 0xec, 0x2d, 0xe6, 0xbd, 0x26, 0x69, 0xe, 0x5c, 0xec, 0xd, 0x7d, 0x2d, 0x32, 0xb5}}
+	return a, nil
+}
+
+var _dataStopwordsPlTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3d\x94\x41\x8e\xe3\x20\x10\x45\xf7\x75\x4b\x6c\xa3\x0c\x76\x30\x51\x8c\x85\xec\x65\x6b\x5a\x73\x86\x51\x1f\xa3\xb7\xd9\x75\x7c\xaf\x79\xbf\x9c\x8c\x42\xfd\x5f\xc1\x05\x14\x9f\x82\x60\xa1\xdb\x2c\xf4\xbf\xb0\x5d\x36\x95\x6b\x4b\x71\xb2\x30\x5a\xb8\x76\x05\x88\xb2\xe3\x61\x61\x4e\x16\xe0\x2e\xdc\x87\x3d\xc5\xf1\x74\x8a\x75\x6a\x8c\xc9\xc6\x4c\xdd\x76\x4d\xc0\x1c\xc6\x3c\x7b\xcc\xf6\xfc\x03\x1c\x1f\x0e\xc1\xb1\x38\x12\xfb\xfc\xab\x89\x9c\x9f\x9f\xd6\x07\x86\xf6\x41\x51\xc2\xcd\x7a\xfe\xa6\xd8\x11\xd1\xa7\xe7\x5f\xeb\x0b\xed\x9d\x5e\x5f\x8e\x2f\xeb\xf7\xb0\x84\x9c\x9c\x59\xbe\xdf\x63\x5e\xc1\x4d\xc6\x5c\x03\x79\x4f\xc5\x86\x6b\x90\xf1\xf5\xe2\x7f\xaa\xb3\x5a\x77\xdf\x1c\xf7\x08\x4d\xcf\xcf\x01\x3a\xbe\x48\x78\x58\x8f\x07\x01\x2d\xc8\x46\x80\x1c\x86\x56\x46\x90\x84\x8f\xdf\xa2\x25\xe9\xcb\x4e\x66\xd3\x3a\x92\x1e\x2e\x29\x5d\x86\x4d\xd6\x39\x22\xd6\xc5\x77\xe8\xf8\x4e\xdd\xff\x10\x9a\x2c\x21\x7b\x42\xdf\x94\x2d\xcd\x73\x10\x44\xc1\xe6\xa0\x8f\xcc\x30\x06\xda\x24\x0b\x0c\x82\x98\x1b\x4c\x0e\xfd\xaf\xb3\x8f\x35\x84\xaf\x3f\x3e\x6a\x7a\xaf\x87\x5b\x1c\xf4\x95\xb8\x38\xc4\x59\xc8\x82\x8e\xd3\x8b\x8e\x87\x7f\x9c\x09\x96\x42\x23\xa7\x37\x4a\xd0\x31\x2e\xd5\x01\x89\xa1\x1d\x21\xe1\xe3\x0b\x85\xa1\x47\x14\xaf\x5a\x92\x33\x24\xc7\x07\x0a\x90\x8f\xe3\x75\x0a\x60\x66\xe1\xa9\x16\xd9\x3b\x27\x5c\xef\xfc\xf9\xbe\x87\x93\xe2\x8b\x2e\xe5\xe5\x8c\x27\x6f\x2f\x42\x8d\xd3\xc9\x27\x73\xca\xd3\x6a\x9c\xa6\x5d\x23\x95\x7b\x5d\x3b\xcb\x81\xa6\x2c\x72\xc8\x98\x0a\x8d\xe2\xc8\x29\x8b\x55\x6b\x9b\xa9\x28\x01\xc5\x94\x8b\x63\x92\x11\x5e\xd0\x39\xeb\x84\x73\x91\x10\xc2\x6b\x6a\xa7\x83\x52\xe8\x90\xd7\x85\x60\xe6\xf8\xf9\x1e\x8d\xae\x39\x0c\x58\x96\x25\x60\x91\xb9\xb3\x3b\x04\xc7\x78\x22\xbb\x12\x6b\x17\x73\xa8\x25\xa7\x80\xa6\x78\x74\xbc\xfd\x16\xc1\xd4\xcb\x08\x22\x4d\xec\xf4\x34\x58\x77\x09\x20\x8d\x39\xa9\xc6\x66\x72\xc6\x58\x2f\xb1\x8d\x59\x47\xce\xc1\xf1\xeb\xca\x64\x65\x30\x94\xd6\xfe\xcb\x4c\x0b\x58\xc4\x12\x46\xd7\x3d\xec\x56\x38\x8e\xd2\x48\x2c\xdb\x2d\xcc\xb2\x20\x48\x76\x2b\xb4\x41\xa6\x4b\x05\xbb\x7c\xb7\xa2\xcd\x82\x29\x36\x3d\x01\x37\xee\x3b\xfe\x7c\x3a\x1a\xeb\x9c\x5e\xac\x01\xbb\x3a\xf7\xe1\x1e\x5a\x42\x23\x77\xf5\x48\xcc\x89\x7e\x75\x46\x68\x8f\xdc\x6f\x4d\x87\x37\x9c\x78\x76\x0f\xaa\x35\x39\xbb\x70\xb3\x7b\xe1\xac\xef\x3f\xdf\x6d\xf6\x78\x2e\xbd\x2c\xd8\xa2\x77\x61\xf1\x9b\xbb\x14\x3d\x14\x20\x72\x2c\xb7\xb2\xfc\x7c\x77\xb6\xf8\x95\xe5\x28\xe6\xd2\x48\xf2\xe5\x6c\xb6\x10\x53\x03\x6d\x92\xb9\x93\x1c\xa2\x50\xc7\x5f\x59\xa1\x42\xd2\xbe\x22\x7d\x95\xf2\x95\x0d\xd7\x28\xf9\xaa\xb2\x40\xc2\xea\x8b\x56\x5f\xb4\x96\xb3\x97\xac\x3b\xa6\x24\x7c\xad\xbc\x10\xb5\x51\x62\x82\x2c\xa4\xc8\xaa\x27\x55\xdb\xa6\x0e\x95\x52\xdd\x4c\x75\x00\x5c\xb9\xa8\x95\xfe\xd5\x9a\x35\x32\x68\xaa\xac\xc6\x31\x34\x15\x55\xf3\xa2\x6a\x5e\x54\x4d\x6d\x38\x3e\xd6\x8b\x21\x25\x4f\x88\x90\x61\xe8\xd1\x9f\x48\xd2\x9c\xef\xb6\xf4\xdb\xc9\x55\x8f\xc5\x7f\x37\xfe\xf7\xf2\xdb\x2b\xd6\xaa\x6e\x6d\xa3\xf1\x06\x1f\x5f\xaa\xc1\xdd\x58\x72\x0f\xb7\xd8\xa8\xa0\x3d\xa8\x62\x4c\x0d\x1d\x57\x90\x13\xb1\xbd\x2c\x95\xbb\x66\x3b\x76\x3c\x82\x1e\x17\x11\x7a\x3b\xc5\x93\xb4\x41\x09\x8b\xf1\x7e\xfd\x03\x8f\xf0\xdf\xfc\x76\x06\x00\x00")
+
+func dataStopwordsPlTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsPlTxt,
+		"data/stopwords-pl.txt",
+	)
+}
+
+func dataStopwordsPlTxt() (*asset, error) {
+	bytes, err := dataStopwordsPlTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-pl.txt", size: 1654, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3a, 0x6a, 0x3b, 0x84, 0x9c, 0x5, 0x27, 0xbd, 0x50, 0x60, 0x36, 0x35, 0x10, 0x45, 0xb1, 0xfb, 0xcc, 0xa9, 0x3c, 0xd0, 0x94, 0x1, 0xd4, 0xfc, 0x31, 0x97, 0xc4, 0x9, 0xd3, 0xe8, 0xb2, 0x8}}
+	return a, nil
+}
+
+var _dataStopwordsPtTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x54\xc1\x6e\xe4\x30\x08\xbd\xf3\x97\xcc\xc4\xd3\x71\x35\x0e\xa9\x71\x72\xe8\xd7\x74\xb5\x87\xd5\xac\xd4\x53\xf7\x0f\xfc\x63\xfb\x00\x3b\xad\x14\x1e\x60\x30\xc6\x60\xc2\xc4\x97\xca\xfd\x8f\x28\xf1\x8b\x54\x26\xce\xeb\x02\x14\x7c\x58\x7a\xdb\xd3\x83\x07\x1b\x6a\x1a\xcc\xd5\xec\xf0\x80\x37\xd4\x96\xd6\x6b\x16\xe5\x92\xd6\x06\xaf\xd6\x9f\x74\x65\x04\xbb\x4a\x31\x12\xba\xee\x65\xab\xd9\xcc\x88\x0d\xc3\x82\x5d\x4b\xc2\x87\x33\x16\x3f\x61\xb1\xf8\x8b\x47\x5f\xd2\x26\x19\x4c\xf0\x29\x25\x32\x27\xf7\x31\x17\xf7\x48\x85\x10\xaa\x42\x43\xde\x20\xa8\xaa\xec\xa0\x86\xc9\xc1\xc4\xc6\x0e\x45\x42\xa9\x8e\x21\x1f\x3c\x98\xed\x6e\xc9\xe1\x95\x07\x2b\x93\xc7\xc6\x88\x95\x0e\xf7\xca\x27\x1b\x56\x48\x75\x72\x3e\x85\x32\xa5\x74\x4a\xdf\xfe\x91\xe3\x90\x86\xbd\x3f\x2b\x9f\x1e\xfd\x69\x86\xd0\x64\x37\xec\xbf\x02\x8f\xe9\xd4\x7f\x0b\xa5\x9d\x6e\x7c\x48\xa5\x9b\x64\x90\x59\x6e\xae\x21\x11\x83\x62\x98\x1c\xc3\x66\x07\x3b\x62\x0d\x4d\xbc\xf5\x7f\x71\x28\x84\x71\xe0\x9d\x51\x05\x83\x12\xe8\x4b\x47\x9a\xbc\xd2\x3d\x65\xba\xcb\x8e\x2a\x38\xba\xc1\x84\x3a\x18\x4f\x5e\x86\x30\xfd\x2d\x91\x29\x9c\x9b\x32\x9f\xc2\xb4\x7e\x1b\x71\xe7\x21\xe0\xae\x43\xfa\xe4\xd3\x6e\x97\x99\x42\x6c\x9e\x35\x0c\x79\xde\xc8\xc2\x58\x84\xac\x6a\xd0\x84\x5e\xb1\xf4\xb8\x27\x23\xa5\xc2\xd9\x00\x94\xf0\x29\xde\x6b\x41\x5d\x41\x58\xc9\xeb\x9d\x03\xa1\xec\x19\x5b\x57\xc6\xa7\xb4\xe2\xc4\x15\x1a\xe2\x83\xf0\xfa\x1c\x43\x91\x40\x28\x7b\x31\x82\xd1\x8e\x5f\xfb\x97\x92\x90\x5c\x6a\x7e\xb1\xf1\x18\x02\x56\x1e\x48\x07\xee\x68\xf4\xc6\xa8\xdf\x66\x4f\x7e\xf3\x37\x0f\x14\x07\x88\x68\xec\x56\xd3\xbb\x6d\x0d\x2e\xf4\xb6\xf3\xc3\x60\x75\x39\x19\x15\x42\x55\xd4\x9e\xb2\xfa\x43\xd6\xf1\x8c\xd5\x2d\x98\x43\xb3\x56\x23\xf4\x45\x47\x2f\xd4\x1b\xa1\xd1\x05\xf5\xc2\x6b\x54\x5d\xcf\x92\x2b\x8a\xa2\x56\x14\xf5\x77\xa6\xc8\x56\x77\x36\x82\xe2\xae\xfd\x8b\x30\x6b\x97\xfe\x2c\x84\x81\x6a\xc9\x98\xb9\xe2\xff\x80\x2a\x3a\x96\xc1\xc6\xaa\x00\x2d\x8f\x36\xf2\x68\x9e\x47\x8b\x3c\x9a\xe7\xd1\x22\x8f\x76\xe6\xd1\x90\x47\xb3\x3c\x7c\x20\x9b\x77\xc8\x11\x3b\xb2\xaf\xc4\xa3\x8c\xb9\x1c\x53\x39\x67\x72\x4e\xe4\x39\x8f\x73\x1a\xcf\x59\xfc\x31\x89\x3f\xe7\xb0\xe1\x50\xdc\xb6\xd9\x6d\x9b\xdf\xb0\x7f\x8e\x8b\xa0\xc9\xd6\xe3\x43\xae\xfd\x6f\xa0\x82\x29\xf5\x0f\x7c\x60\x4f\x9a\x01\xff\x03\x8b\x79\x22\x6f\x75\x05\x00\x00")
+
+func dataStopwordsPtTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsPtTxt,
+		"data/stopwords-pt.txt",
+	)
+}
+
+func dataStopwordsPtTxt() (*asset, error) {
+	bytes, err := dataStopwordsPtTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-pt.txt", size: 1397, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0xdc, 0x27, 0x33, 0x8d, 0x20, 0x6a, 0xd6, 0xe3, 0xff, 0x81, 0x79, 0x5a, 0xac, 0x6c, 0xd5, 0xc5, 0x92, 0xc1, 0x1, 0x58, 0x3f, 0xc6, 0x9d, 0xd9, 0x6, 0xba, 0xaf, 0x6, 0x2b, 0xf3, 0x73, 0x54}}
+	return a, nil
+}
+
+var _dataStopwordsRoTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x3d\x94\x4d\x8e\xdb\x30\x0c\x85\xf7\xba\xa5\xa2\x28\xad\x0a\xfd\x18\xb6\xe4\x45\xb7\x39\x46\x17\x03\x4c\xf7\xbe\x44\x32\xc0\x1c\xab\xdf\xa3\xec\x02\xe6\x7b\x14\x45\x91\x14\xa9\xc4\x3b\x7f\x4b\xde\xf9\x10\x27\x6c\xfd\xe2\xf7\x53\xca\x34\x27\x03\x53\xb3\xc1\x54\xe3\x44\x5b\x6c\x7d\xe2\xb9\x88\x27\xd9\xf2\xeb\xa3\xa7\x8b\x65\x68\xb9\x81\xa3\x38\x8f\x59\x96\x74\x53\xb6\x14\x58\x12\x5f\x71\x2d\x6a\xee\x21\xee\x93\x53\x35\x8d\x23\x2b\x1f\x0e\x5b\x2c\xb1\xca\xc9\x52\xce\x4c\x2a\xfa\x37\x31\xb6\xb1\xac\x2c\x87\xf3\xbb\xcc\x7b\x2c\x82\xaf\xbf\x6c\x69\xfb\xeb\x43\xe2\xef\x0a\x25\xe3\x8d\xe0\xee\x36\xc2\x58\xc1\x4a\x25\xc1\xf3\x91\x24\xe8\xcb\xce\x8a\x08\x3f\x13\xfe\x54\xa8\x52\xdc\x59\x4f\x18\x4e\xd7\x40\xb4\x78\x7d\xd6\xbb\xb0\x1b\x44\xa1\xc2\x87\xd7\x61\xf6\xa3\x1b\xc8\x7e\x98\x5d\x99\xde\xcf\x60\xda\x1a\x27\xb5\xd5\x68\xd8\xaa\x53\xc3\xdd\xf3\x05\x03\xfc\x55\xf3\x1d\x63\x3c\xef\x78\x8f\x1c\x07\x94\xf4\x1e\x7f\xc9\xb0\x2d\x3a\x45\xaf\xd9\x48\x55\xd2\xd7\x89\x98\x9b\xce\x8f\x85\x48\x34\x86\x94\xdc\x4e\x63\x8c\x2b\x9d\xb5\xa9\xc5\xe1\xe6\xb8\x1e\xcc\xcb\x3d\xe0\x24\x8a\xd6\x8f\x47\xd2\xba\x20\xaa\xff\x41\x9d\x04\x52\x5b\x52\x5c\x93\xe3\x51\x10\x2a\xa3\x70\x89\xcc\x0d\x32\x0d\xf9\x81\x47\xa6\x01\xe2\x42\xce\x42\xd2\xa2\x94\x25\xae\xe4\x2a\x12\x2c\xea\x69\x19\xb9\x1b\xc8\x75\x64\xa5\x30\x1a\x25\x6e\x41\xea\xf7\x9f\x53\x7d\x7d\x9a\x3f\x7e\x50\xd5\xb3\x01\xde\x4f\xab\xa2\x26\xde\x85\x88\x5b\xb0\xd9\x78\x14\xeb\xc5\x3a\xd0\xd8\x6b\xe8\x03\xc2\x45\x27\x86\x6b\x10\x62\x97\x14\x4f\x54\x12\x31\x13\x16\xd9\x78\xa7\xd2\x27\x1f\x97\xe1\x30\xc3\xa8\x8c\x66\xd1\x57\x15\x7f\xb1\x86\x2e\xcd\x1b\x76\xc7\x5c\x3c\x90\xca\xc4\x91\x45\xd5\x80\x09\x2d\x84\xa7\xbe\x85\xa0\xd0\xe6\xf9\xe8\x12\x30\x3a\x38\xdc\xc6\x42\x93\xdd\x3a\x01\xfa\xc5\x72\x45\x11\x8d\x1b\x52\xbb\x01\x0f\xde\x48\x3d\xdc\x88\xd8\x27\xca\x6c\x6c\x76\x1d\xa2\x69\x82\xe1\xf8\x11\x75\x25\x24\x72\xd7\xbd\xbb\x95\x2d\xc4\xad\x53\x3d\x32\xf4\xa6\x7a\xd3\xe1\xce\x09\x9d\xed\x3a\x3b\x2a\x9f\x77\x76\x7d\xc1\x2e\x9d\x39\x03\x59\x96\xa8\xf6\x8e\xca\xab\xd8\x93\xdb\xcf\x89\xec\xd7\x44\xf6\x26\xa3\x4d\x64\x6f\xc3\x0c\x73\x2e\xfb\x1a\x9b\x80\xf0\x3b\xe6\xd7\x91\x90\x8c\x14\x29\x85\x96\xae\x89\x54\x5c\x48\xe2\xe9\x62\xfc\xaf\x98\x39\xe0\xd1\x4c\xd1\xc4\x8c\x0f\x63\xe5\x37\x1a\xd7\x8e\xa9\xb6\xa9\xdb\xbd\x9f\xbc\x65\x20\x0a\xf5\xff\x22\xb4\xc5\xfc\x07\x53\x1f\xe4\x87\x44\xf7\x0f\xe5\xbb\x88\xae\x42\x05\x00\x00")
+
+func dataStopwordsRoTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsRoTxt,
+		"data/stopwords-ro.txt",
+	)
+}
+
+func dataStopwordsRoTxt() (*asset, error) {
+	bytes, err := dataStopwordsRoTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-ro.txt", size: 1346, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x1e, 0x9a, 0x71, 0xa3, 0x78, 0x42, 0xc4, 0x6c, 0x64, 0x3d, 0xf7, 0x8, 0x55, 0x47, 0xd5, 0x6, 0xe1, 0x72, 0x9f, 0x5e, 0xa5, 0xc7, 0xf3, 0x89, 0xae, 0xb5, 0x1e, 0x81, 0x8, 0x20, 0x1d, 0xa4}}
+	return a, nil
+}
+
+var _dataStopwordsRuTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x5d\x54\x5b\x72\xa3\x40\x0c\xfc\xe7\xd4\x36\x54\x16\xa7\xe2\xac\x2b\x37\xc1\xc4\x18\xb0\x99\xf1\x15\x34\x37\x4a\x77\x4b\x63\x6f\xb6\x8a\x52\xb7\x06\xf4\xd6\x60\x43\x63\x67\x9b\x6c\x26\x64\xbb\x83\x4e\x41\xcb\xb1\x1c\xa4\x94\xce\x2e\x36\x95\x36\x68\x69\x2d\x93\x7e\x48\xd8\x3d\xc0\x86\x4a\x96\x4a\xfc\xb3\xd2\x96\x63\x63\x23\x1e\x1b\x6c\x13\x94\xbd\xc3\x21\x80\xb6\x4e\x16\x92\x4b\xd9\x21\xd0\x37\xe9\x04\x85\xd6\xf4\x05\xc1\x24\x46\x7b\x94\x1d\x68\x8f\x97\x74\x57\xf6\xcc\x52\x60\xdf\xc8\x74\x78\x29\x39\x68\x79\x13\x29\x7f\x1d\xbe\x08\x48\x9f\x5f\xc3\x54\x26\x10\x76\x75\x6d\x74\xfd\x5e\x4e\x04\xf8\x88\x7c\xd0\x94\xb5\xa9\x7e\xd5\xa7\xc9\x0f\xb6\xd2\x01\x10\x48\xb5\x83\x78\xc9\x53\x79\xd7\x47\xe5\x9d\x11\x27\x8a\x6b\xe3\x41\x66\x86\x80\xa8\x45\xcc\xea\xf0\x9e\x56\x33\xc3\x21\x19\x26\xda\x42\x76\xb6\x02\x61\x03\xd7\x0b\xe7\xb4\x78\x98\x85\x76\x8b\x25\x64\x15\x55\x2f\x2c\xf3\x86\x07\xd6\x15\x10\xe2\x54\xa9\xd2\xbf\xbd\xbe\x27\x4d\x08\xdb\xd3\x89\x54\x8e\x96\xc1\xdd\xa6\xaa\x99\xd1\x9f\x2a\x26\xfb\x9f\xba\xfe\x52\x95\x84\xef\xc8\x4d\x9b\x83\x48\x4a\xf8\x5e\xba\xd2\xfb\x4a\x6d\xa8\xfa\x8a\x3a\x3b\xa7\x89\xf1\x36\xe6\xe2\x90\xbd\xc5\x1b\xc8\xd5\x17\xcf\x69\xaa\xa7\xab\x40\x56\x9c\x63\x62\x0c\x08\xbb\x04\xf0\xbb\x14\x35\xb3\xc2\x3f\x52\xb9\x75\xc9\xb7\x2e\xd5\xad\x4b\x75\xeb\x14\x3c\xd5\xe9\x26\x9f\x6f\xf2\x09\x13\x70\x1d\x6c\x66\xc4\xe4\x19\x25\x37\x5a\xe2\x4e\x1c\x5d\xf9\xa7\xbb\xc9\x27\x94\x7c\x2c\x84\xfe\xe5\x9d\x82\xd5\x03\xb3\x9d\x29\x2e\x9c\x25\x89\x0b\x7a\xc8\x1e\x24\x63\xdf\x4f\xbe\x51\xda\xff\x07\x12\xd8\xf1\x62\x90\x66\x89\xe0\x68\xd1\xc0\x36\x63\x63\xb8\x3b\x83\x0e\xb5\x96\x93\x53\x4c\x85\x39\x3d\x29\x53\xa0\xd2\x43\x45\x24\xde\xab\x0a\xbc\x7b\x8a\xc6\x05\x68\xb4\x91\x73\x00\x5f\x35\x68\x26\x3c\xf3\x42\x03\x46\xee\x08\x3a\x15\x94\x15\x07\xad\x67\xb8\x7a\xba\x93\x67\x99\x92\xa0\x97\x22\x2b\xfa\x32\xb8\xb7\x2c\x11\xb7\x57\x8e\x1f\x7c\xc5\x26\xf3\x5d\xab\xbd\x76\xd0\x35\x0a\xaa\x28\xad\xe7\xd2\x56\xd7\xad\xbb\x20\xa8\x61\x68\x9f\x96\x52\x85\xfb\x88\x82\xc6\x59\x38\xd4\x8f\x8f\x4e\x9b\xe8\x96\xf6\xba\x05\xa8\x39\x80\xce\xb5\x8f\x06\xdd\x43\xaf\x47\xfd\x38\x06\x86\xd3\xc5\xf9\x7d\xa4\xeb\xf1\x3c\x9a\xb4\x15\x4a\xac\xc3\xbf\x80\x82\x51\xdf\xfc\x9a\x61\x11\xb3\x2b\x1c\xf6\x73\x5d\xe2\x1f\x41\xd0\xdc\x31\x86\xde\x4b\x11\x60\x7d\x2a\x61\x4e\x3d\xf3\xa3\xf9\xa7\x66\x2a\xc8\x01\xee\xce\xe9\x5a\xc9\x16\x84\x55\x7d\xb2\xbc\x06\x45\xfc\x00\xc9\x1c\x8d\x48\x17\x06\x00\x00")
+
+func dataStopwordsRuTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsRuTxt,
+		"data/stopwords-ru.txt",
+	)
+}
+
+func dataStopwordsRuTxt() (*asset, error) {
+	bytes, err := dataStopwordsRuTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-ru.txt", size: 1559, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x54, 0x1a, 0x97, 0x92, 0x70, 0x94, 0xa3, 0xeb, 0x3b, 0x2a, 0x49, 0xdb, 0xfe, 0xfc, 0x99, 0x97, 0x9, 0x45, 0xa4, 0x34, 0x1d, 0x87, 0xac, 0x74, 0xdb, 0xde, 0xdc, 0x61, 0xc0, 0xb3, 0x9e, 0x5f}}
+	return a, nil
+}
+
+var _dataStopwordsSvTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x2d\x51\xed\x8d\xec\x20\x0c\xfc\x3f\x5d\xfa\x36\x6c\xe2\x04\x9c\x15\x98\x48\xaf\x9f\x4d\x0b\xd7\x00\x8d\xbd\x31\x39\x89\xf9\x00\x0c\xc6\x46\x72\x16\x48\xce\x3e\x49\x17\x88\xd3\x5e\xf8\x91\x2a\xf8\xc9\x89\x2e\x6b\xa0\x06\x5d\xea\x58\x12\x47\x21\x2c\x60\x42\xae\xd2\xc8\xed\xa1\x58\x88\x28\x77\x3a\x5d\x09\x0b\xc4\x84\x57\x2f\x1d\xcb\xf8\x56\xd2\x8d\xf4\xf6\x54\x91\x76\xa4\x9c\xc3\x18\x82\x99\x37\x55\x07\xcf\xe3\x5d\xc7\x6d\x78\x8f\xdf\x8a\x4d\x38\x98\x7a\x13\x0b\x34\x12\x17\x79\x76\xe3\x1b\xd2\xc3\x5c\x3c\x2d\x70\x16\x6c\x9d\xdb\xe3\x9b\x9b\xa9\xad\x32\x7d\x85\x42\x5f\x47\x02\x57\x98\x4b\x23\x4c\xcd\x13\x76\x59\xb1\x77\x1c\xbc\xfa\xe8\xc6\x24\x85\xae\xa4\x85\x60\x77\xc2\x12\xac\xa4\x68\x28\x2b\x29\x51\x49\x39\x89\x6b\x43\xf9\xc7\x3b\x1d\xa6\xb0\x0e\x8b\x34\x36\xee\x95\x0f\x99\xe2\x53\x58\xd3\xf9\xda\x88\xa3\xb1\x6e\xe6\x3d\xd9\xac\x0f\x6d\x4d\x0b\x13\x34\x29\x45\xd0\x1e\xcf\x44\x4d\x43\x99\xa8\x69\x74\xb1\xed\x2c\xe4\x42\x3b\x3a\xfb\x84\xc6\xe3\x71\x0b\x31\xe3\xa7\xc8\x9f\x3a\x5c\x5e\x07\x5c\x73\x46\x94\x52\xd1\x3f\x1f\x74\xe7\x60\xec\x25\x0b\x51\x03\x12\x34\x3b\x4b\xe5\xaf\x92\xf7\x14\xdc\x82\x38\xe7\x17\x5f\xca\xc1\x13\x9a\x0f\x79\xb8\x4d\x61\x3b\xa6\x30\x6a\x7c\x2d\xeb\xca\xdd\x71\xd7\x49\x8f\x75\x70\x03\xd1\x8b\x71\xd3\xff\x5e\x7c\xc8\x7f\xc1\x47\xd9\xb8\x6a\x02\x00\x00")
+
+func dataStopwordsSvTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsSvTxt,
+		"data/stopwords-sv.txt",
+	)
+}
+
+func dataStopwordsSvTxt() (*asset, error) {
+	bytes, err := dataStopwordsSvTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-sv.txt", size: 618, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x7a, 0x68, 0x53, 0xa6, 0x8, 0x1e, 0x49, 0xa4, 0x77, 0xfb, 0x91, 0xc0, 0x3a, 0xec, 0x6e, 0xa6, 0xaf, 0x91, 0x5a, 0x8e, 0xd6, 0x1, 0xbe, 0x8d, 0xbc, 0x37, 0x22, 0x93, 0xbd, 0xa3, 0x7f, 0xd3}}
+	return a, nil
+}
+
+var _dataStopwordsTrTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x4d\x51\xbb\x8e\x84\x30\x0c\xec\xfd\x97\xe6\xe2\x05\x2b\x89\x39\x25\xb0\x12\xfc\x0c\x25\xf5\x36\x57\xd1\x85\xfd\xaf\x1b\x47\x7b\xd2\x49\x9e\xb1\x4d\x2c\x3f\x06\xfe\xe2\x81\x89\x33\x50\xd3\xfd\xb2\x80\x60\xa7\x81\x8d\x41\xfb\xfd\xa2\x41\x52\x54\xb0\x39\x34\xd3\xa0\xc5\xa1\x4e\x91\xdb\xe9\xfe\x7d\xc8\x06\xbf\x3b\xc4\xc9\xeb\x56\x98\x25\x2e\xee\x56\x42\xdf\xc0\x13\x48\x60\x0f\xf7\xf7\xa1\x89\x82\x6e\x42\xe8\x8d\x4c\x0a\x8d\x3a\x28\x8d\xed\xa7\x08\x4d\x92\x81\x6f\x47\x55\x70\xa1\x49\x31\x4c\xb1\x8b\x26\x21\xad\x40\x3b\xd5\x28\x72\xc0\x8c\x28\x3b\xe1\x29\x62\x70\x6a\xd7\xf2\x40\xcb\x2c\x65\xf2\xdb\xf2\x4a\xb9\x5d\x94\x71\x8b\x71\xbd\x5f\x89\x4c\x60\x01\x25\x26\x25\x78\x5c\xe4\xe3\xb0\x8c\x69\xa7\xde\x7b\xa6\x39\xb1\x39\x15\x8e\x70\x61\xbd\x8f\xf5\xcf\xe3\xa8\x19\x2a\xcd\xfd\xc6\xce\x9f\x04\x2a\x22\xf0\xd7\xd5\xa8\xba\x92\x20\x2c\x57\x79\x1b\x31\xde\x0b\x72\xf2\x8f\x9b\x57\x56\x49\x9c\xbd\x45\x15\x4f\x0c\x63\x2b\x94\xac\xae\x24\xc8\xd3\xd9\xd0\x7a\x11\xa8\x1c\x63\xbb\xca\xbf\x30\x41\x97\xa5\x5d\x99\x9e\x68\xf0\x14\xd8\xc6\xd4\xcd\x94\xb6\x39\x52\x3b\x3b\xb5\xcb\x50\x4e\xed\x82\x84\xfd\x67\xbd\x71\x07\xd0\x77\x7f\xf7\x5b\x7e\x01\x45\x7f\x51\xb0\x09\x02\x00\x00")
+
+func dataStopwordsTrTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsTrTxt,
+		"data/stopwords-tr.txt",
+	)
+}
+
+func dataStopwordsTrTxt() (*asset, error) {
+	bytes, err := dataStopwordsTrTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-tr.txt", size: 521, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x3c, 0x5, 0xb7, 0x11, 0xa5, 0x6c, 0xad, 0x39, 0xe, 0x73, 0xf3, 0x1d, 0xbf, 0xbd, 0x9d, 0x81, 0x5d, 0xe7, 0x13, 0xe7, 0x1f, 0xb0, 0xe, 0x73, 0x7d, 0x55, 0x15, 0xfe, 0x4d, 0xcf, 0xba, 0xf1}}
+	return a, nil
+}
+
+var _dataStopwordsUkTxt = []byte("\x1f\x8b\x08\x00\x00\x00\x00\x00\x00\xff\x55\x92\x51\x4e\xc3\x40\x0c\x44\xff\xf7\xd4\x14\x54\xa0\x12\xa2\x3f\x55\x7b\x8d\x34\x64\x69\x68\x93\xf4\x0a\xf6\x8d\x98\x67\x67\x8b\x90\x22\xcf\xec\xc6\xf6\xda\x63\x5b\x57\xac\xb3\xb3\x8d\x09\x0b\x30\xd8\xb7\x55\xc8\x0d\x38\xeb\xb3\x6a\x97\x92\x5e\x67\x7f\xb1\x3e\x61\xf0\x8f\x24\x37\xeb\x1a\x19\x1b\x59\x82\xf8\x33\x37\xf2\xef\x95\x6e\x0a\xf0\x4d\xc2\xfb\x0a\xc4\x26\xa9\x2b\xf1\x23\xa4\xfa\x86\xfc\x7d\x16\xd3\x47\x1e\x5b\x6c\x4e\x7f\xc8\xe3\x46\x6f\xf5\xbe\x09\x37\x82\x74\xf7\xb5\xde\x45\x2a\x3f\xda\x90\x30\x07\xa8\xa8\xce\x3f\x8b\xfa\xac\x98\x9b\xef\x01\x05\x0c\xbe\xb7\xab\x8a\xd6\x3f\xb5\x7b\xe1\x25\xbd\xf1\xa3\xaf\xa5\xbc\x8a\xa8\x1e\x12\x41\xa3\x5f\x11\x7f\xf6\x27\xbc\x1b\xad\x7f\x74\x24\x7e\x3d\x50\xcd\x0d\x05\x74\x21\xef\x89\x44\xf8\x06\xe1\xe7\x44\xbe\x89\x8e\xa8\x61\x12\xa8\xb4\x49\x85\x2b\x47\x34\x2e\x93\x1d\x20\x0d\x87\x21\x61\x0a\x40\xda\x39\xa5\x9d\x9b\xb4\x73\x93\x76\x6e\xd2\xce\x79\xaa\xca\xdf\x3d\x88\x1f\x82\xf2\x2c\x70\x02\xc6\x4c\x3b\xfa\x56\xe0\x2f\x25\x6b\x94\xb1\xef\x82\x18\x0b\x32\xa8\xb1\x62\x77\x8e\x32\x31\xe6\xaf\xac\xfe\x1e\xcd\x47\xb1\x79\xc0\x25\x47\x21\x90\x06\xe8\x8e\x6c\xd2\x5a\xa7\x3e\xa2\x20\xd1\x2e\xe3\x64\xef\x20\x8b\x36\xe9\x58\x98\x5a\x18\xbb\xae\xc0\x2c\x92\x4e\x40\x0d\x93\x41\x90\xf8\x37\x62\x96\x30\x2d\x4b\x8e\x32\xc8\xd0\x6e\xf4\x60\xcc\x4d\xd1\x3c\xc2\xe2\x16\x75\x2c\x64\x85\x7c\xab\xe2\xde\xf8\xf3\x4a\x72\x19\x02\x5e\x1f\x9b\x26\xba\xc7\x1c\xc3\xf8\x41\xf2\xc9\x7d\xc4\xac\x0e\x1a\x05\x53\x55\x97\x8c\xf1\xdf\x91\x05\xf1\x1d\x79\x77\x78\xee\x28\xb4\xb0\x88\x61\xf0\x06\x6a\x42\x38\xb3\xa4\x7a\xea\x50\xa4\x94\xb4\x3b\xe9\xb9\x5f\xbf\x00\x6e\x9d\xc7\x03\x00\x00")
+
+func dataStopwordsUkTxtBytes() ([]byte, error) {
+	return bindataRead(
+		_dataStopwordsUkTxt,
+		"data/stopwords-uk.txt",
+	)
+}
+
+func dataStopwordsUkTxt() (*asset, error) {
+	bytes, err := dataStopwordsUkTxtBytes()
+	if err != nil {
+		return nil, err
+	}
+
+	info := bindataFileInfo{name: "data/stopwords-uk.txt", size: 967, mode: os.FileMode(420), modTime: time.Unix(1792352091, 0)}
+	a := &asset{bytes: bytes, info: info, digest: [32]uint8{0x49, 0x88, 0x5b, 0xbc, 0xa9, 0x5e, 0xd5, 0x75, 0x4e, 0xf, 0x9f, 0x8f, 0xd9, 0xbd, 0x5b, 0x2b, 0x80, 0xd2, 0x1, 0x7a, 0xf3, 0xc0, 0x50, 0xf6, 0x74, 0xd5, 0x47, 0x2d, 0x25, 0xb1, 0x5a, 0xce}}
+	return a, nil
+}
+
 // Asset loads and returns the asset for the given name.
 // It returns an error if the asset could not be found or
 // could not be loaded.
@@ -201,9 +558,43 @@ func AssetNames() []string {
 
 // _bindata is a table, holding each asset generator, mapped to its name.
 var _bindata = map[string]func() (*asset, error){
+	"data/stopwords-cs.txt": dataStopwordsCsTxt,
+
+	"data/stopwords-da.txt": dataStopwordsDaTxt,
+
+	"data/stopwords-de.txt": dataStopwordsDeTxt,
+
 	"data/stopwords-en.txt": dataStopwordsEnTxt,
 
+	"data/stopwords-es.txt": dataStopwordsEsTxt,
+
+	"data/stopwords-fi.txt": dataStopwordsFiTxt,
+
 	"data/stopwords-fr.txt": dataStopwordsFrTxt,
+
+	"data/stopwords-hu.txt": dataStopwordsHuTxt,
+
+	"data/stopwords-id.txt": dataStopwordsIdTxt,
+
+	"data/stopwords-it.txt": dataStopwordsItTxt,
+
+	"data/stopwords-nl.txt": dataStopwordsNlTxt,
+
+	"data/stopwords-no.txt": dataStopwordsNoTxt,
+
+	"data/stopwords-pl.txt": dataStopwordsPlTxt,
+
+	"data/stopwords-pt.txt": dataStopwordsPtTxt,
+
+	"data/stopwords-ro.txt": dataStopwordsRoTxt,
+
+	"data/stopwords-ru.txt": dataStopwordsRuTxt,
+
+	"data/stopwords-sv.txt": dataStopwordsSvTxt,
+
+	"data/stopwords-tr.txt": dataStopwordsTrTxt,
+
+	"data/stopwords-uk.txt": dataStopwordsUkTxt,
 }
 
 // AssetDir returns the file names below a certain
@@ -248,8 +639,25 @@ type bintree struct {
 
 var _bintree = &bintree{nil, map[string]*bintree{
 	"data": &bintree{nil, map[string]*bintree{
+		"stopwords-cs.txt": &bintree{dataStopwordsCsTxt, map[string]*bintree{}},
+		"stopwords-da.txt": &bintree{dataStopwordsDaTxt, map[string]*bintree{}},
+		"stopwords-de.txt": &bintree{dataStopwordsDeTxt, map[string]*bintree{}},
 		"stopwords-en.txt": &bintree{dataStopwordsEnTxt, map[string]*bintree{}},
+		"stopwords-es.txt": &bintree{dataStopwordsEsTxt, map[string]*bintree{}},
+		"stopwords-fi.txt": &bintree{dataStopwordsFiTxt, map[string]*bintree{}},
 		"stopwords-fr.txt": &bintree{dataStopwordsFrTxt, map[string]*bintree{}},
+		"stopwords-hu.txt": &bintree{dataStopwordsHuTxt, map[string]*bintree{}},
+		"stopwords-id.txt": &bintree{dataStopwordsIdTxt, map[string]*bintree{}},
+		"stopwords-it.txt": &bintree{dataStopwordsItTxt, map[string]*bintree{}},
+		"stopwords-nl.txt": &bintree{dataStopwordsNlTxt, map[string]*bintree{}},
+		"stopwords-no.txt": &bintree{dataStopwordsNoTxt, map[string]*bintree{}},
+		"stopwords-pl.txt": &bintree{dataStopwordsPlTxt, map[string]*bintree{}},
+		"stopwords-pt.txt": &bintree{dataStopwordsPtTxt, map[string]*bintree{}},
+		"stopwords-ro.txt": &bintree{dataStopwordsRoTxt, map[string]*bintree{}},
+		"stopwords-ru.txt": &bintree{dataStopwordsRuTxt, map[string]*bintree{}},
+		"stopwords-sv.txt": &bintree{dataStopwordsSvTxt, map[string]*bintree{}},
+		"stopwords-tr.txt": &bintree{dataStopwordsTrTxt, map[string]*bintree{}},
+		"stopwords-uk.txt": &bintree{dataStopwordsUkTxt, map[string]*bintree{}},
 	}},
 }}
 
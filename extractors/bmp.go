@@ -0,0 +1,175 @@
+package extractors
+
+import (
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+)
+
+var errBMPFormat = errors.New("unsupported BMP format")
+
+func init() {
+	image.RegisterFormat("bmp", "BM", decodeBMP, decodeBMPConfig)
+}
+
+type bmpHeader struct {
+	offset      int
+	width       int
+	height      int
+	topDown     bool
+	bitCount    int
+	compression uint32
+	colors      int
+	headerSize  int
+	masks       [3]uint32
+}
+
+func readBMPHeader(b []byte) (*bmpHeader, error) {
+	if len(b) < 26 || b[0] != 'B' || b[1] != 'M' {
+		return nil, errBMPFormat
+	}
+	h := &bmpHeader{
+		offset:     int(binary.LittleEndian.Uint32(b[10:14])),
+		headerSize: int(binary.LittleEndian.Uint32(b[14:18])),
+	}
+	if h.headerSize == 12 {
+		// OS/2 BITMAPCOREHEADER
+		h.width = int(binary.LittleEndian.Uint16(b[18:20]))
+		h.height = int(binary.LittleEndian.Uint16(b[20:22]))
+		h.bitCount = int(binary.LittleEndian.Uint16(b[24:26]))
+		return h, nil
+	}
+	if h.headerSize < 40 || len(b) < 14+40 {
+		return nil, errBMPFormat
+	}
+	h.width = int(int32(binary.LittleEndian.Uint32(b[18:22])))
+	height := int(int32(binary.LittleEndian.Uint32(b[22:26])))
+	if height < 0 {
+		h.topDown = true
+		height = -height
+	}
+	h.height = height
+	h.bitCount = int(binary.LittleEndian.Uint16(b[28:30]))
+	h.compression = binary.LittleEndian.Uint32(b[30:34])
+	h.colors = int(binary.LittleEndian.Uint32(b[46:50]))
+	if h.compression == 3 {
+		// BI_BITFIELDS: the masks follow the header (or are part of it in V4/V5 headers)
+		if len(b) < 14+40+12 {
+			return nil, errBMPFormat
+		}
+		for i := range h.masks {
+			h.masks[i] = binary.LittleEndian.Uint32(b[54+4*i:])
+		}
+	} else if h.compression != 0 {
+		return nil, errBMPFormat
+	}
+	if h.width <= 0 || h.height <= 0 || h.width > 1<<15 || h.height > 1<<15 {
+		return nil, errBMPFormat
+	}
+	return h, nil
+}
+
+func decodeBMPConfig(r io.Reader) (image.Config, error) {
+	b := make([]byte, 14+40+12)
+	n, err := io.ReadFull(r, b)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return image.Config{}, err
+	}
+	h, err := readBMPHeader(b[:n])
+	if err != nil {
+		return image.Config{}, err
+	}
+	return image.Config{ColorModel: color.RGBAModel, Width: h.width, Height: h.height}, nil
+}
+
+// decodeBMP decodes the uncompressed BMP images (1, 4, 8, 16, 24 and 32 bits per pixel).
+func decodeBMP(r io.Reader) (image.Image, error) {
+	b, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	h, err := readBMPHeader(b)
+	if err != nil {
+		return nil, err
+	}
+	var palette []color.RGBA
+	if h.bitCount <= 8 {
+		entrySize := 4
+		if h.headerSize == 12 {
+			entrySize = 3
+		}
+		count := h.colors
+		if count == 0 || count > 1<<uint(h.bitCount) {
+			count = 1 << uint(h.bitCount)
+		}
+		start := 14 + h.headerSize
+		for i := 0; i < count && start+entrySize*(i+1) <= len(b); i++ {
+			p := b[start+entrySize*i:]
+			palette = append(palette, color.RGBA{R: p[2], G: p[1], B: p[0], A: 0xff})
+		}
+		if len(palette) == 0 {
+			return nil, errBMPFormat
+		}
+	}
+	stride := ((h.width*h.bitCount + 31) / 32) * 4
+	if h.offset < 0 || h.offset+stride*h.height > len(b) {
+		return nil, errBMPFormat
+	}
+	img := image.NewRGBA(image.Rect(0, 0, h.width, h.height))
+	for row := 0; row < h.height; row++ {
+		y := h.height - 1 - row
+		if h.topDown {
+			y = row
+		}
+		line := b[h.offset+row*stride : h.offset+(row+1)*stride]
+		for x := 0; x < h.width; x++ {
+			var c color.RGBA
+			switch h.bitCount {
+			case 1, 4, 8:
+				bitPos := x * h.bitCount
+				idx := int(line[bitPos/8]>>uint(8-h.bitCount-bitPos%8)) & (1<<uint(h.bitCount) - 1)
+				if idx < len(palette) {
+					c = palette[idx]
+				}
+			case 16:
+				v := uint32(binary.LittleEndian.Uint16(line[2*x:]))
+				if h.compression == 3 {
+					c = bitfieldsColor(v, h.masks)
+				} else {
+					c = color.RGBA{R: uint8((v >> 10 & 0x1f) << 3), G: uint8((v >> 5 & 0x1f) << 3), B: uint8((v & 0x1f) << 3), A: 0xff}
+				}
+			case 24:
+				c = color.RGBA{R: line[3*x+2], G: line[3*x+1], B: line[3*x], A: 0xff}
+			case 32:
+				if h.compression == 3 {
+					c = bitfieldsColor(binary.LittleEndian.Uint32(line[4*x:]), h.masks)
+				} else {
+					c = color.RGBA{R: line[4*x+2], G: line[4*x+1], B: line[4*x], A: 0xff}
+				}
+			default:
+				return nil, errBMPFormat
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img, nil
+}
+
+func bitfieldsColor(v uint32, masks [3]uint32) color.RGBA {
+	var channels [3]uint8
+	for i, mask := range masks {
+		if mask == 0 {
+			continue
+		}
+		shift := uint(0)
+		for mask>>shift&1 == 0 {
+			shift++
+		}
+		max := mask >> shift
+		channels[i] = uint8(uint64((v&mask)>>shift) * 255 / uint64(max))
+	}
+	return color.RGBA{R: channels[0], G: channels[1], B: channels[2], A: 0xff}
+}
@@ -0,0 +1,203 @@
+package extractors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+// Categories of mails.
+const (
+	CategoryBounce        = "bounce"
+	CategoryReport        = "report"
+	CategoryAutoReply     = "auto-reply"
+	CategoryList          = "list"
+	CategoryBulk          = "bulk"
+	CategoryTransactional = "transactional"
+	CategoryPersonal      = "personal"
+)
+
+type esp struct {
+	name string
+	// headers that only this ESP adds
+	headers []string
+	// domains of the Return-Path and DKIM signatures
+	domains []string
+}
+
+var esps = []esp{
+	{name: "Mailchimp", headers: []string{"x-mc-user", "x-mailchimp-campaign"}, domains: []string{"mcsv.net", "mcdlv.net", "rsgsv.net", "mailchimpapp.net"}},
+	{name: "Mandrill", headers: []string{"x-mandrill-user"}, domains: []string{"mandrillapp.com"}},
+	{name: "SendGrid", headers: []string{"x-sg-eid", "x-sg-id"}, domains: []string{"sendgrid.net", "sendgrid.com"}},
+	{name: "Amazon SES", headers: []string{"x-ses-outgoing"}, domains: []string{"amazonses.com"}},
+	{name: "Mailgun", headers: []string{"x-mailgun-sid", "x-mailgun-variables"}, domains: []string{"mailgun.org", "mailgun.net"}},
+	{name: "Postmark", headers: []string{"x-pm-message-id"}, domains: []string{"mtasv.net", "postmarkapp.com"}},
+	{name: "SparkPost", headers: []string{"x-msfbl"}, domains: []string{"sparkpostmail.com", "sparkpost.com"}},
+	{name: "Brevo", headers: []string{"x-sib-id", "x-mailin-campaign"}, domains: []string{"sendinblue.com", "brevo.com"}},
+	{name: "Mailjet", headers: []string{"x-mj-mid", "x-mailjet-campaign"}, domains: []string{"mailjet.com"}},
+	{name: "Constant Contact", headers: []string{"x-roving-id"}, domains: []string{"constantcontact.com", "roving.com"}},
+	{name: "Campaign Monitor", headers: []string{"x-cmae-score"}, domains: []string{"createsend.com", "cmail1.com", "cmail2.com", "cmail19.com", "cmail20.com"}},
+	{name: "Salesforce Marketing Cloud", headers: []string{"x-sfmc-stack"}, domains: []string{"exacttarget.com", "exct.net"}},
+	{name: "HubSpot", headers: []string{"x-hs-cid"}, domains: []string{"hubspotemail.net", "hs-email.net"}},
+	{name: "Klaviyo", headers: []string{"x-kmail-account"}, domains: []string{"klaviyomail.com", "klaviyodns.com"}},
+}
+
+var dkimDomainRE = regexp.MustCompile(`(?:^|;)\s*d\s*=\s*([^;\s]+)`)
+
+var bounceSubjectRE = regexp.MustCompile(`(?i)(undeliver|delivery status notification|returned mail|failure notice|mail delivery failed|delivery failure|non remis|unzustellbar)`)
+var bounceSenderRE = regexp.MustCompile(`(?i)^(mailer-daemon|postmaster)@`)
+
+var autoReplySubjectRE = regexp.MustCompile(`(?i)^(automatic reply|auto-reply|autoreply|out of (the )?office|r[ée]ponse automatique|absence|abwesenheitsnotiz|automatische antwort|autosvar|respuesta autom[áa]tica|risposta automatica)\b`)
+var outOfOfficeRE = regexp.MustCompile(`(?i)(i am (currently )?out of (the )?office|i('m| am) (currently )?away|limited access to (my )?e-?mail|je suis (actuellement )?absente?|absente? du bureau|ich bin (derzeit )?(nicht im büro|abwesend)|estoy fuera de la oficina)`)
+
+var noReplyRE = regexp.MustCompile(`(?i)^(no-?reply|do-?not-?reply|donotreply|notifications?|alerts?|mailer|system)[^@]*@`)
+
+// Categorize classifies a mail as bounce, report, auto-reply, list, bulk, transactional or
+// personal, and detects the ESP that sent it. It returns the signals that led to the decision.
+// headers must have lower case keys.
+func Categorize(headers map[string][]string, from string, subject string, body string, report *models.Report) (category string, espName string, evidence []string) {
+	get := func(key string) string {
+		if len(headers[key]) == 0 {
+			return ""
+		}
+		return strings.TrimSpace(headers[key][0])
+	}
+
+	espName, espEvidence := detectESP(headers)
+	evidence = append(evidence, espEvidence...)
+
+	if report != nil {
+		switch report.ReportType {
+		case "delivery-status":
+			return CategoryBounce, espName, append(evidence, "multipart/report; report-type=delivery-status")
+		case "feedback-report":
+			return CategoryReport, espName, append(evidence, "multipart/report; report-type=feedback-report")
+		}
+	}
+	returnPath := get("return-path")
+	if returnPath == "<>" && (bounceSenderRE.MatchString(from) || bounceSubjectRE.MatchString(subject)) {
+		return CategoryBounce, espName, append(evidence, "null Return-Path")
+	}
+	if bounceSenderRE.MatchString(from) && bounceSubjectRE.MatchString(subject) {
+		return CategoryBounce, espName, append(evidence, "From: "+from, "Subject: "+subject)
+	}
+
+	autoSubmitted := strings.ToLower(get("auto-submitted"))
+	precedence := strings.ToLower(get("precedence"))
+	var autoReply []string
+	if strings.HasPrefix(autoSubmitted, "auto-replied") {
+		autoReply = append(autoReply, "Auto-Submitted: "+autoSubmitted)
+	}
+	for _, h := range []string{"x-autoreply", "x-autorespond", "x-autoresponder"} {
+		if get(h) != "" {
+			autoReply = append(autoReply, h+" header")
+		}
+	}
+	if precedence == "auto_reply" {
+		autoReply = append(autoReply, "Precedence: "+precedence)
+	}
+	if autoReplySubjectRE.MatchString(subject) {
+		autoReply = append(autoReply, "Subject: "+subject)
+	}
+	if m := outOfOfficeRE.FindString(body); m != "" {
+		autoReply = append(autoReply, fmt.Sprintf("out of office phrase %q", m))
+	}
+	// a phrase in the body alone is not enough: it may be quoted
+	if len(autoReply) > 1 || (len(autoReply) == 1 && !strings.HasPrefix(autoReply[0], "out of office")) {
+		return CategoryAutoReply, espName, append(evidence, autoReply...)
+	}
+
+	listID := get("list-id")
+	if listID != "" && (get("list-post") != "" || get("x-mailman-version") != "" || get("mailing-list") != "") {
+		evidence = append(evidence, "List-Id: "+listID)
+		if get("list-post") != "" {
+			evidence = append(evidence, "List-Post header")
+		}
+		return CategoryList, espName, evidence
+	}
+	if precedence == "list" && espName == "" {
+		return CategoryList, espName, append(evidence, "Precedence: list")
+	}
+
+	var bulk []string
+	if precedence == "bulk" || precedence == "junk" {
+		bulk = append(bulk, "Precedence: "+precedence)
+	}
+	if unsubscribe := get("list-unsubscribe"); unsubscribe != "" {
+		bulk = append(bulk, "List-Unsubscribe header")
+		if strings.Contains(strings.ToLower(get("list-unsubscribe-post")), "list-unsubscribe=one-click") {
+			bulk = append(bulk, "one-click unsubscription (RFC 8058)")
+		}
+	}
+	if listID != "" {
+		bulk = append(bulk, "List-Id: "+listID)
+	}
+	for _, h := range []string{"x-campaign", "x-campaignid", "x-mailchimp-campaign", "x-mailjet-campaign", "x-mailin-campaign"} {
+		if get(h) != "" {
+			bulk = append(bulk, h+" header")
+		}
+	}
+	if len(bulk) > 0 {
+		return CategoryBulk, espName, append(evidence, bulk...)
+	}
+
+	var transactional []string
+	if strings.HasPrefix(autoSubmitted, "auto-generated") {
+		transactional = append(transactional, "Auto-Submitted: "+autoSubmitted)
+	}
+	if feedbackID := get("feedback-id"); feedbackID != "" {
+		transactional = append(transactional, "Feedback-ID: "+feedbackID)
+	}
+	if noReplyRE.MatchString(from) {
+		transactional = append(transactional, "From: "+from)
+	}
+	if espName != "" {
+		transactional = append(transactional, "sent by "+espName+" without unsubscription")
+	}
+	if len(transactional) > 0 {
+		return CategoryTransactional, espName, append(evidence, transactional...)
+	}
+	return CategoryPersonal, espName, evidence
+}
+
+// detectESP finds the email service provider from its specific headers, the Return-Path and the
+// domains of the DKIM signatures.
+func detectESP(headers map[string][]string) (string, []string) {
+	var domains []string
+	var sources []string
+	for _, rp := range headers["return-path"] {
+		if d := utils.DomainFromAddress(strings.Trim(strings.TrimSpace(rp), "<>")); d != "" {
+			domains = append(domains, d)
+			sources = append(sources, "Return-Path")
+		}
+	}
+	for _, sig := range headers["dkim-signature"] {
+		if m := dkimDomainRE.FindStringSubmatch(sig); m != nil {
+			domains = append(domains, strings.ToLower(m[1]))
+			sources = append(sources, "DKIM d=")
+		}
+	}
+	for _, e := range esps {
+		for _, h := range e.headers {
+			if len(headers[h]) > 0 {
+				return e.name, []string{fmt.Sprintf("%s: %s header", e.name, h)}
+			}
+		}
+		for i, d := range domains {
+			for _, espDomain := range e.domains {
+				if d == espDomain || strings.HasSuffix(d, "."+espDomain) {
+					return e.name, []string{fmt.Sprintf("%s: %s %s", e.name, sources[i], d)}
+				}
+			}
+		}
+	}
+	for _, feedbackID := range headers["feedback-id"] {
+		if strings.Contains(strings.ToLower(feedbackID), "amazonses") {
+			return "Amazon SES", []string{"Amazon SES: Feedback-ID " + strings.TrimSpace(feedbackID)}
+		}
+	}
+	return "", nil
+}
@@ -0,0 +1,97 @@
+package extractors
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestCategorize(t *testing.T) {
+	type h = map[string][]string
+	cases := []struct {
+		name     string
+		headers  h
+		from     string
+		subject  string
+		body     string
+		report   *models.Report
+		category string
+		esp      string
+	}{
+		// bounces and reports come first
+		{"DSN", h{"auto-submitted": {"auto-replied"}, "list-id": {"<l.example.org>"}, "list-post": {"<mailto:l@example.org>"}},
+			"MAILER-DAEMON@example.org", "Returned mail", "", &models.Report{ReportType: "delivery-status"}, CategoryBounce, ""},
+		{"ARF", h{"precedence": {"bulk"}}, "abuse@example.net", "FW: spam", "", &models.Report{ReportType: "feedback-report"}, CategoryReport, ""},
+		{"other report", h{}, "alice@example.org", "Read: hello", "", &models.Report{ReportType: "disposition-notification"}, CategoryPersonal, ""},
+		{"null Return-Path", h{"return-path": {"<>"}, "auto-submitted": {"auto-replied"}, "list-id": {"<l.example.org>"}},
+			"postmaster@example.org", "Hello", "", nil, CategoryBounce, ""},
+		{"null Return-Path with a bounce subject", h{"return-path": {"<>"}}, "alice@example.org", "Undeliverable: invoice", "", nil, CategoryBounce, ""},
+		{"bounce sender and subject", h{}, "mailer-daemon@mx.example.org", "Mail delivery failed: returning message to sender", "", nil, CategoryBounce, ""},
+		{"bounce sender only", h{}, "postmaster@example.org", "Your mailbox", "", nil, CategoryPersonal, ""},
+
+		// auto-replies come before the lists
+		{"Auto-Submitted auto-replied on a list", h{"auto-submitted": {"auto-replied"}, "list-id": {"<users.lists.example.org>"},
+			"list-post": {"<mailto:users@lists.example.org>"}}, "bob@example.org", "Re: meeting", "", nil, CategoryAutoReply, ""},
+		{"X-Autoreply", h{"x-autoreply": {"yes"}}, "bob@example.org", "Re: meeting", "", nil, CategoryAutoReply, ""},
+		{"Precedence auto_reply", h{"precedence": {"auto_reply"}}, "bob@example.org", "Re: meeting", "", nil, CategoryAutoReply, ""},
+		{"out of office subject", h{}, "bob@example.org", "Out of Office: meeting", "", nil, CategoryAutoReply, ""},
+		{"French subject", h{}, "bob@example.fr", "Réponse automatique : réunion", "", nil, CategoryAutoReply, ""},
+		{"out of office phrase only", h{}, "bob@example.org", "Re: meeting", "> I am currently out of the office", nil, CategoryPersonal, ""},
+		{"out of office phrase and header", h{"x-autorespond": {"1"}}, "bob@example.org", "Re: meeting", "I am out of office until Monday", nil, CategoryAutoReply, ""},
+
+		// lists come before bulk mails
+		{"mailing list", h{"list-id": {"<users.lists.example.org>"}, "list-post": {"<mailto:users@lists.example.org>"},
+			"list-unsubscribe": {"<mailto:leave@lists.example.org>"}, "precedence": {"bulk"}}, "carol@example.org", "[users] question", "", nil, CategoryList, ""},
+		{"Mailman", h{"list-id": {"<dev.example.org>"}, "x-mailman-version": {"2.1.29"}}, "carol@example.org", "[dev] patch", "", nil, CategoryList, ""},
+		{"Precedence list", h{"precedence": {"list"}}, "carol@example.org", "digest", "", nil, CategoryList, ""},
+		{"Precedence list by an ESP", h{"precedence": {"list"}, "x-mc-user": {"abc"}, "list-unsubscribe": {"<https://example.us1.list-manage.com/unsubscribe>"}},
+			"news@shop.example.com", "Sales", "", nil, CategoryBulk, "Mailchimp"},
+		{"List-Id only", h{"list-id": {"<news.example.com>"}}, "news@example.com", "News", "", nil, CategoryBulk, ""},
+		{"Precedence bulk", h{"precedence": {"bulk"}}, "news@example.com", "News", "", nil, CategoryBulk, ""},
+		{"campaign header", h{"x-campaign": {"2024-spring"}}, "news@example.com", "Spring", "", nil, CategoryBulk, ""},
+
+		// transactional mails
+		{"Auto-Submitted auto-generated", h{"auto-submitted": {"auto-generated"}}, "billing@example.com", "Your invoice", "", nil, CategoryTransactional, ""},
+		{"no-reply sender", h{}, "no-reply@accounts.example.com", "Security alert", "", nil, CategoryTransactional, ""},
+		{"ESP by DKIM", h{"dkim-signature": {"v=1; a=rsa-sha256; d=sendgrid.net; s=smtpapi; h=from:to"}},
+			"orders@shop.example.com", "Your order", "", nil, CategoryTransactional, "SendGrid"},
+		{"ESP by Return-Path", h{"return-path": {"<bounce-mc.us5_123.456-alice=example.org@mail123.sea31.mcsv.net>"}},
+			"orders@shop.example.com", "Your order", "", nil, CategoryTransactional, "Mailchimp"},
+		{"ESP by Feedback-ID", h{"feedback-id": {"1.us-east-1.abc=:AmazonSES"}},
+			"orders@shop.example.com", "Your order", "", nil, CategoryTransactional, "Amazon SES"},
+		{"personal", h{"return-path": {"<alice@example.org>"}}, "alice@example.org", "Lunch?", "See you at noon", nil, CategoryPersonal, ""},
+	}
+	for _, c := range cases {
+		category, esp, evidence := Categorize(c.headers, c.from, c.subject, c.body, c.report)
+		if category != c.category || esp != c.esp {
+			t.Errorf("%s: expected %s %q, got %s %q (%q)", c.name, c.category, c.esp, category, esp, evidence)
+		}
+		if category != CategoryPersonal && len(evidence) == 0 {
+			t.Errorf("%s: no evidence", c.name)
+		}
+	}
+}
+
+func TestDetectESP(t *testing.T) {
+	cases := []struct {
+		headers  map[string][]string
+		esp      string
+		evidence []string
+	}{
+		{map[string][]string{"x-mailgun-sid": {"abc"}}, "Mailgun", []string{"Mailgun: x-mailgun-sid header"}},
+		{map[string][]string{"dkim-signature": {"v=1; d=example.com; s=s1", "v=1; d=Mail.Klaviyomail.com; s=kl"}},
+			"Klaviyo", []string{"Klaviyo: DKIM d= mail.klaviyomail.com"}},
+		{map[string][]string{"return-path": {"<b@bounces.sparkpostmail.com>"}}, "SparkPost", []string{"SparkPost: Return-Path bounces.sparkpostmail.com"}},
+		// a domain that only ends like the ESP domain
+		{map[string][]string{"return-path": {"<b@notsendgrid.net>"}}, "", nil},
+		{map[string][]string{"dkim-signature": {"v=1; d=example.com"}}, "", nil},
+		{map[string][]string{}, "", nil},
+	}
+	for _, c := range cases {
+		esp, evidence := detectESP(c.headers)
+		if esp != c.esp || !reflect.DeepEqual(evidence, c.evidence) {
+			t.Errorf("%v: expected %q %q, got %q %q", c.headers, c.esp, c.evidence, esp, evidence)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package extractors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"strings"
+)
+
+// Compiled HTML Help files are ITSF containers. The directory of the container is a list of
+// PMGL chunks that give the names of the internal files. The content of the files is mostly
+// LZX compressed, so only the names are reported.
+
+// maxCHMFiles limits the number of names read from a CHM directory.
+const maxCHMFiles = 10000
+
+var ErrNotCHM = errors.New("not a CHM file")
+
+// CHMFiles lists the files stored in a Compiled HTML Help file. The internal files of the
+// container (system files, and the names that start with '#' or '$') are not listed.
+func CHMFiles(content []byte) ([]string, error) {
+	if len(content) < 0x58 || !bytes.HasPrefix(content, []byte("ITSF")) {
+		return nil, ErrNotCHM
+	}
+	dirOffset := binary.LittleEndian.Uint64(content[0x48:])
+	dirLength := binary.LittleEndian.Uint64(content[0x50:])
+	if dirOffset >= uint64(len(content)) || dirLength > uint64(len(content))-dirOffset {
+		return nil, errors.New("invalid CHM directory offset")
+	}
+	dir := content[dirOffset : dirOffset+dirLength]
+	if len(dir) < 0x54 || !bytes.HasPrefix(dir, []byte("ITSP")) {
+		return nil, errors.New("invalid CHM directory header")
+	}
+	headerLength := int(binary.LittleEndian.Uint32(dir[0x08:]))
+	chunkSize := int(binary.LittleEndian.Uint32(dir[0x10:]))
+	if chunkSize < 0x14 || headerLength < 0 || headerLength > len(dir) {
+		return nil, errors.New("invalid CHM directory header")
+	}
+	var names []string
+	for chunk := dir[headerLength:]; len(chunk) >= chunkSize && len(names) < maxCHMFiles; chunk = chunk[chunkSize:] {
+		if !bytes.HasPrefix(chunk, []byte("PMGL")) {
+			// index chunks (PMGI) only repeat the names
+			continue
+		}
+		free := int(binary.LittleEndian.Uint32(chunk[4:]))
+		end := chunkSize - free
+		if end > chunkSize || end < 0x14 {
+			end = chunkSize
+		}
+		entries := chunk[0x14:end]
+		for len(entries) > 0 {
+			nameLength, n := encint(entries)
+			if n == 0 || nameLength == 0 || nameLength > uint64(len(entries)-n) {
+				// the free space at the end of the chunk is zeroed
+				break
+			}
+			name := string(entries[n : n+int(nameLength)])
+			entries = entries[n+int(nameLength):]
+			// content section, offset and length
+			for i := 0; i < 3; i++ {
+				_, n = encint(entries)
+				if n == 0 {
+					entries = nil
+					break
+				}
+				entries = entries[n:]
+			}
+			if strings.HasPrefix(name, "::") || strings.HasPrefix(name, "/#") || strings.HasPrefix(name, "/$") || strings.HasSuffix(name, "/") {
+				continue
+			}
+			names = append(names, name)
+		}
+	}
+	return names, nil
+}
+
+// encint decodes a variable length integer: 7 bits per byte, most significant bits first.
+func encint(b []byte) (v uint64, n int) {
+	for n < len(b) && n < 9 {
+		c := b[n]
+		n++
+		v = v<<7 | uint64(c&0x7f)
+		if c&0x80 == 0 {
+			return v, n
+		}
+	}
+	return 0, 0
+}
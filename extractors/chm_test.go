@@ -0,0 +1,146 @@
+package extractors
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"reflect"
+	"testing"
+)
+
+const testCHMChunkSize = 0x80
+
+// pmglEntry encodes a directory entry: the name, then its content section, offset and length.
+func pmglEntry(name string) []byte {
+	b := []byte{byte(len(name))}
+	b = append(b, name...)
+	return append(b, 0, 0x81, 0x00, 0x7f)
+}
+
+// chmChunk builds a directory chunk of testCHMChunkSize bytes.
+func chmChunk(magic string, entries ...[]byte) []byte {
+	chunk := make([]byte, 0x14, testCHMChunkSize)
+	copy(chunk, magic)
+	for _, e := range entries {
+		chunk = append(chunk, e...)
+	}
+	binary.LittleEndian.PutUint32(chunk[4:], uint32(testCHMChunkSize-len(chunk)))
+	return chunk[:testCHMChunkSize]
+}
+
+// chmFile builds an ITSF container whose directory is made of the chunks.
+func chmFile(chunks ...[]byte) []byte {
+	dir := make([]byte, 0x54)
+	copy(dir, "ITSP")
+	binary.LittleEndian.PutUint32(dir[0x08:], 0x54)
+	binary.LittleEndian.PutUint32(dir[0x10:], testCHMChunkSize)
+	for _, c := range chunks {
+		dir = append(dir, c...)
+	}
+	header := make([]byte, 0x58)
+	copy(header, "ITSF")
+	binary.LittleEndian.PutUint64(header[0x48:], 0x58)
+	binary.LittleEndian.PutUint64(header[0x50:], uint64(len(dir)))
+	return append(header, dir...)
+}
+
+func TestCHMFiles(t *testing.T) {
+	valid := chmFile(
+		chmChunk("PMGL", pmglEntry("/"), pmglEntry("::DataSpace/NameList"), pmglEntry("/#SYSTEM"),
+			pmglEntry("/$FIftiMain"), pmglEntry("/index.html"), pmglEntry("/images/")),
+		chmChunk("PMGI", pmglEntry("/index.html")),
+		chmChunk("PMGL", pmglEntry("/images/logo.png"), pmglEntry("/payload.exe")),
+	)
+	names, err := CHMFiles(valid)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := []string{"/index.html", "/images/logo.png", "/payload.exe"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("expected %q, got %q", expected, names)
+	}
+
+	set := func(b []byte, offset int, v uint64, size int) []byte {
+		b = append([]byte{}, b...)
+		if size == 4 {
+			binary.LittleEndian.PutUint32(b[offset:], uint32(v))
+		} else {
+			binary.LittleEndian.PutUint64(b[offset:], v)
+		}
+		return b
+	}
+	malformed := []struct {
+		name    string
+		content []byte
+	}{
+		{"empty", nil},
+		{"short", valid[:0x57]},
+		{"truncated", valid[:len(valid)-1]},
+		{"not ITSF", append([]byte("MSCF"), valid[4:]...)},
+		{"directory out of the file", set(valid, 0x48, uint64(len(valid)), 8)},
+		{"directory longer than the file", set(valid, 0x50, uint64(len(valid)), 8)},
+		{"huge directory length", set(valid, 0x50, ^uint64(0), 8)},
+		{"short directory", set(valid, 0x50, 0x20, 8)},
+		{"not ITSP", set(valid, 0x58, 0, 4)},
+		{"small chunks", set(valid, 0x58+0x10, 0x10, 4)},
+		{"header longer than the directory", set(valid, 0x58+0x08, uint64(len(valid)), 4)},
+	}
+	for _, c := range malformed {
+		if names, err := CHMFiles(c.content); err == nil {
+			t.Errorf("%s: no error, names %q", c.name, names)
+		}
+	}
+
+	partial := []struct {
+		name    string
+		content []byte
+		names   []string
+	}{
+		{"truncated chunk", set(valid[:len(valid)-1], 0x50, uint64(len(valid)-0x58-1), 8), []string{"/index.html"}},
+		{"name longer than the chunk", chmFile(chmChunk("PMGL", pmglEntry("/a.html"), []byte{0x7f, 'b'})), []string{"/a.html"}},
+		{"unterminated integer", chmFile(chmChunk("PMGL", pmglEntry("/a.html"), []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff})), []string{"/a.html"}},
+		{"free space larger than the chunk", set(chmFile(chmChunk("PMGL", pmglEntry("/a.html"))), 0x58+0x54+4, 0xffffffff, 4), []string{"/a.html"}},
+	}
+	for _, c := range partial {
+		names, err := CHMFiles(c.content)
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if !reflect.DeepEqual(names, c.names) {
+			t.Errorf("%s: expected %q, got %q", c.name, c.names, names)
+		}
+	}
+
+	// truncated and corrupted files are read without panic
+	for i := range valid {
+		_, _ = CHMFiles(valid[:i])
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 1000; i++ {
+		corrupted := append([]byte{}, valid...)
+		for j := 0; j < 4; j++ {
+			corrupted[r.Intn(len(corrupted))] = byte(r.Intn(256))
+		}
+		_, _ = CHMFiles(corrupted)
+	}
+}
+
+func TestEncint(t *testing.T) {
+	cases := []struct {
+		b []byte
+		v uint64
+		n int
+	}{
+		{[]byte{0x00}, 0, 1},
+		{[]byte{0x7f, 0x01}, 0x7f, 1},
+		{[]byte{0x81, 0x00}, 0x80, 2},
+		{[]byte{0x82, 0x80, 0x01}, 0x8001, 3},
+		{nil, 0, 0},
+		{[]byte{0x80}, 0, 0},
+		{[]byte{0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x80, 0x01}, 0, 0},
+	}
+	for _, c := range cases {
+		if v, n := encint(c.b); v != c.v || n != c.n {
+			t.Errorf("encint(%x): expected %d %d, got %d %d", c.b, c.v, c.n, v, n)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package extractors
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// confusables maps characters to the ASCII prototype they can be confused with. It is the part of
+// the Unicode confusables data (UTS #39) that matters for domain names: the lowercase letters and
+// digits that are valid in hostnames and that look like an ASCII letter.
+var confusables = map[rune]string{
+	// ASCII
+	'0': "o", '1': "l", 'm': "rn", 'd': "cl",
+	// Latin
+	'ı': "i", 'ȷ': "j", 'ɑ': "a", 'ɡ': "g", 'ɩ': "i", 'ɪ': "i", 'ʏ': "y", 'ɴ': "n", 'ʀ': "r",
+	'ℓ': "l", 'ǀ': "l", 'ɒ': "a", 'ɸ': "o", 'ꞵ': "b", 'ƅ': "b", 'ɓ': "b", 'ƿ': "p", 'ʋ': "u",
+	'ɜ': "e", 'ʙ': "b", 'ᴅ': "d", 'ᴏ': "o", 'ᴜ': "u", 'ᴠ': "v", 'ᴡ': "w", 'ᴢ': "z", 'ᴄ': "c",
+	// Cyrillic
+	'а': "a", 'в': "b", 'с': "c", 'ԁ': "d", 'е': "e", 'ё': "e", 'һ': "h", 'і': "i", 'ї': "i",
+	'ј': "j", 'к': "k", 'ӏ': "l", 'м': "rn", 'н': "h", 'о': "o", 'р': "p", 'ԛ': "q", 'г': "r",
+	'ѕ': "s", 'т': "t", 'ц': "u", 'ѵ': "v", 'ԝ': "w", 'х': "x", 'у': "y", 'ү': "y", 'ь': "b",
+	'ъ': "b", 'п': "n", 'ѡ': "w",
+	// Greek
+	'α': "a", 'β': "b", 'γ': "y", 'ε': "e", 'η': "n", 'ι': "i", 'κ': "k", 'ν': "v", 'ο': "o",
+	'ρ': "p", 'τ': "t", 'υ': "u", 'χ': "x", 'ω': "w", 'ϲ': "c", 'ϳ': "j", 'ζ': "z",
+	// Armenian
+	'ա': "w", 'ց': "g", 'հ': "h", 'ո': "n", 'ռ': "n", 'ս': "u", 'օ': "o", 'զ': "q", 'ք': "p",
+}
+
+// Skeleton returns the skeleton of s, in the sense of UTS #39: two strings that look the same
+// have the same skeleton. The diacritics are dropped as well, as they are barely visible in
+// small fonts.
+func Skeleton(s string) string {
+	s = norm.NFD.String(norm.NFKC.String(strings.ToLower(s)))
+	var b strings.Builder
+	for _, r := range s {
+		if unicode.Is(unicode.Mn, r) {
+			continue
+		}
+		if proto, ok := confusables[r]; ok {
+			b.WriteString(proto)
+		} else {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,124 @@
+package extractors
+
+import (
+	"net"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+// DirectionClassifier finds the direction of the mails, relative to the internal domains of the
+// organization and to the trusted networks that submit its mails.
+type DirectionClassifier struct {
+	domains []string
+	trusted []*net.IPNet
+}
+
+// NewDirectionClassifier returns nil when neither internal domains nor trusted networks are
+// configured.
+func NewDirectionClassifier(domains []string, trusted []*net.IPNet) *DirectionClassifier {
+	c := &DirectionClassifier{trusted: trusted}
+	for _, d := range domains {
+		d = strings.Trim(strings.ToLower(strings.TrimSpace(d)), ".")
+		if d != "" {
+			c.domains = append(c.domains, d)
+		}
+	}
+	if len(c.domains) == 0 && len(c.trusted) == 0 {
+		return nil
+	}
+	return c
+}
+
+// IsInternalDomain reports whether domain is an internal domain, or one of its subdomains.
+func (c *DirectionClassifier) IsInternalDomain(domain string) bool {
+	domain = strings.Trim(strings.ToLower(domain), ".")
+	if domain == "" {
+		return false
+	}
+	for _, d := range c.domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsTrusted reports whether the client at addr is in a trusted network. The loopback is only
+// trusted when it is listed: the SMTP service usually receives the mails relayed by a local MTA.
+func (c *DirectionClassifier) IsTrusted(addr string) bool {
+	ip := net.ParseIP(addr)
+	if ip == nil {
+		return false
+	}
+	for _, n := range c.trusted {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReceivedFromClient reports whether the mails of family are received from a SMTP client: by the
+// SMTP service ("smtp"), or by the milter (the family of the client connection). The other mails
+// are read from a mailbox or posted to the HTTP API.
+func ReceivedFromClient(family string) bool {
+	switch family {
+	case "smtp", "tcp4", "tcp6", "unix", "unknown":
+		return true
+	}
+	return false
+}
+
+// Classify finds the direction of a mail from its sender and recipients addresses, the family
+// and the address of the client that submitted it, and the user authenticated by the MTA (the
+// {auth_authen} macro of the milter).
+//
+// The mail comes from the organization when the SMTP client is trusted or authenticated. When the
+// mail was read from a mailbox or posted to the HTTP API, the SMTP client is unknown, and the
+// sender domain decides: the address of the HTTP client is the uploader's. A mail received from
+// an unknown SMTP client is not trusted.
+func (c *DirectionClassifier) Classify(sender string, recipients []string, family string, addr string, authUser string) *models.Direction {
+	fromClient := ReceivedFromClient(family)
+	d := &models.Direction{
+		InternalSender: c.IsInternalDomain(utils.DomainFromAddress(sender)),
+		TrustedClient:  fromClient && c.IsTrusted(addr),
+		Authenticated:  fromClient && authUser != "",
+	}
+	for _, r := range recipients {
+		r = strings.ToLower(strings.Trim(strings.TrimSpace(r), "<>"))
+		if r == "" || len(c.domains) == 0 {
+			// without internal domains, the recipients can not be split
+			continue
+		}
+		if c.IsInternalDomain(utils.DomainFromAddress(r)) {
+			d.InternalRecipients = append(d.InternalRecipients, r)
+		} else {
+			d.ExternalRecipients = append(d.ExternalRecipients, r)
+		}
+	}
+	d.InternalRecipients = distinctStrings(d.InternalRecipients)
+	d.ExternalRecipients = distinctStrings(d.ExternalRecipients)
+
+	fromOrganization := d.TrustedClient || d.Authenticated || (d.InternalSender && !fromClient)
+	if d.InternalSender && !fromOrganization {
+		d.SpoofedInternalSender = true
+	}
+
+	switch {
+	case fromOrganization && len(d.ExternalRecipients) > 0:
+		d.Direction = models.DirectionOutbound
+	case fromOrganization && len(d.InternalRecipients) > 0:
+		d.Direction = models.DirectionInternal
+	case fromOrganization:
+		// the recipients are unknown
+		d.Direction = models.DirectionOutbound
+	case len(d.InternalRecipients) > 0 || len(d.ExternalRecipients) == 0:
+		d.Direction = models.DirectionInbound
+	default:
+		// relayed between external parties
+		d.Direction = models.DirectionExternal
+	}
+	return d
+}
@@ -0,0 +1,71 @@
+package extractors
+
+import (
+	"net"
+	"testing"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestClassifyDirection(t *testing.T) {
+	_, trusted, _ := net.ParseCIDR("10.0.0.0/8")
+	c := NewDirectionClassifier([]string{"example.org"}, []*net.IPNet{trusted})
+	cases := []struct {
+		name       string
+		sender     string
+		recipients []string
+		family     string
+		addr       string
+		authUser   string
+		direction  string
+		spoofed    bool
+	}{
+		{"inbound", "bob@example.com", []string{"alice@example.org"}, "smtp", "198.51.100.7", "", models.DirectionInbound, false},
+		{"outbound from trusted network", "alice@example.org", []string{"bob@example.com"}, "smtp", "10.1.2.3", "", models.DirectionOutbound, false},
+		{"internal", "alice@example.org", []string{"carol@sub.example.org"}, "tcp4", "10.1.2.3", "", models.DirectionInternal, false},
+		{"authenticated", "alice@example.org", []string{"bob@example.com"}, "smtp", "198.51.100.7", "alice", models.DirectionOutbound, false},
+		{"spoofed internal sender", "alice@example.org", []string{"carol@example.org"}, "smtp", "198.51.100.7", "", models.DirectionInbound, true},
+		{"unknown smtp client", "alice@example.org", []string{"carol@example.org"}, "smtp", "", "", models.DirectionInbound, true},
+		{"unknown milter client", "alice@example.org", []string{"bob@example.com"}, "unknown", "", "", models.DirectionExternal, true},
+		{"loopback", "alice@example.org", []string{"bob@example.com"}, "smtp", "127.0.0.1", "", models.DirectionExternal, true},
+		{"loopback inbound", "bob@example.com", []string{"alice@example.org"}, "smtp", "::1", "", models.DirectionInbound, false},
+		{"http from trusted network", "bob@example.com", []string{"dan@example.net"}, "http", "10.1.2.3", "", models.DirectionExternal, false},
+		{"http internal sender", "alice@example.org", []string{"bob@example.com"}, "http", "198.51.100.7", "", models.DirectionOutbound, false},
+		{"authenticated http", "bob@example.com", []string{"dan@example.net"}, "http", "", "bob", models.DirectionExternal, false},
+		{"mailbox", "alice@example.org", []string{"bob@example.com"}, "mbox", "", "", models.DirectionOutbound, false},
+		{"mailbox inbound", "bob@example.com", []string{"alice@example.org"}, "maildir", "", "", models.DirectionInbound, false},
+		{"external relay", "bob@example.com", []string{"dan@example.net"}, "smtp", "198.51.100.7", "", models.DirectionExternal, false},
+	}
+	for _, tc := range cases {
+		d := c.Classify(tc.sender, tc.recipients, tc.family, tc.addr, tc.authUser)
+		if d.Direction != tc.direction {
+			t.Errorf("%s: direction = %s, want %s", tc.name, d.Direction, tc.direction)
+		}
+		if d.SpoofedInternalSender != tc.spoofed {
+			t.Errorf("%s: spoofed = %v, want %v", tc.name, d.SpoofedInternalSender, tc.spoofed)
+		}
+	}
+}
+
+func TestClassifyDirectionTrustedLoopback(t *testing.T) {
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+	c := NewDirectionClassifier([]string{"example.org"}, []*net.IPNet{loopback})
+	d := c.Classify("alice@example.org", []string{"bob@example.com"}, "smtp", "127.0.0.1", "")
+	if d.Direction != models.DirectionOutbound || !d.TrustedClient || d.SpoofedInternalSender {
+		t.Errorf("listed loopback: %+v", d)
+	}
+	if c.IsTrusted("::1") {
+		t.Error("IPv6 loopback trusted without being listed")
+	}
+}
+
+func TestDirectionIs(t *testing.T) {
+	var unknown *models.Direction
+	if unknown.Is(models.DirectionInbound, models.DirectionOutbound) {
+		t.Error("an unknown direction matches")
+	}
+	d := &models.Direction{Direction: models.DirectionInbound}
+	if !d.Is(models.DirectionOutbound, models.DirectionInbound) || d.Is(models.DirectionOutbound) {
+		t.Error("Is does not match the direction")
+	}
+}
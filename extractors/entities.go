@@ -0,0 +1,614 @@
+package extractors
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/jdkato/prose"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// The entity extractors surface the people, organisations and places, and the payment details
+// that are typical of BEC, invoice fraud and sextortion: amounts, bank accounts, phone numbers,
+// cryptocurrency wallets, gift cards, tracking numbers and invoice or order references.
+
+// maxNERText is the size of the text given to the named-entity recognizer, which is slow.
+const maxNERText = 20000
+
+// maxEntities is the number of values kept for each kind of entity.
+const maxEntities = 50
+
+var nerModel *prose.Model
+var nerOnce sync.Once
+
+// loadNERModel loads the tagger and the entity model of prose once. prose panics when its model
+// cannot be loaded: the named-entity recognition is then disabled.
+func loadNERModel() *prose.Model {
+	nerOnce.Do(func() {
+		defer func() {
+			if r := recover(); r != nil {
+				nerModel = nil
+			}
+		}()
+		doc, err := prose.NewDocument("", prose.WithSegmentation(false))
+		if err == nil {
+			nerModel = doc.Model
+		}
+	})
+	return nerModel
+}
+
+// ExtractEntities finds the named entities and the financial indicators in text.
+func ExtractEntities(text string) *models.Entities {
+	text = strings.TrimSpace(text)
+	if len(text) == 0 {
+		return nil
+	}
+	e := new(models.Entities)
+	namedEntities(text, e)
+	e.Organisations = limit(distinctStrings(append(e.Organisations, findOrganisations(text)...)))
+	e.Amounts = findAmounts(text)
+	e.IBANs = limit(findIBANs(text))
+	e.BICs = limit(findBICs(text))
+	e.Phones = limit(findPhones(text))
+	e.Wallets = findWallets(text)
+	e.GiftCards = limit(findGiftCards(text))
+	e.TrackingNumbers = findTrackingNumbers(text)
+	e.References = findReferences(text)
+	if len(e.People) == 0 && len(e.Organisations) == 0 && len(e.Places) == 0 && len(e.Amounts) == 0 &&
+		len(e.IBANs) == 0 && len(e.BICs) == 0 && len(e.Phones) == 0 && len(e.Wallets) == 0 &&
+		len(e.GiftCards) == 0 && len(e.TrackingNumbers) == 0 && len(e.References) == 0 {
+		return nil
+	}
+	return e
+}
+
+func namedEntities(text string, e *models.Entities) {
+	model := loadNERModel()
+	if model == nil {
+		return
+	}
+	if len(text) > maxNERText {
+		text = text[:maxNERText]
+	}
+	doc, err := prose.NewDocument(text, prose.WithSegmentation(false), prose.UsingModel(model))
+	if err != nil {
+		return
+	}
+	for _, ent := range doc.Entities() {
+		name := strings.Join(strings.Fields(ent.Text), " ")
+		if len(name) < 2 {
+			continue
+		}
+		switch ent.Label {
+		case "PERSON":
+			e.People = append(e.People, name)
+		case "ORG", "ORGANIZATION":
+			e.Organisations = append(e.Organisations, name)
+		case "GPE", "LOC", "LOCATION":
+			e.Places = append(e.Places, name)
+		}
+	}
+	e.People = limit(distinctStrings(e.People))
+	e.Places = limit(distinctStrings(e.Places))
+}
+
+// the entity model of prose does not know organisations: they are recognized by their legal form
+var organisationRE = regexp.MustCompile(`(?:^|[^\p{L}])((?:\p{Lu}[\p{L}&'-]*\s+){1,3}(?:Inc|Ltd|LLC|LLP|GmbH|AG|SA|SAS|SARL|PLC|Plc|Corp|Corporation|Limited|Bank|Group))(?:[^\p{L}]|$)`)
+
+func findOrganisations(text string) (orgs []string) {
+	for _, m := range organisationRE.FindAllStringSubmatch(text, -1) {
+		orgs = append(orgs, strings.Join(strings.Fields(m[1]), " "))
+	}
+	return orgs
+}
+
+// amounts
+
+const amountNumber = `\d{1,3}(?:[,.' \x{00a0}\x{202f}]\d{3})+(?:[.,]\d{1,8})?|\d+(?:[.,]\d{1,8})?`
+const currencyCodes = `USD|EUR|GBP|CHF|JPY|CNY|CAD|AUD|RUB|INR|BTC|XBT|ETH`
+
+var amountPrefixRE = regexp.MustCompile(`(?i)(US\$|CA\$|AU\$|A\$|\$|€|£|¥|₽|₹|₿|\b(?:` + currencyCodes + `)\b)\s?(` + amountNumber + `)`)
+var amountSuffixRE = regexp.MustCompile(`(?i)\b(` + amountNumber + `)\s?(\$|€|£|¥|₽|₹|₿|(?:` + currencyCodes + `|dollars?|euros?|pounds?|bitcoins?)\b|руб(?:лей|ля|ль)?)`)
+
+var currencies = map[string]string{
+	"$": "USD", "us$": "USD", "dollar": "USD", "dollars": "USD",
+	"ca$": "CAD", "a$": "AUD", "au$": "AUD",
+	"€": "EUR", "euro": "EUR", "euros": "EUR",
+	"£": "GBP", "pound": "GBP", "pounds": "GBP",
+	"¥": "JPY", "₹": "INR",
+	"₽": "RUB", "руб": "RUB", "рубль": "RUB", "рубля": "RUB", "рублей": "RUB",
+	"₿": "BTC", "xbt": "BTC", "bitcoin": "BTC", "bitcoins": "BTC",
+}
+
+func currencyCode(s string) string {
+	s = strings.ToLower(s)
+	if c, ok := currencies[s]; ok {
+		return c
+	}
+	return strings.ToUpper(s)
+}
+
+func findAmounts(text string) (amounts []models.Amount) {
+	seen := make(map[models.Amount]bool)
+	add := func(match, currency, number string) {
+		currency = currencyCode(currency)
+		value, ok := parseAmount(number, currency == "BTC" || currency == "ETH")
+		if !ok || len(amounts) >= maxEntities {
+			return
+		}
+		key := models.Amount{Currency: currency, Value: value}
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		amounts = append(amounts, models.Amount{Text: strings.TrimSpace(match), Currency: currency, Value: value})
+	}
+	for _, m := range amountPrefixRE.FindAllStringSubmatch(text, -1) {
+		add(m[0], m[1], m[2])
+	}
+	for _, m := range amountSuffixRE.FindAllStringSubmatch(text, -1) {
+		add(m[0], m[2], m[1])
+	}
+	return amounts
+}
+
+// parseAmount parses a number written with the English or the continental conventions. The last
+// separator is the decimal one, unless it is followed by three digits (1,000 or 1.000), which are
+// decimals only for the cryptocurrencies.
+func parseAmount(s string, crypto bool) (float64, bool) {
+	s = strings.Map(func(r rune) rune {
+		if r == ' ' || r == '\'' || r == '\u00a0' || r == '\u202f' {
+			return -1
+		}
+		return r
+	}, s)
+	last := strings.LastIndexAny(s, ".,")
+	if last >= 0 {
+		intPart, frac := s[:last], s[last+1:]
+		sep := s[last]
+		decimal := true
+		if len(frac) == 3 && !crypto && intPart != "0" {
+			// 1,000 is a thousand, but 1.000,500 has decimals
+			decimal = strings.IndexByte(intPart, otherSeparator(sep)) >= 0
+		}
+		if strings.IndexByte(intPart, sep) >= 0 {
+			// the same separator used several times is a thousands separator
+			decimal = false
+		}
+		intPart = strings.NewReplacer(",", "", ".", "").Replace(intPart)
+		if decimal {
+			s = intPart + "." + frac
+		} else {
+			s = intPart + frac
+		}
+	}
+	v, err := strconv.ParseFloat(s, 64)
+	if err != nil || v <= 0 {
+		return 0, false
+	}
+	return v, true
+}
+
+func otherSeparator(sep byte) byte {
+	if sep == '.' {
+		return ','
+	}
+	return '.'
+}
+
+// bank accounts
+
+var ibanLengths = map[string]int{
+	"AD": 24, "AE": 23, "AL": 28, "AT": 20, "AZ": 28, "BA": 20, "BE": 16, "BG": 22, "BH": 22,
+	"BR": 29, "BY": 28, "CH": 21, "CR": 22, "CY": 28, "CZ": 24, "DE": 22, "DK": 18, "DO": 28,
+	"EE": 20, "EG": 29, "ES": 24, "FI": 18, "FO": 18, "FR": 27, "GB": 22, "GE": 22, "GI": 23,
+	"GL": 18, "GR": 27, "GT": 28, "HR": 21, "HU": 28, "IE": 22, "IL": 23, "IQ": 23, "IS": 26,
+	"IT": 27, "JO": 30, "KW": 30, "KZ": 20, "LB": 28, "LC": 32, "LI": 21, "LT": 20, "LU": 20,
+	"LV": 21, "MC": 27, "MD": 24, "ME": 22, "MK": 19, "MR": 27, "MT": 31, "MU": 30, "NL": 18,
+	"NO": 15, "PK": 24, "PL": 28, "PS": 29, "PT": 25, "QA": 29, "RO": 24, "RS": 22, "SA": 24,
+	"SC": 31, "SE": 24, "SI": 19, "SK": 24, "SM": 27, "TN": 24, "TR": 26, "UA": 29, "VA": 22,
+	"VG": 24, "XK": 20,
+}
+
+// the countries that have banks with a BIC, besides the IBAN countries
+var bicCountries = map[string]bool{
+	"US": true, "CA": true, "CN": true, "JP": true, "AU": true, "IN": true, "HK": true, "SG": true,
+	"RU": true, "ZA": true, "MX": true, "NZ": true, "KR": true, "NG": true, "MA": true, "TH": true,
+}
+
+// IBANRE matches the IBAN candidates, to be checked by NormalizeIBAN.
+var IBANRE = regexp.MustCompile(`\b[A-Z]{2}\d{2}(?: ?[A-Z0-9]{4}){2,7}(?: ?[A-Z0-9]{1,4})?\b`)
+
+func findIBANs(text string) (ibans []string) {
+	for _, m := range IBANRE.FindAllString(text, -1) {
+		if iban, ok := NormalizeIBAN(m); ok {
+			ibans = append(ibans, iban)
+		}
+	}
+	return distinctStrings(ibans)
+}
+
+// NormalizeIBAN removes the spaces of an IBAN candidate and checks its length and its checksum.
+func NormalizeIBAN(candidate string) (string, bool) {
+	compact := strings.Replace(candidate, " ", "", -1)
+	// the match may swallow the word that follows the IBAN
+	length, ok := ibanLengths[compact[:2]]
+	if !ok || len(compact) < length {
+		return "", false
+	}
+	iban := compact[:length]
+	return iban, validIBAN(iban)
+}
+
+// validIBAN checks the ISO 13616 checksum: the number made of the IBAN, with the first four
+// characters moved to the end and the letters replaced by 10..35, is 1 modulo 97.
+func validIBAN(iban string) bool {
+	rearranged := iban[4:] + iban[:4]
+	remainder := 0
+	for _, c := range rearranged {
+		switch {
+		case c >= '0' && c <= '9':
+			remainder = (remainder*10 + int(c-'0')) % 97
+		case c >= 'A' && c <= 'Z':
+			remainder = (remainder*100 + int(c-'A') + 10) % 97
+		default:
+			return false
+		}
+	}
+	return remainder == 1
+}
+
+// a BIC looks like many uppercase words: it must be introduced by its name
+var bicRE = regexp.MustCompile(`(?i:\b(?:bic|swift)(?:\s*/\s*(?:bic|swift))?(?:[ -]?code)?)\s*[:#]?\s*([A-Z]{4}[A-Z]{2}[A-Z0-9]{2}(?:[A-Z0-9]{3})?)\b`)
+
+func findBICs(text string) (bics []string) {
+	for _, m := range bicRE.FindAllStringSubmatch(text, -1) {
+		country := m[1][4:6]
+		if _, ok := ibanLengths[country]; ok || bicCountries[country] {
+			bics = append(bics, m[1])
+		}
+	}
+	return distinctStrings(bics)
+}
+
+// phone numbers
+
+var intlPhoneRE = regexp.MustCompile(`(?:\+|\b00)[1-9][\d \-.()\x{00a0}]{6,20}\d`)
+
+// national numbers are recognized for North America and France
+var nanpPhoneRE = regexp.MustCompile(`(?:\b1[ .-]?)?(?:\(\b[2-9]\d{2}\)\s?|\b[2-9]\d{2}[ .-])\d{3}[ .-]\d{4}\b`)
+var frPhoneRE = regexp.MustCompile(`\b0[1-9](?:[ .-]?\d{2}){4}\b`)
+
+func digits(s string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, s)
+}
+
+func findPhones(text string) (phones []string) {
+	add := func(number string) {
+		// E.164 numbers have at most 15 digits
+		if len(number) >= 8 && len(number) <= 15 {
+			phones = append(phones, "+"+number)
+		}
+	}
+	// the digits of the bank accounts look like phone numbers
+	text = IBANRE.ReplaceAllString(text, " ")
+	for _, m := range intlPhoneRE.FindAllString(text, -1) {
+		// +44 (0)20 7946 0958: the trunk prefix is not dialled from abroad
+		m = strings.Replace(m, "(0)", "", 1)
+		number := digits(m)
+		if strings.HasPrefix(m, "00") {
+			number = number[2:]
+		}
+		add(number)
+	}
+	for _, m := range nanpPhoneRE.FindAllString(text, -1) {
+		number := digits(m)
+		if len(number) == 10 {
+			number = "1" + number
+		}
+		add(number)
+	}
+	for _, m := range frPhoneRE.FindAllString(text, -1) {
+		add("33" + digits(m)[1:])
+	}
+	return distinctStrings(phones)
+}
+
+// cryptocurrency wallets
+
+var btcLegacyRE = regexp.MustCompile(`\b[13][a-km-zA-HJ-NP-Z1-9]{25,34}\b`)
+var btcBech32RE = regexp.MustCompile(`(?i)\bbc1[ac-hj-np-z02-9]{11,71}\b`)
+var ethRE = regexp.MustCompile(`\b0x[0-9a-fA-F]{40}\b`)
+
+func findWallets(text string) (wallets []models.Wallet) {
+	seen := make(map[string]bool)
+	add := func(w models.Wallet) {
+		if !seen[w.Address] && len(wallets) < maxEntities {
+			seen[w.Address] = true
+			wallets = append(wallets, w)
+		}
+	}
+	for _, m := range btcLegacyRE.FindAllString(text, -1) {
+		if validBase58Check(m) {
+			add(models.Wallet{Currency: "BTC", Address: m, Checksummed: true})
+		}
+	}
+	for _, m := range btcBech32RE.FindAllString(text, -1) {
+		if validBech32(m) {
+			add(models.Wallet{Currency: "BTC", Address: strings.ToLower(m), Checksummed: true})
+		}
+	}
+	for _, m := range ethRE.FindAllString(text, -1) {
+		hexPart := m[2:]
+		mixed := strings.ToLower(hexPart) != hexPart && strings.ToUpper(hexPart) != hexPart
+		if mixed && !validEIP55(hexPart) {
+			// a typo, or not an address at all
+			continue
+		}
+		add(models.Wallet{Currency: "ETH", Address: m, Checksummed: mixed})
+	}
+	return wallets
+}
+
+const base58Alphabet = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+func base58Decode(s string) []byte {
+	var out []byte
+	for _, c := range s {
+		carry := strings.IndexRune(base58Alphabet, c)
+		if carry < 0 {
+			return nil
+		}
+		for i := len(out) - 1; i >= 0; i-- {
+			carry += 58 * int(out[i])
+			out[i] = byte(carry)
+			carry >>= 8
+		}
+		for ; carry > 0; carry >>= 8 {
+			out = append([]byte{byte(carry)}, out...)
+		}
+	}
+	for _, c := range s {
+		if c != '1' {
+			break
+		}
+		out = append([]byte{0}, out...)
+	}
+	return out
+}
+
+// validBase58Check checks the P2PKH and P2SH addresses: a version byte, a 20 bytes hash and the
+// first 4 bytes of the double SHA-256 of both.
+func validBase58Check(s string) bool {
+	b := base58Decode(s)
+	if len(b) != 25 || (b[0] != 0x00 && b[0] != 0x05) {
+		return false
+	}
+	first := sha256.Sum256(b[:21])
+	second := sha256.Sum256(first[:])
+	return bytes.Equal(second[:4], b[21:])
+}
+
+const bech32Charset = "qpzry9x8gf2tvdw0s3jn54khce6mua7l"
+
+func bech32Polymod(values []byte) uint32 {
+	generator := [5]uint32{0x3b6a57b2, 0x26508e6d, 0x1ea119fa, 0x3d4233dd, 0x2a1462b3}
+	chk := uint32(1)
+	for _, v := range values {
+		top := chk >> 25
+		chk = (chk&0x1ffffff)<<5 ^ uint32(v)
+		for i := 0; i < 5; i++ {
+			if (top>>uint(i))&1 == 1 {
+				chk ^= generator[i]
+			}
+		}
+	}
+	return chk
+}
+
+// validBech32 checks the segwit addresses: BIP 173 (bech32) for the version 0, BIP 350 (bech32m)
+// for the later versions.
+func validBech32(s string) bool {
+	if strings.ToLower(s) != s && strings.ToUpper(s) != s {
+		return false
+	}
+	s = strings.ToLower(s)
+	sepIdx := strings.LastIndexByte(s, '1')
+	hrp, data := s[:sepIdx], s[sepIdx+1:]
+	if len(data) < 7 {
+		return false
+	}
+	values := make([]byte, 0, 2*len(hrp)+1+len(data))
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]>>5)
+	}
+	values = append(values, 0)
+	for i := 0; i < len(hrp); i++ {
+		values = append(values, hrp[i]&31)
+	}
+	for i := 0; i < len(data); i++ {
+		v := strings.IndexByte(bech32Charset, data[i])
+		if v < 0 {
+			return false
+		}
+		values = append(values, byte(v))
+	}
+	witnessVersion := values[2*len(hrp)+1]
+	switch bech32Polymod(values) {
+	case 1:
+		return witnessVersion == 0
+	case 0x2bc830a3:
+		return witnessVersion > 0 && witnessVersion <= 16
+	}
+	return false
+}
+
+// validEIP55 checks the mixed-case checksum of an Ethereum address: a letter is uppercase when
+// the matching nibble of the Keccak-256 hash of the lowercase address is at least 8.
+func validEIP55(address string) bool {
+	hash := hex.EncodeToString(keccak256([]byte(strings.ToLower(address))))
+	for i, c := range address {
+		if !unicode.IsLetter(c) {
+			continue
+		}
+		if (hash[i] >= '8') != unicode.IsUpper(c) {
+			return false
+		}
+	}
+	return true
+}
+
+// gift cards and prepaid vouchers
+
+const giftCardBrands = `itunes|apple|google play|amazon|steam|ebay|walmart|target|vanilla|visa|sephora|best buy|razer gold|netflix|xbox|playstation|psn`
+
+var giftCardREs = []*regexp.Regexp{
+	regexp.MustCompile(`(?i)\b(?:(` + giftCardBrands + `)\s+)?e?-?gift\s?cards?\b`),
+	regexp.MustCompile(`(?i)\b(?:e-?)?cartes?[ -]cadeaux?\b(?:\s+(` + giftCardBrands + `)\b)?`),
+	regexp.MustCompile(`(?i)\b(?:(` + giftCardBrands + `)[ -])?(?:geschenk|gutschein)karten?\b`),
+	regexp.MustCompile(`(?i)подарочн\p{L}*\s+карт\p{L}*(?:\s+(` + giftCardBrands + `)\b)?`),
+	regexp.MustCompile(`(?i)\b(paysafecard|neosurf|transcash|pcs mastercard)\b`),
+}
+
+func findGiftCards(text string) (brands []string) {
+	for _, re := range giftCardREs {
+		for _, m := range re.FindAllStringSubmatch(text, -1) {
+			brand := strings.ToLower(strings.Join(strings.Fields(m[1]), " "))
+			if brand == "" {
+				brand = "generic"
+			}
+			brands = append(brands, brand)
+		}
+	}
+	return distinctStrings(brands)
+}
+
+// tracking numbers
+
+var upsRE = regexp.MustCompile(`\b1Z[0-9A-Z]{16}\b`)
+
+// S10 is the UPU format of the international postal items: RR123456785FR
+var s10RE = regexp.MustCompile(`\b[A-Z]{2}\d{9}[A-Z]{2}\b`)
+
+var trackingContextRE = regexp.MustCompile(`(?i)(?:^|[^\p{L}])(?:tracking|suivi|sendungsnummer|трек)\p{L}*(?:\s+(?:number|no\.?|num[ée]ro|code|id|n°|nr\.?|номер))?\s*[:#]?\s*([A-Z0-9]{10,30})\b`)
+
+func findTrackingNumbers(text string) (numbers []models.TrackingNumber) {
+	seen := make(map[string]bool)
+	add := func(carrier, number string) {
+		if !seen[number] && len(numbers) < maxEntities {
+			seen[number] = true
+			numbers = append(numbers, models.TrackingNumber{Carrier: carrier, Number: number})
+		}
+	}
+	for _, m := range upsRE.FindAllString(text, -1) {
+		if validUPS(m) {
+			add("ups", m)
+		}
+	}
+	for _, m := range s10RE.FindAllString(text, -1) {
+		if validS10(m) {
+			add("postal", m)
+		}
+	}
+	for _, m := range trackingContextRE.FindAllStringSubmatch(text, -1) {
+		number := strings.ToUpper(m[1])
+		if digits(number) == "" || seen[number] {
+			continue
+		}
+		add(guessCarrier(number), number)
+	}
+	return numbers
+}
+
+// validUPS checks the check digit of a UPS number: the letters count as (index+2) modulo 10,
+// the digits in even positions are doubled.
+func validUPS(s string) bool {
+	sum := 0
+	for i, c := range s[2:17] {
+		v := int(c - '0')
+		if c >= 'A' && c <= 'Z' {
+			v = (int(c-'A') + 2) % 10
+		}
+		if i%2 == 1 {
+			v *= 2
+		}
+		sum += v
+	}
+	return (10-sum%10)%10 == int(s[17]-'0')
+}
+
+// validS10 checks the check digit of a S10 number, computed with the weights 8 6 4 2 3 5 9 7.
+func validS10(s string) bool {
+	weights := [8]int{8, 6, 4, 2, 3, 5, 9, 7}
+	sum := 0
+	for i, w := range weights {
+		sum += w * int(s[2+i]-'0')
+	}
+	check := 11 - sum%11
+	switch check {
+	case 10:
+		check = 0
+	case 11:
+		check = 5
+	}
+	return check == int(s[10]-'0')
+}
+
+func guessCarrier(number string) string {
+	if digits(number) != number {
+		return "unknown"
+	}
+	switch {
+	case len(number) == 12 || len(number) == 15:
+		return "fedex"
+	case len(number) >= 20 && len(number) <= 22 && number[0] == '9':
+		return "usps"
+	case len(number) == 10:
+		return "dhl"
+	}
+	return "unknown"
+}
+
+// invoice and order references
+
+var referenceRE = regexp.MustCompile(`(?i)(?:^|[^\p{L}])(purchase order|bon de commande|p\.o\.|po|invoice|inv|facture|rechnung|сч[её]т|order|commande|bestellung|заказ)[\s.:#№-]+(?:(?:no|nr|n°|num(?:ber|éro)?|ref(?:erence)?|№)\.?\s*[:#]?\s*)?([A-Z0-9][A-Z0-9\-/_.]{2,24}[A-Z0-9])`)
+
+var referenceKinds = map[string]string{
+	"purchase order": "purchase-order", "bon de commande": "purchase-order", "p.o.": "purchase-order", "po": "purchase-order",
+	"invoice": "invoice", "inv": "invoice", "facture": "invoice", "rechnung": "invoice", "счет": "invoice", "счёт": "invoice",
+	"order": "order", "commande": "order", "bestellung": "order", "заказ": "order",
+}
+
+func findReferences(text string) (refs []models.Reference) {
+	seen := make(map[models.Reference]bool)
+	for _, m := range referenceRE.FindAllStringSubmatch(text, -1) {
+		if digits(m[2]) == "" {
+			continue
+		}
+		ref := models.Reference{Kind: referenceKinds[strings.ToLower(m[1])], Value: m[2]}
+		if !seen[ref] && len(refs) < maxEntities {
+			seen[ref] = true
+			refs = append(refs, ref)
+		}
+	}
+	return refs
+}
+
+func limit(values []string) []string {
+	if len(values) > maxEntities {
+		return values[:maxEntities]
+	}
+	return values
+}
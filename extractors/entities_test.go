@@ -0,0 +1,200 @@
+package extractors
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestKeccak256(t *testing.T) {
+	cases := []struct {
+		input string
+		hash  string
+	}{
+		{"", "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470"},
+		{"abc", "4e03657aea45a94fc7d47ba826c8d667c0d1e6e33a64a036ec44f58fa12d6c45"},
+		{"The quick brown fox jumps over the lazy dog", "4d741b6f1eb29cb2a9b9911c82f56fa8d73b04959d3d9d222895df6c0b28aa15"},
+	}
+	for _, c := range cases {
+		if hash := hex.EncodeToString(keccak256([]byte(c.input))); hash != c.hash {
+			t.Errorf("keccak256(%q): expected %s, got %s", c.input, c.hash, hash)
+		}
+	}
+}
+
+func TestNormalizeIBAN(t *testing.T) {
+	cases := []struct {
+		candidate string
+		iban      string
+		valid     bool
+	}{
+		{"GB82 WEST 1234 5698 7654 32", "GB82WEST12345698765432", true},
+		{"DE89370400440532013000", "DE89370400440532013000", true},
+		{"FR14 2004 1010 0505 0001 3M02 606", "FR1420041010050500013M02606", true},
+		{"NL91 ABNA 0417 1643 00", "NL91ABNA0417164300", true},
+		{"BE68 5390 0754 7034", "BE68539007547034", true},
+		{"CH93 0076 2011 6238 5295 7", "CH9300762011623852957", true},
+		{"NO93 8601 1117 947", "NO9386011117947", true},
+		{"MT84 MALT 0110 0001 2345 MTLC AST0 01S", "MT84MALT011000012345MTLCAST001S", true},
+		// the word that follows the IBAN
+		{"DE89 3704 0044 0532 0130 00 SEPA", "DE89370400440532013000", true},
+		{"GB82 WEST 1234 5698 7654 33", "GB82WEST12345698765433", false},
+		{"GB28 WEST 1234 5698 7654 32", "GB28WEST12345698765432", false},
+		{"DE89 3704 0044 0532 0130 0", "", false},
+		{"XX89 3704 0044 0532 0130 00", "", false},
+	}
+	for _, c := range cases {
+		iban, valid := NormalizeIBAN(c.candidate)
+		if valid != c.valid || (c.iban != "" && iban != c.iban) {
+			t.Errorf("NormalizeIBAN(%q): expected %q %t, got %q %t", c.candidate, c.iban, c.valid, iban, valid)
+		}
+	}
+}
+
+func TestWallets(t *testing.T) {
+	cases := []struct {
+		address string
+		valid   func(string) bool
+		ok      bool
+	}{
+		// P2PKH: the genesis block, P2SH: BIP 13
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", validBase58Check, true},
+		{"1BvBMSEYstWetqTFn5Au4m4GFg7xJaNVN2", validBase58Check, true},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy", validBase58Check, true},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb", validBase58Check, false},
+		{"3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLz", validBase58Check, false},
+		{"1A1zP1eP5QGefi2DMPTfTL5SLmv7Divf0a", validBase58Check, false},
+		// BIP 173
+		{"BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", validBech32, true},
+		{"bc1qrp33g0q5c5txsp9arysrx4k6zdkfs4nce4xj0gdcccefvpysxf3qccfmv3", validBech32, true},
+		{"bc1qar0srrr7xfkvy5l643lydnw9re59gtzzwf5mdq", validBech32, true},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t5", validBech32, false},
+		{"bc1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4", validBech32, false},
+		// BIP 350
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vqzk5jj0", validBech32, true},
+		{"BC1SW50QGDZ25J", validBech32, true},
+		// a bech32 checksum for the versions 1 and 2, a bech32m checksum for the version 0
+		{"bc1p0xlxvlhemja6c4dqv22uapctqupfhlxm9h8z3k2e72q4k9hcz7vq5zuyut", validBech32, false},
+		{"bc1zw508d6qejxtdg4y5r3zarvaryvg6kdaj", validBech32, false},
+		{"bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kemeawh", validBech32, false},
+		// EIP-55
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", validEIP55, true},
+		{"fB6916095ca1df60bB79Ce92cE3Ea74c37c5d359", validEIP55, true},
+		{"dbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB", validEIP55, true},
+		{"D1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb", validEIP55, true},
+		{"5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD", validEIP55, false},
+		{"5AAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", validEIP55, false},
+	}
+	for _, c := range cases {
+		if ok := c.valid(c.address); ok != c.ok {
+			t.Errorf("%s: expected %t, got %t", c.address, c.ok, ok)
+		}
+	}
+
+	text := `Send 0.05 BTC to 1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa or BC1QW508D6QEJXTDG4Y5R3ZARVARY0C5XW7KV8F3T4.
+ETH: 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed, 0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD, 0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae.
+Not an address: 1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNb`
+	expected := []models.Wallet{
+		{Currency: "BTC", Address: "1A1zP1eP5QGefi2DMPTfTL5SLmv7DivfNa", Checksummed: true},
+		{Currency: "BTC", Address: "bc1qw508d6qejxtdg4y5r3zarvary0c5xw7kv8f3t4", Checksummed: true},
+		{Currency: "ETH", Address: "0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed", Checksummed: true},
+		{Currency: "ETH", Address: "0xde0b295669a9fd93d5f28d9ec85e40f4cb697bae", Checksummed: false},
+	}
+	if wallets := findWallets(text); !reflect.DeepEqual(wallets, expected) {
+		t.Errorf("expected %+v, got %+v", expected, wallets)
+	}
+}
+
+func TestTrackingNumbers(t *testing.T) {
+	cases := []struct {
+		number string
+		valid  func(string) bool
+		ok     bool
+	}{
+		{"1Z999AA10123456784", validUPS, true},
+		{"1Z5R89390357567127", validUPS, true},
+		{"1Z879E930346834440", validUPS, true},
+		{"1Z410E7W0392751591", validUPS, true},
+		{"1ZXX3150YW44070023", validUPS, true},
+		{"1Z999AA10123456785", validUPS, false},
+		{"1Z5R89390357567128", validUPS, false},
+		{"RR123456785FR", validS10, true},
+		{"RA473124829GB", validS10, true},
+		// the check digit 10 is written 0, and 11 is written 5
+		{"EE000000005US", validS10, true},
+		{"LX000000080DE", validS10, true},
+		{"RR123456784FR", validS10, false},
+		{"RA473124828GB", validS10, false},
+	}
+	for _, c := range cases {
+		if ok := c.valid(c.number); ok != c.ok {
+			t.Errorf("%s: expected %t, got %t", c.number, c.ok, ok)
+		}
+	}
+
+	text := "UPS 1Z999AA10123456784 and 1Z999AA10123456785, La Poste RR123456785FR and RR123456784FR.\n" +
+		"FedEx tracking number: 123456789012, DHL tracking: 1234567890, USPS tracking #9400111899223197428490"
+	expected := []models.TrackingNumber{
+		{Carrier: "ups", Number: "1Z999AA10123456784"},
+		{Carrier: "postal", Number: "RR123456785FR"},
+		{Carrier: "fedex", Number: "123456789012"},
+		{Carrier: "dhl", Number: "1234567890"},
+		{Carrier: "usps", Number: "9400111899223197428490"},
+	}
+	if numbers := findTrackingNumbers(text); !reflect.DeepEqual(numbers, expected) {
+		t.Errorf("expected %+v, got %+v", expected, numbers)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	cases := []struct {
+		number string
+		crypto bool
+		value  float64
+		ok     bool
+	}{
+		{"1000", false, 1000, true},
+		{"1,000", false, 1000, true},
+		{"1.000", false, 1000, true},
+		{"1,000.50", false, 1000.5, true},
+		{"1.000,50", false, 1000.5, true},
+		{"1.000,500", false, 1000.5, true},
+		{"1,000,000", false, 1000000, true},
+		{"1.000.000", false, 1000000, true},
+		{"1,000,000.75", false, 1000000.75, true},
+		{"12 345,67", false, 12345.67, true},
+		{"1'234.50", false, 1234.5, true},
+		{"1,5", false, 1.5, true},
+		{"9.99", false, 9.99, true},
+		{"0,500", false, 0.5, true},
+		// three decimals are common for the cryptocurrencies
+		{"1.000", true, 1, true},
+		{"0.005", true, 0.005, true},
+		{"1,000.5", true, 1000.5, true},
+		{"0", false, 0, false},
+		{"0.00", false, 0, false},
+	}
+	for _, c := range cases {
+		value, ok := parseAmount(c.number, c.crypto)
+		if ok != c.ok || value != c.value {
+			t.Errorf("parseAmount(%q, %t): expected %v %t, got %v %t", c.number, c.crypto, c.value, c.ok, value, ok)
+		}
+	}
+}
+
+func TestFindAmounts(t *testing.T) {
+	text := "Please wire $1,000.50 today, then 1.234,56 € and EUR 2,500. The fee is 0.05 BTC, " +
+		"or 1.500 BTC for the full amount. Again: US$1,000.50."
+	expected := []models.Amount{
+		{Text: "$1,000.50", Currency: "USD", Value: 1000.5},
+		{Text: "EUR 2,500", Currency: "EUR", Value: 2500},
+		{Text: "1.234,56 €", Currency: "EUR", Value: 1234.56},
+		{Text: "0.05 BTC", Currency: "BTC", Value: 0.05},
+		{Text: "1.500 BTC", Currency: "BTC", Value: 1.5},
+	}
+	if amounts := findAmounts(text); !reflect.DeepEqual(amounts, expected) {
+		t.Errorf("expected %+v, got %+v", expected, amounts)
+	}
+}
@@ -0,0 +1,243 @@
+package extractors
+
+import (
+	"mime"
+	"path"
+	"strings"
+	"unicode"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// minWhitespaceRun is the length of the whitespace runs that are used to push the real extension out of sight.
+const minWhitespaceRun = 5
+
+// dangerousExtensions maps the extensions of files that run code when opened to the kind of file.
+var dangerousExtensions = map[string]string{
+	"exe": "executable", "com": "executable", "pif": "executable", "scr": "executable",
+	"cpl": "executable", "dll": "executable", "msi": "installer", "msp": "installer",
+	"lnk": "shortcut", "url": "shortcut", "scf": "shortcut",
+	"hta": "hta",
+	"js":  "script", "jse": "script", "vbs": "script", "vbe": "script", "wsf": "script",
+	"wsh": "script", "ps1": "script", "bat": "script", "cmd": "script", "jar": "script",
+	"reg": "script",
+	"iso": "disk image", "img": "disk image", "vhd": "disk image", "vhdx": "disk image",
+	"one": "onenote",
+	"chm": "chm",
+}
+
+// dangerousTypes maps the MIME types of files that run code when opened to the kind of file.
+var dangerousTypes = map[string]string{
+	"application/x-ms-shortcut":   "shortcut",
+	"application/hta":             "hta",
+	"application/x-iso9660-image": "disk image",
+	"application/onenote":         "onenote",
+	"application/vnd.ms-htmlhelp": "chm",
+}
+
+// extensionFamilies maps the extensions of common documents to the family of their MIME type.
+// Only the formats that are reliably recognized by their content are listed.
+var extensionFamilies = map[string]string{
+	"pdf": "pdf",
+	"doc": "document", "docx": "document", "docm": "document", "xls": "document", "xlsx": "document",
+	"xlsm": "document", "ppt": "document", "pptx": "document", "odt": "document", "ods": "document",
+	"odp": "document", "rtf": "document",
+	"jpg": "image", "jpeg": "image", "png": "image", "gif": "image", "bmp": "image", "tif": "image",
+	"tiff": "image", "webp": "image",
+	"zip": "archive", "rar": "archive", "7z": "archive", "tar": "archive", "gz": "archive",
+	"tgz": "archive", "bz2": "archive", "xz": "archive",
+	"mp3": "audio", "wav": "audio", "ogg": "audio",
+	"mp4": "video", "avi": "video", "mov": "video", "mkv": "video",
+	"exe": "executable", "dll": "executable", "scr": "executable", "com": "executable", "pif": "executable",
+	"cpl": "executable",
+	"lnk": "shortcut", "one": "onenote", "chm": "chm", "iso": "disk image",
+}
+
+// AnalyseFilename looks for the tricks used to disguise the real type of a file:
+// double extensions, bidi control characters, whitespace padding, and contradictions between
+// the extension, the type inferred from the content and the type reported by the sender.
+// It returns nil when nothing is suspicious.
+func AnalyseFilename(filename, inferredType, reportedType string) *models.FilenameAnalysis {
+	analysis := new(models.FilenameAnalysis)
+	name := path.Base(strings.Replace(filename, "\\", "/", -1))
+
+	if strings.IndexFunc(name, isBidiControl) >= 0 {
+		analysis.BidiControl = true
+		analysis.DisplayedName = displayedName(name)
+	}
+	analysis.WhitespacePadding = hasWhitespaceRun(name)
+
+	// extensions are computed on the name without the bidi tricks
+	clean := strings.Map(func(r rune) rune {
+		if isBidiControl(r) {
+			return -1
+		}
+		return r
+	}, name)
+	ext := extension(clean)
+	if ext != "" {
+		// the extension is lowercased, so the name is cut at the last dot
+		prev := extension(strings.TrimRightFunc(clean[:strings.LastIndexByte(clean, '.')], isSpace))
+		if prev != "" && extensionFamilies[prev] != "" && dangerousExtensions[ext] != "" && extensionFamilies[prev] != extensionFamilies[ext] {
+			analysis.DoubleExtension = true
+		}
+	}
+
+	inferredType = mediaType(inferredType)
+	reportedType = mediaType(reportedType)
+	inferred := typeFamily(inferredType)
+	if family := extensionFamilies[ext]; family != "" && inferred != "" && !compatibleFamilies(family, inferred) {
+		analysis.ExtensionMismatch = true
+	}
+	if reported := typeFamily(reportedType); reported != "" && inferred != "" && !compatibleFamilies(reported, inferred) {
+		analysis.ReportedTypeMismatch = true
+	}
+
+	switch {
+	case dangerousTypes[inferredType] != "":
+		analysis.DangerousType = dangerousTypes[inferredType]
+	case IsExecutable(inferredType):
+		analysis.DangerousType = "executable"
+	case dangerousExtensions[ext] != "":
+		analysis.DangerousType = dangerousExtensions[ext]
+	}
+
+	if *analysis == (models.FilenameAnalysis{}) {
+		return nil
+	}
+	return analysis
+}
+
+// IsDangerous tells whether the analysis reports a file that runs code when opened.
+func IsDangerous(analysis *models.FilenameAnalysis) bool {
+	return analysis != nil && analysis.DangerousType != ""
+}
+
+func extension(name string) string {
+	idx := strings.LastIndexByte(name, '.')
+	if idx < 0 || idx == len(name)-1 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(name[idx+1:]))
+}
+
+func mediaType(t string) string {
+	if t == "" {
+		return ""
+	}
+	mt, _, err := mime.ParseMediaType(t)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(t))
+	}
+	return mt
+}
+
+// typeFamily groups MIME types whose differences are not meaningful. It returns an empty string
+// for generic or unknown types.
+func typeFamily(mimetype string) string {
+	if kind := dangerousTypes[mimetype]; kind != "" {
+		return kind
+	}
+	if IsExecutable(mimetype) {
+		return "executable"
+	}
+	switch mimetype {
+	case "", "application/octet-stream", "application/binary", "application/unknown", "text/plain":
+		return ""
+	case "application/pdf", "application/x-pdf":
+		return "pdf"
+	case "application/msword", "application/rtf", "text/rtf":
+		return "document"
+	case "application/zip", "application/x-zip-compressed", "application/x-rar-compressed",
+		"application/x-rar", "application/vnd.rar", "application/x-tar", "application/gzip",
+		"application/x-gzip", "application/x-bzip2", "application/x-xz", "application/x-7z-compressed":
+		return "archive"
+	}
+	if strings.HasPrefix(mimetype, "application/vnd.openxmlformats-officedocument.") ||
+		strings.HasPrefix(mimetype, "application/vnd.ms-") ||
+		strings.HasPrefix(mimetype, "application/vnd.oasis.opendocument.") {
+		return "document"
+	}
+	switch mimetype[:strings.IndexByte(mimetype+"/", '/')] {
+	case "image":
+		if mimetype == "image/svg+xml" {
+			return ""
+		}
+		return "image"
+	case "audio":
+		return "audio"
+	case "video":
+		return "video"
+	}
+	return ""
+}
+
+func compatibleFamilies(f1, f2 string) bool {
+	if f1 == f2 {
+		return true
+	}
+	// office documents are zip files, and may not be recognized as documents
+	return (f1 == "document" && f2 == "archive") || (f1 == "archive" && f2 == "document")
+}
+
+func isBidiControl(r rune) bool {
+	switch {
+	case r >= '\u202a' && r <= '\u202e', r >= '\u2066' && r <= '\u2069':
+		return true
+	case r == '\u200e', r == '\u200f', r == '\u061c':
+		return true
+	}
+	return false
+}
+
+func isSpace(r rune) bool {
+	// braille blank and hangul fillers render as blanks too
+	return unicode.IsSpace(r) || r == '\u2800' || r == '\u3164' || r == '\u115f' || r == '\u1160'
+}
+
+func hasWhitespaceRun(name string) bool {
+	run := 0
+	for _, r := range name {
+		if isSpace(r) {
+			run++
+			if run >= minWhitespaceRun {
+				return true
+			}
+		} else {
+			run = 0
+		}
+	}
+	return false
+}
+
+// displayedName approximates the rendering of a name that contains bidi overrides: the text after a
+// right-to-left override is reversed, up to the matching pop directional formatting.
+func displayedName(name string) string {
+	var b strings.Builder
+	var reversed []rune
+	depth := 0
+	flush := func() {
+		for i := len(reversed) - 1; i >= 0; i-- {
+			b.WriteRune(reversed[i])
+		}
+		reversed = reversed[:0]
+	}
+	for _, r := range name {
+		switch {
+		case r == '\u202e':
+			depth++
+		case r == '\u202c' && depth > 0:
+			depth--
+			if depth == 0 {
+				flush()
+			}
+		case isBidiControl(r):
+		case depth > 0:
+			reversed = append(reversed, r)
+		default:
+			b.WriteRune(r)
+		}
+	}
+	flush()
+	return b.String()
+}
@@ -0,0 +1,93 @@
+package extractors
+
+import (
+	"testing"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestAnalyseFilename(t *testing.T) {
+	cases := []struct {
+		filename string
+		inferred string
+		reported string
+		analysis *models.FilenameAnalysis
+	}{
+		{"report.pdf", "application/pdf", "application/pdf; name=report.pdf", nil},
+		{"my.photo.jpg", "image/jpeg", "", nil},
+		{"notes.txt.pdf", "application/pdf", "", nil},
+		{"statement.docx", "application/zip", "application/vnd.openxmlformats-officedocument.wordprocessingml.document", nil},
+		{"report.pdf", "text/plain", "application/octet-stream", nil},
+		{"drawing.svg", "image/svg+xml", "", nil},
+
+		// double extensions
+		{"invoice.pdf.exe", "application/x-dosexec", "application/pdf",
+			&models.FilenameAnalysis{DoubleExtension: true, ReportedTypeMismatch: true, DangerousType: "executable"}},
+		{"SHIPPING.PDF.EXE", "", "", &models.FilenameAnalysis{DoubleExtension: true, DangerousType: "executable"}},
+		{"payload.pdf.js", "text/plain", "", &models.FilenameAnalysis{DoubleExtension: true, DangerousType: "script"}},
+		{`C:\Users\bob\invoice.pdf.lnk`, "application/x-ms-shortcut", "",
+			&models.FilenameAnalysis{DoubleExtension: true, DangerousType: "shortcut"}},
+		{"invoice.pdf.exe ", "", "", &models.FilenameAnalysis{DoubleExtension: true, DangerousType: "executable"}},
+		{"photos.zip.scr", "", "", &models.FilenameAnalysis{DoubleExtension: true, DangerousType: "executable"}},
+		{"backup.zip.iso", "application/x-iso9660-image", "",
+			&models.FilenameAnalysis{DoubleExtension: true, DangerousType: "disk image"}},
+		// the same family
+		{"setup.exe.exe", "", "", &models.FilenameAnalysis{DangerousType: "executable"}},
+
+		// whitespace padding
+		{"invoice.pdf            .exe", "", "", &models.FilenameAnalysis{DoubleExtension: true, WhitespacePadding: true, DangerousType: "executable"}},
+		{"invoice.pdf\u3164\u3164\u3164\u3164\u3164.exe", "", "",
+			&models.FilenameAnalysis{DoubleExtension: true, WhitespacePadding: true, DangerousType: "executable"}},
+		{"my    file.txt", "", "", nil},
+
+		// right-to-left override
+		{"Annual\u202efdp.exe", "application/x-dosexec", "application/pdf",
+			&models.FilenameAnalysis{BidiControl: true, DisplayedName: "Annualexe.pdf", ReportedTypeMismatch: true, DangerousType: "executable"}},
+		{"photo_\u202egnp.js", "", "", &models.FilenameAnalysis{BidiControl: true, DisplayedName: "photo_sj.png", DangerousType: "script"}},
+		{"invoice\u202excod\u202c.lnk", "", "", &models.FilenameAnalysis{BidiControl: true, DisplayedName: "invoicedocx.lnk", DangerousType: "shortcut"}},
+		// the bidi characters are removed before looking for the extensions
+		{"report.pdf\u200f.exe", "", "", &models.FilenameAnalysis{BidiControl: true, DisplayedName: "report.pdf.exe", DoubleExtension: true, DangerousType: "executable"}},
+
+		// content and extension
+		{"image.jpg", "application/x-dosexec", "image/jpeg",
+			&models.FilenameAnalysis{ExtensionMismatch: true, ReportedTypeMismatch: true, DangerousType: "executable"}},
+		{"invoice.pdf", "application/x-iso9660-image", "", &models.FilenameAnalysis{ExtensionMismatch: true, DangerousType: "disk image"}},
+		{"notes.one", "application/onenote", "", &models.FilenameAnalysis{DangerousType: "onenote"}},
+		{"help.chm", "application/vnd.ms-htmlhelp", "", &models.FilenameAnalysis{DangerousType: "chm"}},
+		{"data.bin", "application/x-msdownload", "application/octet-stream", &models.FilenameAnalysis{DangerousType: "executable"}},
+	}
+	for _, c := range cases {
+		analysis := AnalyseFilename(c.filename, c.inferred, c.reported)
+		switch {
+		case analysis == nil && c.analysis == nil:
+		case analysis == nil || c.analysis == nil || *analysis != *c.analysis:
+			t.Errorf("%q: expected %+v, got %+v", c.filename, c.analysis, analysis)
+		}
+		if IsDangerous(analysis) != (c.analysis != nil && c.analysis.DangerousType != "") {
+			t.Errorf("%q: IsDangerous is %t", c.filename, IsDangerous(analysis))
+		}
+	}
+}
+
+func TestTypeFamily(t *testing.T) {
+	cases := map[string]string{
+		"application/pdf":                         "pdf",
+		"application/x-dosexec":                   "executable",
+		"application/x-ms-shortcut":               "shortcut",
+		"application/vnd.ms-excel":                "document",
+		"application/vnd.oasis.opendocument.text": "document",
+		"application/x-7z-compressed":             "archive",
+		"image/png":                               "image",
+		"image/svg+xml":                           "",
+		"video/mp4":                               "video",
+		"text/plain":                              "",
+		"application/octet-stream":                "",
+		"text/html":                               "",
+		"":                                        "",
+	}
+	for mimetype, family := range cases {
+		if f := typeFamily(mimetype); f != family {
+			t.Errorf("typeFamily(%q) = %q, want %q", mimetype, f, family)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package extractors
+
+import (
+	"bytes"
+	"fmt"
+	"net/mail"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+// HeaderField is a header field of a mail, as it appears in the raw mail.
+type HeaderField struct {
+	// Name has its original casing
+	Name string
+	// Value is unfolded, but not decoded
+	Value string
+	// EightBit is set when the raw field contains bytes outside of ASCII
+	EightBit bool
+}
+
+// ReadHeaderFields reads the header fields of a raw mail, in their original order. Only the lines
+// before the first blank line are read.
+func ReadHeaderFields(data []byte) []HeaderField {
+	var fields []HeaderField
+	for idx := 0; len(data) > 0; idx++ {
+		var line string
+		if eol := bytes.IndexByte(data, '\n'); eol >= 0 {
+			line, data = string(data[:eol]), data[eol+1:]
+		} else {
+			line, data = string(data), nil
+		}
+		line = strings.TrimSuffix(line, "\r")
+		if idx == 0 && strings.HasPrefix(line, "From ") {
+			// mbox separator
+			continue
+		}
+		if line == "" {
+			break
+		}
+		eightBit := hasEightBit(line)
+		if line[0] == ' ' || line[0] == '\t' {
+			if len(fields) > 0 {
+				last := &fields[len(fields)-1]
+				last.Value += " " + strings.TrimSpace(line)
+				last.EightBit = last.EightBit || eightBit
+			}
+			continue
+		}
+		colon := strings.Index(line, ":")
+		if colon <= 0 {
+			continue
+		}
+		fields = append(fields, HeaderField{
+			Name:     strings.TrimSpace(line[:colon]),
+			Value:    strings.TrimSpace(line[colon+1:]),
+			EightBit: eightBit,
+		})
+	}
+	return fields
+}
+
+func hasEightBit(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= 0x80 {
+			return true
+		}
+	}
+	return false
+}
+
+// HeaderNames returns the names of the fields, in order.
+func HeaderNames(fields []HeaderField) []string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+	return names
+}
+
+func fieldValues(fields []HeaderField, name string) []string {
+	var values []string
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			values = append(values, f.Value)
+		}
+	}
+	return values
+}
+
+// mandatoryHeaders are required by RFC 5322, except Message-ID that is only recommended but is
+// added by every legitimate mail software.
+var mandatoryHeaders = []string{"From", "Date", "Message-ID"}
+
+// singletonHeaders may appear at most once (RFC 5322 section 3.6, RFC 2045).
+var singletonHeaders = []string{
+	"Date", "From", "Sender", "Reply-To", "To", "Cc", "Bcc", "Message-ID", "In-Reply-To",
+	"References", "Subject", "MIME-Version", "Content-Type", "Content-Transfer-Encoding",
+}
+
+// rfcDateRE matches the date-time format of RFC 5322, with the obsolete time zones.
+var rfcDateRE = regexp.MustCompile(`^(?:(?:Mon|Tue|Wed|Thu|Fri|Sat|Sun),\s*)?\d{1,2}\s+(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)\s+\d{4}\s+\d{2}:\d{2}(?::\d{2})?\s+(?:[+-]\d{4}|UT|GMT|[ECMP][SD]T|[A-IK-Za-ik-z])(?:\s*\(.*\))?$`)
+
+var messageIDDomainRE = regexp.MustCompile(`@([^>@\s]+)>?\s*$`)
+
+// Tolerances on the Date header, compared to the time the mail was received
+var (
+	MaxDateFuture = time.Hour
+	MaxDateAge    = 7 * 24 * time.Hour
+)
+
+// AnalyseHeaders looks for anomalies in the header fields. received is the time the mail was
+// received, senderDomains are the domains that are allowed in the Message-ID, and mailer is the
+// fingerprint of the software that wrote the mail (or nil).
+func AnalyseHeaders(fields []HeaderField, received time.Time, senderDomains []string, mailer *models.Mailer) *models.HeaderAnomalies {
+	a := new(models.HeaderAnomalies)
+
+	for _, name := range mandatoryHeaders {
+		if len(fieldValues(fields, name)) == 0 {
+			a.MissingHeaders = append(a.MissingHeaders, name)
+			a.Anomalies = append(a.Anomalies, "missing "+name)
+		}
+	}
+	for _, name := range singletonHeaders {
+		if n := len(fieldValues(fields, name)); n > 1 {
+			a.DuplicateHeaders = append(a.DuplicateHeaders, name)
+			a.Anomalies = append(a.Anomalies, fmt.Sprintf("%d %s headers", n, name))
+		}
+	}
+	seen := make(map[string]bool)
+	for _, f := range fields {
+		if f.EightBit && !seen[strings.ToLower(f.Name)] {
+			seen[strings.ToLower(f.Name)] = true
+			a.EightBitHeaders = append(a.EightBitHeaders, f.Name)
+			a.Anomalies = append(a.Anomalies, "8-bit data in "+f.Name)
+		}
+	}
+
+	if dates := fieldValues(fields, "Date"); len(dates) > 0 {
+		date := dates[0]
+		if !rfcDateRE.MatchString(date) {
+			a.InvalidDate = true
+			a.Anomalies = append(a.Anomalies, "non-RFC 5322 Date: "+date)
+		}
+		d, err := mail.ParseDate(date)
+		if err == nil && !received.IsZero() {
+			if d.Sub(received) > MaxDateFuture {
+				a.DateInFuture = true
+				a.Anomalies = append(a.Anomalies, fmt.Sprintf("Date is %s in the future", d.Sub(received).Round(time.Minute)))
+			} else if received.Sub(d) > MaxDateAge {
+				a.DateTooOld = true
+				a.Anomalies = append(a.Anomalies, fmt.Sprintf("Date is %s in the past", received.Sub(d).Round(time.Hour)))
+			}
+		}
+	}
+
+	if ids := fieldValues(fields, "Message-ID"); len(ids) > 0 {
+		if m := messageIDDomainRE.FindStringSubmatch(ids[0]); m != nil {
+			a.MessageIDDomain = strings.ToLower(strings.TrimSuffix(m[1], "."))
+			matching := false
+			for _, d := range senderDomains {
+				if d != "" && utils.SameDomain(d, a.MessageIDDomain) {
+					matching = true
+				}
+			}
+			if !matching && len(senderDomains) > 0 {
+				a.MessageIDDomainMismatch = true
+				a.Anomalies = append(a.Anomalies, "Message-ID domain "+a.MessageIDDomain+" does not match the sender")
+			}
+		}
+	}
+	if mailer != nil && mailer.Inconsistent {
+		a.MailerInconsistent = true
+		a.Anomalies = append(a.Anomalies, "X-Mailer/User-Agent inconsistent with the headers: "+mailer.Declared)
+	}
+	return a
+}
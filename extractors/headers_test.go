@@ -0,0 +1,183 @@
+package extractors
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestReadHeaderFields(t *testing.T) {
+	raw := "From sender@example.com Mon Jan  7 10:00:00 2019\r\n" +
+		"Received: from mx.example.com\r\n" +
+		"\tby mx.example.org; Mon, 7 Jan 2019 10:00:00 +0100\r\n" +
+		"Subject: =?utf-8?q?caf=C3=A9?=\r\n" +
+		"X-Name: caf\xc3\xa9\r\n" +
+		"not a header\r\n" +
+		"To: <john@example.org>\r\n" +
+		"\r\n" +
+		"Body-Line: not a header\r\n"
+	want := []HeaderField{
+		{Name: "Received", Value: "from mx.example.com by mx.example.org; Mon, 7 Jan 2019 10:00:00 +0100"},
+		{Name: "Subject", Value: "=?utf-8?q?caf=C3=A9?="},
+		{Name: "X-Name", Value: "caf\xc3\xa9", EightBit: true},
+		{Name: "To", Value: "<john@example.org>"},
+	}
+	if fields := ReadHeaderFields([]byte(raw)); !reflect.DeepEqual(fields, want) {
+		t.Errorf("fields = %+v, want %+v", fields, want)
+	}
+
+	// a header without body, and without a final newline
+	fields := ReadHeaderFields([]byte("From: a@example.com\nSubject: hello"))
+	if len(fields) != 2 || fields[1].Name != "Subject" || fields[1].Value != "hello" {
+		t.Errorf("fields without final newline = %+v", fields)
+	}
+	if fields := ReadHeaderFields(nil); len(fields) != 0 {
+		t.Errorf("fields of an empty mail = %+v", fields)
+	}
+}
+
+func headerFields(raw string) []HeaderField {
+	return ReadHeaderFields([]byte(strings.Replace(raw, "\n", "\r\n", -1)))
+}
+
+func TestAnalyseHeaders(t *testing.T) {
+	received := time.Date(2019, 1, 7, 10, 0, 0, 0, time.UTC)
+	valid := `From: John <john@example.com>
+To: jane@example.org
+Date: Mon, 7 Jan 2019 10:58:12 +0100
+Message-ID: <123@mail.example.com>
+Subject: hello
+`
+	a := AnalyseHeaders(headerFields(valid), received, []string{"example.com"}, nil)
+	if len(a.Anomalies) != 0 || a.MessageIDDomain != "mail.example.com" {
+		t.Errorf("anomalies of a valid mail = %+v", a)
+	}
+
+	cases := []struct {
+		name    string
+		raw     string
+		mailer  *models.Mailer
+		check   func(*models.HeaderAnomalies) bool
+		anomaly string
+	}{
+		{
+			"missing headers", "Subject: hello\n", nil,
+			func(a *models.HeaderAnomalies) bool {
+				return reflect.DeepEqual(a.MissingHeaders, []string{"From", "Date", "Message-ID"})
+			},
+			"missing From",
+		},
+		{
+			"duplicate", valid + "Subject: again\nsubject: and again\n", nil,
+			func(a *models.HeaderAnomalies) bool {
+				return reflect.DeepEqual(a.DuplicateHeaders, []string{"Subject"})
+			},
+			"3 Subject headers",
+		},
+		{
+			"8-bit", valid + "X-Comment: d\xc3\xa9j\xc3\xa0\n", nil,
+			func(a *models.HeaderAnomalies) bool {
+				return reflect.DeepEqual(a.EightBitHeaders, []string{"X-Comment"})
+			},
+			"8-bit data in X-Comment",
+		},
+		{
+			"invalid date", strings.Replace(valid, "Mon, 7 Jan 2019 10:58:12 +0100", "2019-01-07 10:58:12", 1), nil,
+			func(a *models.HeaderAnomalies) bool { return a.InvalidDate },
+			"non-RFC 5322 Date: 2019-01-07 10:58:12",
+		},
+		{
+			"future date", strings.Replace(valid, "7 Jan 2019", "9 Jan 2019", 1), nil,
+			func(a *models.HeaderAnomalies) bool { return a.DateInFuture && !a.InvalidDate },
+			"Date is 47h58m0s in the future",
+		},
+		{
+			"old date", strings.Replace(valid, "7 Jan 2019", "7 Dec 2018", 1), nil,
+			func(a *models.HeaderAnomalies) bool { return a.DateTooOld },
+			"Date is 744h0m0s in the past",
+		},
+		{
+			"message-id domain", strings.Replace(valid, "@mail.example.com", "@bulk.example.net", 1), nil,
+			func(a *models.HeaderAnomalies) bool { return a.MessageIDDomainMismatch },
+			"Message-ID domain bulk.example.net does not match the sender",
+		},
+		{
+			"inconsistent mailer", valid, &models.Mailer{Declared: "Microsoft Outlook 16.0", Inconsistent: true},
+			func(a *models.HeaderAnomalies) bool { return a.MailerInconsistent },
+			"X-Mailer/User-Agent inconsistent with the headers: Microsoft Outlook 16.0",
+		},
+	}
+	for _, c := range cases {
+		a := AnalyseHeaders(headerFields(c.raw), received, []string{"example.com"}, c.mailer)
+		if !c.check(a) {
+			t.Errorf("%s: anomalies = %+v", c.name, a)
+		}
+		found := false
+		for _, anomaly := range a.Anomalies {
+			found = found || anomaly == c.anomaly
+		}
+		if !found {
+			t.Errorf("%s: anomalies = %q, want %q", c.name, a.Anomalies, c.anomaly)
+		}
+	}
+}
+
+func TestFingerprintMailer(t *testing.T) {
+	thunderbird := `Message-ID: <5c3a7b1e-2f4d-4e8a-9b1c-0d2e3f4a5b6c@example.com>
+Date: Mon, 7 Jan 2019 10:58:12 +0100
+MIME-Version: 1.0
+User-Agent: Mozilla/5.0 (X11; Linux x86_64; rv:60.0) Gecko/20100101 Thunderbird/60.4.0
+Content-Language: en-US
+To: jane@example.org
+From: John <john@example.com>
+Subject: hello
+Content-Type: multipart/alternative; boundary="------------A1b2C3d4E5f6G7h8I9j0K1l2"
+`
+	phpmailer := `Date: Mon, 7 Jan 2019 10:58:12 +0100
+To: jane@example.org
+From: Shop <shop@example.com>
+Subject: Your order
+Message-ID: <0123456789abcdef0123456789abcdef@example.com>
+X-Mailer: PHPMailer 6.0.6 (https://github.com/PHPMailer/PHPMailer)
+MIME-Version: 1.0
+Content-Type: multipart/alternative; boundary="b1_0123456789abcdef0123456789abcdef"
+`
+	// declared as Outlook, but laid out by Thunderbird
+	forged := strings.Replace(thunderbird, "Mozilla/5.0 (X11; Linux x86_64; rv:60.0) Gecko/20100101 Thunderbird/60.4.0", "Microsoft Outlook 16.0", 1)
+	unknown := `From: john@example.com
+To: jane@example.org
+Subject: hello
+`
+	cases := []struct {
+		name         string
+		raw          string
+		mailer       string
+		kind         string
+		inconsistent bool
+	}{
+		{"thunderbird", thunderbird, "Mozilla Thunderbird", MailerMUA, false},
+		{"phpmailer", phpmailer, "PHPMailer", MailerLibrary, false},
+		{"forged outlook", forged, "Mozilla Thunderbird", MailerMUA, true},
+		{"spam kit", unknown + "X-Mailer: Atomic Mail Sender 9.0\n", "Atomic Mail Sender", MailerSpamKit, false},
+	}
+	for _, c := range cases {
+		m := FingerprintMailer(headerFields(c.raw))
+		if m == nil {
+			t.Errorf("%s: no mailer", c.name)
+			continue
+		}
+		if m.Name != c.mailer || m.Kind != c.kind || m.Inconsistent != c.inconsistent {
+			t.Errorf("%s: mailer = %+v, want %s (%s), inconsistent %v", c.name, m, c.mailer, c.kind, c.inconsistent)
+		}
+	}
+	if m := FingerprintMailer(headerFields(unknown)); m != nil {
+		t.Errorf("unknown: mailer = %+v, want nil", m)
+	}
+	m := FingerprintMailer(headerFields(unknown + "X-Mailer: HomeMade 1.0\n"))
+	if m == nil || m.Name != "" || m.Declared != "HomeMade 1.0" {
+		t.Errorf("declared unknown mailer = %+v", m)
+	}
+}
@@ -0,0 +1,364 @@
+package extractors
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"golang.org/x/net/html"
+)
+
+var maxHiddenTexts = 20
+var maxHiddenTextLength = 200
+
+var textDomainRE = regexp.MustCompile(`(?i)\b(?:https?://)?((?:[a-z0-9](?:[a-z0-9-]*[a-z0-9])?\.)+([a-z]{2,12}))\b`)
+var cssImportRE = regexp.MustCompile(`(?i)@import\s+(?:url\()?\s*['"]?([^'")\s;]+)`)
+var cssFontFaceRE = regexp.MustCompile(`(?is)@font-face\s*{([^}]*)}`)
+var cssURLRE = regexp.MustCompile(`(?i)url\(\s*['"]?([^'")]+)['"]?\s*\)`)
+var metaRefreshRE = regexp.MustCompile(`(?i)url\s*=\s*['"]?([^'"]+)`)
+
+// file extensions that look like TLDs in anchor texts
+var notTLDs = map[string]bool{
+	"pdf": true, "doc": true, "docx": true, "xls": true, "xlsx": true, "zip": true, "jpg": true,
+	"jpeg": true, "png": true, "gif": true, "html": true, "htm": true, "php": true, "exe": true,
+	"txt": true, "aspx": true,
+}
+
+var fontHosts = map[string]bool{
+	"fonts.googleapis.com": true,
+	"fonts.gstatic.com":    true,
+	"use.typekit.net":      true,
+	"fonts.bunny.net":      true,
+	"use.fontawesome.com":  true,
+}
+
+var namedColors = map[string]string{
+	"white": "#ffffff",
+	"black": "#000000",
+	"red":   "#ff0000",
+	"green": "#008000",
+	"blue":  "#0000ff",
+	"gray":  "#808080",
+	"grey":  "#808080",
+}
+
+type htmlStyle struct {
+	hidden     bool
+	color      string
+	background string
+}
+
+type htmlAnalyser struct {
+	analysis    *models.HTMLAnalysis
+	currentForm int
+}
+
+// AnalyseHTML looks for phishing indicators in the HTML document h and adds them to analysis.
+func AnalyseHTML(h string, analysis *models.HTMLAnalysis) *models.HTMLAnalysis {
+	if analysis == nil {
+		analysis = new(models.HTMLAnalysis)
+	}
+	h = strings.TrimSpace(h)
+	if len(h) == 0 {
+		return analysis
+	}
+	root, err := html.Parse(strings.NewReader(h))
+	if err != nil {
+		return analysis
+	}
+	a := &htmlAnalyser{
+		analysis:    analysis,
+		currentForm: -1,
+	}
+	a.walk(root, htmlStyle{})
+
+	analysis.EventHandlers = distinctStrings(analysis.EventHandlers)
+	analysis.ScriptSources = distinctStrings(analysis.ScriptSources)
+	analysis.RemoteStylesheets = distinctStrings(analysis.RemoteStylesheets)
+	analysis.RemoteFonts = distinctStrings(analysis.RemoteFonts)
+	return analysis
+}
+
+func (a *htmlAnalyser) walk(n *html.Node, style htmlStyle) {
+	switch n.Type {
+	case html.TextNode:
+		a.text(n.Data, style)
+		return
+	case html.ElementNode:
+		style = elementStyle(n, style)
+		if !a.element(n, style) {
+			return
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		a.walk(c, style)
+	}
+	if n.Type == html.ElementNode && strings.ToLower(n.Data) == "form" {
+		a.currentForm = -1
+	}
+}
+
+// element records the indicators carried by the element n. It returns false when the children of n should not be visited.
+func (a *htmlAnalyser) element(n *html.Node, style htmlStyle) bool {
+	for _, attr := range n.Attr {
+		key := strings.ToLower(attr.Key)
+		if len(key) > 2 && strings.HasPrefix(key, "on") {
+			a.analysis.EventHandlers = append(a.analysis.EventHandlers, key)
+		}
+	}
+	switch strings.ToLower(n.Data) {
+	case "script":
+		a.analysis.Scripts++
+		if src := getAttr(n, "src"); src != "" {
+			a.analysis.ScriptSources = append(a.analysis.ScriptSources, src)
+		}
+		return false
+	case "style":
+		a.stylesheet(textContent(n))
+		return false
+	case "a":
+		a.anchor(n)
+	case "form":
+		a.analysis.Forms = append(a.analysis.Forms, models.HTMLForm{
+			Action:       getAttr(n, "action"),
+			Method:       strings.ToLower(getAttr(n, "method")),
+			ActionDomain: remoteHost(getAttr(n, "action")),
+		})
+		a.currentForm = len(a.analysis.Forms) - 1
+	case "input":
+		if strings.ToLower(getAttr(n, "type")) == "password" {
+			a.analysis.PasswordInputs++
+			if a.currentForm >= 0 {
+				a.analysis.Forms[a.currentForm].PasswordInputs++
+			}
+		}
+	case "iframe", "frame":
+		a.analysis.Iframes = append(a.analysis.Iframes, getAttr(n, "src"))
+	case "object":
+		a.analysis.Objects = append(a.analysis.Objects, getAttr(n, "data"))
+	case "embed":
+		a.analysis.Embeds = append(a.analysis.Embeds, getAttr(n, "src"))
+	case "meta":
+		if strings.ToLower(getAttr(n, "http-equiv")) == "refresh" {
+			content := getAttr(n, "content")
+			if m := metaRefreshRE.FindStringSubmatch(content); len(m) == 2 {
+				a.analysis.MetaRefresh = strings.TrimSpace(m[1])
+			} else {
+				a.analysis.MetaRefresh = strings.TrimSpace(content)
+			}
+		}
+	case "img":
+		if strings.HasPrefix(strings.ToLower(strings.TrimSpace(getAttr(n, "src"))), "data:") {
+			a.analysis.DataURIImages++
+		}
+	case "link":
+		href := getAttr(n, "href")
+		if remoteHost(href) == "" {
+			break
+		}
+		rel := strings.ToLower(getAttr(n, "rel"))
+		if isFontURL(href) || strings.Contains(rel, "font") {
+			a.analysis.RemoteFonts = append(a.analysis.RemoteFonts, href)
+		} else if strings.Contains(rel, "stylesheet") {
+			a.analysis.RemoteStylesheets = append(a.analysis.RemoteStylesheets, href)
+		}
+	}
+	return true
+}
+
+func (a *htmlAnalyser) text(t string, style htmlStyle) {
+	t = strings.TrimSpace(t)
+	if len(t) == 0 || len(a.analysis.HiddenText) >= maxHiddenTexts {
+		return
+	}
+	if style.hidden || (style.color != "" && style.color == style.background) {
+		if len(t) > maxHiddenTextLength {
+			t = t[:maxHiddenTextLength]
+		}
+		a.analysis.HiddenText = append(a.analysis.HiddenText, t)
+	}
+}
+
+func (a *htmlAnalyser) anchor(n *html.Node) {
+	href := getAttr(n, "href")
+	hrefHost := remoteHost(href)
+	if hrefHost == "" {
+		return
+	}
+	text := strings.TrimSpace(textContent(n))
+	for _, m := range textDomainRE.FindAllStringSubmatch(text, -1) {
+		if notTLDs[strings.ToLower(m[2])] {
+			continue
+		}
+		textDomain := strings.ToLower(m[1])
+		if !utils.SameDomain(textDomain, hrefHost) {
+			a.analysis.DeceptiveLinks = append(a.analysis.DeceptiveLinks, models.DeceptiveLink{
+				Text:       text,
+				Href:       href,
+				TextDomain: textDomain,
+				HrefDomain: hrefHost,
+			})
+			return
+		}
+	}
+}
+
+func (a *htmlAnalyser) stylesheet(css string) {
+	for _, m := range cssImportRE.FindAllStringSubmatch(css, -1) {
+		if remoteHost(m[1]) == "" {
+			continue
+		}
+		if isFontURL(m[1]) {
+			a.analysis.RemoteFonts = append(a.analysis.RemoteFonts, m[1])
+		} else {
+			a.analysis.RemoteStylesheets = append(a.analysis.RemoteStylesheets, m[1])
+		}
+	}
+	for _, face := range cssFontFaceRE.FindAllStringSubmatch(css, -1) {
+		for _, m := range cssURLRE.FindAllStringSubmatch(face[1], -1) {
+			if remoteHost(m[1]) != "" {
+				a.analysis.RemoteFonts = append(a.analysis.RemoteFonts, m[1])
+			}
+		}
+	}
+}
+
+// elementStyle computes the style of element n, given the style inherited from its parent.
+func elementStyle(n *html.Node, parent htmlStyle) htmlStyle {
+	style := parent
+	for _, attr := range n.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "hidden":
+			style.hidden = true
+		case "bgcolor":
+			style.background = normalizeColor(attr.Val)
+		case "color":
+			if strings.ToLower(n.Data) == "font" {
+				style.color = normalizeColor(attr.Val)
+			}
+		case "style":
+			for prop, value := range parseInlineStyle(attr.Val) {
+				switch prop {
+				case "display":
+					if value == "none" {
+						style.hidden = true
+					}
+				case "visibility":
+					if value == "hidden" {
+						style.hidden = true
+					}
+				case "font-size", "opacity", "max-height":
+					if isZeroLength(value) {
+						style.hidden = true
+					}
+				case "color":
+					style.color = normalizeColor(value)
+				case "background-color", "background":
+					if c := normalizeColor(strings.Fields(value + " ")[0]); c != "" {
+						style.background = c
+					}
+				}
+			}
+		}
+	}
+	return style
+}
+
+func parseInlineStyle(s string) map[string]string {
+	props := make(map[string]string)
+	for _, decl := range strings.Split(s, ";") {
+		idx := strings.Index(decl, ":")
+		if idx == -1 {
+			continue
+		}
+		prop := strings.ToLower(strings.TrimSpace(decl[:idx]))
+		value := strings.ToLower(strings.TrimSpace(decl[idx+1:]))
+		value = strings.TrimSpace(strings.TrimSuffix(value, "!important"))
+		if prop != "" && value != "" {
+			props[prop] = value
+		}
+	}
+	return props
+}
+
+func isZeroLength(v string) bool {
+	v = strings.TrimRight(v, "abcdefghijklmnopqrstuvwxyz%")
+	return strings.Trim(v, "0.") == "" && v != ""
+}
+
+func normalizeColor(c string) string {
+	c = strings.ToLower(strings.TrimSpace(c))
+	if named, ok := namedColors[c]; ok {
+		return named
+	}
+	if strings.HasPrefix(c, "#") && len(c) == 4 {
+		return string([]byte{'#', c[1], c[1], c[2], c[2], c[3], c[3]})
+	}
+	return strings.Replace(c, " ", "", -1)
+}
+
+func isFontURL(u string) bool {
+	if fontHosts[remoteHost(u)] {
+		return true
+	}
+	path := strings.ToLower(u)
+	if idx := strings.IndexAny(path, "?#"); idx != -1 {
+		path = path[:idx]
+	}
+	for _, ext := range []string{".woff", ".woff2", ".ttf", ".otf", ".eot"} {
+		if strings.HasSuffix(path, ext) {
+			return true
+		}
+	}
+	return false
+}
+
+// remoteHost returns the host of u when u is an absolute HTTP(S) URL, and an empty string otherwise.
+func remoteHost(u string) string {
+	u = strings.TrimSpace(u)
+	l := strings.ToLower(u)
+	if !strings.HasPrefix(l, "http://") && !strings.HasPrefix(l, "https://") && !strings.HasPrefix(l, "//") {
+		return ""
+	}
+	return utils.URLHost(u)
+}
+
+func getAttr(n *html.Node, key string) string {
+	for _, attr := range n.Attr {
+		if strings.ToLower(attr.Key) == key {
+			return strings.TrimSpace(attr.Val)
+		}
+	}
+	return ""
+}
+
+func textContent(n *html.Node) string {
+	var b strings.Builder
+	var f func(*html.Node)
+	f = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			f(c)
+		}
+	}
+	f(n)
+	return b.String()
+}
+
+func distinctStrings(set []string) []string {
+	if len(set) == 0 {
+		return set
+	}
+	m := make(map[string]bool)
+	res := make([]string, 0, len(set))
+	for _, s := range set {
+		if !m[s] {
+			m[s] = true
+			res = append(res, s)
+		}
+	}
+	return res
+}
@@ -0,0 +1,82 @@
+package extractors
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestAnalyseHTML(t *testing.T) {
+	h := `<html><head>
+<meta http-equiv="refresh" content="0; url=https://phish.example.net/login">
+<link rel="stylesheet" href="https://cdn.example.net/style.css">
+<link rel="stylesheet" href="https://fonts.googleapis.com/css?family=Roboto">
+<style>
+@import url("https://cdn.example.net/more.css");
+@font-face { font-family: X; src: url(https://cdn.example.net/x.woff2) }
+</style>
+<script src="https://cdn.example.net/app.js"></script>
+<script>document.write("x")</script>
+</head>
+<body onload="init()">
+<p onmouseover="show()" onclick="go()">Hello</p>
+<a href="https://phish.example.net/paypal" onclick="track()">https://www.paypal.com/signin</a>
+<a href="https://www.paypal.com/help">www.paypal.com</a>
+<a href="https://example.com/report.pdf">report.pdf</a>
+<form action="https://phish.example.net/post" method="POST">
+<input type="text" name="user"><input type="password" name="pass">
+</form>
+<input type="password">
+<iframe src="https://ads.example.net/frame"></iframe>
+<object data="https://ads.example.net/flash.swf"></object>
+<embed src="https://ads.example.net/movie.swf">
+<img src="data:image/png;base64,iVBORw0KGgo=">
+<div style="display:none">hidden invoice text</div>
+<font color="white" style="background-color: #fff">white on white</font>
+<span style="font-size:0px">zero size</span>
+</body></html>`
+	want := &models.HTMLAnalysis{
+		DeceptiveLinks: []models.DeceptiveLink{{
+			Text:       "https://www.paypal.com/signin",
+			Href:       "https://phish.example.net/paypal",
+			TextDomain: "www.paypal.com",
+			HrefDomain: "phish.example.net",
+		}},
+		Forms: []models.HTMLForm{{
+			Action:         "https://phish.example.net/post",
+			Method:         "post",
+			ActionDomain:   "phish.example.net",
+			PasswordInputs: 1,
+		}},
+		PasswordInputs:    2,
+		Scripts:           2,
+		ScriptSources:     []string{"https://cdn.example.net/app.js"},
+		EventHandlers:     []string{"onload", "onmouseover", "onclick"},
+		Iframes:           []string{"https://ads.example.net/frame"},
+		Objects:           []string{"https://ads.example.net/flash.swf"},
+		Embeds:            []string{"https://ads.example.net/movie.swf"},
+		MetaRefresh:       "https://phish.example.net/login",
+		DataURIImages:     1,
+		HiddenText:        []string{"hidden invoice text", "white on white", "zero size"},
+		RemoteStylesheets: []string{"https://cdn.example.net/style.css", "https://cdn.example.net/more.css"},
+		RemoteFonts:       []string{"https://fonts.googleapis.com/css?family=Roboto", "https://cdn.example.net/x.woff2"},
+	}
+	// the order of the lists does not depend on the run
+	for i := 0; i < 10; i++ {
+		if got := AnalyseHTML(h, nil); !reflect.DeepEqual(got, want) {
+			t.Fatalf("analysis = %+v, want %+v", got, want)
+		}
+	}
+}
+
+func TestAnalyseHTMLParts(t *testing.T) {
+	analysis := AnalyseHTML(`<body onload="a()"><p onclick="b()">x</p></body>`, nil)
+	analysis = AnalyseHTML(`<body onclick="c()" onfocus="d()">y</body>`, analysis)
+	if want := []string{"onload", "onclick", "onfocus"}; !reflect.DeepEqual(analysis.EventHandlers, want) {
+		t.Errorf("event handlers = %q, want %q", analysis.EventHandlers, want)
+	}
+	if empty := AnalyseHTML("  ", nil); !reflect.DeepEqual(empty, new(models.HTMLAnalysis)) {
+		t.Errorf("analysis of an empty document = %+v", empty)
+	}
+}
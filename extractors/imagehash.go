@@ -0,0 +1,133 @@
+package extractors
+
+import (
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"sort"
+	"strconv"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// ImageHashes computes the perceptual hashes of img.
+func ImageHashes(img image.Image) *models.ImageHashes {
+	return &models.ImageHashes{
+		AHash: FormatHash(AverageHash(img)),
+		DHash: FormatHash(DifferenceHash(img)),
+		PHash: FormatHash(PerceptualHash(img)),
+	}
+}
+
+// FormatHash returns the hexadecimal representation of a 64 bits hash.
+func FormatHash(h uint64) string {
+	return fmt.Sprintf("%016x", h)
+}
+
+// ParseHash parses the hexadecimal representation of a 64 bits hash.
+func ParseHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
+
+// HammingDistance returns the number of different bits between two hashes.
+func HammingDistance(h1, h2 uint64) int {
+	return bits.OnesCount64(h1 ^ h2)
+}
+
+// AverageHash compares each pixel of a 8x8 grayscale thumbnail to the mean.
+func AverageHash(img image.Image) uint64 {
+	pixels := grayThumbnail(img, 8, 8)
+	var mean float64
+	for _, p := range pixels {
+		mean += p
+	}
+	mean /= float64(len(pixels))
+	var h uint64
+	for i, p := range pixels {
+		if p > mean {
+			h |= 1 << uint(63-i)
+		}
+	}
+	return h
+}
+
+// DifferenceHash compares the adjacent pixels of a 9x8 grayscale thumbnail.
+func DifferenceHash(img image.Image) uint64 {
+	pixels := grayThumbnail(img, 9, 8)
+	var h uint64
+	i := 0
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if pixels[y*9+x] > pixels[y*9+x+1] {
+				h |= 1 << uint(63-i)
+			}
+			i++
+		}
+	}
+	return h
+}
+
+// PerceptualHash compares the low frequencies of the DCT of a 32x32 grayscale thumbnail to their median.
+func PerceptualHash(img image.Image) uint64 {
+	const size = 32
+	pixels := grayThumbnail(img, size, size)
+	var coefficients [64]float64
+	for v := 0; v < 8; v++ {
+		for u := 0; u < 8; u++ {
+			var sum float64
+			for y := 0; y < size; y++ {
+				cy := math.Cos(float64(2*y+1) * float64(v) * math.Pi / (2 * size))
+				for x := 0; x < size; x++ {
+					sum += pixels[y*size+x] * math.Cos(float64(2*x+1)*float64(u)*math.Pi/(2*size)) * cy
+				}
+			}
+			coefficients[v*8+u] = sum
+		}
+	}
+	sorted := append([]float64{}, coefficients[:]...)
+	sort.Float64s(sorted)
+	median := (sorted[31] + sorted[32]) / 2
+	var h uint64
+	for i, c := range coefficients {
+		if c > median {
+			h |= 1 << uint(63-i)
+		}
+	}
+	return h
+}
+
+// grayThumbnail scales img down to width x height by averaging the luminance of the pixels.
+// Transparent pixels are composed over a white background.
+func grayThumbnail(img image.Image, width, height int) []float64 {
+	bounds := img.Bounds()
+	pixels := make([]float64, width*height)
+	counts := make([]int, width*height)
+	dx, dy := bounds.Dx(), bounds.Dy()
+	if dx == 0 || dy == 0 {
+		return pixels
+	}
+	// sampling at most 256 pixels in each direction for each thumbnail pixel is plenty
+	stepX, stepY := 1+dx/(width*256), 1+dy/(height*256)
+	for y := 0; y < dy; y += stepY {
+		ty := y * height / dy
+		for x := 0; x < dx; x += stepX {
+			r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+			l := float64(r*299+g*587+b*114)/1000 + float64(0xffff-a)
+			idx := ty*width + x*width/dx
+			pixels[idx] += l / 257
+			counts[idx]++
+		}
+	}
+	for i, c := range counts {
+		if c > 0 {
+			pixels[i] /= float64(c)
+			continue
+		}
+		// image smaller than the thumbnail: take the nearest pixel
+		x, y := (i%width)*dx/width, (i/width)*dy/height
+		r, g, b, a := img.At(bounds.Min.X+x, bounds.Min.Y+y).RGBA()
+		pixels[i] = (float64(r*299+g*587+b*114)/1000 + float64(0xffff-a)) / 257
+	}
+	return pixels
+}
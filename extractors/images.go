@@ -0,0 +1,44 @@
+package extractors
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	_ "image/gif"
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/stephane-martin/mailstats/qrcode"
+)
+
+// maxImagePixels bounds the size of the decoded images, that take up to 4 bytes per pixel: the
+// photos of a phone fit, but not the decompression bombs.
+var maxImagePixels = 12 * 1024 * 1024
+
+var ErrImageTooLarge = errors.New("image is too large")
+
+// DecodeImage decodes a PNG, JPEG, GIF or BMP image. Only the first frame of animated GIFs is returned.
+func DecodeImage(content []byte) (image.Image, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(content))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width <= 0 || config.Height <= 0 || config.Width > maxImagePixels/config.Height {
+		return nil, ErrImageTooLarge
+	}
+	img, _, err := image.Decode(bytes.NewReader(content))
+	return img, err
+}
+
+// QRCodes returns the contents of the QR codes found in the images, and of the other 2D barcodes
+// (Data Matrix, Aztec, PDF417).
+func QRCodes(images ...image.Image) []string {
+	var contents []string
+	for _, img := range images {
+		decoded, err := qrcode.Decode(img)
+		if err == nil {
+			contents = append(contents, decoded...)
+		}
+	}
+	return distinctStrings(contents)
+}
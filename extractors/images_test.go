@@ -0,0 +1,51 @@
+package extractors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+)
+
+// pngHeader returns the signature and the header chunk of a PNG image, enough for DecodeConfig.
+func pngHeader(width, height uint32) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("\x89PNG\r\n\x1a\n")
+	chunk := make([]byte, 0, 17)
+	chunk = append(chunk, "IHDR"...)
+	chunk = append(chunk, make([]byte, 13)...)
+	binary.BigEndian.PutUint32(chunk[4:], width)
+	binary.BigEndian.PutUint32(chunk[8:], height)
+	chunk[12] = 8 // bit depth, grayscale
+	_ = binary.Write(&buf, binary.BigEndian, uint32(13))
+	buf.Write(chunk)
+	_ = binary.Write(&buf, binary.BigEndian, crc32.ChecksumIEEE(chunk))
+	return buf.Bytes()
+}
+
+func TestDecodeImage(t *testing.T) {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewGray(image.Rect(0, 0, 40, 30))); err != nil {
+		t.Fatal(err)
+	}
+	img, err := DecodeImage(buf.Bytes())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if img.Bounds().Dx() != 40 || img.Bounds().Dy() != 30 {
+		t.Errorf("bounds = %v", img.Bounds())
+	}
+
+	// a 20000x20000 image is refused from its header, before the pixels are allocated
+	if _, err := DecodeImage(pngHeader(20000, 20000)); err != ErrImageTooLarge {
+		t.Errorf("large image: err = %v, want ErrImageTooLarge", err)
+	}
+	if _, err := DecodeImage(pngHeader(4000, 3000)); err == ErrImageTooLarge {
+		t.Error("a 12 megapixels photo is refused")
+	}
+	if _, err := DecodeImage([]byte("not an image")); err == nil {
+		t.Error("invalid image decoded")
+	}
+}
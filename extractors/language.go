@@ -1,27 +1,97 @@
 package extractors
 
 import (
+	"regexp"
+	"strings"
+	"unicode"
+
 	"github.com/abadojack/whatlanggo"
 	"github.com/chrisport/go-lang-detector/langdet"
 	"github.com/chrisport/go-lang-detector/langdet/langdetdef"
-	"strings"
+	"github.com/stephane-martin/mailstats/models"
 )
 
-var whitelist = whatlanggo.Options{
-	Whitelist: map[whatlanggo.Lang]bool{
-		whatlanggo.Fra: true,
-		whatlanggo.Eng: true,
-		whatlanggo.Deu: true,
-		whatlanggo.Rus: true,
-	},
+// chunkLetters is the size, in letters, of the chunks of text that vote for the language.
+const chunkLetters = 200
+
+// reliableLetters is the number of letters from which a detection is fully trusted.
+const reliableLetters = 100
+
+// minSecondaryShare is the share of a text that must be written in another language to report it.
+const minSecondaryShare = 0.2
+
+// commonLanguages are the languages with a list of stop words.
+var commonLanguages = whatlanggo.Options{Whitelist: make(map[whatlanggo.Lang]bool)}
+
+func init() {
+	for lang, name := range whatlanggo.Langs {
+		if _, ok := stopWordLists[strings.ToLower(name)]; ok {
+			commonLanguages.Whitelist[lang] = true
+		}
+	}
+}
+
+func countLetters(s string) int {
+	n := 0
+	for _, r := range s {
+		if unicode.IsLetter(r) {
+			n++
+		}
+	}
+	return n
+}
+
+// minStopWords is the number of stop words from which a short text is in their language.
+const minStopWords = 2
+
+// stopWordsLanguage returns the common language that has the most stop words in content, when
+// there is a single one with at least minStopWords, or "". The short words are not counted, as some
+// lists do not have them.
+func stopWordsLanguage(content string) string {
+	words := strings.FieldsFunc(strings.ToLower(content), func(r rune) bool {
+		return !unicode.IsLetter(r) && r != '\''
+	})
+	best, hits, tie := "", 0, false
+	for language, s := range stopWords {
+		n := 0
+		for _, w := range words {
+			if !tooShort(w) && s.Contains(w) {
+				n++
+			}
+		}
+		switch {
+		case n > hits:
+			best, hits, tie = language, n, false
+		case n == hits:
+			tie = true
+		}
+	}
+	if hits < minStopWords || tie {
+		return ""
+	}
+	return best
 }
 
+// Language returns the language of content, among all the languages known to whatlanggo, or "".
+// The trigrams of a few words do not tell the languages apart: a short text is detected by its
+// stop words, or else among the common languages.
 func Language(content string) string {
-	lang := whatlanggo.DetectLangWithOptions(content, whitelist)
-	if lang != -1 {
-		return strings.ToLower(whatlanggo.Langs[lang])
+	var options whatlanggo.Options
+	if countLetters(content) < reliableLetters {
+		if l := stopWordsLanguage(content); l != "" {
+			return l
+		}
+		options = commonLanguages
 	}
-	return ""
+	info := whatlanggo.DetectWithOptions(content, options)
+	if info.Script != nil && info.Lang < 0 {
+		// a script without a common language
+		info = whatlanggo.Detect(content)
+	}
+	if info.Script == nil || info.Lang < 0 {
+		return ""
+	}
+	return strings.ToLower(whatlanggo.Langs[info.Lang])
 }
 
 func Language2(content string) string {
@@ -37,3 +107,78 @@ func Language2(content string) string {
 	}
 	return strings.ToLower(detector.GetClosestLanguage(content))
 }
+
+var sentenceRE = regexp.MustCompile(`[^.!?\n]+[.!?\n]*`)
+
+type chunk struct {
+	text    string
+	letters int
+}
+
+// languageChunks splits content into chunks of whole sentences of about chunkLetters letters.
+func languageChunks(content string) []chunk {
+	var chunks []chunk
+	var current chunk
+	for _, sentence := range sentenceRE.FindAllString(content, -1) {
+		for _, r := range sentence {
+			if unicode.IsLetter(r) {
+				current.letters++
+			}
+		}
+		current.text += sentence
+		if current.letters >= chunkLetters {
+			chunks = append(chunks, current)
+			current = chunk{}
+		}
+	}
+	if current.letters > 0 {
+		if len(chunks) > 0 && current.letters < chunkLetters/2 {
+			// too short to vote alone
+			last := &chunks[len(chunks)-1]
+			last.text += current.text
+			last.letters += current.letters
+		} else {
+			chunks = append(chunks, current)
+		}
+	}
+	return chunks
+}
+
+// DetectLanguage returns the language of content, with the confidence of the detection, and the
+// secondary language of a multilingual text. The chunks of the text vote for their language: the
+// confidence is the share of the text written in the main language, lowered for the short texts.
+// It returns nil when no language is detected.
+func DetectLanguage(content string) *models.DetectedLanguage {
+	votes := make(map[string]int)
+	total := 0
+	for _, c := range languageChunks(content) {
+		if l := Language(c.text); l != "" {
+			votes[l] += c.letters
+			total += c.letters
+		}
+	}
+	if total == 0 {
+		return nil
+	}
+	main, secondary := "", ""
+	for l, v := range votes {
+		switch {
+		case v > votes[main] || (v == votes[main] && l < main):
+			main, secondary = l, main
+		case secondary == "" || v > votes[secondary] || (v == votes[secondary] && l < secondary):
+			secondary = l
+		}
+	}
+	d := &models.DetectedLanguage{Language: main}
+	d.Confidence = float64(votes[main]) / float64(total)
+	if total < reliableLetters {
+		d.Confidence *= float64(total) / reliableLetters
+	}
+	d.Confidence = round(d.Confidence)
+	// a short text is not multilingual
+	if secondary != "" && total >= reliableLetters && float64(votes[secondary]) >= minSecondaryShare*float64(total) {
+		d.Secondary = secondary
+		d.SecondaryShare = round(float64(votes[secondary]) / float64(total))
+	}
+	return d
+}
@@ -0,0 +1,229 @@
+package extractors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"unicode/utf16"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// Windows shortcuts are described in [MS-SHLLINK].
+
+const lnkHeaderSize = 0x4c
+
+const (
+	lnkHasLinkTargetIDList = 1 << iota
+	lnkHasLinkInfo
+	lnkHasName
+	lnkHasRelativePath
+	lnkHasWorkingDir
+	lnkHasArguments
+	lnkHasIconLocation
+	lnkIsUnicode
+)
+
+const (
+	lnkEnvironmentBlock = 0xa0000001
+	lnkTrackerBlock     = 0xa0000003
+)
+
+const lnkShowMinNoActive = 7
+
+var ErrNotLnk = errors.New("not a Windows shortcut")
+
+// ParseLnk extracts the target, the arguments and the origin of a Windows shortcut.
+func ParseLnk(content []byte) (*models.LnkMeta, error) {
+	if len(content) < lnkHeaderSize || binary.LittleEndian.Uint32(content) != lnkHeaderSize {
+		return nil, ErrNotLnk
+	}
+	flags := binary.LittleEndian.Uint32(content[0x14:])
+	meta := &models.LnkMeta{
+		Hidden: binary.LittleEndian.Uint32(content[0x3c:]) == lnkShowMinNoActive,
+	}
+	r := &leReader{content: content, pos: lnkHeaderSize, unicode: flags&lnkIsUnicode != 0}
+
+	if flags&lnkHasLinkTargetIDList != 0 {
+		// IDListSize does not count itself
+		size := int(r.uint16())
+		r.pos += size
+	}
+	if flags&lnkHasLinkInfo != 0 {
+		start := r.pos
+		size := int(r.uint32())
+		if size < 0x1c || start+size > len(content) {
+			return meta, errors.New("invalid LinkInfo structure")
+		}
+		meta.TargetPath, meta.NetworkShare = parseLinkInfo(content[start : start+size])
+		r.pos = start + size
+	}
+	strs := []struct {
+		flag uint32
+		dest *string
+	}{
+		{lnkHasName, &meta.Description},
+		{lnkHasRelativePath, &meta.RelativePath},
+		{lnkHasWorkingDir, &meta.WorkingDir},
+		{lnkHasArguments, &meta.Arguments},
+		{lnkHasIconLocation, &meta.IconLocation},
+	}
+	for _, s := range strs {
+		if flags&s.flag != 0 {
+			*s.dest = r.stringData()
+		}
+	}
+	if r.err != nil {
+		return meta, r.err
+	}
+
+	// extra data blocks
+	for r.pos+8 <= len(content) {
+		start := r.pos
+		size := int(r.uint32())
+		if size < 8 || start+size > len(content) {
+			break
+		}
+		block := content[start : start+size]
+		switch r.uint32() {
+		case lnkTrackerBlock:
+			if len(block) >= 0x20 {
+				meta.MachineID = nulTerminated(block[0x10:0x20])
+			}
+		case lnkEnvironmentBlock:
+			if meta.TargetPath == "" && len(block) >= 0x314 {
+				meta.TargetPath = utf16NulTerminated(block[0x10c:0x314])
+				if meta.TargetPath == "" {
+					meta.TargetPath = nulTerminated(block[8:0x10c])
+				}
+			}
+		}
+		r.pos = start + size
+	}
+	if meta.TargetPath == "" && meta.RelativePath != "" {
+		meta.TargetPath = meta.RelativePath
+	}
+	return meta, nil
+}
+
+// parseLinkInfo returns the local path or the network path of the target.
+func parseLinkInfo(info []byte) (target string, share string) {
+	headerSize := binary.LittleEndian.Uint32(info[4:])
+	flags := binary.LittleEndian.Uint32(info[8:])
+	offset := func(pos int) int {
+		return int(binary.LittleEndian.Uint32(info[pos:]))
+	}
+	str := func(off int) string {
+		if off <= 0 || off >= len(info) {
+			return ""
+		}
+		return nulTerminated(info[off:])
+	}
+	ustr := func(off int) string {
+		if off <= 0 || off >= len(info) {
+			return ""
+		}
+		return utf16NulTerminated(info[off:])
+	}
+	suffix := str(offset(0x18))
+	if headerSize >= 0x24 && len(info) >= 0x24 {
+		if s := ustr(offset(0x20)); s != "" {
+			suffix = s
+		}
+	}
+	if flags&1 != 0 {
+		base := str(offset(0x10))
+		if headerSize >= 0x24 && len(info) >= 0x24 {
+			if s := ustr(offset(0x1c)); s != "" {
+				base = s
+			}
+		}
+		target = base + suffix
+	}
+	if flags&2 != 0 {
+		netOffset := offset(0x14)
+		if netOffset > 0 && netOffset+0x14 <= len(info) {
+			network := info[netOffset:]
+			share = str(netOffset + int(binary.LittleEndian.Uint32(network[8:])))
+			if target == "" && share != "" {
+				target = share + `\` + suffix
+			}
+		}
+	}
+	return target, share
+}
+
+type leReader struct {
+	content []byte
+	pos     int
+	unicode bool
+	err     error
+}
+
+var errTruncated = errors.New("truncated data")
+
+func (r *leReader) uint16() uint16 {
+	if r.pos+2 > len(r.content) {
+		r.err = errTruncated
+		r.pos = len(r.content)
+		return 0
+	}
+	v := binary.LittleEndian.Uint16(r.content[r.pos:])
+	r.pos += 2
+	return v
+}
+
+func (r *leReader) uint32() uint32 {
+	if r.pos+4 > len(r.content) {
+		r.err = errTruncated
+		r.pos = len(r.content)
+		return 0
+	}
+	v := binary.LittleEndian.Uint32(r.content[r.pos:])
+	r.pos += 4
+	return v
+}
+
+// stringData reads a StringData structure: a count of characters followed by the characters.
+func (r *leReader) stringData() string {
+	count := int(r.uint16())
+	size := count
+	if r.unicode {
+		size = 2 * count
+	}
+	if r.pos+size > len(r.content) {
+		r.err = errTruncated
+		r.pos = len(r.content)
+		return ""
+	}
+	b := r.content[r.pos : r.pos+size]
+	r.pos += size
+	if r.unicode {
+		return decodeUTF16(b)
+	}
+	return string(b)
+}
+
+func nulTerminated(b []byte) string {
+	if idx := bytes.IndexByte(b, 0); idx >= 0 {
+		b = b[:idx]
+	}
+	return string(b)
+}
+
+func utf16NulTerminated(b []byte) string {
+	for i := 0; i+1 < len(b); i += 2 {
+		if b[i] == 0 && b[i+1] == 0 {
+			return decodeUTF16(b[:i])
+		}
+	}
+	return decodeUTF16(b)
+}
+
+func decodeUTF16(b []byte) string {
+	u := make([]uint16, len(b)/2)
+	for i := range u {
+		u[i] = binary.LittleEndian.Uint16(b[2*i:])
+	}
+	return string(utf16.Decode(u))
+}
@@ -0,0 +1,228 @@
+package extractors
+
+import (
+	"encoding/binary"
+	"math/rand"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func put32(b []byte, v int) {
+	binary.LittleEndian.PutUint32(b, uint32(v))
+}
+
+// lnkFile builds a shortcut with the header flags and show command, followed by the structures.
+func lnkFile(flags uint32, showCommand int, structures ...[]byte) []byte {
+	b := make([]byte, lnkHeaderSize)
+	put32(b, lnkHeaderSize)
+	// the LinkCLSID {00021401-0000-0000-C000-000000000046}
+	copy(b[4:], []byte{0x01, 0x14, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46})
+	binary.LittleEndian.PutUint32(b[0x14:], flags)
+	put32(b[0x3c:], showCommand)
+	for _, s := range structures {
+		b = append(b, s...)
+	}
+	// terminal block
+	return append(b, 0, 0, 0, 0)
+}
+
+// linkInfo builds a LinkInfo structure with a local base path or a network share.
+func linkInfo(local, share, suffix string) []byte {
+	b := make([]byte, 0x1c)
+	put32(b[4:], 0x1c)
+	flags := 0
+	if local != "" {
+		flags |= 1
+		put32(b[0x0c:], len(b))
+		volumeID := make([]byte, 0x11)
+		put32(volumeID, len(volumeID))
+		put32(volumeID[4:], 3)
+		put32(volumeID[0x0c:], 0x10)
+		b = append(b, volumeID...)
+		put32(b[0x10:], len(b))
+		b = append(append(b, local...), 0)
+	}
+	if share != "" {
+		flags |= 2
+		put32(b[0x14:], len(b))
+		link := make([]byte, 0x14)
+		put32(link, 0x14+len(share)+1)
+		put32(link[8:], 0x14)
+		b = append(append(append(b, link...), share...), 0)
+	}
+	put32(b[0x18:], len(b))
+	b = append(append(b, suffix...), 0)
+	put32(b[8:], flags)
+	put32(b, len(b))
+	return b
+}
+
+func stringData(unicode bool, s string) []byte {
+	if !unicode {
+		b := []byte{byte(len(s)), byte(len(s) >> 8)}
+		return append(b, s...)
+	}
+	u := utf16.Encode([]rune(s))
+	b := []byte{byte(len(u)), byte(len(u) >> 8)}
+	for _, c := range u {
+		b = append(b, byte(c), byte(c>>8))
+	}
+	return b
+}
+
+func trackerBlock(machineID string) []byte {
+	b := make([]byte, 0x60)
+	put32(b, len(b))
+	binary.LittleEndian.PutUint32(b[4:], lnkTrackerBlock)
+	put32(b[8:], 0x58)
+	copy(b[0x10:0x20], machineID)
+	return b
+}
+
+func environmentBlock(ansi, unicode string) []byte {
+	b := make([]byte, 0x314)
+	put32(b, len(b))
+	binary.LittleEndian.PutUint32(b[4:], lnkEnvironmentBlock)
+	copy(b[8:], ansi)
+	for i, c := range utf16.Encode([]rune(unicode)) {
+		binary.LittleEndian.PutUint16(b[0x10c+2*i:], c)
+	}
+	return b
+}
+
+const powershell = `C:\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`
+
+func TestParseLnk(t *testing.T) {
+	cases := []struct {
+		name    string
+		content []byte
+		meta    models.LnkMeta
+	}{
+		{
+			"local target",
+			lnkFile(lnkHasLinkInfo|lnkHasRelativePath|lnkHasArguments|lnkIsUnicode, lnkShowMinNoActive,
+				linkInfo(`C:\Windows\System32\`, "", `WindowsPowerShell\v1.0\powershell.exe`),
+				stringData(true, `..\..\..\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`),
+				stringData(true, "-nop -w hidden -enc SQBFAFgA"),
+				trackerBlock("desktop-7a1b2c")),
+			models.LnkMeta{
+				TargetPath:   powershell,
+				RelativePath: `..\..\..\Windows\System32\WindowsPowerShell\v1.0\powershell.exe`,
+				Arguments:    "-nop -w hidden -enc SQBFAFgA",
+				MachineID:    "desktop-7a1b2c",
+				Hidden:       true,
+			},
+		},
+		{
+			"network share",
+			lnkFile(lnkHasLinkInfo|lnkHasName, 1, linkInfo("", `\\198.51.100.7\share`, "invoice.exe"),
+				stringData(false, "Invoice")),
+			models.LnkMeta{
+				TargetPath:   `\\198.51.100.7\share\invoice.exe`,
+				NetworkShare: `\\198.51.100.7\share`,
+				Description:  "Invoice",
+			},
+		},
+		{
+			"environment variables",
+			lnkFile(lnkHasLinkTargetIDList|lnkHasWorkingDir|lnkHasIconLocation|lnkIsUnicode, 1,
+				[]byte{4, 0, 2, 0, 0, 0},
+				stringData(true, `%windir%`),
+				stringData(true, `%SystemRoot%\System32\shell32.dll`),
+				environmentBlock(`%windir%\system32\mshta.exe`, `%windir%\System32\mshta.exe`)),
+			models.LnkMeta{
+				TargetPath:   `%windir%\System32\mshta.exe`,
+				WorkingDir:   `%windir%`,
+				IconLocation: `%SystemRoot%\System32\shell32.dll`,
+			},
+		},
+		{
+			"ANSI environment variables",
+			lnkFile(0, 1, environmentBlock(`%windir%\system32\cmd.exe`, "")),
+			models.LnkMeta{TargetPath: `%windir%\system32\cmd.exe`},
+		},
+		{
+			"relative path",
+			lnkFile(lnkHasRelativePath|lnkHasArguments, 1, stringData(false, `.\payload.bat`), stringData(false, "/c start")),
+			models.LnkMeta{TargetPath: `.\payload.bat`, RelativePath: `.\payload.bat`, Arguments: "/c start"},
+		},
+	}
+	for _, c := range cases {
+		meta, err := ParseLnk(c.content)
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if *meta != c.meta {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.meta, *meta)
+		}
+	}
+}
+
+func TestParseLnkMalformed(t *testing.T) {
+	valid := lnkFile(lnkHasLinkTargetIDList|lnkHasLinkInfo|lnkHasArguments|lnkIsUnicode, lnkShowMinNoActive,
+		[]byte{4, 0, 2, 0, 0, 0},
+		linkInfo(`C:\Windows\System32\`, `\\server\share`, "cmd.exe"),
+		stringData(true, "/c powershell -enc SQBFAFgA"),
+		trackerBlock("desktop-7a1b2c"),
+		environmentBlock(`%windir%\system32\cmd.exe`, `%windir%\system32\cmd.exe`))
+	if _, err := ParseLnk(valid); err != nil {
+		t.Fatal(err)
+	}
+
+	notLnk := [][]byte{nil, valid[:lnkHeaderSize-1], append([]byte{0x4d, 0x5a, 0, 0}, valid[4:]...)}
+	for _, content := range notLnk {
+		if _, err := ParseLnk(content); err != ErrNotLnk {
+			t.Errorf("%d bytes: expected ErrNotLnk, got %v", len(content), err)
+		}
+	}
+
+	badInfo := append([]byte{}, valid...)
+	put32(badInfo[lnkHeaderSize+6:], len(valid))
+	if _, err := ParseLnk(badInfo); err == nil {
+		t.Error("no error for a LinkInfo longer than the file")
+	}
+	badInfo = append([]byte{}, valid...)
+	put32(badInfo[lnkHeaderSize+6:], 0x10)
+	if _, err := ParseLnk(badInfo); err == nil {
+		t.Error("no error for a short LinkInfo")
+	}
+
+	// the arguments are cut
+	cut := lnkHeaderSize + 6 + len(linkInfo(`C:\Windows\System32\`, `\\server\share`, "cmd.exe")) + 10
+	if meta, err := ParseLnk(valid[:cut]); err != errTruncated || meta == nil || meta.TargetPath != `C:\Windows\System32\cmd.exe` {
+		t.Errorf("truncated arguments: got %+v, %v", meta, err)
+	}
+
+	// truncated and corrupted files are read without panic
+	for i := range valid {
+		_, _ = ParseLnk(valid[:i])
+	}
+	r := rand.New(rand.NewSource(1))
+	for i := 0; i < 2000; i++ {
+		corrupted := append([]byte{}, valid...)
+		for j := 0; j < 4; j++ {
+			corrupted[lnkHeaderSize+r.Intn(len(corrupted)-lnkHeaderSize)] = byte(r.Intn(256))
+		}
+		_, _ = ParseLnk(corrupted)
+	}
+}
+
+func TestLnkLanguage(t *testing.T) {
+	cases := map[string]string{
+		powershell:                        "powershell",
+		`C:\Windows\System32\cmd.exe`:     "batch",
+		`%windir%\System32\MSHTA.EXE`:     "hta",
+		`C:/Windows/System32/wscript.exe`: "wsh",
+		"pwsh.exe":                        "powershell",
+		`C:\Program Files\app\app.exe`:    "command",
+		"":                                "command",
+	}
+	for target, language := range cases {
+		if l := LnkLanguage(target); l != language {
+			t.Errorf("LnkLanguage(%q) = %q, want %q", target, l, language)
+		}
+	}
+}
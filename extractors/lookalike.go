@@ -0,0 +1,170 @@
+package extractors
+
+import (
+	"net"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+// The techniques used to imitate a domain
+const (
+	LookalikeHomoglyph     = "homoglyph"
+	LookalikePunycode      = "punycode"
+	LookalikeTransposition = "transposition"
+	LookalikeBitsquatting  = "bitsquatting"
+	LookalikeMissingDot    = "missing-dot"
+	LookalikeTypo          = "typo"
+	LookalikeSubdomain     = "subdomain"
+	LookalikeTLDSwap       = "tld-swap"
+)
+
+// minTypoLength is the length under which a domain name is too short to look for typos: nearly
+// every short name is one edit away from another.
+const minTypoLength = 4
+
+type protectedDomain struct {
+	// domain is the registered domain (paypal.com)
+	domain string
+	// label is the registered label (paypal) and suffix the public suffix (com)
+	label    string
+	suffix   string
+	skeleton string
+}
+
+// LookalikeDetector finds the hosts that imitate a list of protected domains.
+type LookalikeDetector struct {
+	protected []protectedDomain
+}
+
+// NewLookalikeDetector returns a detector for the given protected domains, or nil if the list is
+// empty.
+func NewLookalikeDetector(domains []string) *LookalikeDetector {
+	d := new(LookalikeDetector)
+	seen := make(map[string]bool)
+	for _, domain := range domains {
+		reg := utils.RegisteredDomain(ToUnicode(domain))
+		label, suffix := splitRegistered(reg)
+		if label == "" || suffix == "" || seen[reg] {
+			continue
+		}
+		seen[reg] = true
+		d.protected = append(d.protected, protectedDomain{
+			domain:   reg,
+			label:    label,
+			suffix:   suffix,
+			skeleton: Skeleton(label),
+		})
+	}
+	if len(d.protected) == 0 {
+		return nil
+	}
+	return d
+}
+
+func splitRegistered(reg string) (label, suffix string) {
+	idx := strings.Index(reg, ".")
+	if idx == -1 {
+		return reg, ""
+	}
+	return reg[:idx], reg[idx+1:]
+}
+
+// Check returns the protected domain that host imitates and the technique that is used, or empty
+// strings if host does not look like a protected domain. The hosts that belong to a protected
+// domain are legitimate.
+func (d *LookalikeDetector) Check(host string) (protected string, technique string) {
+	host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), ".")
+	if d == nil || host == "" || net.ParseIP(host) != nil {
+		return "", ""
+	}
+	unicodeHost := ToUnicode(host)
+	ace := unicodeHost != host
+	reg := utils.RegisteredDomain(unicodeHost)
+	for _, p := range d.protected {
+		if reg == p.domain {
+			return "", ""
+		}
+	}
+	label, _ := splitRegistered(reg)
+	skeleton := Skeleton(label)
+	for _, p := range d.protected {
+		if t := p.match(unicodeHost, label, skeleton, ace); t != "" {
+			return p.domain, t
+		}
+	}
+	return "", ""
+}
+
+func (p protectedDomain) match(host, label, skeleton string, ace bool) string {
+	// paypal.com.evil.net, paypal.com-login.net
+	h := "." + host + "."
+	if strings.Contains(h, "."+p.domain+".") || strings.Contains(h, "."+p.domain+"-") {
+		return LookalikeSubdomain
+	}
+	if label == p.label {
+		return LookalikeTLDSwap
+	}
+	if skeleton == p.skeleton {
+		if ace {
+			return LookalikePunycode
+		}
+		return LookalikeHomoglyph
+	}
+	if len(p.label) < minTypoLength {
+		return ""
+	}
+	switch {
+	case isTransposition(skeleton, p.skeleton):
+		return LookalikeTransposition
+	case isBitsquat(label, p.label):
+		return LookalikeBitsquatting
+	case label == "www"+p.label || label == strings.Replace(p.domain, ".", "", -1):
+		return LookalikeMissingDot
+	case utils.EditDistance(skeleton, p.skeleton) <= utils.MaxTypos(p.skeleton):
+		return LookalikeTypo
+	}
+	return ""
+}
+
+// isTransposition reports whether a is b with two adjacent characters swapped.
+func isTransposition(a, b string) bool {
+	ra, rb := []rune(a), []rune(b)
+	if len(ra) != len(rb) {
+		return false
+	}
+	for i := 0; i < len(ra); i++ {
+		if ra[i] == rb[i] {
+			continue
+		}
+		if i+1 < len(ra) && ra[i] == rb[i+1] && ra[i+1] == rb[i] {
+			return string(ra[i+2:]) == string(rb[i+2:])
+		}
+		return false
+	}
+	return false
+}
+
+// isBitsquat reports whether a is b with a single bit flipped in one character, as memory errors
+// do, and still a valid hostname.
+func isBitsquat(a, b string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	diff := -1
+	for i := 0; i < len(a); i++ {
+		if a[i] != b[i] {
+			if diff != -1 {
+				return false
+			}
+			diff = i
+		}
+	}
+	if diff == -1 {
+		return false
+	}
+	x := a[diff] ^ b[diff]
+	c := a[diff]
+	valid := (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') || c == '-'
+	return valid && x&(x-1) == 0
+}
@@ -0,0 +1,52 @@
+package extractors
+
+import (
+	"testing"
+)
+
+func TestLookalikeDetector(t *testing.T) {
+	d := NewLookalikeDetector([]string{"paypal.com", "Microsoft.com", "www.example.co.uk", "ups.com"})
+	cases := []struct {
+		host      string
+		protected string
+		technique string
+	}{
+		{"paypal.com", "", ""},
+		{"www.paypal.com", "", ""},
+		{"mail.example.co.uk", "", ""},
+		{"paypa1.com", "paypal.com", LookalikeHomoglyph},
+		{"xn--pypal-4ve.com", "paypal.com", LookalikePunycode},
+		{"paypal.net", "paypal.com", LookalikeTLDSwap},
+		{"example.com", "example.co.uk", LookalikeTLDSwap},
+		{"paypal.com.secure-login.net", "paypal.com", LookalikeSubdomain},
+		{"paypal.com-login.net", "paypal.com", LookalikeSubdomain},
+		{"pyapal.com", "paypal.com", LookalikeTransposition},
+		{"qaypal.com", "paypal.com", LookalikeBitsquatting},
+		{"wwwpaypal.com", "paypal.com", LookalikeMissingDot},
+		{"paypalcom.net", "paypal.com", LookalikeMissingDot},
+		{"paypl.com", "paypal.com", LookalikeTypo},
+		{"micosoft.com", "microsoft.com", LookalikeTypo},
+		{"mcrosoftt.com", "microsoft.com", LookalikeTypo},
+		// too far, or too short to look for typos
+		{"pypl.com", "", ""},
+		{"mcrsft.com", "", ""},
+		{"ups2.com", "", ""},
+		{"ubs.com", "", ""},
+		{"192.0.2.1", "", ""},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		protected, technique := d.Check(c.host)
+		if protected != c.protected || technique != c.technique {
+			t.Errorf("Check(%q) = %q, %q, want %q, %q", c.host, protected, technique, c.protected, c.technique)
+		}
+	}
+
+	var none *LookalikeDetector
+	if protected, technique := none.Check("paypa1.com"); protected != "" || technique != "" {
+		t.Errorf("nil detector: Check = %q, %q", protected, technique)
+	}
+	if NewLookalikeDetector(nil) != nil {
+		t.Error("detector without protected domains")
+	}
+}
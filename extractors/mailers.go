@@ -0,0 +1,313 @@
+package extractors
+
+import (
+	"fmt"
+	"mime"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// The kinds of mail software
+const (
+	MailerMUA     = "mua"
+	MailerWebmail = "webmail"
+	MailerLibrary = "library"
+	MailerSpamKit = "spam-kit"
+)
+
+// mailerFingerprint describes the headers written by a mail software.
+type mailerFingerprint struct {
+	name string
+	kind string
+	// declared matches the X-Mailer or User-Agent header
+	declared *regexp.Regexp
+	// order is the relative order of the headers that the software writes
+	order []string
+	// headers are specific to the software
+	headers []string
+	// casing are header names with their specific casing
+	casing    []string
+	messageID *regexp.Regexp
+	boundary  *regexp.Regexp
+}
+
+var mailerFingerprints = []mailerFingerprint{
+	{
+		name:      "Microsoft Outlook",
+		kind:      MailerMUA,
+		declared:  regexp.MustCompile(`(?i)^microsoft (office )?outlook (1[0-9]|[0-9]+\.)`),
+		order:     []string{"From", "To", "Subject", "Thread-Topic", "Thread-Index", "Date", "Message-ID", "Accept-Language", "Content-Language", "X-MS-Has-Attach", "Content-Type", "MIME-Version"},
+		headers:   []string{"Thread-Index", "X-MS-Has-Attach", "X-MS-TNEF-Correlator"},
+		casing:    []string{"Message-ID", "MIME-Version"},
+		messageID: regexp.MustCompile(`(?i)@[a-z0-9.]*(outlook\.com|prod\.exchangelabs\.com)>$`),
+		boundary:  regexp.MustCompile(`^_00[0-9]_[A-Za-z0-9]+_$`),
+	},
+	{
+		name:      "Outlook Express",
+		kind:      MailerMUA,
+		declared:  regexp.MustCompile(`(?i)^microsoft outlook express|^microsoft windows (live )?mail`),
+		order:     []string{"Message-ID", "From", "To", "Subject", "Date", "MIME-Version", "Content-Type", "X-Priority", "X-MSMail-Priority", "X-Mailer", "X-MimeOLE"},
+		headers:   []string{"X-MimeOLE", "X-MSMail-Priority"},
+		casing:    []string{"Message-ID", "MIME-Version"},
+		messageID: regexp.MustCompile(`^<[0-9a-f]{6}\$[0-9a-f]{8}\$[0-9a-f]{8}@`),
+		boundary:  regexp.MustCompile(`^----=_NextPart_000_[0-9A-F]{4}_[0-9A-F]{8}\.[0-9A-F]{8}$`),
+	},
+	{
+		name:      "Mozilla Thunderbird",
+		kind:      MailerMUA,
+		declared:  regexp.MustCompile(`(?i)thunderbird/|icedove/`),
+		order:     []string{"Message-ID", "Date", "MIME-Version", "User-Agent", "Content-Language", "To", "From", "Subject", "Content-Type"},
+		casing:    []string{"Message-ID", "MIME-Version", "User-Agent"},
+		messageID: regexp.MustCompile(`^<[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}@`),
+		boundary:  regexp.MustCompile(`^-{12}[0-9A-Za-z]{22,24}$`),
+	},
+	{
+		name:      "Apple Mail",
+		kind:      MailerMUA,
+		declared:  regexp.MustCompile(`(?i)^(apple mail|iphone mail|ipad mail)`),
+		order:     []string{"Content-Type", "Mime-Version", "Subject", "Message-Id", "Date", "To"},
+		casing:    []string{"Mime-Version", "Message-Id"},
+		messageID: regexp.MustCompile(`^<[0-9A-F]{8}-[0-9A-F]{4}-[0-9A-F]{4}-[0-9A-F]{4}-[0-9A-F]{12}@`),
+		boundary:  regexp.MustCompile(`^Apple-Mail(=_|-)[0-9A-F-]+$`),
+	},
+	{
+		name:      "Gmail",
+		kind:      MailerWebmail,
+		order:     []string{"MIME-Version", "References", "In-Reply-To", "From", "Date", "Message-ID", "Subject", "To", "Content-Type"},
+		headers:   []string{"X-Gm-Message-State", "X-Google-DKIM-Signature"},
+		casing:    []string{"Message-ID", "MIME-Version"},
+		messageID: regexp.MustCompile(`@mail\.gmail\.com>$`),
+		boundary:  regexp.MustCompile(`^0{10,12}[0-9a-f]{12,16}$`),
+	},
+	{
+		name:     "Roundcube",
+		kind:     MailerWebmail,
+		declared: regexp.MustCompile(`(?i)^roundcube`),
+		order:    []string{"MIME-Version", "Date", "From", "To", "Subject", "Message-ID", "X-Sender", "User-Agent"},
+		headers:  []string{"X-Sender"},
+		boundary: regexp.MustCompile(`^=_[0-9a-f]{32}$`),
+	},
+	{
+		name:      "PHPMailer",
+		kind:      MailerLibrary,
+		declared:  regexp.MustCompile(`(?i)phpmailer`),
+		order:     []string{"Date", "To", "From", "Reply-To", "Subject", "Message-ID", "X-Mailer", "MIME-Version", "Content-Type"},
+		casing:    []string{"Message-ID", "MIME-Version"},
+		messageID: regexp.MustCompile(`^<[0-9A-Za-z]{32,}@`),
+		boundary:  regexp.MustCompile(`^b[12](_|=_)[0-9A-Za-z]{32,}$`),
+	},
+	{
+		name:      "Symfony Mailer",
+		kind:      MailerLibrary,
+		order:     []string{"Message-ID", "Date", "Subject", "From", "To", "MIME-Version", "Content-Type"},
+		messageID: regexp.MustCompile(`@swift\.generated>$|^<[0-9a-f]{32}@symfony>$`),
+		boundary:  regexp.MustCompile(`^_=_swift_`),
+	},
+	{
+		name:     "Python email",
+		kind:     MailerLibrary,
+		order:    []string{"Content-Type", "MIME-Version", "Subject", "From", "To"},
+		boundary: regexp.MustCompile(`^={15}\d{18,20}==$`),
+	},
+	{
+		name:    "PHP mail()",
+		kind:    MailerLibrary,
+		headers: []string{"X-PHP-Originating-Script", "X-PHP-Script"},
+	},
+	{
+		name:     "Atomic Mail Sender",
+		kind:     MailerSpamKit,
+		declared: regexp.MustCompile(`(?i)atomic mail sender`),
+	},
+	{
+		name:     "SendBlaster",
+		kind:     MailerSpamKit,
+		declared: regexp.MustCompile(`(?i)sendblaster`),
+	},
+	{
+		name:     "Gammadyne Mailer",
+		kind:     MailerSpamKit,
+		declared: regexp.MustCompile(`(?i)gammadyne`),
+	},
+	{
+		name:     "Turbo-Mailer",
+		kind:     MailerSpamKit,
+		declared: regexp.MustCompile(`(?i)turbo-?mailer`),
+	},
+	{
+		name:     "SuperMailer",
+		kind:     MailerSpamKit,
+		declared: regexp.MustCompile(`(?i)supermailer`),
+	},
+	{
+		name:     "Leaf PHPMailer",
+		kind:     MailerSpamKit,
+		declared: regexp.MustCompile(`(?i)leaf ?phpmailer|phpmailer.*leaf`),
+		boundary: regexp.MustCompile(`^b1_[0-9a-f]{32}$`),
+	},
+	{
+		name:     "Mass mailer",
+		kind:     MailerSpamKit,
+		declared: regexp.MustCompile(`(?i)mass ?mail(er)?|bulk ?mail(er)?|e-?mail ?blaster|advanced mass sender`),
+	},
+}
+
+// minMailerScore is the score needed to identify a mail software.
+const minMailerScore = 3
+
+// orderMatch checks the relative order of the headers of a fingerprint. It returns the number of
+// headers of the fingerprint that are present, and whether they appear in order.
+func orderMatch(names []string, order []string) (int, bool) {
+	position := make(map[string]int)
+	for i, name := range names {
+		lower := strings.ToLower(name)
+		if _, ok := position[lower]; !ok {
+			position[lower] = i
+		}
+	}
+	present, last, ok := 0, -1, true
+	for _, name := range order {
+		pos, found := position[strings.ToLower(name)]
+		if !found {
+			continue
+		}
+		present++
+		if pos < last {
+			ok = false
+		}
+		last = pos
+	}
+	return present, ok
+}
+
+type mailerScore struct {
+	fp       *mailerFingerprint
+	declared bool
+	// structure is the score of the headers layout, without the declared name
+	structure int
+	evidence  []string
+	// orderBroken is set when the headers are not in the order of the fingerprint
+	orderBroken bool
+}
+
+func (fp *mailerFingerprint) score(names []string, fields []HeaderField, declared, messageID, boundary string) mailerScore {
+	s := mailerScore{fp: fp}
+	if fp.declared != nil && declared != "" && fp.declared.MatchString(declared) {
+		s.declared = true
+		s.evidence = append(s.evidence, "declared: "+declared)
+	}
+	if len(fp.order) > 0 {
+		present, ok := orderMatch(names, fp.order)
+		if present >= 4 && ok {
+			s.structure += 2
+			s.evidence = append(s.evidence, "header order")
+		} else if present >= 3 && !ok {
+			s.structure -= 2
+			s.orderBroken = true
+		}
+	}
+	found := 0
+	for _, h := range fp.headers {
+		if len(fieldValues(fields, h)) > 0 && found < 2 {
+			found++
+			s.structure++
+			s.evidence = append(s.evidence, h+" header")
+		}
+	}
+	if len(fp.casing) > 0 {
+		present, exact := 0, 0
+		for _, c := range fp.casing {
+			for _, name := range names {
+				if strings.EqualFold(name, c) {
+					present++
+					if name == c {
+						exact++
+					}
+					break
+				}
+			}
+		}
+		if present >= 2 && exact == present {
+			s.structure++
+			s.evidence = append(s.evidence, "header casing")
+		}
+	}
+	if fp.messageID != nil && messageID != "" && fp.messageID.MatchString(messageID) {
+		s.structure += 2
+		s.evidence = append(s.evidence, "Message-ID format")
+	}
+	if fp.boundary != nil && boundary != "" && fp.boundary.MatchString(boundary) {
+		s.structure += 2
+		s.evidence = append(s.evidence, "MIME boundary format")
+	}
+	return s
+}
+
+func (s mailerScore) total() int {
+	if s.declared {
+		return s.structure + 3
+	}
+	return s.structure
+}
+
+// FingerprintMailer identifies the software that wrote a mail from the order and the format of
+// its headers. It returns nil when the software is unknown.
+func FingerprintMailer(fields []HeaderField) *models.Mailer {
+	names := HeaderNames(fields)
+	declared := ""
+	for _, h := range []string{"X-Mailer", "User-Agent"} {
+		if values := fieldValues(fields, h); len(values) > 0 {
+			declared = values[0]
+			break
+		}
+	}
+	var messageID, boundary string
+	if values := fieldValues(fields, "Message-ID"); len(values) > 0 {
+		messageID = values[0]
+	}
+	if values := fieldValues(fields, "Content-Type"); len(values) > 0 {
+		_, params, err := mime.ParseMediaType(values[0])
+		if err == nil {
+			boundary = params["boundary"]
+		}
+	}
+
+	scores := make([]mailerScore, 0, len(mailerFingerprints))
+	for i := range mailerFingerprints {
+		scores = append(scores, mailerFingerprints[i].score(names, fields, declared, messageID, boundary))
+	}
+	sort.SliceStable(scores, func(a, b int) bool { return scores[a].total() > scores[b].total() })
+
+	var declaredScore *mailerScore
+	for i := range scores {
+		if scores[i].declared {
+			declaredScore = &scores[i]
+			break
+		}
+	}
+	mailer := &models.Mailer{Declared: declared}
+	best := scores[0]
+	if best.total() >= minMailerScore {
+		mailer.Name = best.fp.name
+		mailer.Kind = best.fp.kind
+		mailer.Score = best.total()
+		mailer.Evidence = best.evidence
+	}
+	if declaredScore != nil {
+		switch {
+		case declaredScore.orderBroken:
+			mailer.Inconsistent = true
+			mailer.Evidence = append(mailer.Evidence, fmt.Sprintf("the header order is not the order of %s", declaredScore.fp.name))
+		case declaredScore.fp != best.fp && best.structure > minMailerScore && declaredScore.structure <= 0:
+			mailer.Inconsistent = true
+			mailer.Evidence = append(mailer.Evidence, fmt.Sprintf("declared as %s, but the headers are written by %s", declaredScore.fp.name, best.fp.name))
+		}
+	}
+	if mailer.Name == "" && mailer.Declared == "" {
+		return nil
+	}
+	return mailer
+}
@@ -0,0 +1,371 @@
+package extractors
+
+import (
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/stephane-martin/mailstats/models"
+	"golang.org/x/text/unicode/norm"
+)
+
+// maxMixedScriptSamples is the number of mixed-script words reported.
+const maxMixedScriptSamples = 5
+
+// upperConfusables are the uppercase letters of other scripts that look like an uppercase Latin
+// letter. The lowercase ones are in confusables.
+var upperConfusables = map[rune]rune{
+	// Cyrillic
+	'А': 'A', 'В': 'B', 'Е': 'E', 'К': 'K', 'М': 'M', 'Н': 'H', 'О': 'O', 'Р': 'P', 'С': 'C',
+	'Т': 'T', 'Х': 'X', 'У': 'Y', 'Ѕ': 'S', 'І': 'I', 'Ј': 'J', 'Ԛ': 'Q', 'Ԝ': 'W',
+	// Greek
+	'Α': 'A', 'Β': 'B', 'Ε': 'E', 'Ζ': 'Z', 'Η': 'H', 'Ι': 'I', 'Κ': 'K', 'Μ': 'M', 'Ν': 'N',
+	'Ο': 'O', 'Ρ': 'P', 'Τ': 'T', 'Υ': 'Y', 'Χ': 'X',
+	// Armenian
+	'Օ': 'O', 'Տ': 'S',
+}
+
+// scriptLookalikes are the Latin letters that look like a letter of another script, to restore
+// the Cyrillic and Greek words written with some Latin letters.
+var scriptLookalikes = map[string]map[rune]rune{
+	"Cyrillic": {
+		'a': 'а', 'c': 'с', 'e': 'е', 'o': 'о', 'p': 'р', 'x': 'х', 'y': 'у',
+		'A': 'А', 'B': 'В', 'C': 'С', 'E': 'Е', 'H': 'Н', 'K': 'К', 'M': 'М', 'O': 'О', 'P': 'Р',
+		'T': 'Т', 'X': 'Х', 'Y': 'У',
+	},
+	"Greek": {
+		'o': 'ο', 'v': 'ν',
+		'A': 'Α', 'B': 'Β', 'E': 'Ε', 'Z': 'Ζ', 'H': 'Η', 'I': 'Ι', 'K': 'Κ', 'M': 'Μ', 'N': 'Ν',
+		'O': 'Ο', 'P': 'Ρ', 'T': 'Τ', 'Y': 'Υ', 'X': 'Χ',
+	},
+}
+
+// lookalike returns the letters of the target script that r, a letter of another script, looks
+// like.
+func lookalike(r rune, target string) (string, bool) {
+	if target == "Latin" {
+		return latinPrototype(r)
+	}
+	if l, ok := scriptLookalikes[target][r]; ok {
+		return string(l), true
+	}
+	return "", false
+}
+
+// latinPrototype returns the Latin letters that r, a letter of another script, looks like.
+func latinPrototype(r rune) (string, bool) {
+	if p, ok := upperConfusables[r]; ok {
+		return string(p), true
+	}
+	if r < 0x80 || unicode.Is(unicode.Latin, r) {
+		return "", false
+	}
+	if r == 'м' {
+		// "rn" in a hostname, but a small capital M in a text
+		return "m", true
+	}
+	p, ok := confusables[r]
+	if !ok {
+		return "", false
+	}
+	for i := 0; i < len(p); i++ {
+		if p[i] < 'a' || p[i] > 'z' {
+			return "", false
+		}
+	}
+	return p, true
+}
+
+func isEmoji(r rune) bool {
+	return unicode.Is(unicode.So, r) || unicode.Is(unicode.Sk, r)
+}
+
+// isInvisible reports the characters that are not rendered: the format characters (zero-width
+// spaces and joiners, soft hyphen, bidi controls, tags), the combining grapheme joiner, the
+// fillers and the blanks that are not spaces. The joiners and the variation selectors are
+// legitimate in the emoji sequences.
+func isInvisible(prev rune, r rune, next rune) bool {
+	switch r {
+	case '\u200d':
+		return !isEmoji(prev) && !isEmoji(next)
+	case '\u034f', '\u115f', '\u1160', '\u17b4', '\u17b5', '\u180e', '\u2800', '\u3164', '\uffa0':
+		return true
+	}
+	if unicode.Is(unicode.Variation_Selector, r) {
+		return unicode.IsLetter(prev)
+	}
+	return unicode.Is(unicode.Cf, r)
+}
+
+// mainScripts are tried first when looking for the script of a character.
+var mainScripts = []string{"Latin", "Common", "Inherited", "Cyrillic", "Greek", "Armenian", "Arabic", "Hebrew", "Han", "Hiragana", "Katakana", "Hangul"}
+
+type scriptCache map[rune]string
+
+// script returns the Unicode script of r.
+func (c scriptCache) script(r rune) string {
+	if r < 0x80 {
+		if unicode.IsLetter(r) {
+			return "Latin"
+		}
+		return "Common"
+	}
+	if s, ok := c[r]; ok {
+		return s
+	}
+	s := ""
+	for _, name := range mainScripts {
+		if unicode.Is(unicode.Scripts[name], r) {
+			s = name
+			break
+		}
+	}
+	if s == "" {
+		for name, table := range unicode.Scripts {
+			if unicode.Is(table, r) {
+				s = name
+				break
+			}
+		}
+	}
+	c[r] = s
+	return s
+}
+
+// singleScript reports whether the set of scripts of a word resolves to a single script, in the
+// sense of UTS #39: the Japanese and Korean writings mix Han with their own scripts.
+func singleScript(scripts map[string]bool) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+	for _, augmented := range [][]string{{"Han", "Hiragana", "Katakana"}, {"Han", "Hangul"}, {"Han", "Bopomofo"}} {
+		n := 0
+		for _, s := range augmented {
+			if scripts[s] {
+				n++
+			}
+		}
+		if n == len(scripts) {
+			return true
+		}
+	}
+	return false
+}
+
+// obfuscation is the result of the analysis of a text.
+type obfuscation struct {
+	models.TextObfuscation
+	deobfuscated string
+}
+
+func analyseObfuscation(raw string) *obfuscation {
+	o := new(obfuscation)
+	runes := []rune(raw)
+	o.Length = len(runes)
+	if o.Length == 0 {
+		return o
+	}
+
+	counts := make(map[rune]int)
+	kept := make([]rune, 0, len(runes))
+	for i, r := range runes {
+		counts[r]++
+		prev, next := ' ', ' '
+		if i > 0 {
+			prev = runes[i-1]
+		}
+		if i < len(runes)-1 {
+			next = runes[i+1]
+		}
+		if isBidiControl(r) {
+			o.BidiControls++
+		}
+		if isInvisible(prev, r, next) {
+			o.InvisibleChars++
+			continue
+		}
+		if r >= 0x80 && !norm.NFKC.IsNormalString(string(r)) {
+			o.CompatibilityChars++
+		}
+		kept = append(kept, r)
+	}
+	for _, c := range counts {
+		p := float64(c) / float64(o.Length)
+		o.Entropy -= p * math.Log2(p)
+	}
+	o.Entropy = round(o.Entropy)
+	o.InvisibleDensity = round(float64(o.InvisibleChars) / float64(o.Length))
+
+	normalized := []rune(norm.NFKC.String(string(kept)))
+	cache := make(scriptCache)
+	// the lookalike words of other scripts are only substituted in a Latin text
+	textScripts := make(map[string]int)
+	letters := 0
+	for _, r := range normalized {
+		if unicode.IsLetter(r) {
+			letters++
+			textScripts[cache.script(r)]++
+		}
+	}
+	textScript := dominantScript(textScripts, "")
+	latinText := textScripts["Latin"]*2 > letters
+
+	var b strings.Builder
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			b.WriteString(o.deobfuscateWord(word, cache, textScript, latinText))
+			word = word[:0]
+		}
+	}
+	for _, r := range normalized {
+		if unicode.IsLetter(r) || unicode.IsMark(r) {
+			word = append(word, r)
+			continue
+		}
+		flush()
+		b.WriteRune(r)
+	}
+	flush()
+	o.deobfuscated = b.String()
+	o.NormalizationDistance = round(math.Min(1, float64(o.InvisibleChars+o.CompatibilityChars+o.ConfusableSubstitutions)/float64(o.Length)))
+	return o
+}
+
+// dominantScript returns the script with the most letters, or fallback on a tie.
+func dominantScript(scripts map[string]int, fallback string) string {
+	best, max, tie := "", 0, false
+	for s, n := range scripts {
+		switch {
+		case n > max:
+			best, max, tie = s, n, false
+		case n == max:
+			tie = true
+		}
+	}
+	if tie {
+		return fallback
+	}
+	return best
+}
+
+// deobfuscateWord counts the mixed scripts and the confusable substitutions in word, and returns
+// it with its lookalike letters replaced by the letters of its script.
+func (o *obfuscation) deobfuscateWord(word []rune, cache scriptCache, textScript string, latinText bool) string {
+	scripts := make(map[string]int)
+	for _, r := range word {
+		if s := cache.script(r); s != "Common" && s != "Inherited" && s != "" {
+			scripts[s]++
+		}
+	}
+	names := make(map[string]bool, len(scripts))
+	for s := range scripts {
+		names[s] = true
+	}
+	mixed := !singleScript(names)
+	if mixed {
+		o.MixedScriptWords++
+		if len(o.MixedScriptSamples) < maxMixedScriptSamples {
+			o.MixedScriptSamples = distinctStrings(append(o.MixedScriptSamples, string(word)))
+		}
+	}
+	var target string
+	switch {
+	case mixed:
+		target = substitutionTarget(word, scripts, cache, textScript)
+	case latinText && len(word) > 1 && scripts["Latin"] == 0 && convertible(word, "Latin", cache):
+		// a whole word of lookalike letters, like a Cyrillic "реасе" in an English text
+		target = "Latin"
+	}
+	if target == "" {
+		return string(word)
+	}
+	var b strings.Builder
+	for _, r := range word {
+		if unicode.IsLetter(r) && cache.script(r) != target {
+			if p, ok := lookalike(r, target); ok {
+				o.ConfusableSubstitutions++
+				b.WriteString(p)
+				continue
+			}
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// substitutionTarget returns the script of a mixed word: a script in which all the letters of the
+// other scripts have a lookalike, preferably the dominant script of the word, then of the text.
+// It returns "" when the word can not be restored in any of its scripts.
+func substitutionTarget(word []rune, scripts map[string]int, cache scriptCache, textScript string) string {
+	candidates := make(map[string]int)
+	for s, n := range scripts {
+		if convertible(word, s, cache) {
+			candidates[s] = n
+		}
+	}
+	if s := dominantScript(candidates, ""); s != "" {
+		return s
+	}
+	if candidates[textScript] > 0 {
+		return textScript
+	}
+	return ""
+}
+
+// convertible reports whether all the letters of word that are not in the target script have a
+// lookalike in it.
+func convertible(word []rune, target string, cache scriptCache) bool {
+	for _, r := range word {
+		if !unicode.IsLetter(r) {
+			continue
+		}
+		if s := cache.script(r); s == target || s == "Common" || s == "Inherited" {
+			continue
+		}
+		if _, ok := lookalike(r, target); !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// AnalyseObfuscation measures the tricks used to defeat the keyword filters in a raw text, before
+// its unicode normalization. It also returns the de-obfuscated text. The measures are nil for an
+// empty text.
+func AnalyseObfuscation(raw string) (*models.TextObfuscation, string) {
+	if raw == "" {
+		return nil, ""
+	}
+	o := analyseObfuscation(raw)
+	return &o.TextObfuscation, o.deobfuscated
+}
+
+// Deobfuscate removes the invisible characters from text, normalizes it, and replaces the
+// lookalike letters of other scripts in the Latin words. The result is meant for the keyword
+// extraction.
+func Deobfuscate(text string) string {
+	return analyseObfuscation(text).deobfuscated
+}
+
+var numericEntityRE = regexp.MustCompile(`&#(?:[xX]([0-9a-fA-F]{1,6})|([0-9]{1,7}));?`)
+
+// HTMLEntityNoise counts the numeric character references of an HTML document that encode plain
+// ASCII letters and digits, and have no reason to be escaped.
+func HTMLEntityNoise(html string) int {
+	n := 0
+	for _, m := range numericEntityRE.FindAllStringSubmatch(html, -1) {
+		var v int64
+		if m[1] != "" {
+			v, _ = strconv.ParseInt(m[1], 16, 32)
+		} else {
+			v, _ = strconv.ParseInt(m[2], 10, 32)
+		}
+		if v < 0x80 && (unicode.IsLetter(rune(v)) || unicode.IsDigit(rune(v))) {
+			n++
+		}
+	}
+	return n
+}
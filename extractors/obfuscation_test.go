@@ -0,0 +1,41 @@
+package extractors
+
+import (
+	"testing"
+)
+
+func TestAnalyseObfuscation(t *testing.T) {
+	cases := []struct {
+		name          string
+		text          string
+		deobfuscated  string
+		mixed         int
+		substitutions int
+		invisible     int
+	}{
+		{"latin letters in a cyrillic word", "пapoль", "пароль", 1, 3, 0},
+		{"latin letters in a russian text", "Ваш пapoль истёк", "Ваш пароль истёк", 1, 3, 0},
+		{"cyrillic letter in a latin word", "Verify your pаypal account", "Verify your paypal account", 1, 1, 0},
+		{"cyrillic word in an english text", "Give реасе a chance", "Give peace a chance", 0, 5, 0},
+		{"russian text", "Ваш пароль истёк", "Ваш пароль истёк", 0, 0, 0},
+		{"russian word in an english text", "The word пароль means password", "The word пароль means password", 0, 0, 0},
+		{"zero-width space", "pass​word", "password", 0, 0, 1},
+		{"japanese", "パスワードの有効期限が切れました", "パスワードの有効期限が切れました", 0, 0, 0},
+		{"greek letter in a latin word", "Micrοsoft", "Microsoft", 1, 1, 0},
+	}
+	for _, c := range cases {
+		o, deobfuscated := AnalyseObfuscation(c.text)
+		if deobfuscated != c.deobfuscated {
+			t.Errorf("%s: deobfuscated = %q, want %q", c.name, deobfuscated, c.deobfuscated)
+		}
+		if o.MixedScriptWords != c.mixed {
+			t.Errorf("%s: mixed script words = %d, want %d", c.name, o.MixedScriptWords, c.mixed)
+		}
+		if o.ConfusableSubstitutions != c.substitutions {
+			t.Errorf("%s: substitutions = %d, want %d", c.name, o.ConfusableSubstitutions, c.substitutions)
+		}
+		if o.InvisibleChars != c.invisible {
+			t.Errorf("%s: invisible chars = %d, want %d", c.name, o.InvisibleChars, c.invisible)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package extractors
+
+import (
+	"bytes"
+	"encoding/binary"
+)
+
+// The files embedded in OneNote sections are stored in FileDataStoreObject structures ([MS-ONESTORE] 2.6.13).
+
+// fileDataStoreHeader is the GUID {BDE316E7-2665-4511-A4C4-8D4D0B7A9EAC} that starts a FileDataStoreObject.
+var fileDataStoreHeader = []byte{
+	0xe7, 0x16, 0xe3, 0xbd, 0x65, 0x26, 0x11, 0x45,
+	0xa4, 0xc4, 0x8d, 0x4d, 0x0b, 0x7a, 0x9e, 0xac,
+}
+
+// fileDataStoreOffset is the size of the guidHeader, cbLength, unused and reserved fields.
+const fileDataStoreOffset = 16 + 8 + 4 + 8
+
+// OneNoteFiles extracts the files embedded in a OneNote section.
+func OneNoteFiles(content []byte) []EmbeddedObject {
+	var objects []EmbeddedObject
+	pos := 0
+	for len(objects) < maxEmbeddedObjects {
+		idx := bytes.Index(content[pos:], fileDataStoreHeader)
+		if idx < 0 {
+			break
+		}
+		start := pos + idx
+		if start+fileDataStoreOffset > len(content) {
+			break
+		}
+		size := binary.LittleEndian.Uint64(content[start+16:])
+		dataStart := start + fileDataStoreOffset
+		if size > uint64(len(content)-dataStart) {
+			// truncated file: keep what is available
+			size = uint64(len(content) - dataStart)
+		}
+		if size > 0 {
+			objects = append(objects, EmbeddedObject{Content: content[dataStart : dataStart+int(size)]})
+		}
+		pos = dataStart + int(size)
+	}
+	return objects
+}
@@ -0,0 +1,65 @@
+package extractors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+// fileDataStoreObject builds a FileDataStoreObject whose cbLength is size, followed by data.
+func fileDataStoreObject(size uint64, data []byte) []byte {
+	b := append([]byte{}, fileDataStoreHeader...)
+	b = append(b, make([]byte, 8+4+8)...)
+	binary.LittleEndian.PutUint64(b[16:], size)
+	return append(b, data...)
+}
+
+func TestOneNoteFiles(t *testing.T) {
+	padding := []byte("section header")
+	first := []byte("MZ first embedded file")
+	second := []byte("<html>second</html>")
+	section := append(append([]byte{}, padding...), fileDataStoreObject(uint64(len(first)), first)...)
+	section = append(section, fileDataStoreObject(uint64(len(second)), second)...)
+
+	cases := []struct {
+		name    string
+		content []byte
+		files   [][]byte
+	}{
+		{"two files", section, [][]byte{first, second}},
+		{"empty", nil, nil},
+		{"no file", []byte("no file data store object here"), nil},
+		{"header only", fileDataStoreHeader, nil},
+		{"truncated header", fileDataStoreObject(10, nil)[:fileDataStoreOffset-1], nil},
+		{"empty file", fileDataStoreObject(0, nil), nil},
+		{"truncated file", fileDataStoreObject(1000, first), [][]byte{first}},
+		{"huge length", fileDataStoreObject(1<<63, second), [][]byte{second}},
+	}
+	for _, c := range cases {
+		objects := OneNoteFiles(c.content)
+		if len(objects) != len(c.files) {
+			t.Errorf("%s: %d files, want %d", c.name, len(objects), len(c.files))
+			continue
+		}
+		for i, o := range objects {
+			if !bytes.Equal(o.Content, c.files[i]) {
+				t.Errorf("%s: file %d is %q, want %q", c.name, i, o.Content, c.files[i])
+			}
+		}
+	}
+
+	// every truncation of the section is read without panic
+	for i := range section {
+		OneNoteFiles(section[:i])
+	}
+}
+
+func TestOneNoteFilesLimit(t *testing.T) {
+	var section []byte
+	for i := 0; i < maxEmbeddedObjects+5; i++ {
+		section = append(section, fileDataStoreObject(1, []byte{'x'})...)
+	}
+	if objects := OneNoteFiles(section); len(objects) != maxEmbeddedObjects {
+		t.Errorf("%d files, want %d", len(objects), maxEmbeddedObjects)
+	}
+}
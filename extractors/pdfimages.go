@@ -0,0 +1,247 @@
+package extractors
+
+import (
+	"bytes"
+	"compress/zlib"
+	"image"
+	"image/color"
+	"io"
+	"io/ioutil"
+	"regexp"
+	"strconv"
+)
+
+var maxPDFImages = 20
+
+var pdfStreamRE = regexp.MustCompile(`>>\s*stream\r?\n`)
+var pdfObj = []byte("obj")
+var pdfEndStream = []byte("endstream")
+var pdfWidthRE = pdfKeyRE("Width")
+var pdfHeightRE = pdfKeyRE("Height")
+var pdfBitsRE = pdfKeyRE("BitsPerComponent")
+var pdfPredictorRE = pdfKeyRE("Predictor")
+var pdfColorsRE = pdfKeyRE("Colors")
+var pdfColumnsRE = pdfKeyRE("Columns")
+var pdfImageRE = regexp.MustCompile(`/Subtype\s*/Image`)
+var pdfFilterRE = regexp.MustCompile(`/Filter\s*(?:/(\w+)|\[\s*/(\w+)\s*(/\w+)?\s*\])`)
+
+// PDFImages extracts the image XObjects embedded in a PDF document, without rendering it.
+// Only the JPEG (DCTDecode) and the raw or deflated (FlateDecode) images are returned.
+func PDFImages(content []byte) (images []image.Image) {
+	for _, loc := range pdfStreamRE.FindAllIndex(content, -1) {
+		if len(images) >= maxPDFImages {
+			break
+		}
+		// the stream dictionary starts after the "obj" keyword of the object
+		objStart := bytes.LastIndex(content[:loc[0]], pdfObj)
+		if objStart == -1 {
+			continue
+		}
+		dict := content[objStart:loc[0]]
+		if !pdfImageRE.Match(dict) {
+			continue
+		}
+		start := loc[1]
+		end := bytes.Index(content[start:], pdfEndStream)
+		if end == -1 {
+			break
+		}
+		data := bytes.TrimRight(content[start:start+end], "\r\n")
+		img := pdfImage(dict, data)
+		if img != nil {
+			images = append(images, img)
+		}
+	}
+	return images
+}
+
+func pdfKeyRE(key string) *regexp.Regexp {
+	return regexp.MustCompile(`/` + key + `\s+(\d+)`)
+}
+
+func pdfInt(re *regexp.Regexp, dict []byte, def int) int {
+	m := re.FindSubmatch(dict)
+	if m == nil {
+		return def
+	}
+	i, err := strconv.Atoi(string(m[1]))
+	if err != nil {
+		return def
+	}
+	return i
+}
+
+func pdfImage(dict []byte, data []byte) image.Image {
+	filter := ""
+	if m := pdfFilterRE.FindSubmatch(dict); m != nil {
+		if len(m[3]) > 0 {
+			// chained filters are not supported
+			return nil
+		}
+		filter = string(m[1]) + string(m[2])
+	}
+	if filter == "DCTDecode" || filter == "DCT" {
+		// the dimensions are checked in the JPEG header
+		img, err := DecodeImage(data)
+		if err != nil {
+			return nil
+		}
+		return img
+	}
+	width := pdfInt(pdfWidthRE, dict, 0)
+	height := pdfInt(pdfHeightRE, dict, 0)
+	bits := pdfInt(pdfBitsRE, dict, 8)
+	if width <= 0 || height <= 0 || width > maxImagePixels/height {
+		return nil
+	}
+	if bytes.Contains(dict, []byte("/ImageMask")) {
+		bits = 1
+	}
+	if bits != 1 && bits != 2 && bits != 4 && bits != 8 && bits != 16 {
+		return nil
+	}
+	switch filter {
+	case "FlateDecode", "Fl":
+		r, err := zlib.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil
+		}
+		// at most 4 components, and the predictor byte of each row
+		maxSize := int64(height) * int64((width*4*bits+7)/8+1)
+		inflated, err := ioutil.ReadAll(io.LimitReader(r, maxSize+1))
+		if int64(len(inflated)) > maxSize || (err != nil && len(inflated) == 0) {
+			return nil
+		}
+		data = inflated
+	case "":
+	default:
+		return nil
+	}
+	if predictor := pdfInt(pdfPredictorRE, dict, 1); predictor >= 10 {
+		colors := pdfInt(pdfColorsRE, dict, 1)
+		columns := pdfInt(pdfColumnsRE, dict, width)
+		if colors < 1 || colors > 4 || columns < 1 || columns > width {
+			return nil
+		}
+		data = pngUnpredict(data, colors, bits, columns)
+		if data == nil {
+			return nil
+		}
+	}
+	return rawImage(data, width, height, bits)
+}
+
+// pngUnpredict reverts the PNG predictors applied to the rows of an image.
+func pngUnpredict(data []byte, colors, bits, columns int) []byte {
+	rowSize := (colors*bits*columns + 7) / 8
+	bpp := (colors*bits + 7) / 8
+	if rowSize <= 0 {
+		return nil
+	}
+	result := make([]byte, 0, len(data))
+	previous := make([]byte, rowSize)
+	for len(data) >= rowSize+1 {
+		filter := data[0]
+		row := append([]byte{}, data[1:rowSize+1]...)
+		data = data[rowSize+1:]
+		for i := range row {
+			var left, upLeft byte
+			if i >= bpp {
+				left = row[i-bpp]
+				upLeft = previous[i-bpp]
+			}
+			up := previous[i]
+			switch filter {
+			case 1:
+				row[i] += left
+			case 2:
+				row[i] += up
+			case 3:
+				row[i] += byte((int(left) + int(up)) / 2)
+			case 4:
+				row[i] += paeth(left, up, upLeft)
+			}
+		}
+		result = append(result, row...)
+		previous = row
+	}
+	return result
+}
+
+func paeth(a, b, c byte) byte {
+	p := int(a) + int(b) - int(c)
+	pa, pb, pc := p-int(a), p-int(b), p-int(c)
+	if pa < 0 {
+		pa = -pa
+	}
+	if pb < 0 {
+		pb = -pb
+	}
+	if pc < 0 {
+		pc = -pc
+	}
+	if pa <= pb && pa <= pc {
+		return a
+	}
+	if pb <= pc {
+		return b
+	}
+	return c
+}
+
+// rawImage builds an image from uncompressed samples. The number of color components
+// is deduced from the size of the data, so that indexed and ICC based color spaces
+// don't need to be resolved: the QR codes only need the luminance.
+func rawImage(data []byte, width, height, bits int) image.Image {
+	if bits != 1 && bits != 2 && bits != 4 && bits != 8 && bits != 16 {
+		return nil
+	}
+	components := 0
+	for _, c := range []int{4, 3, 1} {
+		if len(data) >= height*((width*c*bits+7)/8) {
+			components = c
+			break
+		}
+	}
+	if components == 0 {
+		return nil
+	}
+	rowSize := (width*components*bits + 7) / 8
+	sample := func(row []byte, idx int) uint8 {
+		switch bits {
+		case 8:
+			return row[idx]
+		case 16:
+			return row[2*idx]
+		}
+		bitPos := idx * bits
+		v := (row[bitPos/8] >> uint(8-bits-bitPos%8)) & (1<<uint(bits) - 1)
+		return uint8(int(v) * 255 / (1<<uint(bits) - 1))
+	}
+	if components == 1 {
+		img := image.NewGray(image.Rect(0, 0, width, height))
+		for y := 0; y < height; y++ {
+			row := data[y*rowSize : (y+1)*rowSize]
+			for x := 0; x < width; x++ {
+				img.SetGray(x, y, color.Gray{Y: sample(row, x)})
+			}
+		}
+		return img
+	}
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		row := data[y*rowSize : (y+1)*rowSize]
+		for x := 0; x < width; x++ {
+			var c color.RGBA
+			if components == 3 {
+				c = color.RGBA{R: sample(row, 3*x), G: sample(row, 3*x+1), B: sample(row, 3*x+2), A: 0xff}
+			} else {
+				cyan, magenta, yellow, black := sample(row, 4*x), sample(row, 4*x+1), sample(row, 4*x+2), sample(row, 4*x+3)
+				r, g, b := color.CMYKToRGB(cyan, magenta, yellow, black)
+				c = color.RGBA{R: r, G: g, B: b, A: 0xff}
+			}
+			img.SetRGBA(x, y, c)
+		}
+	}
+	return img
+}
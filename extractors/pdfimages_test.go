@@ -0,0 +1,111 @@
+package extractors
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"testing"
+)
+
+func deflate(data []byte) []byte {
+	var buf bytes.Buffer
+	w := zlib.NewWriter(&buf)
+	_, _ = w.Write(data)
+	_ = w.Close()
+	return buf.Bytes()
+}
+
+// pdfWithImage returns a minimal PDF document with one image XObject.
+func pdfWithImage(dict string, data []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("%PDF-1.4\n1 0 obj\n<< /Type /XObject /Subtype /Image " + dict + fmt.Sprintf(" /Length %d", len(data)) + " >>\nstream\n")
+	buf.Write(data)
+	buf.WriteString("\nendstream\nendobj\n%%EOF\n")
+	return buf.Bytes()
+}
+
+func TestPDFImages(t *testing.T) {
+	gray := make([]byte, 8*6)
+	for i := range gray {
+		gray[i] = byte(i * 5)
+	}
+	var predicted []byte
+	for y := 0; y < 6; y++ {
+		// the "up" PNG predictor
+		predicted = append(predicted, 2)
+		for x := 0; x < 8; x++ {
+			if y == 0 {
+				predicted = append(predicted, gray[x])
+			} else {
+				predicted = append(predicted, gray[y*8+x]-gray[(y-1)*8+x])
+			}
+		}
+	}
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, image.NewGray(image.Rect(0, 0, 16, 12)), nil); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		name   string
+		dict   string
+		data   []byte
+		width  int
+		height int
+	}{
+		{"raw", "/Width 8 /Height 6 /BitsPerComponent 8 /ColorSpace /DeviceGray", gray, 8, 6},
+		{"flate", "/Width 8 /Height 6 /BitsPerComponent 8 /ColorSpace /DeviceGray /Filter /FlateDecode", deflate(gray), 8, 6},
+		{"flate with predictor", "/Width 8 /Height 6 /BitsPerComponent 8 /Filter [/FlateDecode] /DecodeParms << /Predictor 12 /Colors 1 /Columns 8 >>", deflate(predicted), 8, 6},
+		{"jpeg", "/Width 16 /Height 12 /BitsPerComponent 8 /Filter /DCTDecode", jpg.Bytes(), 16, 12},
+	}
+	for _, c := range cases {
+		images := PDFImages(pdfWithImage(c.dict, c.data))
+		if len(images) != 1 {
+			t.Errorf("%s: %d images", c.name, len(images))
+			continue
+		}
+		if b := images[0].Bounds(); b.Dx() != c.width || b.Dy() != c.height {
+			t.Errorf("%s: bounds %v", c.name, b)
+		}
+		if g, ok := images[0].(*image.Gray); ok && c.name != "jpeg" && !bytes.Equal(g.Pix, gray) {
+			t.Errorf("%s: pixels %v", c.name, g.Pix)
+		}
+	}
+}
+
+func TestPDFImagesBombs(t *testing.T) {
+	// 64 MB of zeros, deflated to 64 KB, for a 10x10 image
+	bomb := deflate(make([]byte, 64<<20))
+	// a JPEG header that announces 60000x60000 pixels
+	var jpg bytes.Buffer
+	if err := jpeg.Encode(&jpg, image.NewGray(image.Rect(0, 0, 16, 12)), nil); err != nil {
+		t.Fatal(err)
+	}
+	huge := jpg.Bytes()
+	sof := bytes.Index(huge, []byte{0xff, 0xc0})
+	if sof < 0 {
+		t.Fatal("no SOF0 marker")
+	}
+	copy(huge[sof+5:], []byte{0xea, 0x60, 0xea, 0x60})
+
+	cases := []struct {
+		name string
+		dict string
+		data []byte
+	}{
+		{"flate bomb", "/Width 10 /Height 10 /BitsPerComponent 8 /Filter /FlateDecode", bomb},
+		{"huge jpeg", "/Width 16 /Height 12 /Filter /DCTDecode", huge},
+		{"huge dimensions", "/Width 100000 /Height 100000 /BitsPerComponent 8 /Filter /FlateDecode", deflate(make([]byte, 100))},
+		{"huge predictor columns", "/Width 10 /Height 10 /BitsPerComponent 8 /Filter /FlateDecode /DecodeParms << /Predictor 12 /Columns 1000000000 >>", deflate(make([]byte, 110))},
+		{"invalid bits", "/Width 10 /Height 10 /BitsPerComponent 3", make([]byte, 100)},
+		{"chained filters", "/Width 10 /Height 10 /Filter [/ASCIIHexDecode /FlateDecode]", deflate(make([]byte, 100))},
+		{"truncated deflate", "/Width 10 /Height 10 /Filter /FlateDecode", deflate(make([]byte, 100))[:4]},
+	}
+	for _, c := range cases {
+		if images := PDFImages(pdfWithImage(c.dict, c.data)); len(images) != 0 {
+			t.Errorf("%s: %d images", c.name, len(images))
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package extractors
+
+import (
+	"errors"
+	"strings"
+)
+
+// Punycode parameters, from RFC 3492
+const (
+	punyBase        = 36
+	punyTMin        = 1
+	punyTMax        = 26
+	punySkew        = 38
+	punyDamp        = 700
+	punyInitialBias = 72
+	punyInitialN    = 128
+	acePrefix       = "xn--"
+)
+
+var errPunycode = errors.New("invalid punycode")
+
+// ToUnicode decodes the punycode (xn--) labels of a domain name. The labels that can not be
+// decoded are kept as is.
+func ToUnicode(domain string) string {
+	if !strings.Contains(domain, acePrefix) {
+		return domain
+	}
+	labels := strings.Split(domain, ".")
+	for i, label := range labels {
+		if !strings.HasPrefix(strings.ToLower(label), acePrefix) {
+			continue
+		}
+		decoded, err := decodePunycode(label[len(acePrefix):])
+		if err == nil {
+			labels[i] = decoded
+		}
+	}
+	return strings.Join(labels, ".")
+}
+
+func punyAdapt(delta, numPoints int, first bool) int {
+	if first {
+		delta /= punyDamp
+	} else {
+		delta /= 2
+	}
+	delta += delta / numPoints
+	k := 0
+	for delta > ((punyBase-punyTMin)*punyTMax)/2 {
+		delta /= punyBase - punyTMin
+		k += punyBase
+	}
+	return k + (punyBase-punyTMin+1)*delta/(delta+punySkew)
+}
+
+func punyDigit(c byte) (int, bool) {
+	switch {
+	case c >= '0' && c <= '9':
+		return int(c-'0') + 26, true
+	case c >= 'a' && c <= 'z':
+		return int(c - 'a'), true
+	case c >= 'A' && c <= 'Z':
+		return int(c - 'A'), true
+	}
+	return 0, false
+}
+
+func decodePunycode(s string) (string, error) {
+	var output []rune
+	if pos := strings.LastIndex(s, "-"); pos >= 0 {
+		for _, r := range s[:pos] {
+			if r >= 0x80 {
+				return "", errPunycode
+			}
+			output = append(output, r)
+		}
+		s = s[pos+1:]
+	}
+	n, i, bias := punyInitialN, 0, punyInitialBias
+	for len(s) > 0 {
+		oldi, w := i, 1
+		for k := punyBase; ; k += punyBase {
+			if len(s) == 0 {
+				return "", errPunycode
+			}
+			digit, ok := punyDigit(s[0])
+			s = s[1:]
+			if !ok {
+				return "", errPunycode
+			}
+			i += digit * w
+			if i < 0 || i > 0x10FFFF*punyBase {
+				return "", errPunycode
+			}
+			t := k - bias
+			if t < punyTMin {
+				t = punyTMin
+			} else if t > punyTMax {
+				t = punyTMax
+			}
+			if digit < t {
+				break
+			}
+			w *= punyBase - t
+		}
+		bias = punyAdapt(i-oldi, len(output)+1, oldi == 0)
+		n += i / (len(output) + 1)
+		if n > 0x10FFFF {
+			return "", errPunycode
+		}
+		i %= len(output) + 1
+		output = append(output, 0)
+		copy(output[i+1:], output[i:])
+		output[i] = rune(n)
+		i++
+	}
+	return string(output), nil
+}
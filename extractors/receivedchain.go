@@ -0,0 +1,158 @@
+package extractors
+
+import (
+	"net"
+	"strings"
+	"time"
+
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+// clockSkew is the tolerance on the timestamps of consecutive hops.
+const clockSkew = 5 * time.Minute
+
+// maxTransit is the transit time after which an untrusted timestamp is suspicious.
+const maxTransit = 7 * 24 * time.Hour
+
+// AnalyseReceivedChain computes the transit delays of the hops, and finds the first hop received
+// from outside the internal networks. The hops are in header order, the newest first. The private
+// and loopback addresses are always internal.
+func AnalyseReceivedChain(hops []models.ReceivedElement, internal []*net.IPNet) *models.ReceivedChain {
+	chain := &models.ReceivedChain{
+		Hops:             len(hops),
+		FirstExternalHop: -1,
+	}
+	if len(hops) == 0 {
+		return chain
+	}
+
+	var newest, oldest *time.Time
+	for i := range hops {
+		t := hops[i].Timestamp
+		if t == nil {
+			continue
+		}
+		if newest == nil {
+			newest = t
+		}
+		oldest = t
+		if i+1 < len(hops) && hops[i+1].Timestamp != nil {
+			delay := t.Sub(*hops[i+1].Timestamp)
+			seconds := delay.Seconds()
+			hops[i].Delay = &seconds
+			if delay < -clockSkew {
+				hops[i].OutOfOrder = true
+				chain.OutOfOrder = true
+			}
+		}
+	}
+	if newest != nil && oldest != newest {
+		total := newest.Sub(*oldest).Seconds()
+		chain.TotalTransit = &total
+	}
+
+	for i := range hops {
+		ip := hopIP(hops[i])
+		if ip != nil && !ip.IsLoopback() && !hops[i].Encrypted {
+			chain.CleartextHops++
+		}
+	}
+
+	for i := range hops {
+		hops[i].Trusted = true
+		ip := hopIP(hops[i])
+		if ip == nil || isInternalIP(ip, internal) {
+			continue
+		}
+		chain.FirstExternalHop = i
+		chain.SendingIP = ip.String()
+		chain.SendingLocation = hops[i].From.IP.Location
+		break
+	}
+
+	if chain.FirstExternalHop >= 0 {
+		for i := chain.FirstExternalHop + 1; i < len(hops); i++ {
+			hops[i].ForgeryHints = forgeryHints(hops, i, chain.FirstExternalHop, internal)
+			if len(hops[i].ForgeryHints) > 0 {
+				chain.Forged = true
+			}
+		}
+	}
+	return chain
+}
+
+func hopIP(hop models.ReceivedElement) net.IP {
+	if hop.From == nil || hop.From.IP == nil {
+		return nil
+	}
+	return hop.From.IP.Parsed
+}
+
+func isInternalIP(ip net.IP, internal []*net.IPNet) bool {
+	if ip.IsLoopback() || utils.IsPrivateIP(ip) {
+		return true
+	}
+	return inNetworks(ip, internal)
+}
+
+func inNetworks(ip net.IP, networks []*net.IPNet) bool {
+	for _, n := range networks {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// forgeryHints checks an untrusted hop, added before the mail reached the internal networks.
+func forgeryHints(hops []models.ReceivedElement, i int, boundary int, internal []*net.IPNet) (hints []string) {
+	hop := hops[i]
+	if hop.From == nil && hop.By == nil {
+		return []string{"unparseable header"}
+	}
+	if hop.Timestamp != nil {
+		if newer := hops[i-1].Timestamp; newer != nil && hop.Timestamp.After(newer.Add(clockSkew)) {
+			hints = append(hints, "timestamp after the next hop")
+		}
+		if delivery := hops[boundary].Timestamp; delivery != nil && delivery.Sub(*hop.Timestamp) > maxTransit {
+			hints = append(hints, "timestamp long before the delivery")
+		}
+	}
+	// the sender claims that the mail went through the internal networks
+	if ip := hopIP(hop); ip != nil && inNetworks(ip, internal) {
+		hints = append(hints, "received from an internal address")
+	}
+	if hop.By != nil && hop.By.Name != "" {
+		for _, trusted := range hops[:boundary+1] {
+			if trusted.By != nil && strings.EqualFold(trusted.By.Name, hop.By.Name) {
+				hints = append(hints, "received by an internal MTA")
+				break
+			}
+		}
+	}
+	// the hop just below the boundary should be added by the host that connected to the internal MTAs
+	if i == boundary+1 && hop.By != nil && isHostname(hop.By.Name) && !connectedHost(hops[boundary].From, hop.By.Name) {
+		hints = append(hints, "not received by the host that connected to the internal MTAs")
+	}
+	return hints
+}
+
+func connectedHost(from *models.ReceivedFrom, name string) bool {
+	if from == nil {
+		return true
+	}
+	candidates := []string{from.ReportedName, from.DNSReversedName}
+	known := false
+	for _, c := range candidates {
+		if !isHostname(c) {
+			continue
+		}
+		known = true
+		if utils.SameDomain(c, name) {
+			return true
+		}
+	}
+	// nothing to compare with
+	return !known
+}
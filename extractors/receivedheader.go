@@ -1,69 +1,230 @@
 package extractors
 
 import (
-	"github.com/inconshreveable/log15"
-	"github.com/stephane-martin/mailstats/models"
-	"github.com/stephane-martin/mailstats/utils"
 	"net"
+	"net/mail"
 	"regexp"
 	"strings"
 	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
 )
 
-// from m0892.contabo.net ([91.194.91.211]:40338 helo=91.194.91.211) by m1363.contabo.net with esmtpsa (TLSv1.2:ECDHE-RSA-AES256-GCM-SHA384:256) (Exim 4.88) (envelope-from <support@contabo.com>) id 1ciAQt-0005YX-7L; Mon, 27 Feb 2017 02:48:59 +0100
-// (envelope-from <newsletter@liberation.cccampaigns.net>)
-// (envelope-from <bounce-md_30850198.5c14c57c.v1-a48f024874c347249d7f5eb681ffab1d@mandrillapp.com>)
+// Received headers follow the grammar of RFC 5321: clauses introduced by "from", "by", "via",
+// "with", "id" and "for", each one followed by a value and by comments, and then the date after
+// a semicolon. The MTAs put different information in the comments:
+//
+// Postfix:  from mail.example.com (mail.example.com [192.0.2.1]) (using TLSv1.3 with cipher ...) (Authenticated sender: x) by mx.example.org (Postfix) with ESMTPSA id 4Xy; date
+// Exim:     from m0892.contabo.net ([91.194.91.211]:40338 helo=91.194.91.211) by m1363.contabo.net with esmtpsa (TLSv1.2:ECDHE-RSA-AES256-GCM-SHA384:256) (Exim 4.88) (envelope-from <support@contabo.com>) id 1ciAQt-0005YX-7L; date
+// Sendmail: from host.example.com (host.example.com [192.0.2.1]) by mx.example.org (8.14.4/8.14.4) with ESMTP id x; date
+// Gmail:    from mail-sor-f41.google.com (mail-sor-f41.google.com. [209.85.220.41]) by mx.google.com with SMTPS id x (Google Transport Security); date
+// Exchange: from EX1.corp.local (10.0.0.1) by EX2.corp.local (10.0.0.2) with Microsoft SMTP Server (version=TLS1_2, cipher=TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384) id 15.1.1713.5; date
+// qmail:    from unknown (HELO mail.example.com) (192.0.2.1) by mx.example.org with SMTP; date
+//           (qmail 1234 invoked from network); date
 
+var receivedKeywords = map[string]bool{
+	"from": true,
+	"by":   true,
+	"via":  true,
+	"with": true,
+	"id":   true,
+	"for":  true,
+}
 
-var receivedHeaderRE = regexp.MustCompile(`(from ([^\s();]+)\s+(\(\s*(([^\s\[]+)?(\s*\[\s*(\S+)\s*][:\d]*\s*)?)\)\s+)?)?by\s+([^\s;]+)(\s+\(([^;)]+\)*)\))?(\s+with\s+([^\s;]+))?(\s+id\s+([^\s;]+))?(\s+\(([^)]+)\))?(\s+for\s+([^\s;]+))?;?(.*?)$`)
-var envelopeRE = regexp.MustCompile(`\(envelope-from (\S+)\)`)
-var heloRE = regexp.MustCompile(`helo=([^\s()[\]]+)`)
+type receivedClause struct {
+	value    string
+	comments []string
+}
 
-func ParseReceivedHeader(h string, geoip utils.GeoIP, logger log15.Logger) (e models.ReceivedElement) {
-	e.Raw = h
-	envMatches := envelopeRE.FindStringSubmatch(h)
-	if len(envMatches) == 2 {
-		e.EnvelopeFrom = strings.TrimSpace(envMatches[1])
-		h = envelopeRE.ReplaceAllString(h, "")
-	}
-	heloMatches := heloRE.FindStringSubmatch(h)
-	if len(heloMatches) == 2 {
-		e.Helo = strings.TrimSpace(heloMatches[1])
-		h = heloRE.ReplaceAllString(h, "")
-	}
-	matches := receivedHeaderRE.FindStringSubmatch(h)
-	if len(matches) == 0 {
-		return e
-	}
-	if len(matches) > 2 && matches[2] != "" {
-		e.From = new(models.ReceivedFrom)
-		e.From.ReportedName = strings.Trim(matches[2], "[]")
-		if net.ParseIP(e.From.ReportedName) != nil {
-			e.From.IP = &models.ReceivedIP{Reported: e.From.ReportedName}
-			if e.Helo != "" {
-				e.From.ReportedName = e.Helo
+var bracketIPRE = regexp.MustCompile(`\[(?:IPv6:)?([0-9a-fA-F:.]+)\]`)
+var envelopeRE = regexp.MustCompile(`(?i)^envelope-from\s+<?([^\s>]*)>?`)
+var heloRE = regexp.MustCompile(`(?i)\bhelo[=\s]+([^\s()[\]]+)`)
+var authenticatedRE = regexp.MustCompile(`(?i)^(authenticated sender|authenticated as|authenticated user):?\s*(\S*)`)
+var tlsRE = regexp.MustCompile(`(?i)(\bTLS ?v?1[._]?[0-3]\b|\bTLS1_[0-3]\b|\bSSLv3\b|\bversion=TLS|\bcipher[= ]|Google Transport Security)`)
+var encryptedProtocolRE = regexp.MustCompile(`(?i)^(utf8)?(e?smtp|lmtp)s`)
+var authenticatedProtocolRE = regexp.MustCompile(`(?i)^(utf8)?(e?smtp|lmtp)s?a$`)
+var mtaRE = []struct {
+	name string
+	re   *regexp.Regexp
+}{
+	{"Postfix", regexp.MustCompile(`(?i)^postfix\b`)},
+	{"Exim", regexp.MustCompile(`(?i)^exim\b`)},
+	{"Sendmail", regexp.MustCompile(`^\d+\.\d+\.\d+(/[\d.]+)*$`)},
+	{"Microsoft Exchange", regexp.MustCompile(`(?i)^microsoft smtp`)},
+	{"qmail", regexp.MustCompile(`(?i)^qmail\b`)},
+	{"Gmail", regexp.MustCompile(`(?i)^google transport security`)},
+}
+
+// splitReceived separates the clauses of a Received header from its date.
+func splitReceived(h string) (string, string) {
+	depth := 0
+	last := -1
+	for i, c := range h {
+		switch c {
+		case '(':
+			depth++
+		case ')':
+			if depth > 0 {
+				depth--
+			}
+		case ';':
+			if depth == 0 {
+				last = i
 			}
 		}
 	}
+	if last < 0 {
+		return h, ""
+	}
+	return h[:last], h[last+1:]
+}
 
-	if len(matches) > 5 && matches[5] != "" {
-		if e.From == nil {
-			e.From = new(models.ReceivedFrom)
+// tokenizeReceived returns the words and the comments (between parentheses) of a header.
+func tokenizeReceived(h string) (tokens []string) {
+	var b strings.Builder
+	depth := 0
+	flush := func() {
+		if b.Len() > 0 {
+			tokens = append(tokens, b.String())
+			b.Reset()
 		}
-		e.From.DNSReversedName = matches[5]
 	}
-	if len(matches) > 7 && matches[7] != "" {
-		if e.From == nil {
-			e.From = new(models.ReceivedFrom)
+	for _, c := range h {
+		switch {
+		case c == '(':
+			if depth == 0 {
+				flush()
+			}
+			depth++
+			b.WriteRune(c)
+		case c == ')' && depth > 0:
+			depth--
+			b.WriteRune(c)
+			if depth == 0 {
+				flush()
+			}
+		case depth == 0 && (c == ' ' || c == '\t' || c == '\r' || c == '\n'):
+			flush()
+		default:
+			b.WriteRune(c)
 		}
-		if e.From.IP == nil {
-			e.From.IP = &models.ReceivedIP{Reported: matches[7]}
+	}
+	flush()
+	return tokens
+}
+
+func isComment(token string) bool {
+	return strings.HasPrefix(token, "(")
+}
+
+func commentContent(token string) string {
+	return strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(token, "("), ")"))
+}
+
+// parseClauses groups the tokens by clause. Comments before the first clause are returned apart.
+func parseClauses(tokens []string) (map[string]*receivedClause, []string) {
+	clauses := make(map[string]*receivedClause)
+	var current *receivedClause
+	var leading []string
+	for _, token := range tokens {
+		if isComment(token) {
+			if current == nil {
+				leading = append(leading, commentContent(token))
+			} else {
+				current.comments = append(current.comments, commentContent(token))
+			}
+			continue
+		}
+		keyword := strings.ToLower(token)
+		if receivedKeywords[keyword] {
+			if _, ok := clauses[keyword]; !ok {
+				current = new(receivedClause)
+				clauses[keyword] = current
+				continue
+			}
+		}
+		if current == nil {
+			continue
+		}
+		if current.value == "" {
+			current.value = token
+		} else {
+			current.value += " " + token
 		}
 	}
+	return clauses, leading
+}
+
+// hostValue cleans a host name or an address literal.
+func hostValue(s string) string {
+	s = strings.TrimSpace(s)
+	if m := bracketIPRE.FindStringSubmatch(s); m != nil && strings.HasPrefix(s, "[") {
+		return m[1]
+	}
+	return strings.TrimSuffix(s, ".")
+}
+
+func ParseReceivedHeader(h string, geoip utils.GeoIP, logger log15.Logger) (e models.ReceivedElement) {
+	e.Raw = h
+	h = strings.Join(strings.Fields(h), " ")
+	clausesPart, datePart := splitReceived(h)
+	clauses, leading := parseClauses(tokenizeReceived(clausesPart))
+	e.Timestamp = parseDate(datePart, logger)
+
+	var allComments []string
+	allComments = append(allComments, leading...)
+	for _, keyword := range []string{"from", "by", "via", "with", "id", "for"} {
+		if c, ok := clauses[keyword]; ok {
+			allComments = append(allComments, c.comments...)
+		}
+	}
+	for _, comment := range allComments {
+		if m := envelopeRE.FindStringSubmatch(comment); m != nil {
+			e.EnvelopeFrom = m[1]
+		}
+		if m := authenticatedRE.FindStringSubmatch(comment); m != nil {
+			e.Authenticated = true
+			e.AuthenticatedSender = m[2]
+		}
+		if e.TLS == "" && tlsRE.MatchString(comment) {
+			e.TLS = comment
+		}
+	}
+
+	if from, ok := clauses["from"]; ok {
+		e.From = parseFromClause(from, &e)
+	}
+	if by, ok := clauses["by"]; ok {
+		e.By = &models.ReceivedBy{Name: hostValue(by.value)}
+		if len(by.comments) > 0 && !tlsRE.MatchString(by.comments[0]) && net.ParseIP(by.comments[0]) == nil {
+			e.By.Software = by.comments[0]
+		}
+	}
+	if with, ok := clauses["with"]; ok {
+		e.Protocol = with.value
+	}
+	if id, ok := clauses["id"]; ok {
+		e.ID = strings.Trim(id.value, "<>")
+	}
+	if f, ok := clauses["for"]; ok {
+		e.For = strings.Trim(f.value, "<>")
+	}
+	e.MTA = detectMTA(e.Protocol, allComments)
+	if e.By == nil && e.MTA == "qmail" && len(leading) > 0 {
+		e.By = &models.ReceivedBy{Software: leading[0]}
+	}
+
+	e.Encrypted = e.TLS != "" || encryptedProtocolRE.MatchString(e.Protocol)
+	if authenticatedProtocolRE.MatchString(e.Protocol) {
+		e.Authenticated = true
+	}
+
 	if e.From != nil && e.From.IP != nil && e.From.IP.Reported != "" {
 		if ip := net.ParseIP(e.From.IP.Reported); ip != nil {
 			e.From.IP.Parsed = ip
-			e.From.IP.Public = !utils.IsPrivateIP(e.From.IP.Parsed)
+			e.From.IP.Public = !utils.IsPrivateIP(e.From.IP.Parsed) && !ip.IsLoopback()
 			if e.From.IP.Public && geoip != nil {
 				l, err := geoip.GeoIP(e.From.IP.Parsed)
 				if err == nil {
@@ -72,33 +233,82 @@ func ParseReceivedHeader(h string, geoip utils.GeoIP, logger log15.Logger) (e mo
 			}
 		}
 	}
+	return e
+}
 
-	if len(matches) > 8 && matches[8] != "" {
-		e.By = new(models.ReceivedBy)
-		e.By.Name = matches[8]
+// parseFromClause reads the host reported by the client and the host seen by the server.
+func parseFromClause(clause *receivedClause, e *models.ReceivedElement) *models.ReceivedFrom {
+	from := new(models.ReceivedFrom)
+	reported := hostValue(clause.value)
+	// an address literal is the HELO of the client: the address seen by the server, in the
+	// comments, is preferred
+	var literal string
+	if net.ParseIP(reported) != nil {
+		literal = reported
 	}
-	if len(matches) > 10 && matches[10] != "" {
-		if e.By == nil {
-			e.By = new(models.ReceivedBy)
+	from.ReportedName = reported
+
+	for _, comment := range clause.comments {
+		if m := heloRE.FindStringSubmatch(comment); m != nil {
+			e.Helo = hostValue(m[1])
+			comment = strings.TrimSpace(heloRE.ReplaceAllString(comment, ""))
+		}
+		if from.IP == nil {
+			if m := bracketIPRE.FindStringSubmatch(comment); m != nil && net.ParseIP(m[1]) != nil {
+				from.IP = &models.ReceivedIP{Reported: m[1]}
+			}
+		}
+		for _, word := range strings.Fields(comment) {
+			word = strings.TrimSuffix(strings.TrimSuffix(word, ","), ".")
+			// qmail reports user@ip
+			if idx := strings.LastIndex(word, "@"); idx >= 0 {
+				word = word[idx+1:]
+			}
+			if ip := net.ParseIP(word); ip != nil {
+				if from.IP == nil {
+					from.IP = &models.ReceivedIP{Reported: word}
+				}
+				continue
+			}
+			if from.DNSReversedName == "" && isHostname(word) {
+				from.DNSReversedName = word
+			}
+			// only the first word of a comment may be the reverse name
+			break
 		}
-		e.By.Software = matches[10]
-	}
-	if len(matches) > 12 && matches[12] != "" {
-		e.Protocol = matches[12]
 	}
-	if len(matches) > 14 && matches[14] != "" {
-		e.ID = matches[14]
+	if literal != "" {
+		if from.IP == nil {
+			from.IP = &models.ReceivedIP{Reported: literal}
+		} else if from.IP.Reported != literal && e.Helo == "" {
+			e.Helo = literal
+		}
 	}
-	if len(matches) > 16 && matches[16] != "" {
-		e.TLS = matches[16]
+	if from.IP != nil && from.ReportedName == from.IP.Reported && e.Helo != "" {
+		from.ReportedName = e.Helo
 	}
-	if len(matches) > 18 && matches[18] != "" {
-		e.For = matches[18]
+	if strings.EqualFold(from.ReportedName, "unknown") && e.Helo != "" {
+		from.ReportedName = e.Helo
 	}
-	if len(matches) > 19 {
-		e.Timestamp = parseDate(matches[19], logger)
+	return from
+}
+
+var hostnameRE = regexp.MustCompile(`^(?i)[a-z0-9_]([a-z0-9_-]*[a-z0-9_])?(\.[a-z0-9_]([a-z0-9_-]*[a-z0-9_])?)+$`)
+
+func isHostname(s string) bool {
+	return hostnameRE.MatchString(s)
+}
+
+func detectMTA(protocol string, comments []string) string {
+	candidates := append([]string{protocol}, comments...)
+	for _, m := range mtaRE {
+		for _, c := range candidates {
+			if m.re.MatchString(c) {
+				return m.name
+			}
+		}
 	}
-	return e
+	return ""
 }
 
 func parseDate(d string, logger log15.Logger) *time.Time {
@@ -118,6 +328,9 @@ func parseDate(d string, logger log15.Logger) *time.Time {
 			return &t
 		}
 	}
+	if t, err := mail.ParseDate(d); err == nil {
+		return &t
+	}
 	logger.Info("Failed to parse timestamp", "timestamp", d)
 
 	return nil
@@ -126,6 +339,7 @@ func parseDate(d string, logger log15.Logger) *time.Time {
 // Thu,  6 Dec 2018 12:20:17 +0100 (CET)
 // Thu, 6 Dec 2018 12:20:11 +0100 (CET)
 // Thu, 6 Dec 2018 11:20:06 +0000
+// 6 Dec 2018 11:20:06 -0000
 
 var timeFormats = []string{
 	"Mon, 02 Jan 2006 15:04:05 MST",
@@ -144,6 +358,8 @@ var timeFormats = []string{
 	"Mon, 02 Jan 2006 15:04:05 -0700 (MST)",
 	"Mon, _2 Jan 2006 15:04:05 -0700 (MST)",
 	"Mon,_2 Jan 2006 15:04:05 -0700 (MST)",
+	"_2 Jan 2006 15:04:05 -0700",
+	"_2 Jan 2006 15:04:05 -0700 (MST)",
 	"2006-01-02 15:04:05",
 	"2006-01-02 15:04:05 MST",
 	"2006-01-02 15:04:05 (MST)",
@@ -151,4 +367,3 @@ var timeFormats = []string{
 	"2006-01-02 15:04:05 -0700 MST",
 	"2006-01-02 15:04:05 -0700 (MST)",
 }
-
@@ -0,0 +1,261 @@
+package extractors
+
+import (
+	"net"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+type receivedCase struct {
+	mta          string
+	header       string
+	fromName     string
+	reversedName string
+	ip           string
+	helo         string
+	by           string
+	protocol     string
+	id           string
+	forAddr      string
+	envelopeFrom string
+	encrypted    bool
+	authSender   string
+	detectedMTA  string
+	timestamp    string
+}
+
+var receivedCorpus = []receivedCase{
+	{
+		mta:          "postfix",
+		header:       "from mail.example.com (mail.example.com [192.0.2.1])\r\n\tby mx.example.org (Postfix) with ESMTP id 4ABCD1234\r\n\tfor <bob@example.org>; Thu,  6 Dec 2018 12:20:17 +0100 (CET)",
+		fromName:     "mail.example.com",
+		reversedName: "mail.example.com",
+		ip:           "192.0.2.1",
+		by:           "mx.example.org",
+		protocol:     "ESMTP",
+		id:           "4ABCD1234",
+		forAddr:      "bob@example.org",
+		detectedMTA:  "Postfix",
+		timestamp:    "2018-12-06T12:20:17+01:00",
+	},
+	{
+		mta: "postfix esmtpsa",
+		header: "from [192.168.1.20] (unknown [198.51.100.7])\n\t(using TLSv1.3 with cipher TLS_AES_256_GCM_SHA384 (256/256 bits)\n\t key-exchange X25519 server-signature RSA-PSS (2048 bits) server-digest SHA256)\n" +
+			"\t(No client certificate requested)\n\t(Authenticated sender: alice@example.com)\n\tby smtp.example.com (Postfix) with ESMTPSA id 4Xyz;\n\tMon, 12 Oct 2026 10:00:00 +0200 (CEST)",
+		fromName:    "192.168.1.20",
+		ip:          "198.51.100.7",
+		helo:        "192.168.1.20",
+		by:          "smtp.example.com",
+		protocol:    "ESMTPSA",
+		id:          "4Xyz",
+		encrypted:   true,
+		authSender:  "alice@example.com",
+		detectedMTA: "Postfix",
+		timestamp:   "2026-10-12T10:00:00+02:00",
+	},
+	{
+		mta:          "exim",
+		header:       "from m0892.contabo.net ([91.194.91.211]:40338 helo=91.194.91.211) by m1363.contabo.net with esmtpsa (TLSv1.2:ECDHE-RSA-AES256-GCM-SHA384:256) (Exim 4.88) (envelope-from <support@contabo.com>) id 1ciAQt-0005YX-7L; Mon, 27 Feb 2017 02:48:59 +0100",
+		fromName:     "m0892.contabo.net",
+		ip:           "91.194.91.211",
+		helo:         "91.194.91.211",
+		by:           "m1363.contabo.net",
+		protocol:     "esmtpsa",
+		id:           "1ciAQt-0005YX-7L",
+		envelopeFrom: "support@contabo.com",
+		encrypted:    true,
+		detectedMTA:  "Exim",
+		timestamp:    "2017-02-27T02:48:59+01:00",
+	},
+	{
+		mta:          "exim literal",
+		header:       "from [203.0.113.9] (helo=client.example.net) by mx.example.org with esmtp (Exim 4.92) (envelope-from <bob@example.net>) id 1kXyz-0001Ab-Cd; Tue, 13 Oct 2026 09:00:00 +0000",
+		fromName:     "client.example.net",
+		ip:           "203.0.113.9",
+		helo:         "client.example.net",
+		by:           "mx.example.org",
+		protocol:     "esmtp",
+		id:           "1kXyz-0001Ab-Cd",
+		envelopeFrom: "bob@example.net",
+		detectedMTA:  "Exim",
+		timestamp:    "2026-10-13T09:00:00Z",
+	},
+	{
+		mta:          "sendmail",
+		header:       "from host.example.com (host.example.com [192.0.2.5]) by mx.example.org (8.14.4/8.14.4) with ESMTP id x8BKq1a2003456 for <bob@example.org>; Wed, 11 Sep 2019 22:52:01 +0200",
+		fromName:     "host.example.com",
+		reversedName: "host.example.com",
+		ip:           "192.0.2.5",
+		by:           "mx.example.org",
+		protocol:     "ESMTP",
+		id:           "x8BKq1a2003456",
+		forAddr:      "bob@example.org",
+		detectedMTA:  "Sendmail",
+		timestamp:    "2019-09-11T22:52:01+02:00",
+	},
+	{
+		mta:          "gmail",
+		header:       "from mail-sor-f41.google.com (mail-sor-f41.google.com. [209.85.220.41])\n        by mx.google.com with SMTPS id a13sor1234567wrx.5.2026.10.15.02.00.00\n        for <bob@gmail.com>\n        (Google Transport Security);\n        Thu, 15 Oct 2026 02:00:00 -0700 (PDT)",
+		fromName:     "mail-sor-f41.google.com",
+		reversedName: "mail-sor-f41.google.com",
+		ip:           "209.85.220.41",
+		by:           "mx.google.com",
+		protocol:     "SMTPS",
+		id:           "a13sor1234567wrx.5.2026.10.15.02.00.00",
+		forAddr:      "bob@gmail.com",
+		encrypted:    true,
+		detectedMTA:  "Gmail",
+		timestamp:    "2026-10-15T02:00:00-07:00",
+	},
+	{
+		mta:       "gmail internal",
+		header:    "by 2002:a05:6a10:8e0d:0:0:0:0 with SMTP id p13csp123456pxv;\n        Thu, 15 Oct 2026 02:00:01 -0700 (PDT)",
+		by:        "2002:a05:6a10:8e0d:0:0:0:0",
+		protocol:  "SMTP",
+		id:        "p13csp123456pxv",
+		timestamp: "2026-10-15T02:00:01-07:00",
+	},
+	{
+		mta:         "exchange",
+		header:      "from EX1.corp.local (10.0.0.1) by EX2.corp.local (10.0.0.2) with Microsoft SMTP Server (version=TLS1_2, cipher=TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384) id 15.1.1713.5; Tue, 13 Oct 2026 08:00:00 +0000",
+		fromName:    "EX1.corp.local",
+		ip:          "10.0.0.1",
+		by:          "EX2.corp.local",
+		protocol:    "Microsoft SMTP Server",
+		id:          "15.1.1713.5",
+		encrypted:   true,
+		detectedMTA: "Microsoft Exchange",
+		timestamp:   "2026-10-13T08:00:00Z",
+	},
+	{
+		mta:         "exchange online",
+		header:      "from AM0PR01MB1234.eurprd01.prod.exchangelabs.com (2603:10a6:208:1::15) by AM0PR01MB5678.eurprd01.prod.exchangelabs.com (2603:10a6:208:2::16) with Microsoft SMTP Server (version=TLS1_2, cipher=TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384) id 15.20.5123.14; Tue, 13 Oct 2026 08:00:01 +0000",
+		fromName:    "AM0PR01MB1234.eurprd01.prod.exchangelabs.com",
+		ip:          "2603:10a6:208:1::15",
+		by:          "AM0PR01MB5678.eurprd01.prod.exchangelabs.com",
+		protocol:    "Microsoft SMTP Server",
+		id:          "15.20.5123.14",
+		encrypted:   true,
+		detectedMTA: "Microsoft Exchange",
+		timestamp:   "2026-10-13T08:00:01Z",
+	},
+	{
+		mta:         "qmail",
+		header:      "from unknown (HELO mail.example.com) (192.0.2.9)\n  by mx.example.org with SMTP; 1 Jan 2020 12:00:00 -0000",
+		fromName:    "mail.example.com",
+		ip:          "192.0.2.9",
+		helo:        "mail.example.com",
+		by:          "mx.example.org",
+		protocol:    "SMTP",
+		detectedMTA: "",
+		timestamp:   "2020-01-01T12:00:00Z",
+	},
+	{
+		mta:         "qmail local",
+		header:      "(qmail 12345 invoked from network); 1 Jan 2020 12:00:00 -0000",
+		detectedMTA: "qmail",
+		timestamp:   "2020-01-01T12:00:00Z",
+	},
+	{
+		mta:          "ipv6 literal",
+		header:       "from [IPv6:2001:db8::25] (unknown [IPv6:2001:db8::25]) by mx.example.org (Postfix) with ESMTPS id 4Q; Thu, 15 Oct 2026 09:00:00 +0000",
+		fromName:     "2001:db8::25",
+		ip:           "2001:db8::25",
+		by:           "mx.example.org",
+		protocol:     "ESMTPS",
+		id:           "4Q",
+		encrypted:    true,
+		detectedMTA:  "Postfix",
+		timestamp:    "2026-10-15T09:00:00Z",
+		reversedName: "",
+	},
+}
+
+func TestParseReceivedHeaderCorpus(t *testing.T) {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	for _, c := range receivedCorpus {
+		e := ParseReceivedHeader(c.header, nil, logger)
+		var fromName, reversedName, ip, by string
+		if e.From != nil {
+			fromName = e.From.ReportedName
+			reversedName = e.From.DNSReversedName
+			if e.From.IP != nil {
+				ip = e.From.IP.Reported
+			}
+		}
+		if e.By != nil {
+			by = e.By.Name
+		}
+		var timestamp string
+		if e.Timestamp != nil {
+			timestamp = e.Timestamp.Format("2006-01-02T15:04:05Z07:00")
+		}
+		checks := []struct {
+			field     string
+			got, want string
+		}{
+			{"from", fromName, c.fromName},
+			{"reversed name", reversedName, c.reversedName},
+			{"ip", ip, c.ip},
+			{"helo", e.Helo, c.helo},
+			{"by", by, c.by},
+			{"protocol", e.Protocol, c.protocol},
+			{"id", e.ID, c.id},
+			{"for", e.For, c.forAddr},
+			{"envelope from", e.EnvelopeFrom, c.envelopeFrom},
+			{"authenticated sender", e.AuthenticatedSender, c.authSender},
+			{"mta", e.MTA, c.detectedMTA},
+			{"timestamp", timestamp, c.timestamp},
+		}
+		for _, check := range checks {
+			if check.got != check.want {
+				t.Errorf("%s: %s = %q, want %q", c.mta, check.field, check.got, check.want)
+			}
+		}
+		if e.Encrypted != c.encrypted {
+			t.Errorf("%s: encrypted = %v, want %v", c.mta, e.Encrypted, c.encrypted)
+		}
+	}
+}
+
+func TestAnalyseReceivedChain(t *testing.T) {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	headers := []string{
+		"from filter.example.org (filter.example.org [10.0.0.5]) by mailbox.example.org (Postfix) with ESMTP id B; Mon, 12 Oct 2026 10:00:10 +0000",
+		"from mail.sender.example (mail.sender.example [203.0.113.10]) by mx.example.org (Postfix) with ESMTPS id A; Mon, 12 Oct 2026 10:00:05 +0000",
+		"from workstation (workstation [192.168.0.2]) by mail.sender.example (Postfix) with ESMTP id S; Mon, 12 Oct 2026 10:00:00 +0000",
+		"from relay.other.example (relay.other.example [198.51.100.1]) by mx.example.org (Postfix) with ESMTP id F; Mon, 12 Oct 2026 11:00:00 +0000",
+	}
+	var hops []models.ReceivedElement
+	for _, h := range headers {
+		hops = append(hops, ParseReceivedHeader(h, nil, logger))
+	}
+	_, internal, _ := net.ParseCIDR("198.51.100.0/24")
+	chain := AnalyseReceivedChain(hops, []*net.IPNet{internal})
+
+	if chain.FirstExternalHop != 1 || chain.SendingIP != "203.0.113.10" {
+		t.Errorf("first external hop = %d (%s), want 1 (203.0.113.10)", chain.FirstExternalHop, chain.SendingIP)
+	}
+	if !hops[0].Trusted || !hops[1].Trusted || hops[2].Trusted {
+		t.Errorf("wrong trusted hops")
+	}
+	if hops[0].Delay == nil || *hops[0].Delay != 5 {
+		t.Errorf("delay of the first hop = %v, want 5", hops[0].Delay)
+	}
+	if !chain.OutOfOrder || !hops[2].OutOfOrder {
+		t.Errorf("out of order timestamps not detected")
+	}
+	if chain.CleartextHops != 3 {
+		t.Errorf("cleartext hops = %d, want 3", chain.CleartextHops)
+	}
+	if len(hops[2].ForgeryHints) != 0 {
+		t.Errorf("unexpected forgery hints: %v", hops[2].ForgeryHints)
+	}
+	if !chain.Forged || len(hops[3].ForgeryHints) != 3 {
+		t.Errorf("forgery hints of the injected header = %v", hops[3].ForgeryHints)
+	}
+}
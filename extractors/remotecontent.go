@@ -0,0 +1,248 @@
+package extractors
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+	"golang.org/x/net/html"
+)
+
+var maxRecipientTokens = 50
+
+// URL fragments used by the open-tracking systems of the usual email service providers
+var trackingPaths = []string{
+	"/track/open",
+	"/wf/open",
+	"/open.php",
+	"/open.aspx",
+	"/open.gif",
+	"/trk/open",
+	"/e/o/",
+	"/email/open",
+	"/mail/open",
+	"/opens/",
+	"/pixel.gif",
+	"/pixel.png",
+	"/beacon",
+	"/__ptq.gif",
+	"/imp?",
+	"list-manage.com/track/",
+	"mandrillapp.com/track/",
+	"sendgrid.net/wf/",
+	"ct.sendgrid.net",
+	"cl.exct.net",
+	"t.hubspotemail.net",
+	"mailtrack.io",
+	"awstrack.me",
+}
+
+type remoteContentWalker struct {
+	inventory  *models.RemoteContent
+	recipients []string
+	cids       map[string]bool
+}
+
+// RemoteContentInventory lists the remote images and the inline cid: references of the HTML document h,
+// and adds them to inventory. recipients are used to detect per-recipient tokens in URLs.
+func RemoteContentInventory(h string, recipients []string, inventory *models.RemoteContent) *models.RemoteContent {
+	if inventory == nil {
+		inventory = new(models.RemoteContent)
+	}
+	h = strings.TrimSpace(h)
+	if len(h) == 0 {
+		return inventory
+	}
+	root, err := html.Parse(strings.NewReader(h))
+	if err != nil {
+		return inventory
+	}
+	w := &remoteContentWalker{
+		inventory: inventory,
+		cids:      make(map[string]bool),
+	}
+	for _, r := range recipients {
+		r = strings.ToLower(strings.Trim(strings.TrimSpace(r), "<>"))
+		if r != "" {
+			w.recipients = append(w.recipients, r)
+		}
+	}
+	for _, img := range inventory.InlineImages {
+		w.cids[img.ContentID] = true
+	}
+	w.walk(root)
+	return inventory
+}
+
+func (w *remoteContentWalker) walk(n *html.Node) {
+	if n.Type == html.ElementNode {
+		switch strings.ToLower(n.Data) {
+		case "img":
+			w.image(n)
+		case "a":
+			w.tokens(getAttr(n, "href"))
+		}
+	}
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		w.walk(c)
+	}
+}
+
+func (w *remoteContentWalker) image(n *html.Node) {
+	src := getAttr(n, "src")
+	if strings.HasPrefix(strings.ToLower(src), "cid:") {
+		cid := strings.Trim(strings.TrimSpace(src[4:]), "<>")
+		if v, err := url.PathUnescape(cid); err == nil {
+			cid = v
+		}
+		if cid != "" && !w.cids[cid] {
+			w.cids[cid] = true
+			w.inventory.InlineImages = append(w.inventory.InlineImages, models.InlineImage{ContentID: cid})
+		}
+		return
+	}
+	host := remoteHost(src)
+	if host == "" {
+		return
+	}
+	if w.inventory.ImageHosts == nil {
+		w.inventory.ImageHosts = make(map[string]int)
+	}
+	w.inventory.ImageHosts[host]++
+	if reason := trackingReason(n, src); reason != "" {
+		w.inventory.TracksOpens = true
+		w.inventory.TrackingPixels = append(w.inventory.TrackingPixels, models.TrackingPixel{
+			URL:    src,
+			Host:   host,
+			Reason: reason,
+		})
+	}
+	w.tokens(src)
+}
+
+func (w *remoteContentWalker) tokens(u string) {
+	if remoteHost(u) == "" || len(w.inventory.RecipientTokens) >= maxRecipientTokens {
+		return
+	}
+	w.inventory.RecipientTokens = append(w.inventory.RecipientTokens, RecipientTokens(u, w.recipients)...)
+}
+
+// trackingReason tells why the image n looks like an open-tracking pixel, or returns an empty string.
+func trackingReason(n *html.Node, src string) string {
+	width, height := imageSize(n)
+	if width == 0 || height == 0 {
+		return "zero-size"
+	}
+	if width == 1 && height == 1 {
+		return "1x1"
+	}
+	style := parseInlineStyle(getAttr(n, "style"))
+	if style["display"] == "none" || style["visibility"] == "hidden" {
+		return "hidden"
+	}
+	lsrc := strings.ToLower(src)
+	for _, path := range trackingPaths {
+		if strings.Contains(lsrc, path) {
+			return "tracking-path"
+		}
+	}
+	return ""
+}
+
+// imageSize returns the declared dimensions of image n, or -1 when a dimension is not declared.
+func imageSize(n *html.Node) (width int, height int) {
+	width, height = -1, -1
+	style := parseInlineStyle(getAttr(n, "style"))
+	parse := func(attr string) int {
+		v := style[attr]
+		if v == "" {
+			v = getAttr(n, attr)
+		}
+		v = strings.TrimSuffix(strings.TrimSpace(v), "px")
+		if v == "" {
+			return -1
+		}
+		i, err := strconv.Atoi(v)
+		if err != nil {
+			return -1
+		}
+		return i
+	}
+	return parse("width"), parse("height")
+}
+
+// RecipientTokens looks for query parameters or path segments of u that identify one of the
+// recipients, given in lowercase: the address itself, or its base64 or hexadecimal encoding, or its
+// MD5, SHA1 or SHA256 hash. The other identifiers of a URL may be tracking tokens too, but they
+// can not be told apart from the identifiers of a resource.
+func RecipientTokens(u string, recipients []string) (tokens []models.RecipientToken) {
+	pu, err := url.Parse(strings.TrimSpace(u))
+	if err != nil {
+		return nil
+	}
+	for param, values := range pu.Query() {
+		for _, v := range values {
+			if kind := tokenKind(v, recipients); kind != "" {
+				tokens = append(tokens, models.RecipientToken{URL: u, Parameter: param, Kind: kind})
+			}
+		}
+	}
+	for _, segment := range strings.Split(pu.Path, "/") {
+		if kind := tokenKind(segment, recipients); kind != "" {
+			tokens = append(tokens, models.RecipientToken{URL: u, Parameter: "path", Kind: kind})
+		}
+	}
+	return tokens
+}
+
+func tokenKind(value string, recipients []string) string {
+	value = strings.TrimSpace(value)
+	if len(value) < 6 {
+		return ""
+	}
+	lvalue := strings.ToLower(value)
+	for _, rcpt := range recipients {
+		if strings.Contains(lvalue, rcpt) {
+			return "address"
+		}
+		if decoded := decodeBase64Token(value); decoded != "" && strings.Contains(strings.ToLower(decoded), rcpt) {
+			return "base64-address"
+		}
+		if decoded, err := hex.DecodeString(value); err == nil && strings.Contains(strings.ToLower(string(decoded)), rcpt) {
+			return "hex-address"
+		}
+		m := md5.Sum([]byte(rcpt))
+		if lvalue == hex.EncodeToString(m[:]) {
+			return "md5-address"
+		}
+		s1 := sha1.Sum([]byte(rcpt))
+		if lvalue == hex.EncodeToString(s1[:]) {
+			return "sha1-address"
+		}
+		s256 := sha256.Sum256([]byte(rcpt))
+		if lvalue == hex.EncodeToString(s256[:]) {
+			return "sha256-address"
+		}
+	}
+	return ""
+}
+
+func decodeBase64Token(value string) string {
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.URLEncoding, base64.RawStdEncoding, base64.RawURLEncoding} {
+		if b, err := enc.DecodeString(value); err == nil {
+			return string(b)
+		}
+	}
+	return ""
+}
+
+// IsRemoteImage tells whether the image reference src points to a remote resource.
+func IsRemoteImage(src string) bool {
+	return remoteHost(src) != ""
+}
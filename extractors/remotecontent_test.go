@@ -0,0 +1,98 @@
+package extractors
+
+import (
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestRecipientTokens(t *testing.T) {
+	rcpt := "john.doe@example.org"
+	md5sum := md5.Sum([]byte(rcpt))
+	sha256sum := sha256.Sum256([]byte(rcpt))
+	cases := []struct {
+		name  string
+		url   string
+		param string
+		kind  string
+	}{
+		{"address", "https://t.example.net/o?u=john.doe@example.org", "u", "address"},
+		{"escaped address", "https://t.example.net/o?email=John.Doe%40Example.org", "email", "address"},
+		{"base64 address", "https://t.example.net/o/" + base64.StdEncoding.EncodeToString([]byte(rcpt)) + "/p.gif", "path", "base64-address"},
+		{"url base64 address", "https://t.example.net/o?r=" + base64.RawURLEncoding.EncodeToString([]byte("id=1;to="+rcpt)), "r", "base64-address"},
+		{"hex address", "https://t.example.net/o?x=" + hex.EncodeToString([]byte(rcpt)), "x", "hex-address"},
+		{"md5 address", "https://t.example.net/o?h=" + strings.ToUpper(hex.EncodeToString(md5sum[:])), "h", "md5-address"},
+		{"sha256 address", "https://t.example.net/" + hex.EncodeToString(sha256sum[:]), "path", "sha256-address"},
+		{"resource identifier", "https://cdn.example.net/images/a8f3k2m9x7q4w1e6r5t0y2u8/logo.png", "", ""},
+		{"tracking identifier", "https://t.example.net/o?id=5f2b9c1e8a7d4f3b2c1d0e9f", "", ""},
+		{"other address", "https://t.example.net/o?u=jane.doe@example.org", "", ""},
+		{"short value", "https://t.example.net/o?u=john", "", ""},
+	}
+	for _, c := range cases {
+		tokens := RecipientTokens(c.url, []string{rcpt})
+		if c.kind == "" {
+			if len(tokens) != 0 {
+				t.Errorf("%s: tokens = %+v, want none", c.name, tokens)
+			}
+			continue
+		}
+		if len(tokens) != 1 || tokens[0].Kind != c.kind || tokens[0].Parameter != c.param || tokens[0].URL != c.url {
+			t.Errorf("%s: tokens = %+v, want %s in %s", c.name, tokens, c.kind, c.param)
+		}
+	}
+	if tokens := RecipientTokens("https://t.example.net/o?id=5f2b9c1e8a7d4f3b2c1d0e9f", nil); len(tokens) != 0 {
+		t.Errorf("tokens without recipients = %+v", tokens)
+	}
+}
+
+func TestRemoteContentInventory(t *testing.T) {
+	h := `<html><body>
+<img src="cid:logo@example" alt="logo">
+<img src="https://img.example.net/banner.png" width="600" height="200">
+<img src="https://t.example.net/pixel.gif?u=John.Doe@example.org" width="1" height="1">
+<img src="https://img.example.net/spacer.png" style="display: none">
+<img src="https://mailer.example.com/track/open/a8f3k2m9x7q4">
+<img src="https://img.example.net/zero.png" style="width:0px;height:0px">
+<a href="https://www.example.com/unsubscribe?e=am9obi5kb2VAZXhhbXBsZS5vcmc=">unsubscribe</a>
+<a href="mailto:john.doe@example.org">mail</a>
+</body></html>`
+	inventory := RemoteContentInventory(h, []string{"<John.Doe@Example.org>"}, nil)
+	if !inventory.TracksOpens {
+		t.Error("the tracking pixels are not detected")
+	}
+	reasons := make(map[string]string)
+	for _, p := range inventory.TrackingPixels {
+		reasons[p.URL] = p.Reason
+	}
+	want := map[string]string{
+		"https://t.example.net/pixel.gif?u=John.Doe@example.org": "1x1",
+		"https://img.example.net/spacer.png":                     "hidden",
+		"https://mailer.example.com/track/open/a8f3k2m9x7q4":     "tracking-path",
+		"https://img.example.net/zero.png":                       "zero-size",
+	}
+	if len(reasons) != len(want) {
+		t.Errorf("tracking pixels = %v, want %v", reasons, want)
+	}
+	for u, reason := range want {
+		if reasons[u] != reason {
+			t.Errorf("%s: reason = %q, want %q", u, reasons[u], reason)
+		}
+	}
+	if inventory.ImageHosts["img.example.net"] != 3 || inventory.ImageHosts["t.example.net"] != 1 {
+		t.Errorf("image hosts = %v", inventory.ImageHosts)
+	}
+	if len(inventory.InlineImages) != 1 || inventory.InlineImages[0].ContentID != "logo@example" {
+		t.Errorf("inline images = %+v", inventory.InlineImages)
+	}
+	kinds := make(map[string]string)
+	for _, token := range inventory.RecipientTokens {
+		kinds[token.URL] = token.Kind
+	}
+	if len(kinds) != 2 || kinds["https://t.example.net/pixel.gif?u=John.Doe@example.org"] != "address" ||
+		kinds["https://www.example.com/unsubscribe?e=am9obi5kb2VAZXhhbXBsZS5vcmc="] != "base64-address" {
+		t.Errorf("recipient tokens = %+v", inventory.RecipientTokens)
+	}
+}
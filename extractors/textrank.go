@@ -3,7 +3,6 @@ package extractors
 import (
 	"fmt"
 	"strings"
-	"unicode/utf8"
 
 	"github.com/DavidBelicza/TextRank"
 	"github.com/DavidBelicza/TextRank/rank"
@@ -18,7 +17,7 @@ type lang struct {
 
 func (l *lang) IsStopWord(word string) bool {
 	norm := utils.Normalize(strings.ToLower(strings.TrimSpace(word)))
-	if utf8.RuneCountInString(norm) <= 3 {
+	if tooShort(norm) {
 		return true
 	}
 	return l.stopWords(norm)
@@ -72,16 +71,12 @@ func TextRank(content string, stems map[string]string, language string) ([]rank.
 	if len(stems) == 0 {
 		stems = Stems(BagOfWords(content, language), language)
 	}
-	var stopWords set.Set
-	if language == "french" {
-		stopWords = StopWordsFrench
-	} else {
-		stopWords = StopWordsEnglish
-	}
+	// the same words as in the bags of words
+	content = segmentText(content)
 
 	l := &lang{
 		stopWords: func(w string) bool {
-			return stopWords.Contains(w)
+			return IsStopWord(w, language)
 		},
 		stems: stems,
 	}
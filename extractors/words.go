@@ -2,10 +2,12 @@ package extractors
 
 import (
 	"strings"
+	"unicode"
 	"unicode/utf8"
 
+	"github.com/abadojack/whatlanggo"
 	"github.com/jdkato/prose"
-	"github.com/kljensen/snowball"
+	"github.com/stephane-martin/mailstats/stemmer"
 	"github.com/stephane-martin/mailstats/utils"
 )
 
@@ -14,12 +16,27 @@ func ExtractWords(text string) (words []string) {
 	if len(text) == 0 {
 		return nil
 	}
-	doc, err := prose.NewDocument(text, prose.WithExtraction(false), prose.WithSegmentation(false), prose.WithTagging(false))
-	if err != nil {
-		return nil
+	var tokens []string
+	if whatlanggo.DetectScript(text) == unicode.Latin {
+		doc, err := prose.NewDocument(text, prose.WithExtraction(false), prose.WithSegmentation(false), prose.WithTagging(false))
+		if err != nil {
+			return nil
+		}
+		for _, tok := range doc.Tokens() {
+			tokens = append(tokens, tok.Text)
+		}
+	} else {
+		// the prose tokenizer loses the end of some Korean texts
+		tokens = strings.FieldsFunc(text, func(r rune) bool {
+			return !unicode.IsLetter(r) && !unicode.IsMark(r) && !unicode.IsDigit(r)
+		})
 	}
-	for _, tok := range doc.Tokens() {
-		word := filterWord(tok.Text)
+	for _, tok := range tokens {
+		if strings.IndexFunc(tok, isCJK) >= 0 {
+			words = append(words, segmentCJK(tok)...)
+			continue
+		}
+		word := filterWord(tok)
 		if len(word) > 0 {
 			words = append(words, word)
 		}
@@ -41,7 +58,7 @@ func filterWord(w string) string {
 		}
 	}
 	w = strings.TrimSuffix(w, "»")
-	if utf8.RuneCountInString(w) <= 3 {
+	if tooShort(w) {
 		return ""
 	}
 	if strings.ContainsAny(w, "«<>0123456789_-~#{}[]()|`^=+°&$£µ%/:;,?§!.@") {
@@ -59,20 +76,10 @@ func BagOfWords(text string, language string) map[string]int {
 	for _, word := range words {
 		bag[word] = bag[word] + 1
 	}
-	switch language {
-	case "english":
-		for word := range bag {
-			if StopWordsEnglish.Contains(word) {
-				delete(bag, word)
-			}
-		}
-	case "french":
-		for word := range bag {
-			if StopWordsFrench.Contains(word) {
-				delete(bag, word)
-			}
+	for word := range bag {
+		if IsStopWord(word, language) {
+			delete(bag, word)
 		}
-	default:
 	}
 	return bag
 }
@@ -80,12 +87,8 @@ func BagOfWords(text string, language string) map[string]int {
 func Stems(bag map[string]int, language string) map[string]string {
 	s := make(map[string][]string)
 	for word := range bag {
-		stem, err := snowball.Stem(word, language, false)
-		if err == nil {
-			s[stem] = append(s[stem], word)
-		} else {
-			s[word] = append(s[word], word)
-		}
+		stem := stemmer.Stem(word, language)
+		s[stem] = append(s[stem], word)
 	}
 	stems := map[string]string{}
 	for _, words := range s {
@@ -113,3 +116,96 @@ func shortestWord(words []string) string {
 	}
 	return shortest
 }
+
+// isCJK reports the Chinese and Japanese characters, written without spaces between the words.
+func isCJK(r rune) bool {
+	return unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana) || r == 'ー'
+}
+
+// tooShort reports the words too short to be meaningful. The Korean words have few syllables.
+func tooShort(w string) bool {
+	n := utf8.RuneCountInString(w)
+	switch {
+	case strings.IndexFunc(w, isCJK) >= 0:
+		return n == 0
+	case strings.IndexFunc(w, func(r rune) bool { return unicode.Is(unicode.Hangul, r) }) >= 0:
+		return n < 2
+	}
+	return n <= 3
+}
+
+// segmentCJK splits the runs of Chinese and Japanese characters of a token into overlapping
+// bigrams, as there is no dictionary to find the words. The rest of the token is kept as words.
+func segmentCJK(token string) []string {
+	var words []string
+	var run []rune
+	var other strings.Builder
+	flush := func() {
+		switch {
+		case len(run) == 1:
+			words = append(words, string(run))
+		case len(run) > 1:
+			for i := 0; i < len(run)-1; i++ {
+				words = append(words, string(run[i:i+2]))
+			}
+		}
+		run = run[:0]
+		if w := filterWord(other.String()); w != "" {
+			words = append(words, w)
+		}
+		other.Reset()
+	}
+	for _, r := range utils.Normalize(token) {
+		switch {
+		case isCJK(r):
+			if other.Len() > 0 {
+				flush()
+			}
+			run = append(run, r)
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if len(run) > 0 {
+				flush()
+			}
+			other.WriteRune(r)
+		default:
+			flush()
+		}
+	}
+	flush()
+	return words
+}
+
+// segmentText rewrites the runs of Chinese and Japanese characters of text as bigrams separated
+// by spaces, for the keyword extraction.
+func segmentText(text string) string {
+	if strings.IndexFunc(text, isCJK) < 0 {
+		return text
+	}
+	var b strings.Builder
+	var run []rune
+	flush := func() {
+		if len(run) == 1 {
+			b.WriteString(string(run))
+		}
+		for i := 0; i < len(run)-1; i++ {
+			if i > 0 {
+				b.WriteByte(' ')
+			}
+			b.WriteString(string(run[i : i+2]))
+		}
+		run = run[:0]
+	}
+	for _, r := range text {
+		if isCJK(r) {
+			run = append(run, r)
+			continue
+		}
+		if len(run) > 0 {
+			flush()
+			b.WriteByte(' ')
+		}
+		b.WriteRune(r)
+	}
+	flush()
+	return b.String()
+}
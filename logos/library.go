@@ -0,0 +1,279 @@
+// Package logos recognizes brand logos in images, by comparing their perceptual hashes
+// to the hashes of reference images.
+//
+// The library is extended by operators with a directory that contains one sub-directory per brand:
+//
+//	<dir>/<brand>/domains.txt   legitimate sender domains of the brand, one per line
+//	<dir>/<brand>/hashes.txt    hashes of reference logos, one per line: "ahash dhash phash", or only "phash"
+//	<dir>/<brand>/*.png         reference logos (PNG, JPEG, GIF or BMP)
+//
+// The lines of hashes.txt are the image_hashes of the features, in hexadecimal. An image matches a
+// reference when its pHash is close, and its aHash and dHash too when the reference has them: the
+// three hashes together make fewer false positives than the pHash alone.
+//
+// The domains of a few commonly impersonated brands are built in, but the reference logos are not:
+// the logos are trademarks, and the operators provide the ones of the brands they care about.
+package logos
+
+import (
+	"bufio"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/extractors"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+var builtinDomains = map[string][]string{
+	"adobe":      {"adobe.com", "adobesign.com", "echosign.com"},
+	"amazon":     {"amazon.com", "amazon.fr", "amazon.de", "amazon.co.uk", "amazon.it", "amazon.es", "amazonses.com"},
+	"ameli":      {"ameli.fr", "assurance-maladie.fr"},
+	"apple":      {"apple.com", "icloud.com", "me.com"},
+	"chronopost": {"chronopost.fr"},
+	"dhl":        {"dhl.com", "dhl.de", "dhl.fr"},
+	"docusign":   {"docusign.com", "docusign.net"},
+	"dropbox":    {"dropbox.com", "dropboxmail.com"},
+	"facebook":   {"facebook.com", "facebookmail.com", "meta.com"},
+	"fedex":      {"fedex.com"},
+	"google":     {"google.com", "gmail.com", "googlemail.com", "youtube.com"},
+	"impots":     {"impots.gouv.fr", "dgfip.finances.gouv.fr"},
+	"laposte":    {"laposte.fr", "laposte.net", "labanquepostale.fr"},
+	"linkedin":   {"linkedin.com"},
+	"microsoft":  {"microsoft.com", "office.com", "office365.com", "outlook.com", "live.com", "hotmail.com", "microsoftonline.com", "sharepoint.com", "onedrive.com"},
+	"netflix":    {"netflix.com"},
+	"orange":     {"orange.fr", "orange.com"},
+	"paypal":     {"paypal.com", "paypal.fr", "paypal.me"},
+	"ups":        {"ups.com"},
+	"wetransfer": {"wetransfer.com"},
+}
+
+var imageExtensions = map[string]bool{
+	".png": true, ".jpg": true, ".jpeg": true, ".gif": true, ".bmp": true,
+}
+
+type reference struct {
+	name  string
+	phash uint64
+	// full is true when the aHash and the dHash are known
+	full  bool
+	ahash uint64
+	dhash uint64
+}
+
+// parseReference parses a line of hashes.txt.
+func parseReference(line string) (reference, error) {
+	ref := reference{name: line}
+	fields := strings.Fields(line)
+	var err error
+	switch len(fields) {
+	case 1:
+		ref.phash, err = extractors.ParseHash(fields[0])
+	case 3:
+		ref.full = true
+		if ref.ahash, err = extractors.ParseHash(fields[0]); err != nil {
+			return ref, err
+		}
+		if ref.dhash, err = extractors.ParseHash(fields[1]); err != nil {
+			return ref, err
+		}
+		ref.phash, err = extractors.ParseHash(fields[2])
+	default:
+		err = fmt.Errorf("expected one or three hashes, got %d", len(fields))
+	}
+	return ref, err
+}
+
+// distance returns the largest distance between the hashes of the reference and the hashes of an
+// image.
+func (ref reference) distance(ahash, dhash, phash uint64) int {
+	d := extractors.HammingDistance(phash, ref.phash)
+	if ref.full {
+		if da := extractors.HammingDistance(ahash, ref.ahash); da > d {
+			d = da
+		}
+		if dd := extractors.HammingDistance(dhash, ref.dhash); dd > d {
+			d = dd
+		}
+	}
+	return d
+}
+
+type brand struct {
+	name       string
+	domains    []string
+	references []reference
+}
+
+// Library is a set of brands, with their legitimate domains and their reference logos.
+type Library struct {
+	brands      map[string]*brand
+	maxDistance int
+}
+
+// NewLibrary returns a library with the built-in brands, extended by the content of directory (if not empty).
+// Logos match when the Hamming distance between the perceptual hashes is at most maxDistance.
+func NewLibrary(directory string, maxDistance int, logger log15.Logger) (*Library, error) {
+	lib := &Library{
+		brands:      make(map[string]*brand),
+		maxDistance: maxDistance,
+	}
+	for name, domains := range builtinDomains {
+		lib.brands[name] = &brand{name: name, domains: domains}
+	}
+	if directory == "" {
+		return lib, nil
+	}
+	entries, err := ioutil.ReadDir(directory)
+	if os.IsNotExist(err) {
+		return lib, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		name := strings.ToLower(entry.Name())
+		b := lib.brands[name]
+		if b == nil {
+			b = &brand{name: name}
+			lib.brands[name] = b
+		}
+		lib.loadBrand(b, filepath.Join(directory, entry.Name()), logger)
+	}
+	return lib, nil
+}
+
+func (lib *Library) loadBrand(b *brand, dir string, logger log15.Logger) {
+	files, err := ioutil.ReadDir(dir)
+	if err != nil {
+		logger.Warn("Error reading logos directory", "directory", dir, "error", err)
+		return
+	}
+	for _, f := range files {
+		if f.IsDir() {
+			continue
+		}
+		path := filepath.Join(dir, f.Name())
+		switch {
+		case f.Name() == "domains.txt":
+			lines, err := readLines(path)
+			if err != nil {
+				logger.Warn("Error reading brand domains", "file", path, "error", err)
+			}
+			for _, line := range lines {
+				b.domains = append(b.domains, strings.ToLower(line))
+			}
+		case f.Name() == "hashes.txt":
+			lines, err := readLines(path)
+			if err != nil {
+				logger.Warn("Error reading brand hashes", "file", path, "error", err)
+			}
+			for _, line := range lines {
+				ref, err := parseReference(line)
+				if err != nil {
+					logger.Warn("Invalid perceptual hashes", "file", path, "hashes", line, "error", err)
+					continue
+				}
+				b.references = append(b.references, ref)
+			}
+		case imageExtensions[strings.ToLower(filepath.Ext(f.Name()))]:
+			content, err := ioutil.ReadFile(path)
+			if err != nil {
+				logger.Warn("Error reading reference logo", "file", path, "error", err)
+				continue
+			}
+			img, err := extractors.DecodeImage(content)
+			if err != nil {
+				logger.Warn("Error decoding reference logo", "file", path, "error", err)
+				continue
+			}
+			b.references = append(b.references, reference{
+				name:  f.Name(),
+				phash: extractors.PerceptualHash(img),
+				full:  true,
+				ahash: extractors.AverageHash(img),
+				dhash: extractors.DifferenceHash(img),
+			})
+		}
+	}
+}
+
+func readLines(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	//noinspection GoUnhandledErrorResult
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line != "" && !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// Match returns the brands whose reference logos are close to the image, best match first. The
+// distance of a match is the largest distance between the hashes of the image and of the reference.
+func (lib *Library) Match(hashes *models.ImageHashes) []models.LogoMatch {
+	if hashes == nil {
+		return nil
+	}
+	ahash, errA := extractors.ParseHash(hashes.AHash)
+	dhash, errD := extractors.ParseHash(hashes.DHash)
+	phash, errP := extractors.ParseHash(hashes.PHash)
+	if errA != nil || errD != nil || errP != nil {
+		return nil
+	}
+	var matches []models.LogoMatch
+	for _, b := range lib.brands {
+		best := -1
+		var bestRef string
+		for _, ref := range b.references {
+			d := ref.distance(ahash, dhash, phash)
+			if d <= lib.maxDistance && (best == -1 || d < best) {
+				best, bestRef = d, ref.name
+			}
+		}
+		if best >= 0 {
+			matches = append(matches, models.LogoMatch{Brand: b.name, Reference: bestRef, Distance: best})
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return matches[i].Distance < matches[j].Distance })
+	return matches
+}
+
+// Legitimate tells whether domain is one of the domains of the brand (or a subdomain).
+func (lib *Library) Legitimate(brandName string, domain string) bool {
+	b := lib.brands[brandName]
+	if b == nil || domain == "" {
+		return false
+	}
+	domain = strings.ToLower(domain)
+	for _, d := range b.domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) || utils.RegisteredDomain(domain) == d {
+			return true
+		}
+	}
+	return false
+}
+
+// References returns the number of reference logos in the library.
+func (lib *Library) References() int {
+	n := 0
+	for _, b := range lib.brands {
+		n += len(b.references)
+	}
+	return n
+}
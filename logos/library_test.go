@@ -0,0 +1,143 @@
+package logos
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/extractors"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// drawLogo draws a size x size image: a dark disc in the upper left corner and a bar at the
+// bottom, on a light background.
+func drawLogo(size int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			c := color.RGBA{R: 240, G: 240, B: 240, A: 255}
+			dx, dy := x-size/3, y-size/3
+			if dx*dx+dy*dy < size*size/16 {
+				c = color.RGBA{R: 0, G: 80, B: 160, A: 255}
+			}
+			if y > size*3/4 && x > size/8 && x < size*7/8 {
+				c = color.RGBA{R: 200, G: 30, B: 30, A: 255}
+			}
+			img.Set(x, y, c)
+		}
+	}
+	return img
+}
+
+// drawGradient draws a diagonal gradient, that looks like nothing of drawLogo.
+func drawGradient(size int) image.Image {
+	img := image.NewGray(image.Rect(0, 0, size, size))
+	for y := 0; y < size; y++ {
+		for x := 0; x < size; x++ {
+			img.SetGray(x, y, color.Gray{Y: uint8(255 * (size - 1 - x + y) / (2 * size))})
+		}
+	}
+	return img
+}
+
+func writeLibrary(t *testing.T, files map[string][]byte) string {
+	dir, err := ioutil.TempDir("", "logos")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, content := range files {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := ioutil.WriteFile(path, content, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return dir
+}
+
+func encodePNG(t *testing.T, img image.Image) []byte {
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, img); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func discardLogger() log15.Logger {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	return logger
+}
+
+func TestLibraryMatchImages(t *testing.T) {
+	dir := writeLibrary(t, map[string][]byte{
+		"acme/logo.png":    encodePNG(t, drawLogo(64)),
+		"acme/domains.txt": []byte("# the brand domains\nacme.com\n"),
+	})
+	defer os.RemoveAll(dir)
+	lib, err := NewLibrary(dir, 8, discardLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lib.References() != 1 {
+		t.Fatalf("references = %d, want 1", lib.References())
+	}
+
+	// the same logo, at another size
+	matches := lib.Match(extractors.ImageHashes(drawLogo(200)))
+	if len(matches) != 1 || matches[0].Brand != "acme" || matches[0].Reference != "logo.png" || matches[0].Distance > 8 {
+		t.Errorf("matches of the resized logo = %+v", matches)
+	}
+	if matches := lib.Match(extractors.ImageHashes(drawGradient(64))); len(matches) != 0 {
+		t.Errorf("matches of another image = %+v", matches)
+	}
+	if matches := lib.Match(nil); matches != nil {
+		t.Errorf("matches without hashes = %+v", matches)
+	}
+	if matches := lib.Match(&models.ImageHashes{PHash: "zz"}); matches != nil {
+		t.Errorf("matches of invalid hashes = %+v", matches)
+	}
+
+	if !lib.Legitimate("acme", "mail.acme.com") || lib.Legitimate("acme", "acme.com.example.net") {
+		t.Error("wrong legitimate domains for the library brand")
+	}
+	if !lib.Legitimate("microsoft", "outlook.com") || lib.Legitimate("microsoft", "micros0ft.com") {
+		t.Error("wrong legitimate domains for a built-in brand")
+	}
+}
+
+func TestLibraryMatchHashes(t *testing.T) {
+	logo := extractors.ImageHashes(drawLogo(64))
+	other := extractors.ImageHashes(drawGradient(64))
+	dir := writeLibrary(t, map[string][]byte{
+		// the pHash of the logo, with the aHash and dHash of another image
+		"full/hashes.txt":  []byte(other.AHash + " " + other.DHash + " " + logo.PHash + "\n"),
+		"phash/hashes.txt": []byte(logo.PHash + "\n"),
+		"both/hashes.txt":  []byte(logo.AHash + " " + logo.DHash + " " + logo.PHash + "\n"),
+		"bad/hashes.txt":   []byte("not a hash\n" + logo.AHash + " " + logo.PHash + "\n"),
+	})
+	defer os.RemoveAll(dir)
+	lib, err := NewLibrary(dir, 8, discardLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lib.References() != 3 {
+		t.Errorf("references = %d, want 3", lib.References())
+	}
+	matches := lib.Match(logo)
+	brands := make(map[string]int)
+	for _, m := range matches {
+		brands[m.Brand] = m.Distance
+	}
+	if len(brands) != 2 || brands["phash"] != 0 || brands["both"] != 0 {
+		t.Errorf("matches = %+v, want the phash and both brands", matches)
+	}
+}
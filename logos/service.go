@@ -0,0 +1,140 @@
+package logos
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"go.uber.org/fx"
+)
+
+var reloadInterval = 5 * time.Minute
+
+type Logos interface {
+	utils.Service
+	utils.Prestartable
+	utils.Startable
+	Match(hashes *models.ImageHashes) []models.LogoMatch
+	Legitimate(brand string, domain string) bool
+}
+
+type impl struct {
+	logger      log15.Logger
+	directory   string
+	maxDistance int
+	library     atomic.Value
+	modified    time.Time
+}
+
+func NewLogos(directory string, maxDistance int, logger log15.Logger) Logos {
+	return &impl{
+		directory:   directory,
+		maxDistance: maxDistance,
+		logger:      logger,
+	}
+}
+
+func (i *impl) Name() string {
+	return "Logos"
+}
+
+func (i *impl) Prestart() error {
+	return i.load()
+}
+
+// Start reloads the library when the content of the logos directory changes.
+func (i *impl) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(reloadInterval):
+		}
+		if i.lastModified().After(i.modified) {
+			err := i.load()
+			if err != nil {
+				i.logger.Warn("Error reloading logos library", "error", err)
+			}
+		}
+	}
+}
+
+func (i *impl) load() error {
+	modified := i.lastModified()
+	lib, err := NewLibrary(i.directory, i.maxDistance, i.logger)
+	if err != nil {
+		return err
+	}
+	i.library.Store(lib)
+	i.modified = modified
+	i.logger.Info("Logos library loaded", "directory", i.directory, "references", lib.References())
+	return nil
+}
+
+// lastModified returns the most recent modification time in the logos directory.
+func (i *impl) lastModified() (last time.Time) {
+	if i.directory == "" {
+		return last
+	}
+	_ = filepath.Walk(i.directory, func(path string, info os.FileInfo, err error) error {
+		if err == nil && info.ModTime().After(last) {
+			last = info.ModTime()
+		}
+		return nil
+	})
+	return last
+}
+
+func (i *impl) getLibrary() *Library {
+	lib := i.library.Load()
+	if lib == nil {
+		return nil
+	}
+	return lib.(*Library)
+}
+
+func (i *impl) Match(hashes *models.ImageHashes) []models.LogoMatch {
+	lib := i.getLibrary()
+	if lib == nil {
+		return nil
+	}
+	return lib.Match(hashes)
+}
+
+func (i *impl) Legitimate(brand string, domain string) bool {
+	lib := i.getLibrary()
+	if lib == nil {
+		return false
+	}
+	return lib.Legitimate(brand, domain)
+}
+
+type Params struct {
+	fx.In
+	Args   *arguments.Args `optional:"true"`
+	Logger log15.Logger    `optional:"true"`
+}
+
+var Service = fx.Provide(func(lc fx.Lifecycle, params Params) Logos {
+	if params.Args == nil || params.Args.Logos.Directory == "" {
+		return nil
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	l := NewLogos(
+		params.Args.Logos.Directory,
+		params.Args.Logos.MaxDistance,
+		logger,
+	)
+	utils.Append(lc, l, logger)
+	return l
+})
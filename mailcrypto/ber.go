@@ -0,0 +1,179 @@
+package mailcrypto
+
+import (
+	"bytes"
+	"errors"
+)
+
+// maxBERDepth limits the nesting of BER structures.
+const maxBERDepth = 64
+
+var errBER = errors.New("invalid BER encoding")
+
+// berToDER converts the BER encoding used by many S/MIME agents (indefinite lengths, constructed
+// strings) to the DER encoding that encoding/asn1 requires.
+func berToDER(ber []byte) ([]byte, error) {
+	var out bytes.Buffer
+	rest, err := convertBER(ber, &out, 0)
+	if err != nil {
+		return nil, err
+	}
+	if len(rest) > 0 && !allZero(rest) {
+		return nil, errBER
+	}
+	return out.Bytes(), nil
+}
+
+// convertBER converts one TLV element, and returns the remaining bytes.
+func convertBER(b []byte, out *bytes.Buffer, depth int) ([]byte, error) {
+	if depth > maxBERDepth || len(b) < 2 {
+		return nil, errBER
+	}
+	// identifier octets
+	idLen := 1
+	if b[0]&0x1f == 0x1f {
+		for idLen < len(b) && b[idLen]&0x80 != 0 {
+			idLen++
+		}
+		idLen++
+		if idLen >= len(b) {
+			return nil, errBER
+		}
+	}
+	identifier := b[:idLen]
+	constructed := b[0]&0x20 != 0
+	b = b[idLen:]
+
+	// length octets
+	var content []byte
+	indefinite := false
+	switch {
+	case b[0] == 0x80:
+		if !constructed {
+			return nil, errBER
+		}
+		indefinite = true
+		b = b[1:]
+	case b[0]&0x80 == 0:
+		l := int(b[0])
+		b = b[1:]
+		if l > len(b) {
+			return nil, errBER
+		}
+		content, b = b[:l], b[l:]
+	default:
+		n := int(b[0] & 0x7f)
+		if n > 4 || n+1 > len(b) {
+			return nil, errBER
+		}
+		l := 0
+		for _, c := range b[1 : n+1] {
+			l = l<<8 | int(c)
+		}
+		b = b[n+1:]
+		if l < 0 || l > len(b) {
+			return nil, errBER
+		}
+		content, b = b[:l], b[l:]
+	}
+
+	if !constructed {
+		writeTLV(out, identifier, content)
+		return b, nil
+	}
+
+	// constructed: convert the children
+	var children bytes.Buffer
+	var err error
+	if indefinite {
+		for {
+			if len(b) < 2 {
+				return nil, errBER
+			}
+			if b[0] == 0 && b[1] == 0 {
+				b = b[2:]
+				break
+			}
+			b, err = convertBER(b, &children, depth+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for len(content) > 0 {
+			content, err = convertBER(content, &children, depth+1)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+	// constructed OCTET STRING: DER requires the primitive form
+	if len(identifier) == 1 && identifier[0] == 0x24 {
+		flat, err := flattenStrings(children.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		writeTLV(out, []byte{0x04}, flat)
+		return b, nil
+	}
+	writeTLV(out, identifier, children.Bytes())
+	return b, nil
+}
+
+// flattenStrings concatenates the contents of DER encoded OCTET STRINGs.
+func flattenStrings(b []byte) ([]byte, error) {
+	var flat []byte
+	for len(b) > 0 {
+		if len(b) < 2 || b[0] != 0x04 {
+			return nil, errBER
+		}
+		l, n := derLength(b[1:])
+		if n == 0 || 1+n+l > len(b) {
+			return nil, errBER
+		}
+		flat = append(flat, b[1+n:1+n+l]...)
+		b = b[1+n+l:]
+	}
+	return flat, nil
+}
+
+func derLength(b []byte) (l int, n int) {
+	if b[0]&0x80 == 0 {
+		return int(b[0]), 1
+	}
+	size := int(b[0] & 0x7f)
+	if size > 4 || size+1 > len(b) {
+		return 0, 0
+	}
+	for _, c := range b[1 : size+1] {
+		l = l<<8 | int(c)
+	}
+	return l, size + 1
+}
+
+func writeTLV(out *bytes.Buffer, identifier []byte, content []byte) {
+	out.Write(identifier)
+	l := len(content)
+	switch {
+	case l < 0x80:
+		out.WriteByte(byte(l))
+	case l < 0x100:
+		out.Write([]byte{0x81, byte(l)})
+	case l < 0x10000:
+		out.Write([]byte{0x82, byte(l >> 8), byte(l)})
+	case l < 0x1000000:
+		out.Write([]byte{0x83, byte(l >> 16), byte(l >> 8), byte(l)})
+	default:
+		out.Write([]byte{0x84, byte(l >> 24), byte(l >> 16), byte(l >> 8), byte(l)})
+	}
+	out.Write(content)
+}
+
+func allZero(b []byte) bool {
+	for _, c := range b {
+		if c != 0 {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,75 @@
+package mailcrypto
+
+import (
+	"bytes"
+	"encoding/hex"
+	"strings"
+	"testing"
+)
+
+func TestBERToDER(t *testing.T) {
+	long := strings.Repeat("61", 200)
+	cases := []struct {
+		name string
+		ber  string
+		der  string
+	}{
+		{"der", "3003020105", "3003020105"},
+		{"indefinite sequence", "30800201050000", "3003020105"},
+		{"nested indefinite", "3080308002010500000000", "30053003020105"},
+		{"constructed octet string", "2480040261620401630000", "0403616263"},
+		{"definite constructed octet string", "2407040261620401 63", "0403616263"},
+		{"long form length", "0481c8" + long, "0481c8" + long},
+		{"non minimal length", "04820003616263", "0403616263"},
+		{"high tag number", "bf1f80020105 0000", "bf1f03020105"},
+		{"trailing padding", "30030201050000", "3003020105"},
+	}
+	for _, c := range cases {
+		der, err := berToDER(unhex(t, c.ber))
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if !bytes.Equal(der, unhex(t, c.der)) {
+			t.Errorf("%s: %x, want %s", c.name, der, strings.Replace(c.der, " ", "", -1))
+		}
+	}
+}
+
+func TestBERToDERInvalid(t *testing.T) {
+	cases := []struct {
+		name string
+		ber  string
+	}{
+		{"empty", ""},
+		{"truncated content", "30050201"},
+		{"truncated length", "0482"},
+		{"length too large", "0485ffffffffff"},
+		{"missing end of contents", "3080020105"},
+		{"indefinite primitive", "0480616200 00"},
+		{"invalid child", "3003020205"},
+		{"invalid string fragment", "2403050100"},
+		{"trailing garbage", "3003020105ff"},
+		{"truncated tag", "1f81"},
+	}
+	for _, c := range cases {
+		if der, err := berToDER(unhex(t, c.ber)); err == nil {
+			t.Errorf("%s: converted to %x", c.name, der)
+		}
+	}
+
+	// nested too deep
+	deep := bytes.Repeat([]byte{0x30, 0x80}, maxBERDepth+2)
+	deep = append(deep, bytes.Repeat([]byte{0, 0}, maxBERDepth+2)...)
+	if _, err := berToDER(deep); err == nil {
+		t.Error("too deep: converted")
+	}
+}
+
+func unhex(t *testing.T, s string) []byte {
+	b, err := hex.DecodeString(strings.Replace(s, " ", "", -1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
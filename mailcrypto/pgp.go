@@ -0,0 +1,656 @@
+package mailcrypto
+
+import (
+	"bufio"
+	"bytes"
+	"crypto"
+	"crypto/dsa"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// OpenPGP messages are described in RFC 4880. Only the version 4 keys, and the version 3 and 4
+// signatures are supported.
+
+const (
+	pgpTagPKESK         = 1
+	pgpTagSignature     = 2
+	pgpTagPublicKey     = 6
+	pgpTagUserID        = 13
+	pgpTagPublicSubkey  = 14
+	pgpSubpacketTime    = 2
+	pgpSubpacketIssuer  = 16
+	pgpSubpacketIssuerF = 33
+	pgpSigTypeText      = 1
+)
+
+const (
+	pgpRSA        = 1
+	pgpRSASign    = 3
+	pgpDSA        = 17
+	pgpECDSA      = 19
+	pgpEdDSA      = 22
+	pgpRSAEncrypt = 2
+)
+
+var pgpHashes = map[byte]crypto.Hash{
+	2:  crypto.SHA1,
+	8:  crypto.SHA256,
+	9:  crypto.SHA384,
+	10: crypto.SHA512,
+	11: crypto.SHA224,
+}
+
+var pgpCurves = map[string]elliptic.Curve{
+	"2a8648ce3d030107": elliptic.P256(),
+	"2b81040022":       elliptic.P384(),
+	"2b81040023":       elliptic.P521(),
+}
+
+const ed25519OID = "2b06010401da470f01"
+
+var errPGPPacket = errors.New("invalid OpenPGP packet")
+
+type pgpPacket struct {
+	tag  byte
+	body []byte
+}
+
+// readPackets splits an OpenPGP message into packets.
+func readPackets(b []byte) ([]pgpPacket, error) {
+	var packets []pgpPacket
+	for len(b) > 0 {
+		header := b[0]
+		if header&0x80 == 0 {
+			return packets, errPGPPacket
+		}
+		b = b[1:]
+		var tag byte
+		var body []byte
+		if header&0x40 != 0 {
+			// new format
+			tag = header & 0x3f
+			for {
+				l, n, partial := newFormatLength(b)
+				if n == 0 || l > len(b)-n {
+					return packets, errPGPPacket
+				}
+				body = append(body, b[n:n+l]...)
+				b = b[n+l:]
+				if !partial {
+					break
+				}
+			}
+		} else {
+			// old format
+			tag = (header >> 2) & 0x0f
+			var l, n int
+			switch header & 3 {
+			case 0:
+				if len(b) < 1 {
+					return packets, errPGPPacket
+				}
+				l, n = int(b[0]), 1
+			case 1:
+				if len(b) < 2 {
+					return packets, errPGPPacket
+				}
+				l, n = int(binary.BigEndian.Uint16(b)), 2
+			case 2:
+				if len(b) < 4 {
+					return packets, errPGPPacket
+				}
+				l, n = int(binary.BigEndian.Uint32(b)), 4
+			default:
+				l, n = len(b), 0
+			}
+			if l < 0 || l > len(b)-n {
+				return packets, errPGPPacket
+			}
+			body = b[n : n+l]
+			b = b[n+l:]
+		}
+		packets = append(packets, pgpPacket{tag: tag, body: body})
+	}
+	return packets, nil
+}
+
+func newFormatLength(b []byte) (l int, n int, partial bool) {
+	if len(b) < 1 {
+		return 0, 0, false
+	}
+	switch {
+	case b[0] < 192:
+		return int(b[0]), 1, false
+	case b[0] < 224:
+		if len(b) < 2 {
+			return 0, 0, false
+		}
+		return (int(b[0])-192)<<8 + int(b[1]) + 192, 2, false
+	case b[0] == 255:
+		if len(b) < 5 {
+			return 0, 0, false
+		}
+		return int(binary.BigEndian.Uint32(b[1:])), 5, false
+	default:
+		return 1 << (b[0] & 0x1f), 1, true
+	}
+}
+
+// readMPI reads a multiprecision integer.
+func readMPI(b []byte) (mpi []byte, rest []byte, err error) {
+	if len(b) < 2 {
+		return nil, nil, errPGPPacket
+	}
+	bits := int(binary.BigEndian.Uint16(b))
+	l := (bits + 7) / 8
+	if l > len(b)-2 {
+		return nil, nil, errPGPPacket
+	}
+	return b[2 : 2+l], b[2+l:], nil
+}
+
+// dearmor decodes an ASCII armored block. It returns the content of the first block of the given type.
+func dearmor(text []byte, blockType string) ([]byte, error) {
+	begin := []byte("-----BEGIN " + blockType + "-----")
+	idx := bytes.Index(text, begin)
+	if idx < 0 {
+		return nil, fmt.Errorf("no %s block", blockType)
+	}
+	scanner := bufio.NewScanner(bytes.NewReader(text[idx+len(begin):]))
+	scanner.Buffer(make([]byte, 0, 64*1024), len(text)+1)
+	inHeaders := true
+	var b64 strings.Builder
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if strings.HasPrefix(line, "-----END ") {
+			break
+		}
+		if inHeaders {
+			if line == "" {
+				inHeaders = false
+			} else if !strings.Contains(line, ": ") {
+				// no armor headers
+				inHeaders = false
+				b64.WriteString(line)
+			}
+			continue
+		}
+		if strings.HasPrefix(line, "=") && len(line) == 5 {
+			// checksum
+			continue
+		}
+		b64.WriteString(line)
+	}
+	return base64.StdEncoding.DecodeString(b64.String())
+}
+
+// pgpSignature is a decoded signature packet.
+type pgpSignature struct {
+	version  byte
+	sigType  byte
+	pubAlgo  byte
+	hash     crypto.Hash
+	hashed   []byte // the part of the packet that is hashed with the data
+	prefix   []byte
+	keyID    uint64
+	created  time.Time
+	mpis     [][]byte
+	hashAlgo byte
+}
+
+func parseSignature(body []byte) (*pgpSignature, error) {
+	if len(body) < 1 {
+		return nil, errPGPPacket
+	}
+	sig := &pgpSignature{version: body[0]}
+	var rest []byte
+	switch sig.version {
+	case 3:
+		if len(body) < 19 || body[1] != 5 {
+			return nil, errPGPPacket
+		}
+		sig.sigType = body[2]
+		sig.hashed = body[2:7]
+		sig.created = time.Unix(int64(binary.BigEndian.Uint32(body[3:])), 0).UTC()
+		sig.keyID = binary.BigEndian.Uint64(body[7:])
+		sig.pubAlgo = body[15]
+		sig.hashAlgo = body[16]
+		sig.prefix = body[17:19]
+		rest = body[19:]
+	case 4:
+		if len(body) < 6 {
+			return nil, errPGPPacket
+		}
+		sig.sigType = body[1]
+		sig.pubAlgo = body[2]
+		sig.hashAlgo = body[3]
+		hashedLen := int(binary.BigEndian.Uint16(body[4:]))
+		if 6+hashedLen+2 > len(body) {
+			return nil, errPGPPacket
+		}
+		sig.hashed = body[:6+hashedLen]
+		sig.parseSubpackets(body[6 : 6+hashedLen])
+		unhashedLen := int(binary.BigEndian.Uint16(body[6+hashedLen:]))
+		pos := 6 + hashedLen + 2
+		if pos+unhashedLen+2 > len(body) {
+			return nil, errPGPPacket
+		}
+		sig.parseSubpackets(body[pos : pos+unhashedLen])
+		pos += unhashedLen
+		sig.prefix = body[pos : pos+2]
+		rest = body[pos+2:]
+	default:
+		return nil, fmt.Errorf("unsupported signature version %d", sig.version)
+	}
+	for len(rest) > 0 {
+		mpi, r, err := readMPI(rest)
+		if err != nil {
+			return nil, err
+		}
+		sig.mpis = append(sig.mpis, mpi)
+		rest = r
+	}
+	sig.hash = pgpHashes[sig.hashAlgo]
+	return sig, nil
+}
+
+func (sig *pgpSignature) parseSubpackets(b []byte) {
+	for len(b) > 0 {
+		var l, n int
+		switch {
+		case b[0] < 192:
+			l, n = int(b[0]), 1
+		case b[0] < 255:
+			if len(b) < 2 {
+				return
+			}
+			l, n = (int(b[0])-192)<<8+int(b[1])+192, 2
+		default:
+			if len(b) < 5 {
+				return
+			}
+			l, n = int(binary.BigEndian.Uint32(b[1:])), 5
+		}
+		if l < 1 || l > len(b)-n {
+			return
+		}
+		typ := b[n] & 0x7f
+		data := b[n+1 : n+l]
+		switch {
+		case typ == pgpSubpacketTime && len(data) == 4:
+			sig.created = time.Unix(int64(binary.BigEndian.Uint32(data)), 0).UTC()
+		case typ == pgpSubpacketIssuer && len(data) == 8 && sig.keyID == 0:
+			sig.keyID = binary.BigEndian.Uint64(data)
+		case typ == pgpSubpacketIssuerF && len(data) == 21 && sig.keyID == 0:
+			sig.keyID = binary.BigEndian.Uint64(data[13:])
+		}
+		b = b[n+l:]
+	}
+}
+
+// digest hashes the signed data with the trailer of the signature.
+func (sig *pgpSignature) digest(data []byte) ([]byte, error) {
+	if sig.hash == 0 || !sig.hash.Available() {
+		return nil, fmt.Errorf("unsupported hash algorithm %d", sig.hashAlgo)
+	}
+	h := sig.hash.New()
+	h.Write(data)
+	h.Write(sig.hashed)
+	if sig.version == 4 {
+		var trailer [6]byte
+		trailer[0], trailer[1] = 4, 0xff
+		binary.BigEndian.PutUint32(trailer[2:], uint32(len(sig.hashed)))
+		h.Write(trailer[:])
+	}
+	return h.Sum(nil), nil
+}
+
+// pgpKey is a public key or subkey of a keyring.
+type pgpKey struct {
+	keyID   uint64
+	algo    byte
+	public  interface{}
+	userIDs *[]string
+}
+
+func parsePublicKey(body []byte) (*pgpKey, error) {
+	if len(body) < 6 || body[0] != 4 {
+		return nil, errors.New("unsupported public key version")
+	}
+	// v4 fingerprint: SHA1 of 0x99, the length of the packet and the packet
+	h := sha1.New()
+	h.Write([]byte{0x99, byte(len(body) >> 8), byte(len(body))})
+	h.Write(body)
+	fingerprint := h.Sum(nil)
+	key := &pgpKey{keyID: binary.BigEndian.Uint64(fingerprint[12:]), algo: body[5]}
+	rest := body[6:]
+	var err error
+	switch key.algo {
+	case pgpRSA, pgpRSASign, pgpRSAEncrypt:
+		var n, e []byte
+		if n, rest, err = readMPI(rest); err != nil {
+			return nil, err
+		}
+		if e, _, err = readMPI(rest); err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() > 1<<31 {
+			return nil, errors.New("invalid RSA exponent")
+		}
+		key.public = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}
+	case pgpDSA:
+		var p, q, g, y []byte
+		for _, dest := range []*[]byte{&p, &q, &g, &y} {
+			if *dest, rest, err = readMPI(rest); err != nil {
+				return nil, err
+			}
+		}
+		key.public = &dsa.PublicKey{
+			Parameters: dsa.Parameters{P: new(big.Int).SetBytes(p), Q: new(big.Int).SetBytes(q), G: new(big.Int).SetBytes(g)},
+			Y:          new(big.Int).SetBytes(y),
+		}
+	case pgpECDSA, pgpEdDSA:
+		if len(rest) < 1 || int(rest[0])+1 > len(rest) {
+			return nil, errPGPPacket
+		}
+		oid := fmt.Sprintf("%x", rest[1:1+int(rest[0])])
+		var point []byte
+		if point, _, err = readMPI(rest[1+int(rest[0]):]); err != nil {
+			return nil, err
+		}
+		if key.algo == pgpEdDSA {
+			if oid != ed25519OID || len(point) != 33 || point[0] != 0x40 {
+				return nil, errors.New("unsupported EdDSA curve")
+			}
+			key.public = ed25519.PublicKey(point[1:])
+			break
+		}
+		curve := pgpCurves[oid]
+		if curve == nil {
+			return nil, errors.New("unsupported ECDSA curve")
+		}
+		x, y := elliptic.Unmarshal(curve, point)
+		if x == nil {
+			return nil, errors.New("invalid ECDSA point")
+		}
+		key.public = &ecdsa.PublicKey{Curve: curve, X: x, Y: y}
+	default:
+		return nil, fmt.Errorf("unsupported public key algorithm %d", key.algo)
+	}
+	return key, nil
+}
+
+// verify checks the signature of the digest with the key.
+func (key *pgpKey) verify(sig *pgpSignature, digest []byte) error {
+	mpi := func(i int) *big.Int {
+		if i >= len(sig.mpis) {
+			return new(big.Int)
+		}
+		return new(big.Int).SetBytes(sig.mpis[i])
+	}
+	switch pub := key.public.(type) {
+	case *rsa.PublicKey:
+		if len(sig.mpis) != 1 {
+			return errPGPPacket
+		}
+		s := make([]byte, (pub.N.BitLen()+7)/8)
+		if len(sig.mpis[0]) > len(s) {
+			return errors.New("invalid RSA signature")
+		}
+		copy(s[len(s)-len(sig.mpis[0]):], sig.mpis[0])
+		return rsa.VerifyPKCS1v15(pub, sig.hash, digest, s)
+	case *dsa.PublicKey:
+		d := digest
+		if l := (pub.Q.BitLen() + 7) / 8; len(d) > l {
+			d = d[:l]
+		}
+		if !dsa.Verify(pub, d, mpi(0), mpi(1)) {
+			return errors.New("invalid DSA signature")
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.Verify(pub, digest, mpi(0), mpi(1)) {
+			return errors.New("invalid ECDSA signature")
+		}
+	case ed25519.PublicKey:
+		if len(sig.mpis) != 2 || len(sig.mpis[0]) > 32 || len(sig.mpis[1]) > 32 {
+			return errors.New("invalid EdDSA signature")
+		}
+		s := make([]byte, 64)
+		copy(s[32-len(sig.mpis[0]):32], sig.mpis[0])
+		copy(s[64-len(sig.mpis[1]):], sig.mpis[1])
+		if !ed25519.Verify(pub, digest, s) {
+			return errors.New("invalid EdDSA signature")
+		}
+	default:
+		return errors.New("unsupported public key algorithm")
+	}
+	return nil
+}
+
+// Keyring is a set of OpenPGP public keys.
+type Keyring struct {
+	keys map[uint64]*pgpKey
+}
+
+// ReadKeyring reads the public keys of an armored or binary keyring.
+func ReadKeyring(r io.Reader) (*Keyring, error) {
+	content, err := ioutil.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	kr := &Keyring{keys: make(map[uint64]*pgpKey)}
+	return kr, kr.add(content)
+}
+
+func (kr *Keyring) add(content []byte) error {
+	if bytes.Contains(content, []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----")) {
+		var blocks [][]byte
+		rest := content
+		for {
+			idx := bytes.Index(rest, []byte("-----BEGIN PGP PUBLIC KEY BLOCK-----"))
+			if idx < 0 {
+				break
+			}
+			b, err := dearmor(rest[idx:], "PGP PUBLIC KEY BLOCK")
+			if err != nil {
+				return err
+			}
+			blocks = append(blocks, b)
+			rest = rest[idx+1:]
+		}
+		for _, b := range blocks {
+			if err := kr.addPackets(b); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return kr.addPackets(content)
+}
+
+func (kr *Keyring) addPackets(b []byte) error {
+	packets, err := readPackets(b)
+	if err != nil && len(packets) == 0 {
+		return err
+	}
+	var userIDs *[]string
+	for _, p := range packets {
+		switch p.tag {
+		case pgpTagPublicKey, pgpTagPublicSubkey:
+			key, err := parsePublicKey(p.body)
+			if err != nil {
+				// unsupported keys are ignored
+				continue
+			}
+			if p.tag == pgpTagPublicKey {
+				userIDs = new([]string)
+			}
+			key.userIDs = userIDs
+			kr.keys[key.keyID] = key
+		case pgpTagUserID:
+			if userIDs != nil {
+				*userIDs = append(*userIDs, string(p.body))
+			}
+		}
+	}
+	return nil
+}
+
+// Len returns the number of keys and subkeys in the keyring.
+func (kr *Keyring) Len() int {
+	if kr == nil {
+		return 0
+	}
+	return len(kr.keys)
+}
+
+// verifyPGP verifies a detached OpenPGP signature of data.
+func verifyPGP(signature []byte, data []byte, keyring *Keyring) models.Signature {
+	result := models.Signature{Type: "pgp"}
+	if bytes.Contains(signature, []byte("-----BEGIN PGP SIGNATURE-----")) {
+		b, err := dearmor(signature, "PGP SIGNATURE")
+		if err != nil {
+			result.Error = err.Error()
+			return result
+		}
+		signature = b
+	}
+	packets, err := readPackets(signature)
+	if err != nil && len(packets) == 0 {
+		result.Error = err.Error()
+		return result
+	}
+	var sig *pgpSignature
+	for _, p := range packets {
+		if p.tag == pgpTagSignature {
+			sig, err = parseSignature(p.body)
+			if err != nil {
+				result.Error = err.Error()
+				return result
+			}
+			break
+		}
+	}
+	if sig == nil {
+		result.Error = "no signature packet"
+		return result
+	}
+	result.KeyID = fmt.Sprintf("%016X", sig.keyID)
+	if !sig.created.IsZero() {
+		result.SigningTime = &sig.created
+	}
+	if sig.sigType == pgpSigTypeText {
+		data = canonicalText(data)
+	}
+	digest, err := sig.digest(data)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	if !bytes.Equal(digest[:2], sig.prefix) {
+		result.Error = "hash mismatch"
+		return result
+	}
+	var key *pgpKey
+	if keyring != nil {
+		key = keyring.keys[sig.keyID]
+	}
+	if key == nil {
+		result.Error = "public key not found in keyring"
+		return result
+	}
+	if key.userIDs != nil {
+		result.UserIDs = *key.userIDs
+	}
+	err = key.verify(sig, digest)
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	// the keyring holds the trusted keys
+	result.Valid = true
+	result.Trusted = true
+	return result
+}
+
+// RecipientKeyIDs returns the key IDs of the recipients of an encrypted OpenPGP message.
+func RecipientKeyIDs(message []byte) []string {
+	if bytes.Contains(message, []byte("-----BEGIN PGP MESSAGE-----")) {
+		b, err := dearmor(message, "PGP MESSAGE")
+		if err != nil {
+			return nil
+		}
+		message = b
+	}
+	packets, _ := readPackets(message)
+	var ids []string
+	for _, p := range packets {
+		if p.tag == pgpTagPKESK && len(p.body) >= 10 && p.body[0] == 3 {
+			ids = append(ids, fmt.Sprintf("%016X", binary.BigEndian.Uint64(p.body[1:])))
+		}
+	}
+	return ids
+}
+
+// canonicalText converts the line endings to CRLF.
+func canonicalText(data []byte) []byte {
+	var b bytes.Buffer
+	b.Grow(len(data) + len(data)/40)
+	for i, c := range data {
+		if c == '\n' && (i == 0 || data[i-1] != '\r') {
+			b.WriteByte('\r')
+		}
+		b.WriteByte(c)
+	}
+	return b.Bytes()
+}
+
+// ClearSigned splits a cleartext signed message (RFC 4880, section 7) into the signed text and the signature.
+func ClearSigned(text []byte) (signed []byte, signature []byte, ok bool) {
+	begin := bytes.Index(text, []byte("-----BEGIN PGP SIGNED MESSAGE-----"))
+	if begin < 0 {
+		return nil, nil, false
+	}
+	sigStart := bytes.Index(text[begin:], []byte("-----BEGIN PGP SIGNATURE-----"))
+	if sigStart < 0 {
+		return nil, nil, false
+	}
+	sigStart += begin
+	body := text[begin:sigStart]
+	// skip the armor header line and the Hash headers
+	idx := bytes.Index(body, []byte("\n\n"))
+	idxCRLF := bytes.Index(body, []byte("\r\n\r\n"))
+	switch {
+	case idxCRLF >= 0 && (idx < 0 || idxCRLF < idx):
+		body = body[idxCRLF+4:]
+	case idx >= 0:
+		body = body[idx+2:]
+	default:
+		return nil, nil, false
+	}
+	lines := strings.Split(strings.Replace(string(body), "\r\n", "\n", -1), "\n")
+	// the line ending before the signature is not part of the signed text
+	if len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(strings.TrimPrefix(line, "- "), " \t")
+	}
+	return []byte(strings.Join(lines, "\r\n")), text[sigStart:], true
+}
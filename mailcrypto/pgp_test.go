@@ -0,0 +1,125 @@
+package mailcrypto
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+)
+
+const (
+	janeKeyID  = "D28AD04C758BBE50"
+	johnKeyID  = "EEDBE24A36044030"
+	otherKeyID = "C48636F769BE3F50"
+)
+
+func TestReadKeyring(t *testing.T) {
+	kr, err := ReadKeyring(bytes.NewReader(readTestdata(t, "keyring.asc")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	// two primary keys, without subkeys
+	if kr.Len() != 2 {
+		t.Errorf("Len = %d, want 2", kr.Len())
+	}
+	if _, err := ReadKeyring(bytes.NewReader([]byte("-----BEGIN PGP PUBLIC KEY BLOCK-----\n\n!!!\n-----END PGP PUBLIC KEY BLOCK-----\n"))); err == nil {
+		t.Error("invalid armor: no error")
+	}
+}
+
+func TestVerifyPGP(t *testing.T) {
+	v := newTestVerifier(t, "", filepath.Join("testdata", "keyring.asc"))
+	text := readTestdata(t, "text.txt")
+	content := readTestdata(t, "content.txt")
+	cases := []struct {
+		file    string
+		content []byte
+		keyID   string
+		userID  string
+	}{
+		{"ed25519-text.asc", text, janeKeyID, "Jane Doe <jane.doe@example.com>"},
+		{"rsa-binary.sig", content, johnKeyID, "John Smith <john.smith@example.com>"},
+	}
+	for _, c := range cases {
+		signature := readTestdata(t, c.file)
+		sig := v.VerifyPGP(signature, c.content)
+		if !sig.Valid || !sig.Trusted || sig.Error != "" || sig.Type != "pgp" || sig.SigningTime == nil {
+			t.Errorf("%s: %+v", c.file, sig)
+			continue
+		}
+		if sig.KeyID != c.keyID || len(sig.UserIDs) != 1 || sig.UserIDs[0] != c.userID {
+			t.Errorf("%s: key %s, user IDs %q", c.file, sig.KeyID, sig.UserIDs)
+		}
+		if sig := v.VerifyPGP(signature, tamper(c.content, 5)); sig.Valid || sig.Error == "" {
+			t.Errorf("%s: tampered content: %+v", c.file, sig)
+		}
+	}
+
+	// a text signature is independent of the line endings
+	if sig := v.VerifyPGP(readTestdata(t, "ed25519-text.asc"), bytes.Replace(text, []byte("\n"), []byte("\r\n"), -1)); !sig.Valid {
+		t.Errorf("CRLF text: %+v", sig)
+	}
+
+	binary := readTestdata(t, "rsa-binary.sig")
+	// the RSA signature MPI ends the packet
+	if sig := v.VerifyPGP(tamper(binary, -1), content); sig.Valid || sig.Error == "" {
+		t.Errorf("tampered signature: %+v", sig)
+	}
+	// the first bytes of the digest precede the MPI
+	packets, err := readPackets(binary)
+	if err != nil || len(packets) != 1 {
+		t.Fatalf("packets: %v, %s", packets, err)
+	}
+	parsed, err := parseSignature(packets[0].body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := len(binary) - 2 - len(parsed.mpis[0]) - 2
+	if sig := v.VerifyPGP(tamper(binary, prefix), content); sig.Valid || sig.Error != "hash mismatch" {
+		t.Errorf("tampered digest prefix: %+v", sig)
+	}
+	for _, n := range []int{1, 10, len(binary) - 1} {
+		if sig := v.VerifyPGP(binary[:n], content); sig.Valid || sig.Error == "" {
+			t.Errorf("truncated to %d bytes: %+v", n, sig)
+		}
+	}
+	armored := readTestdata(t, "ed25519-text.asc")
+	if sig := v.VerifyPGP(armored[:len(armored)/2], text); sig.Valid || sig.Error == "" {
+		t.Errorf("truncated armor: %+v", sig)
+	}
+
+	if sig := v.VerifyPGP(readTestdata(t, "unknown-key.asc"), text); sig.Valid || sig.KeyID != otherKeyID || sig.Error != "public key not found in keyring" {
+		t.Errorf("unknown key: %+v", sig)
+	}
+	noKeyring := newTestVerifier(t, "", "")
+	if sig := noKeyring.VerifyPGP(armored, text); sig.Valid || sig.KeyID != janeKeyID {
+		t.Errorf("no keyring: %+v", sig)
+	}
+}
+
+func TestClearSigned(t *testing.T) {
+	v := newTestVerifier(t, "", filepath.Join("testdata", "keyring.asc"))
+	clear := readTestdata(t, "clearsigned.asc")
+	signed, signature, ok := ClearSigned(clear)
+	if !ok {
+		t.Fatal("not a clear signed message")
+	}
+	if want := "Please find the invoice attached.\r\nRegards,\r\nJane"; string(signed) != want {
+		t.Errorf("signed text = %q, want %q", signed, want)
+	}
+	if sig := v.VerifyPGP(signature, signed); !sig.Valid || sig.KeyID != janeKeyID {
+		t.Errorf("clear signed: %+v", sig)
+	}
+
+	tampered := bytes.Replace(clear, []byte("invoice"), []byte("receipt"), 1)
+	signed, signature, ok = ClearSigned(tampered)
+	if !ok {
+		t.Fatal("tampered: not a clear signed message")
+	}
+	if sig := v.VerifyPGP(signature, signed); sig.Valid || sig.Error == "" {
+		t.Errorf("tampered clear signed: %+v", sig)
+	}
+
+	if _, _, ok = ClearSigned(clear[:bytes.Index(clear, []byte("-----BEGIN PGP SIGNATURE-----"))]); ok {
+		t.Error("truncated before the signature: ok")
+	}
+}
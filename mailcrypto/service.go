@@ -0,0 +1,148 @@
+// Package mailcrypto verifies the S/MIME and OpenPGP signatures of mails.
+package mailcrypto
+
+import (
+	"bytes"
+	"crypto/x509"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"go.uber.org/fx"
+)
+
+type Verifier interface {
+	utils.Service
+	utils.Prestartable
+	// VerifySMIME verifies a detached S/MIME signature (application/pkcs7-signature) of content.
+	VerifySMIME(signature []byte, content []byte) models.Signature
+	// VerifyOpaqueSMIME verifies a S/MIME signed-data structure, and returns the encapsulated content.
+	VerifyOpaqueSMIME(der []byte) (models.Signature, []byte)
+	// VerifyPGP verifies a detached OpenPGP signature of content.
+	VerifyPGP(signature []byte, content []byte) models.Signature
+}
+
+type impl struct {
+	logger     log15.Logger
+	trustStore string
+	keyring    string
+	roots      *x509.CertPool
+	keys       *Keyring
+}
+
+// NewVerifier returns a verifier that trusts the certificates in trustStore (a PEM file or a
+// directory of PEM files), or the system roots when trustStore is empty, and the OpenPGP keys in
+// keyring (a file or a directory of armored or binary keys).
+func NewVerifier(trustStore string, keyring string, logger log15.Logger) Verifier {
+	return &impl{
+		trustStore: trustStore,
+		keyring:    keyring,
+		logger:     logger,
+	}
+}
+
+func (i *impl) Name() string {
+	return "Verifier"
+}
+
+func (i *impl) Prestart() error {
+	if i.trustStore != "" {
+		roots := x509.NewCertPool()
+		err := readFiles(i.trustStore, func(content []byte) error {
+			roots.AppendCertsFromPEM(content)
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+		i.roots = roots
+	}
+	if i.keyring != "" {
+		keys := &Keyring{keys: make(map[uint64]*pgpKey)}
+		err := readFiles(i.keyring, keys.add)
+		if err != nil {
+			return err
+		}
+		i.keys = keys
+		i.logger.Info("OpenPGP keyring loaded", "keyring", i.keyring, "keys", keys.Len())
+	}
+	return nil
+}
+
+// readFiles calls f with the content of path, or of the files in path if it is a directory.
+func readFiles(path string, f func([]byte) error) error {
+	info, err := os.Stat(path)
+	if err != nil {
+		return err
+	}
+	paths := []string{path}
+	if info.IsDir() {
+		entries, err := ioutil.ReadDir(path)
+		if err != nil {
+			return err
+		}
+		paths = paths[:0]
+		for _, entry := range entries {
+			if !entry.IsDir() {
+				paths = append(paths, filepath.Join(path, entry.Name()))
+			}
+		}
+	}
+	for _, p := range paths {
+		content, err := ioutil.ReadFile(p)
+		if err != nil {
+			return err
+		}
+		err = f(content)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (i *impl) VerifySMIME(signature []byte, content []byte) models.Signature {
+	// the signed entity is canonicalized with CRLF line endings, but some agents sign the raw content
+	sig, _ := verifySMIME(signature, canonicalText(content), i.roots)
+	if !sig.Valid && !bytes.Equal(canonicalText(content), content) {
+		raw, _ := verifySMIME(signature, content, i.roots)
+		if raw.Valid {
+			return raw
+		}
+	}
+	return sig
+}
+
+func (i *impl) VerifyOpaqueSMIME(der []byte) (models.Signature, []byte) {
+	return verifySMIME(der, nil, i.roots)
+}
+
+func (i *impl) VerifyPGP(signature []byte, content []byte) models.Signature {
+	return verifyPGP(signature, canonicalText(content), i.keys)
+}
+
+type Params struct {
+	fx.In
+	Args   *arguments.Args `optional:"true"`
+	Logger log15.Logger    `optional:"true"`
+}
+
+var Service = fx.Provide(func(lc fx.Lifecycle, params Params) Verifier {
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	var trustStore, keyring string
+	if params.Args != nil {
+		trustStore = params.Args.Crypto.TrustStore
+		keyring = params.Args.Crypto.Keyring
+	}
+	v := NewVerifier(trustStore, keyring, logger)
+	utils.Append(lc, v, logger)
+	return v
+})
@@ -0,0 +1,290 @@
+package mailcrypto
+
+import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	_ "crypto/sha1"
+	_ "crypto/sha256"
+	_ "crypto/sha512"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/asn1"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// S/MIME messages are CMS structures (RFC 5652).
+
+var (
+	oidSignedData        = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 2}
+	oidEnvelopedData     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 7, 3}
+	oidAuthEnvelopedData = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 16, 1, 23}
+	oidMessageDigest     = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 4}
+	oidSigningTime       = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 9, 5}
+	oidRSAPSS            = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 1, 10}
+)
+
+var digestAlgorithms = map[string]crypto.Hash{
+	"1.3.14.3.2.26":          crypto.SHA1,
+	"2.16.840.1.101.3.4.2.1": crypto.SHA256,
+	"2.16.840.1.101.3.4.2.2": crypto.SHA384,
+	"2.16.840.1.101.3.4.2.3": crypto.SHA512,
+	"2.16.840.1.101.3.4.2.4": crypto.SHA224,
+}
+
+type contentInfo struct {
+	ContentType asn1.ObjectIdentifier
+	Content     asn1.RawValue `asn1:"explicit,optional,tag:0"`
+}
+
+type signedData struct {
+	Version          int
+	DigestAlgorithms []pkix.AlgorithmIdentifier `asn1:"set"`
+	ContentInfo      contentInfo
+	Certificates     asn1.RawValue `asn1:"optional,tag:0"`
+	CRLs             asn1.RawValue `asn1:"optional,tag:1"`
+	SignerInfos      []signerInfo  `asn1:"set"`
+}
+
+type signerInfo struct {
+	Version            int
+	SID                asn1.RawValue
+	DigestAlgorithm    pkix.AlgorithmIdentifier
+	SignedAttributes   asn1.RawValue `asn1:"optional,tag:0"`
+	SignatureAlgorithm pkix.AlgorithmIdentifier
+	Signature          []byte
+	UnsignedAttributes asn1.RawValue `asn1:"optional,tag:1"`
+}
+
+type issuerAndSerial struct {
+	Issuer asn1.RawValue
+	Serial *big.Int
+}
+
+type attribute struct {
+	Type   asn1.ObjectIdentifier
+	Values asn1.RawValue `asn1:"set"`
+}
+
+var ErrNotSignedData = errors.New("not a CMS signed-data structure")
+
+// parseCMS parses a CMS ContentInfo, in DER or BER.
+func parseCMS(der []byte) (*contentInfo, error) {
+	converted, err := berToDER(der)
+	if err != nil {
+		return nil, err
+	}
+	info := new(contentInfo)
+	_, err = asn1.Unmarshal(converted, info)
+	if err != nil {
+		return nil, err
+	}
+	return info, nil
+}
+
+// SMIMEType returns "signed-data", "enveloped-data" or "auth-enveloped-data" for a CMS structure.
+func SMIMEType(der []byte) string {
+	info, err := parseCMS(der)
+	if err != nil {
+		return ""
+	}
+	switch {
+	case info.ContentType.Equal(oidSignedData):
+		return "signed-data"
+	case info.ContentType.Equal(oidEnvelopedData):
+		return "enveloped-data"
+	case info.ContentType.Equal(oidAuthEnvelopedData):
+		return "auth-enveloped-data"
+	}
+	return ""
+}
+
+// verifySMIME verifies a CMS signed-data structure. When content is nil, the signed content is
+// the one encapsulated in the structure, and it is returned.
+func verifySMIME(der []byte, content []byte, roots *x509.CertPool) (models.Signature, []byte) {
+	sig := models.Signature{Type: "smime"}
+	info, err := parseCMS(der)
+	if err != nil {
+		sig.Error = err.Error()
+		return sig, nil
+	}
+	if !info.ContentType.Equal(oidSignedData) {
+		sig.Error = ErrNotSignedData.Error()
+		return sig, nil
+	}
+	var sd signedData
+	_, err = asn1.Unmarshal(info.Content.Bytes, &sd)
+	if err != nil {
+		sig.Error = err.Error()
+		return sig, nil
+	}
+	if content == nil {
+		var encapsulated []byte
+		if len(sd.ContentInfo.Content.Bytes) > 0 {
+			_, err = asn1.Unmarshal(sd.ContentInfo.Content.Bytes, &encapsulated)
+			if err != nil {
+				sig.Error = err.Error()
+				return sig, nil
+			}
+		}
+		content = encapsulated
+	}
+	var certs []*x509.Certificate
+	if len(sd.Certificates.Bytes) > 0 {
+		certs, err = x509.ParseCertificates(sd.Certificates.Bytes)
+		if err != nil {
+			sig.Error = err.Error()
+			return sig, content
+		}
+	}
+	if len(sd.SignerInfos) == 0 {
+		sig.Error = "no signer"
+		return sig, content
+	}
+	// only the first signer is reported
+	signer := sd.SignerInfos[0]
+	cert := findSigner(signer.SID, certs)
+	if cert == nil {
+		sig.Error = "signer certificate not found"
+		return sig, content
+	}
+	sig.Signer = describeCertificate(cert)
+
+	signingTime, err := checkSignerInfo(signer, cert, content)
+	if err != nil {
+		sig.Error = err.Error()
+		return sig, content
+	}
+	sig.Valid = true
+	if !signingTime.IsZero() {
+		sig.SigningTime = &signingTime
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, c := range certs {
+		if c != cert {
+			intermediates.AddCert(c)
+		}
+	}
+	opts := x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageEmailProtection},
+	}
+	if !signingTime.IsZero() {
+		opts.CurrentTime = signingTime
+	}
+	_, err = cert.Verify(opts)
+	if err != nil {
+		sig.Error = err.Error()
+	} else {
+		sig.Trusted = true
+	}
+	return sig, content
+}
+
+func findSigner(sid asn1.RawValue, certs []*x509.Certificate) *x509.Certificate {
+	if sid.Class == asn1.ClassContextSpecific && sid.Tag == 0 {
+		for _, c := range certs {
+			if bytes.Equal(c.SubjectKeyId, sid.Bytes) {
+				return c
+			}
+		}
+		return nil
+	}
+	var ias issuerAndSerial
+	_, err := asn1.Unmarshal(sid.FullBytes, &ias)
+	if err != nil {
+		return nil
+	}
+	for _, c := range certs {
+		if bytes.Equal(c.RawIssuer, ias.Issuer.FullBytes) && c.SerialNumber.Cmp(ias.Serial) == 0 {
+			return c
+		}
+	}
+	return nil
+}
+
+// checkSignerInfo verifies the message digest and the signature of a signer.
+func checkSignerInfo(signer signerInfo, cert *x509.Certificate, content []byte) (signingTime time.Time, err error) {
+	hash, ok := digestAlgorithms[signer.DigestAlgorithm.Algorithm.String()]
+	if !ok || !hash.Available() {
+		return signingTime, fmt.Errorf("unsupported digest algorithm %s", signer.DigestAlgorithm.Algorithm)
+	}
+	h := hash.New()
+	h.Write(content)
+	digest := h.Sum(nil)
+
+	signed := content
+	if len(signer.SignedAttributes.FullBytes) > 0 {
+		// the attributes are the content of the implicitly tagged SET
+		var attrs []attribute
+		rest := signer.SignedAttributes.Bytes
+		for len(rest) > 0 {
+			var a attribute
+			rest, err = asn1.Unmarshal(rest, &a)
+			if err != nil {
+				return signingTime, err
+			}
+			attrs = append(attrs, a)
+		}
+		var messageDigest []byte
+		for _, a := range attrs {
+			switch {
+			case a.Type.Equal(oidMessageDigest):
+				_, err = asn1.Unmarshal(a.Values.Bytes, &messageDigest)
+				if err != nil {
+					return signingTime, err
+				}
+			case a.Type.Equal(oidSigningTime):
+				_, _ = asn1.Unmarshal(a.Values.Bytes, &signingTime)
+			}
+		}
+		if !bytes.Equal(messageDigest, digest) {
+			return signingTime, errors.New("message digest mismatch")
+		}
+		// the signature covers the DER encoding of the attributes, as a SET
+		signed = append([]byte{0x31}, signer.SignedAttributes.FullBytes[1:]...)
+		h = hash.New()
+		h.Write(signed)
+		digest = h.Sum(nil)
+	}
+
+	switch pub := cert.PublicKey.(type) {
+	case *rsa.PublicKey:
+		if signer.SignatureAlgorithm.Algorithm.Equal(oidRSAPSS) {
+			err = rsa.VerifyPSS(pub, hash, digest, signer.Signature, nil)
+		} else {
+			err = rsa.VerifyPKCS1v15(pub, hash, digest, signer.Signature)
+		}
+	case *ecdsa.PublicKey:
+		if !ecdsa.VerifyASN1(pub, digest, signer.Signature) {
+			err = errors.New("invalid ECDSA signature")
+		}
+	case ed25519.PublicKey:
+		if !ed25519.Verify(pub, signed, signer.Signature) {
+			err = errors.New("invalid Ed25519 signature")
+		}
+	default:
+		err = errors.New("unsupported public key algorithm")
+	}
+	return signingTime, err
+}
+
+func describeCertificate(cert *x509.Certificate) *models.Certificate {
+	return &models.Certificate{
+		Subject:      cert.Subject.String(),
+		Issuer:       cert.Issuer.String(),
+		SerialNumber: cert.SerialNumber.String(),
+		NotBefore:    cert.NotBefore,
+		NotAfter:     cert.NotAfter,
+		Emails:       cert.EmailAddresses,
+	}
+}
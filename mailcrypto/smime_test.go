@@ -0,0 +1,121 @@
+package mailcrypto
+
+import (
+	"bytes"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+)
+
+func readTestdata(t *testing.T, name string) []byte {
+	content, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return content
+}
+
+func newTestVerifier(t *testing.T, trustStore string, keyring string) Verifier {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	v := NewVerifier(trustStore, keyring, logger)
+	if err := v.Prestart(); err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
+
+// tamper returns a copy of b with the byte at i flipped. Negative indexes count from the end.
+func tamper(b []byte, i int) []byte {
+	c := append([]byte{}, b...)
+	if i < 0 {
+		i += len(c)
+	}
+	c[i] ^= 0x01
+	return c
+}
+
+func TestVerifySMIME(t *testing.T) {
+	v := newTestVerifier(t, filepath.Join("testdata", "ca.pem"), "")
+	content := readTestdata(t, "content.txt")
+	cases := []struct {
+		file   string
+		signer string
+	}{
+		{"detached.p7s", "jane.doe@example.com"},
+		{"detached-ecdsa-ber.p7s", "john.smith@example.com"},
+		{"noattr.p7s", "jane.doe@example.com"},
+	}
+	for _, c := range cases {
+		signature := readTestdata(t, c.file)
+		if SMIMEType(signature) != "signed-data" {
+			t.Errorf("%s: type %q", c.file, SMIMEType(signature))
+		}
+		sig := v.VerifySMIME(signature, content)
+		if !sig.Valid || !sig.Trusted || sig.Error != "" || sig.Type != "smime" {
+			t.Errorf("%s: %+v", c.file, sig)
+			continue
+		}
+		if sig.Signer == nil || len(sig.Signer.Emails) != 1 || sig.Signer.Emails[0] != c.signer {
+			t.Errorf("%s: signer %+v", c.file, sig.Signer)
+		}
+		// the signed entity with LF line endings is canonicalized
+		if sig := v.VerifySMIME(signature, bytes.Replace(content, []byte("\r\n"), []byte("\n"), -1)); !sig.Valid {
+			t.Errorf("%s: LF content: %+v", c.file, sig)
+		}
+
+		if sig := v.VerifySMIME(signature, tamper(content, -3)); sig.Valid || sig.Error == "" {
+			t.Errorf("%s: tampered content: %+v", c.file, sig)
+		}
+		if sig := v.VerifySMIME(signature[:len(signature)/2], content); sig.Valid || sig.Error == "" {
+			t.Errorf("%s: truncated signature: %+v", c.file, sig)
+		}
+	}
+
+	signed := readTestdata(t, "detached.p7s")
+	// the signature value ends the DER structure
+	if sig := v.VerifySMIME(tamper(signed, -1), content); sig.Valid || sig.Error == "" {
+		t.Errorf("tampered signature: %+v", sig)
+	}
+	if sig := v.VerifySMIME(signed, nil); sig.Valid {
+		t.Errorf("no content: %+v", sig)
+	}
+
+	// without the test CA, the signature is valid but not trusted
+	untrusted := newTestVerifier(t, "", "")
+	if sig := untrusted.VerifySMIME(signed, content); !sig.Valid || sig.Trusted || sig.Error == "" {
+		t.Errorf("untrusted: %+v", sig)
+	}
+}
+
+func TestVerifyOpaqueSMIME(t *testing.T) {
+	v := newTestVerifier(t, filepath.Join("testdata", "ca.pem"), "")
+	opaque := readTestdata(t, "opaque-ber.p7m")
+	sig, content := v.VerifyOpaqueSMIME(opaque)
+	if !sig.Valid || !sig.Trusted || sig.SigningTime == nil {
+		t.Errorf("opaque: %+v", sig)
+	}
+	if !bytes.Equal(content, readTestdata(t, "content.txt")) {
+		t.Errorf("opaque: content %q", content)
+	}
+
+	idx := bytes.Index(opaque, []byte("invoice"))
+	if idx < 0 {
+		t.Fatal("encapsulated content not found")
+	}
+	sig, content = v.VerifyOpaqueSMIME(tamper(opaque, idx))
+	if sig.Valid || sig.Error != "message digest mismatch" || !bytes.Contains(content, []byte("hnvoice")) {
+		t.Errorf("tampered content: %+v, %q", sig, content)
+	}
+	if sig, _ = v.VerifyOpaqueSMIME(opaque[:len(opaque)-10]); sig.Valid || sig.Error == "" {
+		t.Errorf("truncated: %+v", sig)
+	}
+	if sig, _ = v.VerifyOpaqueSMIME([]byte("not a CMS structure")); sig.Valid || sig.Error == "" {
+		t.Errorf("garbage: %+v", sig)
+	}
+	if SMIMEType([]byte("not a CMS structure")) != "" {
+		t.Error("garbage has a S/MIME type")
+	}
+}
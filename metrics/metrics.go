@@ -22,6 +22,7 @@ type Metrics struct {
 	CollectorPendingSize prometheus.Gauge
 	ParsingDuration      prometheus.Histogram
 	ParsingErrors        *prometheus.CounterVec
+	Mails                *prometheus.CounterVec
 	MessageSize prometheus.Histogram
 	Registry             *prometheus.Registry
 }
@@ -82,6 +83,14 @@ func newMetrics() *Metrics {
 		[]string{"family"},
 	)
 
+	m.Mails = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "mails_total",
+			Help: "Number of parsed emails by direction",
+		},
+		[]string{"direction", "family"},
+	)
+
 	m.MessageSize = prometheus.NewHistogram(
 		prometheus.HistogramOpts{
 			Name: "message_size",
@@ -99,6 +108,7 @@ func newMetrics() *Metrics {
 		m.CollectorPendingSize,
 		m.ParsingDuration,
 		m.ParsingErrors,
+		m.Mails,
 		m.MessageSize,
 	)
 	return m
@@ -7,28 +7,383 @@ import (
 )
 
 type FeaturesMail struct {
-	BaseInfos   `yaml:",inline"`
-	UID         string              `json:"uid,omitempty"`
-	Size        int64               `json:"size_bytes"`
-	ContentType string              `json:"content_type,omitempty"`
-	Reported    string              `json:"timereported,omitempty"`
-	Headers     map[string][]string `json:"headers,omitempty"`
-	Attachments []*Attachment       `json:"attachments,omitempty"`
-	BagOfWords  map[string]int      `json:"bag_of_words,omitempty"`
-	Keywords    []string            `json:"keywords,omitempty" yaml:",flow"`
-	Phrases     []string            `json:"phrases,omitempty" yaml:",flow"`
-	Language    string              `json:"language,omitempty"`
-	TimeHeader  string              `json:"time_header,omitempty"`
-	Received    []ReceivedElement   `json:"received,omitempty"`
+	BaseInfos     `yaml:",inline"`
+	UID           string              `json:"uid,omitempty"`
+	Size          int64               `json:"size_bytes"`
+	ContentType   string              `json:"content_type,omitempty"`
+	Reported      string              `json:"timereported,omitempty"`
+	Headers       map[string][]string `json:"headers,omitempty"`
+	Attachments   []*Attachment       `json:"attachments,omitempty"`
+	BagOfWords    map[string]int      `json:"bag_of_words,omitempty"`
+	Keywords      []string            `json:"keywords,omitempty" yaml:",flow"`
+	Phrases       []string            `json:"phrases,omitempty" yaml:",flow"`
+	Language      string              `json:"language,omitempty"`
+	TimeHeader    string              `json:"time_header,omitempty"`
+	Received      []ReceivedElement   `json:"received,omitempty"`
+	ReceivedChain *ReceivedChain      `json:"received_chain,omitempty"`
 	// TODO: check that From is consistent/scam
-	From          *FromAddress      `json:"from,omitempty"`
-	To            []Address         `json:"to,omitempty" yaml:",flow"`
-	Title         string            `json:"title,omitempty"`
-	Emails        []string          `json:"emails,omitempty"`
-	URLs          []string          `json:"urls,omitempty"`
-	PhishtankURLS []*PhishtankEntry `json:"phishtank_urls,omitempty"`
-	Images        []string          `json:"images,omitempty"`
-	DKIM          *DKIMValidation   `json:"dkim,omitempty"`
+	From        *FromAddress `json:"from,omitempty"`
+	To          []Address    `json:"to,omitempty" yaml:",flow"`
+	Cc          []Address    `json:"cc,omitempty" yaml:",flow"`
+	Bcc         []Address    `json:"bcc,omitempty" yaml:",flow"`
+	ReplyTo     []Address    `json:"reply_to,omitempty" yaml:",flow"`
+	Sender      *Address     `json:"sender,omitempty"`
+	ReturnPath  *Address     `json:"return_path,omitempty"`
+	DeliveredTo []Address    `json:"delivered_to,omitempty" yaml:",flow"`
+	// AddressErrors maps the address headers to their parsing error
+	AddressErrors    map[string]string `json:"address_errors,omitempty"`
+	AddressRelations *AddressRelations `json:"address_relations,omitempty"`
+	Title            string            `json:"title,omitempty"`
+	Emails           []string          `json:"emails,omitempty"`
+	URLs             []string          `json:"urls,omitempty"`
+	URLSources       map[string]string `json:"url_sources,omitempty"`
+	PhishtankURLS    []*PhishtankEntry `json:"phishtank_urls,omitempty"`
+	Images           []string          `json:"images,omitempty"`
+	DKIM             *DKIMValidation   `json:"dkim,omitempty"`
+	HTML             *HTMLAnalysis     `json:"html,omitempty"`
+	RemoteContent    *RemoteContent    `json:"remote_content,omitempty"`
+	// brands whose logo appears in the images, while the sender domain does not belong to the brand
+	ImpersonatedBrands []string `json:"impersonated_brands,omitempty"`
+	Crypto             *Crypto  `json:"crypto,omitempty"`
+	Report             *Report  `json:"report,omitempty"`
+	// Category is bounce, report, auto-reply, list, bulk, transactional or personal
+	Category string `json:"category,omitempty"`
+	// ESP is the email service provider that sent the mail
+	ESP              string   `json:"esp,omitempty"`
+	CategoryEvidence []string `json:"category_evidence,omitempty"`
+	// AddrLocation locates the client that submitted the mail
+	AddrLocation *GeoIPResult `json:"addr_location,omitempty"`
+	// URLIPLocations locates the URL hosts that are IP addresses
+	URLIPLocations map[string]*GeoIPResult `json:"url_ip_locations,omitempty"`
+	DNS            *DNS                    `json:"dns,omitempty"`
+	// Lookalikes are the sender domains and URL hosts that imitate a protected domain
+	Lookalikes    []Lookalike    `json:"lookalikes,omitempty"`
+	Impersonation *Impersonation `json:"impersonation,omitempty"`
+	// HeaderOrder are the names of the headers, in their original order and casing
+	HeaderOrder     []string         `json:"header_order,omitempty"`
+	HeaderAnomalies *HeaderAnomalies `json:"header_anomalies,omitempty"`
+	Mailer          *Mailer          `json:"mailer,omitempty"`
+	Thread          *Thread          `json:"thread,omitempty"`
+	// Segments split the body into new content, quoted history, signature and disclaimer. The
+	// text analysis is run on the new content only.
+	Segments *BodySegments `json:"segments,omitempty"`
+	// Entities are the named entities and the financial indicators found in the subject and the
+	// new content of the body
+	Entities *Entities `json:"entities,omitempty"`
+	// DLP reports the sensitive data found in the body and in the text of the attachments
+	DLP       *DLP       `json:"dlp,omitempty"`
+	Direction *Direction `json:"direction,omitempty"`
+	// Obfuscation measures the tricks used to defeat the keyword filters, before the unicode
+	// normalization
+	Obfuscation *Obfuscation `json:"obfuscation,omitempty"`
+	// Languages are the languages of the subject, of the new content of the body, of the quoted
+	// history, of the signature and of the disclaimer
+	Languages []DetectedLanguage `json:"languages,omitempty"`
+	// Multilingual is set when the parts of the mail, or the body itself, use several languages
+	Multilingual bool `json:"multilingual,omitempty"`
+	// Classification is the class of the mail according to the trained Bayesian classifier
+	Classification *Classification `json:"classification,omitempty"`
+}
+
+// Classification gives the probabilities of the classes learned by the Bayesian classifier.
+type Classification struct {
+	// Class is the most probable class
+	Class         string             `json:"class"`
+	Probability   float64            `json:"probability"`
+	Probabilities map[string]float64 `json:"probabilities"`
+	// Tokens is the number of tokens of the mail known to the model
+	Tokens int `json:"tokens"`
+}
+
+// DetectedLanguage is the language of a text, or of a part of a mail.
+type DetectedLanguage struct {
+	Part       string  `json:"part,omitempty"`
+	Language   string  `json:"language"`
+	Confidence float64 `json:"confidence"`
+	// Secondary is another language used in a significant share of the text
+	Secondary      string  `json:"secondary,omitempty"`
+	SecondaryShare float64 `json:"secondary_share,omitempty"`
+}
+
+const (
+	DirectionInbound  = "inbound"
+	DirectionOutbound = "outbound"
+	DirectionInternal = "internal"
+	// DirectionExternal is a mail relayed between external parties
+	DirectionExternal = "external"
+)
+
+// Direction is the direction of a mail, relative to the organization.
+type Direction struct {
+	Direction      string `json:"direction"`
+	InternalSender bool   `json:"internal_sender"`
+	TrustedClient  bool   `json:"trusted_client"`
+	Authenticated  bool   `json:"authenticated"`
+	// SpoofedInternalSender is set when an internal sender domain is used by an untrusted client
+	SpoofedInternalSender bool     `json:"spoofed_internal_sender"`
+	InternalRecipients    []string `json:"internal_recipients,omitempty"`
+	ExternalRecipients    []string `json:"external_recipients,omitempty"`
+}
+
+// Is reports whether the direction of the mail is one of dirs. An unknown direction matches none.
+func (d *Direction) Is(dirs ...string) bool {
+	if d == nil {
+		return false
+	}
+	for _, dir := range dirs {
+		if d.Direction == dir {
+			return true
+		}
+	}
+	return false
+}
+
+// Obfuscation reports the obfuscation of the subject, of the display name of the sender and of
+// the body, measured on the raw texts.
+type Obfuscation struct {
+	Subject     *TextObfuscation `json:"subject,omitempty"`
+	DisplayName *TextObfuscation `json:"display_name,omitempty"`
+	Body        *TextObfuscation `json:"body,omitempty"`
+}
+
+// TextObfuscation measures a raw text. The densities are relative to the number of characters.
+type TextObfuscation struct {
+	Length int `json:"length"`
+	// InvisibleChars are the zero-width characters, soft hyphens, fillers and other format
+	// characters
+	InvisibleChars   int     `json:"invisible_chars"`
+	InvisibleDensity float64 `json:"invisible_density"`
+	// BidiControls are the embeddings, overrides, isolates and marks that reorder the text
+	BidiControls int `json:"bidi_controls"`
+	// MixedScriptWords are the words that mix scripts, in the sense of UTS #39
+	MixedScriptWords   int      `json:"mixed_script_words"`
+	MixedScriptSamples []string `json:"mixed_script_samples,omitempty"`
+	// ConfusableSubstitutions are the letters of another script that look like a Latin letter,
+	// in Latin words
+	ConfusableSubstitutions int `json:"confusable_substitutions"`
+	// CompatibilityChars are the characters changed by the unicode normalization, like the
+	// fullwidth and the mathematical letters
+	CompatibilityChars int `json:"compatibility_chars"`
+	// NormalizationDistance is the proportion of the characters changed or removed by the
+	// normalization and the de-obfuscation
+	NormalizationDistance float64 `json:"normalization_distance"`
+	// Entropy is the Shannon entropy of the characters, in bits
+	Entropy float64 `json:"entropy"`
+	// HTMLEntities are the numeric character references that encode plain letters and digits
+	HTMLEntities int `json:"html_entities,omitempty"`
+	// Deobfuscated is the de-obfuscated text, for the subject and the display name, when it
+	// differs from the raw text
+	Deobfuscated string `json:"deobfuscated,omitempty"`
+}
+
+// DLP is the result of the data-loss-prevention detectors.
+type DLP struct {
+	// Verdict is set when a detector reached its threshold
+	Verdict   bool         `json:"verdict"`
+	Triggered []string     `json:"triggered,omitempty"`
+	Findings  []DLPFinding `json:"findings,omitempty"`
+}
+
+// DLPFinding counts the values found by a detector in the body or in an attachment.
+type DLPFinding struct {
+	Detector string `json:"detector"`
+	// Source is "body", or the name of the attachment
+	Source string `json:"source"`
+	// Count is the number of distinct values
+	Count int `json:"count"`
+	// Confidence is the highest confidence of the values, between 0 and 1
+	Confidence float64 `json:"confidence"`
+	// Samples are redacted: the full values are never reported
+	Samples []string `json:"samples,omitempty"`
+}
+
+// Entities are the people, organisations, places and the payment details mentioned in a mail.
+type Entities struct {
+	People        []string `json:"people,omitempty"`
+	Organisations []string `json:"organisations,omitempty"`
+	Places        []string `json:"places,omitempty"`
+	Amounts       []Amount `json:"amounts,omitempty"`
+	// IBANs have a valid checksum, and are written without spaces
+	IBANs []string `json:"ibans,omitempty"`
+	BICs  []string `json:"bics,omitempty"`
+	// Phones are in E.164 format
+	Phones  []string `json:"phones,omitempty"`
+	Wallets []Wallet `json:"wallets,omitempty"`
+	// GiftCards are the brands of the gift cards that are mentioned ("generic" when no brand is
+	// given)
+	GiftCards       []string         `json:"gift_cards,omitempty"`
+	TrackingNumbers []TrackingNumber `json:"tracking_numbers,omitempty"`
+	References      []Reference      `json:"references,omitempty"`
+}
+
+// Amount is a sum of money.
+type Amount struct {
+	Text string `json:"text"`
+	// Currency is an ISO 4217 code, or BTC/ETH
+	Currency string  `json:"currency"`
+	Value    float64 `json:"value"`
+}
+
+// Wallet is a cryptocurrency address.
+type Wallet struct {
+	Currency string `json:"currency"`
+	Address  string `json:"address"`
+	// Checksummed is set when the address carries a checksum that was verified (always for BTC,
+	// mixed-case addresses for ETH)
+	Checksummed bool `json:"checksummed"`
+}
+
+// TrackingNumber is a parcel tracking number.
+type TrackingNumber struct {
+	Carrier string `json:"carrier"`
+	Number  string `json:"number"`
+}
+
+// Reference is an invoice, order or purchase order number.
+type Reference struct {
+	Kind  string `json:"kind"`
+	Value string `json:"value"`
+}
+
+type BodySegments struct {
+	New            string `json:"new,omitempty"`
+	Quoted         string `json:"quoted,omitempty"`
+	Signature      string `json:"signature,omitempty"`
+	Disclaimer     string `json:"disclaimer,omitempty"`
+	NewSize        int    `json:"new_size"`
+	QuotedSize     int    `json:"quoted_size"`
+	SignatureSize  int    `json:"signature_size"`
+	DisclaimerSize int    `json:"disclaimer_size"`
+}
+
+// Thread is the position of a mail in its conversation.
+type Thread struct {
+	ThreadID  string `json:"thread_id"`
+	MessageID string `json:"message_id"`
+	ParentID  string `json:"parent_id,omitempty"`
+	// ParentSeen is set when the parent message has been analysed
+	ParentSeen bool `json:"parent_seen"`
+	// ParentFrom is the sender of the parent message, when it has been analysed
+	ParentFrom string `json:"parent_from,omitempty"`
+	// Depth is the number of ancestors of the message
+	Depth int `json:"depth"`
+	// Position is the rank of the message in the thread, by arrival
+	Position   int  `json:"position"`
+	ThreadSize int  `json:"thread_size"`
+	Duplicate  bool `json:"duplicate,omitempty"`
+	// IsReply is set when the subject has a reply prefix
+	IsReply bool `json:"is_reply"`
+	// SubjectMatch is set when the message was threaded by its subject only
+	SubjectMatch bool `json:"subject_match,omitempty"`
+	// FakeReply is set when the subject has a reply prefix, but no reference to a known message
+	FakeReply       bool   `json:"fake_reply"`
+	FakeReplyReason string `json:"fake_reply_reason,omitempty"`
+}
+
+// HeaderAnomalies lists the suspicious properties of the headers of a mail.
+type HeaderAnomalies struct {
+	MessageIDDomain         string `json:"message_id_domain,omitempty"`
+	MessageIDDomainMismatch bool   `json:"message_id_domain_mismatch"`
+	DateInFuture            bool   `json:"date_in_future"`
+	DateTooOld              bool   `json:"date_too_old"`
+	// InvalidDate is set when the Date header is not in the RFC 5322 format
+	InvalidDate      bool     `json:"invalid_date"`
+	MissingHeaders   []string `json:"missing_headers,omitempty"`
+	DuplicateHeaders []string `json:"duplicate_headers,omitempty"`
+	EightBitHeaders  []string `json:"eight_bit_headers,omitempty"`
+	// MailerInconsistent is set when the X-Mailer or User-Agent header does not match the
+	// headers layout
+	MailerInconsistent bool     `json:"mailer_inconsistent"`
+	Anomalies          []string `json:"anomalies,omitempty"`
+}
+
+// Mailer is the software that wrote a mail.
+type Mailer struct {
+	Name string `json:"name,omitempty"`
+	// Kind is mua, webmail, library or spam-kit
+	Kind string `json:"kind,omitempty"`
+	// Declared is the X-Mailer or User-Agent header
+	Declared     string   `json:"declared,omitempty"`
+	Score        int      `json:"score,omitempty"`
+	Inconsistent bool     `json:"inconsistent"`
+	Evidence     []string `json:"evidence,omitempty"`
+}
+
+// AddressRelations relates the envelope of a mail to its address headers.
+type AddressRelations struct {
+	// RecipientCount is the number of distinct recipients, in the envelope and in the To, Cc and
+	// Bcc headers
+	RecipientCount int `json:"recipient_count"`
+	// HiddenRecipients are the envelope recipients that are not in the To and Cc headers, as the
+	// Bcc recipients
+	HiddenRecipients      []string `json:"hidden_recipients,omitempty"`
+	ReturnPathDiffers     bool     `json:"return_path_differs"`
+	ReplyToDomainMismatch bool     `json:"reply_to_domain_mismatch"`
+}
+
+// Impersonation describes a mail that pretends to come from a VIP.
+type Impersonation struct {
+	VIP   string `json:"vip"`
+	Title string `json:"title,omitempty"`
+	// Source is display-name or signature
+	Source string `json:"source"`
+	// Match is exact, reordered, initials or fuzzy
+	Match       string `json:"match"`
+	MatchedName string `json:"matched_name"`
+	Address     string `json:"address"`
+	// KnownAddress is set when the sender address belongs to the VIP
+	KnownAddress bool `json:"known_address"`
+	FreeMail     bool `json:"free_mail"`
+	// FirstContact is set when the address had never used the name of the VIP
+	FirstContact bool `json:"first_contact"`
+	// ReplyTo are the Reply-To addresses that differ from From
+	ReplyTo  []string `json:"reply_to,omitempty"`
+	Evidence []string `json:"evidence"`
+}
+
+// Lookalike is a host that imitates a protected domain.
+type Lookalike struct {
+	Host string `json:"host"`
+	// Source is from, reply-to, envelope or url
+	Source    string `json:"source"`
+	Protected string `json:"protected"`
+	// Technique is homoglyph, punycode, transposition, bitsquatting, missing-dot, typo, subdomain
+	// or tld-swap
+	Technique string `json:"technique"`
+}
+
+// DNS holds the results of the DNS lookups about the sender of a mail.
+type DNS struct {
+	ClientIP     string   `json:"client_ip,omitempty"`
+	ReverseNames []string `json:"reverse_names,omitempty"`
+	// FCrDNS is set when a reverse name of the client IP resolves back to the IP
+	FCrDNS   bool        `json:"fcrdns"`
+	Domains  []DomainDNS `json:"domains,omitempty"`
+	Listings []Listing   `json:"listings,omitempty"`
+	Errors   []string    `json:"errors,omitempty"`
+}
+
+type DomainDNS struct {
+	Domain string `json:"domain"`
+	// Sources are "envelope" (MAIL FROM) and "from" (From header)
+	Sources []string `json:"sources"`
+	HasMX   bool     `json:"has_mx"`
+	// NullMX is set when the domain declares that it does not accept mail (RFC 7505)
+	NullMX bool     `json:"null_mx"`
+	MX     []string `json:"mx,omitempty"`
+	HasNS  bool     `json:"has_ns"`
+	NS     []string `json:"ns,omitempty"`
+	HasA   bool     `json:"has_a"`
+}
+
+// Listing is an IP address or a domain listed by a DNS blocklist.
+type Listing struct {
+	List string `json:"list"`
+	Item string `json:"item"`
+	// Kind is "ip" or "domain"
+	Kind     string   `json:"kind"`
+	Answers  []string `json:"answers"`
+	Meanings []string `json:"meanings,omitempty"`
 }
 
 func (f *FeaturesMail) Encode(indent bool) ([]byte, error) {
@@ -39,6 +394,91 @@ func (f *FeaturesMail) Encode(indent bool) ([]byte, error) {
 	}
 }
 
+// Crypto describes the S/MIME and OpenPGP signatures and encryption of a mail.
+type Crypto struct {
+	Signatures []Signature `json:"signatures,omitempty"`
+	// Encryption is "smime" or "pgp" when (a part of) the body is encrypted
+	Encryption      string   `json:"encryption,omitempty"`
+	RecipientKeyIDs []string `json:"recipient_key_ids,omitempty"`
+	// Unanalysable is set when the encrypted content could not be analysed
+	Unanalysable bool `json:"unanalysable"`
+}
+
+type Signature struct {
+	Type string `json:"type"`
+	// Valid is set when the signature matches the content
+	Valid bool `json:"valid"`
+	// Trusted is set when the signer certificate chains to the trust store, or when the key is in the keyring
+	Trusted     bool         `json:"trusted"`
+	Error       string       `json:"error,omitempty"`
+	SigningTime *time.Time   `json:"signing_time,omitempty"`
+	Signer      *Certificate `json:"signer,omitempty"`
+	KeyID       string       `json:"key_id,omitempty"`
+	UserIDs     []string     `json:"user_ids,omitempty"`
+}
+
+// Report is the machine readable part of a multipart/report mail: a delivery status notification
+// (RFC 3464) or an abuse feedback report (RFC 5965).
+type Report struct {
+	ReportType     string          `json:"report_type"`
+	DeliveryStatus *DeliveryStatus `json:"delivery_status,omitempty"`
+	Feedback       *FeedbackReport `json:"feedback,omitempty"`
+	// headers of the original message, when the report includes them
+	OriginalMessageID string              `json:"original_message_id,omitempty"`
+	OriginalFrom      string              `json:"original_from,omitempty"`
+	OriginalTo        []string            `json:"original_to,omitempty"`
+	OriginalSubject   string              `json:"original_subject,omitempty"`
+	OriginalHeaders   map[string][]string `json:"original_headers,omitempty"`
+	// Truncated is set when a part of the report has a malformed line: the fields after it are
+	// missing
+	Truncated bool `json:"truncated,omitempty"`
+}
+
+type DeliveryStatus struct {
+	ReportingMTA       string         `json:"reporting_mta,omitempty"`
+	OriginalEnvelopeID string         `json:"original_envelope_id,omitempty"`
+	ReceivedFromMTA    string         `json:"received_from_mta,omitempty"`
+	ArrivalDate        string         `json:"arrival_date,omitempty"`
+	Recipients         []DSNRecipient `json:"recipients,omitempty"`
+}
+
+type DSNRecipient struct {
+	FinalRecipient    string `json:"final_recipient"`
+	OriginalRecipient string `json:"original_recipient,omitempty"`
+	// Action is failed, delayed, delivered, relayed or expanded
+	Action string `json:"action"`
+	Status string `json:"status"`
+	// StatusClass is "success", "transient" or "permanent", from the first digit of Status
+	StatusClass     string `json:"status_class,omitempty"`
+	DiagnosticCode  string `json:"diagnostic_code,omitempty"`
+	RemoteMTA       string `json:"remote_mta,omitempty"`
+	LastAttemptDate string `json:"last_attempt_date,omitempty"`
+	WillRetryUntil  string `json:"will_retry_until,omitempty"`
+}
+
+type FeedbackReport struct {
+	// FeedbackType is abuse, fraud, virus, not-spam, auth-failure or other
+	FeedbackType          string   `json:"feedback_type"`
+	UserAgent             string   `json:"user_agent,omitempty"`
+	SourceIP              string   `json:"source_ip,omitempty"`
+	OriginalMailFrom      string   `json:"original_mail_from,omitempty"`
+	OriginalRcptTo        []string `json:"original_rcpt_to,omitempty"`
+	ReportedDomain        []string `json:"reported_domain,omitempty"`
+	ReportedURI           []string `json:"reported_uri,omitempty"`
+	ArrivalDate           string   `json:"arrival_date,omitempty"`
+	AuthenticationResults []string `json:"authentication_results,omitempty"`
+	Incidents             int      `json:"incidents,omitempty"`
+}
+
+type Certificate struct {
+	Subject      string    `json:"subject"`
+	Issuer       string    `json:"issuer"`
+	SerialNumber string    `json:"serial_number"`
+	NotBefore    time.Time `json:"not_before"`
+	NotAfter     time.Time `json:"not_after"`
+	Emails       []string  `json:"emails,omitempty"`
+}
+
 type DKIMValidation struct {
 	Error         string             `json:"error,omitempty"`
 	Verifications []DKIMVerification `json:"verifications,omitempty"`
@@ -60,6 +500,37 @@ type ReceivedElement struct {
 	EnvelopeFrom string        `json:"envelope_from,omitempty"`
 	Helo         string        `json:"helo,omitempty"`
 	Raw          string        `json:"raw,omitempty"`
+	// MTA is the software that added the header, when it can be recognized
+	MTA string `json:"mta,omitempty"`
+	// Encrypted is set when the hop used TLS
+	Encrypted           bool   `json:"encrypted"`
+	Authenticated       bool   `json:"authenticated,omitempty"`
+	AuthenticatedSender string `json:"authenticated_sender,omitempty"`
+	// Delay is the transit time since the previous hop, in seconds
+	Delay *float64 `json:"delay_seconds,omitempty"`
+	// OutOfOrder is set when the timestamp is before the timestamp of the previous hop
+	OutOfOrder bool `json:"out_of_order,omitempty"`
+	// Trusted is set for the hops added by the internal MTAs, up to the first external hop
+	Trusted bool `json:"trusted"`
+	// ForgeryHints lists why the header may have been injected by the sender
+	ForgeryHints []string `json:"forgery_hints,omitempty"`
+}
+
+// ReceivedChain summarizes the Received headers of a mail.
+type ReceivedChain struct {
+	Hops int `json:"hops"`
+	// TotalTransit is the time between the oldest and the newest timestamps, in seconds
+	TotalTransit *float64 `json:"total_transit_seconds,omitempty"`
+	OutOfOrder   bool     `json:"out_of_order"`
+	// CleartextHops counts the hops that did not use TLS
+	CleartextHops int `json:"cleartext_hops"`
+	// FirstExternalHop is the index in Received of the first hop received from outside the
+	// internal networks, or -1
+	FirstExternalHop int `json:"first_external_hop"`
+	// SendingIP is the IP address that connected to the internal networks
+	SendingIP       string       `json:"sending_ip,omitempty"`
+	SendingLocation *GeoIPResult `json:"sending_location,omitempty"`
+	Forged          bool         `json:"forged"`
 }
 
 type ReceivedFrom struct {
@@ -81,10 +552,15 @@ type ReceivedBy struct {
 }
 
 type GeoIPResult struct {
-	Country     string  `json:"country,omitempty"`
-	Continent   string  `json:"continent,omitempty"`
-	City        string  `json:"city,omitempty"`
-	Coordinates *LatLon `json:"coordinates,omitempty"`
+	Country        string  `json:"country,omitempty"`
+	CountryCode    string  `json:"country_code,omitempty"`
+	Continent      string  `json:"continent,omitempty"`
+	City           string  `json:"city,omitempty"`
+	Coordinates    *LatLon `json:"coordinates,omitempty"`
+	ASN            uint    `json:"asn,omitempty"`
+	ASOrganization string  `json:"as_organization,omitempty"`
+	// Hosting is set when the ASN belongs to a hosting or cloud provider
+	Hosting bool `json:"hosting,omitempty"`
 }
 
 type LatLon struct {
@@ -106,6 +582,7 @@ type Attachment struct {
 	Name           string                 `json:"name,omitempty"`
 	InferredType   string                 `json:"inferred_type,omitempty"`
 	ReportedType   string                 `json:"reported_type,omitempty"`
+	ContentID      string                 `json:"content_id,omitempty"`
 	Size           int64                  `json:"size_bytes"`
 	Hash           string                 `json:"hash,omitempty"`
 	PDFMetadata    *PDFMeta               `json:"pdf_metadata,omitempty"`
@@ -113,11 +590,23 @@ type Attachment struct {
 	ExeMetadata    map[string]interface{} `json:"exe_metadata,omitempty"`
 	EventsMetadata []*Event               `json:"event_metadata,omitempty"`
 	// TODO: ImageMetadata should be more defined
-	ImageMetadata map[string]interface{} `json:"image_metadata,omitempty"`
-	Archives      map[string]*Archive    `json:"archive_content,omitempty"`
-	SubAttachment *Attachment            `json:"sub_attachment,omitempty"`
-	// TODO
+	ImageMetadata  map[string]interface{} `json:"image_metadata,omitempty"`
+	HTMLMetadata   *HTMLAnalysis          `json:"html_metadata,omitempty"`
+	LnkMetadata    *LnkMeta               `json:"lnk_metadata,omitempty"`
+	ScriptMetadata *ScriptMeta            `json:"script_metadata,omitempty"`
+	RTFMetadata    *RTFMeta               `json:"rtf_metadata,omitempty"`
+	QRCodes        []string               `json:"qr_codes,omitempty"`
+	ImageHashes    *ImageHashes           `json:"image_hashes,omitempty"`
+	LogoMatches    []LogoMatch            `json:"logo_matches,omitempty"`
+	Archives       map[string]*Archive    `json:"archive_content,omitempty"`
+	SubAttachment  *Attachment            `json:"sub_attachment,omitempty"`
+	// SubAttachments are the objects embedded in a document (OLE objects, files in a OneNote section...)
+	SubAttachments []*Attachment     `json:"sub_attachments,omitempty"`
+	Filename       *FilenameAnalysis `json:"filename_analysis,omitempty"`
+	// Executable is set for PE executables, and for other types that run code when opened (scripts, shortcuts...)
 	Executable bool `json:"is_executable"`
+	// Text is the text extracted from the documents, for the DLP detectors. It is not reported.
+	Text string `json:"-"`
 }
 
 type Address struct {
@@ -172,11 +661,69 @@ type DocMeta struct {
 	Properties map[string]interface{} `json:"properties,omitempty"`
 }
 
+// LnkMeta describes a Windows shortcut.
+type LnkMeta struct {
+	TargetPath   string `json:"target_path,omitempty"`
+	Arguments    string `json:"arguments,omitempty"`
+	WorkingDir   string `json:"working_dir,omitempty"`
+	RelativePath string `json:"relative_path,omitempty"`
+	Description  string `json:"description,omitempty"`
+	IconLocation string `json:"icon_location,omitempty"`
+	NetworkShare string `json:"network_share,omitempty"`
+	// MachineID is the NetBIOS name of the machine where the shortcut was created
+	MachineID string `json:"machine_id,omitempty"`
+	// Hidden is set when the target runs with a minimized window
+	Hidden bool `json:"hidden"`
+}
+
+// RTFMeta describes the objects embedded in a RTF document.
+type RTFMeta struct {
+	Objects       int      `json:"objects"`
+	Pictures      int      `json:"pictures"`
+	ObjectClasses []string `json:"object_classes,omitempty"`
+	// EquationEditor is set when an object targets the Equation Editor, which is exploited by CVE-2017-11882
+	EquationEditor bool `json:"equation_editor"`
+	// ObfuscatedControlWords counts the constructs that Word ignores but that confuse the analysis tools
+	ObfuscatedControlWords int  `json:"obfuscated_control_words"`
+	NonStandardHeader      bool `json:"non_standard_header"`
+}
+
+// ScriptMeta reports the obfuscation indicators found in a script.
+type ScriptMeta struct {
+	Language         string   `json:"language,omitempty"`
+	Entropy          float64  `json:"entropy"`
+	MaxStringEntropy float64  `json:"max_string_entropy"`
+	LongStrings      int      `json:"long_strings"`
+	DynamicExecution []string `json:"dynamic_execution,omitempty"`
+	ActiveXObjects   []string `json:"activex_objects,omitempty"`
+	Base64Blobs      int      `json:"base64_blobs"`
+	CharCodeArrays   int      `json:"char_code_arrays"`
+	// ScriptEncoded is set for scripts encoded by the Microsoft Script Encoder (.jse, .vbe)
+	ScriptEncoded   bool     `json:"script_encoded"`
+	EncodedCommands []string `json:"encoded_commands,omitempty"`
+	URLs            []string `json:"urls,omitempty"`
+	IPs             []string `json:"ips,omitempty"`
+}
+
 type ArchiveFile struct {
-	Name        string `json:"name,omitempty"`
-	Extension   string `json:"extension,omitempty"`
-	Type        string `json:"type,omitempty"`
-	Compression string `json:"compression,omitempty"`
+	Name        string            `json:"name,omitempty"`
+	Extension   string            `json:"extension,omitempty"`
+	Type        string            `json:"type,omitempty"`
+	Compression string            `json:"compression,omitempty"`
+	Filename    *FilenameAnalysis `json:"filename_analysis,omitempty"`
+}
+
+// FilenameAnalysis reports the tricks used to disguise the real type of a file.
+type FilenameAnalysis struct {
+	// DisplayedName is the name as a mail client would display it, when bidi control characters change it
+	DisplayedName        string `json:"displayed_name,omitempty"`
+	DoubleExtension      bool   `json:"double_extension"`
+	BidiControl          bool   `json:"bidi_control"`
+	WhitespacePadding    bool   `json:"whitespace_padding"`
+	ExtensionMismatch    bool   `json:"extension_mismatch"`
+	ReportedTypeMismatch bool   `json:"reported_type_mismatch"`
+	// DangerousType is the kind of the file when it can run code (exe, script, shortcut, disk image...)
+	DangerousType string `json:"dangerous_type,omitempty"`
 }
 
 type Archive struct {
@@ -186,3 +733,77 @@ type Archive struct {
 	SubArchives        map[string]*Archive `json:"sub_archives,omitempty"`
 	ContainsExecutable bool                `json:"contains_exe"`
 }
+
+type HTMLAnalysis struct {
+	DeceptiveLinks    []DeceptiveLink `json:"deceptive_links,omitempty"`
+	Forms             []HTMLForm      `json:"forms,omitempty"`
+	PasswordInputs    int             `json:"password_inputs"`
+	Scripts           int             `json:"scripts"`
+	ScriptSources     []string        `json:"script_sources,omitempty"`
+	EventHandlers     []string        `json:"event_handlers,omitempty"`
+	Iframes           []string        `json:"iframes,omitempty"`
+	Objects           []string        `json:"objects,omitempty"`
+	Embeds            []string        `json:"embeds,omitempty"`
+	MetaRefresh       string          `json:"meta_refresh,omitempty"`
+	DataURIImages     int             `json:"data_uri_images"`
+	HiddenText        []string        `json:"hidden_text,omitempty"`
+	RemoteStylesheets []string        `json:"remote_stylesheets,omitempty"`
+	RemoteFonts       []string        `json:"remote_fonts,omitempty"`
+}
+
+type DeceptiveLink struct {
+	Text       string `json:"text,omitempty"`
+	Href       string `json:"href,omitempty"`
+	TextDomain string `json:"text_domain,omitempty"`
+	HrefDomain string `json:"href_domain,omitempty"`
+}
+
+type HTMLForm struct {
+	Action         string `json:"action,omitempty"`
+	Method         string `json:"method,omitempty"`
+	ActionDomain   string `json:"action_domain,omitempty"`
+	PasswordInputs int    `json:"password_inputs"`
+}
+
+type RemoteContent struct {
+	TracksOpens     bool             `json:"tracks_opens"`
+	TrackingPixels  []TrackingPixel  `json:"tracking_pixels,omitempty"`
+	ImageHosts      map[string]int   `json:"image_hosts,omitempty"`
+	RecipientTokens []RecipientToken `json:"recipient_tokens,omitempty"`
+	Stylesheets     []string         `json:"stylesheets,omitempty"`
+	Fonts           []string         `json:"fonts,omitempty"`
+	InlineImages    []InlineImage    `json:"inline_images,omitempty"`
+}
+
+type TrackingPixel struct {
+	URL    string `json:"url,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Reason string `json:"reason,omitempty"`
+}
+
+type RecipientToken struct {
+	URL       string `json:"url,omitempty"`
+	Parameter string `json:"parameter,omitempty"`
+	Kind      string `json:"kind,omitempty"`
+}
+
+type InlineImage struct {
+	ContentID  string `json:"content_id,omitempty"`
+	Found      bool   `json:"found"`
+	Attachment string `json:"attachment,omitempty"`
+	Type       string `json:"type,omitempty"`
+}
+
+// ImageHashes are the perceptual hashes of an image, in hexadecimal.
+type ImageHashes struct {
+	AHash string `json:"ahash"`
+	DHash string `json:"dhash"`
+	PHash string `json:"phash"`
+}
+
+// LogoMatch is a brand logo recognized in an image.
+type LogoMatch struct {
+	Brand     string `json:"brand"`
+	Reference string `json:"reference,omitempty"`
+	Distance  int    `json:"distance"`
+}
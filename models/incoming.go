@@ -10,6 +10,7 @@ type BaseInfos struct {
 	Port         int       `json:"port"`
 	Addr         string    `json:"addr,omitempty"`
 	Helo         string    `json:"helo,omitempty"`
+	AuthUser     string    `json:"auth_user,omitempty"`
 	TimeReported time.Time `json:"-" yaml:"-"`
 	UID          [16]byte  `json:"-" yaml:"-"`
 }
@@ -79,6 +79,12 @@ func (z *BaseInfos) DecodeMsg(dc *msgp.Reader) (err error) {
 				err = msgp.WrapError(err, "Helo")
 				return
 			}
+		case "AuthUser":
+			z.AuthUser, err = dc.ReadString()
+			if err != nil {
+				err = msgp.WrapError(err, "AuthUser")
+				return
+			}
 		case "TimeReported":
 			z.TimeReported, err = dc.ReadTime()
 			if err != nil {
@@ -104,9 +110,9 @@ func (z *BaseInfos) DecodeMsg(dc *msgp.Reader) (err error) {
 
 // EncodeMsg implements msgp.Encodable
 func (z *BaseInfos) EncodeMsg(en *msgp.Writer) (err error) {
-	// map header, size 9
+	// map header, size 10
 	// write "MailFrom"
-	err = en.Append(0x89, 0xa8, 0x4d, 0x61, 0x69, 0x6c, 0x46, 0x72, 0x6f, 0x6d)
+	err = en.Append(0x8a, 0xa8, 0x4d, 0x61, 0x69, 0x6c, 0x46, 0x72, 0x6f, 0x6d)
 	if err != nil {
 		return
 	}
@@ -182,6 +188,16 @@ func (z *BaseInfos) EncodeMsg(en *msgp.Writer) (err error) {
 		err = msgp.WrapError(err, "Helo")
 		return
 	}
+	// write "AuthUser"
+	err = en.Append(0xa8, 0x41, 0x75, 0x74, 0x68, 0x55, 0x73, 0x65, 0x72)
+	if err != nil {
+		return
+	}
+	err = en.WriteString(z.AuthUser)
+	if err != nil {
+		err = msgp.WrapError(err, "AuthUser")
+		return
+	}
 	// write "TimeReported"
 	err = en.Append(0xac, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64)
 	if err != nil {
@@ -208,9 +224,9 @@ func (z *BaseInfos) EncodeMsg(en *msgp.Writer) (err error) {
 // MarshalMsg implements msgp.Marshaler
 func (z *BaseInfos) MarshalMsg(b []byte) (o []byte, err error) {
 	o = msgp.Require(b, z.Msgsize())
-	// map header, size 9
+	// map header, size 10
 	// string "MailFrom"
-	o = append(o, 0x89, 0xa8, 0x4d, 0x61, 0x69, 0x6c, 0x46, 0x72, 0x6f, 0x6d)
+	o = append(o, 0x8a, 0xa8, 0x4d, 0x61, 0x69, 0x6c, 0x46, 0x72, 0x6f, 0x6d)
 	o = msgp.AppendString(o, z.MailFrom)
 	// string "RcptTo"
 	o = append(o, 0xa6, 0x52, 0x63, 0x70, 0x74, 0x54, 0x6f)
@@ -233,6 +249,9 @@ func (z *BaseInfos) MarshalMsg(b []byte) (o []byte, err error) {
 	// string "Helo"
 	o = append(o, 0xa4, 0x48, 0x65, 0x6c, 0x6f)
 	o = msgp.AppendString(o, z.Helo)
+	// string "AuthUser"
+	o = append(o, 0xa8, 0x41, 0x75, 0x74, 0x68, 0x55, 0x73, 0x65, 0x72)
+	o = msgp.AppendString(o, z.AuthUser)
 	// string "TimeReported"
 	o = append(o, 0xac, 0x54, 0x69, 0x6d, 0x65, 0x52, 0x65, 0x70, 0x6f, 0x72, 0x74, 0x65, 0x64)
 	o = msgp.AppendTime(o, z.TimeReported)
@@ -315,6 +334,12 @@ func (z *BaseInfos) UnmarshalMsg(bts []byte) (o []byte, err error) {
 				err = msgp.WrapError(err, "Helo")
 				return
 			}
+		case "AuthUser":
+			z.AuthUser, bts, err = msgp.ReadStringBytes(bts)
+			if err != nil {
+				err = msgp.WrapError(err, "AuthUser")
+				return
+			}
 		case "TimeReported":
 			z.TimeReported, bts, err = msgp.ReadTimeBytes(bts)
 			if err != nil {
@@ -345,7 +370,7 @@ func (z *BaseInfos) Msgsize() (s int) {
 	for za0001 := range z.RcptTo {
 		s += msgp.StringPrefixSize + len(z.RcptTo[za0001])
 	}
-	s += 5 + msgp.StringPrefixSize + len(z.Host) + 7 + msgp.StringPrefixSize + len(z.Family) + 5 + msgp.IntSize + 5 + msgp.StringPrefixSize + len(z.Addr) + 5 + msgp.StringPrefixSize + len(z.Helo) + 13 + msgp.TimeSize + 4 + msgp.ArrayHeaderSize + (16 * (msgp.ByteSize))
+	s += 5 + msgp.StringPrefixSize + len(z.Host) + 7 + msgp.StringPrefixSize + len(z.Family) + 5 + msgp.IntSize + 5 + msgp.StringPrefixSize + len(z.Addr) + 5 + msgp.StringPrefixSize + len(z.Helo) + 9 + msgp.StringPrefixSize + len(z.AuthUser) + 13 + msgp.TimeSize + 4 + msgp.ArrayHeaderSize + (16 * (msgp.ByteSize))
 	return
 }
 
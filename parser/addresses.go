@@ -0,0 +1,193 @@
+package parser
+
+import (
+	"net/mail"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+)
+
+// parseAddressValues parses the raw values of an address header. It tolerates the broken values:
+// when a value can not be parsed as a list, each comma separated item is parsed on its own, and
+// the address is extracted from the items that still can not be parsed. The returned error is
+// the first parsing error, if any.
+func parseAddressValues(values []string) (addrs []models.Address, err error) {
+	for _, value := range values {
+		value = strings.TrimSpace(value)
+		if value == "" || value == "<>" {
+			continue
+		}
+		list, e := ParseAddressList(value)
+		if e == nil {
+			for _, a := range list {
+				addrs = append(addrs, models.Address{Name: a.Name, Address: a.Address})
+			}
+			continue
+		}
+		if err == nil {
+			err = e
+		}
+		for _, item := range splitAddressList(value) {
+			if a, ok := parseBrokenAddress(item); ok {
+				addrs = append(addrs, a)
+			}
+		}
+	}
+	return addrs, err
+}
+
+// parseBrokenAddress parses an address that net/mail rejects, for example because of an unknown
+// charset or of unquoted special characters in the display name.
+func parseBrokenAddress(item string) (models.Address, bool) {
+	item = strings.TrimSpace(item)
+	if item == "" {
+		return models.Address{}, false
+	}
+	if a, err := ParseAddress(item); err == nil {
+		return models.Address{Name: a.Name, Address: a.Address}, true
+	}
+	if a, err := mail.ParseAddress(item); err == nil {
+		return models.Address{Name: a.Name, Address: a.Address}, true
+	}
+	var address, name string
+	if open := strings.LastIndex(item, "<"); open >= 0 && strings.HasSuffix(item, ">") {
+		address = strings.TrimSpace(item[open+1 : len(item)-1])
+		name = strings.TrimSpace(item[:open])
+	} else if found := findEmailAddresses(item); len(found) > 0 {
+		address = found[0]
+		name = strings.TrimSpace(strings.Replace(item, address, "", 1))
+		name = strings.TrimSpace(strings.Trim(name, "()<>"))
+	}
+	if !strings.Contains(address, "@") {
+		return models.Address{}, false
+	}
+	name = strings.Trim(name, `"' `)
+	if decoded, err := StringDecode(name); err == nil {
+		name = decoded
+	}
+	return models.Address{Name: name, Address: address}, true
+}
+
+// splitAddressList splits an address list on the commas that are not quoted, commented, or
+// inside angle brackets.
+func splitAddressList(value string) []string {
+	var items []string
+	var quoted, escaped bool
+	var depth, angle int
+	start := 0
+	for i, c := range value {
+		switch {
+		case escaped:
+			escaped = false
+		case c == '\\':
+			escaped = true
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case c == '<':
+			angle++
+		case c == '>' && angle > 0:
+			angle--
+		case c == ',' && depth == 0 && angle == 0:
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, value[start:])
+}
+
+// addressHeaders are the address headers that are structured in the features, besides From.
+var addressHeaders = []string{"To", "Cc", "Bcc", "Reply-To", "Sender", "Return-Path", "Delivered-To"}
+
+// parseAddressHeaders structures the address headers. The raw values are used, so that the
+// encoded words are decoded after the addresses have been split.
+func (p *impl) parseAddressHeaders(features *models.FeaturesMail, header mail.Header) {
+	for _, name := range addressHeaders {
+		values := header[name]
+		if len(values) == 0 {
+			continue
+		}
+		addrs, err := parseAddressValues(values)
+		key := strings.ToLower(name)
+		if err != nil {
+			p.logger.Info("Error parsing address header", "header", name, "error", err)
+			if features.AddressErrors == nil {
+				features.AddressErrors = make(map[string]string)
+			}
+			features.AddressErrors[key] = err.Error()
+		}
+		switch name {
+		case "To":
+			features.To = addrs
+		case "Cc":
+			features.Cc = addrs
+		case "Bcc":
+			features.Bcc = addrs
+		case "Reply-To":
+			features.ReplyTo = addrs
+		case "Sender":
+			if len(addrs) > 0 {
+				features.Sender = &addrs[0]
+			}
+		case "Return-Path":
+			if len(addrs) > 0 {
+				features.ReturnPath = &addrs[0]
+			}
+		case "Delivered-To":
+			features.DeliveredTo = addrs
+		}
+		// the Return-Path is kept in the headers for the categorization, as it may be null (<>)
+		if err == nil && name != "Return-Path" {
+			delete(features.Headers, key)
+		}
+	}
+	features.AddressRelations = addressRelations(features)
+}
+
+// addressRelations relates the envelope to the address headers.
+func addressRelations(features *models.FeaturesMail) *models.AddressRelations {
+	rel := new(models.AddressRelations)
+	visible := make(map[string]bool)
+	recipients := make(map[string]bool)
+	for _, list := range [][]models.Address{features.To, features.Cc} {
+		for _, a := range list {
+			addr := strings.ToLower(a.Address)
+			visible[addr] = true
+			recipients[addr] = true
+		}
+	}
+	for _, a := range features.Bcc {
+		recipients[strings.ToLower(a.Address)] = true
+	}
+	for _, rcpt := range features.RcptTo {
+		addr := strings.ToLower(strings.Trim(strings.TrimSpace(rcpt), "<>"))
+		if addr == "" {
+			continue
+		}
+		recipients[addr] = true
+		if !visible[addr] {
+			rel.HiddenRecipients = append(rel.HiddenRecipients, addr)
+		}
+	}
+	rel.RecipientCount = len(recipients)
+
+	mailFrom := strings.ToLower(strings.Trim(strings.TrimSpace(features.MailFrom), "<>"))
+	if features.ReturnPath != nil && mailFrom != "" {
+		rel.ReturnPathDiffers = strings.ToLower(features.ReturnPath.Address) != mailFrom
+	}
+	if features.From != nil {
+		fromDomain := utils.DomainFromAddress(features.From.Address.Address)
+		for _, r := range features.ReplyTo {
+			d := utils.DomainFromAddress(r.Address)
+			if fromDomain != "" && d != "" && !utils.SameDomain(d, fromDomain) {
+				rel.ReplyToDomainMismatch = true
+			}
+		}
+	}
+	return rel
+}
@@ -0,0 +1,201 @@
+package parser
+
+import (
+	"net/mail"
+	"reflect"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestParseAddressValues(t *testing.T) {
+	cases := []struct {
+		values []string
+		addrs  []models.Address
+		broken bool
+	}{
+		{nil, nil, false},
+		{[]string{"", "  ", "<>"}, nil, false},
+		{[]string{"alice@example.org"}, []models.Address{{Address: "alice@example.org"}}, false},
+		{[]string{`"Alice Liddell" <alice@example.org>, bob@example.com`},
+			[]models.Address{{Name: "Alice Liddell", Address: "alice@example.org"}, {Address: "bob@example.com"}}, false},
+		{[]string{"alice@example.org", "Bob <bob@example.com>"},
+			[]models.Address{{Address: "alice@example.org"}, {Name: "Bob", Address: "bob@example.com"}}, false},
+		// the encoded words are decoded after the split
+		{[]string{"=?utf-8?q?Dupont=2C_Andr=C3=A9?= <andre@example.fr>, bob@example.com"},
+			[]models.Address{{Name: "Dupont, André", Address: "andre@example.fr"}, {Address: "bob@example.com"}}, false},
+		{[]string{"=?iso-8859-1?q?Ren=E9?= <rene@example.fr>"},
+			[]models.Address{{Name: "René", Address: "rene@example.fr"}}, false},
+		{[]string{"undisclosed-recipients:;"}, nil, false},
+		// unquoted special characters in the display name
+		{[]string{"Dupont, Jean <jean@example.fr>"},
+			[]models.Address{{Name: "Jean", Address: "jean@example.fr"}}, true},
+		{[]string{"John [IT] Doe <john@example.com>, alice@example.org"},
+			[]models.Address{{Name: "John [IT] Doe", Address: "john@example.com"}, {Address: "alice@example.org"}}, true},
+		// the unknown charsets
+		{[]string{"=?x-unknown?q?Jean?= <jean@example.fr>"},
+			[]models.Address{{Name: "=?x-unknown?q?Jean?=", Address: "jean@example.fr"}}, true},
+		// the address is extracted from the garbage
+		{[]string{"Jean Dupont jean@example.fr"},
+			[]models.Address{{Name: "Jean Dupont", Address: "jean@example.fr"}}, true},
+		{[]string{"<jean@example.fr"}, []models.Address{{Address: "jean@example.fr"}}, true},
+		{[]string{"Jean Dupont"}, nil, true},
+		{[]string{"Jean <Dupont>"}, nil, true},
+	}
+	for _, c := range cases {
+		addrs, err := parseAddressValues(c.values)
+		if !reflect.DeepEqual(addrs, c.addrs) {
+			t.Errorf("%q: expected %+v, got %+v", c.values, c.addrs, addrs)
+		}
+		if (err != nil) != c.broken {
+			t.Errorf("%q: unexpected error %v", c.values, err)
+		}
+	}
+}
+
+func TestSplitAddressList(t *testing.T) {
+	cases := []struct {
+		value string
+		items []string
+	}{
+		{"", []string{""}},
+		{"a@x, b@y", []string{"a@x", " b@y"}},
+		{`"Dupont, Jean" <j@x>, b@y`, []string{`"Dupont, Jean" <j@x>`, " b@y"}},
+		{`"Dupont \", Jean" <j@x>,b@y`, []string{`"Dupont \", Jean" <j@x>`, "b@y"}},
+		{"j@x (Dupont, Jean), b@y", []string{"j@x (Dupont, Jean)", " b@y"}},
+		{"Jean <j,@x>, b@y", []string{"Jean <j,@x>", " b@y"}},
+		{"a@x,,b@y", []string{"a@x", "", "b@y"}},
+		// the unbalanced closing characters are ignored
+		{"a@x), b@y>, c@z", []string{"a@x)", " b@y>", " c@z"}},
+	}
+	for _, c := range cases {
+		if items := splitAddressList(c.value); !reflect.DeepEqual(items, c.items) {
+			t.Errorf("%q: expected %q, got %q", c.value, c.items, items)
+		}
+	}
+}
+
+func TestParseAddressHeaders(t *testing.T) {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	p := &impl{logger: logger}
+	header := mail.Header{
+		"To":           {"alice@example.org, Dupont, Jean <jean@example.fr>"},
+		"Cc":           {"Bob <bob@example.com>"},
+		"Reply-To":     {"=?utf-8?q?Support?= <support@example.net>"},
+		"Sender":       {"list-bounces@example.org"},
+		"Return-Path":  {"<>"},
+		"Delivered-To": {"alice@example.org", "carol@example.org"},
+	}
+	features := &models.FeaturesMail{Headers: map[string][]string{
+		"to": header["To"], "cc": header["Cc"], "reply-to": header["Reply-To"], "sender": header["Sender"],
+		"return-path": header["Return-Path"], "delivered-to": header["Delivered-To"],
+	}}
+	p.parseAddressHeaders(features, header)
+
+	expectedTo := []models.Address{{Address: "alice@example.org"}, {Name: "Jean", Address: "jean@example.fr"}}
+	if !reflect.DeepEqual(features.To, expectedTo) {
+		t.Errorf("To: expected %+v, got %+v", expectedTo, features.To)
+	}
+	if len(features.Cc) != 1 || features.Cc[0] != (models.Address{Name: "Bob", Address: "bob@example.com"}) {
+		t.Errorf("Cc: got %+v", features.Cc)
+	}
+	if len(features.ReplyTo) != 1 || features.ReplyTo[0].Name != "Support" {
+		t.Errorf("Reply-To: got %+v", features.ReplyTo)
+	}
+	if features.Sender == nil || features.Sender.Address != "list-bounces@example.org" {
+		t.Errorf("Sender: got %+v", features.Sender)
+	}
+	if features.ReturnPath != nil {
+		t.Errorf("Return-Path: expected the null path, got %+v", features.ReturnPath)
+	}
+	if len(features.DeliveredTo) != 2 {
+		t.Errorf("Delivered-To: got %+v", features.DeliveredTo)
+	}
+	// the broken headers and the Return-Path are kept
+	for _, key := range []string{"to", "return-path"} {
+		if _, ok := features.Headers[key]; !ok {
+			t.Errorf("the %s header has been removed", key)
+		}
+	}
+	for _, key := range []string{"cc", "reply-to", "sender", "delivered-to"} {
+		if _, ok := features.Headers[key]; ok {
+			t.Errorf("the %s header has been kept", key)
+		}
+	}
+	if len(features.AddressErrors) != 1 || features.AddressErrors["to"] == "" {
+		t.Errorf("unexpected address errors %v", features.AddressErrors)
+	}
+	if features.AddressRelations == nil || features.AddressRelations.RecipientCount != 3 {
+		t.Errorf("unexpected relations %+v", features.AddressRelations)
+	}
+}
+
+func TestAddressRelations(t *testing.T) {
+	from := &models.FromAddress{Address: models.Address{Address: "ceo@example.com"}}
+	cases := []struct {
+		name     string
+		features models.FeaturesMail
+		rel      models.AddressRelations
+	}{
+		{"empty", models.FeaturesMail{}, models.AddressRelations{}},
+		{
+			"bcc",
+			models.FeaturesMail{
+				To:        []models.Address{{Address: "Alice@Example.org"}},
+				Bcc:       []models.Address{{Address: "bob@example.com"}},
+				BaseInfos: models.BaseInfos{RcptTo: []string{"<alice@example.org>", "<bob@example.com>", " "}},
+			},
+			models.AddressRelations{RecipientCount: 2, HiddenRecipients: []string{"bob@example.com"}},
+		},
+		{
+			"cc",
+			models.FeaturesMail{
+				To:        []models.Address{{Address: "alice@example.org"}},
+				Cc:        []models.Address{{Address: "carol@example.org"}, {Address: "alice@example.org"}},
+				BaseInfos: models.BaseInfos{RcptTo: []string{"carol@example.org"}},
+			},
+			models.AddressRelations{RecipientCount: 2},
+		},
+		{
+			"return path",
+			models.FeaturesMail{
+				BaseInfos:  models.BaseInfos{MailFrom: "<bounces@mailer.example.net>"},
+				ReturnPath: &models.Address{Address: "bounces@mailer.example.net"},
+			},
+			models.AddressRelations{},
+		},
+		{
+			"return path differs",
+			models.FeaturesMail{
+				BaseInfos:  models.BaseInfos{MailFrom: "<bounces@mailer.example.net>"},
+				ReturnPath: &models.Address{Address: "other@example.net"},
+			},
+			models.AddressRelations{ReturnPathDiffers: true},
+		},
+		{
+			"null sender",
+			models.FeaturesMail{BaseInfos: models.BaseInfos{MailFrom: "<>"}, ReturnPath: &models.Address{Address: "other@example.net"}},
+			models.AddressRelations{},
+		},
+		{
+			"reply-to subdomain",
+			models.FeaturesMail{From: from, ReplyTo: []models.Address{{Address: "assistant@mail.example.com"}}},
+			models.AddressRelations{},
+		},
+		{
+			"reply-to mismatch",
+			models.FeaturesMail{
+				From:    from,
+				ReplyTo: []models.Address{{Address: "ceo@example.com"}, {Address: "ceo.example@freemail.test"}},
+			},
+			models.AddressRelations{ReplyToDomainMismatch: true},
+		},
+	}
+	for _, c := range cases {
+		if rel := addressRelations(&c.features); !reflect.DeepEqual(*rel, c.rel) {
+			t.Errorf("%s: expected %+v, got %+v", c.name, c.rel, *rel)
+		}
+	}
+}
@@ -194,7 +194,8 @@ func analyzeEntry(filename string, reader io.Reader, logger log15.Logger) (entry
 		return entry, exe, subArchive
 	}
 	entry.Type = t.MIME.Value
-	if extractors.IsExecutable(entry.Type) {
+	entry.Filename = extractors.AnalyseFilename(filename, entry.Type, "")
+	if extractors.IsExecutable(entry.Type) || extractors.IsDangerous(entry.Filename) {
 		exe = true
 	}
 	t, newReader, entry.Compression, err = replaceCompressed(t, newReader, logger)
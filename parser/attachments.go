@@ -16,6 +16,7 @@ import (
 	"github.com/xi2/xz"
 	"io"
 	"io/ioutil"
+	"path/filepath"
 	"strings"
 )
 
@@ -38,10 +39,11 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 		return nil, err
 	}
 	attachment.InferredType = typ.MIME.Value
-	attachment.Executable = extractors.IsExecutable(attachment.InferredType)
+	attachment.Filename = extractors.AnalyseFilename(filename, attachment.InferredType, ct)
+	attachment.Executable = extractors.IsExecutable(attachment.InferredType) || extractors.IsDangerous(attachment.Filename)
 	l.Debug("Attachment", "value", typ.MIME.Value, "filename", filename)
 
-	if attachment.Executable {
+	if t != nil && extractors.IsExecutable(attachment.InferredType) {
 		meta, err := t.Extract(content, nil, "-EXE:All")
 		if err != nil {
 			l.Warn("Failed to extract metadata with 'exiftool'", "error", err)
@@ -51,12 +53,53 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 		return attachment, nil
 	}
 
+	if lang := extractors.ScriptLanguage(filename, attachment.InferredType); lang != "" {
+		attachment.ScriptMetadata = extractors.AnalyseScript(string(content), lang)
+	}
+
 	switch typ {
+	case utils.LnkType:
+		meta, err := extractors.ParseLnk(content)
+		if err != nil {
+			l.Warn("Error parsing Windows shortcut", "error", err)
+		}
+		if meta != nil {
+			attachment.LnkMetadata = meta
+			if meta.Arguments != "" {
+				attachment.ScriptMetadata = extractors.AnalyseScript(meta.TargetPath+" "+meta.Arguments, extractors.LnkLanguage(meta.TargetPath))
+			}
+		}
+	case matchers.TypeRtf:
+		meta, objects := extractors.ParseRTF(content)
+		attachment.RTFMetadata = meta
+		analyseEmbedded(objects, attachment, t, l)
+	case utils.OneNoteType:
+		analyseEmbedded(extractors.OneNoteFiles(content), attachment, t, l)
+	case utils.ChmType:
+		names, err := extractors.CHMFiles(content)
+		if err != nil {
+			l.Warn("Error listing CHM content", "error", err)
+		} else {
+			archive := &models.Archive{ArchiveType: "chm"}
+			for _, name := range names {
+				entry := &models.ArchiveFile{
+					Name:      name,
+					Extension: strings.Trim(filepath.Ext(name), "."),
+					Filename:  extractors.AnalyseFilename(name, "", ""),
+				}
+				if extractors.IsDangerous(entry.Filename) {
+					archive.ContainsExecutable = true
+				}
+				archive.Files = append(archive.Files, entry)
+			}
+			attachment.Archives = map[string]*models.Archive{filename: archive}
+		}
 	case matchers.TypePdf:
 		text, err := extractors.PDFBytesToText(content)
 		if err != nil {
 			l.Warn("Error extracting text from PDF", "error", err)
 		} else if len(text) > 0 {
+			attachment.Text = text
 			lang := extractors.Language(text)
 			if lang != "" {
 				attachment.PDFMetadata = new(models.PDFMeta)
@@ -68,6 +111,7 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 		if err != nil {
 			l.Warn("Error extracting metadata from PDF", "error", err)
 		}
+		attachment.QRCodes = extractors.QRCodes(extractors.PDFImages(content)...)
 	case matchers.TypeDocx:
 		text, props, hasMacro, err := extractors.ConvertBytesDocx(content)
 		if err != nil {
@@ -77,6 +121,7 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 				Properties: props,
 				HasMacro:   hasMacro,
 			}
+			attachment.Text = text
 			if len(text) > 0 {
 				lang := extractors.Language(text)
 				if lang != "" {
@@ -92,6 +137,7 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 		} else {
 			attachment.DocMetadata = new(models.DocMeta)
 			attachment.DocMetadata.Properties = props
+			attachment.Text = text
 			if len(text) > 0 {
 				lang := extractors.Language(text)
 				if lang != "" {
@@ -106,6 +152,7 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 		if err != nil {
 			l.Warn("Error extracting text from DOC", "error", err)
 		} else {
+			attachment.Text = text
 			if len(text) > 0 {
 				lang := extractors.Language(text)
 				if lang != "" {
@@ -114,18 +161,21 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 					attachment.DocMetadata.Keywords, attachment.DocMetadata.Phrases = extractors.Keywords(text, nil, attachment.DocMetadata.Language)
 				}
 			}
-			p, err := t.Extract(content, nil, "-FlashPix:All")
-			if err != nil {
-				l.Warn("Error extracting metadata from DOC", "error", err)
-			} else {
-				if attachment.DocMetadata == nil {
-					attachment.DocMetadata = new(models.DocMeta)
+			if t != nil {
+				p, err := t.Extract(content, nil, "-FlashPix:All")
+				if err != nil {
+					l.Warn("Error extracting metadata from DOC", "error", err)
+				} else {
+					if attachment.DocMetadata == nil {
+						attachment.DocMetadata = new(models.DocMeta)
+					}
+					attachment.DocMetadata.Properties = p
 				}
-				attachment.DocMetadata.Properties = p
 			}
 		}
 
 	case utils.HTMLType:
+		attachment.HTMLMetadata = extractors.AnalyseHTML(string(content), nil)
 		text, _, _ := extractors.HTML2Text(string(content))
 		if len(text) > 0 {
 			lang := extractors.Language(text)
@@ -157,8 +207,9 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 				attachment.ImageMetadata = meta
 			}
 		}
+		analyseImage(content, attachment, l)
 
-	case matchers.TypeJpeg, matchers.TypeWebp, matchers.TypeGif:
+	case matchers.TypeJpeg, matchers.TypeWebp, matchers.TypeGif, matchers.TypeBmp:
 		if t != nil {
 			meta, err := t.Extract(content, nil, "-EXIF:All")
 			if err != nil {
@@ -167,6 +218,9 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 				attachment.ImageMetadata = meta
 			}
 		}
+		if typ != matchers.TypeWebp {
+			analyseImage(content, attachment, l)
+		}
 	case matchers.TypeZip, matchers.TypeTar, matchers.TypeRar:
 		archive, err := AnalyzeArchive(typ, bytes.NewReader(content), attachment.Size, l)
 		if err != nil {
@@ -191,7 +245,7 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 				l.Warn("Error analyzing subattachement", "error", err)
 			} else {
 				attachment.SubAttachment = subAttachment
-				attachment.Executable = subAttachment.Executable
+				attachment.Executable = attachment.Executable || subAttachment.Executable
 			}
 		}
 
@@ -205,7 +259,7 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 			l.Warn("Error analyzing sub-attachement", "error", err)
 		} else {
 			attachment.SubAttachment = subAttachment
-			attachment.Executable = subAttachment.Executable
+			attachment.Executable = attachment.Executable || subAttachment.Executable
 		}
 
 	case matchers.TypeXz:
@@ -221,7 +275,7 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 				l.Warn("Error analyzing sub-attachment", "error", err)
 			} else {
 				attachment.SubAttachment = subAttachment
-				attachment.Executable = subAttachment.Executable
+				attachment.Executable = attachment.Executable || subAttachment.Executable
 			}
 		}
 
@@ -241,3 +295,27 @@ func AnalyseAttachment(filename string, ct string, r io.Reader, t extractors.Exi
 
 	return attachment, nil
 }
+
+// analyseEmbedded runs the attachment analysis on the objects embedded in a document.
+func analyseEmbedded(objects []extractors.EmbeddedObject, attachment *models.Attachment, t extractors.ExifTool, l log15.Logger) {
+	for _, obj := range objects {
+		sub, err := AnalyseAttachment(obj.Name, obj.ContentType, bytes.NewReader(obj.Content), t, l)
+		if err != nil {
+			l.Warn("Error analyzing embedded object", "error", err)
+			continue
+		}
+		attachment.SubAttachments = append(attachment.SubAttachments, sub)
+		attachment.Executable = attachment.Executable || sub.Executable
+	}
+}
+
+// analyseImage decodes the barcodes found in an image attachment, and computes its perceptual hashes.
+func analyseImage(content []byte, attachment *models.Attachment, l log15.Logger) {
+	img, err := extractors.DecodeImage(content)
+	if err != nil {
+		l.Debug("Failed to decode image", "error", err)
+		return
+	}
+	attachment.QRCodes = extractors.QRCodes(img)
+	attachment.ImageHashes = extractors.ImageHashes(img)
+}
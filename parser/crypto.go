@@ -0,0 +1,169 @@
+package parser
+
+import (
+	"bytes"
+	"encoding/base64"
+	"io"
+	"io/ioutil"
+	"mime"
+	"mime/quotedprintable"
+	"net/mail"
+	"strings"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/extractors"
+	"github.com/stephane-martin/mailstats/mailcrypto"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func isSMIMEType(contentType string) bool {
+	return contentType == "application/pkcs7-mime" || contentType == "application/x-pkcs7-mime"
+}
+
+// decodeTransfer decodes the content transfer encoding of a binary body.
+func decodeTransfer(r io.Reader, transferEncoding string) ([]byte, error) {
+	switch strings.ToLower(strings.TrimSpace(transferEncoding)) {
+	case "base64":
+		r = base64.NewDecoder(base64.StdEncoding, r)
+	case "quoted-printable":
+		r = quotedprintable.NewReader(r)
+	}
+	return ioutil.ReadAll(r)
+}
+
+// splitMultipart returns the raw content (headers and body) of the parts of a multipart body.
+// The parts of a multipart/signed body must be kept byte for byte to verify the signature.
+func splitMultipart(body []byte, boundary string) [][]byte {
+	delimiter := []byte("--" + boundary)
+	var parts [][]byte
+	start := -1
+	pos := 0
+	for pos < len(body) {
+		idx := bytes.Index(body[pos:], delimiter)
+		if idx < 0 {
+			break
+		}
+		idx += pos
+		if idx > 0 && body[idx-1] != '\n' {
+			pos = idx + len(delimiter)
+			continue
+		}
+		if start >= 0 {
+			// the line ending before the delimiter belongs to the delimiter
+			end := idx
+			if end > start && body[end-1] == '\n' {
+				end--
+			}
+			if end > start && body[end-1] == '\r' {
+				end--
+			}
+			parts = append(parts, body[start:end])
+		}
+		after := idx + len(delimiter)
+		if bytes.HasPrefix(body[after:], []byte("--")) {
+			break
+		}
+		nl := bytes.IndexByte(body[after:], '\n')
+		if nl < 0 {
+			break
+		}
+		start = after + nl + 1
+		pos = start
+	}
+	return parts
+}
+
+// parseSigned verifies a multipart/signed body, and returns the signed entity.
+func parseSigned(body io.Reader, params map[string]string, verifier mailcrypto.Verifier, crypto *models.Crypto, logger log15.Logger) []byte {
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		logger.Info("Error reading signed body", "error", err)
+		return nil
+	}
+	parts := splitMultipart(content, strings.TrimSpace(params["boundary"]))
+	if len(parts) == 0 {
+		return nil
+	}
+	if len(parts) < 2 {
+		return parts[0]
+	}
+	sigPart, err := mail.ReadMessage(bytes.NewReader(parts[1]))
+	if err != nil {
+		logger.Info("Error reading signature part", "error", err)
+		return parts[0]
+	}
+	signature, err := decodeTransfer(sigPart.Body, sigPart.Header.Get("Content-Transfer-Encoding"))
+	if err != nil {
+		logger.Info("Error decoding signature", "error", err)
+		return parts[0]
+	}
+	sigType, _, _ := mime.ParseMediaType(sigPart.Header.Get("Content-Type"))
+	protocol := strings.ToLower(strings.TrimSpace(params["protocol"]))
+	switch {
+	case protocol == "application/pgp-signature" || sigType == "application/pgp-signature":
+		crypto.Signatures = append(crypto.Signatures, verifier.VerifyPGP(signature, parts[0]))
+	case strings.HasSuffix(protocol, "pkcs7-signature") || strings.HasSuffix(sigType, "pkcs7-signature"):
+		crypto.Signatures = append(crypto.Signatures, verifier.VerifySMIME(signature, parts[0]))
+	default:
+		logger.Info("Unknown signature protocol", "protocol", protocol)
+	}
+	return parts[0]
+}
+
+// parseSMIME decodes an application/pkcs7-mime body. It returns the encapsulated entity of
+// signed-data structures. Enveloped data cannot be analysed.
+func parseSMIME(body io.Reader, transferEncoding string, verifier mailcrypto.Verifier, crypto *models.Crypto, logger log15.Logger) []byte {
+	der, err := decodeTransfer(body, transferEncoding)
+	if err != nil {
+		logger.Info("Error decoding S/MIME body", "error", err)
+		return nil
+	}
+	switch mailcrypto.SMIMEType(der) {
+	case "signed-data":
+		sig, content := verifier.VerifyOpaqueSMIME(der)
+		crypto.Signatures = append(crypto.Signatures, sig)
+		return content
+	case "enveloped-data", "auth-enveloped-data":
+		crypto.Encryption = "smime"
+		crypto.Unanalysable = true
+	default:
+		logger.Info("Unknown S/MIME content")
+	}
+	return nil
+}
+
+// parsePGPEncrypted reports the recipients of a multipart/encrypted body.
+func parsePGPEncrypted(body io.Reader, crypto *models.Crypto, logger log15.Logger) {
+	crypto.Encryption = "pgp"
+	crypto.Unanalysable = true
+	content, err := ioutil.ReadAll(body)
+	if err != nil {
+		logger.Info("Error reading encrypted body", "error", err)
+		return
+	}
+	crypto.RecipientKeyIDs = append(crypto.RecipientKeyIDs, mailcrypto.RecipientKeyIDs(content)...)
+}
+
+// inlinePGP verifies the cleartext signatures, and reports the encrypted messages, of a text body.
+func inlinePGP(text string, verifier mailcrypto.Verifier, crypto *models.Crypto) {
+	if !strings.Contains(text, "-----BEGIN PGP ") {
+		return
+	}
+	if signed, signature, ok := mailcrypto.ClearSigned([]byte(text)); ok {
+		crypto.Signatures = append(crypto.Signatures, verifier.VerifyPGP(signature, signed))
+	}
+	if strings.Contains(text, "-----BEGIN PGP MESSAGE-----") {
+		crypto.Encryption = "pgp"
+		crypto.Unanalysable = true
+		crypto.RecipientKeyIDs = append(crypto.RecipientKeyIDs, mailcrypto.RecipientKeyIDs([]byte(text))...)
+	}
+}
+
+// parseEntity parses a MIME entity extracted from a signed structure.
+func parseEntity(entity []byte, tool extractors.ExifTool, verifier mailcrypto.Verifier, crypto *models.Crypto, logger log15.Logger) (string, []string, []*models.Attachment) {
+	if len(entity) == 0 {
+		return "", nil, nil
+	}
+	_, plain, htmls, attachments := ParsePart(bytes.NewReader(entity), tool, verifier, crypto, logger)
+	return plain, htmls, attachments
+}
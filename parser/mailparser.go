@@ -8,6 +8,7 @@ import (
 	"io"
 	"mime"
 	"mime/multipart"
+	"net"
 	"net/mail"
 	"net/url"
 	"regexp"
@@ -16,6 +17,11 @@ import (
 
 	"github.com/ahmetb/go-linq"
 	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/bayes"
+	"github.com/stephane-martin/mailstats/dlp"
+	"github.com/stephane-martin/mailstats/dnsenrich"
+	"github.com/stephane-martin/mailstats/logos"
+	"github.com/stephane-martin/mailstats/mailcrypto"
 	"github.com/stephane-martin/mailstats/metrics"
 	"github.com/stephane-martin/mailstats/phishtank"
 	"go.uber.org/fx"
@@ -26,7 +32,9 @@ import (
 	"github.com/stephane-martin/mailstats/consumers"
 	"github.com/stephane-martin/mailstats/extractors"
 	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/threading"
 	"github.com/stephane-martin/mailstats/utils"
+	"github.com/stephane-martin/mailstats/vip"
 	"golang.org/x/sync/errgroup"
 
 	"github.com/emersion/go-dkim"
@@ -41,25 +49,42 @@ type Parser interface {
 	ParseMany(context.Context, <-chan *models.IncomingMail, chan<- *models.FeaturesMail)
 }
 
-func NewParser(nbWorkers int,
-	nodkim bool,
-	collector collectors.Collector,
-	consumer consumers.Consumer,
-	geoip utils.GeoIP,
-	tool extractors.ExifTool,
-	phishtank phishtank.Phishtank,
-	logger log15.Logger,
-) Parser {
+// NewParser builds the parser from its dependencies. All of them are optional: the analyses whose
+// service is missing are skipped.
+func NewParser(params Params) Parser {
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	verifier := params.Verifier
+	if verifier == nil {
+		verifier = mailcrypto.NewVerifier("", "", logger)
+	}
 
 	parser := impl{
-		logger:    logger,
-		collector: collector,
-		consumer:  consumer,
-		nbWorkers: nbWorkers,
-		tool:      tool,
-		geoip:     geoip,
-		noDKIM:    nodkim,
-		phishtank: phishtank,
+		logger:     logger,
+		collector:  params.Collector,
+		consumer:   params.Consumer,
+		nbWorkers:  1,
+		tool:       params.Tool,
+		geoip:      params.GeoIP,
+		lookalike:  extractors.NewLookalikeDetector(nil),
+		phishtank:  params.Phishtank,
+		logos:      params.Logos,
+		verifier:   verifier,
+		dns:        params.DNS,
+		vips:       params.VIPs,
+		threads:    params.Threads,
+		dlp:        params.DLP,
+		classifier: params.Bayes,
+	}
+	if args := params.Args; args != nil {
+		parser.nbWorkers = args.NbParsers
+		parser.noDKIM = args.NoDKIM
+		parser.internal = args.Network.Internal()
+		parser.lookalike = extractors.NewLookalikeDetector(args.Lookalike.ProtectedDomains)
+		parser.direction = extractors.NewDirectionClassifier(args.Network.InternalDomains, args.Network.Trusted())
 	}
 
 	return &parser
@@ -73,42 +98,44 @@ type Params struct {
 	Tool      extractors.ExifTool  `optional:"true"`
 	GeoIP     utils.GeoIP          `optional:"true"`
 	Phishtank phishtank.Phishtank  `optional:"true"`
+	Logos     logos.Logos          `optional:"true"`
+	Verifier  mailcrypto.Verifier  `optional:"true"`
+	DNS       dnsenrich.Enricher   `optional:"true"`
+	VIPs      vip.VIPs             `optional:"true"`
+	Threads   threading.Threads    `optional:"true"`
+	DLP       dlp.Scanner          `optional:"true"`
+	Bayes     bayes.Classifier     `optional:"true"`
 	Logger    log15.Logger         `optional:"true"`
 }
 
 var Service = fx.Provide(func(lc fx.Lifecycle, params Params) Parser {
-	var nbWorkers = int(1)
-	if params.Args != nil {
-		nbWorkers = params.Args.NbParsers
-	}
-	logger := params.Logger
-	if logger == nil {
-		logger = log15.New()
-		logger.SetHandler(log15.DiscardHandler())
-	}
-	p := NewParser(
-		nbWorkers,
-		params.Args.NoDKIM,
-		params.Collector,
-		params.Consumer,
-		params.GeoIP,
-		params.Tool,
-		params.Phishtank,
-		logger,
-	)
-	utils.Append(lc, p, logger)
+	p := NewParser(params)
+	utils.Append(lc, p, p.(*impl).logger)
 	return p
 })
 
+// dnsTimeout bounds the DNS lookups for one mail.
+var dnsTimeout = 15 * time.Second
+
 type impl struct {
-	logger    log15.Logger
-	tool      extractors.ExifTool
-	collector collectors.Collector
-	consumer  consumers.Consumer
-	nbWorkers int
-	geoip     utils.GeoIP
-	phishtank phishtank.Phishtank
-	noDKIM    bool
+	logger     log15.Logger
+	tool       extractors.ExifTool
+	collector  collectors.Collector
+	consumer   consumers.Consumer
+	nbWorkers  int
+	geoip      utils.GeoIP
+	phishtank  phishtank.Phishtank
+	logos      logos.Logos
+	verifier   mailcrypto.Verifier
+	dns        dnsenrich.Enricher
+	vips       vip.VIPs
+	threads    threading.Threads
+	dlp        dlp.Scanner
+	classifier bayes.Classifier
+	noDKIM     bool
+	internal   []*net.IPNet
+	lookalike  *extractors.LookalikeDetector
+	direction  *extractors.DirectionClassifier
 }
 
 func (p *impl) Name() string { return "Parser" }
@@ -194,6 +221,11 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 	defer func() {
 		if err == nil {
 			metrics.M().ParsingDuration.Observe(time.Now().Sub(now).Seconds())
+			direction := "unknown"
+			if features.Direction != nil {
+				direction = features.Direction.Direction
+			}
+			metrics.M().Mails.WithLabelValues(direction, i.Family).Inc()
 		} else {
 			metrics.M().ParsingErrors.WithLabelValues(i.Family).Inc()
 		}
@@ -226,9 +258,22 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 	}
 	features.Size = int64(len(i.Data))
 
-	contentType, plain, htmls, attachments := ParsePart(bytes.NewReader(i.Data), p.tool, p.logger)
+	crypto := new(models.Crypto)
+	contentType, plain, htmls, attachments := ParsePart(bytes.NewReader(i.Data), p.tool, p.verifier, crypto, p.logger)
 	features.ContentType = contentType
+	if len(crypto.Signatures) > 0 || crypto.Encryption != "" {
+		features.Crypto = crypto
+	}
+	if contentType == "multipart/report" {
+		report, err := ParseReport(i.Data)
+		if err != nil {
+			p.logger.Info("Error parsing report", "error", err)
+		}
+		features.Report = report
+	}
 	features.Attachments = attachments
+	// the quote markers are needed to split the replies
+	rawPlain := plain
 	plain = filterPlain(plain)
 	urls := make([]string, 0)
 	images := make([]string, 0)
@@ -243,15 +288,26 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 		}
 		urls = append(urls, eURLs...)
 		images = append(images, eImages...)
+		features.HTML = extractors.AnalyseHTML(h, features.HTML)
 	}
 
 	ahtml := strings.TrimSpace(b.String())
 	if len(ahtml) > 0 && (len(ahtml) >= (len(plain) / 2)) {
 		plain = ahtml
+		rawPlain = ahtml
 	}
 	// unicode normalization
 	plain = utils.Normalize(plain)
 
+	// the text analysis is run on the new content of the replies
+	analysed := plain
+	if strings.TrimSpace(rawPlain) != "" {
+		features.Segments = extractors.SplitReply(utils.Normalize(rawPlain))
+		if n := filterPlain(features.Segments.New); n != "" {
+			analysed = n
+		}
+	}
+
 	moreURLs = append(moreURLs, xurls.Relaxed().FindAllString(ahtml, -1)...)
 	for _, u := range moreURLs {
 		v, err := url.PathUnescape(u)
@@ -261,7 +317,29 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 			p.logger.Debug("Error decoding URL", "error", err, "url", u)
 		}
 	}
+	// URLs hidden in QR codes
+	for _, u := range qrCodeURLs(features.Attachments) {
+		if features.URLSources == nil {
+			features.URLSources = make(map[string]string)
+		}
+		features.URLSources[u] = "qrcode"
+		urls = append(urls, u)
+	}
+	// URLs embedded in script and shortcut attachments
+	for _, u := range scriptURLs(features.Attachments) {
+		if features.URLSources == nil {
+			features.URLSources = make(map[string]string)
+		}
+		if _, ok := features.URLSources[u]; !ok {
+			features.URLSources[u] = "script"
+		}
+		urls = append(urls, u)
+	}
 	features.URLs = distinct(urls)
+	if p.geoip != nil {
+		features.URLIPLocations = p.locateURLHosts(features.URLs)
+		features.AddrLocation = p.locate(features.Addr)
+	}
 	if p.phishtank != nil {
 		features.PhishtankURLS = p.phishtank.URLMany(features.URLs)
 	}
@@ -270,20 +348,39 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 	emails = append(emails, findEmailAddresses(ahtml)...)
 	features.Emails = distinct(emails)
 
-	features.Images = distinct(images)
+	remoteImages := make([]string, 0, len(images))
+	for _, img := range images {
+		if extractors.IsRemoteImage(img) {
+			remoteImages = append(remoteImages, img)
+		}
+	}
+	features.Images = distinct(remoteImages)
 
-	if len(plain) > 0 {
-		features.Language = extractors.Language(plain)
+	// the keywords are extracted from the de-obfuscated text
+	analysed = extractors.Deobfuscate(analysed)
+	var bodyLanguage *models.DetectedLanguage
+	if len(analysed) > 0 {
+		bodyLanguage = extractors.DetectLanguage(analysed)
+		if bodyLanguage != nil {
+			features.Language = bodyLanguage.Language
+		}
 	}
 
 	if len(features.Language) > 0 {
-		bagOfWords := extractors.BagOfWords(plain, features.Language)
+		bagOfWords := extractors.BagOfWords(analysed, features.Language)
+		if bodyLanguage.Secondary != "" {
+			for word := range bagOfWords {
+				if extractors.IsStopWord(word, bodyLanguage.Secondary) {
+					delete(bagOfWords, word)
+				}
+			}
+		}
 		stems := extractors.Stems(bagOfWords, features.Language)
 		features.BagOfWords = make(map[string]int)
 		for word := range bagOfWords {
 			features.BagOfWords[stems[word]] += bagOfWords[word]
 		}
-		features.Keywords, features.Phrases = extractors.Keywords(plain, stems, features.Language)
+		features.Keywords, features.Phrases = extractors.Keywords(analysed, stems, features.Language)
 	}
 
 	if len(features.Headers["date"]) > 0 {
@@ -294,8 +391,8 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 		delete(features.Headers, "date")
 	}
 
-	if len(features.Headers["from"]) > 0 {
-		addr := features.Headers["from"][0]
+	if values := m.Header["From"]; len(values) > 0 {
+		addr := values[0]
 		paddr, err := ParseAddress(addr)
 		features.From = new(models.FromAddress)
 		if err == nil {
@@ -306,6 +403,9 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 			features.From.Multiple = multiple
 			features.From.Different = different
 			features.From.Spoofed = spoofed
+		} else if broken, ok := parseBrokenAddress(addr); ok {
+			features.From.Address = broken
+			features.From.Error = err.Error()
 		} else {
 			features.From.Address.Address = addr
 			features.From.Error = err.Error()
@@ -313,31 +413,44 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 		delete(features.Headers, "from")
 	}
 
-	if len(features.Headers["to"]) > 0 {
-		pAddrs, err := ParseAddressList(features.Headers["to"][0])
-		if err == nil {
-			for _, pAddr := range pAddrs {
-				features.To = append(features.To, models.Address{
-					Name:    pAddr.Name,
-					Address: pAddr.Address,
-				})
-			}
-			delete(features.Headers, "to")
-		} else {
-			p.logger.Info("Error parsing the address list from the To: header", "error", err)
+	p.parseAddressHeaders(features, m.Header)
+
+	if len(htmls) > 0 {
+		recipients := append([]string{}, features.RcptTo...)
+		for _, to := range features.To {
+			recipients = append(recipients, to.Address)
+		}
+		for _, h := range htmls {
+			features.RemoteContent = extractors.RemoteContentInventory(h, recipients, features.RemoteContent)
+		}
+		matchInlineImages(features.RemoteContent, features.Attachments)
+		if features.HTML != nil {
+			features.RemoteContent.Stylesheets = features.HTML.RemoteStylesheets
+			features.RemoteContent.Fonts = features.HTML.RemoteFonts
+		}
+	}
+
+	if p.logos != nil {
+		var senderDomain string
+		if features.From != nil {
+			senderDomain = utils.DomainFromAddress(features.From.Address.Address)
 		}
+		features.ImpersonatedBrands = matchLogos(p.logos, features.Attachments, senderDomain)
 	}
 
 	if len(features.Headers["subject"]) > 0 {
 		features.Title = features.Headers["subject"][0]
 		delete(features.Headers, "subject")
 	}
+	features.Obfuscation = analyseObfuscation(features, rawPlain, htmls)
+	detectLanguages(features, bodyLanguage)
 
 	if len(features.Headers["received"]) > 0 {
 		features.Received = make([]models.ReceivedElement, 0)
 		for _, h := range features.Headers["received"] {
 			features.Received = append(features.Received, extractors.ParseReceivedHeader(h, p.geoip, p.logger))
 		}
+		features.ReceivedChain = extractors.AnalyseReceivedChain(features.Received, p.internal)
 		delete(features.Headers, "received")
 	}
 
@@ -369,9 +482,384 @@ func (p *impl) Parse(i *models.IncomingMail) (features *models.FeaturesMail, err
 		}
 	}
 
+	var fromAddress string
+	if features.From != nil {
+		fromAddress = features.From.Address.Address
+	}
+
+	if p.direction != nil {
+		features.Direction = p.classifyDirection(features, fromAddress)
+	}
+
+	fields := extractors.ReadHeaderFields(i.Data)
+	features.HeaderOrder = extractors.HeaderNames(fields)
+	features.Mailer = extractors.FingerprintMailer(fields)
+	features.HeaderAnomalies = extractors.AnalyseHeaders(
+		fields, receptionTime(features, i.TimeReported), senderDomains(features, fromAddress), features.Mailer,
+	)
+
+	features.Category, features.ESP, features.CategoryEvidence = extractors.Categorize(
+		features.Headers, fromAddress, features.Title, plain, features.Report,
+	)
+
+	// the text written by the sender: the new content of the replies and the signature
+	written := plain
+	if features.Segments != nil {
+		written = features.Segments.New + "\n" + features.Segments.Signature
+	}
+	features.Entities = extractors.ExtractEntities(features.Title + "\n" + written)
+
+	// the DLP is about what leaves the organization. Without internal domains nor trusted
+	// networks, the direction is unknown and all the mails are scanned.
+	if p.dlp != nil && (p.direction == nil || features.Direction.Is(models.DirectionOutbound)) {
+		features.DLP = p.dlp.Scan(dlpTexts(features.Title, plain, features.Attachments))
+	}
+
+	if p.threads != nil {
+		features.Thread = p.threads.Add(threading.Message{
+			MessageID:  firstValue(fields, "Message-ID"),
+			InReplyTo:  firstValue(fields, "In-Reply-To"),
+			References: firstValue(fields, "References"),
+			Subject:    features.Title,
+			From:       fromAddress,
+		})
+	}
+
+	if p.lookalike != nil {
+		features.Lookalikes = p.findLookalikes(features, fromAddress)
+	}
+
+	// the impersonation of VIPs is about the mails from outside
+	if p.vips != nil && features.From != nil && (p.direction == nil || features.Direction.Is(models.DirectionInbound)) {
+		var replyTo []string
+		for _, addr := range features.ReplyTo {
+			replyTo = append(replyTo, addr.Address)
+		}
+		features.Impersonation = p.vips.Check(features.From.Address, replyTo, written)
+	}
+
+	if p.dns != nil {
+		features.DNS = p.enrichDNS(features, fromAddress)
+	}
+
+	if p.classifier != nil {
+		features.Classification = p.classifier.Classify(features)
+	}
+
 	return features, nil
 }
 
+// firstValue returns the value of the first header field with the given name.
+func firstValue(fields []extractors.HeaderField, name string) string {
+	for _, f := range fields {
+		if strings.EqualFold(f.Name, name) {
+			return f.Value
+		}
+	}
+	return ""
+}
+
+// receptionTime returns the time the mail was received: the time it was reported, or the time
+// of the last Received header for the mails that were received earlier and are read from an
+// archive.
+func receptionTime(features *models.FeaturesMail, reported time.Time) time.Time {
+	if len(features.Received) > 0 && features.Received[0].Timestamp != nil {
+		if t := *features.Received[0].Timestamp; t.Before(reported) {
+			return t
+		}
+	}
+	return reported
+}
+
+// senderDomains returns the domains of the sender addresses.
+func senderDomains(features *models.FeaturesMail, fromAddress string) []string {
+	addresses := []string{fromAddress, features.MailFrom}
+	if features.Sender != nil {
+		addresses = append(addresses, features.Sender.Address)
+	}
+	if features.ReturnPath != nil {
+		addresses = append(addresses, features.ReturnPath.Address)
+	}
+	var domains []string
+	for _, addr := range addresses {
+		if d := utils.DomainFromAddress(addr); d != "" {
+			domains = append(domains, d)
+		}
+	}
+	return domains
+}
+
+// classifyDirection finds the direction of the mail. The sender and the recipients of the
+// envelope are used, or the headers when the mail was not received by SMTP.
+func (p *impl) classifyDirection(features *models.FeaturesMail, fromAddress string) *models.Direction {
+	sender := features.MailFrom
+	if sender == "" {
+		sender = fromAddress
+	}
+	recipients := features.RcptTo
+	if len(recipients) == 0 {
+		for _, list := range [][]models.Address{features.To, features.Cc, features.Bcc} {
+			for _, addr := range list {
+				recipients = append(recipients, addr.Address)
+			}
+		}
+	}
+	return p.direction.Classify(sender, recipients, features.Family, features.Addr, features.AuthUser)
+}
+
+// detectLanguages detects the languages of the parts of the mail. The body is the new content of
+// the replies, whose language is already detected.
+func detectLanguages(features *models.FeaturesMail, body *models.DetectedLanguage) {
+	add := func(part string, d *models.DetectedLanguage) {
+		if d != nil {
+			d.Part = part
+			features.Languages = append(features.Languages, *d)
+		}
+	}
+	add("subject", extractors.DetectLanguage(features.Title))
+	add("body", body)
+	if s := features.Segments; s != nil {
+		add("quoted", extractors.DetectLanguage(s.Quoted))
+		add("signature", extractors.DetectLanguage(s.Signature))
+		add("disclaimer", extractors.DetectLanguage(s.Disclaimer))
+	}
+	// the short parts, like the subject, are not reliable enough
+	languages := make(map[string]bool)
+	for _, l := range features.Languages {
+		if l.Secondary != "" {
+			features.Multilingual = true
+		}
+		if l.Confidence >= 0.5 {
+			languages[l.Language] = true
+		}
+	}
+	if len(languages) > 1 {
+		features.Multilingual = true
+	}
+}
+
+// analyseObfuscation measures the obfuscation of the subject, of the display name of the sender
+// and of the body, before their normalization.
+func analyseObfuscation(features *models.FeaturesMail, rawBody string, htmls []string) *models.Obfuscation {
+	o := new(models.Obfuscation)
+	// the de-obfuscated subject and display name are reported, the body is too long
+	var deobfuscated string
+	o.Subject, deobfuscated = extractors.AnalyseObfuscation(features.Title)
+	if o.Subject != nil && deobfuscated != features.Title {
+		o.Subject.Deobfuscated = deobfuscated
+	}
+	if features.From != nil {
+		name := features.From.Address.Name
+		o.DisplayName, deobfuscated = extractors.AnalyseObfuscation(name)
+		if o.DisplayName != nil && deobfuscated != name {
+			o.DisplayName.Deobfuscated = deobfuscated
+		}
+	}
+	o.Body, _ = extractors.AnalyseObfuscation(rawBody)
+	if o.Body != nil {
+		for _, h := range htmls {
+			o.Body.HTMLEntities += extractors.HTMLEntityNoise(h)
+		}
+	}
+	if o.Subject == nil && o.DisplayName == nil && o.Body == nil {
+		return nil
+	}
+	return o
+}
+
+// dlpTexts returns the texts scanned by the DLP detectors: the subject and the body, and the text
+// of the attached documents, including the embedded ones.
+func dlpTexts(subject string, body string, attachments []*models.Attachment) []dlp.Text {
+	texts := []dlp.Text{{Source: "body", Content: subject + "\n" + body}}
+	var walk func(a *models.Attachment)
+	walk = func(a *models.Attachment) {
+		if a == nil {
+			return
+		}
+		if a.Text != "" {
+			name := a.Name
+			if name == "" {
+				name = "attachment"
+			}
+			texts = append(texts, dlp.Text{Source: name, Content: a.Text})
+		}
+		walk(a.SubAttachment)
+		for _, sub := range a.SubAttachments {
+			walk(sub)
+		}
+	}
+	for _, a := range attachments {
+		walk(a)
+	}
+	return texts
+}
+
+// findLookalikes checks the sender domains and the URL hosts against the protected domains.
+func (p *impl) findLookalikes(features *models.FeaturesMail, fromAddress string) []models.Lookalike {
+	var lookalikes []models.Lookalike
+	seen := make(map[string]bool)
+	check := func(host, source string) {
+		if host == "" || seen[source+" "+host] {
+			return
+		}
+		seen[source+" "+host] = true
+		protected, technique := p.lookalike.Check(host)
+		if technique != "" {
+			lookalikes = append(lookalikes, models.Lookalike{
+				Host:      host,
+				Source:    source,
+				Protected: protected,
+				Technique: technique,
+			})
+		}
+	}
+	check(utils.DomainFromAddress(fromAddress), "from")
+	for _, addr := range features.ReplyTo {
+		check(utils.DomainFromAddress(addr.Address), "reply-to")
+	}
+	check(utils.DomainFromAddress(features.MailFrom), "envelope")
+	for _, u := range features.URLs {
+		check(utils.URLHost(u), "url")
+	}
+	return lookalikes
+}
+
+// enrichDNS looks up the client IP, the sender domains and the URL hosts in the DNS. The client
+// IP is the connecting client, or the first external hop of the Received headers.
+func (p *impl) enrichDNS(features *models.FeaturesMail, fromAddress string) *models.DNS {
+	q := dnsenrich.Query{
+		EnvelopeDomain: utils.DomainFromAddress(features.MailFrom),
+		FromDomain:     utils.DomainFromAddress(fromAddress),
+	}
+	addr := strings.TrimSpace(features.Addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if ip := net.ParseIP(addr); ip != nil && !ip.IsLoopback() && !utils.IsPrivateIP(ip) {
+		q.ClientIP = ip
+	} else if features.ReceivedChain != nil && features.ReceivedChain.SendingIP != "" {
+		q.ClientIP = net.ParseIP(features.ReceivedChain.SendingIP)
+	}
+	for _, u := range features.URLs {
+		if host := utils.URLHost(u); host != "" {
+			q.URLHosts = append(q.URLHosts, host)
+		}
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), dnsTimeout)
+	defer cancel()
+	return p.dns.Enrich(ctx, q)
+}
+
+// locate returns the location of a public IP address, with an optional port.
+func (p *impl) locate(addr string) *models.GeoIPResult {
+	addr = strings.TrimSpace(addr)
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	ip := net.ParseIP(strings.Trim(addr, "[]"))
+	if ip == nil || ip.IsLoopback() || utils.IsPrivateIP(ip) {
+		return nil
+	}
+	l, err := p.geoip.GeoIP(ip)
+	if err != nil {
+		p.logger.Debug("Error locating IP address", "ip", addr, "error", err)
+		return nil
+	}
+	return l
+}
+
+// locateURLHosts locates the URL hosts that are IP addresses.
+func (p *impl) locateURLHosts(urls []string) map[string]*models.GeoIPResult {
+	var locations map[string]*models.GeoIPResult
+	for _, u := range urls {
+		host := utils.URLHost(u)
+		if net.ParseIP(host) == nil {
+			continue
+		}
+		if _, ok := locations[host]; ok {
+			continue
+		}
+		if l := p.locate(host); l != nil {
+			if locations == nil {
+				locations = make(map[string]*models.GeoIPResult)
+			}
+			locations[host] = l
+		}
+	}
+	return locations
+}
+
+// walkAttachments calls f for the attachments, and recursively for the files that they contain.
+func walkAttachments(attachments []*models.Attachment, f func(a *models.Attachment)) {
+	for _, a := range attachments {
+		if a == nil {
+			continue
+		}
+		f(a)
+		if a.SubAttachment != nil {
+			walkAttachments([]*models.Attachment{a.SubAttachment}, f)
+		}
+		walkAttachments(a.SubAttachments, f)
+	}
+}
+
+// qrCodeURLs returns the URLs found in the QR codes of the attachments.
+func qrCodeURLs(attachments []*models.Attachment) (urls []string) {
+	walkAttachments(attachments, func(a *models.Attachment) {
+		for _, content := range a.QRCodes {
+			urls = append(urls, xurls.Relaxed().FindAllString(content, -1)...)
+		}
+	})
+	return urls
+}
+
+// scriptURLs returns the URLs found in the script attachments.
+func scriptURLs(attachments []*models.Attachment) (urls []string) {
+	walkAttachments(attachments, func(a *models.Attachment) {
+		if a.ScriptMetadata != nil {
+			urls = append(urls, a.ScriptMetadata.URLs...)
+		}
+	})
+	return urls
+}
+
+// matchLogos recognizes the brand logos in the image attachments, and returns the
+// brands that are not legitimately used by the sender domain.
+func matchLogos(l logos.Logos, attachments []*models.Attachment, senderDomain string) (impersonated []string) {
+	walkAttachments(attachments, func(a *models.Attachment) {
+		if a.ImageHashes == nil {
+			return
+		}
+		a.LogoMatches = l.Match(a.ImageHashes)
+		for _, match := range a.LogoMatches {
+			if !l.Legitimate(match.Brand, senderDomain) {
+				impersonated = append(impersonated, match.Brand)
+			}
+		}
+	})
+	return distinct(impersonated)
+}
+
+// matchInlineImages looks for the MIME parts referenced by the cid: images of the HTML body.
+func matchInlineImages(inventory *models.RemoteContent, attachments []*models.Attachment) {
+	if inventory == nil {
+		return
+	}
+	byContentID := make(map[string]*models.Attachment)
+	for _, attachment := range attachments {
+		if attachment.ContentID != "" {
+			byContentID[attachment.ContentID] = attachment
+		}
+	}
+	for i, img := range inventory.InlineImages {
+		if attachment, ok := byContentID[img.ContentID]; ok {
+			inventory.InlineImages[i].Found = true
+			inventory.InlineImages[i].Attachment = attachment.Name
+			inventory.InlineImages[i].Type = attachment.InferredType
+		}
+	}
+}
+
 func nameContainsAddress(fullAddr *mail.Address) (bool, bool, bool, bool) {
 	if fullAddr == nil {
 		return false, false, false, false
@@ -465,7 +953,15 @@ func filterPlain(plain string) string {
 	return strings.TrimSpace(plain)
 }
 
-func ParsePart(part io.Reader, tool extractors.ExifTool, logger log15.Logger) (string, string, []string, []*models.Attachment) {
+// ParsePart parses a MIME entity. The signatures and the encryption found in the entity are
+// accumulated in crypto.
+func ParsePart(part io.Reader, tool extractors.ExifTool, verifier mailcrypto.Verifier, crypto *models.Crypto, logger log15.Logger) (string, string, []string, []*models.Attachment) {
+	if verifier == nil {
+		verifier = mailcrypto.NewVerifier("", "", logger)
+	}
+	if crypto == nil {
+		crypto = new(models.Crypto)
+	}
 	plain := ""
 	var allHTML []string
 	var attachments []*models.Attachment
@@ -488,8 +984,23 @@ func ParsePart(part io.Reader, tool extractors.ExifTool, logger log15.Logger) (s
 	charSet := strings.TrimSpace(params["charset"])
 	transferEncoding := strings.ToLower(strings.TrimSpace(msg.Header.Get("Content-Transfer-Encoding")))
 
+	if contentType == "multipart/signed" {
+		entity := parseSigned(msg.Body, params, verifier, crypto, logger)
+		plain, allHTML, attachments = parseEntity(entity, tool, verifier, crypto, logger)
+		return contentType, plain, allHTML, attachments
+	}
+	if isSMIMEType(contentType) {
+		entity := parseSMIME(msg.Body, transferEncoding, verifier, crypto, logger)
+		plain, allHTML, attachments = parseEntity(entity, tool, verifier, crypto, logger)
+		return contentType, plain, allHTML, attachments
+	}
+	if contentType == "multipart/encrypted" {
+		parsePGPEncrypted(msg.Body, crypto, logger)
+		return contentType, "", nil, nil
+	}
 	if contentType == "text/plain" {
 		b := decodeBody(msg.Body, charSet, transferEncoding)
+		inlinePGP(b, verifier, crypto)
 		return contentType, b, nil, nil
 	}
 	if contentType == "text/html" {
@@ -534,15 +1045,20 @@ func ParsePart(part io.Reader, tool extractors.ExifTool, logger log15.Logger) (s
 		}
 		subCharset := strings.TrimSpace(subParams["charset"])
 
+		if isReportPart(subContentType) {
+			// parsed by ParseReport
+			continue
+		}
+
 		if strings.HasPrefix(subContentType, "message/") {
-			_, subplain, subhtmls, subAttachments := ParsePart(subPart, tool, logger)
+			_, subplain, subhtmls, subAttachments := ParsePart(subPart, tool, verifier, crypto, logger)
 			plain = plain + subplain + "\n"
 			allHTML = append(allHTML, subhtmls...)
 			attachments = append(attachments, subAttachments...)
 			continue
 		}
 
-		if strings.HasPrefix(subContentType, "multipart/") {
+		if strings.HasPrefix(subContentType, "multipart/") || isSMIMEType(subContentType) {
 			h := fmt.Sprintf("Content-Type: %s\n", subContentTypeHeader)
 			if subTransferHeader != "" {
 				h += fmt.Sprintf("Content-Transfer-Encoding: %s\n", subTransferHeader)
@@ -555,6 +1071,8 @@ func ParsePart(part io.Reader, tool extractors.ExifTool, logger log15.Logger) (s
 					subPart,
 				),
 				tool,
+				verifier,
+				crypto,
 				logger,
 			)
 			plain = plain + subPlain + "\n"
@@ -563,10 +1081,12 @@ func ParsePart(part io.Reader, tool extractors.ExifTool, logger log15.Logger) (s
 			continue
 		}
 
+		contentID := strings.Trim(strings.TrimSpace(subPart.Header.Get("Content-ID")), "<>")
 		fn := strings.TrimSpace(subPart.FileName())
-		if len(fn) == 0 {
+		if len(fn) == 0 && (len(contentID) == 0 || strings.HasPrefix(subContentType, "text/")) {
 			if subContentType == "text/plain" {
 				b := decodeBody(subPart, subCharset, subTransferHeader)
+				inlinePGP(b, verifier, crypto)
 				plain = plain + b + "\n"
 			}
 			if subContentType == "text/html" {
@@ -578,6 +1098,10 @@ func ParsePart(part io.Reader, tool extractors.ExifTool, logger log15.Logger) (s
 			}
 			continue
 		}
+		if len(fn) == 0 {
+			// inline part without a filename, referenced by its Content-ID
+			fn = contentID
+		}
 		filename, err := StringDecode(fn)
 		if err != nil {
 			continue
@@ -589,6 +1113,7 @@ func ParsePart(part io.Reader, tool extractors.ExifTool, logger log15.Logger) (s
 		attachment, err := AnalyseAttachment(filename, subContentType, subPartReader, tool, logger)
 		if err == nil {
 			attachment.ReportedType = subContentType
+			attachment.ContentID = contentID
 			attachments = append(attachments, attachment)
 		}
 	}
@@ -0,0 +1,244 @@
+package parser
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net"
+	"net/mail"
+	"net/textproto"
+	"strconv"
+	"strings"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+var ErrNotReport = errors.New("not a multipart/report mail")
+
+func isReportPart(contentType string) bool {
+	switch contentType {
+	case "message/delivery-status", "message/global-delivery-status", "message/feedback-report":
+		return true
+	}
+	return false
+}
+
+// ParseReport parses the machine readable parts of a multipart/report mail: the delivery status
+// of a bounce (RFC 3464), the feedback report of an abuse report (RFC 5965), and the headers of
+// the original message.
+func ParseReport(data []byte) (*models.Report, error) {
+	msg, err := mail.ReadMessage(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	contentType, params, err := mime.ParseMediaType(msg.Header.Get("Content-Type"))
+	if err != nil {
+		return nil, err
+	}
+	boundary := strings.TrimSpace(params["boundary"])
+	if contentType != "multipart/report" || boundary == "" {
+		return nil, ErrNotReport
+	}
+	report := &models.Report{
+		ReportType: strings.ToLower(strings.TrimSpace(params["report-type"])),
+	}
+	// the first malformed part is reported with the fields read before it
+	var malformed error
+	mr := multipart.NewReader(msg.Body, boundary)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return report, err
+		}
+		partType, _, err := mime.ParseMediaType(part.Header.Get("Content-Type"))
+		if err != nil {
+			continue
+		}
+		body, err := decodeTransfer(part, part.Header.Get("Content-Transfer-Encoding"))
+		if err != nil {
+			continue
+		}
+		switch partType {
+		case "message/delivery-status", "message/global-delivery-status":
+			report.DeliveryStatus, err = parseDeliveryStatus(body)
+		case "message/feedback-report":
+			report.Feedback, err = parseFeedbackReport(body)
+		case "message/rfc822", "message/global", "text/rfc822-headers", "message/global-headers":
+			err = parseOriginalHeaders(body, report)
+		}
+		if err != nil {
+			report.Truncated = true
+			if malformed == nil {
+				malformed = err
+			}
+		}
+	}
+	return report, malformed
+}
+
+// readFieldGroups reads the groups of fields, separated by blank lines, of a report part. The
+// reading stops at the first malformed line: the groups read before it are returned with the
+// error.
+func readFieldGroups(body []byte) ([]textproto.MIMEHeader, error) {
+	var groups []textproto.MIMEHeader
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(body)))
+	for {
+		h, err := r.ReadMIMEHeader()
+		if len(h) > 0 {
+			groups = append(groups, h)
+		}
+		if err == io.EOF {
+			return groups, nil
+		}
+		if err != nil {
+			return groups, err
+		}
+	}
+}
+
+// fieldValue removes the type prefix of a typed field, like "rfc822; user@example.org".
+func fieldValue(h textproto.MIMEHeader, key string) string {
+	v := strings.TrimSpace(h.Get(key))
+	if idx := strings.Index(v, ";"); idx >= 0 {
+		v = strings.TrimSpace(v[idx+1:])
+	}
+	return v
+}
+
+func parseDeliveryStatus(body []byte) (*models.DeliveryStatus, error) {
+	groups, err := readFieldGroups(body)
+	if len(groups) == 0 {
+		return nil, err
+	}
+	perMessage := groups[0]
+	status := &models.DeliveryStatus{
+		ReportingMTA:       fieldValue(perMessage, "Reporting-Mta"),
+		OriginalEnvelopeID: strings.TrimSpace(perMessage.Get("Original-Envelope-Id")),
+		ReceivedFromMTA:    fieldValue(perMessage, "Received-From-Mta"),
+		ArrivalDate:        strings.TrimSpace(perMessage.Get("Arrival-Date")),
+	}
+	for _, h := range groups[1:] {
+		if h.Get("Final-Recipient") == "" {
+			continue
+		}
+		rcpt := models.DSNRecipient{
+			FinalRecipient:    fieldValue(h, "Final-Recipient"),
+			OriginalRecipient: fieldValue(h, "Original-Recipient"),
+			Action:            strings.ToLower(strings.TrimSpace(h.Get("Action"))),
+			Status:            statusCode(h.Get("Status")),
+			DiagnosticCode:    fieldValue(h, "Diagnostic-Code"),
+			RemoteMTA:         fieldValue(h, "Remote-Mta"),
+			LastAttemptDate:   strings.TrimSpace(h.Get("Last-Attempt-Date")),
+			WillRetryUntil:    strings.TrimSpace(h.Get("Will-Retry-Until")),
+		}
+		rcpt.StatusClass = statusClass(rcpt.Status)
+		status.Recipients = append(status.Recipients, rcpt)
+	}
+	return status, err
+}
+
+// statusCode keeps the "class.subject.detail" code of a Status field, without any comment.
+func statusCode(status string) string {
+	fields := strings.Fields(status)
+	if len(fields) == 0 {
+		return ""
+	}
+	return fields[0]
+}
+
+func statusClass(status string) string {
+	switch {
+	case strings.HasPrefix(status, "2."):
+		return "success"
+	case strings.HasPrefix(status, "4."):
+		return "transient"
+	case strings.HasPrefix(status, "5."):
+		return "permanent"
+	}
+	return ""
+}
+
+func parseFeedbackReport(body []byte) (*models.FeedbackReport, error) {
+	groups, err := readFieldGroups(body)
+	if len(groups) == 0 {
+		return nil, err
+	}
+	h := groups[0]
+	feedback := &models.FeedbackReport{
+		FeedbackType:     strings.ToLower(strings.TrimSpace(h.Get("Feedback-Type"))),
+		UserAgent:        strings.TrimSpace(h.Get("User-Agent")),
+		OriginalMailFrom: strings.Trim(strings.TrimSpace(h.Get("Original-Mail-From")), "<>"),
+		ArrivalDate:      strings.TrimSpace(h.Get("Arrival-Date")),
+	}
+	if feedback.ArrivalDate == "" {
+		feedback.ArrivalDate = strings.TrimSpace(h.Get("Received-Date"))
+	}
+	if ip := net.ParseIP(strings.TrimSpace(h.Get("Source-Ip"))); ip != nil {
+		feedback.SourceIP = ip.String()
+	}
+	for _, rcpt := range h["Original-Rcpt-To"] {
+		feedback.OriginalRcptTo = append(feedback.OriginalRcptTo, strings.Trim(strings.TrimSpace(rcpt), "<>"))
+	}
+	for _, domain := range h["Reported-Domain"] {
+		feedback.ReportedDomain = append(feedback.ReportedDomain, strings.ToLower(strings.TrimSpace(domain)))
+	}
+	for _, uri := range h["Reported-Uri"] {
+		feedback.ReportedURI = append(feedback.ReportedURI, strings.TrimSpace(uri))
+	}
+	for _, result := range h["Authentication-Results"] {
+		feedback.AuthenticationResults = append(feedback.AuthenticationResults, strings.TrimSpace(result))
+	}
+	if incidents, err := strconv.Atoi(strings.TrimSpace(h.Get("Incidents"))); err == nil {
+		feedback.Incidents = incidents
+	}
+	return feedback, err
+}
+
+// parseOriginalHeaders links the report to the headers of the original message. The body of the
+// message, if any, is not read.
+func parseOriginalHeaders(body []byte, report *models.Report) error {
+	h, err := textproto.NewReader(bufio.NewReader(bytes.NewReader(body))).ReadMIMEHeader()
+	if err == io.EOF {
+		err = nil
+	}
+	if len(h) == 0 {
+		return err
+	}
+	report.OriginalHeaders = make(map[string][]string)
+	for k, vl := range h {
+		k = strings.ToLower(k)
+		for _, v := range vl {
+			decv, err := StringDecode(v)
+			if err != nil {
+				decv = v
+			}
+			report.OriginalHeaders[k] = append(report.OriginalHeaders[k], decv)
+		}
+	}
+	first := func(key string) string {
+		if len(report.OriginalHeaders[key]) == 0 {
+			return ""
+		}
+		return strings.TrimSpace(report.OriginalHeaders[key][0])
+	}
+	report.OriginalMessageID = strings.Trim(first("message-id"), "<>")
+	report.OriginalFrom = first("from")
+	report.OriginalSubject = first("subject")
+	for _, to := range report.OriginalHeaders["to"] {
+		addrs, err := mail.ParseAddressList(to)
+		if err != nil {
+			report.OriginalTo = append(report.OriginalTo, strings.TrimSpace(to))
+			continue
+		}
+		for _, addr := range addrs {
+			report.OriginalTo = append(report.OriginalTo, addr.Address)
+		}
+	}
+	return err
+}
@@ -0,0 +1,204 @@
+package parser
+
+import (
+	"encoding/base64"
+	"io/ioutil"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func readSample(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := ioutil.ReadFile(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// reportMail builds a multipart/report mail with the parts, each given with its headers.
+func reportMail(reportType string, parts ...string) []byte {
+	var b strings.Builder
+	b.WriteString("From: MAILER-DAEMON@example.org\nSubject: report\nMIME-Version: 1.0\n")
+	b.WriteString("Content-Type: multipart/report; report-type=" + reportType + "; boundary=\"b1\"\n\n")
+	for _, p := range parts {
+		b.WriteString("--b1\n" + p + "\n")
+	}
+	b.WriteString("--b1--\n")
+	return []byte(b.String())
+}
+
+func TestParseReportDSN(t *testing.T) {
+	report, err := ParseReport(readSample(t, "dsn.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ReportType != "delivery-status" || report.Truncated {
+		t.Errorf("report type %q, truncated %t", report.ReportType, report.Truncated)
+	}
+	expected := &models.DeliveryStatus{
+		ReportingMTA: "cs.utk.edu",
+		ArrivalDate:  "Sat, 2 Jul 1994 17:10:28 -0400",
+		Recipients: []models.DSNRecipient{
+			{
+				FinalRecipient:    "louisl@larry.slip.umd.edu",
+				OriginalRecipient: "louisl@larry.slip.umd.edu",
+				Action:            "failed",
+				Status:            "4.0.0",
+				StatusClass:       "transient",
+				DiagnosticCode:    "426 connection timed out",
+				LastAttemptDate:   "Thu, 7 Jul 1994 17:15:49 -0400",
+			},
+			{
+				FinalRecipient: "thomas@de-montfort.ac.uk",
+				Action:         "failed",
+				Status:         "5.0.0",
+				StatusClass:    "permanent",
+				DiagnosticCode: "550 user unknown",
+				RemoteMTA:      "bodkin.de-montfort.ac.uk",
+			},
+			{
+				FinalRecipient: "nm@rainbow.example.org",
+				Action:         "delayed",
+				Status:         "4.4.1",
+				StatusClass:    "transient",
+				WillRetryUntil: "Sat, 9 Jul 1994 17:10:28 -0400",
+			},
+		},
+	}
+	if !reflect.DeepEqual(report.DeliveryStatus, expected) {
+		t.Errorf("expected %+v, got %+v", expected, report.DeliveryStatus)
+	}
+	if report.OriginalMessageID != "199407022110.RAA02456@CS.UTK.EDU" || report.OriginalFrom != "owner-info-mime@cs.utk.edu" ||
+		report.OriginalSubject != "Réunion" {
+		t.Errorf("original message: %q %q %q", report.OriginalMessageID, report.OriginalFrom, report.OriginalSubject)
+	}
+	if to := []string{"louisl@larry.slip.umd.edu", "thomas@de-montfort.ac.uk"}; !reflect.DeepEqual(report.OriginalTo, to) {
+		t.Errorf("original recipients: expected %q, got %q", to, report.OriginalTo)
+	}
+	if report.Feedback != nil {
+		t.Errorf("feedback in a DSN: %+v", report.Feedback)
+	}
+}
+
+func TestParseReportARF(t *testing.T) {
+	report, err := ParseReport(readSample(t, "arf.eml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.ReportType != "feedback-report" || report.Truncated {
+		t.Errorf("report type %q, truncated %t", report.ReportType, report.Truncated)
+	}
+	expected := &models.FeedbackReport{
+		FeedbackType:          "abuse",
+		UserAgent:             "SomeGenerator/1.0",
+		SourceIP:              "192.0.2.1",
+		OriginalMailFrom:      "somespammer@example.net",
+		OriginalRcptTo:        []string{"user@example.com"},
+		ReportedDomain:        []string{"example.net"},
+		ReportedURI:           []string{"http://example.net/earn_money.html", "mailto:user@example.com"},
+		ArrivalDate:           "Thu, 8 Mar 2005 14:00:00 EDT",
+		AuthenticationResults: []string{"mail.example.com; spf=fail smtp.mail=somespammer@example.com"},
+	}
+	if !reflect.DeepEqual(report.Feedback, expected) {
+		t.Errorf("expected %+v, got %+v", expected, report.Feedback)
+	}
+	if report.OriginalMessageID != "8787KJKJ3K4J3K4J3K4J3.mail@example.net" || report.OriginalSubject != "Earn money" {
+		t.Errorf("original message: %q %q", report.OriginalMessageID, report.OriginalSubject)
+	}
+	// not an address list
+	if to := []string{"<Undisclosed Recipients>"}; !reflect.DeepEqual(report.OriginalTo, to) {
+		t.Errorf("original recipients: expected %q, got %q", to, report.OriginalTo)
+	}
+	if report.DeliveryStatus != nil {
+		t.Errorf("delivery status in a feedback report: %+v", report.DeliveryStatus)
+	}
+}
+
+func TestParseReportEncoded(t *testing.T) {
+	status := "Reporting-MTA: dns; mx.example.org\n\nFinal-Recipient: utf-8; jöran@example.org\nAction: failed\nStatus: 5.1.1\n"
+	data := reportMail("delivery-status",
+		"Content-Type: message/global-delivery-status\nContent-Transfer-Encoding: base64\n\n"+
+			base64.StdEncoding.EncodeToString([]byte(status)),
+		"Content-Type: text/rfc822-headers\n\nMessage-ID: <1@example.org>\nSubject: hello\n")
+	report, err := ParseReport(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ds := report.DeliveryStatus
+	if ds == nil || ds.ReportingMTA != "mx.example.org" || len(ds.Recipients) != 1 ||
+		ds.Recipients[0].FinalRecipient != "jöran@example.org" || ds.Recipients[0].StatusClass != "permanent" {
+		t.Errorf("unexpected delivery status: %+v", ds)
+	}
+	if report.OriginalMessageID != "1@example.org" || report.OriginalSubject != "hello" {
+		t.Errorf("original message: %q %q", report.OriginalMessageID, report.OriginalSubject)
+	}
+}
+
+func TestParseReportTruncated(t *testing.T) {
+	// a DSN whose second recipient has a malformed line
+	data := reportMail("delivery-status",
+		"Content-Type: message/delivery-status\n\nReporting-MTA: dns; mx.example.org\n\n"+
+			"Final-Recipient: rfc822; a@example.org\nAction: failed\nStatus: 5.1.1\n\n"+
+			"Final-Recipient: rfc822; b@example.org\nthis line is not a field\nAction: failed\nStatus: 5.2.2\n\n"+
+			"Final-Recipient: rfc822; c@example.org\nAction: failed\nStatus: 5.1.1\n")
+	report, err := ParseReport(data)
+	if err == nil || report == nil || !report.Truncated {
+		t.Fatalf("DSN: expected a truncated report, got %+v, %v", report, err)
+	}
+	if ds := report.DeliveryStatus; ds == nil || len(ds.Recipients) != 2 || ds.Recipients[0].FinalRecipient != "a@example.org" ||
+		ds.Recipients[1].FinalRecipient != "b@example.org" || ds.Recipients[1].Action != "" {
+		t.Errorf("DSN: the fields before the malformed line are not kept: %+v", ds)
+	}
+
+	data = reportMail("feedback-report",
+		"Content-Type: message/feedback-report\n\nFeedback-Type: abuse\nUser-Agent: SomeGenerator/1.0\n"+
+			": no name\nSource-IP: 192.0.2.1\n",
+		"Content-Type: message/rfc822\n\nSubject: spam\n\nThe body: not a header\n")
+	report, err = ParseReport(data)
+	if err == nil || report == nil || !report.Truncated {
+		t.Fatalf("ARF: expected a truncated report, got %+v, %v", report, err)
+	}
+	if f := report.Feedback; f == nil || f.FeedbackType != "abuse" || f.UserAgent != "SomeGenerator/1.0" || f.SourceIP != "" {
+		t.Errorf("ARF: unexpected feedback %+v", f)
+	}
+	if report.OriginalSubject != "spam" {
+		t.Errorf("ARF: original subject %q", report.OriginalSubject)
+	}
+
+	// the body of the original message is not parsed
+	data = reportMail("feedback-report",
+		"Content-Type: message/feedback-report\n\nFeedback-Type: fraud\n",
+		"Content-Type: message/rfc822\n\nSubject: spam\n\nThe body: not a header\nnot a field\n")
+	if report, err = ParseReport(data); err != nil || report.Truncated {
+		t.Errorf("original body: truncated %t, %v", report.Truncated, err)
+	}
+
+	data = reportMail("feedback-report",
+		"Content-Type: message/feedback-report\n\nFeedback-Type: abuse\n",
+		"Content-Type: text/rfc822-headers\n\nSubject: spam\nFrom spammer@example.net\nMessage-ID: <2@example.net>\n")
+	report, err = ParseReport(data)
+	if err == nil || !report.Truncated || report.OriginalSubject != "spam" || report.OriginalMessageID != "" {
+		t.Errorf("original headers: %+v, %v", report, err)
+	}
+}
+
+func TestParseReportNotReport(t *testing.T) {
+	mails := []string{
+		"Content-Type: text/plain\n\nhello\n",
+		"Content-Type: multipart/mixed; boundary=b1\n\n--b1\n\nhello\n--b1--\n",
+		"Content-Type: multipart/report; report-type=delivery-status\n\nhello\n",
+	}
+	for _, m := range mails {
+		if _, err := ParseReport([]byte(m)); err != ErrNotReport {
+			t.Errorf("%q: expected ErrNotReport, got %v", m, err)
+		}
+	}
+	if _, err := ParseReport([]byte("Content-Type: multipart/report; boundary=\"b1\n\n")); err == nil {
+		t.Error("no error for an invalid Content-Type")
+	}
+}
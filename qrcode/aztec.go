@@ -0,0 +1,536 @@
+package qrcode
+
+import (
+	"errors"
+	"math"
+)
+
+// Aztec codes are described in ISO/IEC 24778.
+
+var errAztecMode = errors.New("invalid Aztec mode message")
+
+// aztecCenter is a possible bullseye of an Aztec code.
+type aztecCenter struct {
+	point
+	// runs are the positions in the middle of the rings, on the right of the center
+	runs       []float64
+	moduleSize float64
+}
+
+// decodeAztecCodes returns the contents of the Aztec codes found in image.
+func decodeAztecCodes(image *bitMatrix) []string {
+	var contents []string
+	for _, center := range findBullseyes(image) {
+		if content, err := decodeAztec(image, center); err == nil && content != "" {
+			contents = append(contents, content)
+		}
+	}
+	return contents
+}
+
+// findBullseyes scans the rows for the alternating runs of the same length that cross the center
+// of a bullseye, and checks them on the column.
+func findBullseyes(image *bitMatrix) []aztecCenter {
+	var centers []aztecCenter
+	for y := 0; y < image.height; y++ {
+		starts := runStarts(image, y)
+		for i := 0; i+7 < len(starts); i++ {
+			// L D L D L D L: the rings 3 to 1 on each side of the dark center
+			if image.get(starts[i], y) || starts[i+7]-starts[i] < 11 || !equalRuns(starts[i:i+8]) {
+				continue
+			}
+			cx := float64(starts[i+3]+starts[i+4]) / 2
+			known := false
+			for _, c := range centers {
+				if math.Abs(c.x-cx) < 3*c.moduleSize && math.Abs(c.y-float64(y)) < 3*c.moduleSize {
+					known = true
+					break
+				}
+			}
+			if known {
+				continue
+			}
+			if center, ok := checkBullseye(image, int(cx), y); ok {
+				centers = append(centers, center)
+			}
+		}
+	}
+	return centers
+}
+
+// equalRuns tells if the runs between the consecutive starts have about the same length.
+func equalRuns(starts []int) bool {
+	n := len(starts) - 1
+	average := float64(starts[n]-starts[0]) / float64(n)
+	if average < 1 {
+		return false
+	}
+	for i := 0; i < n; i++ {
+		if math.Abs(float64(starts[i+1]-starts[i])-average) > average/2 {
+			return false
+		}
+	}
+	return true
+}
+
+// runsFrom returns the lengths of the first n runs of pixels from (x, y) in the direction (dx, dy).
+func runsFrom(image *bitMatrix, x, y, dx, dy, n int) []int {
+	runs := make([]int, 0, n)
+	color := image.get(x, y)
+	length := 0
+	for x >= 0 && y >= 0 && x < image.width && y < image.height {
+		if image.get(x, y) != color {
+			runs = append(runs, length)
+			if len(runs) == n {
+				break
+			}
+			color = !color
+			length = 0
+		}
+		length++
+		x += dx
+		y += dy
+	}
+	return runs
+}
+
+// checkBullseye checks the runs of the column through the possible center, then locates the
+// center on the row and measures the rings on its right.
+func checkBullseye(image *bitMatrix, x, y int) (aztecCenter, bool) {
+	up, down := runsFrom(image, x, y, 0, -1, 4), runsFrom(image, x, y, 0, 1, 4)
+	if len(up) < 4 || len(down) < 4 || !image.get(x, y) {
+		return aztecCenter{}, false
+	}
+	starts := []int{0, up[3], up[3] + up[2], up[3] + up[2] + up[1]}
+	for _, r := range []int{up[0] + down[0] - 1, down[1], down[2], down[3]} {
+		starts = append(starts, starts[len(starts)-1]+r)
+	}
+	if !equalRuns(starts) {
+		return aztecCenter{}, false
+	}
+	cy := y - up[0] + 1 + (up[0]+down[0]-1)/2
+	left, right := runsFrom(image, x, cy, -1, 0, 1), runsFrom(image, x, cy, 1, 0, 7)
+	if len(left) < 1 || len(right) < 4 {
+		return aztecCenter{}, false
+	}
+	start := x - left[0] + 1
+	center := aztecCenter{point: point{float64(start) + float64(left[0]+right[0]-1)/2, float64(cy) + 0.5}}
+	pos := float64(x + right[0])
+	for _, r := range right[1:] {
+		center.runs = append(center.runs, pos+float64(r)/2)
+		pos += float64(r)
+	}
+	center.moduleSize = float64(starts[7]) / 7
+	if center.moduleSize < 1.5 {
+		// too small to be sampled, and too common in noisy images
+		return aztecCenter{}, false
+	}
+	return center, true
+}
+
+// bullseyeCorners locates the corners of the square at k modules around the center, in the
+// middle of the dark ring k-1 and the light ring k, that are both closed.
+func bullseyeCorners(image *bitMatrix, center aztecCenter, k int) ([4]point, bool) {
+	var corners [4]point
+	if len(center.runs) < k {
+		return corners, false
+	}
+	limit := int(16*float64(k)*center.moduleSize*center.moduleSize) + 50
+	var rects [2][4]point
+	for i, ring := range []int{k - 1, k} {
+		r := fill(image, int(center.runs[ring-1]), int(center.y), limit)
+		if r == nil || image.get(int(center.runs[ring-1]), int(center.y)) != (ring%2 == 0) {
+			return corners, false
+		}
+		rect, ok := minAreaRect(r.hull())
+		if !ok {
+			return corners, false
+		}
+		middle := point{(rect[0].x + rect[2].x) / 2, (rect[0].y + rect[2].y) / 2}
+		side := distance(rect[0], rect[1]) / float64(2*ring+1) / center.moduleSize
+		if distance(middle, center.point) > 1.5*center.moduleSize || side < 0.5 || side > 1.5 {
+			return corners, false
+		}
+		rects[i] = rect
+	}
+	for i, c := range rects[1] {
+		nearest := rects[0][0]
+		for _, d := range rects[0][1:] {
+			if distance(c, d) < distance(c, nearest) {
+				nearest = d
+			}
+		}
+		corners[i] = point{(c.x + nearest.x) / 2, (c.y + nearest.y) / 2}
+	}
+	return corners, true
+}
+
+// aztecSymbol reads the modules of a code around its center.
+type aztecSymbol struct {
+	image    *bitMatrix
+	p        perspective
+	rotation int
+}
+
+// get returns the module at (x, y) from the center, in the orientation of the symbol.
+func (s *aztecSymbol) get(x, y int) bool {
+	for i := 0; i < s.rotation; i++ {
+		x, y = -y, x
+	}
+	ix, iy := s.p.transform(float64(x), float64(y))
+	return s.image.get(int(math.Floor(ix)), int(math.Floor(iy)))
+}
+
+// oriented tells if the orientation marks at the corners of the mode message are in place.
+func (s *aztecSymbol) oriented(radius int) bool {
+	r := radius
+	marks := []struct {
+		x, y int
+		dark bool
+	}{
+		{-r, -r, true}, {-r + 1, -r, true}, {-r, -r + 1, true},
+		{r, -r, true}, {r, -r + 1, true}, {r - 1, -r, false},
+		{r, r - 1, true}, {r, r, false}, {r - 1, r, false},
+		{-r, r, false}, {-r + 1, r, false}, {-r, r - 1, false},
+	}
+	wrong := 0
+	for _, m := range marks {
+		if s.get(m.x, m.y) != m.dark {
+			wrong++
+		}
+	}
+	return wrong <= 1
+}
+
+// modeMessage reads the number of layers and of data words around the bullseye.
+func (s *aztecSymbol) modeMessage(compact bool) (layers int, dataWords int, err error) {
+	var bits []bool
+	var words, dataCount int
+	if compact {
+		bits = make([]bool, 28)
+		for i := 0; i < 7; i++ {
+			offset := -3 + i
+			bits[i] = s.get(offset, -5)
+			bits[i+7] = s.get(5, offset)
+			bits[20-i] = s.get(offset, 5)
+			bits[27-i] = s.get(-5, offset)
+		}
+		words, dataCount = 7, 2
+	} else {
+		bits = make([]bool, 40)
+		for i := 0; i < 10; i++ {
+			offset := -5 + i + i/5
+			bits[i] = s.get(offset, -7)
+			bits[i+10] = s.get(7, offset)
+			bits[29-i] = s.get(offset, 7)
+			bits[39-i] = s.get(-7, offset)
+		}
+		words, dataCount = 10, 4
+	}
+	codewords := make([]int, words)
+	for i := range codewords {
+		codewords[i] = readBits(bits, 4*i, 4)
+	}
+	if err := aztecParamField.correct(codewords, words-dataCount); err != nil {
+		return 0, 0, errAztecMode
+	}
+	v := 0
+	for _, c := range codewords[:dataCount] {
+		v = v<<4 | c
+	}
+	if compact {
+		return v>>6 + 1, v&0x3f + 1, nil
+	}
+	return v>>11 + 1, v&0x7ff + 1, nil
+}
+
+// readBits returns the n bits from offset as an integer, the most significant bit first.
+func readBits(bits []bool, offset, n int) int {
+	v := 0
+	for i := offset; i < offset+n; i++ {
+		v <<= 1
+		if bits[i] {
+			v |= 1
+		}
+	}
+	return v
+}
+
+// decodeAztec decodes the Aztec code around the bullseye. The full range codes have two more
+// rings than the compact ones.
+func decodeAztec(image *bitMatrix, center aztecCenter) (string, error) {
+	err := errAztecMode
+	for _, compact := range []bool{false, true} {
+		k, radius := 5, 7
+		if compact {
+			k, radius = 3, 5
+		}
+		corners, ok := bullseyeCorners(image, center, k)
+		if !ok {
+			continue
+		}
+		fk := float64(k)
+		p, ok := newPerspective([4]point{{-fk, -fk}, {fk, -fk}, {fk, fk}, {-fk, fk}}, corners)
+		if !ok {
+			continue
+		}
+		for rotation := 0; rotation < 4; rotation++ {
+			s := &aztecSymbol{image: image, p: p, rotation: rotation}
+			if !s.oriented(radius) {
+				continue
+			}
+			var layers, dataWords int
+			if layers, dataWords, err = s.modeMessage(compact); err != nil {
+				continue
+			}
+			var content string
+			if content, err = s.decode(compact, layers, dataWords); err == nil {
+				return content, nil
+			}
+		}
+	}
+	return "", err
+}
+
+// decode reads the data layers around the core, and decodes the data words.
+func (s *aztecSymbol) decode(compact bool, layers, dataWords int) (string, error) {
+	if (compact && layers > 4) || layers > 32 {
+		return "", errAztecMode
+	}
+	baseSize := 14 + 4*layers
+	if compact {
+		baseSize = 11 + 4*layers
+	}
+	// the position of the modules from the center; the full range codes have a line of the
+	// reference grid every 16 modules, that does not hold data
+	alignment := make([]int, baseSize)
+	for i := range alignment {
+		alignment[i] = i - baseSize/2
+	}
+	if !compact {
+		for i := 0; i < baseSize/2; i++ {
+			offset := i + i/15 + 1
+			alignment[baseSize/2-i-1] = -offset
+			alignment[baseSize/2+i] = offset
+		}
+	}
+	get := func(x, y int) bool {
+		return s.get(alignment[x], alignment[y])
+	}
+	total := (112 + 16*layers) * layers
+	if compact {
+		total = (88 + 16*layers) * layers
+	}
+	raw := make([]bool, total)
+	for i, rowOffset := 0, 0; i < layers; i++ {
+		rowSize := (layers-i)*4 + 12
+		if compact {
+			rowSize = (layers-i)*4 + 9
+		}
+		low, high := 2*i, baseSize-1-2*i
+		for j := 0; j < rowSize; j++ {
+			for k := 0; k < 2; k++ {
+				raw[rowOffset+2*j+k] = get(low+k, low+j)
+				raw[rowOffset+2*rowSize+2*j+k] = get(low+j, high-k)
+				raw[rowOffset+4*rowSize+2*j+k] = get(high-k, high-j)
+				raw[rowOffset+6*rowSize+2*j+k] = get(high-j, low+k)
+			}
+		}
+		rowOffset += 8 * rowSize
+	}
+	bits, err := correctAztecBits(raw, layers, dataWords)
+	if err != nil {
+		return "", err
+	}
+	return decodeAztecBits(bits)
+}
+
+// correctAztecBits corrects the data words and removes the stuffed bits.
+func correctAztecBits(raw []bool, layers, dataWords int) ([]bool, error) {
+	size := 12
+	switch {
+	case layers <= 2:
+		size = 6
+	case layers <= 8:
+		size = 8
+	case layers <= 22:
+		size = 10
+	}
+	words := len(raw) / size
+	if words < dataWords {
+		return nil, errAztecMode
+	}
+	// the first bits of the layers are not used
+	offset := len(raw) % size
+	codewords := make([]int, words)
+	for i := range codewords {
+		codewords[i] = readBits(raw, offset+i*size, size)
+	}
+	if err := aztecFields[size].correct(codewords, words-dataWords); err != nil {
+		return nil, err
+	}
+	// a word made of the same bits is invalid, and the last bit of the words that are
+	// made of the same bits except the last one was added to prevent that
+	mask := 1<<uint(size) - 1
+	var bits []bool
+	for _, w := range codewords[:dataWords] {
+		switch w {
+		case 0, mask:
+			return nil, errBitstream
+		case 1, mask - 1:
+			for i := 0; i < size-1; i++ {
+				bits = append(bits, w > 1)
+			}
+		default:
+			for i := size - 1; i >= 0; i-- {
+				bits = append(bits, w&(1<<uint(i)) != 0)
+			}
+		}
+	}
+	return bits, nil
+}
+
+// the character tables of the Aztec modes; the values that are not characters switch to
+// another table, by a shift for the next character or by a latch
+const (
+	aztecUpper = iota
+	aztecLower
+	aztecMixed
+	aztecPunct
+	aztecDigit
+	aztecBinary
+)
+
+const (
+	aztecPS = -1 - iota
+	aztecPL
+	aztecUS
+	aztecUL
+	aztecLL
+	aztecML
+	aztecDL
+	aztecBS
+	aztecFLG
+)
+
+var aztecTables = [5][]string{
+	aztecUpper: {"", " ", "A", "B", "C", "D", "E", "F", "G", "H", "I", "J", "K", "L", "M", "N", "O", "P", "Q",
+		"R", "S", "T", "U", "V", "W", "X", "Y", "Z", "", "", "", ""},
+	aztecLower: {"", " ", "a", "b", "c", "d", "e", "f", "g", "h", "i", "j", "k", "l", "m", "n", "o", "p", "q",
+		"r", "s", "t", "u", "v", "w", "x", "y", "z", "", "", "", ""},
+	aztecMixed: {"", " ", "\x01", "\x02", "\x03", "\x04", "\x05", "\x06", "\x07", "\b", "\t", "\n", "\v", "\f",
+		"\r", "\x1b", "\x1c", "\x1d", "\x1e", "\x1f", "@", "\\", "^", "_", "`", "|", "~", "\x7f", "", "", "", ""},
+	aztecPunct: {"", "\r", "\r\n", ". ", ", ", ": ", "!", "\"", "#", "$", "%", "&", "'", "(", ")", "*", "+", ",",
+		"-", ".", "/", ":", ";", "<", "=", ">", "?", "[", "]", "{", "}", ""},
+	aztecDigit: {"", " ", "0", "1", "2", "3", "4", "5", "6", "7", "8", "9", ",", ".", "", ""},
+}
+
+// aztecControls are the codes of the tables that switch to another table.
+var aztecControls = [5]map[int]int{
+	aztecUpper: {0: aztecPS, 28: aztecLL, 29: aztecML, 30: aztecDL, 31: aztecBS},
+	aztecLower: {0: aztecPS, 28: aztecUS, 29: aztecML, 30: aztecDL, 31: aztecBS},
+	aztecMixed: {0: aztecPS, 28: aztecLL, 29: aztecUL, 30: aztecPL, 31: aztecBS},
+	aztecPunct: {0: aztecFLG, 31: aztecUL},
+	aztecDigit: {0: aztecPS, 14: aztecUL, 15: aztecUS},
+}
+
+// decodeAztecBits decodes the corrected data bits of an Aztec code.
+func decodeAztecBits(bits []bool) (string, error) {
+	var out []byte
+	var result string
+	eci := -1
+	latch, shift := aztecUpper, aztecUpper
+	pos := 0
+	read := func(n int) (int, bool) {
+		if pos+n > len(bits) {
+			return 0, false
+		}
+		pos += n
+		return readBits(bits, pos-n, n), true
+	}
+	for pos < len(bits) {
+		if shift == aztecBinary {
+			n, ok := read(5)
+			if !ok {
+				break
+			}
+			if n == 0 {
+				if n, ok = read(11); !ok {
+					break
+				}
+				n += 31
+			}
+			for i := 0; i < n; i++ {
+				c, ok := read(8)
+				if !ok {
+					break
+				}
+				out = append(out, byte(c))
+			}
+			shift = latch
+			continue
+		}
+		size := 5
+		if shift == aztecDigit {
+			size = 4
+		}
+		code, ok := read(size)
+		if !ok {
+			break
+		}
+		control, isControl := aztecControls[shift][code]
+		if !isControl {
+			out = append(out, aztecTables[shift][code]...)
+			shift = latch
+			continue
+		}
+		switch control {
+		case aztecFLG:
+			n, ok := read(3)
+			if !ok {
+				break
+			}
+			switch n {
+			case 0:
+				// FNC1
+				out = append(out, 0x1d)
+			case 7:
+				return "", errBitstream
+			default:
+				v := 0
+				for i := 0; i < n; i++ {
+					digit, ok := read(4)
+					if !ok || digit < 2 || digit > 11 {
+						return "", errBitstream
+					}
+					v = 10*v + digit - 2
+				}
+				result += decodeBytes(out, eci)
+				out = out[:0]
+				eci = v
+			}
+			shift = latch
+		default:
+			// the shifts return to the table they were made from
+			latch = shift
+			switch control {
+			case aztecPS, aztecPL:
+				shift = aztecPunct
+			case aztecUS, aztecUL:
+				shift = aztecUpper
+			case aztecLL:
+				shift = aztecLower
+			case aztecML:
+				shift = aztecMixed
+			case aztecDL:
+				shift = aztecDigit
+			case aztecBS:
+				shift = aztecBinary
+			}
+			if control == aztecPL || control == aztecUL || control == aztecLL || control == aztecML || control == aztecDL {
+				latch = shift
+			}
+		}
+	}
+	return result + decodeBytes(out, eci), nil
+}
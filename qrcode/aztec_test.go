@@ -0,0 +1,68 @@
+package qrcode
+
+import (
+	"testing"
+)
+
+func TestDecodeAztec(t *testing.T) {
+	long := "https://login.example.net/verify?account=john.doe%40example.org&session="
+	for i := 0; i < 8; i++ {
+		long += "a1b2c3d4e5f6"
+	}
+	cases := []struct {
+		file    string
+		content string
+	}{
+		{"aztec.png", "https://example.com/aztec"},
+		{"aztec_text.png", "Hello, World! 12345 café\n@home"},
+		{"aztec_full.png", long},
+		{"aztec_inverted.png", "https://example.com/inverted"},
+		{"aztec_rotated.png", "https://example.com/rotated"},
+		{"aztec_tilted.png", "https://example.com/tilted"},
+		{"aztec_damaged.png", "https://example.com/damaged"},
+	}
+	for _, c := range cases {
+		contents, err := Decode(readImage(t, c.file))
+		if err != nil {
+			t.Errorf("%s: %s", c.file, err)
+			continue
+		}
+		if len(contents) != 1 || contents[0] != c.content {
+			t.Errorf("%s: contents = %q, want %q", c.file, contents, c.content)
+		}
+	}
+}
+
+func bitString(s string) []bool {
+	var bits []bool
+	for _, c := range s {
+		if c == '0' || c == '1' {
+			bits = append(bits, c == '1')
+		}
+	}
+	return bits
+}
+
+func TestDecodeAztecBits(t *testing.T) {
+	cases := []struct {
+		name    string
+		bits    string
+		content string
+	}{
+		{"upper and lower", "01001 11100 01010", "Hi"},
+		{"punctuation pair", "01001 00000 00100 01010", "H, I"},
+		{"mixed latch", "11101 10100 11101 00010", "@A"},
+		{"eci and binary", "00000 00000 010 0100 1000 11111 00010 11000011 10101001 11110 0011", "é1"},
+		{"fnc1", "00000 00000 000 01001", "\x1dH"},
+	}
+	for _, c := range cases {
+		content, err := decodeAztecBits(bitString(c.bits))
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if content != c.content {
+			t.Errorf("%s: expected %q, got %q", c.name, c.content, content)
+		}
+	}
+}
@@ -0,0 +1,181 @@
+package qrcode
+
+import (
+	"image"
+)
+
+// bitMatrix is a 2D array of bits. true means a dark module or pixel.
+type bitMatrix struct {
+	width  int
+	height int
+	bits   []bool
+}
+
+func newBitMatrix(width, height int) *bitMatrix {
+	return &bitMatrix{
+		width:  width,
+		height: height,
+		bits:   make([]bool, width*height),
+	}
+}
+
+func (m *bitMatrix) get(x, y int) bool {
+	if x < 0 || y < 0 || x >= m.width || y >= m.height {
+		return false
+	}
+	return m.bits[y*m.width+x]
+}
+
+func (m *bitMatrix) set(x, y int, v bool) {
+	m.bits[y*m.width+x] = v
+}
+
+func (m *bitMatrix) flip(x, y int) {
+	m.bits[y*m.width+x] = !m.bits[y*m.width+x]
+}
+
+// setRegion marks the rectangle of the given size, starting at (left, top).
+func (m *bitMatrix) setRegion(left, top, width, height int) {
+	for y := top; y < top+height; y++ {
+		for x := left; x < left+width; x++ {
+			m.set(x, y, true)
+		}
+	}
+}
+
+func (m *bitMatrix) invert() *bitMatrix {
+	inv := newBitMatrix(m.width, m.height)
+	for i, b := range m.bits {
+		inv.bits[i] = !b
+	}
+	return inv
+}
+
+// rotate returns the matrix turned by 90 degrees clockwise.
+func (m *bitMatrix) rotate() *bitMatrix {
+	r := newBitMatrix(m.height, m.width)
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			r.set(m.height-1-y, x, m.get(x, y))
+		}
+	}
+	return r
+}
+
+// maxDimension is the size above which images are downscaled before binarization.
+var maxDimension = 1600
+
+// luminances converts img to an array of 8 bits luminance values. Transparent
+// pixels are composed over a white background.
+func luminances(img image.Image) (lum []uint8, width int, height int) {
+	bounds := img.Bounds()
+	scale := 1
+	for bounds.Dx()/scale > maxDimension || bounds.Dy()/scale > maxDimension {
+		scale++
+	}
+	width = bounds.Dx() / scale
+	height = bounds.Dy() / scale
+	lum = make([]uint8, width*height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			var total uint32
+			for dy := 0; dy < scale; dy++ {
+				for dx := 0; dx < scale; dx++ {
+					r, g, b, a := img.At(bounds.Min.X+x*scale+dx, bounds.Min.Y+y*scale+dy).RGBA()
+					l := (r*299 + g*587 + b*114) / 1000
+					// compose over white: l is premultiplied by alpha
+					l = l + (0xffff - a)
+					total += l >> 8
+				}
+			}
+			v := total / uint32(scale*scale)
+			if v > 255 {
+				v = 255
+			}
+			lum[y*width+x] = uint8(v)
+		}
+	}
+	return lum, width, height
+}
+
+// globalBinarize thresholds the luminances with Otsu's method.
+func globalBinarize(lum []uint8, width, height int) *bitMatrix {
+	var histogram [256]int
+	for _, l := range lum {
+		histogram[l]++
+	}
+	total := len(lum)
+	var sum float64
+	for i, h := range histogram {
+		sum += float64(i * h)
+	}
+	var sumB float64
+	var weightB int
+	var best float64
+	threshold := 127
+	for i, h := range histogram {
+		weightB += h
+		if weightB == 0 {
+			continue
+		}
+		weightF := total - weightB
+		if weightF == 0 {
+			break
+		}
+		sumB += float64(i * h)
+		meanB := sumB / float64(weightB)
+		meanF := (sum - sumB) / float64(weightF)
+		between := float64(weightB) * float64(weightF) * (meanB - meanF) * (meanB - meanF)
+		if between > best {
+			best = between
+			threshold = i
+		}
+	}
+	m := newBitMatrix(width, height)
+	for i, l := range lum {
+		m.bits[i] = int(l) <= threshold
+	}
+	return m
+}
+
+// localBinarize thresholds each pixel against the mean luminance of its neighbourhood.
+func localBinarize(lum []uint8, width, height int) *bitMatrix {
+	integral := make([]int, (width+1)*(height+1))
+	for y := 0; y < height; y++ {
+		rowSum := 0
+		for x := 0; x < width; x++ {
+			rowSum += int(lum[y*width+x])
+			integral[(y+1)*(width+1)+x+1] = integral[y*(width+1)+x+1] + rowSum
+		}
+	}
+	radius := width
+	if height < radius {
+		radius = height
+	}
+	radius = radius / 16
+	if radius < 7 {
+		radius = 7
+	}
+	m := newBitMatrix(width, height)
+	for y := 0; y < height; y++ {
+		y0, y1 := clamp(y-radius, 0, height), clamp(y+radius+1, 0, height)
+		for x := 0; x < width; x++ {
+			x0, x1 := clamp(x-radius, 0, width), clamp(x+radius+1, 0, width)
+			count := (x1 - x0) * (y1 - y0)
+			sum := integral[y1*(width+1)+x1] - integral[y0*(width+1)+x1] - integral[y1*(width+1)+x0] + integral[y0*(width+1)+x0]
+			// pixels must be noticeably darker than their surroundings
+			m.bits[y*width+x] = int(lum[y*width+x])*count*100 < sum*92
+		}
+	}
+	return m
+}
+
+func clamp(v, min, max int) int {
+	if v < min {
+		return min
+	}
+	if v > max {
+		return max
+	}
+	return v
+}
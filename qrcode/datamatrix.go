@@ -0,0 +1,685 @@
+package qrcode
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// Data Matrix (ECC 200) symbols are described in ISO/IEC 16022.
+
+// dataMatrixSize describes a Data Matrix symbol. The symbol is made of data regions, each one
+// bordered by the finder pattern on the left and bottom sides and by the timing pattern on the
+// top and right sides.
+type dataMatrixSize struct {
+	rows, cols             int
+	regionRows, regionCols int
+	dataCodewords          int
+	eccPerBlock            int
+	blocks                 int
+}
+
+var dataMatrixSizes = []dataMatrixSize{
+	{10, 10, 8, 8, 3, 5, 1},
+	{12, 12, 10, 10, 5, 7, 1},
+	{14, 14, 12, 12, 8, 10, 1},
+	{16, 16, 14, 14, 12, 12, 1},
+	{18, 18, 16, 16, 18, 14, 1},
+	{20, 20, 18, 18, 22, 18, 1},
+	{22, 22, 20, 20, 30, 20, 1},
+	{24, 24, 22, 22, 36, 24, 1},
+	{26, 26, 24, 24, 44, 28, 1},
+	{32, 32, 14, 14, 62, 36, 1},
+	{36, 36, 16, 16, 86, 42, 1},
+	{40, 40, 18, 18, 114, 48, 1},
+	{44, 44, 20, 20, 144, 56, 1},
+	{48, 48, 22, 22, 174, 68, 1},
+	{52, 52, 24, 24, 204, 42, 2},
+	{64, 64, 14, 14, 280, 56, 2},
+	{72, 72, 16, 16, 368, 36, 4},
+	{80, 80, 18, 18, 456, 48, 4},
+	{88, 88, 20, 20, 576, 56, 4},
+	{96, 96, 22, 22, 696, 68, 4},
+	{104, 104, 24, 24, 816, 56, 6},
+	{120, 120, 18, 18, 1050, 68, 6},
+	{132, 132, 20, 20, 1304, 62, 8},
+	{144, 144, 22, 22, 1558, 62, 10},
+	{8, 18, 6, 16, 5, 7, 1},
+	{8, 32, 6, 14, 10, 11, 1},
+	{12, 26, 10, 24, 16, 14, 1},
+	{12, 36, 10, 16, 22, 18, 1},
+	{16, 36, 14, 16, 32, 24, 1},
+	{16, 48, 14, 22, 49, 28, 1},
+}
+
+// pattern returns the color of the module at (x, y) when it belongs to the finder or to the
+// timing pattern of a data region.
+func (s dataMatrixSize) pattern(x, y int) (dark bool, ok bool) {
+	rx, ry := x%(s.regionCols+2), y%(s.regionRows+2)
+	switch {
+	case rx == 0 || ry == s.regionRows+1:
+		return true, true
+	case ry == 0:
+		return rx%2 == 0, true
+	case rx == s.regionCols+1:
+		return ry%2 == 1, true
+	}
+	return false, false
+}
+
+// dataMatrixCandidate is a possible Data Matrix symbol: its corners in the image and its size.
+type dataMatrixCandidate struct {
+	p     perspective
+	size  dataMatrixSize
+	score float64
+}
+
+// decodeDataMatrices returns the contents of the Data Matrix symbols found in image.
+func decodeDataMatrices(image *bitMatrix) []string {
+	var contents []string
+	for _, candidates := range detectDataMatrices(image) {
+		for _, c := range candidates {
+			grid, ok := sampleGrid(image, c.p, c.size.cols, c.size.rows)
+			if !ok {
+				continue
+			}
+			if content, err := decodeDataMatrix(grid, c.size); err == nil && content != "" {
+				contents = append(contents, content)
+				break
+			}
+		}
+	}
+	return contents
+}
+
+// maxDataMatrixRegions is the number of dark regions that are examined for the L shaped finder pattern.
+var maxDataMatrixRegions = 30
+
+// detectDataMatrices looks for the solid L of the finder pattern on the convex hull of the
+// largest dark regions, and returns the possible symbols for each L, best first.
+func detectDataMatrices(image *bitMatrix) [][]dataMatrixCandidate {
+	regions := darkRegions(image, 8)
+	sort.Slice(regions, func(i, j int) bool { return regions[i].count > regions[j].count })
+	if len(regions) > maxDataMatrixRegions {
+		regions = regions[:maxDataMatrixRegions]
+	}
+	var symbols [][]dataMatrixCandidate
+	for _, r := range regions {
+		for _, l := range findFinderL(image, r.hull()) {
+			if candidates := dataMatrixCandidates(image, l); len(candidates) > 0 {
+				symbols = append(symbols, candidates)
+			}
+		}
+	}
+	return symbols
+}
+
+// finderL is the L of a Data Matrix finder pattern: the corner, the end of the left side and the
+// end of the bottom side, when the symbol is upright.
+type finderL struct {
+	corner, top, right point
+}
+
+// hullSides merges the consecutive edges of the convex hull that are nearly aligned, to find the
+// straight sides of the shape.
+func hullSides(hull []point) [][2]point {
+	n := len(hull)
+	if n < 3 {
+		return nil
+	}
+	angle := func(i int) float64 {
+		a, b := hull[i%n], hull[(i+1)%n]
+		return math.Atan2(b.y-a.y, b.x-a.x)
+	}
+	turn := func(a, b float64) float64 {
+		d := math.Abs(b - a)
+		if d > math.Pi {
+			d = 2*math.Pi - d
+		}
+		return d
+	}
+	// start on the sharpest corner
+	start, sharpest := 0, -1.0
+	for i := 0; i < n; i++ {
+		if t := turn(angle(i+n-1), angle(i)); t > sharpest {
+			start, sharpest = i, t
+		}
+	}
+	const maxTurn = 20 * math.Pi / 180
+	var sides [][2]point
+	first := start
+	for i := start; i < start+n; i++ {
+		if i+1 == start+n || turn(angle(i), angle(i+1)) > maxTurn/2 || turn(angle(first), angle(i+1)) > maxTurn {
+			sides = append(sides, [2]point{hull[first%n], hull[(i+1)%n]})
+			first = i + 1
+		}
+	}
+	return sides
+}
+
+// findFinderL returns the L shapes made by two solid and perpendicular sides of the hull.
+func findFinderL(image *bitMatrix, hull []point) []finderL {
+	sides := hullSides(hull)
+	m := len(sides)
+	if m < 3 {
+		return nil
+	}
+	var found []finderL
+	for i := 0; i < m; i++ {
+		for gap := 1; gap <= 2; gap++ {
+			a, b := sides[i], sides[(i+gap)%m]
+			lenA, lenB := distance(a[0], a[1]), distance(b[0], b[1])
+			if lenA < 8 || lenB < 8 {
+				continue
+			}
+			if gap == 2 {
+				// a small cut corner between the two sides
+				between := sides[(i+1)%m]
+				if distance(between[0], between[1]) > 0.2*math.Min(lenA, lenB) {
+					continue
+				}
+			}
+			cos := ((a[1].x-a[0].x)*(b[1].x-b[0].x) + (a[1].y-a[0].y)*(b[1].y-b[0].y)) / (lenA * lenB)
+			if math.Abs(cos) > 0.26 {
+				continue
+			}
+			corner, ok := lineIntersection(a[0], a[1], b[0], b[1])
+			if !ok || distance(corner, a[1]) > 0.1*lenA+2 || distance(corner, b[0]) > 0.1*lenB+2 {
+				continue
+			}
+			p, q := a[0], b[1]
+			if !solidSide(image, corner, p, q) || !solidSide(image, corner, q, p) {
+				continue
+			}
+			// upright, the bottom side turns clockwise to the left side in image coordinates
+			if (q.x-corner.x)*(p.y-corner.y)-(q.y-corner.y)*(p.x-corner.x) < 0 {
+				found = append(found, finderL{corner: corner, top: p, right: q})
+			} else {
+				found = append(found, finderL{corner: corner, top: q, right: p})
+			}
+		}
+	}
+	return found
+}
+
+// solidSide tells if the pixels along the side from corner to end, just inside the shape
+// (on the side of other), are dark.
+func solidSide(image *bitMatrix, corner, end, other point) bool {
+	length := distance(corner, end)
+	ux, uy := (end.x-corner.x)/length, (end.y-corner.y)/length
+	nx, ny := -uy, ux
+	if nx*(other.x-corner.x)+ny*(other.y-corner.y) < 0 {
+		nx, ny = -nx, -ny
+	}
+	for _, inset := range []float64{0.75, 1.5, 2.5} {
+		dark, total := 0, 0
+		for t := 0.05 * length; t < 0.95*length; t++ {
+			total++
+			if image.get(int(corner.x+t*ux+inset*nx), int(corner.y+t*uy+inset*ny)) {
+				dark++
+			}
+		}
+		if float64(dark) >= 0.9*float64(total) {
+			return true
+		}
+	}
+	return false
+}
+
+// dataMatrixCandidates returns the sizes whose finder and timing patterns match the image
+// around the L, best first.
+func dataMatrixCandidates(image *bitMatrix, l finderL) []dataMatrixCandidate {
+	height, width := distance(l.corner, l.top), distance(l.corner, l.right)
+	opposite := point{l.top.x + l.right.x - l.corner.x, l.top.y + l.right.y - l.corner.y}
+	var candidates []dataMatrixCandidate
+	for _, size := range dataMatrixSizes {
+		rows, cols := float64(size.rows), float64(size.cols)
+		if width/cols < 1.5 || math.Abs(math.Log(width*rows/(height*cols))) > 0.2 {
+			continue
+		}
+		p, ok := newPerspective([4]point{{0, 0}, {cols, 0}, {cols, rows}, {0, rows}},
+			[4]point{l.top, opposite, l.right, l.corner})
+		if !ok {
+			continue
+		}
+		matches, total := 0, 0
+		for y := 0; y < size.rows; y++ {
+			for x := 0; x < size.cols; x++ {
+				dark, ok := size.pattern(x, y)
+				if !ok {
+					continue
+				}
+				total++
+				ix, iy := p.transform(float64(x)+0.5, float64(y)+0.5)
+				if image.get(int(ix), int(iy)) == dark {
+					matches++
+				}
+			}
+		}
+		if score := float64(matches) / float64(total); score >= 0.85 {
+			candidates = append(candidates, dataMatrixCandidate{p: p, size: size, score: score})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].score > candidates[j].score })
+	if len(candidates) > 3 {
+		candidates = candidates[:3]
+	}
+	return candidates
+}
+
+// decodeDataMatrix decodes the content of a sampled Data Matrix symbol.
+func decodeDataMatrix(grid *bitMatrix, size dataMatrixSize) (string, error) {
+	codewords := readDataMatrixCodewords(grid, size)
+	blocks := make([][]int, size.blocks)
+	for k, c := range codewords[:size.dataCodewords+size.eccPerBlock*size.blocks] {
+		blocks[k%size.blocks] = append(blocks[k%size.blocks], int(c))
+	}
+	data := make([]byte, size.dataCodewords)
+	for b, block := range blocks {
+		if err := dataMatrixField.correct(block, size.eccPerBlock); err != nil {
+			return "", err
+		}
+		for i := 0; i < len(block)-size.eccPerBlock; i++ {
+			data[i*size.blocks+b] = byte(block[i])
+		}
+	}
+	return decodeDataMatrixData(data)
+}
+
+// dataMatrixReader reads the codewords placed in the mapping matrix, which is the symbol
+// without the finder and the timing patterns.
+type dataMatrixReader struct {
+	grid       *bitMatrix
+	size       dataMatrixSize
+	rows, cols int
+	read       []bool
+}
+
+func (r *dataMatrixReader) module(row, col int) int {
+	if row < 0 {
+		row += r.rows
+		col += 4 - (r.rows+4)%8
+	}
+	if col < 0 {
+		col += r.cols
+		row += 4 - (r.cols+4)%8
+	}
+	if row >= r.rows {
+		row -= r.rows
+	}
+	r.read[row*r.cols+col] = true
+	x := col/r.size.regionCols*(r.size.regionCols+2) + col%r.size.regionCols + 1
+	y := row/r.size.regionRows*(r.size.regionRows+2) + row%r.size.regionRows + 1
+	if r.grid.get(x, y) {
+		return 1
+	}
+	return 0
+}
+
+// codeword reads the 8 modules of a codeword, the most significant bit first.
+func (r *dataMatrixReader) codeword(modules [8][2]int) byte {
+	v := 0
+	for _, m := range modules {
+		v = v<<1 | r.module(m[0], m[1])
+	}
+	return byte(v)
+}
+
+// readDataMatrixCodewords follows the diagonal placement of the codewords in the mapping matrix.
+func readDataMatrixCodewords(grid *bitMatrix, size dataMatrixSize) []byte {
+	rows := size.regionRows * (size.rows / (size.regionRows + 2))
+	cols := size.regionCols * (size.cols / (size.regionCols + 2))
+	r := &dataMatrixReader{grid: grid, size: size, rows: rows, cols: cols, read: make([]bool, rows*cols)}
+	total := rows * cols / 8
+	codewords := make([]byte, 0, total)
+	utah := func(row, col int) [8][2]int {
+		return [8][2]int{{row - 2, col - 2}, {row - 2, col - 1}, {row - 1, col - 2}, {row - 1, col - 1},
+			{row - 1, col}, {row, col - 2}, {row, col - 1}, {row, col}}
+	}
+	row, col := 4, 0
+	for (row < rows || col < cols) && len(codewords) < total {
+		switch {
+		case row == rows && col == 0:
+			codewords = append(codewords, r.codeword([8][2]int{{rows - 1, 0}, {rows - 1, 1}, {rows - 1, 2},
+				{0, cols - 2}, {0, cols - 1}, {1, cols - 1}, {2, cols - 1}, {3, cols - 1}}))
+		case row == rows-2 && col == 0 && cols%4 != 0:
+			codewords = append(codewords, r.codeword([8][2]int{{rows - 3, 0}, {rows - 2, 0}, {rows - 1, 0},
+				{0, cols - 4}, {0, cols - 3}, {0, cols - 2}, {0, cols - 1}, {1, cols - 1}}))
+		case row == rows-2 && col == 0 && cols%8 == 4:
+			codewords = append(codewords, r.codeword([8][2]int{{rows - 3, 0}, {rows - 2, 0}, {rows - 1, 0},
+				{0, cols - 2}, {0, cols - 1}, {1, cols - 1}, {2, cols - 1}, {3, cols - 1}}))
+		case row == rows+4 && col == 2 && cols%8 == 0:
+			codewords = append(codewords, r.codeword([8][2]int{{rows - 1, 0}, {rows - 1, cols - 1},
+				{0, cols - 3}, {0, cols - 2}, {0, cols - 1}, {1, cols - 3}, {1, cols - 2}, {1, cols - 1}}))
+		}
+		// up and to the right
+		for {
+			if row < rows && col >= 0 && !r.read[row*cols+col] && len(codewords) < total {
+				codewords = append(codewords, r.codeword(utah(row, col)))
+			}
+			row -= 2
+			col += 2
+			if row < 0 || col >= cols {
+				break
+			}
+		}
+		row++
+		col += 3
+		// down and to the left
+		for {
+			if row >= 0 && col < cols && !r.read[row*cols+col] && len(codewords) < total {
+				codewords = append(codewords, r.codeword(utah(row, col)))
+			}
+			row += 2
+			col -= 2
+			if row >= rows || col < 0 {
+				break
+			}
+		}
+		row += 3
+		col++
+	}
+	for len(codewords) < total {
+		codewords = append(codewords, 0)
+	}
+	return codewords
+}
+
+// the encodation schemes of the data codewords
+const (
+	dataMatrixASCII = iota
+	dataMatrixC40
+	dataMatrixText
+	dataMatrixX12
+	dataMatrixEDIFACT
+	dataMatrixBase256
+	dataMatrixEnd
+)
+
+const c40Punctuation = "!\"#$%&'()*+,-./:;<=>?@[\\]^_"
+
+type dataMatrixDecoder struct {
+	data       []byte
+	pos        int
+	out        strings.Builder
+	buf        []byte
+	eci        int
+	upperShift bool
+}
+
+// emit adds a character to the current ECI segment.
+func (d *dataMatrixDecoder) emit(c int) {
+	if d.upperShift {
+		c += 128
+		d.upperShift = false
+	}
+	d.buf = append(d.buf, byte(c))
+}
+
+func (d *dataMatrixDecoder) flush() {
+	d.out.WriteString(decodeBytes(d.buf, d.eci))
+	d.buf = d.buf[:0]
+}
+
+// decodeDataMatrixData decodes the data codewords of a Data Matrix symbol.
+func decodeDataMatrixData(data []byte) (string, error) {
+	d := &dataMatrixDecoder{data: data, eci: -1}
+	trailer := ""
+	mode := dataMatrixASCII
+	for mode != dataMatrixEnd && d.pos < len(d.data) {
+		var err error
+		switch mode {
+		case dataMatrixASCII:
+			mode, trailer, err = d.ascii(trailer)
+		case dataMatrixC40, dataMatrixText:
+			err = d.c40(mode == dataMatrixText)
+			mode = dataMatrixASCII
+		case dataMatrixX12:
+			err = d.x12()
+			mode = dataMatrixASCII
+		case dataMatrixEDIFACT:
+			d.edifact()
+			mode = dataMatrixASCII
+		case dataMatrixBase256:
+			err = d.base256()
+			mode = dataMatrixASCII
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	d.buf = append(d.buf, trailer...)
+	d.flush()
+	return d.out.String(), nil
+}
+
+// ascii decodes the codewords in the ASCII encodation, until a latch to another encodation.
+func (d *dataMatrixDecoder) ascii(trailer string) (int, string, error) {
+	for d.pos < len(d.data) {
+		c := int(d.data[d.pos])
+		d.pos++
+		switch {
+		case c == 0:
+			return 0, trailer, errBitstream
+		case c <= 128:
+			d.emit(c - 1)
+		case c == 129:
+			// padding
+			return dataMatrixEnd, trailer, nil
+		case c <= 229:
+			v := c - 130
+			d.buf = append(d.buf, byte('0'+v/10), byte('0'+v%10))
+		case c == 230:
+			return dataMatrixC40, trailer, nil
+		case c == 231:
+			return dataMatrixBase256, trailer, nil
+		case c == 232:
+			// FNC1 in first position only marks a GS1 symbol
+			if d.pos > 1 {
+				d.buf = append(d.buf, 0x1d)
+			}
+		case c == 233:
+			// structured append: the position of the symbol and the file identification
+			d.pos += 3
+		case c == 234:
+			// reader programming
+		case c == 235:
+			d.upperShift = true
+		case c == 236:
+			d.buf = append(d.buf, "[)>\x1e05\x1d"...)
+			trailer = "\x1e\x04" + trailer
+		case c == 237:
+			d.buf = append(d.buf, "[)>\x1e06\x1d"...)
+			trailer = "\x1e\x04" + trailer
+		case c == 238:
+			return dataMatrixX12, trailer, nil
+		case c == 239:
+			return dataMatrixText, trailer, nil
+		case c == 240:
+			return dataMatrixEDIFACT, trailer, nil
+		case c == 241:
+			eci, err := d.readECI()
+			if err != nil {
+				return 0, trailer, err
+			}
+			d.flush()
+			d.eci = eci
+		case c == 254:
+			// unlatch at the end of the data
+		default:
+			return 0, trailer, errBitstream
+		}
+	}
+	return dataMatrixEnd, trailer, nil
+}
+
+func (d *dataMatrixDecoder) next() (int, error) {
+	if d.pos >= len(d.data) {
+		return 0, errBitstream
+	}
+	d.pos++
+	return int(d.data[d.pos-1]), nil
+}
+
+// readECI reads the ECI number, on 1 to 3 codewords.
+func (d *dataMatrixDecoder) readECI() (int, error) {
+	c1, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	if c1 <= 127 {
+		return c1 - 1, nil
+	}
+	c2, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	if c1 <= 191 {
+		return (c1-128)*254 + c2 - 1 + 127, nil
+	}
+	c3, err := d.next()
+	if err != nil {
+		return 0, err
+	}
+	return (c1-192)*64516 + (c2-1)*254 + c3 - 1 + 16383, nil
+}
+
+// triplets calls f with the three values packed in each pair of codewords, until the unlatch
+// codeword or until less than two codewords remain.
+func (d *dataMatrixDecoder) triplets(f func(c int) error) error {
+	for d.pos+1 < len(d.data) {
+		if d.data[d.pos] == 254 {
+			d.pos++
+			return nil
+		}
+		v := int(d.data[d.pos])<<8 | int(d.data[d.pos+1]) - 1
+		d.pos += 2
+		if v >= 64000 {
+			return errBitstream
+		}
+		for _, c := range [3]int{v / 1600, v / 40 % 40, v % 40} {
+			if err := f(c); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// c40 decodes the C40 or the Text encodation: they only differ by the case of the letters.
+func (d *dataMatrixDecoder) c40(text bool) error {
+	shift := 0
+	return d.triplets(func(c int) error {
+		switch shift {
+		case 0:
+			switch {
+			case c < 3:
+				shift = c + 1
+				return nil
+			case c == 3:
+				d.emit(' ')
+			case c < 14:
+				d.emit('0' + c - 4)
+			case text:
+				d.emit('a' + c - 14)
+			default:
+				d.emit('A' + c - 14)
+			}
+		case 1:
+			d.emit(c)
+		case 2:
+			switch {
+			case c < len(c40Punctuation):
+				d.emit(int(c40Punctuation[c]))
+			case c == 27:
+				d.emit(0x1d)
+			case c == 30:
+				d.upperShift = true
+			default:
+				return errBitstream
+			}
+		case 3:
+			switch {
+			case !text:
+				d.emit(c + 96)
+			case c == 0:
+				d.emit('`')
+			case c < 27:
+				d.emit('A' + c - 1)
+			case c < 32:
+				d.emit(int("{|}~\x7f"[c-27]))
+			default:
+				return errBitstream
+			}
+		}
+		shift = 0
+		return nil
+	})
+}
+
+// x12 decodes the ANSI X12 encodation.
+func (d *dataMatrixDecoder) x12() error {
+	return d.triplets(func(c int) error {
+		switch {
+		case c < 3:
+			d.emit(int("\r*>"[c]))
+		case c == 3:
+			d.emit(' ')
+		case c < 14:
+			d.emit('0' + c - 4)
+		default:
+			d.emit('A' + c - 14)
+		}
+		return nil
+	})
+}
+
+// edifact decodes the EDIFACT encodation: four 6 bits values in three codewords.
+func (d *dataMatrixDecoder) edifact() {
+	r := &bitReader{data: d.data, pos: 8 * d.pos}
+	defer func() { d.pos = (r.pos + 7) / 8 }()
+	for r.available() > 16 {
+		for i := 0; i < 4; i++ {
+			v, _ := r.read(6)
+			if v == 0x1f {
+				// unlatch, the rest of the codeword is ignored
+				return
+			}
+			if v&0x20 == 0 {
+				v |= 0x40
+			}
+			d.emit(v)
+		}
+	}
+}
+
+// base256 decodes the Base 256 encodation. The codewords are randomized with their position.
+func (d *dataMatrixDecoder) base256() error {
+	next := func() (int, error) {
+		c, err := d.next()
+		return (c - (149*d.pos)%255 - 1 + 256) % 256, err
+	}
+	n, err := next()
+	if err != nil {
+		return err
+	}
+	switch {
+	case n == 0:
+		// until the end of the symbol
+		n = len(d.data) - d.pos
+	case n >= 250:
+		m, err := next()
+		if err != nil {
+			return err
+		}
+		n = 250*(n-249) + m
+	}
+	if d.pos+n > len(d.data) {
+		return errBitstream
+	}
+	for i := 0; i < n; i++ {
+		c, _ := next()
+		d.buf = append(d.buf, byte(c))
+	}
+	return nil
+}
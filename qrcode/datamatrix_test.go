@@ -0,0 +1,66 @@
+package qrcode
+
+import (
+	"testing"
+)
+
+func TestDecodeDataMatrix(t *testing.T) {
+	long := "https://login.example.net/verify?account=john.doe%40example.org&session="
+	for i := 0; i < 8; i++ {
+		long += "a1b2c3d4e5f6"
+	}
+	cases := []struct {
+		file    string
+		content string
+	}{
+		{"datamatrix.png", "https://example.com/datamatrix"},
+		{"datamatrix_rect.png", "INVOICE 2024-0042"},
+		{"datamatrix_large.png", long},
+		{"datamatrix_utf8.png", "naïve café"},
+		{"datamatrix_inverted.png", "https://example.com/inverted"},
+		{"datamatrix_rotated.png", "https://example.com/rotated"},
+		{"datamatrix_tilted.png", "https://example.com/tilted"},
+		{"datamatrix_damaged.png", "https://example.com/damaged"},
+	}
+	for _, c := range cases {
+		contents, err := Decode(readImage(t, c.file))
+		if err != nil {
+			t.Errorf("%s: %s", c.file, err)
+			continue
+		}
+		if len(contents) != 1 || contents[0] != c.content {
+			t.Errorf("%s: contents = %q, want %q", c.file, contents, c.content)
+		}
+	}
+}
+
+func TestDecodeDataMatrixData(t *testing.T) {
+	cases := []struct {
+		name      string
+		codewords []byte
+		content   string
+	}{
+		{"ascii", []byte{'A' + 1, 130 + 12, 130 + 34, 129, 200}, "A1234"},
+		{"c40", []byte{230, 108, 146, 88, 19, 92, 200, 143, 76, 38, 39, 254, '4' + 1}, "DATA MATRIX 2024"},
+		{"text", []byte{239, 134, 42, 160, 164, 16, 53, 197, 186}, "hello World"},
+		{"x12", []byte{238, 89, 218, 32, 51}, "AB*12>"},
+		{"edifact", []byte{240, 20, 66, 110, 199, 44, 223, '!' + 1}, "EDI.123!"},
+		{"base256", []byte{231, 47, 163, 217, 152, 'x' + 1}, "€x"},
+		{"upper shift", []byte{235, 0xe9 - 127}, "é"},
+		{"shift jis", []byte{241, 21, 235, 0x93 - 127, 235, 0xfa - 127}, "日"},
+		{"macro", []byte{236, 130 + 12}, "[)>\x1e05\x1d12\x1e\x04"},
+	}
+	for _, c := range cases {
+		content, err := decodeDataMatrixData(c.codewords)
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if content != c.content {
+			t.Errorf("%s: expected %q, got %q", c.name, c.content, content)
+		}
+	}
+	if _, err := decodeDataMatrixData([]byte{'A' + 1, 0}); err == nil {
+		t.Error("invalid codeword: expected an error")
+	}
+}
@@ -0,0 +1,375 @@
+package qrcode
+
+import (
+	"errors"
+	"math/bits"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/charmap"
+	"golang.org/x/text/encoding/japanese"
+)
+
+var errFormat = errors.New("invalid format information")
+var errBitstream = errors.New("invalid bitstream")
+
+// readFormat returns the error correction level and the data mask of the code.
+func readFormat(grid *bitMatrix) (level int, mask int, err error) {
+	dimension := grid.width
+	bit := func(v int, x, y int) int {
+		v <<= 1
+		if grid.get(x, y) {
+			v |= 1
+		}
+		return v
+	}
+	copy1 := 0
+	for x := 0; x < 6; x++ {
+		copy1 = bit(copy1, x, 8)
+	}
+	copy1 = bit(copy1, 7, 8)
+	copy1 = bit(copy1, 8, 8)
+	copy1 = bit(copy1, 8, 7)
+	for y := 5; y >= 0; y-- {
+		copy1 = bit(copy1, 8, y)
+	}
+	copy2 := 0
+	for y := dimension - 1; y >= dimension-7; y-- {
+		copy2 = bit(copy2, 8, y)
+	}
+	for x := dimension - 8; x < dimension; x++ {
+		copy2 = bit(copy2, x, 8)
+	}
+	best, bestDistance := -1, 4
+	for data, codeword := range formatCodewords {
+		for _, read := range []int{copy1, copy2} {
+			if d := bits.OnesCount(uint(read ^ codeword)); d < bestDistance {
+				best, bestDistance = data, d
+			}
+		}
+	}
+	if best == -1 {
+		return 0, 0, errFormat
+	}
+	return formatLevels[best>>3], best & 7, nil
+}
+
+// readCodewords unmasks the grid and reads the codewords in the zigzag order.
+func readCodewords(grid *bitMatrix, version int, mask int) []byte {
+	dimension := grid.width
+	function := functionPattern(version)
+	total := numRawCodewords(version)
+	result := make([]byte, 0, total)
+	var current byte
+	bitsRead := 0
+	up := true
+	for j := dimension - 1; j > 0; j -= 2 {
+		if j == 6 {
+			// skip the vertical timing pattern
+			j--
+		}
+		for count := 0; count < dimension; count++ {
+			i := count
+			if up {
+				i = dimension - 1 - count
+			}
+			for col := 0; col < 2; col++ {
+				x := j - col
+				if function.get(x, i) {
+					continue
+				}
+				current <<= 1
+				if grid.get(x, i) != masked(mask, i, x) {
+					current |= 1
+				}
+				bitsRead++
+				if bitsRead == 8 {
+					result = append(result, current)
+					if len(result) == total {
+						return result
+					}
+					current = 0
+					bitsRead = 0
+				}
+			}
+		}
+		up = !up
+	}
+	return result
+}
+
+// correctBlocks splits the codewords in blocks, corrects them, and returns the data codewords.
+func correctBlocks(codewords []byte, version int, level int) ([]byte, error) {
+	numBlocks := numErrorCorrectionBlocks[level][version]
+	numECC := eccCodewordsPerBlock[level][version]
+	total := numRawCodewords(version)
+	if len(codewords) != total {
+		return nil, errBitstream
+	}
+	numShortBlocks := numBlocks - total%numBlocks
+	shortBlockLen := total / numBlocks
+	shortDataLen := shortBlockLen - numECC
+	blocks := make([][]byte, numBlocks)
+	for j := range blocks {
+		length := shortBlockLen
+		if j >= numShortBlocks {
+			length++
+		}
+		blocks[j] = make([]byte, 0, length)
+	}
+	idx := 0
+	for i := 0; i <= shortBlockLen; i++ {
+		for j := 0; j < numBlocks; j++ {
+			if i == shortDataLen && j < numShortBlocks {
+				continue
+			}
+			blocks[j] = append(blocks[j], codewords[idx])
+			idx++
+		}
+	}
+	var data []byte
+	for _, block := range blocks {
+		if err := correctErrors(block, numECC); err != nil {
+			return nil, err
+		}
+		data = append(data, block[:len(block)-numECC]...)
+	}
+	return data, nil
+}
+
+type bitReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *bitReader) available() int {
+	return 8*len(r.data) - r.pos
+}
+
+func (r *bitReader) read(n int) (int, error) {
+	if n > r.available() {
+		return 0, errBitstream
+	}
+	v := 0
+	for i := 0; i < n; i++ {
+		v <<= 1
+		if r.data[r.pos/8]&(0x80>>uint(r.pos%8)) != 0 {
+			v |= 1
+		}
+		r.pos++
+	}
+	return v, nil
+}
+
+// countBits returns the size of the character count indicator of mode in version.
+func countBits(mode int, version int) int {
+	idx := 0
+	if version >= 27 {
+		idx = 2
+	} else if version >= 10 {
+		idx = 1
+	}
+	switch mode {
+	case 1:
+		return [3]int{10, 12, 14}[idx]
+	case 2:
+		return [3]int{9, 11, 13}[idx]
+	case 4:
+		return [3]int{8, 16, 16}[idx]
+	default:
+		return [3]int{8, 10, 12}[idx]
+	}
+}
+
+// decodeBitstream interprets the data codewords as a sequence of segments.
+func decodeBitstream(data []byte, version int) (string, error) {
+	r := &bitReader{data: data}
+	var result strings.Builder
+	eci := -1
+	for r.available() >= 4 {
+		mode, _ := r.read(4)
+		switch mode {
+		case 0:
+			return result.String(), nil
+		case 3:
+			// structured append: sequence number and parity
+			if _, err := r.read(16); err != nil {
+				return "", err
+			}
+		case 5:
+		case 9:
+			if _, err := r.read(8); err != nil {
+				return "", err
+			}
+		case 7:
+			v, err := readECI(r)
+			if err != nil {
+				return "", err
+			}
+			eci = v
+		case 1, 2, 4, 8:
+			count, err := r.read(countBits(mode, version))
+			if err != nil {
+				return "", err
+			}
+			var s string
+			switch mode {
+			case 1:
+				s, err = decodeNumeric(r, count)
+			case 2:
+				s, err = decodeAlphanumeric(r, count)
+			case 4:
+				s, err = decodeByte(r, count, eci)
+			default:
+				s, err = decodeKanji(r, count)
+			}
+			if err != nil {
+				return "", err
+			}
+			result.WriteString(s)
+		default:
+			return "", errBitstream
+		}
+	}
+	return result.String(), nil
+}
+
+func readECI(r *bitReader) (int, error) {
+	first, err := r.read(8)
+	if err != nil {
+		return 0, err
+	}
+	switch {
+	case first&0x80 == 0:
+		return first & 0x7f, nil
+	case first&0xc0 == 0x80:
+		second, err := r.read(8)
+		return (first&0x3f)<<8 | second, err
+	case first&0xe0 == 0xc0:
+		rest, err := r.read(16)
+		return (first&0x1f)<<16 | rest, err
+	}
+	return 0, errBitstream
+}
+
+func decodeNumeric(r *bitReader, count int) (string, error) {
+	var b strings.Builder
+	for count > 0 {
+		digits, size, modulo := 3, 10, 1000
+		if count == 2 {
+			digits, size, modulo = 2, 7, 100
+		} else if count == 1 {
+			digits, size, modulo = 1, 4, 10
+		}
+		v, err := r.read(size)
+		if err != nil {
+			return "", err
+		}
+		if v >= modulo {
+			return "", errBitstream
+		}
+		for d := modulo / 10; d > 0; d /= 10 {
+			b.WriteByte(byte('0' + v/d%10))
+		}
+		count -= digits
+	}
+	return b.String(), nil
+}
+
+func decodeAlphanumeric(r *bitReader, count int) (string, error) {
+	var b strings.Builder
+	for count > 1 {
+		v, err := r.read(11)
+		if err != nil {
+			return "", err
+		}
+		if v >= 45*45 {
+			return "", errBitstream
+		}
+		b.WriteByte(alphanumericChars[v/45])
+		b.WriteByte(alphanumericChars[v%45])
+		count -= 2
+	}
+	if count == 1 {
+		v, err := r.read(6)
+		if err != nil {
+			return "", err
+		}
+		if v >= 45 {
+			return "", errBitstream
+		}
+		b.WriteByte(alphanumericChars[v])
+	}
+	return b.String(), nil
+}
+
+func decodeByte(r *bitReader, count int, eci int) (string, error) {
+	buf := make([]byte, count)
+	for i := range buf {
+		v, err := r.read(8)
+		if err != nil {
+			return "", err
+		}
+		buf[i] = byte(v)
+	}
+	return decodeBytes(buf, eci), nil
+}
+
+// decodeBytes decodes the bytes in the character set designated by the ECI, or -1 when no ECI
+// was given: the bytes are then UTF-8 if they are valid, else ISO-8859-1.
+func decodeBytes(buf []byte, eci int) string {
+	switch {
+	case eci == 26 || (eci == -1 && utf8.Valid(buf)):
+		return string(buf)
+	case eci == 20:
+		return decodeShiftJIS(buf)
+	}
+	s, err := charmap.ISO8859_1.NewDecoder().Bytes(buf)
+	if err != nil {
+		return string(buf)
+	}
+	return string(s)
+}
+
+func decodeKanji(r *bitReader, count int) (string, error) {
+	buf := make([]byte, 0, 2*count)
+	for i := 0; i < count; i++ {
+		v, err := r.read(13)
+		if err != nil {
+			return "", err
+		}
+		assembled := (v/0xc0)<<8 | v%0xc0
+		if assembled < 0x1f00 {
+			assembled += 0x8140
+		} else {
+			assembled += 0xc140
+		}
+		buf = append(buf, byte(assembled>>8), byte(assembled))
+	}
+	return decodeShiftJIS(buf), nil
+}
+
+func decodeShiftJIS(buf []byte) string {
+	s, err := japanese.ShiftJIS.NewDecoder().Bytes(buf)
+	if err != nil {
+		return string(buf)
+	}
+	return string(s)
+}
+
+// decodeGrid decodes the content of a sampled QR code.
+func decodeGrid(grid *bitMatrix) (string, error) {
+	version := (grid.width - 17) / 4
+	if version < 1 || version > 40 {
+		return "", errFormat
+	}
+	level, mask, err := readFormat(grid)
+	if err != nil {
+		return "", err
+	}
+	data, err := correctBlocks(readCodewords(grid, version, mask), version, level)
+	if err != nil {
+		return "", err
+	}
+	return decodeBitstream(data, version)
+}
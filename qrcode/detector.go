@@ -0,0 +1,538 @@
+package qrcode
+
+import (
+	"math"
+	"sort"
+)
+
+type point struct {
+	x float64
+	y float64
+}
+
+func distance(a, b point) float64 {
+	return math.Hypot(a.x-b.x, a.y-b.y)
+}
+
+// finderPattern is a candidate for one of the three big squares in the corners of a QR code.
+type finderPattern struct {
+	point
+	moduleSize float64
+	count      int
+}
+
+// foundPatternCross checks that the run lengths match the 1:1:3:1:1 ratio of a finder pattern.
+func foundPatternCross(counts [5]int) bool {
+	total := 0
+	for _, c := range counts {
+		if c == 0 {
+			return false
+		}
+		total += c
+	}
+	if total < 7 {
+		return false
+	}
+	moduleSize := float64(total) / 7
+	maxVariance := moduleSize / 2
+	return math.Abs(moduleSize-float64(counts[0])) < maxVariance &&
+		math.Abs(moduleSize-float64(counts[1])) < maxVariance &&
+		math.Abs(3*moduleSize-float64(counts[2])) < 3*maxVariance &&
+		math.Abs(moduleSize-float64(counts[3])) < maxVariance &&
+		math.Abs(moduleSize-float64(counts[4])) < maxVariance
+}
+
+// centerFromEnd returns the center of the pattern, given the position just after its last run.
+func centerFromEnd(counts [5]int, end int) float64 {
+	return float64(end-counts[4]-counts[3]) - float64(counts[2])/2
+}
+
+type finderFinder struct {
+	image      *bitMatrix
+	candidates []*finderPattern
+}
+
+func findFinderPatterns(image *bitMatrix) []*finderPattern {
+	f := &finderFinder{image: image}
+	for y := 0; y < image.height; y++ {
+		var counts [5]int
+		state := 0
+		for x := 0; x < image.width; x++ {
+			if image.get(x, y) {
+				if state&1 == 1 {
+					state++
+				}
+				counts[state]++
+				continue
+			}
+			if state&1 == 1 {
+				counts[state]++
+				continue
+			}
+			if state != 4 {
+				state++
+				counts[state]++
+				continue
+			}
+			if foundPatternCross(counts) {
+				f.handlePossibleCenter(counts, y, x)
+			}
+			counts = [5]int{counts[2], counts[3], counts[4], 1, 0}
+			state = 3
+		}
+		if state == 4 && foundPatternCross(counts) {
+			f.handlePossibleCenter(counts, y, image.width)
+		}
+	}
+	return f.candidates
+}
+
+func (f *finderFinder) handlePossibleCenter(counts [5]int, y int, end int) {
+	total := counts[0] + counts[1] + counts[2] + counts[3] + counts[4]
+	centerX := centerFromEnd(counts, end)
+	centerY, ok := f.crossCheck(int(centerX), y, counts[2], total, false)
+	if !ok {
+		return
+	}
+	centerX, ok = f.crossCheck(int(centerX), int(centerY), counts[2], total, true)
+	if !ok {
+		return
+	}
+	moduleSize := float64(total) / 7
+	for _, c := range f.candidates {
+		if math.Abs(c.x-centerX) <= moduleSize && math.Abs(c.y-centerY) <= moduleSize {
+			diff := math.Abs(c.moduleSize - moduleSize)
+			if diff <= 1 || diff <= c.moduleSize {
+				n := float64(c.count)
+				c.x = (c.x*n + centerX) / (n + 1)
+				c.y = (c.y*n + centerY) / (n + 1)
+				c.moduleSize = (c.moduleSize*n + moduleSize) / (n + 1)
+				c.count++
+				return
+			}
+		}
+	}
+	f.candidates = append(f.candidates, &finderPattern{
+		point:      point{x: centerX, y: centerY},
+		moduleSize: moduleSize,
+		count:      1,
+	})
+}
+
+// crossCheck scans vertically (or horizontally) through (x, y) and returns the center of the finder pattern along that line.
+func (f *finderFinder) crossCheck(x, y int, centerCount int, originalTotal int, horizontal bool) (float64, bool) {
+	get := func(i int) bool {
+		if horizontal {
+			return f.image.get(i, y)
+		}
+		return f.image.get(x, i)
+	}
+	start, max := y, f.image.height
+	if horizontal {
+		start, max = x, f.image.width
+	}
+	maxCount := 2 * centerCount
+	var counts [5]int
+	i := start
+	for i >= 0 && get(i) {
+		counts[2]++
+		i--
+	}
+	if i < 0 {
+		return 0, false
+	}
+	for i >= 0 && !get(i) && counts[1] <= maxCount {
+		counts[1]++
+		i--
+	}
+	if i < 0 || counts[1] > maxCount {
+		return 0, false
+	}
+	for i >= 0 && get(i) && counts[0] <= maxCount {
+		counts[0]++
+		i--
+	}
+	if counts[0] > maxCount {
+		return 0, false
+	}
+	i = start + 1
+	for i < max && get(i) {
+		counts[2]++
+		i++
+	}
+	if i == max {
+		return 0, false
+	}
+	for i < max && !get(i) && counts[3] < maxCount {
+		counts[3]++
+		i++
+	}
+	if i == max || counts[3] >= maxCount {
+		return 0, false
+	}
+	for i < max && get(i) && counts[4] < maxCount {
+		counts[4]++
+		i++
+	}
+	if counts[4] >= maxCount {
+		return 0, false
+	}
+	total := counts[0] + counts[1] + counts[2] + counts[3] + counts[4]
+	if 5*abs(total-originalTotal) >= 2*originalTotal {
+		return 0, false
+	}
+	if !foundPatternCross(counts) {
+		return 0, false
+	}
+	return centerFromEnd(counts, i), true
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// selectBestPatterns returns the triplets of finder patterns that are the most likely to
+// be the corners of a QR code, best first. Each triplet is ordered as bottom-left, top-left, top-right.
+func selectBestPatterns(candidates []*finderPattern) [][3]*finderPattern {
+	if len(candidates) < 3 {
+		return nil
+	}
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].count > candidates[j].count })
+	if len(candidates) > 12 {
+		candidates = candidates[:12]
+	}
+	type scored struct {
+		patterns [3]*finderPattern
+		score    float64
+	}
+	var triplets []scored
+	for i := 0; i < len(candidates); i++ {
+		for j := i + 1; j < len(candidates); j++ {
+			for k := j + 1; k < len(candidates); k++ {
+				a, b, c := candidates[i], candidates[j], candidates[k]
+				minSize := math.Min(a.moduleSize, math.Min(b.moduleSize, c.moduleSize))
+				maxSize := math.Max(a.moduleSize, math.Max(b.moduleSize, c.moduleSize))
+				if maxSize > 1.4*minSize {
+					continue
+				}
+				d := []float64{distance(a.point, b.point), distance(b.point, c.point), distance(a.point, c.point)}
+				sort.Float64s(d)
+				if d[0] < 7*minSize {
+					continue
+				}
+				// the three patterns must form an isosceles right triangle
+				legs := math.Abs(d[1]-d[0]) / d[1]
+				hypotenuse := math.Abs(d[2]*d[2]-d[0]*d[0]-d[1]*d[1]) / (d[2] * d[2])
+				if legs > 0.25 || hypotenuse > 0.25 {
+					continue
+				}
+				triplets = append(triplets, scored{
+					patterns: orderPatterns(a, b, c),
+					score:    legs + hypotenuse + (maxSize-minSize)/maxSize,
+				})
+			}
+		}
+	}
+	sort.Slice(triplets, func(i, j int) bool { return triplets[i].score < triplets[j].score })
+	result := make([][3]*finderPattern, 0, len(triplets))
+	for _, t := range triplets {
+		result = append(result, t.patterns)
+	}
+	return result
+}
+
+// orderPatterns orders the patterns as bottom-left, top-left, top-right.
+func orderPatterns(a, b, c *finderPattern) [3]*finderPattern {
+	ab := distance(a.point, b.point)
+	bc := distance(b.point, c.point)
+	ac := distance(a.point, c.point)
+	var topLeft, p1, p2 *finderPattern
+	switch {
+	case bc >= ab && bc >= ac:
+		topLeft, p1, p2 = a, b, c
+	case ac >= bc && ac >= ab:
+		topLeft, p1, p2 = b, a, c
+	default:
+		topLeft, p1, p2 = c, a, b
+	}
+	// z component of the cross product tells on which side p2 is
+	if (p2.x-topLeft.x)*(p1.y-topLeft.y)-(p2.y-topLeft.y)*(p1.x-topLeft.x) < 0 {
+		p1, p2 = p2, p1
+	}
+	return [3]*finderPattern{p1, topLeft, p2}
+}
+
+// findAlignmentPatterns looks for the bottom-right alignment pattern around the estimated position.
+// It returns the candidates, nearest first.
+func findAlignmentPatterns(image *bitMatrix, estimated point, moduleSize float64) []point {
+	// the estimation is rough when the code is seen in perspective: widen the search if needed
+	for _, allowance := range []float64{8, 16} {
+		if candidates := searchAlignmentPatterns(image, estimated, moduleSize, int(allowance*moduleSize)); len(candidates) > 0 {
+			return candidates
+		}
+	}
+	return nil
+}
+
+func searchAlignmentPatterns(image *bitMatrix, estimated point, moduleSize float64, radius int) []point {
+	var candidates []point
+	for y := int(estimated.y) - radius; y <= int(estimated.y)+radius; y++ {
+		for x := int(estimated.x) - radius; x <= int(estimated.x)+radius; x++ {
+			if !image.get(x, y) {
+				continue
+			}
+			cx, ok := alignmentCross(image, x, y, moduleSize, true)
+			if !ok {
+				continue
+			}
+			cy, ok := alignmentCross(image, int(cx), y, moduleSize, false)
+			if !ok {
+				continue
+			}
+			p := point{x: cx, y: cy}
+			if !lightRing(image, p, moduleSize) {
+				continue
+			}
+			duplicate := false
+			for _, c := range candidates {
+				if distance(c, p) < moduleSize {
+					duplicate = true
+					break
+				}
+			}
+			if !duplicate {
+				candidates = append(candidates, p)
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		return distance(candidates[i], estimated) < distance(candidates[j], estimated)
+	})
+	return candidates
+}
+
+// lightRing checks that the points at one module from the center p are all light.
+func lightRing(image *bitMatrix, p point, moduleSize float64) bool {
+	for angle := 0.0; angle < 2*math.Pi; angle += math.Pi / 8 {
+		if image.get(int(p.x+moduleSize*math.Cos(angle)), int(p.y+moduleSize*math.Sin(angle))) {
+			return false
+		}
+	}
+	return true
+}
+
+// alignmentCross checks that the line through (x, y) crosses the dark center, the light ring and
+// the dark ring of an alignment pattern, and returns the center of the pattern along that line.
+// The 1:1:1 ratio of the runs is preserved whatever the rotation of the code.
+func alignmentCross(image *bitMatrix, x, y int, moduleSize float64, horizontal bool) (float64, bool) {
+	get := func(i int) bool {
+		if horizontal {
+			return image.get(i, y)
+		}
+		return image.get(x, i)
+	}
+	start := y
+	if horizontal {
+		start = x
+	}
+	maxRun := int(2*moduleSize) + 1
+	minRun := moduleSize / 2
+	run := func(from int, step int, dark bool) int {
+		n := 0
+		for i := from; get(i) == dark && n <= maxRun; i += step {
+			n++
+		}
+		return n
+	}
+	low := start - run(start, -1, true) + 1
+	high := start + run(start, 1, true) - 1
+	center := high - low + 1
+	lightBefore := run(low-1, -1, false)
+	lightAfter := run(high+1, 1, false)
+	darkBefore := run(low-1-lightBefore, -1, true)
+	darkAfter := run(high+1+lightAfter, 1, true)
+	for _, n := range []int{center, lightBefore, lightAfter} {
+		if n > maxRun || float64(n) < minRun {
+			return 0, false
+		}
+	}
+	if float64(darkBefore) < minRun || float64(darkAfter) < minRun {
+		return 0, false
+	}
+	return float64(low+high+1) / 2, true
+}
+
+// perspective maps module coordinates to image coordinates.
+type perspective [8]float64
+
+func (p perspective) transform(x, y float64) (float64, float64) {
+	d := p[6]*x + p[7]*y + 1
+	return (p[0]*x + p[1]*y + p[2]) / d, (p[3]*x + p[4]*y + p[5]) / d
+}
+
+// newPerspective computes the homography that maps the src points to the dst points.
+func newPerspective(src, dst [4]point) (perspective, bool) {
+	var a [8][9]float64
+	for i := 0; i < 4; i++ {
+		x, y, X, Y := src[i].x, src[i].y, dst[i].x, dst[i].y
+		a[2*i] = [9]float64{x, y, 1, 0, 0, 0, -x * X, -y * X, X}
+		a[2*i+1] = [9]float64{0, 0, 0, x, y, 1, -x * Y, -y * Y, Y}
+	}
+	// gaussian elimination with partial pivoting
+	for col := 0; col < 8; col++ {
+		pivot := col
+		for row := col + 1; row < 8; row++ {
+			if math.Abs(a[row][col]) > math.Abs(a[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(a[pivot][col]) < 1e-12 {
+			return perspective{}, false
+		}
+		a[col], a[pivot] = a[pivot], a[col]
+		for row := 0; row < 8; row++ {
+			if row == col {
+				continue
+			}
+			factor := a[row][col] / a[col][col]
+			for k := col; k < 9; k++ {
+				a[row][k] -= factor * a[col][k]
+			}
+		}
+	}
+	var p perspective
+	for i := 0; i < 8; i++ {
+		p[i] = a[i][8] / a[i][i]
+	}
+	return p, true
+}
+
+// sampleGrid reads the modules of a code with the given number of columns and rows.
+func sampleGrid(image *bitMatrix, p perspective, width, height int) (*bitMatrix, bool) {
+	grid := newBitMatrix(width, height)
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			ix, iy := p.transform(float64(x)+0.5, float64(y)+0.5)
+			if ix < -1 || iy < -1 || ix > float64(image.width)+1 || iy > float64(image.height)+1 {
+				return nil, false
+			}
+			grid.set(x, y, image.get(int(ix), int(iy)))
+		}
+	}
+	return grid, true
+}
+
+// detect returns the candidate module grids for the QR codes found in image.
+func detect(image *bitMatrix) []*bitMatrix {
+	var grids []*bitMatrix
+	for n, patterns := range selectBestPatterns(findFinderPatterns(image)) {
+		if n >= 5 {
+			break
+		}
+		bottomLeft, topLeft, topRight := patterns[0], patterns[1], patterns[2]
+		moduleSize := (moduleSizeAlong(image, topLeft.point, topRight.point, topLeft.moduleSize) +
+			moduleSizeAlong(image, topLeft.point, bottomLeft.point, topLeft.moduleSize) +
+			moduleSizeAlong(image, topRight.point, topLeft.point, topRight.moduleSize) +
+			moduleSizeAlong(image, bottomLeft.point, topLeft.point, bottomLeft.moduleSize)) / 4
+		estimated := int(math.Round((distance(topLeft.point, topRight.point)+distance(topLeft.point, bottomLeft.point))/(2*moduleSize))) + 7
+		for _, dimension := range candidateDimensions(estimated) {
+			grids = append(grids, sampleCode(image, bottomLeft, topLeft, topRight, moduleSize, dimension)...)
+		}
+	}
+	return grids
+}
+
+// moduleSizeAlong measures the module size of the finder pattern centered on from, in the direction of to.
+// The size measured by the row scans is too large when the code is rotated.
+func moduleSizeAlong(image *bitMatrix, from, to point, fallback float64) float64 {
+	d := distance(from, to)
+	if d == 0 {
+		return fallback
+	}
+	dx, dy := (to.x-from.x)/d, (to.y-from.y)/d
+	// from the center, cross the dark center, the light ring and the dark ring: 3.5 modules
+	run := func(dx, dy float64) float64 {
+		state := 0
+		for t := 0.0; t < 10*fallback; t += 0.25 {
+			dark := image.get(int(from.x+t*dx), int(from.y+t*dy))
+			if dark == (state%2 == 1) {
+				state++
+				if state == 3 {
+					return t
+				}
+			}
+		}
+		return -1
+	}
+	forward, backward := run(dx, dy), run(-dx, -dy)
+	if forward <= 0 || backward <= 0 {
+		return fallback
+	}
+	// the edges are on average half a step before the first light samples
+	size := (forward + backward - 0.25) / 7
+	if size < fallback/2 || size > 2*fallback {
+		return fallback
+	}
+	return size
+}
+
+// candidateDimensions lists the valid QR code dimensions close to the estimated one, nearest first.
+func candidateDimensions(estimated int) []int {
+	var dims []int
+	for d := estimated - 10; d <= estimated+10; d++ {
+		if d >= 21 && d <= 177 && d%4 == 1 {
+			dims = append(dims, d)
+		}
+	}
+	sort.Slice(dims, func(i, j int) bool { return abs(dims[i]-estimated) < abs(dims[j]-estimated) })
+	return dims
+}
+
+// maxAlignmentCandidates is the number of alignment pattern candidates tried for each code.
+var maxAlignmentCandidates = 3
+
+// sampleCode returns the module grids read with the possible positions of the bottom-right corner
+// of the code: the alignment pattern candidates, then the corner of the parallelogram built on the finder patterns.
+func sampleCode(image *bitMatrix, bottomLeft, topLeft, topRight *finderPattern, moduleSize float64, dimension int) []*bitMatrix {
+	dim := float64(dimension)
+	bottomRight := point{
+		x: topRight.x - topLeft.x + bottomLeft.x,
+		y: topRight.y - topLeft.y + bottomLeft.y,
+	}
+	src := [3]point{{3.5, 3.5}, {dim - 3.5, 3.5}, {3.5, dim - 3.5}}
+	dst := [3]point{topLeft.point, topRight.point, bottomLeft.point}
+	type corner struct {
+		src point
+		dst point
+	}
+	var corners []corner
+	if dimension > 21 {
+		// the bottom-right alignment pattern is 3 modules closer to the top-left corner than the finder patterns
+		correction := 1 - 3/(dim-7)
+		estimated := point{
+			x: topLeft.x + correction*(bottomRight.x-topLeft.x),
+			y: topLeft.y + correction*(bottomRight.y-topLeft.y),
+		}
+		for i, alignment := range findAlignmentPatterns(image, estimated, moduleSize) {
+			if i >= maxAlignmentCandidates {
+				break
+			}
+			corners = append(corners, corner{src: point{dim - 6.5, dim - 6.5}, dst: alignment})
+		}
+	}
+	corners = append(corners, corner{src: point{dim - 3.5, dim - 3.5}, dst: bottomRight})
+	var grids []*bitMatrix
+	for _, c := range corners {
+		p, ok := newPerspective([4]point{src[0], src[1], src[2], c.src}, [4]point{dst[0], dst[1], dst[2], c.dst})
+		if !ok {
+			continue
+		}
+		if grid, ok := sampleGrid(image, p, dimension, dimension); ok {
+			grids = append(grids, grid)
+		}
+	}
+	return grids
+}
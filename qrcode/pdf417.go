@@ -0,0 +1,504 @@
+package qrcode
+
+import (
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+// PDF417 symbols are described in ISO/IEC 15438. Each row of the symbol starts with the start
+// pattern and the left row indicator, and ends with the right row indicator and the stop pattern.
+// The rows use in turn the three clusters of bar patterns, so that a scan line that crosses two
+// rows is detected.
+
+// pdf417Word is a codeword read in the image: its value and its cluster, from 0 to 2.
+type pdf417Word struct {
+	value   int
+	cluster int
+}
+
+// pdf417Words maps the bar patterns to the codewords.
+var pdf417Words = func() map[uint32]pdf417Word {
+	words := make(map[uint32]pdf417Word, 3*929)
+	for cluster, patterns := range pdf417Patterns {
+		for value, pattern := range patterns {
+			words[pattern] = pdf417Word{value: value, cluster: cluster}
+		}
+	}
+	return words
+}()
+
+// the module widths of the bars and spaces of the start and the stop patterns
+var (
+	pdf417Start = []int{8, 1, 1, 1, 1, 1, 1, 3}
+	pdf417Stop  = []int{7, 1, 1, 3, 1, 1, 1, 2, 1}
+)
+
+// the mode latches and the other special codewords
+const (
+	pdf417Text         = 900
+	pdf417Byte         = 901
+	pdf417Numeric      = 902
+	pdf417ShiftByte    = 913
+	pdf417MacroField   = 922
+	pdf417MacroEnd     = 923
+	pdf417Byte6        = 924
+	pdf417ECIUser      = 925
+	pdf417ECIGeneral   = 926
+	pdf417ECICharset   = 927
+	pdf417MacroControl = 928
+)
+
+// decodePDF417Codes returns the contents of the PDF417 codes found in image, in the four orientations.
+func decodePDF417Codes(image *bitMatrix) []string {
+	var contents []string
+	for turn := 0; turn < 4; turn++ {
+		if turn > 0 {
+			image = image.rotate()
+		}
+		for _, s := range scanPDF417(image) {
+			if content, err := s.decode(); err == nil && content != "" {
+				contents = append(contents, content)
+			}
+		}
+	}
+	return contents
+}
+
+// runStarts returns the positions where the color changes on the row y, with 0 and the width.
+func runStarts(image *bitMatrix, y int) []int {
+	starts := []int{0}
+	for x := 1; x < image.width; x++ {
+		if image.get(x, y) != image.get(x-1, y) {
+			starts = append(starts, x)
+		}
+	}
+	return append(starts, image.width)
+}
+
+// matchRuns tells if the runs between the consecutive starts have the given widths in modules.
+func matchRuns(starts []int, widths []int) bool {
+	modules := 0
+	for _, w := range widths {
+		modules += w
+	}
+	unit := float64(starts[len(widths)]-starts[0]) / float64(modules)
+	for i, w := range widths {
+		if math.Abs(float64(starts[i+1]-starts[i])-float64(w)*unit) > 0.6*unit+0.5 {
+			return false
+		}
+	}
+	return true
+}
+
+// pdf417Line is the codewords read on a row of pixels across a symbol. The first one is the left
+// row indicator.
+type pdf417Line struct {
+	y      int
+	x      int
+	module float64
+	words  []pdf417Word
+	// stop is set when the stop pattern was found after the right row indicator
+	stop bool
+}
+
+// readPDF417Lines reads the codewords after each start pattern found on the row y.
+func readPDF417Lines(image *bitMatrix, y int) []pdf417Line {
+	starts := runStarts(image, y)
+	var lines []pdf417Line
+	for i := 0; i+8 < len(starts); i++ {
+		if !image.get(starts[i], y) || !matchRuns(starts[i:], pdf417Start) {
+			continue
+		}
+		module := float64(starts[i+8]-starts[i]) / 17
+		line := pdf417Line{y: y, x: starts[i], module: module}
+		j := i + 8
+		for j+8 < len(starts) {
+			if j+9 < len(starts) && matchRuns(starts[j:], pdf417Stop) {
+				line.stop = true
+				break
+			}
+			width := float64(starts[j+8] - starts[j])
+			if math.Abs(width-17*module) > 2.5*module {
+				break
+			}
+			var pattern uint32
+			for b := 0; b < 17; b++ {
+				pattern <<= 1
+				if image.get(int(float64(starts[j])+(float64(b)+0.5)*width/17), y) {
+					pattern |= 1
+				}
+			}
+			word, ok := pdf417Words[pattern]
+			if !ok {
+				word = pdf417Word{value: -1, cluster: -1}
+			}
+			line.words = append(line.words, word)
+			j += 8
+		}
+		if len(line.words) >= 2 {
+			lines = append(lines, line)
+		}
+		i = j - 1
+	}
+	return lines
+}
+
+// pdf417Symbol gathers the codewords read on the lines of a symbol.
+type pdf417Symbol struct {
+	x      int
+	lastY  int
+	module float64
+	// votes counts the values read for each row and column
+	votes    map[[2]int]map[int]int
+	columns  map[int]int
+	ecLevels map[int]int
+	rows     int
+}
+
+// scanPDF417 reads all the rows of pixels, and puts the lines that start at the same place together.
+func scanPDF417(image *bitMatrix) []*pdf417Symbol {
+	var symbols []*pdf417Symbol
+	for y := 0; y < image.height; y++ {
+		for _, line := range readPDF417Lines(image, y) {
+			var symbol *pdf417Symbol
+			for _, s := range symbols {
+				if math.Abs(float64(line.x-s.x)) <= 2*s.module && float64(line.y-s.lastY) <= 30*s.module {
+					symbol = s
+					break
+				}
+			}
+			if symbol == nil {
+				symbol = &pdf417Symbol{module: line.module, votes: make(map[[2]int]map[int]int),
+					columns: make(map[int]int), ecLevels: make(map[int]int)}
+				symbols = append(symbols, symbol)
+			}
+			symbol.x, symbol.lastY = line.x, line.y
+			symbol.add(line)
+		}
+	}
+	return symbols
+}
+
+// add counts the codewords of a line. The row indicators give the row number, and in turn the
+// number of columns and the error correction level.
+func (s *pdf417Symbol) add(line pdf417Line) {
+	counts := [3]int{}
+	for _, w := range line.words {
+		if w.cluster >= 0 {
+			counts[w.cluster]++
+		}
+	}
+	cluster := 0
+	for c := range counts {
+		if counts[c] > counts[cluster] {
+			cluster = c
+		}
+	}
+	words := line.words
+	var right pdf417Word
+	if line.stop {
+		s.columns[len(words)-2]++
+		right = words[len(words)-1]
+		words = words[:len(words)-1]
+	}
+	left := words[0]
+	row := -1
+	if left.cluster == cluster {
+		row = 3*(left.value/30) + cluster
+		switch cluster {
+		case 1:
+			s.ecLevels[left.value%30/3]++
+		case 2:
+			s.columns[left.value%30+1]++
+		}
+	}
+	if line.stop && right.cluster == cluster {
+		if row < 0 {
+			row = 3*(right.value/30) + cluster
+		}
+		switch cluster {
+		case 0:
+			s.columns[right.value%30+1]++
+		case 2:
+			s.ecLevels[right.value%30/3]++
+		}
+	}
+	if row < 0 {
+		return
+	}
+	if row+1 > s.rows {
+		s.rows = row + 1
+	}
+	for column, w := range words[1:] {
+		if w.cluster != cluster {
+			continue
+		}
+		key := [2]int{row, column}
+		if s.votes[key] == nil {
+			s.votes[key] = make(map[int]int)
+		}
+		s.votes[key][w.value]++
+	}
+}
+
+// best returns the value with the most votes, or -1.
+func best(votes map[int]int) int {
+	value, count := -1, 0
+	for v, c := range votes {
+		if c > count || (c == count && v < value) {
+			value, count = v, c
+		}
+	}
+	return value
+}
+
+// decode corrects the codewords and decodes them.
+func (s *pdf417Symbol) decode() (string, error) {
+	columns := best(s.columns)
+	if columns < 1 || s.rows < 1 {
+		return "", errBitstream
+	}
+	codewords := make([]int, s.rows*columns)
+	for i := range codewords {
+		if v := best(s.votes[[2]int{i / columns, i % columns}]); v >= 0 {
+			codewords[i] = v
+		}
+	}
+	// the level given by the row indicators first
+	levels := []int{8, 7, 6, 5, 4, 3, 2, 1, 0}
+	sort.SliceStable(levels, func(i, j int) bool { return s.ecLevels[levels[i]] > s.ecLevels[levels[j]] })
+	for _, level := range levels {
+		numECC := 2 << uint(level)
+		if numECC >= len(codewords) {
+			continue
+		}
+		block := append([]int(nil), codewords...)
+		if err := pdf417Field.correct(block, numECC); err != nil {
+			continue
+		}
+		// the symbol length descriptor counts the data codewords, itself included
+		if length := block[0]; length >= 1 && length <= len(block)-numECC {
+			return decodePDF417Data(block[:length])
+		}
+	}
+	return "", errTooManyErrors
+}
+
+// pdf417Decoder decodes the data codewords. The characters are collected as bytes in the
+// character set of the current ECI.
+type pdf417Decoder struct {
+	codewords []int
+	pos       int
+	out       strings.Builder
+	buf       []byte
+	eci       int
+}
+
+func (d *pdf417Decoder) setECI(eci int) {
+	d.out.WriteString(decodeBytes(d.buf, d.eci))
+	d.buf = d.buf[:0]
+	d.eci = eci
+}
+
+func (d *pdf417Decoder) next() int {
+	if d.pos >= len(d.codewords) {
+		return pdf417MacroControl
+	}
+	d.pos++
+	return d.codewords[d.pos-1]
+}
+
+// decodePDF417Data decodes the data codewords, the first one being the symbol length descriptor.
+func decodePDF417Data(codewords []int) (string, error) {
+	d := &pdf417Decoder{codewords: codewords, pos: 1, eci: -1}
+	for d.pos < len(d.codewords) {
+		var err error
+		switch code := d.next(); code {
+		case pdf417Text:
+			d.text()
+		case pdf417Byte, pdf417Byte6:
+			d.bytes(code)
+		case pdf417ShiftByte:
+			d.buf = append(d.buf, byte(d.next()))
+		case pdf417Numeric:
+			err = d.numeric()
+		case pdf417ECICharset:
+			d.setECI(d.next())
+		case pdf417ECIGeneral:
+			d.pos += 2
+		case pdf417ECIUser:
+			d.pos++
+		case pdf417MacroControl, pdf417MacroField, pdf417MacroEnd:
+			// the macro PDF417 control block ends the data
+			d.pos = len(d.codewords)
+		default:
+			if code >= 900 {
+				return "", errBitstream
+			}
+			// the text compaction is the default mode
+			d.pos--
+			d.text()
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	d.setECI(-1)
+	return d.out.String(), nil
+}
+
+// the text compaction submodes
+const (
+	pdf417Alpha = iota
+	pdf417Lower
+	pdf417Mixed
+	pdf417Punct
+	pdf417AlphaShift
+	pdf417PunctShift
+)
+
+const (
+	pdf417MixedChars = "0123456789&\r\t,:#-.$/+%*=^"
+	pdf417PunctChars = ";<>@[\\]_`~!\r\t,:\n-.$/\"|*()?{}'"
+)
+
+// text decodes the text compaction: two values from 0 to 29 in each codeword.
+func (d *pdf417Decoder) text() {
+	mode, prior := pdf417Alpha, pdf417Alpha
+	for d.pos < len(d.codewords) {
+		code := d.codewords[d.pos]
+		switch {
+		case code == pdf417Text:
+			d.pos++
+			mode = pdf417Alpha
+			continue
+		case code == pdf417ShiftByte:
+			d.pos++
+			d.buf = append(d.buf, byte(d.next()))
+			continue
+		case code >= 900:
+			return
+		}
+		d.pos++
+		for _, v := range [2]int{code / 30, code % 30} {
+			current := mode
+			if mode == pdf417AlphaShift || mode == pdf417PunctShift {
+				mode = prior
+			}
+			switch current {
+			case pdf417Alpha, pdf417AlphaShift:
+				switch {
+				case v < 26:
+					d.buf = append(d.buf, byte('A'+v))
+				case v == 26:
+					d.buf = append(d.buf, ' ')
+				case current == pdf417AlphaShift:
+				case v == 27:
+					mode = pdf417Lower
+				case v == 28:
+					mode = pdf417Mixed
+				case v == 29:
+					prior, mode = mode, pdf417PunctShift
+				}
+			case pdf417Lower:
+				switch {
+				case v < 26:
+					d.buf = append(d.buf, byte('a'+v))
+				case v == 26:
+					d.buf = append(d.buf, ' ')
+				case v == 27:
+					prior, mode = mode, pdf417AlphaShift
+				case v == 28:
+					mode = pdf417Mixed
+				case v == 29:
+					prior, mode = mode, pdf417PunctShift
+				}
+			case pdf417Mixed:
+				switch {
+				case v < len(pdf417MixedChars):
+					d.buf = append(d.buf, pdf417MixedChars[v])
+				case v == 25:
+					mode = pdf417Punct
+				case v == 26:
+					d.buf = append(d.buf, ' ')
+				case v == 27:
+					mode = pdf417Lower
+				case v == 28:
+					mode = pdf417Alpha
+				case v == 29:
+					prior, mode = mode, pdf417PunctShift
+				}
+			case pdf417Punct, pdf417PunctShift:
+				switch {
+				case v < len(pdf417PunctChars):
+					d.buf = append(d.buf, pdf417PunctChars[v])
+				case v == 29:
+					mode = pdf417Alpha
+				}
+			}
+		}
+	}
+}
+
+// bytes decodes the byte compaction: 6 bytes in each group of 5 codewords, and one byte in
+// each of the last codewords when their number is not a multiple of 5.
+func (d *pdf417Decoder) bytes(latch int) {
+	for d.pos < len(d.codewords) {
+		for d.pos < len(d.codewords) && d.codewords[d.pos] == pdf417ECICharset {
+			d.pos++
+			d.setECI(d.next())
+		}
+		n := 0
+		for d.pos+n < len(d.codewords) && n < 5 && d.codewords[d.pos+n] < 900 {
+			n++
+		}
+		if n == 0 {
+			return
+		}
+		// with the latch 901, a group of 5 codewords is 6 bytes only when more data follows
+		more := d.pos+n < len(d.codewords) && d.codewords[d.pos+n] < 900
+		if n == 5 && (latch == pdf417Byte6 || more) {
+			var v int64
+			for i := 0; i < 5; i++ {
+				v = 900*v + int64(d.next())
+			}
+			for i := 5; i >= 0; i-- {
+				d.buf = append(d.buf, byte(v>>(8*uint(i))))
+			}
+			continue
+		}
+		for i := 0; i < n; i++ {
+			d.buf = append(d.buf, byte(d.next()))
+		}
+		return
+	}
+}
+
+// numeric decodes the numeric compaction: each group of up to 15 codewords is a number in base
+// 900, whose decimal digits follow a leading 1.
+func (d *pdf417Decoder) numeric() error {
+	for d.pos < len(d.codewords) {
+		v := new(big.Int)
+		n := 0
+		for d.pos < len(d.codewords) && n < 15 && d.codewords[d.pos] < 900 {
+			v.Mul(v, big.NewInt(900))
+			v.Add(v, big.NewInt(int64(d.next())))
+			n++
+		}
+		if n == 0 {
+			if d.pos < len(d.codewords) && d.codewords[d.pos] == pdf417Numeric {
+				d.pos++
+				continue
+			}
+			return nil
+		}
+		digits := v.String()
+		if digits[0] != '1' {
+			return errBitstream
+		}
+		d.buf = append(d.buf, digits[1:]...)
+	}
+	return nil
+}
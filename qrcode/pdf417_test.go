@@ -0,0 +1,101 @@
+package qrcode
+
+import (
+	"testing"
+)
+
+func TestDecodePDF417(t *testing.T) {
+	long := "https://login.example.net/verify?account=john.doe%40example.org&session="
+	for i := 0; i < 8; i++ {
+		long += "a1b2c3d4e5f6"
+	}
+	cases := []struct {
+		file    string
+		content string
+	}{
+		{"pdf417.png", "https://example.com/pdf417"},
+		{"pdf417_text.png", "Hello, World! 12345678901234567890 café\n@home"},
+		{"pdf417_large.png", long},
+		{"pdf417_inverted.png", "https://example.com/inverted"},
+		{"pdf417_rotated.png", "https://example.com/rotated"},
+		{"pdf417_upsidedown.png", "https://example.com/upsidedown"},
+		{"pdf417_damaged.png", "https://example.com/damaged"},
+	}
+	for _, c := range cases {
+		contents, err := Decode(readImage(t, c.file))
+		if err != nil {
+			t.Errorf("%s: %s", c.file, err)
+			continue
+		}
+		if len(contents) != 1 || contents[0] != c.content {
+			t.Errorf("%s: contents = %q, want %q", c.file, contents, c.content)
+		}
+	}
+}
+
+func TestDecodePDF417Data(t *testing.T) {
+	cases := []struct {
+		name      string
+		codewords []int
+		content   string
+	}{
+		{"text", []int{5, 27, 58, 59, 329}, "Ab1!"},
+		{"numeric", []int{6, 902, 15, 369, 753, 190}, "1234567890"},
+		{"byte", []int{8, 901, 109, 326, 368, 127, 330, 'G'}, "ABCDEFG"},
+		{"byte 6", []int{7, 924, 109, 326, 368, 127, 330}, "ABCDEF"},
+		{"byte shift", []int{5, 27, 913, 'x', 56}, "Axb "},
+		{"eci", []int{6, 927, 26, 901, 195, 169}, "é"},
+		{"macro", []int{6, 0, 928, 111, 100, 0}, "AA"},
+	}
+	for _, c := range cases {
+		content, err := decodePDF417Data(c.codewords)
+		if err != nil {
+			t.Errorf("%s: %s", c.name, err)
+			continue
+		}
+		if content != c.content {
+			t.Errorf("%s: expected %q, got %q", c.name, c.content, content)
+		}
+	}
+}
+
+func TestCorrectPDF417(t *testing.T) {
+	f := pdf417Field
+	data := []int{9, 900, 27, 58, 59, 329, 902, 15, 369}
+	numECC := 8
+	// the remainder of the division of the data, times x^numECC, by the generator polynomial
+	generator := []int{1}
+	for i := 1; i <= numECC; i++ {
+		next := make([]int, len(generator)+1)
+		for j, g := range generator {
+			next[j] = f.add(next[j], g)
+			next[j+1] = f.sub(next[j+1], f.mul(g, f.pow(i)))
+		}
+		generator = next
+	}
+	remainder := append(append([]int(nil), data...), make([]int, numECC)...)
+	for i := 0; i < len(data); i++ {
+		factor := remainder[i]
+		for j, g := range generator {
+			remainder[i+j] = f.sub(remainder[i+j], f.mul(factor, g))
+		}
+	}
+	codewords := append([]int(nil), data...)
+	for _, r := range remainder[len(data):] {
+		codewords = append(codewords, f.sub(0, r))
+	}
+	received := append([]int(nil), codewords...)
+	received[1], received[4], received[10], received[15] = 0, 928, 17, 5
+	if err := f.correct(received, numECC); err != nil {
+		t.Fatal(err)
+	}
+	for i := range codewords {
+		if received[i] != codewords[i] {
+			t.Errorf("codeword %d: expected %d, got %d", i, codewords[i], received[i])
+		}
+	}
+	received[0], received[2], received[3], received[5], received[6] = 1, 2, 3, 4, 5
+	if err := f.correct(received, numECC); err == nil {
+		t.Error("5 errors: expected an error")
+	}
+}
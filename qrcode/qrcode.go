@@ -0,0 +1,59 @@
+// Package qrcode locates and decodes 2D barcodes in images.
+//
+// The supported symbologies are QR Code Model 2 (versions 1 to 40), Data Matrix
+// ECC 200 (square and rectangular), Aztec (compact and full range) and PDF417.
+// Micro QR codes, MaxiCode and Macro PDF417 control blocks are not recognized.
+package qrcode
+
+import (
+	"errors"
+	"image"
+)
+
+// ErrNotFound is returned when no barcode could be decoded in the image.
+var ErrNotFound = errors.New("no barcode found")
+
+// Decode returns the contents of the barcodes found in img.
+func Decode(img image.Image) ([]string, error) {
+	if img == nil {
+		return nil, ErrNotFound
+	}
+	lum, width, height := luminances(img)
+	if width < 21 || height < 21 {
+		return nil, ErrNotFound
+	}
+	binarizers := []func([]uint8, int, int) *bitMatrix{globalBinarize, localBinarize}
+	for _, binarize := range binarizers {
+		image := binarize(lum, width, height)
+		// also try the negative image, for light codes on a dark background
+		for _, candidate := range []*bitMatrix{image, image.invert()} {
+			var contents []string
+			seen := make(map[string]bool)
+			for _, decode := range decoders {
+				for _, content := range decode(candidate) {
+					if content != "" && !seen[content] {
+						seen[content] = true
+						contents = append(contents, content)
+					}
+				}
+			}
+			if len(contents) > 0 {
+				return contents, nil
+			}
+		}
+	}
+	return nil, ErrNotFound
+}
+
+// decoders find and decode each kind of barcode in a binarized image.
+var decoders = []func(*bitMatrix) []string{decodeQRCodes, decodeDataMatrices, decodeAztecCodes, decodePDF417Codes}
+
+func decodeQRCodes(image *bitMatrix) []string {
+	var contents []string
+	for _, grid := range detect(image) {
+		if content, err := decodeGrid(grid); err == nil {
+			contents = append(contents, content)
+		}
+	}
+	return contents
+}
@@ -0,0 +1,87 @@
+package qrcode
+
+import (
+	"image"
+	"image/draw"
+	_ "image/png"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func readImage(t *testing.T, name string) image.Image {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer f.Close()
+	img, _, err := image.Decode(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return img
+}
+
+func TestDecode(t *testing.T) {
+	long := "https://login.example.net/verify?account=john.doe%40example.org&session="
+	for i := 0; i < 12; i++ {
+		long += "a1b2c3d4e5f6"
+	}
+	cases := []struct {
+		file    string
+		content string
+	}{
+		{"url.png", "https://example.com/login"},
+		{"alphanumeric.png", "HELLO WORLD 12345"},
+		{"numeric.png", "0123456789012345678901234567890"},
+		{"large.png", long},
+		{"utf8.png", "Пароль: ключ — naïve café"},
+		{"inverted.png", "https://example.com/inverted"},
+		{"rotated.png", "https://example.com/rotated"},
+		{"margin.png", "https://example.com/margin"},
+		{"damaged.png", "https://example.com/damaged"},
+		{"lowcontrast.png", "https://example.com/lowcontrast"},
+	}
+	for _, c := range cases {
+		contents, err := Decode(readImage(t, c.file))
+		if err != nil {
+			t.Errorf("%s: %s", c.file, err)
+			continue
+		}
+		if len(contents) != 1 || contents[0] != c.content {
+			t.Errorf("%s: contents = %q, want %q", c.file, contents, c.content)
+		}
+	}
+}
+
+func TestDecodeTwoCodes(t *testing.T) {
+	left, right := readImage(t, "url.png"), readImage(t, "margin.png")
+	img := image.NewGray(image.Rect(0, 0, left.Bounds().Dx()+right.Bounds().Dx(), right.Bounds().Dy()))
+	draw.Draw(img, img.Bounds(), image.White, image.ZP, draw.Src)
+	draw.Draw(img, left.Bounds(), left, image.ZP, draw.Src)
+	draw.Draw(img, right.Bounds().Add(image.Pt(left.Bounds().Dx(), 0)), right, image.ZP, draw.Src)
+	contents, err := Decode(img)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := make(map[string]bool)
+	for _, c := range contents {
+		found[c] = true
+	}
+	if len(contents) != 2 || !found["https://example.com/login"] || !found["https://example.com/margin"] {
+		t.Errorf("contents = %q, want the two codes", contents)
+	}
+}
+
+func TestDecodeNotFound(t *testing.T) {
+	if _, err := Decode(readImage(t, "noise.png")); err != ErrNotFound {
+		t.Errorf("noise: err = %v, want ErrNotFound", err)
+	}
+	if _, err := Decode(image.NewGray(image.Rect(0, 0, 10, 10))); err != ErrNotFound {
+		t.Errorf("small image: err = %v, want ErrNotFound", err)
+	}
+	if _, err := Decode(nil); err != ErrNotFound {
+		t.Errorf("nil image: err = %v, want ErrNotFound", err)
+	}
+}
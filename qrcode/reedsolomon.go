@@ -0,0 +1,238 @@
+package qrcode
+
+import "errors"
+
+var errTooManyErrors = errors.New("too many errors to correct")
+
+// galoisField is the field of the codewords of a Reed-Solomon code: the polynomials over GF(2)
+// modulo a primitive polynomial, or the integers modulo a prime for PDF417.
+type galoisField struct {
+	size int
+	// prime is set for the integers modulo size
+	prime bool
+	exp   []int
+	log   []int
+	// base is the exponent of the first root of the generator polynomial
+	base int
+}
+
+// newBinaryField returns GF(size), size being a power of 2, built with the primitive polynomial.
+func newBinaryField(polynomial, size, base int) *galoisField {
+	f := &galoisField{size: size, exp: make([]int, 2*size), log: make([]int, size), base: base}
+	x := 1
+	for i := 0; i < size-1; i++ {
+		f.exp[i] = x
+		f.log[x] = i
+		x <<= 1
+		if x&size != 0 {
+			x ^= polynomial
+		}
+	}
+	for i := size - 1; i < 2*size; i++ {
+		f.exp[i] = f.exp[i-size+1]
+	}
+	return f
+}
+
+// newPrimeField returns the integers modulo prime, with the given primitive element.
+func newPrimeField(prime, generator, base int) *galoisField {
+	f := &galoisField{size: prime, prime: true, exp: make([]int, 2*prime), log: make([]int, prime), base: base}
+	x := 1
+	for i := 0; i < prime-1; i++ {
+		f.exp[i] = x
+		f.log[x] = i
+		x = x * generator % prime
+	}
+	for i := prime - 1; i < 2*prime; i++ {
+		f.exp[i] = f.exp[i-prime+1]
+	}
+	return f
+}
+
+// the fields of the supported barcodes
+var (
+	qrField         = newBinaryField(0x11d, 256, 0)
+	dataMatrixField = newBinaryField(0x12d, 256, 1)
+	aztecParamField = newBinaryField(0x13, 16, 1)
+	aztecFields     = map[int]*galoisField{
+		6:  newBinaryField(0x43, 64, 1),
+		8:  dataMatrixField,
+		10: newBinaryField(0x409, 1024, 1),
+		12: newBinaryField(0x1069, 4096, 1),
+	}
+	pdf417Field = newPrimeField(929, 3, 1)
+)
+
+func (f *galoisField) add(a, b int) int {
+	if f.prime {
+		return (a + b) % f.size
+	}
+	return a ^ b
+}
+
+func (f *galoisField) sub(a, b int) int {
+	if f.prime {
+		return (a - b + f.size) % f.size
+	}
+	return a ^ b
+}
+
+func (f *galoisField) mul(a, b int) int {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]+f.log[b]]
+}
+
+func (f *galoisField) div(a, b int) int {
+	if a == 0 {
+		return 0
+	}
+	return f.exp[f.log[a]+f.size-1-f.log[b]]
+}
+
+// pow returns alpha^e.
+func (f *galoisField) pow(e int) int {
+	e %= f.size - 1
+	if e < 0 {
+		e += f.size - 1
+	}
+	return f.exp[e]
+}
+
+// eval evaluates the polynomial p, lowest degree coefficient first, at x.
+func (f *galoisField) eval(p []int, x int) int {
+	y := 0
+	for i := len(p) - 1; i >= 0; i-- {
+		y = f.add(f.mul(y, x), p[i])
+	}
+	return y
+}
+
+// correct fixes in place the codewords of a block that ends with numECC error correction codewords.
+// The first codeword is the highest degree coefficient of the received polynomial.
+func (f *galoisField) correct(block []int, numECC int) error {
+	n := len(block)
+	for _, c := range block {
+		if c < 0 || c >= f.size {
+			return errTooManyErrors
+		}
+	}
+	syndromes := make([]int, numECC)
+	clean := true
+	for i := 0; i < numECC; i++ {
+		s := 0
+		x := f.pow(f.base + i)
+		for _, c := range block {
+			s = f.add(f.mul(s, x), c)
+		}
+		syndromes[i] = s
+		if s != 0 {
+			clean = false
+		}
+	}
+	if clean {
+		return nil
+	}
+
+	// Berlekamp-Massey: find the error locator polynomial
+	locator := []int{1}
+	previous := []int{1}
+	errorsCount := 0
+	shift := 1
+	lastDiscrepancy := 1
+	for k := 0; k < numECC; k++ {
+		d := syndromes[k]
+		for i := 1; i <= errorsCount && i < len(locator); i++ {
+			d = f.add(d, f.mul(locator[i], syndromes[k-i]))
+		}
+		if d == 0 {
+			shift++
+			continue
+		}
+		factor := f.div(d, lastDiscrepancy)
+		next := make([]int, maxInt(len(locator), len(previous)+shift))
+		copy(next, locator)
+		for i, c := range previous {
+			next[i+shift] = f.sub(next[i+shift], f.mul(factor, c))
+		}
+		if 2*errorsCount <= k {
+			previous = locator
+			errorsCount = k + 1 - errorsCount
+			lastDiscrepancy = d
+			shift = 1
+		} else {
+			shift++
+		}
+		locator = next
+	}
+	if 2*errorsCount > numECC {
+		return errTooManyErrors
+	}
+
+	// Chien search: the roots of the locator give the error positions
+	var positions []int
+	for degree := 0; degree < n; degree++ {
+		if f.eval(locator, f.pow(-degree)) == 0 {
+			positions = append(positions, degree)
+		}
+	}
+	if len(positions) != errorsCount {
+		return errTooManyErrors
+	}
+
+	// Forney: compute the error values
+	evaluator := make([]int, numECC)
+	for i := 0; i < numECC; i++ {
+		for j := 0; j <= i && j < len(locator); j++ {
+			evaluator[i] = f.add(evaluator[i], f.mul(locator[j], syndromes[i-j]))
+		}
+	}
+	// the formal derivative: i times the coefficient of degree i
+	derivative := make([]int, len(locator))
+	for i := 1; i < len(locator); i++ {
+		for j := 0; j < i%f.characteristic(); j++ {
+			derivative[i-1] = f.add(derivative[i-1], locator[i])
+		}
+	}
+	for _, degree := range positions {
+		xInv := f.pow(-degree)
+		denominator := f.eval(derivative, xInv)
+		if denominator == 0 {
+			return errTooManyErrors
+		}
+		magnitude := f.mul(f.pow(degree*(1-f.base)), f.div(f.eval(evaluator, xInv), denominator))
+		block[n-1-degree] = f.add(block[n-1-degree], magnitude)
+	}
+	return nil
+}
+
+// characteristic returns the number of times 1 must be added to itself to get 0.
+func (f *galoisField) characteristic() int {
+	if f.prime {
+		return f.size
+	}
+	return 2
+}
+
+// correctErrors fixes in place a block of QR code codewords.
+func correctErrors(block []byte, numECC int) error {
+	codewords := make([]int, len(block))
+	for i, c := range block {
+		codewords[i] = int(c)
+	}
+	if err := qrField.correct(codewords, numECC); err != nil {
+		return err
+	}
+	for i, c := range codewords {
+		block[i] = byte(c)
+	}
+	return nil
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
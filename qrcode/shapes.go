@@ -0,0 +1,191 @@
+package qrcode
+
+import (
+	"math"
+	"sort"
+)
+
+// region is a set of connected pixels of the same color. Only the horizontal extent of each
+// row is kept, which is enough to compute the convex hull.
+type region struct {
+	count int
+	// rows maps the row of the pixels to their leftmost and rightmost columns
+	rows map[int][2]int
+}
+
+// darkRegions returns the regions of 4-connected dark pixels that are at least minSize pixels
+// wide and high.
+func darkRegions(image *bitMatrix, minSize int) []*region {
+	visited := make([]bool, len(image.bits))
+	var regions []*region
+	var stack []int
+	for start, dark := range image.bits {
+		if !dark || visited[start] {
+			continue
+		}
+		r := &region{rows: make(map[int][2]int)}
+		minX, maxX := image.width, -1
+		visited[start] = true
+		stack = append(stack[:0], start)
+		for len(stack) > 0 {
+			i := stack[len(stack)-1]
+			stack = stack[:len(stack)-1]
+			px, py := i%image.width, i/image.width
+			r.add(px, py)
+			if px < minX {
+				minX = px
+			}
+			if px > maxX {
+				maxX = px
+			}
+			for _, n := range [4]int{i - 1, i + 1, i - image.width, i + image.width} {
+				if n < 0 || n >= len(image.bits) || (n == i-1 && px == 0) || (n == i+1 && px == image.width-1) {
+					continue
+				}
+				if image.bits[n] && !visited[n] {
+					visited[n] = true
+					stack = append(stack, n)
+				}
+			}
+		}
+		if maxX-minX+1 >= minSize && len(r.rows) >= minSize {
+			regions = append(regions, r)
+		}
+	}
+	return regions
+}
+
+// add adds the pixel (x, y) to the region.
+func (r *region) add(x, y int) {
+	r.count++
+	if extent, ok := r.rows[y]; !ok {
+		r.rows[y] = [2]int{x, x}
+	} else {
+		if x < extent[0] {
+			extent[0] = x
+		}
+		if x > extent[1] {
+			extent[1] = x
+		}
+		r.rows[y] = extent
+	}
+}
+
+// fill returns the region of the pixels of the same color as (x, y) that are 4-connected to it.
+// It gives up and returns nil when the region has more than limit pixels.
+func fill(image *bitMatrix, x, y int, limit int) *region {
+	if x < 0 || y < 0 || x >= image.width || y >= image.height {
+		return nil
+	}
+	color := image.get(x, y)
+	seen := make(map[int]bool)
+	r := &region{rows: make(map[int][2]int)}
+	stack := []int{y*image.width + x}
+	seen[stack[0]] = true
+	for len(stack) > 0 {
+		i := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		px, py := i%image.width, i/image.width
+		r.add(px, py)
+		if r.count > limit {
+			return nil
+		}
+		for _, n := range [4][2]int{{px - 1, py}, {px + 1, py}, {px, py - 1}, {px, py + 1}} {
+			if n[0] < 0 || n[1] < 0 || n[0] >= image.width || n[1] >= image.height {
+				continue
+			}
+			j := n[1]*image.width + n[0]
+			if !seen[j] && image.bits[j] == color {
+				seen[j] = true
+				stack = append(stack, j)
+			}
+		}
+	}
+	return r
+}
+
+// hull returns the convex hull of the pixel squares of the region.
+func (r *region) hull() []point {
+	points := make([]point, 0, 4*len(r.rows))
+	for y, extent := range r.rows {
+		left, right := float64(extent[0]), float64(extent[1]+1)
+		points = append(points, point{left, float64(y)}, point{left, float64(y + 1)},
+			point{right, float64(y)}, point{right, float64(y + 1)})
+	}
+	return convexHull(points)
+}
+
+// convexHull computes the convex hull of the points with the monotone chain algorithm.
+func convexHull(points []point) []point {
+	if len(points) < 3 {
+		return points
+	}
+	sort.Slice(points, func(i, j int) bool {
+		if points[i].x != points[j].x {
+			return points[i].x < points[j].x
+		}
+		return points[i].y < points[j].y
+	})
+	cross := func(o, a, b point) float64 {
+		return (a.x-o.x)*(b.y-o.y) - (a.y-o.y)*(b.x-o.x)
+	}
+	hull := make([]point, 0, 2*len(points))
+	for _, p := range points {
+		for len(hull) >= 2 && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	lower := len(hull) + 1
+	for i := len(points) - 2; i >= 0; i-- {
+		p := points[i]
+		for len(hull) >= lower && cross(hull[len(hull)-2], hull[len(hull)-1], p) <= 0 {
+			hull = hull[:len(hull)-1]
+		}
+		hull = append(hull, p)
+	}
+	return hull[:len(hull)-1]
+}
+
+// minAreaRect returns the corners of the smallest rectangle that contains the convex polygon,
+// clockwise in image coordinates. One side of that rectangle is along an edge of the polygon.
+func minAreaRect(hull []point) (corners [4]point, ok bool) {
+	if len(hull) < 3 {
+		return corners, false
+	}
+	best := math.Inf(1)
+	for i := range hull {
+		a, b := hull[i], hull[(i+1)%len(hull)]
+		length := distance(a, b)
+		if length == 0 {
+			continue
+		}
+		ux, uy := (b.x-a.x)/length, (b.y-a.y)/length
+		minU, maxU, minV, maxV := math.Inf(1), math.Inf(-1), math.Inf(1), math.Inf(-1)
+		for _, p := range hull {
+			u := (p.x-a.x)*ux + (p.y-a.y)*uy
+			v := -(p.x-a.x)*uy + (p.y-a.y)*ux
+			minU, maxU = math.Min(minU, u), math.Max(maxU, u)
+			minV, maxV = math.Min(minV, v), math.Max(maxV, v)
+		}
+		if area := (maxU - minU) * (maxV - minV); area < best {
+			best = area
+			at := func(u, v float64) point {
+				return point{a.x + u*ux - v*uy, a.y + u*uy + v*ux}
+			}
+			corners = [4]point{at(minU, minV), at(maxU, minV), at(maxU, maxV), at(minU, maxV)}
+			ok = true
+		}
+	}
+	return corners, ok
+}
+
+// lineIntersection returns the intersection of the line through a and b with the line through c and d.
+func lineIntersection(a, b, c, d point) (point, bool) {
+	denominator := (a.x-b.x)*(c.y-d.y) - (a.y-b.y)*(c.x-d.x)
+	if math.Abs(denominator) < 1e-9 {
+		return point{}, false
+	}
+	t := ((a.x-c.x)*(c.y-d.y) - (a.y-c.y)*(c.x-d.x)) / denominator
+	return point{a.x + t*(b.x-a.x), a.y + t*(b.y-a.y)}, true
+}
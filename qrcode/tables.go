@@ -0,0 +1,125 @@
+package qrcode
+
+// error correction levels, in the order used by the tables below
+const (
+	levelL = iota
+	levelM
+	levelQ
+	levelH
+)
+
+// formatLevels maps the two error correction bits of the format information to a level.
+var formatLevels = [4]int{levelM, levelL, levelH, levelQ}
+
+// number of error correction codewords in each block, by level and version
+var eccCodewordsPerBlock = [4][41]int{
+	{-1, 7, 10, 15, 20, 26, 18, 20, 24, 30, 18, 20, 24, 26, 30, 22, 24, 28, 30, 28, 28, 28, 28, 30, 30, 26, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 10, 16, 26, 18, 24, 16, 18, 22, 22, 26, 30, 22, 22, 24, 24, 28, 28, 26, 26, 26, 26, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28, 28},
+	{-1, 13, 22, 18, 26, 18, 24, 18, 22, 20, 24, 28, 26, 24, 20, 30, 24, 28, 28, 26, 30, 28, 30, 30, 30, 30, 28, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+	{-1, 17, 28, 22, 16, 22, 28, 26, 26, 24, 28, 24, 28, 22, 24, 24, 30, 28, 28, 26, 28, 30, 24, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30, 30},
+}
+
+// number of error correction blocks, by level and version
+var numErrorCorrectionBlocks = [4][41]int{
+	{-1, 1, 1, 1, 1, 1, 2, 2, 2, 2, 4, 4, 4, 4, 4, 6, 6, 6, 6, 7, 8, 8, 9, 9, 10, 12, 12, 12, 13, 14, 15, 16, 17, 18, 19, 19, 20, 21, 22, 24, 25},
+	{-1, 1, 1, 1, 2, 2, 4, 4, 4, 5, 5, 5, 8, 9, 9, 10, 10, 11, 13, 14, 16, 17, 17, 18, 20, 21, 23, 25, 26, 28, 29, 31, 33, 35, 37, 38, 40, 43, 45, 47, 49},
+	{-1, 1, 1, 2, 2, 4, 4, 6, 6, 8, 8, 8, 10, 12, 16, 12, 17, 16, 18, 21, 20, 23, 23, 25, 27, 29, 34, 34, 35, 38, 40, 43, 45, 48, 51, 53, 56, 59, 62, 65, 68},
+	{-1, 1, 1, 2, 4, 4, 4, 5, 6, 8, 8, 11, 11, 16, 16, 18, 16, 19, 21, 25, 25, 25, 34, 30, 32, 35, 37, 40, 42, 45, 48, 51, 54, 57, 60, 63, 66, 70, 74, 77, 81},
+}
+
+// numRawCodewords returns the number of codewords (data and error correction) of a version.
+func numRawCodewords(version int) int {
+	result := (16*version+128)*version + 64
+	if version >= 2 {
+		numAlign := version/7 + 2
+		result -= (25*numAlign-10)*numAlign - 55
+		if version >= 7 {
+			result -= 36
+		}
+	}
+	return result / 8
+}
+
+// alignmentPositions returns the coordinates of the centers of the alignment patterns of a version.
+func alignmentPositions(version int) []int {
+	if version == 1 {
+		return nil
+	}
+	numAlign := version/7 + 2
+	step := 26
+	if version != 32 {
+		step = (version*4 + numAlign*2 + 1) / (numAlign*2 - 2) * 2
+	}
+	positions := make([]int, numAlign)
+	positions[0] = 6
+	for i, pos := numAlign-1, version*4+17-7; i >= 1; i, pos = i-1, pos-step {
+		positions[i] = pos
+	}
+	return positions
+}
+
+// functionPattern marks the modules that do not carry data: finder patterns, separators,
+// format and version information, timing and alignment patterns.
+func functionPattern(version int) *bitMatrix {
+	dimension := version*4 + 17
+	m := newBitMatrix(dimension, dimension)
+	m.setRegion(0, 0, 9, 9)
+	m.setRegion(dimension-8, 0, 8, 9)
+	m.setRegion(0, dimension-8, 9, 8)
+	positions := alignmentPositions(version)
+	last := len(positions) - 1
+	for i, x := range positions {
+		for j, y := range positions {
+			if (i == 0 && j == 0) || (i == 0 && j == last) || (i == last && j == 0) {
+				continue
+			}
+			m.setRegion(x-2, y-2, 5, 5)
+		}
+	}
+	m.setRegion(6, 9, 1, dimension-17)
+	m.setRegion(9, 6, dimension-17, 1)
+	if version > 6 {
+		m.setRegion(dimension-11, 0, 3, 6)
+		m.setRegion(0, dimension-11, 6, 3)
+	}
+	return m
+}
+
+// masked reports whether the data mask flips the module at row i and column j.
+func masked(mask int, i, j int) bool {
+	switch mask {
+	case 0:
+		return (i+j)%2 == 0
+	case 1:
+		return i%2 == 0
+	case 2:
+		return j%3 == 0
+	case 3:
+		return (i+j)%3 == 0
+	case 4:
+		return (i/2+j/3)%2 == 0
+	case 5:
+		return (i*j)%2+(i*j)%3 == 0
+	case 6:
+		return ((i*j)%2+(i*j)%3)%2 == 0
+	default:
+		return ((i+j)%2+(i*j)%3)%2 == 0
+	}
+}
+
+// formatCodewords lists the 32 valid format information sequences, indexed by their 5 data bits.
+var formatCodewords [32]int
+
+func init() {
+	for data := 0; data < 32; data++ {
+		rem := data << 10
+		for bit := 14; bit >= 10; bit-- {
+			if rem&(1<<uint(bit)) != 0 {
+				rem ^= 0x537 << uint(bit-10)
+			}
+		}
+		formatCodewords[data] = (data<<10 | rem) ^ 0x5412
+	}
+}
+
+const alphanumericChars = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ $%*+-./:"
@@ -8,9 +8,16 @@ import (
 	"github.com/stephane-martin/mailstats/extractors"
 	"github.com/stephane-martin/mailstats/forwarders"
 	"github.com/stephane-martin/mailstats/logging"
+	"github.com/stephane-martin/mailstats/logos"
+	"github.com/stephane-martin/mailstats/bayes"
+	"github.com/stephane-martin/mailstats/dlp"
+	"github.com/stephane-martin/mailstats/dnsenrich"
+	"github.com/stephane-martin/mailstats/mailcrypto"
 	"github.com/stephane-martin/mailstats/parser"
 	"github.com/stephane-martin/mailstats/phishtank"
+	"github.com/stephane-martin/mailstats/threading"
 	"github.com/stephane-martin/mailstats/utils"
+	"github.com/stephane-martin/mailstats/vip"
 	"github.com/urfave/cli"
 	"go.uber.org/fx"
 )
@@ -30,6 +37,13 @@ func Builder(c *cli.Context, args *arguments.Args, invoke fx.Option, logger log1
 		utils.GeoIPService,
 		utils.RedisService,
 		phishtank.Service,
+		logos.Service,
+		mailcrypto.Service,
+		dnsenrich.Service,
+		vip.Service,
+		threading.Service,
+		dlp.Service,
+		bayes.Service,
 		fx.Provide(
 			func() *cli.Context { return c },
 			func() *arguments.Args { return args },
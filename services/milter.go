@@ -27,6 +27,7 @@ type milterMessage struct {
 	port    int
 	addr    string
 	helo    string
+	auth    string
 	from    string
 	to      []string
 	builder bytes.Buffer
@@ -38,6 +39,7 @@ func (m *milterMessage) Reset() {
 	m.port = 0
 	m.addr = ""
 	m.helo = ""
+	m.auth = ""
 	m.from = ""
 	m.to = make([]string, 0)
 	m.builder = bytes.Buffer{}
@@ -50,6 +52,7 @@ func (m *milterMessage) make() *models.IncomingMail {
 			Family:       m.family,
 			Port:         m.port,
 			Addr:         m.addr,
+			AuthUser:     m.auth,
 			MailFrom:     m.from,
 			RcptTo:       m.to,
 			TimeReported: time.Now(),
@@ -155,6 +158,8 @@ func (e *MilterImpl) Connect(host string, family string, port uint16, addr net.I
 
 func (e *MilterImpl) MailFrom(from string, m *milter.Modifier) (milter.Response, error) {
 	e.message.from = from
+	// the MTA sends the SASL login of the submitted mails in a macro
+	e.message.auth = m.Macros["{auth_authen}"]
 	return milter.RespContinue, nil
 }
 
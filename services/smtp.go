@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/stephane-martin/mailstats/arguments"
 	"github.com/stephane-martin/mailstats/collectors"
@@ -14,6 +15,7 @@ import (
 	"io"
 	"io/ioutil"
 	"net"
+	"sync"
 	"time"
 
 	"github.com/emersion/go-smtp"
@@ -27,6 +29,8 @@ type Backend struct {
 	Collector collectors.Collector
 	Forwarder forwarders.Forwarder
 	Logger    log15.Logger
+	// Addr is the address of the client, when the backend is bound to a connection
+	Addr string
 }
 
 func NewSMTPBackend(args *arguments.Args, collector collectors.Collector, forwarder forwarders.Forwarder, logger log15.Logger) smtp.Backend {
@@ -38,10 +42,20 @@ func NewSMTPBackend(args *arguments.Args, collector collectors.Collector, forwar
 	}
 }
 
+// ForClient returns a copy of the backend bound to the client at addr.
+func (b *Backend) ForClient(addr string) smtp.Backend {
+	c := *b
+	c.Addr = addr
+	return &c
+}
+
+// Login does not check the credentials: the users are not authenticated, and the mails are
+// reported as the anonymous ones.
 func (b *Backend) Login(username, password string) (smtp.User, error) {
 	b.Logger.Debug("Authenticated user")
 	return &User{
 		Port:      b.Port,
+		Addr:      b.Addr,
 		Collector: b.Collector,
 		Forwarder: b.Forwarder,
 		Logger:    b.Logger,
@@ -52,6 +66,7 @@ func (b *Backend) AnonymousLogin() (smtp.User, error) {
 	b.Logger.Debug("Anonymous user")
 	return &User{
 		Port:      b.Port,
+		Addr:      b.Addr,
 		Collector: b.Collector,
 		Forwarder: b.Forwarder,
 		Logger:    b.Logger,
@@ -60,6 +75,7 @@ func (b *Backend) AnonymousLogin() (smtp.User, error) {
 
 type User struct {
 	Port      int
+	Addr      string
 	Collector collectors.Collector
 	Forwarder forwarders.Forwarder
 	Logger    log15.Logger
@@ -77,7 +93,9 @@ func (u *User) Send(from string, to []string, r io.Reader) error {
 			MailFrom:     from,
 			RcptTo:       to,
 			TimeReported: time.Now(),
+			Family:       "smtp",
 			Port:         u.Port,
+			Addr:         u.Addr,
 		},
 		Data: b,
 	}
@@ -90,9 +108,17 @@ func (u *User) Logout() error {
 }
 
 type SMTPServer struct {
-	*smtp.Server
-	listener net.Listener
-	logger log15.Logger
+	Addr            string
+	backend         smtp.Backend
+	maxIdle         int
+	maxMessageBytes int
+	listener        net.Listener
+	logger          log15.Logger
+}
+
+// clientBackend is a backend that can be bound to the client of a connection.
+type clientBackend interface {
+	ForClient(addr string) smtp.Backend
 }
 
 func (s *SMTPServer) Name() string { return "SMTPServer" }
@@ -106,27 +132,118 @@ func (s *SMTPServer) Prestart() error {
 	return nil
 }
 
+// newServer returns a SMTP server that uses backend.
+func (s *SMTPServer) newServer(backend smtp.Backend) *smtp.Server {
+	server := smtp.NewServer(backend)
+	server.Addr = s.Addr
+	server.Domain = "localhost"
+	server.MaxIdleSeconds = s.maxIdle
+	server.MaxMessageBytes = s.maxMessageBytes
+	server.MaxRecipients = 0
+	server.AllowInsecureAuth = true
+	return server
+}
+
+// backendFor returns the backend for the client at addr.
+func (s *SMTPServer) backendFor(addr string) smtp.Backend {
+	if b, ok := s.backend.(clientBackend); ok {
+		return b.ForClient(addr)
+	}
+	return s.backend
+}
 
+// Start serves each connection with its own SMTP server, whose backend is bound to the address
+// of the client: the SMTP library gives neither the connection nor its address to the backends.
 func (s *SMTPServer) Start(ctx context.Context) error {
 	s.logger.Info("Start SMTP service", "listening", s.Addr)
+	var lock sync.Mutex
+	listeners := make(map[*connListener]bool)
 	go func() {
 		<-ctx.Done()
-		s.Server.Close()
+		_ = s.listener.Close()
+		lock.Lock()
+		for l := range listeners {
+			_ = l.conn.Close()
+		}
+		lock.Unlock()
 	}()
-	return s.Server.Serve(s.listener)
+
+	for {
+		c, err := s.listener.Accept()
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return err
+		}
+		host, _, _ := net.SplitHostPort(c.RemoteAddr().String())
+		l := newConnListener(c)
+		lock.Lock()
+		listeners[l] = true
+		lock.Unlock()
+		go func() {
+			_ = s.newServer(s.backendFor(host)).Serve(l)
+			lock.Lock()
+			delete(listeners, l)
+			lock.Unlock()
+		}()
+	}
+}
+
+// connListener is a listener that accepts a single connection, and is closed with it.
+type connListener struct {
+	conn   net.Conn
+	accept chan net.Conn
+	closed chan struct{}
+	once   sync.Once
+}
+
+func newConnListener(c net.Conn) *connListener {
+	l := &connListener{
+		accept: make(chan net.Conn, 1),
+		closed: make(chan struct{}),
+	}
+	l.conn = &listenedConn{Conn: c, listener: l}
+	l.accept <- l.conn
+	return l
+}
+
+func (l *connListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.accept:
+		return c, nil
+	case <-l.closed:
+		return nil, errors.New("connection closed")
+	}
+}
+
+func (l *connListener) Close() error {
+	l.once.Do(func() { close(l.closed) })
+	return nil
+}
+
+func (l *connListener) Addr() net.Addr {
+	return l.conn.LocalAddr()
+}
+
+// listenedConn closes its listener when it is closed.
+type listenedConn struct {
+	net.Conn
+	listener *connListener
+}
+
+func (c *listenedConn) Close() error {
+	_ = c.listener.Close()
+	return c.Conn.Close()
 }
 
 func NewSMTPService(args *arguments.Args, backend smtp.Backend, logger log15.Logger) *SMTPServer {
-	s := smtp.NewServer(backend)
-	s.Domain = "localhost"
-	s.MaxIdleSeconds = args.SMTP.MaxIdle
-	s.MaxMessageBytes = args.SMTP.MaxMessageSize
-	s.MaxRecipients = 0
-	s.AllowInsecureAuth = true
-	s.Addr = net.JoinHostPort(args.SMTP.ListenAddr, fmt.Sprintf("%d", args.SMTP.ListenPort))
 	return &SMTPServer{
-		Server: s,
-		logger: logger,
+		Addr:            net.JoinHostPort(args.SMTP.ListenAddr, fmt.Sprintf("%d", args.SMTP.ListenPort)),
+		backend:         backend,
+		maxIdle:         args.SMTP.MaxIdle,
+		maxMessageBytes: args.SMTP.MaxMessageSize,
+		logger:          logger,
 	}
 }
 
@@ -0,0 +1,134 @@
+package services
+
+import (
+	"context"
+	"net"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+type chanForwarder chan *models.IncomingMail
+
+func (f chanForwarder) Name() string { return "chanForwarder" }
+
+func (f chanForwarder) Forward(mail *models.IncomingMail) { f <- mail }
+
+// insecurePlain is the PLAIN authentication, without the TLS requirement of smtp.PlainAuth.
+type insecurePlain struct {
+	username, password string
+}
+
+func (a insecurePlain) Start(server *smtp.ServerInfo) (string, []byte, error) {
+	return "PLAIN", []byte("\x00" + a.username + "\x00" + a.password), nil
+}
+
+func (a insecurePlain) Next(fromServer []byte, more bool) ([]byte, error) {
+	return nil, nil
+}
+
+func startSMTPService(t *testing.T, listenAddr string) (*SMTPServer, chanForwarder, context.CancelFunc) {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	args := &arguments.Args{SMTP: arguments.SMTPArgs{ListenAddr: listenAddr, MaxIdle: 10, MaxMessageSize: 1 << 20}}
+	received := make(chanForwarder, 2)
+	backend := NewSMTPBackend(args, nil, received, logger)
+	s := NewSMTPService(args, backend, logger)
+	if err := s.Prestart(); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	go func() { _ = s.Start(ctx) }()
+	return s, received, cancel
+}
+
+func TestSMTPClientAddr(t *testing.T) {
+	s, received, cancel := startSMTPService(t, "127.0.0.1")
+	defer cancel()
+
+	addr := s.listener.Addr().String()
+	for i := 0; i < 2; i++ {
+		err := smtp.SendMail(addr, nil, "alice@example.org", []string{"bob@example.com"}, []byte("Subject: test\r\n\r\nhello\r\n"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		select {
+		case mail := <-received:
+			if mail.Addr != "127.0.0.1" {
+				t.Errorf("Addr = %q, want 127.0.0.1", mail.Addr)
+			}
+			if mail.Family != "smtp" {
+				t.Errorf("Family = %q, want smtp", mail.Family)
+			}
+			if mail.MailFrom != "alice@example.org" {
+				t.Errorf("MailFrom = %q", mail.MailFrom)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("no mail received")
+		}
+	}
+}
+
+// nonLoopbackIP returns an IPv4 address of the host that is not a loopback one.
+func nonLoopbackIP(t *testing.T) net.IP {
+	addrs, err := net.InterfaceAddrs()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, a := range addrs {
+		if n, ok := a.(*net.IPNet); ok && !n.IP.IsLoopback() && n.IP.To4() != nil {
+			return n.IP
+		}
+	}
+	return nil
+}
+
+func TestSMTPUncheckedLogin(t *testing.T) {
+	ip := nonLoopbackIP(t)
+	if ip == nil {
+		t.Skip("no network interface besides the loopback")
+	}
+	s, received, cancel := startSMTPService(t, ip.String())
+	defer cancel()
+
+	c, err := smtp.Dial(s.listener.Addr().String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	//noinspection GoUnhandledErrorResult
+	defer c.Close()
+	if err := c.Auth(insecurePlain{username: "ceo@example.org", password: "wrong"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Mail("ceo@example.org"); err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Rcpt("bob@example.com"); err != nil {
+		t.Fatal(err)
+	}
+	w, err := c.Data()
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = w.Write([]byte("Subject: wire transfer\r\n\r\nhello\r\n"))
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	_ = c.Quit()
+
+	select {
+	case mail := <-received:
+		if mail.Addr != ip.String() {
+			t.Errorf("Addr = %q, want %s", mail.Addr, ip)
+		}
+		if mail.AuthUser != "" {
+			t.Errorf("AuthUser = %q for unchecked credentials", mail.AuthUser)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("no mail received")
+	}
+}
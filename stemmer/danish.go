@@ -0,0 +1,55 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+var danishVowel = vowels("aeiouyæåø")
+
+// danish implements the snowball Danish stemmer.
+func danish(s string) string {
+	w := newWord(s)
+	w.standardRegions(danishVowel)
+	if w.r1 < 3 {
+		w.r1 = 3
+		w.clamp()
+	}
+
+	// the suffixes of the three steps are searched inside R1
+	switch suffix := w.longestIn(w.r1, "hed", "ethed", "ered", "e", "erede", "ende", "erende", "ene", "erne", "ere", "en",
+		"heden", "eren", "er", "heder", "erer", "heds", "es", "endes", "erendes", "enes", "ernes", "eres", "ens",
+		"hedens", "erens", "ers", "ets", "erets", "et", "eret", "s"); suffix {
+	case "":
+	case "s":
+		if strings.ContainsRune("abcdfghjklmnoprtvyzå", w.before(suffix)) {
+			w.remove(suffix)
+		}
+	default:
+		w.remove(suffix)
+	}
+
+	consonantPair := func() {
+		if w.longestIn(w.r1, "gd", "dt", "gt", "kt") != "" {
+			w.rs = w.rs[:len(w.rs)-1]
+			w.clamp()
+		}
+	}
+	consonantPair()
+
+	if w.hasSuffix("igst") {
+		w.remove("st")
+	}
+	switch suffix := w.longestIn(w.r1, "ig", "lig", "elig", "els", "løst"); suffix {
+	case "":
+	case "løst":
+		w.replace(suffix, "løs")
+	default:
+		w.remove(suffix)
+		consonantPair()
+	}
+
+	if n := len(w.rs); n >= 2 && n-1 >= w.r1 && w.rs[n-1] == w.rs[n-2] && !danishVowel(w.rs[n-1]) {
+		w.rs = w.rs[:n-1]
+	}
+	return w.String()
+}
@@ -0,0 +1,112 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+var dutchVowel = vowels("aeiouyè")
+
+// dutch implements the snowball Dutch stemmer.
+func dutch(s string) string {
+	s = strings.NewReplacer("ä", "a", "ë", "e", "ï", "i", "ö", "o", "ü", "u", "á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u").Replace(s)
+	w := newWord(s)
+	// the initial y, the y after a vowel and the i between vowels are consonants
+	for i, r := range w.rs {
+		switch {
+		case r == 'y' && (i == 0 || dutchVowel(w.rs[i-1])):
+			w.rs[i] = 'Y'
+		case r == 'i' && i > 0 && i < len(w.rs)-1 && dutchVowel(w.rs[i-1]) && dutchVowel(w.rs[i+1]):
+			w.rs[i] = 'I'
+		}
+	}
+	w.standardRegions(dutchVowel)
+	if w.r1 < 3 {
+		w.r1 = 3
+		w.clamp()
+	}
+
+	validEnEnding := func(suffix string) bool {
+		r := w.before(suffix)
+		stem := string(w.rs[:len(w.rs)-len([]rune(suffix))])
+		return r != 0 && !dutchVowel(r) && !strings.HasSuffix(stem, "gem")
+	}
+	undouble := func() {
+		if w.longest("kk", "dd", "tt") != "" {
+			w.rs = w.rs[:len(w.rs)-1]
+			w.clamp()
+		}
+	}
+	removeEnding := func(suffix string) {
+		if w.in(w.r1, suffix) && validEnEnding(suffix) {
+			w.remove(suffix)
+			undouble()
+		}
+	}
+	eRemoved := false
+	removeE := func() {
+		if w.hasSuffix("e") && w.in(w.r1, "e") && w.before("e") != 0 && !dutchVowel(w.before("e")) {
+			w.remove("e")
+			eRemoved = true
+			undouble()
+		}
+	}
+
+	switch suffix := w.longest("heden", "en", "ene", "s", "se"); suffix {
+	case "heden":
+		if w.in(w.r1, suffix) {
+			w.replace(suffix, "heid")
+		}
+	case "en", "ene":
+		removeEnding(suffix)
+	case "s", "se":
+		r := w.before(suffix)
+		if w.in(w.r1, suffix) && r != 0 && !dutchVowel(r) && r != 'j' {
+			w.remove(suffix)
+		}
+	}
+
+	removeE()
+
+	if w.hasSuffix("heid") && w.in(w.r2, "heid") && w.before("heid") != 'c' {
+		w.remove("heid")
+		if w.hasSuffix("en") {
+			removeEnding("en")
+		}
+	}
+
+	switch suffix := w.longest("end", "ing", "ig", "lijk", "baar", "bar"); suffix {
+	case "end", "ing":
+		if w.removeIn(w.r2, suffix) {
+			if w.hasSuffix("ig") && w.in(w.r2, "ig") && w.before("ig") != 'e' {
+				w.remove("ig")
+			} else {
+				undouble()
+			}
+		}
+	case "ig":
+		if w.before(suffix) != 'e' {
+			w.removeIn(w.r2, suffix)
+		}
+	case "lijk":
+		if w.removeIn(w.r2, suffix) {
+			removeE()
+		}
+	case "baar":
+		w.removeIn(w.r2, suffix)
+	case "bar":
+		if eRemoved {
+			w.removeIn(w.r2, suffix)
+		}
+	}
+
+	// a double vowel between two consonants is undoubled
+	if n := len(w.rs); n >= 4 {
+		c, v, d := w.rs[n-4], w.rs[n-3], w.rs[n-1]
+		if !dutchVowel(c) && v == w.rs[n-2] && strings.ContainsRune("aeou", v) && !dutchVowel(d) && d != 'I' {
+			w.rs = append(w.rs[:n-2], d)
+			w.clamp()
+		}
+	}
+
+	return strings.NewReplacer("I", "i", "Y", "y").Replace(w.String())
+}
@@ -0,0 +1,177 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+var finnishVowel = vowels("aeiouyäö")
+
+// the vowels that are followed by the i of the plural
+var finnishV2 = vowels("aeiouäö")
+
+// finnish implements the snowball Finnish stemmer.
+func finnish(s string) string {
+	w := newWord(s)
+	w.standardRegions(finnishVowel)
+
+	// at returns the letter at i, or 0 outside the word
+	at := func(i int) rune {
+		if i < 0 || i >= len(w.rs) {
+			return 0
+		}
+		return w.rs[i]
+	}
+	// vi reports whether the suffix is preceded by an i that follows a vowel
+	vi := func(suffix string) bool {
+		i := len(w.rs) - len([]rune(suffix))
+		return at(i-1) == 'i' && finnishV2(at(i-2))
+	}
+	// long reports whether the suffix is preceded by a long vowel
+	long := func(suffix string) bool {
+		i := len(w.rs) - len([]rune(suffix))
+		return at(i-1) != 0 && at(i-1) == at(i-2) && strings.ContainsRune("aeiouäö", at(i-1))
+	}
+
+	// particles
+	switch suffix := w.longestIn(w.r1, "kin", "kaan", "kään", "ko", "kö", "han", "hän", "pa", "pä", "sti"); suffix {
+	case "":
+	case "sti":
+		w.removeIn(w.r2, suffix)
+	default:
+		if b := w.before(suffix); finnishVowel(b) || b == 'n' || b == 't' {
+			w.remove(suffix)
+		}
+	}
+
+	// possessive suffixes
+	switch suffix := w.longestIn(w.r1, "si", "ni", "nsa", "nsä", "mme", "nne", "an", "än", "en"); suffix {
+	case "":
+	case "si":
+		if w.before(suffix) != 'k' {
+			w.remove(suffix)
+		}
+	case "ni":
+		w.remove(suffix)
+		if w.hasSuffix("kse") {
+			w.replace("kse", "ksi")
+		}
+	case "an":
+		if w.longest("taan", "ssaan", "staan", "llaan", "ltaan", "naan") != "" {
+			w.remove(suffix)
+		}
+	case "än":
+		if w.longest("tään", "ssään", "stään", "llään", "ltään", "nään") != "" {
+			w.remove(suffix)
+		}
+	case "en":
+		if w.longest("lleen", "ineen") != "" {
+			w.remove(suffix)
+		}
+	default:
+		w.remove(suffix)
+	}
+
+	// cases
+	endingRemoved := false
+	suffix := w.longestIn(w.r1, "han", "hen", "hin", "hon", "hän", "hön", "siin", "seen", "den", "tten", "n", "a", "ä",
+		"tta", "ttä", "ta", "tä", "ssa", "ssä", "sta", "stä", "lla", "llä", "lta", "ltä", "lle", "na", "nä", "ksi", "ine")
+	switch suffix {
+	case "siin", "den", "tten":
+		if !vi(suffix) {
+			suffix = "n"
+		}
+	case "seen":
+		if !long(suffix) {
+			suffix = "n"
+		}
+	}
+	switch suffix {
+	case "":
+	case "han", "hen", "hin", "hon", "hän", "hön":
+		// illative: the vowel is repeated
+		if w.before(suffix) == []rune(suffix)[1] {
+			w.remove(suffix)
+			endingRemoved = true
+		}
+	case "n":
+		// the illative after a long vowel, and the genitive after ie: the vowel is removed too
+		if long(suffix) || (at(len(w.rs)-3) == 'i' && at(len(w.rs)-2) == 'e') {
+			w.rs = w.rs[:len(w.rs)-2]
+			w.clamp()
+		} else {
+			w.remove(suffix)
+		}
+		endingRemoved = true
+	case "a", "ä":
+		// partitive after a vowel and a consonant
+		if i := len(w.rs) - 1; finnishVowel(at(i-1)) && i >= 2 && !finnishVowel(at(i-2)) {
+			w.remove(suffix)
+			endingRemoved = true
+		}
+	case "tta", "ttä":
+		if w.before(suffix) == 'e' {
+			w.remove(suffix)
+			endingRemoved = true
+		}
+	default:
+		w.remove(suffix)
+		endingRemoved = true
+	}
+
+	// comparatives and superlatives
+	switch suffix := w.longestIn(w.r2, "mpi", "mpa", "mpä", "mmi", "mma", "mmä", "impi", "impa", "impä", "immi", "imma",
+		"immä", "eja", "ejä"); suffix {
+	case "":
+	case "mpi", "mpa", "mpä", "mmi", "mma", "mmä":
+		if !strings.HasSuffix(string(w.rs[:len(w.rs)-3]), "po") {
+			w.remove(suffix)
+		}
+	default:
+		w.remove(suffix)
+	}
+
+	// plurals
+	if endingRemoved {
+		if suffix := w.longestIn(w.r1, "i", "j"); suffix != "" {
+			w.remove(suffix)
+		}
+	} else if w.hasSuffix("t") && w.in(w.r1, "t") && len(w.rs)-2 >= w.r1 && finnishVowel(w.before("t")) {
+		w.remove("t")
+		switch suffix := w.longestIn(w.r2, "mma", "imma"); suffix {
+		case "mma":
+			if !w.hasSuffix("pomma") {
+				w.remove(suffix)
+			}
+		case "imma":
+			w.remove(suffix)
+		}
+	}
+
+	// tidy up, inside R1
+	if long("") && w.in(w.r1, "aa") {
+		w.rs = w.rs[:len(w.rs)-1]
+		w.clamp()
+	}
+	if n := len(w.rs); n-2 >= w.r1 && strings.ContainsRune("aäei", at(n-1)) && !finnishVowel(at(n-2)) {
+		w.rs = w.rs[:n-1]
+		w.clamp()
+	}
+	if n := len(w.rs); n-2 >= w.r1 && at(n-1) == 'j' && (at(n-2) == 'o' || at(n-2) == 'u') {
+		w.rs = w.rs[:n-1]
+		w.clamp()
+	}
+	if n := len(w.rs); n-2 >= w.r1 && at(n-1) == 'o' && at(n-2) == 'j' {
+		w.rs = w.rs[:n-1]
+		w.clamp()
+	}
+	// the last consonant is undoubled
+	for i := len(w.rs) - 1; i >= 0; i-- {
+		if !finnishVowel(w.rs[i]) {
+			if i >= 1 && w.rs[i-1] == w.rs[i] {
+				w.rs = append(w.rs[:i], w.rs[i+1:]...)
+			}
+			break
+		}
+	}
+	return w.String()
+}
@@ -0,0 +1,73 @@
+package stemmer
+
+import (
+	"strings"
+	"unicode"
+)
+
+var germanVowel = vowels("aeiouyäöü")
+
+// german implements the snowball German stemmer.
+func german(s string) string {
+	s = strings.Replace(s, "ß", "ss", -1)
+	w := newWord(s)
+	// u and y between vowels are consonants
+	for i := 1; i < len(w.rs)-1; i++ {
+		if (w.rs[i] == 'u' || w.rs[i] == 'y') && germanVowel(w.rs[i-1]) && germanVowel(w.rs[i+1]) {
+			w.rs[i] = unicode.ToUpper(w.rs[i])
+		}
+	}
+	w.standardRegions(germanVowel)
+	if w.r1 < 3 {
+		w.r1 = 3
+		w.clamp()
+	}
+
+	switch suffix := w.longest("em", "ern", "er", "e", "en", "es", "s"); suffix {
+	case "em", "ern", "er":
+		w.removeIn(w.r1, suffix)
+	case "e", "en", "es":
+		if w.removeIn(w.r1, suffix) && w.hasSuffix("niss") {
+			w.remove("s")
+		}
+	case "s":
+		if w.in(w.r1, suffix) && strings.ContainsRune("bdfghklmnrt", w.before(suffix)) {
+			w.remove(suffix)
+		}
+	}
+
+	switch suffix := w.longest("en", "er", "est", "st"); suffix {
+	case "en", "er", "est":
+		w.removeIn(w.r1, suffix)
+	case "st":
+		// preceded by a valid st-ending, itself preceded by at least 3 letters
+		if w.in(w.r1, suffix) && strings.ContainsRune("bdfghklmnt", w.before(suffix)) && len(w.rs) >= 6 {
+			w.remove(suffix)
+		}
+	}
+
+	switch suffix := w.longest("end", "ung", "ig", "ik", "isch", "lich", "heit", "keit"); suffix {
+	case "end", "ung":
+		if w.removeIn(w.r2, suffix) && w.hasSuffix("ig") && w.before("ig") != 'e' {
+			w.removeIn(w.r2, "ig")
+		}
+	case "ig", "ik", "isch":
+		if w.before(suffix) != 'e' {
+			w.removeIn(w.r2, suffix)
+		}
+	case "lich", "heit":
+		if w.removeIn(w.r2, suffix) {
+			if s := w.longest("er", "en"); s != "" {
+				w.removeIn(w.r1, s)
+			}
+		}
+	case "keit":
+		if w.removeIn(w.r2, suffix) {
+			if s := w.longest("lich", "ig"); s != "" {
+				w.removeIn(w.r2, s)
+			}
+		}
+	}
+
+	return strings.NewReplacer("U", "u", "Y", "y", "ä", "a", "ö", "o", "ü", "u").Replace(w.String())
+}
@@ -0,0 +1,111 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+var hungarianVowel = vowels("aeiouáéíóöúüőű")
+
+var hungarianDoubles = []string{"bb", "cc", "ccs", "dd", "ff", "gg", "ggy", "jj", "kk", "ll", "lly", "mm", "nn", "nny",
+	"pp", "rr", "ss", "ssz", "tt", "tty", "vv", "zz", "zzs"}
+
+// the suffixes of each step, with their replacement
+var hungarianCases = map[string]string{"ba": "", "be": "", "ban": "", "ben": "", "ra": "", "re": "", "nak": "", "nek": "", "val": "",
+	"vel": "", "tól": "", "től": "", "ról": "", "ről": "", "ból": "", "ből": "", "hoz": "", "hez": "", "höz": "",
+	"nál": "", "nél": "", "ig": "", "at": "", "et": "", "ot": "", "öt": "", "ért": "", "képp": "", "képpen": "",
+	"kor": "", "ul": "", "ül": "", "vá": "", "vé": "", "onként": "", "enként": "", "anként": "", "ként": "", "en": "",
+	"on": "", "an": "", "ön": "", "n": "", "t": ""}
+
+var hungarianSpecialCases = map[string]string{"én": "e", "án": "a", "ánként": "a"}
+
+var hungarianOtherCases = map[string]string{"astul": "", "estül": "", "stul": "", "stül": "", "ástul": "a",
+	"éstül": "e"}
+
+var hungarianOwned = map[string]string{"oké": "", "öké": "", "aké": "", "eké": "", "éké": "e", "áké": "a", "ké": "",
+	"ééi": "e", "áéi": "a", "éi": "", "éé": "e", "é": ""}
+
+var hungarianSingularOwners = map[string]string{"ünk": "", "unk": "", "ánk": "a", "énk": "e", "nk": "", "em": "",
+	"om": "", "am": "", "ám": "a", "ém": "e", "m": "", "od": "", "ed": "", "ad": "", "öd": "", "ád": "a", "éd": "e",
+	"d": "", "ja": "", "je": "", "a": "", "e": "", "o": "", "á": "a", "é": "e", "juk": "", "jük": "", "uk": "", "ük": "",
+	"ájuk": "a", "éjük": "e"}
+
+var hungarianPluralOwners = map[string]string{"jaim": "", "jeim": "", "áim": "a", "éim": "e", "aim": "", "eim": "",
+	"im": "", "jaid": "", "jeid": "", "áid": "a", "éid": "e", "aid": "", "eid": "", "id": "", "jai": "", "jei": "",
+	"ái": "a", "éi": "e", "ai": "", "ei": "", "i": "", "jaink": "", "jeink": "", "eink": "", "aink": "", "áink": "a",
+	"éink": "e", "ink": "", "jaitok": "", "jeitek": "", "aitok": "", "eitek": "", "áitok": "a", "éitek": "e",
+	"itek": "", "jeik": "", "jaik": "", "aik": "", "eik": "", "áik": "a", "éik": "e", "ik": ""}
+
+var hungarianPlurals = map[string]string{"ák": "a", "ék": "e", "ök": "", "ak": "", "ok": "", "ek": "", "k": ""}
+
+// hungarian implements the snowball Hungarian stemmer.
+func hungarian(s string) string {
+	w := newWord(s)
+	// R1 is after the first consonant, or the first digraph, that follows an initial vowel, or
+	// after the first vowel
+	if len(w.rs) > 0 && hungarianVowel(w.rs[0]) {
+		for i := 1; i < len(w.rs); i++ {
+			if !hungarianVowel(w.rs[i]) {
+				w.r1 = i + 1
+				rest := string(w.rs[i:])
+				for _, digraph := range []string{"dzs", "cs", "gy", "ly", "ny", "sz", "ty", "zs"} {
+					if strings.HasPrefix(rest, digraph) {
+						w.r1 = i + len(digraph)
+						break
+					}
+				}
+				break
+			}
+		}
+	} else {
+		for i := 1; i < len(w.rs); i++ {
+			if hungarianVowel(w.rs[i]) {
+				w.r1 = i + 1
+				break
+			}
+		}
+	}
+
+	// step replaces the longest of the suffixes that ends the word, if it is inside R1
+	step := func(suffixes map[string]string) bool {
+		suffix := w.longestKey(suffixes)
+		if suffix == "" || !w.in(w.r1, suffix) {
+			return false
+		}
+		w.replace(suffix, suffixes[suffix])
+		return true
+	}
+	// doubled removes the suffix after a doubled consonant, and undoubles it
+	doubled := func(suffixes ...string) {
+		suffix := w.longest(suffixes...)
+		if suffix == "" || !w.in(w.r1, suffix) {
+			return
+		}
+		stem := string(w.rs[:len(w.rs)-len([]rune(suffix))])
+		for _, d := range hungarianDoubles {
+			if strings.HasSuffix(stem, d) {
+				w.remove(suffix)
+				n := len(w.rs)
+				w.rs = append(w.rs[:n-2], w.rs[n-1])
+				w.clamp()
+				return
+			}
+		}
+	}
+
+	// instrumental
+	doubled("al", "el")
+	if step(hungarianCases) {
+		if suffix := w.longest("á", "é"); suffix != "" && w.in(w.r1, suffix) {
+			w.replace(suffix, map[string]string{"á": "a", "é": "e"}[suffix])
+		}
+	}
+	step(hungarianSpecialCases)
+	step(hungarianOtherCases)
+	// factive
+	doubled("á", "é")
+	step(hungarianOwned)
+	step(hungarianSingularOwners)
+	step(hungarianPluralOwners)
+	step(hungarianPlurals)
+	return w.String()
+}
@@ -0,0 +1,130 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+var italianVowel = vowels("aeiouàèìòù")
+
+var italianPronouns = []string{"ci", "gli", "la", "le", "li", "lo", "mi", "ne", "si", "ti", "vi", "sene", "gliela",
+	"gliele", "glieli", "glielo", "gliene", "mela", "mele", "meli", "melo", "mene", "tela", "tele", "teli", "telo",
+	"tene", "cela", "cele", "celi", "celo", "cene", "vela", "vele", "veli", "velo", "vene"}
+
+var italianVerbSuffixes = []string{"ammo", "ando", "ano", "are", "arono", "asse", "assero", "assi", "assimo", "ata",
+	"ate", "ati", "ato", "ava", "avamo", "avano", "avate", "avi", "avo", "emmo", "enda", "ende", "endi", "endo", "erà",
+	"erai", "eranno", "ere", "erebbe", "erebbero", "erei", "eremmo", "eremo", "ereste", "eresti", "erete", "erò",
+	"erono", "essero", "ete", "eva", "evamo", "evano", "evate", "evi", "evo", "iamo", "immo", "irà", "irai", "iranno",
+	"ire", "irebbe", "irebbero", "irei", "iremmo", "iremo", "ireste", "iresti", "irete", "irò", "irono", "isca",
+	"iscano", "isce", "isci", "isco", "iscono", "issero", "ita", "ite", "iti", "ito", "iva", "ivamo", "ivano", "ivate",
+	"ivi", "ivo", "ono", "uta", "ute", "uti", "uto", "ar", "ir"}
+
+// italian implements the snowball Italian stemmer.
+func italian(s string) string {
+	s = strings.NewReplacer("á", "à", "é", "è", "í", "ì", "ó", "ò", "ú", "ù").Replace(s)
+	w := newWord(s)
+	// the u after q, and the u and i between vowels, are consonants
+	for i, r := range w.rs {
+		switch {
+		case r == 'u' && i > 0 && w.rs[i-1] == 'q':
+			w.rs[i] = 'U'
+		case (r == 'u' || r == 'i') && i > 0 && i < len(w.rs)-1 && italianVowel(w.rs[i-1]) && italianVowel(w.rs[i+1]):
+			if r == 'u' {
+				w.rs[i] = 'U'
+			} else {
+				w.rs[i] = 'I'
+			}
+		}
+	}
+	w.standardRegions(italianVowel)
+	w.romanceRV(italianVowel)
+
+	// attached pronouns
+	if pronoun := w.longest(italianPronouns...); pronoun != "" {
+		stem := string(w.rs[:len(w.rs)-len([]rune(pronoun))])
+		for _, ending := range []string{"ando", "endo", "ar", "er", "ir"} {
+			if strings.HasSuffix(stem, ending) && w.in(w.rv, ending+pronoun) {
+				if len(ending) == 4 {
+					w.remove(pronoun)
+				} else {
+					w.replace(pronoun, "e")
+				}
+				break
+			}
+		}
+	}
+
+	changed := false
+	removeR2 := func(suffix string) bool {
+		if w.removeIn(w.r2, suffix) {
+			changed = true
+			return true
+		}
+		return false
+	}
+	switch suffix := w.longest("anza", "anze", "ico", "ici", "ica", "ice", "iche", "ichi", "ismo", "ismi", "abile",
+		"abili", "ibile", "ibili", "ista", "iste", "isti", "istà", "istè", "istì", "oso", "osi", "osa", "ose", "mente",
+		"atrice", "atrici", "ante", "anti", "azione", "azioni", "atore", "atori", "logia", "logie", "uzione", "uzioni",
+		"usione", "usioni", "enza", "enze", "amento", "amenti", "imento", "imenti", "amente", "ità", "ivo", "ivi", "iva",
+		"ive"); suffix {
+	case "":
+	case "azione", "azioni", "atore", "atori":
+		if removeR2(suffix) {
+			w.removeIn(w.r2, "ic")
+		}
+	case "logia", "logie":
+		if w.in(w.r2, suffix) {
+			w.replace(suffix, "log")
+			changed = true
+		}
+	case "uzione", "uzioni", "usione", "usioni":
+		if w.in(w.r2, suffix) {
+			w.replace(suffix, "u")
+			changed = true
+		}
+	case "enza", "enze":
+		if w.in(w.r2, suffix) {
+			w.replace(suffix, "ente")
+			changed = true
+		}
+	case "amento", "amenti", "imento", "imenti":
+		if w.removeIn(w.rv, suffix) {
+			changed = true
+		}
+	case "amente":
+		if w.removeIn(w.r1, suffix) {
+			changed = true
+			if w.removeIn(w.r2, "iv") {
+				w.removeIn(w.r2, "at")
+			} else if s := w.longest("os", "ic", "abil"); s != "" {
+				w.removeIn(w.r2, s)
+			}
+		}
+	case "ità":
+		if removeR2(suffix) {
+			if s := w.longest("abil", "ic", "iv"); s != "" {
+				w.removeIn(w.r2, s)
+			}
+		}
+	case "ivo", "ivi", "iva", "ive":
+		if removeR2(suffix) && w.removeIn(w.r2, "at") {
+			w.removeIn(w.r2, "ic")
+		}
+	default:
+		removeR2(suffix)
+	}
+
+	if !changed {
+		if suffix := w.longest(italianVerbSuffixes...); suffix != "" {
+			w.removeIn(w.rv, suffix)
+		}
+	}
+
+	if suffix := w.longest("a", "e", "i", "o", "à", "è", "ì", "ò"); suffix != "" && w.removeIn(w.rv, suffix) {
+		w.removeIn(w.rv, "i")
+	}
+	if suffix := w.longest("ch", "gh"); suffix != "" && w.in(w.rv, suffix) {
+		w.remove("h")
+	}
+
+	return strings.NewReplacer("I", "i", "U", "u").Replace(w.String())
+}
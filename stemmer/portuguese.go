@@ -0,0 +1,117 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+var portugueseVowel = vowels("aeiouáéíóúâêô")
+
+var portugueseVerbSuffixes = []string{"ada", "ida", "ia", "aria", "eria", "iria", "ará", "ara", "erá", "era", "irá",
+	"ava", "asse", "esse", "isse", "aste", "este", "iste", "ei", "arei", "erei", "irei", "am", "iam", "ariam", "eriam",
+	"iriam", "aram", "eram", "iram", "avam", "em", "arem", "erem", "irem", "assem", "essem", "issem", "ado", "ido",
+	"ando", "endo", "indo", "ara~o", "era~o", "ira~o", "ar", "er", "ir", "as", "adas", "idas", "ias", "arias", "erias",
+	"irias", "arás", "aras", "erás", "eras", "irás", "avas", "es", "ardes", "erdes", "irdes", "ares", "eres", "ires",
+	"asses", "esses", "isses", "astes", "estes", "istes", "is", "ais", "eis", "íeis", "aríeis", "eríeis", "iríeis",
+	"áreis", "areis", "éreis", "ereis", "íreis", "ireis", "ásseis", "ésseis", "ísseis", "áveis", "ados", "idos",
+	"ámos", "amos", "íamos", "aríamos", "eríamos", "iríamos", "áramos", "éramos", "íramos", "ávamos", "emos",
+	"aremos", "eremos", "iremos", "ássemos", "êssemos", "íssemos", "imos", "armos", "ermos", "irmos", "eu", "iu",
+	"ou", "ira", "iras"}
+
+// portuguese implements the snowball Portuguese stemmer.
+func portuguese(s string) string {
+	s = strings.NewReplacer("ã", "a~", "õ", "o~").Replace(s)
+	w := newWord(s)
+	w.standardRegions(portugueseVowel)
+	w.romanceRV(portugueseVowel)
+
+	changed := false
+	removeR2 := func(suffix string) bool {
+		if w.removeIn(w.r2, suffix) {
+			changed = true
+			return true
+		}
+		return false
+	}
+	switch suffix := w.longest("eza", "ezas", "ico", "ica", "icos", "icas", "ismo", "ismos", "ável", "ível", "ista",
+		"istas", "oso", "osa", "osos", "osas", "amento", "amentos", "imento", "imentos", "adora", "ador", "aça~o",
+		"adoras", "adores", "aço~es", "ante", "antes", "ância", "logia", "logias", "uça~o", "uço~es", "ência",
+		"ências", "amente", "mente", "idade", "idades", "iva", "ivo", "ivas", "ivos", "ira", "iras"); suffix {
+	case "":
+	case "logia", "logias":
+		if w.in(w.r2, suffix) {
+			w.replace(suffix, "log")
+			changed = true
+		}
+	case "uça~o", "uço~es":
+		if w.in(w.r2, suffix) {
+			w.replace(suffix, "u")
+			changed = true
+		}
+	case "ência", "ências":
+		if w.in(w.r2, suffix) {
+			w.replace(suffix, "ente")
+			changed = true
+		}
+	case "amente":
+		if w.removeIn(w.r1, suffix) {
+			changed = true
+			if w.removeIn(w.r2, "iv") {
+				w.removeIn(w.r2, "at")
+			} else if s := w.longest("os", "ic", "ad"); s != "" {
+				w.removeIn(w.r2, s)
+			}
+		}
+	case "mente":
+		if removeR2(suffix) {
+			if s := w.longest("ante", "avel", "ível"); s != "" {
+				w.removeIn(w.r2, s)
+			}
+		}
+	case "idade", "idades":
+		if removeR2(suffix) {
+			if s := w.longest("abil", "ic", "iv"); s != "" {
+				w.removeIn(w.r2, s)
+			}
+		}
+	case "iva", "ivo", "ivas", "ivos":
+		if removeR2(suffix) {
+			w.removeIn(w.r2, "at")
+		}
+	case "ira", "iras":
+		if w.in(w.rv, suffix) && w.before(suffix) == 'e' {
+			w.replace(suffix, "ir")
+			changed = true
+		}
+	default:
+		removeR2(suffix)
+	}
+
+	if !changed {
+		if suffix := w.longest(portugueseVerbSuffixes...); suffix != "" && w.removeIn(w.rv, suffix) {
+			changed = true
+		}
+	}
+
+	if changed {
+		if w.hasSuffix("ci") {
+			w.removeIn(w.rv, "i")
+		}
+	} else if suffix := w.longest("os", "a", "i", "o", "á", "í", "ó"); suffix != "" {
+		w.removeIn(w.rv, suffix)
+	}
+
+	if suffix := w.longest("e", "é", "ê"); suffix != "" {
+		if w.removeIn(w.rv, suffix) {
+			switch {
+			case w.hasSuffix("gu"):
+				w.removeIn(w.rv, "u")
+			case w.hasSuffix("ci"):
+				w.removeIn(w.rv, "i")
+			}
+		}
+	} else if w.hasSuffix("ç") {
+		w.replace("ç", "c")
+	}
+
+	return strings.NewReplacer("a~", "ã", "o~", "õ").Replace(w.String())
+}
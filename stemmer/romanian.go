@@ -0,0 +1,110 @@
+package stemmer
+
+import (
+	"strings"
+	"unicode"
+)
+
+var romanianVowel = vowels("aeiouâîă")
+
+// the plural and the definite article endings, with their replacement
+var romanianStep0 = map[string]string{"ul": "", "ului": "", "aua": "a", "ea": "e", "ele": "e", "elor": "e", "iua": "i",
+	"iei": "i", "ii": "i", "iile": "i", "iilor": "i", "ile": "i", "ilor": "i", "atei": "at", "aţie": "aţi", "aţia": "aţi"}
+
+// the suffixes that are reduced before a standard suffix, with their replacement
+var romanianCombos = map[string]string{"abilitate": "abil", "abilitati": "abil", "abilităi": "abil",
+	"abilităţi": "abil", "ibilitate": "ibil", "ivitate": "iv", "ivitati": "iv", "ivităi": "iv", "ivităţi": "iv",
+	"icitate": "ic", "icitati": "ic", "icităi": "ic", "icităţi": "ic", "icator": "ic", "icatori": "ic", "iciv": "ic",
+	"iciva": "ic", "icive": "ic", "icivi": "ic", "icivă": "ic", "ical": "ic", "icala": "ic", "icale": "ic",
+	"icali": "ic", "icală": "ic", "ativ": "at", "ativa": "at", "ative": "at", "ativi": "at", "ativă": "at",
+	"aţiune": "at", "atoare": "at", "ator": "at", "atori": "at", "ătoare": "at", "ător": "at", "ători": "at",
+	"itiv": "it", "itiva": "it", "itive": "it", "itivi": "it", "itivă": "it", "iţiune": "it", "itoare": "it",
+	"itor": "it", "itori": "it"}
+
+var romanianStandardSuffixes = []string{"ica", "abila", "ibila", "oasa", "ata", "ita", "anta", "ista", "uta", "iva",
+	"ic", "ice", "abile", "ibile", "isme", "iune", "oase", "ate", "itate", "ite", "ante", "iste", "ute", "ive", "ici",
+	"abili", "ibili", "iuni", "atori", "osi", "ati", "itati", "iti", "anti", "isti", "uti", "işti", "ivi", "ităi",
+	"oşi", "ităţi", "abil", "ibil", "ism", "ator", "os", "at", "it", "ant", "ist", "ut", "iv", "ică", "abilă", "ibilă",
+	"oasă", "ată", "ită", "antă", "istă", "ută", "ivă"}
+
+// the verb suffixes that are removed after a consonant or a u
+var romanianVerbSuffixes = []string{"ea", "ia", "esc", "ăsc", "ind", "ând", "are", "ere", "ire", "âre", "ase", "ise",
+	"use", "âse", "eşte", "ăşte", "eze", "ai", "eai", "iai", "eşti", "ăşti", "ui", "ezi", "aşi", "aseşi", "iseşi",
+	"useşi", "âseşi", "işi", "uşi", "âşi", "âi", "eaţi", "iaţi", "arăţi", "aserăţi", "iserăţi", "userăţi", "âserăţi",
+	"irăţi", "urăţi", "ârăţi", "am", "eam", "iam", "asem", "isem", "usem", "âsem", "arăm", "aserăm", "iserăm", "userăm",
+	"âserăm", "irăm", "urăm", "ârăm", "au", "eau", "iau", "indu", "ându", "ez", "ească", "ară", "aseră", "iseră",
+	"useră", "âseră", "iră", "ură", "âră", "ează"}
+
+// the verb suffixes that are always removed
+var romanianOtherVerbSuffixes = []string{"se", "sese", "sei", "seşi", "seseşi", "aţi", "eţi", "iţi", "serăţi",
+	"seserăţi", "âţi", "em", "sesem", "im", "ăm", "serăm", "seserăm", "âm", "seră", "seseră"}
+
+// romanian implements the snowball Romanian stemmer. The letters with a comma below are written
+// with a cedilla, as in the reference implementation.
+func romanian(s string) string {
+	s = strings.NewReplacer("ș", "ş", "ț", "ţ").Replace(s)
+	w := newWord(s)
+	// the u and the i between vowels are consonants
+	for i := 1; i < len(w.rs)-1; i++ {
+		if (w.rs[i] == 'u' || w.rs[i] == 'i') && romanianVowel(w.rs[i-1]) && romanianVowel(w.rs[i+1]) {
+			w.rs[i] = unicode.ToUpper(w.rs[i])
+		}
+	}
+	w.standardRegions(romanianVowel)
+	w.romanceRV(romanianVowel)
+
+	if suffix := w.longestKey(romanianStep0); suffix != "" && w.in(w.r1, suffix) &&
+		!(suffix == "ile" && w.hasSuffix("abile")) {
+		w.replace(suffix, romanianStep0[suffix])
+	}
+
+	standardRemoved := false
+	for {
+		suffix := w.longestKey(romanianCombos)
+		if suffix == "" || !w.in(w.r1, suffix) {
+			break
+		}
+		w.replace(suffix, romanianCombos[suffix])
+		standardRemoved = true
+	}
+	switch suffix := w.longest(romanianStandardSuffixes...); suffix {
+	case "":
+	case "iune", "iuni":
+		if w.in(w.r2, suffix) && w.before(suffix) == 'ţ' {
+			w.replace("ţ"+suffix, "t")
+			standardRemoved = true
+		}
+	case "ism", "isme", "ist", "ista", "iste", "isti", "istă", "işti":
+		if w.in(w.r2, suffix) {
+			w.replace(suffix, "ist")
+			standardRemoved = true
+		}
+	default:
+		if w.removeIn(w.r2, suffix) {
+			standardRemoved = true
+		}
+	}
+
+	if !standardRemoved {
+		suffix := w.longestIn(w.rv, append(romanianVerbSuffixes, romanianOtherVerbSuffixes...)...)
+		for _, other := range romanianOtherVerbSuffixes {
+			if suffix == other {
+				w.remove(suffix)
+				suffix = ""
+				break
+			}
+		}
+		if suffix != "" {
+			i := len(w.rs) - len([]rune(suffix)) - 1
+			if i >= w.rv && (!romanianVowel(w.rs[i]) || w.rs[i] == 'u') {
+				w.remove(suffix)
+			}
+		}
+	}
+
+	if suffix := w.longest("a", "e", "ie", "i", "ă"); suffix != "" {
+		w.removeIn(w.rv, suffix)
+	}
+
+	return strings.NewReplacer("I", "i", "U", "u").Replace(w.String())
+}
@@ -0,0 +1,220 @@
+// Package stemmer reduces the words to their stem with the snowball algorithms. The English,
+// French, Spanish, Russian, Swedish and Norwegian stemmers are the ones of the snowball package;
+// the German, Dutch, Italian, Portuguese, Danish, Finnish, Hungarian, Romanian and Turkish ones are
+// implemented here, and tested against the stems of the reference implementation in testdata.
+//
+// The other languages that have stopwords are not stemmed: there is no snowball algorithm for
+// Indonesian, Polish, Czech and Ukrainian. Their words are kept as is.
+package stemmer
+
+import (
+	"strings"
+
+	"github.com/kljensen/snowball"
+)
+
+var stemmers = map[string]func(string) string{
+	"german":     german,
+	"dutch":      dutch,
+	"italian":    italian,
+	"portuguese": portuguese,
+	"danish":     danish,
+	"finnish":    finnish,
+	"hungarian":  hungarian,
+	"romanian":   romanian,
+	"turkish":    turkish,
+}
+
+// aliases maps the names of the languages detected by whatlanggo to the names of the stemmers.
+var aliases = map[string]string{
+	"bokmal":  "norwegian",
+	"nynorsk": "norwegian",
+}
+
+// Supported reports whether there is a stemmer for language.
+func Supported(language string) bool {
+	if a, ok := aliases[language]; ok {
+		language = a
+	}
+	switch language {
+	case "english", "french", "spanish", "russian", "swedish", "norwegian":
+		return true
+	}
+	_, ok := stemmers[language]
+	return ok
+}
+
+// Stem returns the stem of the lowercase word in language. The words of the languages without a
+// stemmer are returned as is.
+func Stem(word string, language string) string {
+	if a, ok := aliases[language]; ok {
+		language = a
+	}
+	if f, ok := stemmers[language]; ok {
+		return f(word)
+	}
+	stem, err := snowball.Stem(word, language, false)
+	if err != nil {
+		return word
+	}
+	return stem
+}
+
+// word is a word being stemmed, with its R1, R2 and RV regions, in the sense of snowball.
+type word struct {
+	rs []rune
+	r1 int
+	r2 int
+	rv int
+}
+
+func newWord(s string) *word {
+	rs := []rune(s)
+	return &word{rs: rs, r1: len(rs), r2: len(rs), rv: len(rs)}
+}
+
+func (w *word) String() string {
+	return string(w.rs)
+}
+
+// standardRegions computes R1, the region after the first non-vowel following a vowel, and R2,
+// the same region inside R1.
+func (w *word) standardRegions(isVowel func(rune) bool) {
+	w.r1 = regionAfter(w.rs, 0, isVowel)
+	w.r2 = regionAfter(w.rs, w.r1, isVowel)
+}
+
+func regionAfter(rs []rune, start int, isVowel func(rune) bool) int {
+	for i := start + 1; i < len(rs); i++ {
+		if !isVowel(rs[i]) && isVowel(rs[i-1]) {
+			return i + 1
+		}
+	}
+	return len(rs)
+}
+
+// romanceRV computes RV for the Romance languages: after the next vowel when the second letter is
+// a consonant, after the next consonant when the two first letters are vowels, and after the
+// third letter otherwise.
+func (w *word) romanceRV(isVowel func(rune) bool) {
+	rs := w.rs
+	w.rv = len(rs)
+	if len(rs) < 3 {
+		return
+	}
+	switch {
+	case !isVowel(rs[1]):
+		for i := 2; i < len(rs); i++ {
+			if isVowel(rs[i]) {
+				w.rv = i + 1
+				return
+			}
+		}
+	case isVowel(rs[0]):
+		for i := 2; i < len(rs); i++ {
+			if !isVowel(rs[i]) {
+				w.rv = i + 1
+				return
+			}
+		}
+	default:
+		w.rv = 3
+	}
+}
+
+func (w *word) hasSuffix(suffix string) bool {
+	return strings.HasSuffix(string(w.rs), suffix)
+}
+
+// in reports whether a suffix of n runes is inside the region starting at start.
+func (w *word) in(start int, suffix string) bool {
+	return len(w.rs)-len([]rune(suffix)) >= start
+}
+
+// longest returns the longest of the suffixes that ends the word, or "".
+func (w *word) longest(suffixes ...string) string {
+	s := string(w.rs)
+	found := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(found) && strings.HasSuffix(s, suffix) {
+			found = suffix
+		}
+	}
+	return found
+}
+
+// longestKey returns the longest of the suffixes, the keys of the map, that ends the word, or "".
+func (w *word) longestKey(suffixes map[string]string) string {
+	found := ""
+	for suffix := range suffixes {
+		if len(suffix) > len(found) && w.hasSuffix(suffix) {
+			found = suffix
+		}
+	}
+	return found
+}
+
+// longestIn returns the longest of the suffixes that ends the word inside the region starting at
+// start, or "".
+func (w *word) longestIn(start int, suffixes ...string) string {
+	found := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(found) && w.hasSuffix(suffix) && w.in(start, suffix) {
+			found = suffix
+		}
+	}
+	return found
+}
+
+// replace replaces the suffix, that must end the word, by replacement.
+func (w *word) replace(suffix string, replacement string) {
+	w.rs = append(w.rs[:len(w.rs)-len([]rune(suffix))], []rune(replacement)...)
+	w.clamp()
+}
+
+func (w *word) remove(suffix string) {
+	w.replace(suffix, "")
+}
+
+// removeIn removes the suffix if the word ends with it inside the region starting at start.
+func (w *word) removeIn(start int, suffix string) bool {
+	if w.hasSuffix(suffix) && w.in(start, suffix) {
+		w.remove(suffix)
+		return true
+	}
+	return false
+}
+
+func (w *word) clamp() {
+	if w.r1 > len(w.rs) {
+		w.r1 = len(w.rs)
+	}
+	if w.r2 > len(w.rs) {
+		w.r2 = len(w.rs)
+	}
+	if w.rv > len(w.rs) {
+		w.rv = len(w.rs)
+	}
+}
+
+func (w *word) last() rune {
+	if len(w.rs) == 0 {
+		return 0
+	}
+	return w.rs[len(w.rs)-1]
+}
+
+// before returns the letter before the suffix, or 0.
+func (w *word) before(suffix string) rune {
+	i := len(w.rs) - len([]rune(suffix)) - 1
+	if i < 0 {
+		return 0
+	}
+	return w.rs[i]
+}
+
+func vowels(letters string) func(rune) bool {
+	return func(r rune) bool {
+		return strings.ContainsRune(letters, r)
+	}
+}
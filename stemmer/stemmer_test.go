@@ -0,0 +1,65 @@
+package stemmer
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestStemReference compares the stemmers implemented here to the stems of the reference snowball
+// implementation, in testdata/<language>.txt: one "word stem" pair per line.
+func TestStemReference(t *testing.T) {
+	for language := range stemmers {
+		f, err := os.Open(filepath.Join("testdata", language+".txt"))
+		if err != nil {
+			t.Errorf("%s: %s", language, err)
+			continue
+		}
+		scanner := bufio.NewScanner(f)
+		n := 0
+		for scanner.Scan() {
+			fields := strings.Fields(scanner.Text())
+			if len(fields) != 2 {
+				continue
+			}
+			n++
+			if stem := Stem(fields[0], language); stem != fields[1] {
+				t.Errorf("%s: Stem(%q) = %q, want %q", language, fields[0], stem, fields[1])
+			}
+		}
+		_ = f.Close()
+		if n == 0 {
+			t.Errorf("%s: no reference vectors", language)
+		}
+	}
+}
+
+func TestSupported(t *testing.T) {
+	for _, language := range []string{"english", "french", "german", "dutch", "italian", "portuguese", "danish", "finnish",
+		"hungarian", "romanian", "turkish", "bokmal", "nynorsk"} {
+		if !Supported(language) {
+			t.Errorf("%s is not supported", language)
+		}
+	}
+	for _, language := range []string{"indonesian", "polish", "czech", "ukrainian"} {
+		if Supported(language) {
+			t.Errorf("%s is supported", language)
+		}
+		if Stem("words", language) != "words" {
+			t.Errorf("%s: the words are stemmed", language)
+		}
+	}
+}
+
+// TestRomanianComma checks that the letters with a comma below are stemmed as the ones with a
+// cedilla of the reference implementation.
+func TestRomanianComma(t *testing.T) {
+	for _, w := range []string{"plăți", "informații", "organizațiile", "profesioniști", "totuși", "româneşte"} {
+		cedilla := strings.NewReplacer("ș", "ş", "ț", "ţ").Replace(w)
+		if stem, expected := Stem(w, "romanian"), Stem(cedilla, "romanian"); stem != expected {
+			t.Errorf("Stem(%q) = %q, want %q", w, stem, expected)
+		}
+	}
+}
@@ -0,0 +1,559 @@
+package stemmer
+
+import (
+	"strings"
+)
+
+var turkishVowel = vowels("aeıioöuü")
+
+// the high vowels, that vary with the vowel harmony
+var turkishU = vowels("ıiuü")
+
+// the vowels that may precede each vowel, following the vowel harmony
+var turkishHarmony = map[rune]string{'a': "aıou", 'e': "eiöü", 'ı': "aı", 'i': "ei", 'o': "ou", 'ö': "öü", 'u': "ou",
+	'ü': "öü"}
+
+// the suffixes, named as in the snowball algorithm, where A stands for a or e, U for ı, i, u or ü,
+// and D for d or t
+var (
+	turkishPossessives = []string{"m", "n", "miz", "niz", "muz", "nuz", "mız", "nız", "müz", "nüz"}
+	turkishLArI        = []string{"leri", "ları"}
+	turkishNU          = []string{"ni", "nu", "nı", "nü"}
+	turkishNUn         = []string{"in", "un", "ın", "ün"}
+	turkishYA          = []string{"a", "e"}
+	turkishNA          = []string{"na", "ne"}
+	turkishDA          = []string{"da", "ta", "de", "te"}
+	turkishNdA         = []string{"nda", "nde"}
+	turkishDAn         = []string{"dan", "tan", "den", "ten"}
+	turkishNdAn        = []string{"ndan", "nden"}
+	turkishYlA         = []string{"la", "le"}
+	turkishNcA         = []string{"ca", "ce"}
+	turkishYUm         = []string{"im", "um", "ım", "üm"}
+	turkishSUn         = []string{"sin", "sun", "sın", "sün"}
+	turkishYUz         = []string{"iz", "uz", "ız", "üz"}
+	turkishSUnUz       = []string{"siniz", "sunuz", "sınız", "sünüz"}
+	turkishLAr         = []string{"lar", "ler"}
+	turkishNUz         = []string{"niz", "nuz", "nız", "nüz"}
+	turkishDUr         = []string{"dir", "tir", "dur", "tur", "dır", "tır", "dür", "tür"}
+	turkishCAsInA      = []string{"casına", "cesine"}
+	turkishYDU         = []string{"di", "ti", "dik", "tik", "duk", "tuk", "dık", "tık", "dük", "tük", "dim", "tim", "dum",
+		"tum", "dım", "tım", "düm", "tüm", "din", "tin", "dun", "tun", "dın", "tın", "dün", "tün", "du", "tu", "dı", "tı",
+		"dü", "tü"}
+	turkishYsA   = []string{"sa", "se", "sak", "sek", "sam", "sem", "san", "sen"}
+	turkishYmUs  = []string{"miş", "muş", "mış", "müş"}
+	turkishFinal = map[rune]rune{'b': 'p', 'c': 'ç', 'd': 't', 'ğ': 'k'}
+)
+
+// turkishWord is a Turkish word being stemmed. The Turkish suffixes are chained, so that the
+// snowball algorithm walks the word backwards: the suffixes are marked from the cursor, between
+// the start of the suffix and ket, its end.
+type turkishWord struct {
+	rs     []rune
+	cursor int
+	ket    int
+}
+
+// at returns the letter at i, or 0 outside the word.
+func (t *turkishWord) at(i int) rune {
+	if i < 0 || i >= len(t.rs) {
+		return 0
+	}
+	return t.rs[i]
+}
+
+// save returns the position of the cursor from the end of the word, that the deletions before
+// the cursor do not change.
+func (t *turkishWord) save() int {
+	return len(t.rs) - t.cursor
+}
+
+func (t *turkishWord) restore(saved int) {
+	t.cursor = len(t.rs) - saved
+}
+
+// mark sets the end of the suffix at the cursor.
+func (t *turkishWord) mark() {
+	t.ket = t.cursor
+}
+
+// del removes the marked suffix.
+func (t *turkishWord) del() {
+	t.rs = append(t.rs[:t.cursor], t.rs[t.ket:]...)
+	t.ket = t.cursor
+}
+
+// among moves the cursor before the longest of the suffixes that ends at the cursor.
+func (t *turkishWord) among(suffixes ...string) bool {
+	prefix := string(t.rs[:t.cursor])
+	found := ""
+	for _, suffix := range suffixes {
+		if len(suffix) > len(found) && strings.HasSuffix(prefix, suffix) {
+			found = suffix
+		}
+	}
+	if found == "" {
+		return false
+	}
+	t.cursor -= len([]rune(found))
+	return true
+}
+
+// harmony reports whether the last vowel before the cursor agrees with a vowel before it.
+func (t *turkishWord) harmony() bool {
+	for i := t.cursor - 1; i >= 0; i-- {
+		if !turkishVowel(t.rs[i]) {
+			continue
+		}
+		for j := i - 1; j >= 0; j-- {
+			if strings.ContainsRune(turkishHarmony[t.rs[i]], t.rs[j]) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// optional moves the cursor before the optional letter that joins a suffix to a stem ending
+// with a vowel.
+func (t *turkishWord) optional(in func(rune) bool, joined func(rune) bool) bool {
+	c := t.cursor
+	if c >= 2 && in(t.rs[c-1]) && joined(t.rs[c-2]) {
+		t.cursor--
+		return true
+	}
+	return !(c >= 1 && in(t.rs[c-1])) && c >= 2 && joined(t.rs[c-2])
+}
+
+func (t *turkishWord) optionalConsonant(consonant rune) bool {
+	return t.optional(func(r rune) bool { return r == consonant }, turkishVowel)
+}
+
+func (t *turkishWord) optionalU() bool {
+	return t.optional(turkishU, func(r rune) bool { return !turkishVowel(r) })
+}
+
+// suffix moves the cursor before one of the suffixes, checking the vowel harmony first when
+// harmony is set, and the optional joining consonant when joined is not 0. The cursor is left
+// unchanged when the suffix is not found.
+func (t *turkishWord) suffix(harmony bool, suffixes []string, joined rune) bool {
+	c := t.cursor
+	if (harmony && !t.harmony()) || !t.among(suffixes...) || (joined != 0 && !t.optionalConsonant(joined)) {
+		t.cursor = c
+		return false
+	}
+	return true
+}
+
+func (t *turkishWord) possessives() bool {
+	c := t.cursor
+	if !t.among(turkishPossessives...) || !t.optionalU() {
+		t.cursor = c
+		return false
+	}
+	return true
+}
+
+// sU and yU are a single high vowel.
+func (t *turkishWord) sU() bool {
+	return t.highVowel('s')
+}
+
+func (t *turkishWord) yU() bool {
+	return t.highVowel('y')
+}
+
+func (t *turkishWord) highVowel(joined rune) bool {
+	c := t.cursor
+	if !t.harmony() || c < 1 || !turkishU(t.rs[c-1]) {
+		return false
+	}
+	t.cursor--
+	if !t.optionalConsonant(joined) {
+		t.cursor = c
+		return false
+	}
+	return true
+}
+
+func (t *turkishWord) lArI() bool   { return t.suffix(false, turkishLArI, 0) }
+func (t *turkishWord) nU() bool     { return t.suffix(true, turkishNU, 0) }
+func (t *turkishWord) nUn() bool    { return t.suffix(true, turkishNUn, 'n') }
+func (t *turkishWord) yA() bool     { return t.suffix(true, turkishYA, 'y') }
+func (t *turkishWord) nA() bool     { return t.suffix(true, turkishNA, 0) }
+func (t *turkishWord) dA() bool     { return t.suffix(true, turkishDA, 0) }
+func (t *turkishWord) ndA() bool    { return t.suffix(true, turkishNdA, 0) }
+func (t *turkishWord) dAn() bool    { return t.suffix(true, turkishDAn, 0) }
+func (t *turkishWord) ndAn() bool   { return t.suffix(true, turkishNdAn, 0) }
+func (t *turkishWord) ylA() bool    { return t.suffix(true, turkishYlA, 'y') }
+func (t *turkishWord) ki() bool     { return t.suffix(false, []string{"ki"}, 0) }
+func (t *turkishWord) ncA() bool    { return t.suffix(true, turkishNcA, 'n') }
+func (t *turkishWord) yUm() bool    { return t.suffix(true, turkishYUm, 'y') }
+func (t *turkishWord) sUn() bool    { return t.suffix(true, turkishSUn, 0) }
+func (t *turkishWord) yUz() bool    { return t.suffix(true, turkishYUz, 'y') }
+func (t *turkishWord) sUnUz() bool  { return t.suffix(false, turkishSUnUz, 0) }
+func (t *turkishWord) lAr() bool    { return t.suffix(true, turkishLAr, 0) }
+func (t *turkishWord) nUz() bool    { return t.suffix(true, turkishNUz, 0) }
+func (t *turkishWord) dUr() bool    { return t.suffix(true, turkishDUr, 0) }
+func (t *turkishWord) cAsInA() bool { return t.suffix(false, turkishCAsInA, 0) }
+func (t *turkishWord) yDU() bool    { return t.suffix(true, turkishYDU, 'y') }
+func (t *turkishWord) ysA() bool    { return t.suffix(false, turkishYsA, 'y') }
+func (t *turkishWord) ymUs() bool   { return t.suffix(true, turkishYmUs, 'y') }
+func (t *turkishWord) yken() bool   { return t.suffix(false, []string{"ken"}, 'y') }
+
+// first tries the marks in order, and reports whether one of them moved the cursor.
+func first(marks ...func() bool) bool {
+	for _, m := range marks {
+		if m() {
+			return true
+		}
+	}
+	return false
+}
+
+// attempt runs f, and puts the cursor back when f fails. The deletions made by f are kept.
+func (t *turkishWord) attempt(f func() bool) {
+	saved := t.save()
+	if !f() {
+		t.restore(saved)
+	}
+}
+
+// lArChain removes a plural suffix, then the suffixes before ki.
+func (t *turkishWord) lArChain() bool {
+	t.mark()
+	if !t.lAr() {
+		return false
+	}
+	t.del()
+	return t.chainBeforeKi()
+}
+
+// nominalVerbSuffixes removes the predicate suffixes of the nominal verbs, and reports whether
+// the noun suffixes may be removed after them.
+func (t *turkishWord) nominalVerbSuffixes() (continueStemming bool) {
+	t.mark()
+	continueStemming = true
+	saved := t.save()
+	switch {
+	case first(t.ymUs, t.yDU, t.ysA, t.yken):
+	case t.restoreThen(saved, func() bool {
+		if !t.cAsInA() {
+			return false
+		}
+		first(t.sUnUz, t.lAr, t.yUm, t.sUn, t.yUz)
+		return t.ymUs()
+	}):
+	case t.restoreThen(saved, t.lAr):
+		t.del()
+		t.attempt(func() bool {
+			t.mark()
+			return first(t.dUr, t.yDU, t.ysA, t.ymUs)
+		})
+		continueStemming = false
+	case t.restoreThen(saved, func() bool { return t.nUz() && first(t.yDU, t.ysA) }):
+	case t.restoreThen(saved, func() bool { return first(t.sUnUz, t.yUz, t.sUn, t.yUm) }):
+		t.del()
+		t.attempt(func() bool {
+			t.mark()
+			return t.ymUs()
+		})
+	case t.restoreThen(saved, t.dUr):
+		t.del()
+		t.attempt(func() bool {
+			t.mark()
+			first(t.sUnUz, t.lAr, t.yUm, t.sUn, t.yUz)
+			return t.ymUs()
+		})
+	default:
+		return continueStemming
+	}
+	t.del()
+	return continueStemming
+}
+
+// restoreThen puts the cursor back to saved, then runs f.
+func (t *turkishWord) restoreThen(saved int, f func() bool) bool {
+	t.restore(saved)
+	return f()
+}
+
+// chainBeforeKi removes the chain of suffixes that ends with the relative suffix ki.
+func (t *turkishWord) chainBeforeKi() bool {
+	t.mark()
+	if !t.ki() {
+		return false
+	}
+	saved := t.save()
+	if t.dA() {
+		t.del()
+		t.attempt(func() bool {
+			t.mark()
+			saved := t.save()
+			if t.lAr() {
+				t.del()
+				t.attempt(t.chainBeforeKi)
+				return true
+			}
+			t.restore(saved)
+			if !t.possessives() {
+				return false
+			}
+			t.del()
+			t.attempt(t.lArChain)
+			return true
+		})
+		return true
+	}
+	t.restore(saved)
+	if t.nUn() {
+		t.del()
+		t.attempt(func() bool {
+			t.mark()
+			saved := t.save()
+			if t.lArI() {
+				t.del()
+				return true
+			}
+			t.restore(saved)
+			if first(t.possessives, t.sU) {
+				t.del()
+				t.attempt(t.lArChain)
+				return true
+			}
+			t.restore(saved)
+			return t.chainBeforeKi()
+		})
+		return true
+	}
+	t.restore(saved)
+	if !t.ndA() {
+		return false
+	}
+	saved = t.save()
+	if t.lArI() {
+		t.del()
+		return true
+	}
+	t.restore(saved)
+	if t.sU() {
+		t.del()
+		t.attempt(t.lArChain)
+		return true
+	}
+	t.restore(saved)
+	return t.chainBeforeKi()
+}
+
+// possessivesOrSU removes a possessive suffix or a single high vowel, then a plural suffix and
+// the suffixes before ki.
+func (t *turkishWord) possessivesOrSU() bool {
+	t.mark()
+	if !first(t.possessives, t.sU) {
+		return false
+	}
+	t.del()
+	t.attempt(t.lArChain)
+	return true
+}
+
+// nounSuffixes removes the case, possessive and plural suffixes of the nouns.
+func (t *turkishWord) nounSuffixes() bool {
+	saved := t.save()
+	t.mark()
+	if t.lAr() {
+		t.del()
+		t.attempt(t.chainBeforeKi)
+		return true
+	}
+
+	t.restore(saved)
+	t.mark()
+	if t.ncA() {
+		t.del()
+		t.attempt(func() bool {
+			saved := t.save()
+			t.mark()
+			if t.lArI() {
+				t.del()
+				return true
+			}
+			t.restore(saved)
+			if t.possessivesOrSU() {
+				return true
+			}
+			t.restore(saved)
+			return t.lArChain()
+		})
+		return true
+	}
+
+	t.restore(saved)
+	t.mark()
+	if first(t.ndA, t.nA) {
+		inner := t.save()
+		if t.lArI() {
+			t.del()
+			return true
+		}
+		t.restore(inner)
+		if t.sU() {
+			t.del()
+			t.attempt(t.lArChain)
+			return true
+		}
+		t.restore(inner)
+		if t.chainBeforeKi() {
+			return true
+		}
+	}
+
+	t.restore(saved)
+	t.mark()
+	if first(t.ndAn, t.nU) {
+		inner := t.save()
+		if t.sU() {
+			t.del()
+			t.attempt(t.lArChain)
+			return true
+		}
+		t.restore(inner)
+		if t.lArI() {
+			return true
+		}
+	}
+
+	t.restore(saved)
+	t.mark()
+	if t.dAn() {
+		t.del()
+		t.attempt(func() bool {
+			t.mark()
+			inner := t.save()
+			if t.possessives() {
+				t.del()
+				t.attempt(t.lArChain)
+				return true
+			}
+			t.restore(inner)
+			if t.lAr() {
+				t.del()
+				t.attempt(t.chainBeforeKi)
+				return true
+			}
+			t.restore(inner)
+			return t.chainBeforeKi()
+		})
+		return true
+	}
+
+	t.restore(saved)
+	t.mark()
+	if first(t.nUn, t.ylA) {
+		t.del()
+		t.attempt(func() bool {
+			inner := t.save()
+			if t.lArChain() {
+				return true
+			}
+			t.restore(inner)
+			if t.possessivesOrSU() {
+				return true
+			}
+			t.restore(inner)
+			return t.chainBeforeKi()
+		})
+		return true
+	}
+
+	t.restore(saved)
+	t.mark()
+	if t.lArI() {
+		t.del()
+		return true
+	}
+
+	t.restore(saved)
+	if t.chainBeforeKi() {
+		return true
+	}
+
+	t.restore(saved)
+	t.mark()
+	if first(t.dA, t.yU, t.yA) {
+		t.del()
+		t.attempt(func() bool {
+			t.mark()
+			inner := t.save()
+			if t.possessives() {
+				t.del()
+				t.attempt(func() bool {
+					t.mark()
+					return t.lAr()
+				})
+			} else {
+				t.restore(inner)
+				if !t.lAr() {
+					return false
+				}
+			}
+			t.del()
+			return t.chainBeforeKi()
+		})
+		return true
+	}
+
+	t.restore(saved)
+	return t.possessivesOrSU()
+}
+
+// turkish implements the snowball Turkish stemmer.
+func turkish(s string) string {
+	t := &turkishWord{rs: []rune(s)}
+	// the words of one syllable are not stemmed
+	n := 0
+	for _, r := range t.rs {
+		if turkishVowel(r) {
+			n++
+		}
+	}
+	if n < 2 {
+		return s
+	}
+
+	t.cursor = len(t.rs)
+	continueStemming := t.nominalVerbSuffixes()
+	if !continueStemming {
+		return string(t.rs)
+	}
+	t.cursor = len(t.rs)
+	t.nounSuffixes()
+
+	if stem := string(t.rs); stem == "ad" || stem == "soyad" {
+		return stem
+	}
+	// the stems ending with d or g get back the vowel of their suffix
+	if last := t.at(len(t.rs) - 1); last == 'd' || last == 'g' {
+		for i := len(t.rs) - 1; i >= 0; i-- {
+			if !turkishVowel(t.rs[i]) {
+				continue
+			}
+			switch t.rs[i] {
+			case 'a', 'ı':
+				t.rs = append(t.rs, 'ı')
+			case 'e', 'i':
+				t.rs = append(t.rs, 'i')
+			case 'o', 'u':
+				t.rs = append(t.rs, 'u')
+			case 'ö', 'ü':
+				t.rs = append(t.rs, 'ü')
+			}
+			break
+		}
+	}
+	// the last consonant is devoiced
+	if r, ok := turkishFinal[t.at(len(t.rs)-1)]; ok {
+		t.rs[len(t.rs)-1] = r
+	}
+	return string(t.rs)
+}
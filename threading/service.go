@@ -0,0 +1,163 @@
+package threading
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"go.uber.org/fx"
+)
+
+var saveInterval = time.Minute
+
+// maxContainers is the number of messages that are remembered.
+var maxContainers = 1000000
+
+type Threads interface {
+	utils.Service
+	utils.Prestartable
+	utils.Startable
+	utils.Closeable
+	Add(m Message) *models.Thread
+}
+
+type impl struct {
+	logger    log15.Logger
+	statePath string
+	store     *Store
+	lock      sync.Mutex
+	// version counts the changes of the store, and saved is the version of the state file
+	version uint64
+	saved   uint64
+}
+
+func NewThreads(statePath string, logger log15.Logger) Threads {
+	return &impl{
+		logger:    logger,
+		statePath: statePath,
+		store:     NewStore(maxContainers),
+	}
+}
+
+func (i *impl) Name() string {
+	return "Threads"
+}
+
+func (i *impl) Prestart() error {
+	if i.statePath == "" {
+		return nil
+	}
+	content, err := ioutil.ReadFile(i.statePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	store := NewStore(maxContainers)
+	err = gob.NewDecoder(bytes.NewReader(content)).Decode(store)
+	if err != nil {
+		i.logger.Warn("Invalid threads state, starting afresh", "path", i.statePath, "error", err)
+		return nil
+	}
+	store.MaxContainers = maxContainers
+	i.lock.Lock()
+	i.store = store
+	i.lock.Unlock()
+	i.logger.Info("Threads state loaded", "path", i.statePath, "messages", len(store.Containers), "threads", len(store.Threads))
+	return nil
+}
+
+// Start saves the state periodically.
+func (i *impl) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(saveInterval):
+		}
+		err := i.save()
+		if err != nil {
+			i.logger.Warn("Error saving the threads state", "error", err)
+		}
+	}
+}
+
+func (i *impl) Close() error {
+	return i.save()
+}
+
+func (i *impl) save() error {
+	if i.statePath == "" {
+		return nil
+	}
+	// the store is copied under the lock, and encoded without it
+	i.lock.Lock()
+	if i.version == i.saved {
+		i.lock.Unlock()
+		return nil
+	}
+	version := i.version
+	store := i.store.clone()
+	i.lock.Unlock()
+
+	var buf bytes.Buffer
+	err := gob.NewEncoder(&buf).Encode(store)
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(i.statePath), 0755)
+	if err != nil {
+		return err
+	}
+	tmp := i.statePath + ".tmp"
+	err = ioutil.WriteFile(tmp, buf.Bytes(), 0644)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp, i.statePath)
+	if err != nil {
+		return err
+	}
+	// the changes made during the save are saved next time
+	i.lock.Lock()
+	i.saved = version
+	i.lock.Unlock()
+	return nil
+}
+
+func (i *impl) Add(m Message) *models.Thread {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+	i.version++
+	return i.store.Add(m, time.Now())
+}
+
+type Params struct {
+	fx.In
+	Args   *arguments.Args `optional:"true"`
+	Logger log15.Logger    `optional:"true"`
+}
+
+var Service = fx.Provide(func(lc fx.Lifecycle, params Params) Threads {
+	if params.Args == nil || !params.Args.Threading.Enabled {
+		return nil
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	t := NewThreads(params.Args.Threading.StatePath, logger)
+	utils.Append(lc, t, logger)
+	return t
+})
@@ -0,0 +1,72 @@
+package threading
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/inconshreveable/log15"
+)
+
+func TestThreadsSave(t *testing.T) {
+	dir, err := ioutil.TempDir("", "threading")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "state", "threads.gob")
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+
+	threads := NewThreads(path, logger)
+	if err := threads.Prestart(); err != nil {
+		t.Fatal(err)
+	}
+	if err := threads.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("state saved without changes: %v", err)
+	}
+	root := threads.Add(Message{MessageID: "<1@example.org>", Subject: "Budget", From: "alice@example.org"})
+
+	// a failed save is retried
+	if err := os.MkdirAll(path+".tmp", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := threads.(*impl).save(); err == nil {
+		t.Fatal("save succeeded over a directory")
+	}
+	if err := os.Remove(path + ".tmp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := threads.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	reloaded := NewThreads(path, logger)
+	if err := reloaded.Prestart(); err != nil {
+		t.Fatal(err)
+	}
+	reply := reloaded.Add(Message{MessageID: "<2@example.com>", InReplyTo: "<1@example.org>", Subject: "Re: Budget"})
+	if reply.ThreadID != root.ThreadID || !reply.ParentSeen || reply.ParentFrom != "alice@example.org" || reply.Position != 2 {
+		t.Errorf("reply after reload: %+v", reply)
+	}
+}
+
+func TestStoreClone(t *testing.T) {
+	s := NewStore(100)
+	s.Add(Message{MessageID: "<1@example.org>", Subject: "Budget"}, time.Time{})
+	c := s.clone()
+	s.Add(Message{MessageID: "<2@example.org>", InReplyTo: "<1@example.org>", Subject: "Re: Budget"}, time.Time{})
+	if len(c.Containers) != 1 || len(c.Threads) != 1 || c.MaxContainers != 100 {
+		t.Errorf("clone: %d containers, %d threads", len(c.Containers), len(c.Threads))
+	}
+	for _, th := range c.Threads {
+		if th.Size != 1 {
+			t.Errorf("the clone shares the threads: size %d", th.Size)
+		}
+	}
+}
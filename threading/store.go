@@ -0,0 +1,302 @@
+// Package threading groups the mails in conversations, with a JWZ-style algorithm over the
+// Message-ID, In-Reply-To and References headers, and the normalized subjects. The mails are
+// threaded as they arrive: the thread of a mail is the thread of its known ancestors at that
+// time.
+package threading
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// Message holds the headers of a mail that are used for threading.
+type Message struct {
+	MessageID  string
+	InReplyTo  string
+	References string
+	Subject    string
+	From       string
+}
+
+// container is a node of the threads. The containers of the messages that have only been
+// referenced are placeholders.
+type container struct {
+	ID       string
+	ParentID string
+	ThreadID string
+	Seen     bool
+	From     string
+	Position int
+	LastSeen time.Time
+}
+
+type thread struct {
+	Subject  string
+	Size     int
+	LastSeen time.Time
+}
+
+// Store holds the known messages and threads. It is not safe for concurrent use.
+type Store struct {
+	Containers map[string]*container
+	Threads    map[string]*thread
+	// Subjects maps the normalized subjects to the last thread that used them
+	Subjects map[string]string
+	// MaxContainers bounds the size of the store: the oldest containers are forgotten beyond it
+	MaxContainers int
+}
+
+func NewStore(maxContainers int) *Store {
+	return &Store{
+		Containers:    make(map[string]*container),
+		Threads:       make(map[string]*thread),
+		Subjects:      make(map[string]string),
+		MaxContainers: maxContainers,
+	}
+}
+
+// clone returns a copy of the store, that does not share the containers and the threads.
+func (s *Store) clone() *Store {
+	c := &Store{
+		Containers:    make(map[string]*container, len(s.Containers)),
+		Threads:       make(map[string]*thread, len(s.Threads)),
+		Subjects:      make(map[string]string, len(s.Subjects)),
+		MaxContainers: s.MaxContainers,
+	}
+	containers := make([]container, 0, len(s.Containers))
+	for id, ct := range s.Containers {
+		containers = append(containers, *ct)
+		c.Containers[id] = &containers[len(containers)-1]
+	}
+	for id, t := range s.Threads {
+		copied := *t
+		c.Threads[id] = &copied
+	}
+	for subject, tid := range s.Subjects {
+		c.Subjects[subject] = tid
+	}
+	return c
+}
+
+var messageIDRE = regexp.MustCompile(`<[^<>\s]+>`)
+
+// ParseIDs returns the message IDs in a Message-ID, In-Reply-To or References header.
+func ParseIDs(header string) []string {
+	ids := messageIDRE.FindAllString(header, -1)
+	for i, id := range ids {
+		ids[i] = strings.ToLower(id)
+	}
+	return ids
+}
+
+var replyPrefixRE = regexp.MustCompile(`(?i)^\s*(re|aw|sv|vs|antw|odp|ynt|r|rif|réf|ref|fw|fwd|wg|tr|enc|rv)(\s*\[\d+\])?\s*:\s*`)
+var listTagRE = regexp.MustCompile(`^\s*\[[^\]]*\]\s*`)
+var isReplyRE = regexp.MustCompile(`(?i)^\s*(\[[^\]]*\]\s*)?(re|aw|sv|vs|antw|odp|ynt|r|rif|réf|ref)(\s*\[\d+\])?\s*:`)
+
+// NormalizeSubject removes the reply and forward prefixes and the mailing list tags.
+func NormalizeSubject(subject string) string {
+	for {
+		s := listTagRE.ReplaceAllString(subject, "")
+		s = replyPrefixRE.ReplaceAllString(s, "")
+		if s == subject {
+			break
+		}
+		subject = s
+	}
+	return strings.ToLower(strings.Join(strings.Fields(subject), " "))
+}
+
+// IsReply reports whether a subject has a reply prefix.
+func IsReply(subject string) bool {
+	return isReplyRE.MatchString(subject)
+}
+
+func threadID(rootID string) string {
+	h := sha1.Sum([]byte(rootID))
+	return hex.EncodeToString(h[:8])
+}
+
+func (s *Store) get(id string) *container {
+	c := s.Containers[id]
+	if c == nil {
+		c = &container{ID: id}
+		s.Containers[id] = c
+	}
+	return c
+}
+
+// isAncestor reports whether a is an ancestor of c (or c itself).
+func (s *Store) isAncestor(a, c *container) bool {
+	for depth := 0; c != nil && depth < 1000; depth++ {
+		if c == a {
+			return true
+		}
+		c = s.Containers[c.ParentID]
+	}
+	return false
+}
+
+func (s *Store) link(parent, child *container) {
+	if parent == child || s.isAncestor(child, parent) {
+		return
+	}
+	child.ParentID = parent.ID
+}
+
+// Add threads a message, and returns its position in its thread.
+func (s *Store) Add(m Message, now time.Time) *models.Thread {
+	ids := ParseIDs(m.MessageID)
+	if len(ids) == 0 {
+		return nil
+	}
+	id := ids[0]
+	refs := ParseIDs(m.References)
+	for _, irt := range ParseIDs(m.InReplyTo) {
+		if len(refs) == 0 || refs[len(refs)-1] != irt {
+			refs = append(refs, irt)
+		}
+	}
+	var filtered []string
+	for _, ref := range refs {
+		if ref != id {
+			filtered = append(filtered, ref)
+		}
+	}
+	refs = filtered
+
+	result := &models.Thread{MessageID: id, IsReply: IsReply(m.Subject)}
+	c := s.get(id)
+	duplicate := c.Seen
+
+	// which references are known messages, before they are created as placeholders
+	knownRef := false
+	for _, ref := range refs {
+		if r := s.Containers[ref]; r != nil && r.Seen {
+			knownRef = true
+		}
+	}
+
+	// link the references together, then the message to its last reference
+	var prev *container
+	for _, ref := range refs {
+		r := s.get(ref)
+		r.LastSeen = now
+		if prev != nil && r.ParentID == "" {
+			s.link(prev, r)
+		}
+		prev = r
+	}
+	if prev != nil && !duplicate {
+		if c.ParentID != "" && c.ParentID != prev.ID {
+			c.ParentID = ""
+		}
+		s.link(prev, c)
+	}
+
+	// the thread is the thread of the oldest known ancestor
+	subject := NormalizeSubject(m.Subject)
+	tid := c.ThreadID
+	for _, ref := range refs {
+		if tid != "" {
+			break
+		}
+		tid = s.Containers[ref].ThreadID
+	}
+	if tid == "" && len(refs) == 0 && result.IsReply && subject != "" {
+		// JWZ: the replies without references are grouped by subject
+		if t, ok := s.Subjects[subject]; ok && s.Threads[t] != nil {
+			tid = t
+			result.SubjectMatch = true
+		}
+	}
+	if tid == "" {
+		root := id
+		if len(refs) > 0 {
+			root = refs[0]
+		}
+		tid = threadID(root)
+	}
+	for _, ref := range refs {
+		if r := s.Containers[ref]; r.ThreadID == "" {
+			r.ThreadID = tid
+		}
+	}
+	c.ThreadID = tid
+	c.Seen = true
+	c.LastSeen = now
+	if m.From != "" {
+		c.From = strings.ToLower(m.From)
+	}
+
+	t := s.Threads[tid]
+	if t == nil {
+		t = &thread{Subject: subject}
+		s.Threads[tid] = t
+	}
+	if !duplicate {
+		t.Size++
+		c.Position = t.Size
+	}
+	t.LastSeen = now
+	if subject != "" {
+		s.Subjects[subject] = tid
+	}
+
+	result.ThreadID = tid
+	result.ThreadSize = t.Size
+	result.Position = c.Position
+	result.Duplicate = duplicate
+	if parent := s.Containers[c.ParentID]; parent != nil {
+		result.ParentID = parent.ID
+		result.ParentSeen = parent.Seen
+		result.ParentFrom = parent.From
+	}
+	for p := s.Containers[c.ParentID]; p != nil && result.Depth < 1000; p = s.Containers[p.ParentID] {
+		result.Depth++
+	}
+	if result.IsReply && !knownRef {
+		result.FakeReply = true
+		if len(refs) == 0 {
+			result.FakeReplyReason = "reply subject without In-Reply-To or References"
+		} else {
+			result.FakeReplyReason = "reply subject, but the referenced messages are unknown"
+		}
+	}
+	s.evict()
+	return result
+}
+
+// evict forgets the oldest tenth of the containers when the store is full.
+func (s *Store) evict() {
+	if s.MaxContainers <= 0 || len(s.Containers) <= s.MaxContainers {
+		return
+	}
+	all := make([]*container, 0, len(s.Containers))
+	for _, c := range s.Containers {
+		all = append(all, c)
+	}
+	sort.Slice(all, func(i, j int) bool { return all[i].LastSeen.Before(all[j].LastSeen) })
+	for _, c := range all[:len(all)-s.MaxContainers*9/10] {
+		delete(s.Containers, c.ID)
+	}
+	live := make(map[string]bool)
+	for _, c := range s.Containers {
+		live[c.ThreadID] = true
+	}
+	for tid := range s.Threads {
+		if !live[tid] {
+			delete(s.Threads, tid)
+		}
+	}
+	for subject, tid := range s.Subjects {
+		if !live[tid] {
+			delete(s.Subjects, subject)
+		}
+	}
+}
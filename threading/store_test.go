@@ -0,0 +1,148 @@
+package threading
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseIDs(t *testing.T) {
+	ids := ParseIDs(" <A1@Example.COM>\r\n\t<b2@example.org> garbage <not an id>")
+	if want := []string{"<a1@example.com>", "<b2@example.org>"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("ParseIDs = %q, want %q", ids, want)
+	}
+}
+
+func TestNormalizeSubject(t *testing.T) {
+	cases := map[string]string{
+		"Budget 2027":                          "budget 2027",
+		"Re: Budget 2027":                      "budget 2027",
+		"RE: [finance] Fwd: re:  Budget  2027": "budget 2027",
+		"AW: Re[2]: Budget 2027":               "budget 2027",
+		"[finance] Budget 2027":                "budget 2027",
+		"Réf : Budget 2027":                    "budget 2027",
+		"Regarding the budget":                 "regarding the budget",
+	}
+	for subject, want := range cases {
+		if s := NormalizeSubject(subject); s != want {
+			t.Errorf("NormalizeSubject(%q) = %q, want %q", subject, s, want)
+		}
+	}
+	replies := map[string]bool{
+		"Re: budget":           true,
+		"[finance] RE: budget": true,
+		"SV: budget":           true,
+		"Fwd: budget":          false,
+		"Regarding budget":     false,
+		"budget":               false,
+	}
+	for subject, want := range replies {
+		if IsReply(subject) != want {
+			t.Errorf("IsReply(%q) = %v", subject, !want)
+		}
+	}
+}
+
+func TestAdd(t *testing.T) {
+	s := NewStore(0)
+	now := time.Date(2026, 10, 12, 10, 0, 0, 0, time.UTC)
+
+	root := s.Add(Message{MessageID: "<a@example.org>", Subject: "Budget", From: "Alice@example.org"}, now)
+	if root.ThreadID == "" || root.Position != 1 || root.ThreadSize != 1 || root.Depth != 0 || root.IsReply || root.FakeReply {
+		t.Fatalf("root: %+v", root)
+	}
+
+	// In-Reply-To and References
+	b := s.Add(Message{MessageID: "<b@example.com>", InReplyTo: "<a@example.org>", References: "<a@example.org>", Subject: "Re: Budget", From: "bob@example.com"}, now)
+	if b.ThreadID != root.ThreadID || b.ParentID != "<a@example.org>" || !b.ParentSeen || b.ParentFrom != "alice@example.org" ||
+		b.Depth != 1 || b.Position != 2 || b.ThreadSize != 2 || !b.IsReply || b.FakeReply {
+		t.Errorf("reply: %+v", b)
+	}
+	// References only
+	c := s.Add(Message{MessageID: "<c@example.org>", References: "<a@example.org> <b@example.com>", Subject: "Re: Budget"}, now)
+	if c.ThreadID != root.ThreadID || c.ParentID != "<b@example.com>" || c.Depth != 2 || c.Position != 3 {
+		t.Errorf("references: %+v", c)
+	}
+	// In-Reply-To only
+	d := s.Add(Message{MessageID: "<d@example.org>", InReplyTo: "Carol <c@example.org>", Subject: "Re: Budget"}, now)
+	if d.ThreadID != root.ThreadID || d.ParentID != "<c@example.org>" || d.Depth != 3 || d.Position != 4 {
+		t.Errorf("in-reply-to: %+v", d)
+	}
+	// a duplicate does not grow the thread
+	dup := s.Add(Message{MessageID: "<b@example.com>", InReplyTo: "<a@example.org>", Subject: "Re: Budget"}, now)
+	if !dup.Duplicate || dup.Position != 2 || dup.ThreadSize != 4 {
+		t.Errorf("duplicate: %+v", dup)
+	}
+	// a message that references itself
+	self := s.Add(Message{MessageID: "<self@example.org>", References: "<self@example.org>", Subject: "Loop"}, now)
+	if self.ParentID != "" || self.Depth != 0 {
+		t.Errorf("self reference: %+v", self)
+	}
+	if s.Add(Message{Subject: "no id"}, now) != nil {
+		t.Error("a message without Message-ID is threaded")
+	}
+}
+
+func TestAddOutOfOrder(t *testing.T) {
+	s := NewStore(0)
+	now := time.Now()
+	// the reply arrives before the message it answers
+	reply := s.Add(Message{MessageID: "<2@example.org>", InReplyTo: "<1@example.org>", Subject: "Re: Lunch"}, now)
+	if !reply.FakeReply || reply.ParentSeen || reply.FakeReplyReason != "reply subject, but the referenced messages are unknown" {
+		t.Errorf("reply first: %+v", reply)
+	}
+	original := s.Add(Message{MessageID: "<1@example.org>", Subject: "Lunch"}, now)
+	if original.ThreadID != reply.ThreadID || original.Position != 2 || original.Depth != 0 {
+		t.Errorf("original after the reply: %+v", original)
+	}
+}
+
+func TestFakeReplies(t *testing.T) {
+	s := NewStore(0)
+	now := time.Now()
+	invoice := s.Add(Message{MessageID: "<1@example.org>", Subject: "RE: Invoice 4421"}, now)
+	if !invoice.FakeReply || invoice.FakeReplyReason != "reply subject without In-Reply-To or References" || invoice.SubjectMatch {
+		t.Errorf("reply without references: %+v", invoice)
+	}
+	// grouped by subject, but still a fake reply
+	again := s.Add(Message{MessageID: "<2@example.org>", Subject: "Re: invoice  4421"}, now)
+	if again.ThreadID != invoice.ThreadID || !again.SubjectMatch || !again.FakeReply {
+		t.Errorf("reply grouped by subject: %+v", again)
+	}
+	// a new message with the same subject is not grouped
+	fresh := s.Add(Message{MessageID: "<3@example.org>", Subject: "Invoice 4421"}, now)
+	if fresh.ThreadID == invoice.ThreadID || fresh.SubjectMatch || fresh.FakeReply {
+		t.Errorf("new message: %+v", fresh)
+	}
+	// a forward is not a reply
+	if fwd := s.Add(Message{MessageID: "<4@example.org>", Subject: "Fwd: Invoice 4421"}, now); fwd.FakeReply {
+		t.Errorf("forward: %+v", fwd)
+	}
+}
+
+func TestEvict(t *testing.T) {
+	s := NewStore(10)
+	start := time.Date(2026, 10, 12, 10, 0, 0, 0, time.UTC)
+	var first string
+	for i := 0; i < 25; i++ {
+		th := s.Add(Message{MessageID: fmt.Sprintf("<%d@example.org>", i), Subject: fmt.Sprintf("Subject %d", i)}, start.Add(time.Duration(i)*time.Minute))
+		if i == 0 {
+			first = th.ThreadID
+		}
+		if len(s.Containers) > 10 {
+			t.Fatalf("%d containers after %d messages", len(s.Containers), i+1)
+		}
+	}
+	if s.Containers["<0@example.org>"] != nil || s.Threads[first] != nil || s.Subjects["subject 0"] != "" {
+		t.Error("the oldest message is remembered")
+	}
+	if s.Containers["<24@example.org>"] == nil || len(s.Threads) != len(s.Containers) || len(s.Subjects) != len(s.Containers) {
+		t.Errorf("%d containers, %d threads, %d subjects", len(s.Containers), len(s.Threads), len(s.Subjects))
+	}
+	// a reply to an evicted message starts over
+	reply := s.Add(Message{MessageID: "<r@example.org>", InReplyTo: "<0@example.org>", Subject: "Re: Subject 0"}, start.Add(time.Hour))
+	if reply.ParentSeen || !reply.FakeReply {
+		t.Errorf("reply to an evicted message: %+v", reply)
+	}
+}
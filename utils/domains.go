@@ -0,0 +1,80 @@
+package utils
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// second level labels that are commonly used as public suffixes under a ccTLD (co.uk, com.au...)
+var secondLevelSuffixes = map[string]bool{
+	"co": true, "com": true, "net": true, "org": true, "gov": true, "edu": true,
+	"ac": true, "gouv": true, "asso": true, "ne": true, "or": true, "go": true,
+}
+
+// RegisteredDomain returns an approximation of the registrable part of host
+// (eg "mail.example.co.uk" gives "example.co.uk").
+func RegisteredDomain(host string) string {
+	host = strings.Trim(strings.ToLower(strings.TrimSpace(host)), ".")
+	if host == "" || net.ParseIP(host) != nil {
+		return host
+	}
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	n := 2
+	if len(labels[len(labels)-1]) == 2 && secondLevelSuffixes[labels[len(labels)-2]] {
+		n = 3
+	}
+	return strings.Join(labels[len(labels)-n:], ".")
+}
+
+// SameDomain reports whether both hosts belong to the same registered domain.
+func SameDomain(host1, host2 string) bool {
+	return RegisteredDomain(host1) == RegisteredDomain(host2)
+}
+
+// URLHost returns the lowercased host part of u, or an empty string if u can not be parsed.
+func URLHost(u string) string {
+	u = strings.TrimSpace(u)
+	if strings.HasPrefix(u, "//") {
+		u = "http:" + u
+	} else if !strings.Contains(u, "://") {
+		u = "http://" + u
+	}
+	pu, err := url.Parse(u)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(pu.Hostname())
+}
+
+// DomainFromAddress returns the lowercased domain part of an email address.
+func DomainFromAddress(addr string) string {
+	addr = strings.Trim(strings.TrimSpace(addr), "<>")
+	idx := strings.LastIndex(addr, "@")
+	if idx == -1 {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(addr[idx+1:]))
+}
+
+// freeMailDomains are the domains of the main free webmail providers.
+var freeMailDomains = map[string]bool{
+	"gmail.com": true, "googlemail.com": true, "yahoo.com": true, "yahoo.fr": true, "yahoo.co.uk": true,
+	"ymail.com": true, "rocketmail.com": true, "hotmail.com": true, "hotmail.fr": true, "hotmail.co.uk": true,
+	"outlook.com": true, "outlook.fr": true, "live.com": true, "live.fr": true, "msn.com": true,
+	"aol.com": true, "icloud.com": true, "me.com": true, "mac.com": true, "gmx.com": true, "gmx.de": true,
+	"gmx.net": true, "gmx.fr": true, "web.de": true, "mail.com": true, "protonmail.com": true,
+	"proton.me": true, "pm.me": true, "tutanota.com": true, "zoho.com": true, "yandex.com": true,
+	"yandex.ru": true, "mail.ru": true, "inbox.ru": true, "list.ru": true, "bk.ru": true,
+	"orange.fr": true, "wanadoo.fr": true, "free.fr": true, "laposte.net": true, "sfr.fr": true,
+	"t-online.de": true, "libero.it": true, "qq.com": true, "163.com": true, "126.com": true,
+	"naver.com": true, "fastmail.com": true, "hushmail.com": true, "mailfence.com": true,
+}
+
+// IsFreeMail reports whether domain belongs to a free webmail provider.
+func IsFreeMail(domain string) bool {
+	return freeMailDomains[strings.Trim(strings.ToLower(strings.TrimSpace(domain)), ".")]
+}
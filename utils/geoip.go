@@ -3,6 +3,8 @@ package utils
 import (
 	"archive/tar"
 	"compress/gzip"
+	"context"
+	"github.com/fsnotify/fsnotify"
 	"github.com/inconshreveable/log15"
 	"github.com/oschwald/geoip2-golang"
 	"github.com/pkg/errors"
@@ -10,40 +12,79 @@ import (
 	"github.com/stephane-martin/mailstats/models"
 	"go.uber.org/fx"
 	"io"
+	"io/ioutil"
 	"net"
 	"net/http"
 	"net/url"
 	"os"
 	"path/filepath"
 	"strings"
+	"sync"
+	"time"
 )
 
-var geoipReader *geoip2.Reader
 var geoipFile = "/var/lib/mailstats/GeoLite2-City/GeoLite2-City.mmdb"
 var GeoIPURL = "https://geolite.maxmind.com/download/geoip/database/GeoLite2-City.tar.gz"
 
+// reloadDelay is the time to wait after the last change of a database file before reloading it.
+var reloadDelay = 2 * time.Second
+
 type GeoIP interface {
 	Service
 	GeoIP(ip net.IP) (*models.GeoIPResult, error)
 }
 
 type geoIPImpl struct {
-	reader *geoip2.Reader
+	lock        sync.RWMutex
+	city        *geoip2.Reader
+	country     *geoip2.Reader
+	asn         *geoip2.Reader
+	cityPath    string
+	countryPath string
+	asnPath     string
+	hosting     map[uint]bool
+	watcher     *fsnotify.Watcher
+	logger      log15.Logger
 }
 
-func NewGeoIP(databasePath string) (GeoIP, error) {
-	r, err := geoip2.Open(databasePath)
-	if err != nil {
-		return nil, err
+// NewGeoIP opens the MaxMind City, Country and ASN databases. Only the configured ones are opened.
+func NewGeoIP(args arguments.GeoIPArgs, logger log15.Logger) (GeoIP, error) {
+	g := &geoIPImpl{
+		cityPath:    args.DatabasePath,
+		countryPath: args.CountryDatabasePath,
+		asnPath:     args.ASNDatabasePath,
+		hosting:     HostingASNs,
+		logger:      logger,
+	}
+	if args.HostingASNsPath != "" {
+		f, err := os.Open(args.HostingASNsPath)
+		if err != nil {
+			return nil, err
+		}
+		g.hosting, err = ReadASNs(f)
+		_ = f.Close()
+		if err != nil {
+			return nil, err
+		}
 	}
-	return &geoIPImpl{reader: r}, nil
+	for _, path := range []string{g.cityPath, g.countryPath, g.asnPath} {
+		if path == "" {
+			continue
+		}
+		err := g.reload(path)
+		if err != nil {
+			_ = g.Close()
+			return nil, err
+		}
+	}
+	return g, nil
 }
 
 var GeoIPService = fx.Provide(func(lc fx.Lifecycle, args *arguments.Args, logger log15.Logger) (GeoIP, error) {
 	if !args.GeoIP.Enabled {
 		return nil, nil
 	}
-	s, err := NewGeoIP(args.GeoIP.DatabasePath)
+	s, err := NewGeoIP(args.GeoIP, logger)
 	if err != nil {
 		return nil, err
 	}
@@ -55,10 +96,111 @@ var GeoIPService = fx.Provide(func(lc fx.Lifecycle, args *arguments.Args, logger
 
 func (g *geoIPImpl) Name() string { return "GeoIP"}
 
+// reload opens the database at path, and replaces the previous reader. The database is read in
+// memory instead of being mapped, so that a file rewritten in place does not corrupt the reader
+// in use.
+func (g *geoIPImpl) reload(path string) error {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	r, err := geoip2.FromBytes(content)
+	if err != nil {
+		return err
+	}
+	g.lock.Lock()
+	var old *geoip2.Reader
+	switch path {
+	case g.cityPath:
+		old, g.city = g.city, r
+	case g.countryPath:
+		old, g.country = g.country, r
+	case g.asnPath:
+		old, g.asn = g.asn, r
+	}
+	g.lock.Unlock()
+	if old != nil {
+		_ = old.Close()
+	}
+	return nil
+}
+
+// Start watches the database files, and reloads them when they change on disk.
+func (g *geoIPImpl) Start(ctx context.Context) error {
+	w, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	//noinspection GoUnhandledErrorResult
+	defer w.Close()
+	paths := make(map[string]bool)
+	for _, path := range []string{g.cityPath, g.countryPath, g.asnPath} {
+		if path == "" {
+			continue
+		}
+		path = filepath.Clean(path)
+		paths[path] = true
+		// watch the directory: the databases are usually replaced by a rename
+		err := w.Add(filepath.Dir(path))
+		if err != nil {
+			return err
+		}
+	}
+	pending := make(map[string]bool)
+	timer := time.NewTimer(reloadDelay)
+	timer.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil
+		case event, ok := <-w.Events:
+			if !ok {
+				return nil
+			}
+			name := filepath.Clean(event.Name)
+			if paths[name] && event.Op&(fsnotify.Create|fsnotify.Write|fsnotify.Rename) != 0 {
+				pending[name] = true
+				timer.Reset(reloadDelay)
+			}
+		case err, ok := <-w.Errors:
+			if !ok {
+				return nil
+			}
+			g.logger.Warn("GeoIP watcher reported error", "error", err)
+		case <-timer.C:
+			for path := range pending {
+				err := g.reload(g.configuredPath(path))
+				if err != nil {
+					g.logger.Warn("Error reloading GeoIP database", "path", path, "error", err)
+				} else {
+					g.logger.Info("GeoIP database reloaded", "path", path)
+				}
+			}
+			pending = make(map[string]bool)
+		}
+	}
+}
+
+// configuredPath returns the path as given in the configuration.
+func (g *geoIPImpl) configuredPath(clean string) string {
+	for _, path := range []string{g.cityPath, g.countryPath, g.asnPath} {
+		if path != "" && filepath.Clean(path) == clean {
+			return path
+		}
+	}
+	return clean
+}
+
 func (g *geoIPImpl) Close() error {
-	if g.reader != nil {
-		return g.reader.Close()
+	g.lock.Lock()
+	defer g.lock.Unlock()
+	for _, r := range []*geoip2.Reader{g.city, g.country, g.asn} {
+		if r != nil {
+			_ = r.Close()
+		}
 	}
+	g.city, g.country, g.asn = nil, nil, nil
 	return nil
 }
 
@@ -67,19 +209,47 @@ func (g *geoIPImpl) GeoIP(ip net.IP) (*models.GeoIPResult, error) {
 	if g == nil {
 		return nil, errors.New("GeoIP database not loaded")
 	}
-	c, err := geoipReader.City(ip)
-	if err != nil {
-		return nil, err
+	g.lock.RLock()
+	defer g.lock.RUnlock()
+	if g.city == nil && g.country == nil && g.asn == nil {
+		return nil, errors.New("GeoIP database not loaded")
 	}
-	return &models.GeoIPResult{
-		Country:   c.Country.Names["en"],
-		Continent: c.Continent.Names["en"],
-		City:      c.City.Names["en"],
-		Coordinates: &models.LatLon{
+	result := new(models.GeoIPResult)
+	if g.city != nil {
+		c, err := g.city.City(ip)
+		if err != nil {
+			return nil, err
+		}
+		result.Country = c.Country.Names["en"]
+		result.CountryCode = c.Country.IsoCode
+		result.Continent = c.Continent.Names["en"]
+		result.City = c.City.Names["en"]
+		result.Coordinates = &models.LatLon{
 			Latitude:  c.Location.Latitude,
 			Longitude: c.Location.Longitude,
-		},
-	}, nil
+		}
+	} else if g.country != nil {
+		c, err := g.country.Country(ip)
+		if err != nil {
+			return nil, err
+		}
+		result.Country = c.Country.Names["en"]
+		result.CountryCode = c.Country.IsoCode
+		result.Continent = c.Continent.Names["en"]
+	}
+	if g.asn != nil {
+		a, err := g.asn.ASN(ip)
+		if err != nil {
+			return nil, err
+		}
+		result.ASN = a.AutonomousSystemNumber
+		result.ASOrganization = a.AutonomousSystemOrganization
+		result.Hosting = g.hosting[a.AutonomousSystemNumber]
+	}
+	if result.CountryCode == "" && result.ASN == 0 {
+		return nil, errors.New("IP address not found in the GeoIP databases")
+	}
+	return result, nil
 }
 
 
@@ -0,0 +1,368 @@
+package utils
+
+import (
+	"encoding/binary"
+	"io/ioutil"
+	"math"
+	"net"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+// mmdbControl encodes the control byte of a MaxMind DB field.
+func mmdbControl(typ, size int) []byte {
+	var b []byte
+	switch {
+	case size < 29:
+		b = []byte{byte(size)}
+	default:
+		b = []byte{29, byte(size - 29)}
+	}
+	if typ > 7 {
+		return append([]byte{b[0], byte(typ - 7)}, b[1:]...)
+	}
+	b[0] |= byte(typ << 5)
+	return b
+}
+
+// mmdbEncode encodes a value in the MaxMind DB data format.
+func mmdbEncode(v interface{}) []byte {
+	switch v := v.(type) {
+	case string:
+		return append(mmdbControl(2, len(v)), v...)
+	case float64:
+		b := make([]byte, 8)
+		binary.BigEndian.PutUint64(b, math.Float64bits(v))
+		return append(mmdbControl(3, 8), b...)
+	case uint:
+		var b []byte
+		for ; v > 0; v >>= 8 {
+			b = append([]byte{byte(v)}, b...)
+		}
+		return append(mmdbControl(6, len(b)), b...)
+	case []string:
+		b := mmdbControl(11, len(v))
+		for _, s := range v {
+			b = append(b, mmdbEncode(s)...)
+		}
+		return b
+	case map[string]interface{}:
+		keys := make([]string, 0, len(v))
+		for k := range v {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		b := mmdbControl(7, len(v))
+		for _, k := range keys {
+			b = append(b, mmdbEncode(k)...)
+			b = append(b, mmdbEncode(v[k])...)
+		}
+		return b
+	}
+	panic("unsupported type")
+}
+
+// writeMMDB writes an IPv4 MaxMind DB that maps the networks, given in CIDR notation, to the records.
+func writeMMDB(t *testing.T, path, databaseType string, records map[string]map[string]interface{}) {
+	t.Helper()
+	// the records of the nodes are the next node, -1 when empty, or -2-offset for the data at offset
+	nodes := [][2]int{{-1, -1}}
+	var data []byte
+	for cidr, record := range records {
+		_, network, err := net.ParseCIDR(cidr)
+		if err != nil {
+			t.Fatal(err)
+		}
+		ones, _ := network.Mask.Size()
+		ip := binary.BigEndian.Uint32(network.IP.To4())
+		node := 0
+		for i := 0; i < ones; i++ {
+			bit := int(ip>>uint(31-i)) & 1
+			if i == ones-1 {
+				nodes[node][bit] = -2 - len(data)
+				break
+			}
+			if nodes[node][bit] < 0 {
+				nodes = append(nodes, [2]int{-1, -1})
+				nodes[node][bit] = len(nodes) - 1
+			}
+			node = nodes[node][bit]
+		}
+		data = append(data, mmdbEncode(record)...)
+	}
+	var db []byte
+	for _, n := range nodes {
+		for _, r := range n {
+			value := uint32(r)
+			switch {
+			case r == -1:
+				value = uint32(len(nodes))
+			case r < -1:
+				value = uint32(len(nodes) + 16 - 2 - r)
+			}
+			db = append(db, 0, 0, 0, 0)
+			binary.BigEndian.PutUint32(db[len(db)-4:], value)
+		}
+	}
+	db = append(db, make([]byte, 16)...)
+	db = append(db, data...)
+	db = append(db, "\xAB\xCD\xEFMaxMind.com"...)
+	db = append(db, mmdbEncode(map[string]interface{}{
+		"binary_format_major_version": uint(2),
+		"binary_format_minor_version": uint(0),
+		"build_epoch":                 uint(1500000000),
+		"database_type":               databaseType,
+		"description":                 map[string]interface{}{"en": "test database"},
+		"ip_version":                  uint(4),
+		"languages":                   []string{"en"},
+		"node_count":                  uint(len(nodes)),
+		"record_size":                 uint(32),
+	})...)
+	if err := ioutil.WriteFile(path, db, 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func asnRecord(asn uint, org string) map[string]interface{} {
+	return map[string]interface{}{"autonomous_system_number": asn, "autonomous_system_organization": org}
+}
+
+func countryRecord(code, country, continent string) map[string]interface{} {
+	return map[string]interface{}{
+		"country":   map[string]interface{}{"iso_code": code, "names": map[string]interface{}{"en": country}},
+		"continent": map[string]interface{}{"names": map[string]interface{}{"en": continent}},
+	}
+}
+
+func testGeoIPDatabases(t *testing.T) (dir string) {
+	dir, err := ioutil.TempDir("", "geoip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	writeMMDB(t, filepath.Join(dir, "asn.mmdb"), "GeoLite2-ASN", map[string]map[string]interface{}{
+		"52.0.0.0/8":      asnRecord(16509, "AMAZON-02"),
+		"80.8.0.0/13":     asnRecord(3215, "Orange"),
+		"159.89.0.0/16":   asnRecord(14061, "DIGITALOCEAN-ASN"),
+		"193.51.224.0/19": asnRecord(2200, "Renater"),
+	})
+	writeMMDB(t, filepath.Join(dir, "country.mmdb"), "GeoLite2-Country", map[string]map[string]interface{}{
+		"52.0.0.0/8":    countryRecord("US", "United States", "North America"),
+		"80.0.0.0/8":    countryRecord("FR", "France", "Europe"),
+		"159.89.0.0/16": countryRecord("DE", "Germany", "Europe"),
+	})
+	return dir
+}
+
+func discardLogger() log15.Logger {
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	return logger
+}
+
+func TestGeoIPHosting(t *testing.T) {
+	dir := testGeoIPDatabases(t)
+	defer os.RemoveAll(dir)
+	g, err := NewGeoIP(arguments.GeoIPArgs{
+		CountryDatabasePath: filepath.Join(dir, "country.mmdb"),
+		ASNDatabasePath:     filepath.Join(dir, "asn.mmdb"),
+	}, discardLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.(*geoIPImpl).Close()
+
+	cases := []struct {
+		ip     string
+		result *models.GeoIPResult
+	}{
+		{"52.94.76.10", &models.GeoIPResult{Country: "United States", CountryCode: "US", Continent: "North America",
+			ASN: 16509, ASOrganization: "AMAZON-02", Hosting: true}},
+		{"159.89.10.20", &models.GeoIPResult{Country: "Germany", CountryCode: "DE", Continent: "Europe",
+			ASN: 14061, ASOrganization: "DIGITALOCEAN-ASN", Hosting: true}},
+		{"80.12.24.1", &models.GeoIPResult{Country: "France", CountryCode: "FR", Continent: "Europe",
+			ASN: 3215, ASOrganization: "Orange"}},
+		// only in the country database
+		{"80.0.0.1", &models.GeoIPResult{Country: "France", CountryCode: "FR", Continent: "Europe"}},
+		// only in the ASN database
+		{"193.51.224.1", &models.GeoIPResult{ASN: 2200, ASOrganization: "Renater"}},
+		{"::ffff:52.94.76.10", &models.GeoIPResult{Country: "United States", CountryCode: "US", Continent: "North America",
+			ASN: 16509, ASOrganization: "AMAZON-02", Hosting: true}},
+		{"10.0.0.1", nil},
+		{"2001:db8::1", nil},
+	}
+	for _, c := range cases {
+		result, err := g.GeoIP(net.ParseIP(c.ip))
+		if c.result == nil {
+			if err == nil {
+				t.Errorf("%s: expected an error, got %+v", c.ip, result)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.ip, err)
+			continue
+		}
+		if !reflect.DeepEqual(result, c.result) {
+			t.Errorf("%s: expected %+v, got %+v", c.ip, c.result, result)
+		}
+	}
+}
+
+func TestGeoIPHostingList(t *testing.T) {
+	dir := testGeoIPDatabases(t)
+	defer os.RemoveAll(dir)
+	list := filepath.Join(dir, "hosting.txt")
+	err := ioutil.WriteFile(list, []byte("# the providers of the tests\nAS3215 # Orange\n14061\n"), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g, err := NewGeoIP(arguments.GeoIPArgs{
+		ASNDatabasePath: filepath.Join(dir, "asn.mmdb"),
+		HostingASNsPath: list,
+	}, discardLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.(*geoIPImpl).Close()
+
+	// the list replaces the bundled one
+	hosting := map[string]bool{"52.94.76.10": false, "80.12.24.1": true, "159.89.10.20": true, "193.51.224.1": false}
+	for ip, expected := range hosting {
+		result, err := g.GeoIP(net.ParseIP(ip))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", ip, err)
+			continue
+		}
+		if result.Hosting != expected {
+			t.Errorf("%s: expected hosting %t, got %t", ip, expected, result.Hosting)
+		}
+		if result.CountryCode != "" {
+			t.Errorf("%s: unexpected country %q without the country database", ip, result.CountryCode)
+		}
+	}
+
+	_, err = NewGeoIP(arguments.GeoIPArgs{
+		ASNDatabasePath: filepath.Join(dir, "asn.mmdb"),
+		HostingASNsPath: filepath.Join(dir, "missing.txt"),
+	}, discardLogger())
+	if err == nil {
+		t.Error("expected an error for a missing list")
+	}
+}
+
+func TestGeoIPReload(t *testing.T) {
+	dir := testGeoIPDatabases(t)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "asn.mmdb")
+	g, err := NewGeoIP(arguments.GeoIPArgs{ASNDatabasePath: path}, discardLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer g.(*geoIPImpl).Close()
+	ip := net.ParseIP("80.12.24.1")
+	if result, err := g.GeoIP(ip); err != nil || result.ASN != 3215 {
+		t.Fatalf("unexpected result %+v, %v", result, err)
+	}
+
+	// the databases are replaced by a rename
+	writeMMDB(t, path+".new", "GeoLite2-ASN", map[string]map[string]interface{}{
+		"80.12.0.0/16": asnRecord(5410, "Bouygues Telecom"),
+	})
+	if err := os.Rename(path+".new", path); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.(*geoIPImpl).reload(path); err != nil {
+		t.Fatal(err)
+	}
+	if result, err := g.GeoIP(ip); err != nil || result.ASN != 5410 || result.ASOrganization != "Bouygues Telecom" {
+		t.Errorf("unexpected result after the reload %+v, %v", result, err)
+	}
+	if _, err := g.GeoIP(net.ParseIP("52.94.76.10")); err == nil {
+		t.Error("expected an error for an address removed by the reload")
+	}
+
+	// a broken database is not loaded, and the previous one is kept
+	if err := ioutil.WriteFile(path, []byte("not a database"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := g.(*geoIPImpl).reload(path); err == nil {
+		t.Error("expected an error for a broken database")
+	}
+	if result, err := g.GeoIP(ip); err != nil || result.ASN != 5410 {
+		t.Errorf("unexpected result after a failed reload %+v, %v", result, err)
+	}
+}
+
+func TestGeoIPNotLoaded(t *testing.T) {
+	var nilGeoIP *geoIPImpl
+	if _, err := nilGeoIP.GeoIP(net.ParseIP("52.94.76.10")); err == nil {
+		t.Error("expected an error for a nil GeoIP")
+	}
+	g, err := NewGeoIP(arguments.GeoIPArgs{}, discardLogger())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := g.GeoIP(net.ParseIP("52.94.76.10")); err == nil {
+		t.Error("expected an error without databases")
+	}
+	if _, err := NewGeoIP(arguments.GeoIPArgs{ASNDatabasePath: "/nonexistent/asn.mmdb"}, discardLogger()); err == nil {
+		t.Error("expected an error for a missing database")
+	}
+}
+
+func TestReadASNs(t *testing.T) {
+	cases := []struct {
+		list string
+		asns map[uint]bool
+		err  string
+	}{
+		{"", map[uint]bool{}, ""},
+		{"16509\n", map[uint]bool{16509: true}, ""},
+		{"AS16509\nas14061\n  As8075  \n", map[uint]bool{16509: true, 14061: true, 8075: true}, ""},
+		{"# cloud providers\n\n16509 # Amazon\n\t\n396982#Google", map[uint]bool{16509: true, 396982: true}, ""},
+		{"16509\r\n14061\r\n", map[uint]bool{16509: true, 14061: true}, ""},
+		{"4294967295", map[uint]bool{4294967295: true}, ""},
+		{"16509\nAmazon\n", nil, "invalid ASN on line 2: Amazon"},
+		{"AS-16509", nil, "invalid ASN on line 1: AS-16509"},
+		{"4294967296", nil, "invalid ASN on line 1: 4294967296"},
+		{"16509 14061", nil, "invalid ASN on line 1: 16509 14061"},
+	}
+	for _, c := range cases {
+		asns, err := ReadASNs(strings.NewReader(c.list))
+		if c.err != "" {
+			if err == nil || err.Error() != c.err {
+				t.Errorf("%q: expected error %q, got %v", c.list, c.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: unexpected error: %s", c.list, err)
+			continue
+		}
+		if !reflect.DeepEqual(asns, c.asns) {
+			t.Errorf("%q: expected %v, got %v", c.list, c.asns, asns)
+		}
+	}
+}
+
+func TestHostingASNs(t *testing.T) {
+	for _, asn := range []uint{16509, 14618, 396982, 8075, 14061, 16276, 24940} {
+		if !HostingASNs[asn] {
+			t.Errorf("AS%d is not a hosting provider", asn)
+		}
+	}
+	// the access providers and the mail providers
+	for _, asn := range []uint{3215, 7922, 15169, 2200} {
+		if HostingASNs[asn] {
+			t.Errorf("AS%d is a hosting provider", asn)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// HostingASNs are the autonomous systems of the main hosting and cloud providers. Mails sent
+// from them are usually not sent by the mail servers of an organization.
+var HostingASNs = map[uint]bool{
+	16509:  true, // Amazon AWS
+	14618:  true, // Amazon AWS
+	396982: true, // Google Cloud
+	8075:   true, // Microsoft Azure
+	14061:  true, // DigitalOcean
+	16276:  true, // OVH
+	24940:  true, // Hetzner
+	63949:  true, // Linode
+	20473:  true, // Vultr (Choopa)
+	51167:  true, // Contabo
+	12876:  true, // Scaleway
+	45102:  true, // Alibaba Cloud
+	132203: true, // Tencent Cloud
+	31898:  true, // Oracle Cloud
+	36351:  true, // IBM Cloud (SoftLayer)
+	60781:  true, // Leaseweb
+	28753:  true, // Leaseweb
+	26496:  true, // GoDaddy
+	47583:  true, // Hostinger
+	9009:   true, // M247
+	60068:  true, // Datacamp
+	8560:   true, // IONOS
+	27357:  true, // Rackspace
+	46606:  true, // Unified Layer
+	197695: true, // Reg.ru
+	49505:  true, // Selectel
+	40021:  true, // Contabo US
+	212238: true, // Datacamp
+	53667:  true, // FranTech (BuyVM)
+	35916:  true, // Multacom
+	62567:  true, // DigitalOcean
+	135377: true, // UCloud
+}
+
+// ReadASNs reads a list of ASNs, one per line, with an optional "AS" prefix. The text after a #
+// is a comment.
+func ReadASNs(r io.Reader) (map[uint]bool, error) {
+	asns := make(map[uint]bool)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := scanner.Text()
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		line = strings.TrimPrefix(strings.ToUpper(line), "AS")
+		asn, err := strconv.ParseUint(line, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ASN on line %d: %s", n, scanner.Text())
+		}
+		asns[uint(asn)] = true
+	}
+	return asns, scanner.Err()
+}
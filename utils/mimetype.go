@@ -18,10 +18,36 @@ var IcalType = filetype.NewType("ics", "text/calendar")
 var MarkdownType = filetype.NewType("md", "text/markdown")
 var RestType = filetype.NewType("rst", "text/x-rst")
 var HTMLType = filetype.NewType("html", "text/html")
+var LnkType = filetype.NewType("lnk", "application/x-ms-shortcut")
+var OneNoteType = filetype.NewType("one", "application/onenote")
+var ChmType = filetype.NewType("chm", "application/vnd.ms-htmlhelp")
+var IsoType = filetype.NewType("iso", "application/x-iso9660-image")
 var icalBegin = []byte("BEGIN:VCALENDAR")
 
+// lnkHeader is the header size followed by the LinkCLSID of Windows shortcuts.
+var lnkHeader = []byte{
+	0x4c, 0x00, 0x00, 0x00, 0x01, 0x14, 0x02, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xc0, 0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0x46,
+}
+
+// oneNoteHeader is the GUID that starts OneNote sections (MS-ONESTORE).
+var oneNoteHeader = []byte{
+	0xe4, 0x52, 0x5c, 0x7b, 0x8c, 0xd8, 0xa7, 0x4d,
+	0xae, 0xb1, 0x53, 0x78, 0xd0, 0x29, 0x96, 0xd3,
+}
+
 func init() {
 	filetype.AddMatcher(OdtType, odtMatcher)
+	filetype.AddMatcher(LnkType, func(buf []byte) bool { return bytes.HasPrefix(buf, lnkHeader) })
+	filetype.AddMatcher(OneNoteType, func(buf []byte) bool { return bytes.HasPrefix(buf, oneNoteHeader) })
+	filetype.AddMatcher(ChmType, func(buf []byte) bool { return bytes.HasPrefix(buf, []byte("ITSF\x03\x00\x00\x00")) })
+	filetype.AddMatcher(IsoType, isoMatcher)
+}
+
+// isoMatcher looks for the primary volume descriptor, at the beginning of the 16th sector.
+// The content must be at least 32KB long: GuessReader reads enough of it.
+func isoMatcher(buf []byte) bool {
+	return len(buf) > 0x8006 && string(buf[0x8001:0x8006]) == "CD001"
 }
 
 func odtMatcher(buf []byte) bool {
@@ -41,17 +67,24 @@ func icalMatcher(buf []byte) bool {
 	return bytes.Contains(buf[:28], icalBegin)
 }
 
+// guessSize is the size of the content that is read to guess the type. It covers the ISO 9660
+// volume descriptor, that begins at 32KB.
+const guessSize = 0x8800
+
 func GuessReader(filename string, reader io.Reader) (types.Type, io.Reader, error) {
 	b := new(bytes.Buffer)
-	b.Grow(8192)
-	buffer := make([]byte, 8192)
-	_, err := io.ReadFull(io.TeeReader(reader, b), buffer)
+	b.Grow(guessSize)
+	buffer := make([]byte, guessSize)
+	n, err := io.ReadFull(io.TeeReader(reader, b), buffer)
 	reader = io.MultiReader(b, reader)
 	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
 		return types.Unknown, reader, err
 	}
+	if n == 0 {
+		return types.Unknown, reader, nil
+	}
 
-	t, err := Guess(filename, buffer)
+	t, err := Guess(filename, buffer[:n])
 	return t, reader, err
 }
 
@@ -60,6 +93,10 @@ func Guess(filename string, content []byte) (types.Type, error) {
 	if err != nil {
 		return t, err
 	}
+	if t == types.Unknown && bytes.HasPrefix(content, []byte(`{\rt`)) {
+		// Word opens RTF documents with an incomplete header
+		return matchers.TypeRtf, nil
+	}
 	if t == matchers.TypeZip {
 		if matchers.Docx(content) {
 			return matchers.TypeDocx, nil
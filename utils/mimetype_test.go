@@ -0,0 +1,56 @@
+package utils
+
+import (
+	"bytes"
+	"io/ioutil"
+	"testing"
+
+	"github.com/h2non/filetype/types"
+)
+
+// isoImage returns the beginning of an ISO 9660 image: the system area and the primary volume descriptor.
+func isoImage(size int) []byte {
+	content := make([]byte, size)
+	copy(content[0x8000:], "\x01CD001\x01")
+	return content
+}
+
+func TestGuessReader(t *testing.T) {
+	cases := []struct {
+		name    string
+		content []byte
+		mime    string
+	}{
+		{"iso", isoImage(0x8800), "application/x-iso9660-image"},
+		{"large iso", isoImage(1 << 20), "application/x-iso9660-image"},
+		{"truncated iso", isoImage(0x8000), ""},
+		{"lnk", append(append([]byte{}, lnkHeader...), make([]byte, 100)...), "application/x-ms-shortcut"},
+		{"onenote", append(append([]byte{}, oneNoteHeader...), make([]byte, 100)...), "application/onenote"},
+		{"chm", append([]byte("ITSF\x03\x00\x00\x00"), make([]byte, 100)...), "application/vnd.ms-htmlhelp"},
+		{"pdf", []byte("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n1 0 obj\n<<>>\nendobj\n"), "application/pdf"},
+		{"text", []byte("Hello, this is a short plain text attachment.\n"), "text/plain"},
+	}
+	for _, c := range cases {
+		typ, reader, err := GuessReader("", bytes.NewReader(c.content))
+		if err != nil {
+			t.Errorf("%s: unexpected error: %s", c.name, err)
+			continue
+		}
+		if c.mime == "" {
+			if typ.MIME.Value == "application/x-iso9660-image" {
+				t.Errorf("%s: unexpected type %s", c.name, typ.MIME.Value)
+			}
+		} else if typ.MIME.Value != c.mime {
+			t.Errorf("%s: expected %s, got %s", c.name, c.mime, typ.MIME.Value)
+		}
+		// the returned reader gives back the whole content
+		content, err := ioutil.ReadAll(reader)
+		if err != nil || !bytes.Equal(content, c.content) {
+			t.Errorf("%s: the content has not been restored (%d bytes instead of %d)", c.name, len(content), len(c.content))
+		}
+	}
+	typ, _, err := GuessReader("", bytes.NewReader(nil))
+	if err != nil || typ != types.Unknown {
+		t.Errorf("empty content: got %v, %v", typ, err)
+	}
+}
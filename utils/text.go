@@ -94,4 +94,43 @@ func IsBinary(content []byte) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+// EditDistance is the Levenshtein distance between a and b, in runes.
+func EditDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = prev[j-1] + cost
+			if prev[j]+1 < cur[j] {
+				cur[j] = prev[j] + 1
+			}
+			if cur[j-1]+1 < cur[j] {
+				cur[j] = cur[j-1] + 1
+			}
+		}
+		prev, cur = cur, prev
+	}
+	return prev[len(rb)]
+}
+
+// MaxTypos is the edit distance under which a word is a misspelling of s: none for the words of
+// less than 4 letters, that are one edit away from too many others, 1 up to 8 letters and 2 above.
+func MaxTypos(s string) int {
+	switch n := len([]rune(s)); {
+	case n >= 9:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
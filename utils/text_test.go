@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestEditDistance(t *testing.T) {
+	cases := []struct {
+		a, b     string
+		distance int
+	}{
+		{"", "", 0},
+		{"paypal", "paypal", 0},
+		{"paypal", "", 6},
+		{"paypal", "paypl", 1},
+		{"paypal", "paypall", 1},
+		{"paypal", "qaypal", 1},
+		{"paypal", "pyapal", 2},
+		{"kitten", "sitting", 3},
+		{"müller", "muller", 1},
+		{"сбербанк", "сбербанк", 0},
+	}
+	for _, c := range cases {
+		if d := EditDistance(c.a, c.b); d != c.distance {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", c.a, c.b, d, c.distance)
+		}
+		if d := EditDistance(c.b, c.a); d != c.distance {
+			t.Errorf("EditDistance(%q, %q) = %d, want %d", c.b, c.a, d, c.distance)
+		}
+	}
+}
+
+func TestMaxTypos(t *testing.T) {
+	cases := map[string]int{"": 0, "ups": 0, "dhl1": 1, "paypal": 1, "linkedin": 1, "microsoft": 2, "jérôme": 1, "andré": 1}
+	for s, want := range cases {
+		if n := MaxTypos(s); n != want {
+			t.Errorf("MaxTypos(%q) = %d, want %d", s, n, want)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Package vip detects the mails that impersonate the executives and other VIPs of an
+// organization, as in business email compromise.
+//
+// The VIPs are listed by operators in a JSON file:
+//
+//	[
+//	  {"name": "Jane Doe", "title": "CEO", "addresses": ["jane.doe@example.com"], "aliases": ["Janie Doe"]}
+//	]
+//
+// The addresses are the legitimate addresses of the VIP. The sender addresses that used the name
+// of a VIP are remembered, to detect the first contacts.
+package vip
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"strings"
+)
+
+// VIP is an entry of the directory.
+type VIP struct {
+	Name      string   `json:"name"`
+	Title     string   `json:"title,omitempty"`
+	Addresses []string `json:"addresses,omitempty"`
+	Aliases   []string `json:"aliases,omitempty"`
+}
+
+type person struct {
+	VIP
+	names     [][]string
+	addresses map[string]bool
+}
+
+// Directory is a list of VIPs.
+type Directory struct {
+	persons []*person
+}
+
+// ReadDirectory reads the VIPs from a JSON file.
+func ReadDirectory(path string) (*Directory, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var vips []VIP
+	err = json.Unmarshal(content, &vips)
+	if err != nil {
+		return nil, err
+	}
+	return NewDirectory(vips), nil
+}
+
+func NewDirectory(vips []VIP) *Directory {
+	d := new(Directory)
+	for _, v := range vips {
+		p := &person{VIP: v, addresses: make(map[string]bool)}
+		for _, name := range append([]string{v.Name}, v.Aliases...) {
+			if tokens := nameTokens(name); len(tokens) > 0 {
+				p.names = append(p.names, tokens)
+			}
+		}
+		for _, addr := range v.Addresses {
+			p.addresses[normalizeAddress(addr)] = true
+		}
+		if len(p.names) > 0 {
+			d.persons = append(d.persons, p)
+		}
+	}
+	return d
+}
+
+func normalizeAddress(addr string) string {
+	return strings.ToLower(strings.Trim(strings.TrimSpace(addr), "<>"))
+}
+
+// Len returns the number of VIPs in the directory.
+func (d *Directory) Len() int {
+	return len(d.persons)
+}
+
+// match returns the VIP whose name matches name, and how it matches. The exact matches are
+// preferred to the approximate ones.
+func (d *Directory) match(name string) (*person, string) {
+	tokens := nameTokens(name)
+	if len(tokens) == 0 {
+		return nil, ""
+	}
+	var best *person
+	var bestMethod string
+	for _, p := range d.persons {
+		for _, vipName := range p.names {
+			method := matchName(tokens, vipName)
+			if method != "" && (best == nil || rank[method] < rank[bestMethod]) {
+				best, bestMethod = p, method
+			}
+		}
+	}
+	return best, bestMethod
+}
+
+var rank = map[string]int{MatchExact: 0, MatchReordered: 1, MatchInitials: 2, MatchFuzzy: 3}
+
+// signatureLines is the number of lines at the end of the body that are searched for a signature.
+const signatureLines = 12
+
+// matchSignature looks for the name of a VIP in the signature of a body. Only the exact and
+// reordered names are considered, as the body contains many more words than a display name.
+func (d *Directory) matchSignature(body string) (*person, string, string) {
+	lines := strings.Split(strings.TrimSpace(body), "\n")
+	n := 0
+	for i := len(lines) - 1; i >= 0 && n < signatureLines; i-- {
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, ">") {
+			continue
+		}
+		n++
+		if len(strings.Fields(line)) > 6 {
+			continue
+		}
+		p, method := d.match(line)
+		if p != nil && (method == MatchExact || method == MatchReordered) {
+			return p, method, line
+		}
+	}
+	return nil, "", ""
+}
@@ -0,0 +1,154 @@
+package vip
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/stephane-martin/mailstats/utils"
+	"golang.org/x/text/unicode/norm"
+)
+
+// How a name matched a VIP
+const (
+	MatchExact     = "exact"
+	MatchReordered = "reordered"
+	MatchInitials  = "initials"
+	MatchFuzzy     = "fuzzy"
+)
+
+// titles are the words of the display names that are not part of the name.
+var titles = map[string]bool{
+	"mr": true, "mrs": true, "ms": true, "miss": true, "dr": true, "prof": true, "sir": true,
+	"mme": true, "mlle": true, "herr": true, "frau": true,
+	"ceo": true, "cfo": true, "coo": true, "cto": true, "cio": true, "ciso": true, "vp": true,
+	"president": true, "chairman": true, "chairwoman": true, "director": true, "manager": true,
+	"founder": true, "owner": true, "head": true, "chief": true, "executive": true, "officer": true,
+	"pdg": true, "dg": true, "daf": true, "drh": true, "directeur": true, "directrice": true,
+	"geschaftsfuhrer": true, "vorstand": true, "office": true, "via": true,
+}
+
+// translit transliterates the Cyrillic and Greek letters, and the Latin letters that are not
+// decomposed by NFKD.
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh", 'з': "z",
+	'и': "i", 'й': "i", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o", 'п': "p", 'р': "r",
+	'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts", 'ч': "ch", 'ш': "sh",
+	'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu", 'я': "ya", 'і': "i", 'ї': "i",
+	'є': "e", 'ґ': "g",
+	'α': "a", 'β': "v", 'γ': "g", 'δ': "d", 'ε': "e", 'ζ': "z", 'η': "i", 'θ': "th", 'ι': "i",
+	'κ': "k", 'λ': "l", 'μ': "m", 'ν': "n", 'ξ': "x", 'ο': "o", 'π': "p", 'ρ': "r", 'σ': "s",
+	'ς': "s", 'τ': "t", 'υ': "y", 'φ': "f", 'χ': "ch", 'ψ': "ps", 'ω': "o",
+	'ß': "ss", 'æ': "ae", 'œ': "oe", 'ø': "o", 'ł': "l", 'đ': "d", 'ð': "d", 'þ': "th", 'ı': "i",
+}
+
+// nameTokens returns the normalized words of a name: lowercased, transliterated to ASCII,
+// without the diacritics, the punctuation and the titles.
+func nameTokens(name string) []string {
+	name = norm.NFKD.String(strings.ToLower(name))
+	var b strings.Builder
+	for _, r := range name {
+		switch {
+		case unicode.Is(unicode.Mn, r):
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			b.WriteRune(r)
+		case translit[r] != "":
+			b.WriteString(translit[r])
+		case r == '\'':
+		default:
+			b.WriteByte(' ')
+		}
+	}
+	var tokens []string
+	for _, t := range strings.Fields(b.String()) {
+		if !titles[t] {
+			tokens = append(tokens, t)
+		}
+	}
+	return tokens
+}
+
+// matchName compares the words of a name with the words of a VIP name, and returns how they
+// match, or an empty string.
+func matchName(tokens, vip []string) string {
+	if len(tokens) == 0 || len(vip) == 0 {
+		return ""
+	}
+	if strings.Join(tokens, " ") == strings.Join(vip, " ") || strings.Join(tokens, "") == strings.Join(vip, "") {
+		return MatchExact
+	}
+	if len(tokens) != len(vip) {
+		return ""
+	}
+	sortedTokens := append([]string{}, tokens...)
+	sortedVIP := append([]string{}, vip...)
+	sort.Strings(sortedTokens)
+	sort.Strings(sortedVIP)
+	if strings.Join(sortedTokens, " ") == strings.Join(sortedVIP, " ") {
+		return MatchReordered
+	}
+	if matchInitials(tokens, vip) {
+		return MatchInitials
+	}
+	if matchFuzzy(tokens, vip) {
+		return MatchFuzzy
+	}
+	return ""
+}
+
+// matchInitials matches "J. Doe" or "Doe J" with "Jane Doe". At least one word must match in full.
+func matchInitials(tokens, vip []string) bool {
+	used := make([]bool, len(vip))
+	remaining := make([]string, 0, len(tokens))
+	full := 0
+	for _, t := range tokens {
+		found := false
+		for i, v := range vip {
+			if !used[i] && t == v {
+				used[i], found = true, true
+				full++
+				break
+			}
+		}
+		if !found {
+			remaining = append(remaining, t)
+		}
+	}
+	if full == 0 {
+		return false
+	}
+	for _, t := range remaining {
+		if len(t) != 1 {
+			return false
+		}
+		found := false
+		for i, v := range vip {
+			if !used[i] && v[0] == t[0] {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// matchFuzzy matches the names whose words are only a few typos away, in any order.
+func matchFuzzy(tokens, vip []string) bool {
+	used := make([]bool, len(vip))
+	for _, t := range tokens {
+		found := false
+		for i, v := range vip {
+			if !used[i] && utils.EditDistance(t, v) <= utils.MaxTypos(v) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,95 @@
+package vip
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestNameTokens(t *testing.T) {
+	cases := map[string][]string{
+		"Jane Doe":                       {"jane", "doe"},
+		"  DOE, Jane (CEO) ":             {"doe", "jane"},
+		"Dr. Jérôme Müller-Lüdenscheidt": {"jerome", "muller", "ludenscheidt"},
+		"Łukasz Straße":                  {"lukasz", "strasse"},
+		"Дмитрий Иванов":                 {"dmitrii", "ivanov"},
+		"Ｊａｎｅ　Ｄｏｅ":                       {"jane", "doe"},
+		"O'Brien":                        {"obrien"},
+		"CEO":                            nil,
+		"":                               nil,
+	}
+	for name, want := range cases {
+		if tokens := nameTokens(name); !reflect.DeepEqual(tokens, want) {
+			t.Errorf("nameTokens(%q) = %q, want %q", name, tokens, want)
+		}
+	}
+}
+
+func TestMatchName(t *testing.T) {
+	vip := nameTokens("Jane Doe")
+	cases := map[string]string{
+		"Jane Doe":       MatchExact,
+		"JANE DOE, CEO":  MatchExact,
+		"JaneDoe":        MatchExact,
+		"Jäne Döe":       MatchExact,
+		"Doe Jane":       MatchReordered,
+		"Doe, Jane":      MatchReordered,
+		"J. Doe":         MatchInitials,
+		"Doe J":          MatchInitials,
+		"Jne Doe":        MatchFuzzy,
+		"Janne Doe":      MatchFuzzy,
+		"Jane Deo":       "",
+		"Jnae Doe":       "",
+		"J. D.":          "",
+		"John Doe":       "",
+		"Jane":           "",
+		"Jane Doe Smith": "",
+		"Jane Doerfler":  "",
+		"Support":        "",
+		"":               "",
+	}
+	for name, want := range cases {
+		if method := matchName(nameTokens(name), vip); method != want {
+			t.Errorf("matchName(%q) = %q, want %q", name, method, want)
+		}
+	}
+}
+
+func TestDirectoryMatch(t *testing.T) {
+	d := NewDirectory([]VIP{
+		{Name: "Jane Doe", Title: "CEO", Aliases: []string{"Janie Doe"}},
+		{Name: "John Smith", Title: "CFO"},
+		{Name: "CEO"},
+	})
+	if d.Len() != 2 {
+		t.Errorf("Len = %d, want 2", d.Len())
+	}
+	cases := []struct {
+		name   string
+		vip    string
+		method string
+	}{
+		{"Janie Doe", "Jane Doe", MatchExact},
+		{"Smith John", "John Smith", MatchReordered},
+		{"Jon Smith", "John Smith", MatchFuzzy},
+		{"Alice Martin", "", ""},
+	}
+	for _, c := range cases {
+		p, method := d.match(c.name)
+		name := ""
+		if p != nil {
+			name = p.Name
+		}
+		if name != c.vip || method != c.method {
+			t.Errorf("match(%q) = %q, %q, want %q, %q", c.name, name, method, c.vip, c.method)
+		}
+	}
+
+	body := "Please wire the amount today.\n\nRegards,\nJane Doe\nChief Executive Officer\n\n> On Monday, John Smith wrote:\n> hello"
+	p, method, line := d.matchSignature(body)
+	if p == nil || p.Name != "Jane Doe" || method != MatchExact || line != "Jane Doe" {
+		t.Errorf("matchSignature = %v, %q, %q", p, method, line)
+	}
+	if p, _, _ := d.matchSignature("I met J. Doe yesterday, and Jon Smith too."); p != nil {
+		t.Errorf("matchSignature matched %q in a sentence", p.Name)
+	}
+}
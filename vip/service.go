@@ -0,0 +1,331 @@
+package vip
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/arguments"
+	"github.com/stephane-martin/mailstats/models"
+	"github.com/stephane-martin/mailstats/utils"
+	"go.uber.org/fx"
+)
+
+var reloadInterval = time.Minute
+
+// seenTTL is the time after which an address that used the name of a VIP is forgotten, and
+// maxSeen bounds the number of remembered addresses: the oldest are forgotten beyond it.
+var seenTTL = 365 * 24 * time.Hour
+var maxSeen = 100000
+
+type VIPs interface {
+	utils.Service
+	utils.Prestartable
+	utils.Startable
+	utils.Closeable
+	// Check returns the VIP that a mail impersonates, or nil.
+	Check(from models.Address, replyTo []string, body string) *models.Impersonation
+}
+
+type impl struct {
+	logger        log15.Logger
+	directoryPath string
+	statePath     string
+	directory     atomic.Value
+	modified      time.Time
+	// seen maps the VIP names to the addresses that have used them, and to the last time they did
+	seen      map[string]map[string]time.Time
+	seenCount int
+	seenLock  sync.Mutex
+	// version counts the changes of seen, and saved is the version of the state file
+	version uint64
+	saved   uint64
+}
+
+func NewVIPs(directoryPath string, statePath string, logger log15.Logger) VIPs {
+	return &impl{
+		directoryPath: directoryPath,
+		statePath:     statePath,
+		logger:        logger,
+		seen:          make(map[string]map[string]time.Time),
+	}
+}
+
+func (i *impl) Name() string {
+	return "VIPs"
+}
+
+func (i *impl) Prestart() error {
+	err := i.load()
+	if err != nil {
+		return err
+	}
+	return i.loadState()
+}
+
+// Start reloads the directory when the file changes, and saves the seen addresses.
+func (i *impl) Start(ctx context.Context) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(reloadInterval):
+		}
+		if i.lastModified().After(i.modified) {
+			err := i.load()
+			if err != nil {
+				i.logger.Warn("Error reloading the VIP directory", "error", err)
+			}
+		}
+		err := i.saveState()
+		if err != nil {
+			i.logger.Warn("Error saving the VIP seen addresses", "error", err)
+		}
+	}
+}
+
+func (i *impl) Close() error {
+	return i.saveState()
+}
+
+func (i *impl) lastModified() time.Time {
+	info, err := os.Stat(i.directoryPath)
+	if err != nil {
+		return time.Time{}
+	}
+	return info.ModTime()
+}
+
+func (i *impl) load() error {
+	modified := i.lastModified()
+	d, err := ReadDirectory(i.directoryPath)
+	if err != nil {
+		return fmt.Errorf("failed to read VIP directory: %s", err)
+	}
+	i.directory.Store(d)
+	i.modified = modified
+	i.logger.Info("VIP directory loaded", "path", i.directoryPath, "vips", d.Len())
+	return nil
+}
+
+func (i *impl) getDirectory() *Directory {
+	d := i.directory.Load()
+	if d == nil {
+		return nil
+	}
+	return d.(*Directory)
+}
+
+func (i *impl) loadState() error {
+	if i.statePath == "" {
+		return nil
+	}
+	content, err := ioutil.ReadFile(i.statePath)
+	if os.IsNotExist(err) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	seen := make(map[string]map[string]time.Time)
+	err = json.Unmarshal(content, &seen)
+	if err != nil || seen == nil {
+		i.logger.Warn("Invalid VIP seen addresses, starting afresh", "path", i.statePath, "error", err)
+		return nil
+	}
+	i.seenLock.Lock()
+	defer i.seenLock.Unlock()
+	i.seen = seen
+	i.seenCount = 0
+	for _, addresses := range seen {
+		i.seenCount += len(addresses)
+	}
+	i.forget(time.Now())
+	return nil
+}
+
+func (i *impl) saveState() error {
+	if i.statePath == "" {
+		return nil
+	}
+	i.seenLock.Lock()
+	if i.version == i.saved {
+		i.seenLock.Unlock()
+		return nil
+	}
+	version := i.version
+	content, err := json.Marshal(i.seen)
+	i.seenLock.Unlock()
+	if err != nil {
+		return err
+	}
+	err = os.MkdirAll(filepath.Dir(i.statePath), 0755)
+	if err != nil {
+		return err
+	}
+	tmp := i.statePath + ".tmp"
+	err = ioutil.WriteFile(tmp, content, 0644)
+	if err != nil {
+		return err
+	}
+	err = os.Rename(tmp, i.statePath)
+	if err != nil {
+		return err
+	}
+	// the changes made during the save are saved next time
+	i.seenLock.Lock()
+	i.saved = version
+	i.seenLock.Unlock()
+	return nil
+}
+
+// firstContact records that address used the name of a VIP, and reports whether it is the
+// first time since seenTTL.
+func (i *impl) firstContact(vipName string, address string) bool {
+	now := time.Now()
+	i.seenLock.Lock()
+	defer i.seenLock.Unlock()
+	i.version++
+	addresses := i.seen[vipName]
+	if addresses == nil {
+		addresses = make(map[string]time.Time)
+		i.seen[vipName] = addresses
+	}
+	last, ok := addresses[address]
+	addresses[address] = now
+	if ok && now.Sub(last) < seenTTL {
+		return false
+	}
+	if !ok {
+		i.seenCount++
+		if i.seenCount > maxSeen {
+			i.forget(now)
+		}
+	}
+	return true
+}
+
+// forget removes the addresses that were last seen more than seenTTL ago, and the oldest ones
+// when there are more than maxSeen. It is called with the lock held.
+func (i *impl) forget(now time.Time) {
+	type entry struct {
+		vip, address string
+		last         time.Time
+	}
+	count := i.seenCount
+	var entries []entry
+	for vipName, addresses := range i.seen {
+		for address, last := range addresses {
+			if now.Sub(last) >= seenTTL {
+				delete(addresses, address)
+				i.seenCount--
+				continue
+			}
+			entries = append(entries, entry{vip: vipName, address: address, last: last})
+		}
+	}
+	if len(entries) > maxSeen {
+		// forget a tenth more, not to sort at each new address
+		sort.Slice(entries, func(a, b int) bool { return entries[a].last.Before(entries[b].last) })
+		for _, e := range entries[:len(entries)-maxSeen*9/10] {
+			delete(i.seen[e.vip], e.address)
+			i.seenCount--
+		}
+	}
+	for vipName, addresses := range i.seen {
+		if len(addresses) == 0 {
+			delete(i.seen, vipName)
+		}
+	}
+	if i.seenCount != count {
+		i.version++
+	}
+}
+
+func (i *impl) Check(from models.Address, replyTo []string, body string) *models.Impersonation {
+	d := i.getDirectory()
+	if d == nil {
+		return nil
+	}
+	address := normalizeAddress(from.Address)
+	source := "display-name"
+	matched := from.Name
+	p, method := d.match(from.Name)
+	if p == nil {
+		source = "signature"
+		p, method, matched = d.matchSignature(body)
+	}
+	if p == nil {
+		return nil
+	}
+
+	imp := &models.Impersonation{
+		VIP:          p.Name,
+		Title:        p.Title,
+		Source:       source,
+		Match:        method,
+		MatchedName:  matched,
+		Address:      address,
+		KnownAddress: p.addresses[address],
+	}
+	for _, r := range replyTo {
+		r = normalizeAddress(r)
+		if r != "" && r != address && !p.addresses[r] {
+			imp.ReplyTo = append(imp.ReplyTo, r)
+		}
+	}
+	if imp.KnownAddress && len(imp.ReplyTo) == 0 {
+		// the VIP writing from a legitimate address
+		return nil
+	}
+
+	imp.Evidence = append(imp.Evidence, fmt.Sprintf("%s %q matches VIP %s (%s)", source, matched, p.Name, method))
+	if !imp.KnownAddress {
+		imp.Evidence = append(imp.Evidence, fmt.Sprintf("%s is not a known address of %s", address, p.Name))
+		domain := utils.DomainFromAddress(address)
+		if utils.IsFreeMail(domain) {
+			imp.FreeMail = true
+			imp.Evidence = append(imp.Evidence, fmt.Sprintf("%s is a free-mail provider", domain))
+		}
+		if address != "" && i.firstContact(p.Name, address) {
+			imp.FirstContact = true
+			imp.Evidence = append(imp.Evidence, fmt.Sprintf("first mail from %s with the name of %s", address, p.Name))
+		}
+	}
+	for _, r := range imp.ReplyTo {
+		imp.Evidence = append(imp.Evidence, fmt.Sprintf("Reply-To %s differs from From", r))
+	}
+	return imp
+}
+
+type Params struct {
+	fx.In
+	Args   *arguments.Args `optional:"true"`
+	Logger log15.Logger    `optional:"true"`
+}
+
+var Service = fx.Provide(func(lc fx.Lifecycle, params Params) VIPs {
+	if params.Args == nil || params.Args.VIP.DirectoryPath == "" {
+		return nil
+	}
+	logger := params.Logger
+	if logger == nil {
+		logger = log15.New()
+		logger.SetHandler(log15.DiscardHandler())
+	}
+	v := NewVIPs(
+		params.Args.VIP.DirectoryPath,
+		params.Args.VIP.StatePath,
+		logger,
+	)
+	utils.Append(lc, v, logger)
+	return v
+})
@@ -0,0 +1,142 @@
+package vip
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/inconshreveable/log15"
+	"github.com/stephane-martin/mailstats/models"
+)
+
+func TestCheck(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	directoryPath := filepath.Join(dir, "vips.json")
+	statePath := filepath.Join(dir, "state", "seen.json")
+	err = ioutil.WriteFile(directoryPath, []byte(`[{"name": "Jane Doe", "title": "CEO", "addresses": ["Jane.Doe@example.com"]}]`), 0644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+
+	v := NewVIPs(directoryPath, statePath, logger)
+	if err := v.Prestart(); err != nil {
+		t.Fatal(err)
+	}
+
+	known := models.Address{Name: "Jane Doe", Address: "<jane.doe@example.com>"}
+	if imp := v.Check(known, nil, ""); imp != nil {
+		t.Errorf("known address: %+v", imp)
+	}
+	if imp := v.Check(known, []string{"jane.doe@example.com"}, ""); imp != nil {
+		t.Errorf("known address with the same Reply-To: %+v", imp)
+	}
+	imp := v.Check(known, []string{"ceo.office@gmail.com"}, "")
+	if imp == nil || !imp.KnownAddress || imp.FirstContact || len(imp.ReplyTo) != 1 || imp.ReplyTo[0] != "ceo.office@gmail.com" {
+		t.Errorf("known address with a foreign Reply-To: %+v", imp)
+	}
+
+	spoof := models.Address{Name: "Doe, Jane (CEO)", Address: "jane.doe.ceo@gmail.com"}
+	imp = v.Check(spoof, nil, "")
+	if imp == nil {
+		t.Fatal("display name impersonation not detected")
+	}
+	if imp.VIP != "Jane Doe" || imp.Title != "CEO" || imp.Source != "display-name" || imp.Match != MatchReordered ||
+		imp.KnownAddress || !imp.FreeMail || !imp.FirstContact || len(imp.Evidence) != 4 {
+		t.Errorf("display name impersonation: %+v", imp)
+	}
+	if imp = v.Check(spoof, nil, ""); imp == nil || imp.FirstContact {
+		t.Errorf("second mail: %+v", imp)
+	}
+
+	body := "Can you handle a wire transfer today?\n\nThanks,\nJane Doe\nSent from my phone"
+	imp = v.Check(models.Address{Name: "Office", Address: "office@example.net"}, nil, body)
+	if imp == nil || imp.Source != "signature" || imp.MatchedName != "Jane Doe" || imp.FreeMail || !imp.FirstContact {
+		t.Errorf("signature impersonation: %+v", imp)
+	}
+	if imp = v.Check(models.Address{Name: "Alice Martin", Address: "alice@example.net"}, nil, "Hello"); imp != nil {
+		t.Errorf("unrelated sender: %+v", imp)
+	}
+
+	// the seen addresses are saved and reloaded
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+	v = NewVIPs(directoryPath, statePath, logger)
+	if err := v.Prestart(); err != nil {
+		t.Fatal(err)
+	}
+	if imp = v.Check(spoof, nil, ""); imp == nil || imp.FirstContact {
+		t.Errorf("after reload: %+v", imp)
+	}
+}
+
+func TestSeenState(t *testing.T) {
+	dir, err := ioutil.TempDir("", "vip")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	statePath := filepath.Join(dir, "seen.json")
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	v := NewVIPs("", statePath, logger).(*impl)
+
+	if !v.firstContact("Jane Doe", "spoofer@example.net") || v.firstContact("Jane Doe", "spoofer@example.net") {
+		t.Error("first contact")
+	}
+	// a failed save is retried
+	if err := os.MkdirAll(statePath+".tmp", 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.saveState(); err == nil {
+		t.Fatal("save succeeded over a directory")
+	}
+	if err := os.Remove(statePath + ".tmp"); err != nil {
+		t.Fatal(err)
+	}
+	if err := v.Close(); err != nil {
+		t.Fatal(err)
+	}
+	reloaded := NewVIPs("", statePath, logger).(*impl)
+	if err := reloaded.loadState(); err != nil {
+		t.Fatal(err)
+	}
+	if reloaded.firstContact("Jane Doe", "spoofer@example.net") {
+		t.Error("first contact again after a failed save")
+	}
+
+	// the addresses are forgotten after seenTTL
+	reloaded.seen["Jane Doe"]["spoofer@example.net"] = time.Now().Add(-seenTTL - time.Hour)
+	if !reloaded.firstContact("Jane Doe", "spoofer@example.net") {
+		t.Error("an expired address is remembered")
+	}
+}
+
+func TestSeenLimit(t *testing.T) {
+	defer func(n int) { maxSeen = n }(maxSeen)
+	maxSeen = 10
+	logger := log15.New()
+	logger.SetHandler(log15.DiscardHandler())
+	v := NewVIPs("", "", logger).(*impl)
+	for n := 0; n < 25; n++ {
+		v.firstContact("Jane Doe", fmt.Sprintf("spoofer%d@example.net", n))
+		if v.seenCount > maxSeen {
+			t.Fatalf("%d addresses remembered", v.seenCount)
+		}
+	}
+	if v.seenCount != len(v.seen["Jane Doe"]) {
+		t.Errorf("count %d, %d addresses", v.seenCount, len(v.seen["Jane Doe"]))
+	}
+	if v.firstContact("Jane Doe", "spoofer24@example.net") {
+		t.Error("the last address is forgotten")
+	}
+}